@@ -19,6 +19,19 @@ func (il *IntegerLiteral) String() string {
 	return il.Token.Literal
 }
 
+type FloatLiteral struct {
+	*BaseNode
+	Value float64
+}
+
+func (fl *FloatLiteral) expressionNode() {}
+func (fl *FloatLiteral) TokenLiteral() string {
+	return fl.Token.Literal
+}
+func (fl *FloatLiteral) String() string {
+	return fl.Token.Literal
+}
+
 type StringLiteral struct {
 	*BaseNode
 	Value string
@@ -37,6 +50,53 @@ func (sl *StringLiteral) String() string {
 	return out.String()
 }
 
+type SymbolLiteral struct {
+	*BaseNode
+	Value string
+}
+
+func (sl *SymbolLiteral) expressionNode() {}
+func (sl *SymbolLiteral) TokenLiteral() string {
+	return sl.Token.Literal
+}
+func (sl *SymbolLiteral) String() string {
+	return ":" + sl.Value
+}
+
+// InterpolatedString is a double-quoted string with "#{}" interpolations, it's composed by
+// string literals and interpolated expressions.
+type InterpolatedString struct {
+	*BaseNode
+	Segments []Expression
+}
+
+func (is *InterpolatedString) expressionNode() {}
+func (is *InterpolatedString) TokenLiteral() string {
+	return is.Token.Literal
+}
+func (is *InterpolatedString) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("\"")
+	out.WriteString(is.Token.Literal)
+	out.WriteString("\"")
+	return out.String()
+}
+
+type RegexpLiteral struct {
+	*BaseNode
+	Value string
+	Flags string
+}
+
+func (rl *RegexpLiteral) expressionNode() {}
+func (rl *RegexpLiteral) TokenLiteral() string {
+	return rl.Token.Literal
+}
+func (rl *RegexpLiteral) String() string {
+	return rl.Token.Literal
+}
+
 type ArrayExpression struct {
 	*BaseNode
 	Elements []Expression
@@ -167,6 +227,56 @@ func (ae *AssignExpression) String() string {
 	return out.String()
 }
 
+// CallAssignExpression represents an operator assignment to an attribute or an index, like `a.b += c` or
+// `a[i] ||= b`. The receiver and arguments of the call are only evaluated once.
+type CallAssignExpression struct {
+	*BaseNode
+	Call   *CallExpression
+	Setter string
+	// Operator is the infix operator of the assignment, like `+` for `+=` or `||` for `||=`
+	Operator string
+	Value    Expression
+}
+
+func (ca *CallAssignExpression) expressionNode() {}
+func (ca *CallAssignExpression) TokenLiteral() string {
+	return ca.Token.Literal
+}
+
+// String returns the expanded form of the assignment, like `a.b=((a.b() + c))` or `(a.[](i) || a.[]=(i, b))`
+func (ca *CallAssignExpression) String() string {
+	var out bytes.Buffer
+	var args []string
+
+	for _, arg := range ca.Call.Arguments {
+		args = append(args, arg.String())
+	}
+
+	conditional := ca.Operator == "||" || ca.Operator == "&&"
+
+	if conditional {
+		out.WriteString("(")
+		out.WriteString(ca.Call.String())
+		out.WriteString(" " + ca.Operator + " ")
+		args = append(args, ca.Value.String())
+	} else {
+		args = append(args, "("+ca.Call.String()+" "+ca.Operator+" "+ca.Value.String()+")")
+	}
+
+	out.WriteString(ca.Call.Receiver.String())
+	out.WriteString(".")
+	out.WriteString(ca.Setter)
+	out.WriteString("(")
+	out.WriteString(strings.Join(args, ", "))
+	out.WriteString(")")
+
+	if conditional {
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
+
 type BooleanExpression struct {
 	*BaseNode
 	Value bool
@@ -233,6 +343,39 @@ func (ie *IfExpression) String() string {
 	return out.String()
 }
 
+// UnlessExpression represents `unless ... else ... end`, which executes its consequence when the condition is falsy
+type UnlessExpression struct {
+	*BaseNode
+	Condition   Expression
+	Consequence *BlockStatement
+	Alternative *BlockStatement
+}
+
+func (ue *UnlessExpression) expressionNode() {}
+
+// TokenLiteral returns `unless`
+func (ue *UnlessExpression) TokenLiteral() string {
+	return ue.Token.Literal
+}
+func (ue *UnlessExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("unless ")
+	out.WriteString(ue.Condition.String())
+	out.WriteString("\n")
+	out.WriteString(ue.Consequence.String())
+
+	if ue.Alternative != nil {
+		out.WriteString("\n")
+		out.WriteString("else\n")
+		out.WriteString(ue.Alternative.String())
+	}
+
+	out.WriteString("\nend")
+
+	return out.String()
+}
+
 // ConditionalExpression represents if or elsif expression
 type ConditionalExpression struct {
 	*BaseNode
@@ -257,13 +400,189 @@ func (ce *ConditionalExpression) String() string {
 	return out.String()
 }
 
+// ArgumentPairExpression represents a keyword parameter of method definition, like `host:` or `port: 80`.
+// Value is nil if the keyword argument is required.
+type ArgumentPairExpression struct {
+	*BaseNode
+	Key   *Identifier
+	Value Expression
+}
+
+func (ape *ArgumentPairExpression) expressionNode() {}
+
+// TokenLiteral returns the parameter's name
+func (ape *ArgumentPairExpression) TokenLiteral() string {
+	return ape.Key.Value
+}
+
+func (ape *ArgumentPairExpression) String() string {
+	if ape.Value == nil {
+		return ape.Key.Value + ":"
+	}
+
+	return ape.Key.Value + ": " + ape.Value.String()
+}
+
+// BeginExpression represents `begin` expression, which rescues the errors raised in its body
+type BeginExpression struct {
+	*BaseNode
+	Body    *BlockStatement
+	Rescues []*RescueClause
+	Ensure  *BlockStatement
+}
+
+func (be *BeginExpression) expressionNode() {}
+
+// TokenLiteral returns `begin`
+func (be *BeginExpression) TokenLiteral() string {
+	return be.Token.Literal
+}
+
+func (be *BeginExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("begin\n")
+	out.WriteString(be.Body.String())
+
+	for _, r := range be.Rescues {
+		out.WriteString("\n")
+		out.WriteString(r.String())
+	}
+
+	if be.Ensure != nil {
+		out.WriteString("\nensure\n")
+		out.WriteString(be.Ensure.String())
+	}
+
+	out.WriteString("\nend")
+
+	return out.String()
+}
+
+// RescueClause represents `rescue` clause of begin expression
+type RescueClause struct {
+	*BaseNode
+	ExceptionClasses []Expression
+	Variable         *Identifier
+	Body             *BlockStatement
+}
+
+func (rc *RescueClause) expressionNode() {}
+
+// TokenLiteral returns `rescue`
+func (rc *RescueClause) TokenLiteral() string {
+	return rc.Token.Literal
+}
+
+func (rc *RescueClause) String() string {
+	var out bytes.Buffer
+	classes := []string{}
+
+	for _, c := range rc.ExceptionClasses {
+		classes = append(classes, c.String())
+	}
+
+	out.WriteString("rescue")
+
+	if len(classes) > 0 {
+		out.WriteString(" ")
+		out.WriteString(strings.Join(classes, ", "))
+	}
+
+	if rc.Variable != nil {
+		out.WriteString(" => ")
+		out.WriteString(rc.Variable.String())
+	}
+
+	out.WriteString("\n")
+	out.WriteString(rc.Body.String())
+
+	return out.String()
+}
+
+// CaseExpression represents pattern matching with `case ... in ... end`, which executes the first in clause whose
+// pattern matches the subject
+type CaseExpression struct {
+	*BaseNode
+	Subject     Expression
+	Clauses     []*InClause
+	Alternative *BlockStatement
+}
+
+func (ce *CaseExpression) expressionNode() {}
+
+// TokenLiteral returns `case`
+func (ce *CaseExpression) TokenLiteral() string {
+	return ce.Token.Literal
+}
+
+func (ce *CaseExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("case ")
+	out.WriteString(ce.Subject.String())
+
+	for _, c := range ce.Clauses {
+		out.WriteString("\n")
+		out.WriteString(c.String())
+	}
+
+	if ce.Alternative != nil {
+		out.WriteString("\nelse\n")
+		out.WriteString(ce.Alternative.String())
+	}
+
+	out.WriteString("\nend")
+
+	return out.String()
+}
+
+// InClause represents `in` clause of case expression, which can have a guard like `in [x, y] if x > y`
+type InClause struct {
+	*BaseNode
+	Pattern       Expression
+	Guard         Expression
+	GuardIsUnless bool
+	Consequence   *BlockStatement
+}
+
+func (ic *InClause) expressionNode() {}
+
+// TokenLiteral returns `in`
+func (ic *InClause) TokenLiteral() string {
+	return ic.Token.Literal
+}
+
+func (ic *InClause) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("in ")
+	out.WriteString(ic.Pattern.String())
+
+	if ic.Guard != nil {
+		if ic.GuardIsUnless {
+			out.WriteString(" unless ")
+		} else {
+			out.WriteString(" if ")
+		}
+
+		out.WriteString(ic.Guard.String())
+	}
+
+	out.WriteString("\n")
+	out.WriteString(ic.Consequence.String())
+
+	return out.String()
+}
+
 type CallExpression struct {
 	*BaseNode
-	Receiver       Expression
-	Method         string
-	Arguments      []Expression
-	Block          *BlockStatement
-	BlockArguments []*Identifier
+	Receiver  Expression
+	Method    string
+	Arguments []Expression
+	Block     *BlockStatement
+	// BlockArguments are Identifiers, or AssignExpressions for the ones with default values
+	BlockArguments []Expression
 }
 
 func (ce *CallExpression) expressionNode() {}
@@ -344,10 +663,48 @@ func (ye *YieldExpression) String() string {
 	return out.String()
 }
 
+// SuperExpression represents `super`, which calls the method of the same name in the superclass.
+// ForwardArgs is true for bare `super`, which passes the arguments of the current method.
+type SuperExpression struct {
+	*CallExpression
+	ForwardArgs bool
+}
+
+func (se *SuperExpression) String() string {
+	if se.ForwardArgs && se.Block == nil {
+		return "super"
+	}
+
+	var out bytes.Buffer
+	var args []string
+
+	for _, arg := range se.Arguments {
+		args = append(args, arg.String())
+	}
+
+	out.WriteString("super")
+
+	if !se.ForwardArgs {
+		out.WriteString("(")
+		out.WriteString(strings.Join(args, ", "))
+		out.WriteString(")")
+	}
+
+	if se.Block != nil {
+		out.WriteString(" do\n")
+		out.WriteString(se.Block.String())
+		out.WriteString("\nend")
+	}
+
+	return out.String()
+}
+
 type RangeExpression struct {
 	*BaseNode
 	Start Expression
 	End   Expression
+	// Exclusive is true for three-dot ranges, which don't include their end
+	Exclusive bool
 }
 
 func (re *RangeExpression) expressionNode() {}
@@ -359,7 +716,7 @@ func (re *RangeExpression) String() string {
 
 	out.WriteString("(")
 	out.WriteString(re.Start.String())
-	out.WriteString("..")
+	out.WriteString(re.Token.Literal)
 	out.WriteString(re.End.String())
 	out.WriteString(")")
 
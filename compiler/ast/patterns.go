@@ -0,0 +1,204 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+)
+
+// ValuePattern matches the values that the expression matches with `===`, like `Integer`, `1..5` or `^x`
+type ValuePattern struct {
+	*BaseNode
+	Value Expression
+	// Pinned is true if it's the pinned value of an expression like `^x`, instead of a literal or constant
+	Pinned bool
+}
+
+func (vp *ValuePattern) expressionNode() {}
+
+// TokenLiteral returns the value's first token
+func (vp *ValuePattern) TokenLiteral() string {
+	return vp.Token.Literal
+}
+
+func (vp *ValuePattern) String() string {
+	if vp.Pinned {
+		return "^" + vp.Value.String()
+	}
+
+	return vp.Value.String()
+}
+
+// VariablePattern matches any value and assigns it to the local variable, except `_` which only matches
+type VariablePattern struct {
+	*BaseNode
+	Variable *Identifier
+}
+
+func (vp *VariablePattern) expressionNode() {}
+
+// TokenLiteral returns the variable's name
+func (vp *VariablePattern) TokenLiteral() string {
+	return vp.Token.Literal
+}
+
+func (vp *VariablePattern) String() string {
+	return vp.Variable.Value
+}
+
+// IsWildcard returns true if it's `_`, which doesn't need to be assigned
+func (vp *VariablePattern) IsWildcard() bool {
+	return vp.Variable.Value == "_"
+}
+
+// SplatPattern matches the rest elements in an array pattern like `[first, *rest]`, Variable is nil for `*`
+type SplatPattern struct {
+	*BaseNode
+	Variable *Identifier
+}
+
+func (sp *SplatPattern) expressionNode() {}
+
+// TokenLiteral returns `*`
+func (sp *SplatPattern) TokenLiteral() string {
+	return sp.Token.Literal
+}
+
+func (sp *SplatPattern) String() string {
+	if sp.Variable == nil {
+		return "*"
+	}
+
+	return "*" + sp.Variable.Value
+}
+
+// AlternativePattern matches the value if any of its patterns matches, like `Integer | Float`
+type AlternativePattern struct {
+	*BaseNode
+	Alternatives []Expression
+}
+
+func (ap *AlternativePattern) expressionNode() {}
+
+// TokenLiteral returns the first alternative's first token
+func (ap *AlternativePattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+func (ap *AlternativePattern) String() string {
+	alternatives := []string{}
+
+	for _, a := range ap.Alternatives {
+		alternatives = append(alternatives, a.String())
+	}
+
+	return strings.Join(alternatives, " | ")
+}
+
+// CapturePattern assigns the value to the local variable if the pattern matches, like `Integer => x`
+type CapturePattern struct {
+	*BaseNode
+	Pattern  Expression
+	Variable *Identifier
+}
+
+func (cp *CapturePattern) expressionNode() {}
+
+// TokenLiteral returns `=>`
+func (cp *CapturePattern) TokenLiteral() string {
+	return cp.Token.Literal
+}
+
+func (cp *CapturePattern) String() string {
+	return cp.Pattern.String() + " => " + cp.Variable.Value
+}
+
+// ArrayPattern matches an Array, or an object that responds to `deconstruct`, whose elements match the patterns.
+// It can have a constant that is checked before deconstruction, like `Point[x, y]`.
+type ArrayPattern struct {
+	*BaseNode
+	Constant Expression
+	Elements []Expression
+}
+
+func (ap *ArrayPattern) expressionNode() {}
+
+// TokenLiteral returns `[`
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+	elements := []string{}
+
+	for _, e := range ap.Elements {
+		elements = append(elements, e.String())
+	}
+
+	if ap.Constant != nil {
+		out.WriteString(ap.Constant.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// SplatIndex returns the index of the splat pattern in the elements, or -1 if there's no splat pattern
+func (ap *ArrayPattern) SplatIndex() int {
+	for i, e := range ap.Elements {
+		if _, ok := e.(*SplatPattern); ok {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// HashPattern matches a Hash, or an object that responds to `deconstruct_keys`, which has all the keys and whose
+// values match the patterns. A key without a pattern like `{ name: }` assigns the value to the variable of the
+// key's name. It can have a constant that is checked before deconstruction, like `Point(x:, y:)`.
+type HashPattern struct {
+	*BaseNode
+	Constant Expression
+	Keys     []string
+	Values   []Expression
+}
+
+func (hp *HashPattern) expressionNode() {}
+
+// TokenLiteral returns `{`
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+	pairs := []string{}
+
+	for i, key := range hp.Keys {
+		if hp.Values[i] == nil {
+			pairs = append(pairs, key+":")
+			continue
+		}
+
+		pairs = append(pairs, key+": "+hp.Values[i].String())
+	}
+
+	if hp.Constant != nil {
+		out.WriteString(hp.Constant.String())
+	}
+
+	if len(pairs) == 0 {
+		out.WriteString("{}")
+		return out.String()
+	}
+
+	out.WriteString("{ ")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
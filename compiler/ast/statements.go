@@ -128,9 +128,10 @@ func (ds *DefStatement) String() string {
 	return out.String()
 }
 
-// NextStatement represents "next" keyword
+// NextStatement represents "next" keyword, Value is the block's result and can be nil
 type NextStatement struct {
 	*BaseNode
+	Value Expression
 }
 
 func (ns *NextStatement) statementNode() {}
@@ -140,12 +141,32 @@ func (ns *NextStatement) TokenLiteral() string {
 	return ns.Token.Literal
 }
 func (ns *NextStatement) String() string {
+	if ns.Value != nil {
+		return "next " + ns.Value.String()
+	}
+
 	return "next"
 }
 
-// BreakStatement represents "break" keyword
+// RetryStatement represents "retry" keyword
+type RetryStatement struct {
+	*BaseNode
+}
+
+func (rs *RetryStatement) statementNode() {}
+
+// TokenLiteral returns token's literal
+func (rs *RetryStatement) TokenLiteral() string {
+	return rs.Token.Literal
+}
+func (rs *RetryStatement) String() string {
+	return "retry"
+}
+
+// BreakStatement represents "break" keyword, Value is the result of the method call that yields the block and can be nil
 type BreakStatement struct {
 	*BaseNode
+	Value Expression
 }
 
 func (bs *BreakStatement) statementNode() {}
@@ -155,9 +176,28 @@ func (bs *BreakStatement) TokenLiteral() string {
 	return bs.Token.Literal
 }
 func (bs *BreakStatement) String() string {
+	if bs.Value != nil {
+		return bs.TokenLiteral() + " " + bs.Value.String()
+	}
+
 	return bs.TokenLiteral()
 }
 
+// RedoStatement represents "redo" keyword
+type RedoStatement struct {
+	*BaseNode
+}
+
+func (rs *RedoStatement) statementNode() {}
+
+// TokenLiteral returns token's literal
+func (rs *RedoStatement) TokenLiteral() string {
+	return rs.Token.Literal
+}
+func (rs *RedoStatement) String() string {
+	return "redo"
+}
+
 type WhileStatement struct {
 	*BaseNode
 	Condition Expression
@@ -180,6 +220,29 @@ func (ws *WhileStatement) String() string {
 	return out.String()
 }
 
+// UntilStatement represents `until ... end`, which executes its body until the condition becomes truthy
+type UntilStatement struct {
+	*BaseNode
+	Condition Expression
+	Body      *BlockStatement
+}
+
+func (us *UntilStatement) statementNode() {}
+func (us *UntilStatement) TokenLiteral() string {
+	return us.Token.Literal
+}
+func (us *UntilStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("until ")
+	out.WriteString(us.Condition.String())
+	out.WriteString(" do\n")
+	out.WriteString(us.Body.String())
+	out.WriteString("\nend")
+
+	return out.String()
+}
+
 type BlockStatement struct {
 	*BaseNode
 	Statements []Statement
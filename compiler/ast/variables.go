@@ -5,7 +5,8 @@ import (
 	"strings"
 )
 
-// Variable interface represents assignable nodes in Goby, currently are Identifier, InstanceVariable and Constant
+// Variable interface represents assignable nodes in Goby, currently are Identifier, InstanceVariable, ClassVariable,
+// GlobalVariable and Constant
 type Variable interface {
 	variableNode()
 	ReturnValue() string
@@ -69,10 +70,50 @@ func (iv *InstanceVariable) String() string {
 	return iv.Value
 }
 
+type ClassVariable struct {
+	*BaseNode
+	Value string
+	// IsOptional is true when the class variable is read by `@@foo ||= x`, so it's nil instead of an error if
+	// it's not initialized yet
+	IsOptional bool
+}
+
+func (cv *ClassVariable) variableNode() {}
+func (cv *ClassVariable) ReturnValue() string {
+	return cv.Value
+}
+func (cv *ClassVariable) expressionNode() {}
+func (cv *ClassVariable) TokenLiteral() string {
+	return cv.Token.Literal
+}
+func (cv *ClassVariable) String() string {
+	return cv.Value
+}
+
+type GlobalVariable struct {
+	*BaseNode
+	Value string
+}
+
+func (gv *GlobalVariable) variableNode() {}
+func (gv *GlobalVariable) ReturnValue() string {
+	return gv.Value
+}
+func (gv *GlobalVariable) expressionNode() {}
+func (gv *GlobalVariable) TokenLiteral() string {
+	return gv.Token.Literal
+}
+func (gv *GlobalVariable) String() string {
+	return gv.Value
+}
+
 type Constant struct {
 	*BaseNode
 	Value       string
 	IsNamespace bool
+	// IsOptional is true when the constant is read by `Foo ||= x`, so it's nil instead of an error if it's not
+	// initialized yet
+	IsOptional bool
 }
 
 func (c *Constant) variableNode() {}
@@ -9,13 +9,35 @@ func (g *Generator) compileExpression(is *InstructionSet, exp ast.Expression, sc
 	sourceLine := exp.Line()
 	switch exp := exp.(type) {
 	case *ast.Constant:
+		if exp.IsOptional {
+			is.define(GetConstant, sourceLine, exp.Value, fmt.Sprint(exp.IsNamespace), 1)
+			return
+		}
+
 		is.define(GetConstant, sourceLine, exp.Value, fmt.Sprint(exp.IsNamespace))
 	case *ast.InstanceVariable:
 		is.define(GetInstanceVariable, sourceLine, exp.Value)
+	case *ast.ClassVariable:
+		if exp.IsOptional {
+			is.define(GetClassVariable, sourceLine, exp.Value, 1)
+			return
+		}
+
+		is.define(GetClassVariable, sourceLine, exp.Value)
+	case *ast.GlobalVariable:
+		is.define(GetGlobalVariable, sourceLine, exp.Value)
 	case *ast.IntegerLiteral:
 		is.define(PutObject, sourceLine, fmt.Sprint(exp.Value))
+	case *ast.FloatLiteral:
+		is.define(PutFloat, sourceLine, exp.TokenLiteral())
 	case *ast.StringLiteral:
 		is.define(PutString, sourceLine, exp.Value)
+	case *ast.SymbolLiteral:
+		is.define(PutSymbol, sourceLine, exp.Value)
+	case *ast.InterpolatedString:
+		g.compileInterpolatedString(is, exp, scope, table)
+	case *ast.RegexpLiteral:
+		is.define(NewRegexp, sourceLine, exp.Value, exp.Flags)
 	case *ast.BooleanExpression:
 		is.define(PutObject, sourceLine, fmt.Sprint(exp.Value))
 	case *ast.NilExpression:
@@ -23,7 +45,11 @@ func (g *Generator) compileExpression(is *InstructionSet, exp ast.Expression, sc
 	case *ast.RangeExpression:
 		g.compileExpression(is, exp.Start, scope, table)
 		g.compileExpression(is, exp.End, scope, table)
-		is.define(NewRange, sourceLine, 0)
+		if exp.Exclusive {
+			is.define(NewRange, sourceLine, 1)
+		} else {
+			is.define(NewRange, sourceLine, 0)
+		}
 	case *ast.ArrayExpression:
 		for _, elem := range exp.Elements {
 			g.compileExpression(is, elem, scope, table)
@@ -45,10 +71,20 @@ func (g *Generator) compileExpression(is *InstructionSet, exp ast.Expression, sc
 		g.compileIdentifier(is, exp, scope, table)
 	case *ast.AssignExpression:
 		g.compileAssignExpression(is, exp, scope, table)
+	case *ast.CallAssignExpression:
+		g.compileCallAssignExpression(is, exp, scope, table)
 	case *ast.IfExpression:
 		g.compileIfExpression(is, exp, scope, table)
+	case *ast.UnlessExpression:
+		g.compileUnlessExpression(is, exp, scope, table)
+	case *ast.BeginExpression:
+		g.compileBeginExpression(is, exp, scope, table)
+	case *ast.CaseExpression:
+		g.compileCaseExpression(is, exp, scope, table)
 	case *ast.YieldExpression:
 		g.compileYieldExpression(is, exp, scope, table)
+	case *ast.SuperExpression:
+		g.compileSuperExpression(is, exp, scope, table)
 	case *ast.CallExpression:
 		g.compileCallExpression(is, exp, scope, table)
 	}
@@ -67,6 +103,27 @@ func (g *Generator) compileIdentifier(is *InstructionSet, exp *ast.Identifier, s
 	is.define(Send, exp.Line(), exp.Value, 0)
 }
 
+// compileInterpolatedString converts each interpolated expression into string with `to_s`,
+// and concatenates all segments with `+`
+func (g *Generator) compileInterpolatedString(is *InstructionSet, exp *ast.InterpolatedString, scope *scope, table *localTable) {
+	if len(exp.Segments) == 0 {
+		is.define(PutString, exp.Line(), "")
+		return
+	}
+
+	for i, segment := range exp.Segments {
+		g.compileExpression(is, segment, scope, table)
+
+		if _, ok := segment.(*ast.StringLiteral); !ok {
+			is.define(Send, exp.Line(), "to_s", 0)
+		}
+
+		if i > 0 {
+			is.define(Send, exp.Line(), "+", 1)
+		}
+	}
+}
+
 func (g *Generator) compileYieldExpression(is *InstructionSet, exp *ast.YieldExpression, scope *scope, table *localTable) {
 	is.define(PutSelf, exp.Line())
 
@@ -77,13 +134,72 @@ func (g *Generator) compileYieldExpression(is *InstructionSet, exp *ast.YieldExp
 	is.define(InvokeBlock, exp.Line(), len(exp.Arguments))
 }
 
+// compileSuperExpression compiles `super` like a method call on self, whose arguments are followed by
+// SuperForwardArgs for bare `super` or SuperExplicitArgs otherwise
+func (g *Generator) compileSuperExpression(is *InstructionSet, exp *ast.SuperExpression, scope *scope, table *localTable) {
+	is.define(PutSelf, exp.Line())
+
+	mode := SuperExplicitArgs
+	if exp.ForwardArgs {
+		mode = SuperForwardArgs
+	}
+
+	args := exp.Arguments
+	var blockArg ast.Expression
+
+	if len(args) > 0 && isBlockPass(args[len(args)-1]) {
+		blockArg = args[len(args)-1].(*ast.PrefixExpression).Right
+		args = args[:len(args)-1]
+	}
+
+	for _, arg := range args {
+		g.compileExpression(is, arg, scope, table)
+	}
+
+	switch {
+	case blockArg != nil:
+		g.compileExpression(is, blockArg, scope, table)
+		is.define(InvokeSuper, exp.Line(), len(args), mode, "block:&")
+	case exp.Block != nil:
+		newTable := newLocalTable(table.depth + 1)
+		newTable.upper = table
+		blockIndex := g.blockCounter
+		g.blockCounter++
+		g.compileBlockArgExpression(blockIndex, exp.CallExpression, scope, newTable)
+		is.define(InvokeSuper, exp.Line(), len(args), mode, fmt.Sprintf("block:%d", blockIndex))
+	default:
+		is.define(InvokeSuper, exp.Line(), len(args), mode)
+	}
+}
+
+// isBlockPass returns true if the argument is a proc passed as block like `&block`
+func isBlockPass(arg ast.Expression) bool {
+	prefix, ok := arg.(*ast.PrefixExpression)
+	return ok && prefix.Operator == "&"
+}
+
 func (g *Generator) compileCallExpression(is *InstructionSet, exp *ast.CallExpression, scope *scope, table *localTable) {
 	g.compileExpression(is, exp.Receiver, scope, table)
 
-	for _, arg := range exp.Arguments {
+	args := exp.Arguments
+	var blockArg ast.Expression
+
+	// A proc passed as block like `foo(&block)` is always the last argument
+	if len(args) > 0 && isBlockPass(args[len(args)-1]) {
+		blockArg = args[len(args)-1].(*ast.PrefixExpression).Right
+		args = args[:len(args)-1]
+	}
+
+	for _, arg := range args {
 		g.compileExpression(is, arg, scope, table)
 	}
 
+	if blockArg != nil {
+		g.compileExpression(is, blockArg, scope, table)
+		is.define(Send, exp.Line(), exp.Method, len(args), "block:&")
+		return
+	}
+
 	if exp.Block != nil {
 		// Inside block should be one level deeper than outside
 		newTable := newLocalTable(table.depth + 1)
@@ -117,6 +233,10 @@ func (g *Generator) compileAssignExpression(is *InstructionSet, exp *ast.AssignE
 			is.define(SetLocal, exp.Line(), depth, index)
 		case *ast.InstanceVariable:
 			is.define(SetInstanceVariable, exp.Line(), name.Value)
+		case *ast.ClassVariable:
+			is.define(SetClassVariable, exp.Line(), name.Value)
+		case *ast.GlobalVariable:
+			is.define(SetGlobalVariable, exp.Line(), name.Value)
 		case *ast.Constant:
 			is.define(SetConstant, exp.Line(), name.Value)
 		}
@@ -136,18 +256,93 @@ func (g *Generator) compileAssignExpression(is *InstructionSet, exp *ast.AssignE
 	}
 }
 
+// compileCallAssignExpression compiles `a[i] += b` or `a[i] ||= b`, the receiver and arguments are duplicated
+// for the setter so they're only evaluated once
+func (g *Generator) compileCallAssignExpression(is *InstructionSet, exp *ast.CallAssignExpression, scope *scope, table *localTable) {
+	sourceLine := exp.Line()
+	argCount := len(exp.Call.Arguments)
+
+	g.compileExpression(is, exp.Call.Receiver, scope, table)
+
+	for _, arg := range exp.Call.Arguments {
+		g.compileExpression(is, arg, scope, table)
+	}
+
+	is.define(DupN, sourceLine, argCount+1)
+	is.define(Send, sourceLine, exp.Call.Method, argCount)
+
+	switch exp.Operator {
+	case "||", "&&":
+		anchorSkip := &anchor{}
+		anchorEnd := &anchor{}
+		branch := BranchIf
+
+		if exp.Operator == "&&" {
+			branch = BranchUnless
+		}
+
+		is.define(Dup, sourceLine)
+		is.define(branch, sourceLine, anchorSkip)
+		is.define(Pop, sourceLine)
+		g.compileExpression(is, exp.Value, scope, table)
+		is.define(Send, sourceLine, exp.Setter, argCount+1)
+		is.define(Jump, sourceLine, anchorEnd)
+
+		// The getter's value replaces the receiver when the setter isn't called
+		anchorSkip.line = len(is.Instructions)
+		is.define(SetN, sourceLine, argCount+1)
+
+		for i := 0; i <= argCount; i++ {
+			is.define(Pop, sourceLine)
+		}
+
+		anchorEnd.line = len(is.Instructions)
+	default:
+		g.compileExpression(is, exp.Value, scope, table)
+		is.define(Send, sourceLine, exp.Operator, 1)
+		is.define(Send, sourceLine, exp.Setter, argCount+1)
+	}
+}
+
 func (g *Generator) compileBlockArgExpression(index int, exp *ast.CallExpression, scope *scope, table *localTable) {
 	is := &InstructionSet{}
 	is.name = fmt.Sprint(index)
 	is.isType = Block
 
-	for i := 0; i < len(exp.BlockArguments); i++ {
-		table.set(exp.BlockArguments[i].Value)
+	// Anchors can only be jumped to in the same instruction set, so the block doesn't share them with outer scope
+	outerAnchors := scope.anchors
+	outerRegions := scope.regions
+	scope.anchors = make(map[string]*anchor)
+	scope.regions = nil
+
+	for _, arg := range exp.BlockArguments {
+		switch arg := arg.(type) {
+		case *ast.Identifier:
+			table.set(arg.Value)
+			is.argTypes = append(is.argTypes, NormalArg)
+		case *ast.AssignExpression:
+			table.set(arg.Variables[0].(*ast.Identifier).Value)
+			is.argTypes = append(is.argTypes, OptionedArg)
+		}
+
+		is.argNames = append(is.argNames, getArgName(arg))
 	}
 
+	// Default values are assigned after all arguments are set, so they can refer to previous arguments
+	for _, arg := range exp.BlockArguments {
+		if assignment, ok := arg.(*ast.AssignExpression); ok {
+			g.compileAssignExpression(is, assignment, scope, table)
+		}
+	}
+
+	scope.anchors["redo"] = &anchor{is.count}
+
 	g.compileCodeBlock(is, exp.Block, scope, table)
 	g.endInstructions(is, exp.Line())
 	g.instructionSets = append(g.instructionSets, is)
+
+	scope.anchors = outerAnchors
+	scope.regions = outerRegions
 }
 
 func (g *Generator) compileIfExpression(is *InstructionSet, exp *ast.IfExpression, scope *scope, table *localTable) {
@@ -177,18 +372,224 @@ func (g *Generator) compileIfExpression(is *InstructionSet, exp *ast.IfExpressio
 	anchorLast.line = is.count
 }
 
+// An unless expression is compiled like an if expression without elsif, but it branches when the condition is truthy
+func (g *Generator) compileUnlessExpression(is *InstructionSet, exp *ast.UnlessExpression, scope *scope, table *localTable) {
+	anchorAlternative := &anchor{}
+	anchorLast := &anchor{}
+
+	g.compileExpression(is, exp.Condition, scope, table)
+	is.define(BranchIf, exp.Line(), anchorAlternative)
+
+	g.compileCodeBlock(is, exp.Consequence, scope, table)
+	anchorAlternative.line = is.count + 1
+	is.define(Jump, exp.Line(), anchorLast)
+
+	if exp.Alternative == nil {
+		// jump over the `putnil` in false case
+		anchorLast.line = is.count + 1
+		is.define(PutNull, exp.Line())
+
+		return
+	}
+
+	g.compileCodeBlock(is, exp.Alternative, scope, table)
+
+	anchorLast.line = is.count
+}
+
+// A begin expression registers a rescue handler with `set_rescue`, which the vm jumps to with the raised error on
+// the stack, and removes it with `pop_rescue` when the body finishes normally.
+// The handler compares the error with every rescue clause's classes, and raises it again if no clause matches.
+// A rescue clause ends with `end_rescue`, which tells the vm that the error isn't being handled anymore.
+// Ensure clause has its own handler, which runs ensure's statements and then raises the error again.
+func (g *Generator) compileBeginExpression(is *InstructionSet, exp *ast.BeginExpression, scope *scope, table *localTable) {
+	anchorEnsure := &anchor{}
+
+	if exp.Ensure != nil {
+		is.define(SetRescue, exp.Line(), anchorEnsure, EnsureHandler)
+		g.enterRegion(scope, ensureRegion, exp.Ensure)
+	}
+
+	if len(exp.Rescues) == 0 {
+		g.compileBlockValue(is, exp.Body, exp.Line(), scope, table)
+	} else {
+		g.compileRescueClauses(is, exp, scope, table)
+	}
+
+	if exp.Ensure == nil {
+		return
+	}
+
+	g.leaveRegion(scope)
+	anchorLast := &anchor{}
+
+	is.define(PopRescue, exp.Line())
+	g.compileEnsureClause(is, exp.Ensure, scope, table)
+	is.define(Jump, exp.Line(), anchorLast)
+
+	anchorEnsure.line = is.count
+	g.compileEnsureClause(is, exp.Ensure, scope, table)
+	is.define(Raise, exp.Line())
+
+	anchorLast.line = is.count
+}
+
+func (g *Generator) compileRescueClauses(is *InstructionSet, exp *ast.BeginExpression, scope *scope, table *localTable) {
+	anchorRescue := &anchor{}
+	anchorLast := &anchor{}
+	anchorRetry := &anchor{is.count}
+
+	is.define(SetRescue, exp.Line(), anchorRescue)
+	g.enterRegion(scope, rescueRegion, nil)
+	g.compileBlockValue(is, exp.Body, exp.Line(), scope, table)
+	g.leaveRegion(scope)
+	is.define(PopRescue, exp.Line())
+	is.define(Jump, exp.Line(), anchorLast)
+
+	anchorRescue.line = is.count
+	anchorClauses := make([]*anchor, len(exp.Rescues))
+
+	for i, r := range exp.Rescues {
+		anchorClauses[i] = &anchor{}
+
+		// `rescue` without classes rescues StandardError
+		if len(r.ExceptionClasses) == 0 {
+			is.define(Dup, r.Line())
+			is.define(GetConstant, r.Line(), "StandardError", "false")
+			is.define(Send, r.Line(), "is_a?", 1)
+			is.define(BranchIf, r.Line(), anchorClauses[i])
+			continue
+		}
+
+		for _, c := range r.ExceptionClasses {
+			is.define(Dup, r.Line())
+			g.compileExpression(is, c, scope, table)
+			is.define(Send, r.Line(), "is_a?", 1)
+			is.define(BranchIf, r.Line(), anchorClauses[i])
+		}
+	}
+
+	is.define(Raise, exp.Line())
+
+	outerRetry := scope.anchors["retry"]
+	scope.anchors["retry"] = anchorRetry
+
+	for i, r := range exp.Rescues {
+		anchorClauses[i].line = is.count
+
+		if r.Variable != nil {
+			index, depth := table.setLCL(r.Variable.Value, table.depth)
+			is.define(SetLocal, r.Line(), depth, index)
+		}
+
+		is.define(Pop, r.Line())
+		g.enterRegion(scope, rescueClauseRegion, nil)
+		g.compileBlockValue(is, r.Body, r.Line(), scope, table)
+		g.leaveRegion(scope)
+		is.define(EndRescue, r.Line())
+		is.define(Jump, r.Line(), anchorLast)
+	}
+
+	scope.anchors["retry"] = outerRetry
+	anchorLast.line = is.count
+}
+
+// enterRegion starts a region of a begin expression, which ends with leaveRegion
+func (g *Generator) enterRegion(scope *scope, kind int, ensure *ast.BlockStatement) {
+	anchors := map[string]*anchor{}
+
+	for name, a := range scope.anchors {
+		anchors[name] = a
+	}
+
+	scope.regions = append(scope.regions, &region{kind: kind, ensure: ensure, anchors: anchors})
+}
+
+func (g *Generator) leaveRegion(scope *scope) {
+	scope.regions = scope.regions[:len(scope.regions)-1]
+}
+
+// compileJumpOut cleans up the regions that a jump to the anchor with the given name leaves, which are the ones
+// started outside of the loop or the rescue clause that the anchor belongs to
+func (g *Generator) compileJumpOut(is *InstructionSet, name string, sourceLine int, scope *scope, table *localTable) {
+	target := scope.anchors[name]
+
+	g.compileRegionExits(is, sourceLine, scope, table, func(r *region) bool {
+		return r.anchors[name] == target
+	})
+}
+
+// compileLeaveOut cleans up all the regions of the instruction set, before the frame is left by `return`, `next`
+// or `break`
+func (g *Generator) compileLeaveOut(is *InstructionSet, sourceLine int, scope *scope, table *localTable) {
+	g.compileRegionExits(is, sourceLine, scope, table, func(r *region) bool {
+		return true
+	})
+}
+
+// compileRegionExits cleans up the regions from the innermost one while they're left: rescue handlers are popped,
+// rescued errors are ended and ensure clauses are executed. Each ensure clause is compiled outside of its region,
+// so jumps in it don't execute it again.
+func (g *Generator) compileRegionExits(is *InstructionSet, sourceLine int, scope *scope, table *localTable, leaves func(r *region) bool) {
+	regions := scope.regions
+
+	for i := len(regions) - 1; i >= 0 && leaves(regions[i]); i-- {
+		switch regions[i].kind {
+		case rescueRegion:
+			is.define(PopRescue, sourceLine)
+		case rescueClauseRegion:
+			is.define(EndRescue, sourceLine)
+		case ensureRegion:
+			is.define(PopRescue, sourceLine)
+			scope.regions = regions[:i]
+			g.compileEnsureClause(is, regions[i].ensure, scope, table)
+			scope.regions = regions
+		}
+	}
+}
+
+// compileBlockValue compiles the block and makes sure it leaves a value, which is nil if it doesn't end with an expression
+func (g *Generator) compileBlockValue(is *InstructionSet, block *ast.BlockStatement, sourceLine int, scope *scope, table *localTable) {
+	g.compileCodeBlock(is, block, scope, table)
+
+	if len(block.Statements) == 0 {
+		is.define(PutNull, sourceLine)
+		return
+	}
+
+	if _, ok := block.Statements[len(block.Statements)-1].(*ast.ExpressionStatement); !ok {
+		is.define(PutNull, sourceLine)
+	}
+}
+
+// compileEnsureClause compiles ensure's statements and discards their values
+func (g *Generator) compileEnsureClause(is *InstructionSet, block *ast.BlockStatement, scope *scope, table *localTable) {
+	for _, s := range block.Statements {
+		expStmt, ok := s.(*ast.ExpressionStatement)
+
+		if !ok {
+			g.compileStatement(is, s, scope, table)
+			continue
+		}
+
+		scope.line++
+		g.compileExpression(is, expStmt.Expression, scope, table)
+		is.define(Pop, s.Line())
+	}
+}
+
 func (g *Generator) compilePrefixExpression(is *InstructionSet, exp *ast.PrefixExpression, scope *scope, table *localTable) {
 	switch exp.Operator {
-	case "!":
+	case "!", "~":
 		g.compileExpression(is, exp.Right, scope, table)
 		is.define(Send, exp.Line(), exp.Operator, 0)
 	case "*":
 		g.compileExpression(is, exp.Right, scope, table)
 		is.define(SplatArray, exp.Line())
 	case "-":
-		is.define(PutObject, exp.Line(), 0)
+		// Unary minus is sent as `-@`, so classes can define their own negation
 		g.compileExpression(is, exp.Right, scope, table)
-		is.define(Send, exp.Line(), exp.Operator, 1)
+		is.define(Send, exp.Line(), "-@", 0)
 	}
 }
 
@@ -190,6 +190,177 @@ func TestIfExpressionWithElsifAndAlternativeCompilation(t *testing.T) {
 	compareBytecode(t, bytecode, expected)
 }
 
+func TestUnlessExpressionCompilation(t *testing.T) {
+	input := `
+	a = 10
+	b = 5
+	unless a > b
+	  c = 10
+	else
+	  c = 5
+	end
+
+	c + 1
+	`
+
+	expected := `
+<ProgramStart>
+0 putobject 10
+1 setlocal 0 0
+2 pop
+3 putobject 5
+4 setlocal 0 1
+5 pop
+6 getlocal 0 0
+7 getlocal 0 1
+8 send > 1
+9 branchif 13
+10 putobject 10
+11 setlocal 0 2
+12 jump 15
+13 putobject 5
+14 setlocal 0 2
+15 pop
+16 getlocal 0 2
+17 putobject 1
+18 send + 1
+19 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestBeginExpressionCompilation(t *testing.T) {
+	input := `
+	a = begin
+	  foo
+	rescue FooError => e
+	  e
+	rescue
+	  retry
+	ensure
+	  bar
+	end
+	a
+	`
+
+	expected := `
+<ProgramStart>
+0 set_rescue 31 ensure
+1 set_rescue 6
+2 putself
+3 send foo 0
+4 pop_rescue
+5 jump 26
+6 dup
+7 getconstant FooError false
+8 send is_a? 1
+9 branchif 15
+10 dup
+11 getconstant StandardError false
+12 send is_a? 1
+13 branchif 20
+14 raise
+15 setlocal 0 0
+16 pop
+17 getlocal 0 0
+18 end_rescue
+19 jump 26
+20 pop
+21 end_rescue
+22 jump 1
+23 putnil
+24 end_rescue
+25 jump 26
+26 pop_rescue
+27 putself
+28 send bar 0
+29 pop
+30 jump 35
+31 putself
+32 send bar 0
+33 pop
+34 raise
+35 setlocal 0 1
+36 pop
+37 getlocal 0 1
+38 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestBeginExpressionWithJumpCompilation(t *testing.T) {
+	input := `
+	i = 0
+	while i < 3 do
+	  begin
+	    break
+	  rescue
+	    retry
+	  ensure
+	    foo
+	  end
+	end
+	`
+
+	// break pops the handlers and executes the ensure clause, retry only ends the rescued error
+	expected := `
+<ProgramStart>
+0 putobject 0
+1 setlocal 0 0
+2 pop
+3 jump 39
+4 putnil
+5 pop
+6 jump 39
+7 set_rescue 34 ensure
+8 set_rescue 18
+9 pop_rescue
+10 pop_rescue
+11 putself
+12 send foo 0
+13 pop
+14 jump 45
+15 putnil
+16 pop_rescue
+17 jump 29
+18 dup
+19 getconstant StandardError false
+20 send is_a? 1
+21 branchif 23
+22 raise
+23 pop
+24 end_rescue
+25 jump 8
+26 putnil
+27 end_rescue
+28 jump 29
+29 pop_rescue
+30 putself
+31 send foo 0
+32 pop
+33 jump 38
+34 putself
+35 send foo 0
+36 pop
+37 raise
+38 pop
+39 getlocal 0 0
+40 putobject 3
+41 send < 1
+42 branchif 7
+43 putnil
+44 pop
+45 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestMultipleVariableAssignmentCompilation(t *testing.T) {
 	input := `
 
@@ -275,6 +446,71 @@ func TestBooleanCompilation(t *testing.T) {
 	compareBytecode(t, bytecode, expected)
 }
 
+func TestMinusPrefixCompilation(t *testing.T) {
+	input := `
+	a = 1
+	-a
+`
+	expected := `
+<ProgramStart>
+0 putobject 1
+1 setlocal 0 0
+2 pop
+3 getlocal 0 0
+4 send -@ 0
+5 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestSuperCompilation(t *testing.T) {
+	input := `
+def foo(a)
+  super
+  super()
+  super(a, 1)
+  super(&a)
+  super do |x|
+    x
+  end
+end
+`
+	expected := `
+<Block:0>
+0 getlocal 0 0
+1 leave
+<Def:foo>
+0 putself
+1 invokesuper 0 forward
+2 pop
+3 putself
+4 invokesuper 0 explicit
+5 pop
+6 putself
+7 getlocal 0 0
+8 putobject 1
+9 invokesuper 2 explicit
+10 pop
+11 putself
+12 getlocal 0 0
+13 invokesuper 0 explicit block:&
+14 pop
+15 putself
+16 invokesuper 0 forward block:0
+17 leave
+<ProgramStart>
+0 putself
+1 putstring foo
+2 def_method 1
+3 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestArrayCompilation(t *testing.T) {
 	input := `
 	a = [1, 2, "bar"]
@@ -402,6 +638,163 @@ Got:
 
 }
 
+func TestCompoundAssignmentCompilation(t *testing.T) {
+	input := `
+	Foo ||= 1
+	a.b += 2
+	a[i] ||= 3
+	`
+
+	expected := `
+<ProgramStart>
+0 getconstant Foo false 1
+1 dup
+2 branchif 6
+3 pop
+4 putobject 1
+5 setconstant Foo
+6 pop
+7 putself
+8 send a 0
+9 dupn 1
+10 send b 0
+11 putobject 2
+12 send + 1
+13 send b= 1
+14 pop
+15 putself
+16 send a 0
+17 putself
+18 send i 0
+19 dupn 2
+20 send [] 1
+21 dup
+22 branchif 27
+23 pop
+24 putobject 3
+25 send []= 2
+26 jump 30
+27 setn 2
+28 pop
+29 pop
+30 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestGlobalVariableCompilation(t *testing.T) {
+	input := `
+	$foo = 1
+	$foo += $bar
+	`
+
+	expected := `
+<ProgramStart>
+0 putobject 1
+1 setglobalvariable $foo
+2 pop
+3 getglobalvariable $foo
+4 getglobalvariable $bar
+5 send + 1
+6 setglobalvariable $foo
+7 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestClassVariableCompilation(t *testing.T) {
+	input := `
+	@@foo = 1
+	@@foo += @@bar
+	@@baz ||= 2
+	`
+
+	expected := `
+<ProgramStart>
+0 putobject 1
+1 setclassvariable @@foo
+2 pop
+3 getclassvariable @@foo
+4 getclassvariable @@bar
+5 send + 1
+6 setclassvariable @@foo
+7 pop
+8 getclassvariable @@baz 1
+9 dup
+10 branchif 13
+11 pop
+12 putobject 2
+13 setclassvariable @@baz
+14 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestCaseExpressionCompilation(t *testing.T) {
+	input := `
+	case 1
+	in [a, *] if a
+	  a
+	in { b: }
+	  b
+	end
+	`
+
+	expected := `
+<ProgramStart>
+0 putobject 1
+1 dup
+2 deconstruct 2 1
+3 branchunless 14
+4 dup
+5 putobject 0
+6 send [] 1
+7 setlocal 0 0
+8 pop
+9 putobject true
+10 branchunless 14
+11 pop
+12 putobject true
+13 jump 16
+14 pop
+15 putobject false
+16 branchunless 22
+17 getlocal 0 0
+18 branchunless 22
+19 pop
+20 getlocal 0 0
+21 jump 40
+22 dup
+23 deconstruct_keys b
+24 branchunless 33
+25 dup
+26 putstring b
+27 send [] 1
+28 setlocal 0 1
+29 pop
+30 pop
+31 putobject true
+32 jump 35
+33 pop
+34 putobject false
+35 branchunless 39
+36 pop
+37 getlocal 0 1
+38 jump 40
+39 no_matching_pattern
+40 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestRangeCompilation(t *testing.T) {
 	input := `
 	(1..(1+4)).each do |i|
@@ -429,6 +822,109 @@ func TestRangeCompilation(t *testing.T) {
 	compareBytecode(t, bytecode, expected)
 }
 
+func TestExclusiveRangeCompilation(t *testing.T) {
+	input := `
+	(1...5).to_a
+	`
+
+	expected := `
+<ProgramStart>
+0 putobject 1
+1 putobject 5
+2 newrange 1
+3 send to_a 0
+4 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestRegexpCompilation(t *testing.T) {
+	input := `
+	"Goby" =~ /g(o)by/i
+	`
+
+	expected := `
+<ProgramStart>
+0 putstring Goby
+1 newregexp g(o)by i
+2 send =~ 1
+3 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestFloatCompilation(t *testing.T) {
+	input := `
+	a = 1.5
+	a * 2e3
+	`
+
+	expected := `
+<ProgramStart>
+0 putfloat 1.5
+1 setlocal 0 0
+2 pop
+3 getlocal 0 0
+4 putfloat 2e3
+5 send * 1
+6 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestSymbolCompilation(t *testing.T) {
+	input := `
+	h = {}
+	h[:foo?]
+	`
+
+	expected := `
+<ProgramStart>
+0 newhash 0
+1 setlocal 0 0
+2 pop
+3 getlocal 0 0
+4 putsymbol foo?
+5 send [] 1
+6 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestInterpolatedStringCompilation(t *testing.T) {
+	input := `
+	a = 1
+	"a is #{a + 1}!"
+	`
+
+	expected := `
+<ProgramStart>
+0 putobject 1
+1 setlocal 0 0
+2 pop
+3 putstring a is 
+4 getlocal 0 0
+5 putobject 1
+6 send + 1
+7 send to_s 0
+8 send + 1
+9 putstring !
+10 send + 1
+11 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestUnusedExpressionRemoval(t *testing.T) {
 	input := `
 	i = 0
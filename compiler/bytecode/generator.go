@@ -13,6 +13,30 @@ type scope struct {
 	localTable *localTable
 	line       int
 	anchors    map[string]*anchor
+	// visibility is set by `private`, `protected` or `public` in class body, and applies to the methods defined after it
+	visibility string
+	// regions are the parts of begin expressions being compiled, the innermost one is the last
+	regions []*region
+}
+
+// Kinds of the regions of a begin expression, which need to be cleaned up when a jump leaves them
+const (
+	// rescueRegion is the body of a begin expression with rescue clauses, its rescue handler needs to be popped
+	rescueRegion = iota
+	// rescueClauseRegion is a rescue clause, the error it handles needs to be ended
+	rescueClauseRegion
+	// ensureRegion is the body and the rescue clauses of a begin expression with an ensure clause, its handler
+	// needs to be popped and the ensure clause needs to be executed
+	ensureRegion
+)
+
+// region is a part of a begin expression. `return`, `break`, `next`, `redo` and `retry` that leave it clean it up
+// before jumping, so the ensure clauses are executed.
+type region struct {
+	kind   int
+	ensure *ast.BlockStatement
+	// anchors are the scope's anchors when the region starts, a jump to one of them leaves the region
+	anchors map[string]*anchor
 }
 
 func newScope(stmt ast.Statement) *scope {
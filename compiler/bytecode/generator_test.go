@@ -176,6 +176,34 @@ puts(x)
 	compareBytecode(t, bytecode, expected)
 }
 
+func TestCallWithProcAsBlockCompilation(t *testing.T) {
+	input := `
+def foo(a, &block)
+  block
+end
+
+foo(1, &bar)
+`
+	expected := `
+<Def:foo>
+0 getlocal 0 1
+1 leave
+<ProgramStart>
+0 putself
+1 putstring foo
+2 def_method 2
+3 putself
+4 putobject 1
+5 putself
+6 send bar 0
+7 send foo 1 block:&
+8 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestArithmeticCompilation(t *testing.T) {
 	input := `
 	(1 * 10 + 100) / 2
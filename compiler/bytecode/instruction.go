@@ -19,10 +19,16 @@ const (
 	GetLocal            = "getlocal"
 	GetConstant         = "getconstant"
 	GetInstanceVariable = "getinstancevariable"
+	GetClassVariable    = "getclassvariable"
+	GetGlobalVariable   = "getglobalvariable"
 	SetLocal            = "setlocal"
 	SetConstant         = "setconstant"
 	SetInstanceVariable = "setinstancevariable"
+	SetClassVariable    = "setclassvariable"
+	SetGlobalVariable   = "setglobalvariable"
 	PutString           = "putstring"
+	PutFloat            = "putfloat"
+	PutSymbol           = "putsymbol"
 	PutSelf             = "putself"
 	PutObject           = "putobject"
 	PutNull             = "putnil"
@@ -31,19 +37,44 @@ const (
 	SplatArray          = "splat_array"
 	NewHash             = "newhash"
 	NewRange            = "newrange"
+	NewRegexp           = "newregexp"
 	BranchUnless        = "branchunless"
 	BranchIf            = "branchif"
 	Jump                = "jump"
+	SetRescue           = "set_rescue"
+	PopRescue           = "pop_rescue"
+	EndRescue           = "end_rescue"
+	Raise               = "raise"
 	DefMethod           = "def_method"
 	DefSingletonMethod  = "def_singleton_method"
 	DefClass            = "def_class"
 	Send                = "send"
 	InvokeBlock         = "invokeblock"
+	InvokeSuper         = "invokesuper"
 	Pop                 = "pop"
 	Dup                 = "dup"
+	DupN                = "dupn"
+	SetN                = "setn"
+	CheckMatch          = "checkmatch"
+	Deconstruct         = "deconstruct"
+	DeconstructKeys     = "deconstruct_keys"
+	NoMatchingPattern   = "no_matching_pattern"
+	Break               = "break"
+	Return              = "return"
 	Leave               = "leave"
 )
 
+// How `invokesuper` gets its arguments: SuperForwardArgs passes the current method's arguments for bare `super`,
+// and SuperExplicitArgs passes the ones given to `super(...)`
+const (
+	SuperForwardArgs  = "forward"
+	SuperExplicitArgs = "explicit"
+)
+
+// EnsureHandler marks the `set_rescue` of an ensure clause, its handler also runs the clause when `break` or
+// `return` in a block unwinds the frame
+const EnsureHandler = "ensure"
+
 // Instruction represents compiled bytecode instruction
 type Instruction struct {
 	Action     string
@@ -73,6 +104,9 @@ func (i *Instruction) SourceLine() int {
 }
 
 func (i *Instruction) compile() string {
+	if i.anchor != nil && len(i.Params) > 0 {
+		return fmt.Sprintf("%d %s %d %s\n", i.line, i.Action, i.anchor.line, strings.Join(i.Params, " "))
+	}
 	if i.anchor != nil {
 		return fmt.Sprintf("%d %s %d\n", i.line, i.Action, i.anchor.line)
 	}
@@ -94,6 +128,7 @@ type InstructionSet struct {
 	Instructions []*Instruction
 	count        int
 	argTypes     []int
+	argNames     []string
 }
 
 // ArgTypes returns enums that represents each argument's type
@@ -101,6 +136,11 @@ func (is *InstructionSet) ArgTypes() []int {
 	return is.argTypes
 }
 
+// ArgNames returns the name of each argument
+func (is *InstructionSet) ArgNames() []string {
+	return is.argNames
+}
+
 // Name returns instruction set's name
 func (is *InstructionSet) Name() string {
 	return is.name
@@ -0,0 +1,193 @@
+package bytecode
+
+import (
+	"github.com/goby-lang/goby/compiler/ast"
+)
+
+/*
+compileCaseExpression keeps the subject on the stack and tests it with each in clause's pattern:
+
+	<subject>
+	dup
+	<pattern>        # replaces the duplicated subject with the result of matching
+	branchunless <next clause>
+	<guard>
+	branchunless <next clause>
+	pop              # the subject
+	<clause's body>
+	jump <end>
+
+If there's no else clause, `no_matching_pattern` raises an error with the subject.
+*/
+func (g *Generator) compileCaseExpression(is *InstructionSet, exp *ast.CaseExpression, scope *scope, table *localTable) {
+	anchorLast := &anchor{}
+
+	g.compileExpression(is, exp.Subject, scope, table)
+
+	for _, c := range exp.Clauses {
+		anchorNext := &anchor{}
+
+		is.define(Dup, c.Line())
+		g.compilePattern(is, c.Pattern, scope, table)
+		is.define(BranchUnless, c.Line(), anchorNext)
+
+		if c.Guard != nil {
+			g.compileExpression(is, c.Guard, scope, table)
+
+			if c.GuardIsUnless {
+				is.define(BranchIf, c.Line(), anchorNext)
+			} else {
+				is.define(BranchUnless, c.Line(), anchorNext)
+			}
+		}
+
+		is.define(Pop, c.Line())
+		g.compileBlockValue(is, c.Consequence, c.Line(), scope, table)
+		is.define(Jump, c.Line(), anchorLast)
+		anchorNext.line = is.count
+	}
+
+	if exp.Alternative == nil {
+		is.define(NoMatchingPattern, exp.Line())
+	} else {
+		is.define(Pop, exp.Line())
+		g.compileBlockValue(is, exp.Alternative, exp.Line(), scope, table)
+	}
+
+	anchorLast.line = is.count
+}
+
+// compilePattern replaces the value on the stack top with a boolean that tells if it matches the pattern,
+// variables in the pattern are assigned during the matching
+func (g *Generator) compilePattern(is *InstructionSet, pattern ast.Expression, scope *scope, table *localTable) {
+	switch p := pattern.(type) {
+	case *ast.ValuePattern:
+		g.compileExpression(is, p.Value, scope, table)
+		is.define(CheckMatch, p.Line())
+	case *ast.VariablePattern:
+		if !p.IsWildcard() {
+			g.compileVariableBinding(is, p.Variable, table)
+		}
+
+		is.define(Pop, p.Line())
+		is.define(PutObject, p.Line(), "true")
+	case *ast.AlternativePattern:
+		anchorMatched := &anchor{}
+		anchorLast := &anchor{}
+		last := len(p.Alternatives) - 1
+
+		for _, alt := range p.Alternatives[:last] {
+			is.define(Dup, p.Line())
+			g.compilePattern(is, alt, scope, table)
+			is.define(BranchIf, p.Line(), anchorMatched)
+		}
+
+		g.compilePattern(is, p.Alternatives[last], scope, table)
+		is.define(Jump, p.Line(), anchorLast)
+		anchorMatched.line = is.count
+		is.define(Pop, p.Line())
+		is.define(PutObject, p.Line(), "true")
+		anchorLast.line = is.count
+	case *ast.CapturePattern:
+		anchorFail := &anchor{}
+
+		is.define(Dup, p.Line())
+		g.compilePattern(is, p.Pattern, scope, table)
+		is.define(BranchUnless, p.Line(), anchorFail)
+		g.compileVariableBinding(is, p.Variable, table)
+		g.compilePatternResult(is, p.Line(), anchorFail)
+	case *ast.ArrayPattern:
+		anchorFail := &anchor{}
+
+		g.compilePatternConstant(is, p.Constant, p.Line(), anchorFail, scope, table)
+		is.define(Deconstruct, p.Line(), len(p.Elements), p.SplatIndex())
+		is.define(BranchUnless, p.Line(), anchorFail)
+
+		for i, e := range p.Elements {
+			if sp, ok := e.(*ast.SplatPattern); ok {
+				if sp.Variable != nil {
+					g.compilePatternElement(is, i, sp.Line())
+					g.compileVariableBinding(is, sp.Variable, table)
+					is.define(Pop, sp.Line())
+				}
+
+				continue
+			}
+
+			g.compilePatternElement(is, i, p.Line())
+			g.compilePattern(is, e, scope, table)
+			is.define(BranchUnless, p.Line(), anchorFail)
+		}
+
+		g.compilePatternResult(is, p.Line(), anchorFail)
+	case *ast.HashPattern:
+		anchorFail := &anchor{}
+
+		g.compilePatternConstant(is, p.Constant, p.Line(), anchorFail, scope, table)
+		keys := []interface{}{}
+
+		for _, key := range p.Keys {
+			keys = append(keys, key)
+		}
+
+		is.define(DeconstructKeys, p.Line(), keys...)
+		is.define(BranchUnless, p.Line(), anchorFail)
+
+		for i, key := range p.Keys {
+			is.define(Dup, p.Line())
+			is.define(PutString, p.Line(), key)
+			is.define(Send, p.Line(), "[]", 1)
+
+			// `{ name: }` assigns the value to `name`
+			if p.Values[i] == nil {
+				g.compileVariableBinding(is, &ast.Identifier{BaseNode: p.BaseNode, Value: key}, table)
+				is.define(Pop, p.Line())
+				continue
+			}
+
+			g.compilePattern(is, p.Values[i], scope, table)
+			is.define(BranchUnless, p.Line(), anchorFail)
+		}
+
+		g.compilePatternResult(is, p.Line(), anchorFail)
+	}
+}
+
+// compilePatternConstant checks the value with the constant of patterns like `Point[x, y]` and jumps to the failure
+// if it doesn't match, the value stays on the stack
+func (g *Generator) compilePatternConstant(is *InstructionSet, constant ast.Expression, sourceLine int, anchorFail *anchor, scope *scope, table *localTable) {
+	if constant == nil {
+		return
+	}
+
+	is.define(Dup, sourceLine)
+	g.compileExpression(is, constant, scope, table)
+	is.define(CheckMatch, sourceLine)
+	is.define(BranchUnless, sourceLine, anchorFail)
+}
+
+// compilePatternElement pushes the element at the index of the deconstructed array on the stack top
+func (g *Generator) compilePatternElement(is *InstructionSet, index int, sourceLine int) {
+	is.define(Dup, sourceLine)
+	is.define(PutObject, sourceLine, index)
+	is.define(Send, sourceLine, "[]", 1)
+}
+
+// compileVariableBinding assigns the stack top to the variable and keeps it on the stack
+func (g *Generator) compileVariableBinding(is *InstructionSet, variable *ast.Identifier, table *localTable) {
+	index, depth := table.setLCL(variable.Value, table.depth)
+	is.define(SetLocal, variable.Line(), depth, index)
+}
+
+// compilePatternResult replaces the matched value with true, or with false if it jumps from the failure anchor
+func (g *Generator) compilePatternResult(is *InstructionSet, sourceLine int, anchorFail *anchor) {
+	anchorLast := &anchor{}
+
+	is.define(Pop, sourceLine)
+	is.define(PutObject, sourceLine, "true")
+	is.define(Jump, sourceLine, anchorLast)
+	anchorFail.line = is.count
+	is.define(Pop, sourceLine)
+	is.define(PutObject, sourceLine, "false")
+	anchorLast.line = is.count
+}
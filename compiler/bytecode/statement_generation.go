@@ -11,6 +11,17 @@ const (
 	NormalArg int = iota
 	OptionedArg
 	SplatArg
+	RequiredKeywordArg
+	OptionalKeywordArg
+	BlockArg
+)
+
+// Method visibilities. ModuleFunction makes a module's method private and also defines a public copy on the module itself.
+const (
+	Public         = "public"
+	Private        = "private"
+	Protected      = "protected"
+	ModuleFunction = "module_function"
 )
 
 func (g *Generator) compileStatements(stmts []ast.Statement, scope *scope, table *localTable) {
@@ -28,6 +39,8 @@ func (g *Generator) compileStatement(is *InstructionSet, statement ast.Statement
 	scope.line++
 	switch stmt := statement.(type) {
 	case *ast.ExpressionStatement:
+		g.setVisibility(stmt.Expression, scope, table)
+
 		if !g.REPL && stmt.Expression.IsStmt() {
 			g.compileExpression(is, stmt.Expression, scope, table)
 			is.define(Pop, statement.Line())
@@ -56,19 +69,36 @@ func (g *Generator) compileStatement(is *InstructionSet, statement ast.Statement
 		g.compileModuleStmt(is, stmt, scope)
 	case *ast.ReturnStatement:
 		g.compileExpression(is, stmt.ReturnValue, scope, table)
+		g.compileLeaveOut(is, stmt.Line(), scope, table)
+
+		// `return` in a block returns from the lambda or the method that the block belongs to, which is decided at runtime
+		if is.isType == Block {
+			is.define(Return, stmt.Line())
+			break
+		}
+
 		g.endInstructions(is, stmt.Line())
 	case *ast.WhileStatement:
-		g.compileWhileStmt(is, stmt, scope, table)
+		g.compileLoopStmt(is, stmt, stmt.Condition, stmt.Body, BranchIf, scope, table)
+	case *ast.UntilStatement:
+		g.compileLoopStmt(is, stmt, stmt.Condition, stmt.Body, BranchUnless, scope, table)
 	case *ast.NextStatement:
-		g.compileNextStatement(is, stmt, scope)
+		g.compileNextStatement(is, stmt, scope, table)
 	case *ast.BreakStatement:
-		g.compileBreakStatement(is, stmt, scope)
+		g.compileBreakStatement(is, stmt, scope, table)
+	case *ast.RedoStatement:
+		g.compileRedoStatement(is, stmt, scope, table)
+	case *ast.RetryStatement:
+		g.compileRetryStatement(is, stmt, scope, table)
 	}
 }
 
-func (g *Generator) compileWhileStmt(is *InstructionSet, stmt *ast.WhileStatement, scope *scope, table *localTable) {
+// compileLoopStmt compiles while and until statements, which jump back to the body with the given branch action
+// after evaluating the condition. While statement uses `branchif` and until statement uses `branchunless`.
+func (g *Generator) compileLoopStmt(is *InstructionSet, stmt ast.Statement, condition ast.Expression, body *ast.BlockStatement, branch string, scope *scope, table *localTable) {
 	anchor1 := &anchor{}
 	breakAnchor := &anchor{}
+	outerAnchors := g.saveLoopAnchors(scope)
 
 	is.define(Jump, stmt.Line(), anchor1)
 
@@ -80,26 +110,141 @@ func (g *Generator) compileWhileStmt(is *InstructionSet, stmt *ast.WhileStatemen
 
 	scope.anchors["next"] = anchor1
 	scope.anchors["break"] = breakAnchor
+	scope.anchors["redo"] = anchor2
 
-	g.compileCodeBlock(is, stmt.Body, scope, table)
+	g.compileCodeBlock(is, body, scope, table)
 
 	anchor1.line = is.count
 
-	g.compileExpression(is, stmt.Condition, scope, table)
+	g.compileExpression(is, condition, scope, table)
 
-	is.define(BranchIf, stmt.Line(), anchor2)
+	is.define(branch, stmt.Line(), anchor2)
 	is.define(PutNull, stmt.Line())
 	is.define(Pop, stmt.Line())
 
 	breakAnchor.line = is.count
+
+	g.restoreLoopAnchors(scope, outerAnchors)
+}
+
+// saveLoopAnchors returns the anchors of the enclosing loop, so a nested loop doesn't change where they jump to
+func (g *Generator) saveLoopAnchors(scope *scope) map[string]*anchor {
+	anchors := map[string]*anchor{}
+
+	for _, name := range []string{"next", "break", "redo"} {
+		anchors[name] = scope.anchors[name]
+	}
+
+	return anchors
+}
+
+func (g *Generator) restoreLoopAnchors(scope *scope, anchors map[string]*anchor) {
+	for name, a := range anchors {
+		if a == nil {
+			delete(scope.anchors, name)
+		} else {
+			scope.anchors[name] = a
+		}
+	}
+}
+
+// Inside a loop, `next` jumps to the condition, and its value is discarded since loops don't return values.
+// Outside of loops in a block, `next` ends the block with the value, which becomes the result of `yield`.
+func (g *Generator) compileNextStatement(is *InstructionSet, stmt *ast.NextStatement, scope *scope, table *localTable) {
+	if a, ok := scope.anchors["next"]; ok || is.isType != Block {
+		g.compileDiscardedValue(is, stmt.Value, stmt.Line(), scope, table)
+		g.compileJumpOut(is, "next", stmt.Line(), scope, table)
+		is.define(Jump, stmt.Line(), a)
+		return
+	}
+
+	g.compileJumpValue(is, stmt.Value, stmt.Line(), scope, table)
+	g.compileLeaveOut(is, stmt.Line(), scope, table)
+	is.define(Leave, stmt.Line())
+}
+
+// Inside a loop, `break` jumps out of the loop. Outside of loops in a block, `break` stops the method that yields
+// the block, and its value becomes the result of the method call.
+func (g *Generator) compileBreakStatement(is *InstructionSet, stmt *ast.BreakStatement, scope *scope, table *localTable) {
+	if a, ok := scope.anchors["break"]; ok || is.isType != Block {
+		g.compileDiscardedValue(is, stmt.Value, stmt.Line(), scope, table)
+		g.compileJumpOut(is, "break", stmt.Line(), scope, table)
+		is.define(Jump, stmt.Line(), a)
+		return
+	}
+
+	g.compileJumpValue(is, stmt.Value, stmt.Line(), scope, table)
+	g.compileLeaveOut(is, stmt.Line(), scope, table)
+	is.define(Break, stmt.Line())
+}
+
+// `redo` restarts the body of current loop or block without checking the condition again
+func (g *Generator) compileRedoStatement(is *InstructionSet, stmt ast.Statement, scope *scope, table *localTable) {
+	g.compileJumpOut(is, "redo", stmt.Line(), scope, table)
+	is.define(Jump, stmt.Line(), scope.anchors["redo"])
 }
 
-func (g *Generator) compileNextStatement(is *InstructionSet, stmt ast.Statement, scope *scope) {
-	is.define(Jump, stmt.Line(), scope.anchors["next"])
+// compileJumpValue pushes the value of `next` or `break`, which is nil if it's omitted
+func (g *Generator) compileJumpValue(is *InstructionSet, value ast.Expression, sourceLine int, scope *scope, table *localTable) {
+	if value == nil {
+		is.define(PutNull, sourceLine)
+		return
+	}
+
+	g.compileExpression(is, value, scope, table)
+}
+
+// compileDiscardedValue evaluates the value of `next` or `break` in a loop, which isn't used
+func (g *Generator) compileDiscardedValue(is *InstructionSet, value ast.Expression, sourceLine int, scope *scope, table *localTable) {
+	if value == nil {
+		return
+	}
+
+	g.compileExpression(is, value, scope, table)
+	is.define(Pop, sourceLine)
+}
+
+// `retry` ends the rescue clause and restarts the begin expression, the regions it leaves include the rescue clause
+func (g *Generator) compileRetryStatement(is *InstructionSet, stmt ast.Statement, scope *scope, table *localTable) {
+	g.compileJumpOut(is, "retry", stmt.Line(), scope, table)
+	is.define(Jump, stmt.Line(), scope.anchors["retry"])
+}
+
+// getArgName returns the name of the parameter
+func getArgName(param ast.Expression) string {
+	switch exp := param.(type) {
+	case *ast.AssignExpression:
+		return exp.Variables[0].TokenLiteral()
+	case *ast.PrefixExpression:
+		return exp.Right.TokenLiteral()
+	default:
+		return exp.TokenLiteral()
+	}
 }
 
-func (g *Generator) compileBreakStatement(is *InstructionSet, stmt ast.Statement, scope *scope) {
-	is.define(Jump, stmt.Line(), scope.anchors["break"])
+// setVisibility changes the visibility of the methods defined after `private`, `protected`, `public` or `module_function`
+// without arguments in class or module body. The call is still compiled, it just returns nil.
+func (g *Generator) setVisibility(exp ast.Expression, scope *scope, table *localTable) {
+	ident, ok := exp.(*ast.Identifier)
+
+	if !ok {
+		return
+	}
+
+	switch scope.self.(type) {
+	case *ast.ClassStatement, *ast.ModuleStatement:
+	default:
+		return
+	}
+
+	if _, _, isLocal := table.getLCL(ident.Value, table.depth); isLocal {
+		return
+	}
+
+	switch ident.Value {
+	case Public, Private, Protected, ModuleFunction:
+		scope.visibility = ident.Value
+	}
 }
 
 func (g *Generator) compileClassStmt(is *InstructionSet, stmt *ast.ClassStatement, scope *scope, table *localTable) {
@@ -146,7 +291,12 @@ func (g *Generator) compileDefStmt(is *InstructionSet, stmt *ast.DefStatement, s
 	case nil:
 		is.define(PutSelf, stmt.Line())
 		is.define(PutString, stmt.Line(), stmt.Name.Value)
-		is.define(DefMethod, stmt.Line(), len(stmt.Parameters))
+
+		if scope.visibility != "" && scope.visibility != Public {
+			is.define(DefMethod, stmt.Line(), len(stmt.Parameters), scope.visibility)
+		} else {
+			is.define(DefMethod, stmt.Line(), len(stmt.Parameters))
+		}
 	default:
 		g.compileExpression(is, stmt.Receiver, scope, scope.localTable)
 		is.define(PutString, stmt.Line(), stmt.Name.Value)
@@ -171,15 +321,31 @@ func (g *Generator) compileDefStmt(is *InstructionSet, stmt *ast.DefStatement, s
 			exp.Optioned = 1
 			g.compileAssignExpression(newIS, exp, scope, scope.localTable)
 		case *ast.PrefixExpression:
-			if exp.Operator != "*" {
+			switch exp.Operator {
+			case "*":
+				argType = SplatArg
+			case "&":
+				argType = BlockArg
+			default:
 				continue
 			}
-			argType = SplatArg
 			ident := exp.Right.(*ast.Identifier)
 			scope.localTable.setLCL(ident.Value, scope.localTable.depth)
+		case *ast.ArgumentPairExpression:
+			if exp.Value == nil {
+				argType = RequiredKeywordArg
+				scope.localTable.setLCL(exp.Key.Value, scope.localTable.depth)
+				break
+			}
+
+			// Optional keyword argument's default value is assigned like optioned argument's
+			argType = OptionalKeywordArg
+			assignment := &ast.AssignExpression{BaseNode: exp.BaseNode, Variables: []ast.Expression{exp.Key}, Value: exp.Value, Optioned: 1}
+			g.compileAssignExpression(newIS, assignment, scope, scope.localTable)
 		}
 
 		newIS.argTypes = append(newIS.argTypes, argType)
+		newIS.argNames = append(newIS.argNames, getArgName(stmt.Parameters[i]))
 	}
 
 	if len(stmt.BlockStatement.Statements) == 0 {
@@ -232,6 +232,78 @@ Foo.new.bar
 	compareBytecode(t, bytecode, expected)
 }
 
+func TestMethodVisibilityCompilation(t *testing.T) {
+	input := `
+class Foo
+  def bar
+    10
+  end
+
+  private
+
+  def baz
+    20
+  end
+
+  protected
+
+  def qux
+    30
+  end
+
+  public
+
+  def quux
+    40
+  end
+end
+`
+	expected := `
+<Def:bar>
+0 putobject 10
+1 leave
+<Def:baz>
+0 putobject 20
+1 leave
+<Def:qux>
+0 putobject 30
+1 leave
+<Def:quux>
+0 putobject 40
+1 leave
+<DefClass:Foo>
+0 putself
+1 putstring bar
+2 def_method 0
+3 putself
+4 send private 0
+5 pop
+6 putself
+7 putstring baz
+8 def_method 0 private
+9 putself
+10 send protected 0
+11 pop
+12 putself
+13 putstring qux
+14 def_method 0 protected
+15 putself
+16 send public 0
+17 pop
+18 putself
+19 putstring quux
+20 def_method 0
+21 leave
+<ProgramStart>
+0 putself
+1 def_class class:Foo
+2 pop
+3 leave
+`
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestWhileStatementInBlock(t *testing.T) {
 	input := `
 	i = 1
@@ -367,6 +439,44 @@ func TestWhileStatementWithMethodCallInCondition(t *testing.T) {
 	compareBytecode(t, bytecode, expected)
 }
 
+func TestUntilStatementCompilation(t *testing.T) {
+	input := `
+	i = 10
+
+	until i < 0
+	  i = i - 1
+	end
+
+	i
+`
+	expected := `
+<ProgramStart>
+0 putobject 10
+1 setlocal 0 0
+2 pop
+3 jump 12
+4 putnil
+5 pop
+6 jump 12
+7 getlocal 0 0
+8 putobject 1
+9 send - 1
+10 setlocal 0 0
+11 pop
+12 getlocal 0 0
+13 putobject 0
+14 send < 1
+15 branchunless 7
+16 putnil
+17 pop
+18 getlocal 0 0
+19 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
 func TestNextStatementCompilation(t *testing.T) {
 	input := `
 	x = 0
@@ -515,3 +625,78 @@ a + 100
 	bytecode := compileToBytecode(input)
 	compareBytecode(t, bytecode, expected)
 }
+
+func TestJumpStatementsInBlockCompilation(t *testing.T) {
+	input := `
+	[1, 2].each do |x|
+	  next x * 2 if x == 1
+	  redo if x == 3
+	  break x
+	end
+`
+	expected := `
+<Block:0>
+0 getlocal 0 0
+1 putobject 1
+2 send == 1
+3 branchunless 9
+4 getlocal 0 0
+5 putobject 2
+6 send * 1
+7 leave
+8 jump 10
+9 putnil
+10 pop
+11 getlocal 0 0
+12 putobject 3
+13 send == 1
+14 branchunless 17
+15 jump 0
+16 jump 18
+17 putnil
+18 pop
+19 getlocal 0 0
+20 break
+21 leave
+<ProgramStart>
+0 putobject 1
+1 putobject 2
+2 newarray 2
+3 send each 0 block:0
+4 leave
+`
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
+
+func TestReturnStatementCompilation(t *testing.T) {
+	input := `
+def foo(a)
+  a.each do |x|
+    return x
+  end
+  return a
+end
+`
+	expected := `
+<Block:0>
+0 getlocal 0 0
+1 return
+2 leave
+<Def:foo>
+0 getlocal 0 0
+1 send each 0 block:0
+2 pop
+3 getlocal 0 0
+4 leave
+5 leave
+<ProgramStart>
+0 putself
+1 putstring foo
+2 def_method 1
+3 leave
+`
+
+	bytecode := compileToBytecode(input)
+	compareBytecode(t, bytecode, expected)
+}
@@ -1,6 +1,9 @@
 package lexer
 
 import (
+	"bytes"
+	"strings"
+
 	"github.com/goby-lang/goby/compiler/token"
 	"github.com/looplab/fsm"
 )
@@ -13,6 +16,13 @@ type Lexer struct {
 	ch           rune
 	line         int
 	FSM          *fsm.FSM
+	// lastType records previous token's type, it helps us identify tok '/' is division or regexp literal
+	lastType token.Type
+	// heredocLines is the number of heredoc body lines removed from current line's following input,
+	// they're added to line number when the lexer reaches the end of current line
+	heredocLines int
+	// pendingTokens are the tokens read ahead by a literal that's split into multiple tokens, like `%w(a b)`
+	pendingTokens []token.Token
 }
 
 // New initializes a new lexer with input string
@@ -39,6 +49,20 @@ func New(input string) *Lexer {
 
 // NextToken makes lexer tokenize next character(s)
 func (l *Lexer) NextToken() token.Token {
+	var tok token.Token
+
+	if len(l.pendingTokens) > 0 {
+		tok = l.pendingTokens[0]
+		l.pendingTokens = l.pendingTokens[1:]
+	} else {
+		tok = l.readToken()
+	}
+
+	l.lastType = tok.Type
+	return tok
+}
+
+func (l *Lexer) readToken() token.Token {
 
 	var tok token.Token
 	l.resetNosymbol()
@@ -46,15 +70,40 @@ func (l *Lexer) NextToken() token.Token {
 	l.skipWhitespace()
 	switch l.ch {
 	case '"', '\'':
+		if l.ch == '"' && l.hasInterpolation() {
+			tok.Literal = l.readInterpolatedString('"')
+			tok.Type = token.InterpolatedString
+			tok.Line = l.line
+			return tok
+		}
 		tok.Literal = l.readString(l.ch)
 		tok.Type = token.String
 		tok.Line = l.line
 		return tok
+	case '`':
+		tok.Literal = l.readInterpolatedString('`')
+		tok.Type = token.Command
+		tok.Line = l.line
+		return tok
 	case '=':
-		if l.peekChar() == '=' {
+		if l.isBlockComment() {
+			return l.readBlockComment()
+		}
+
+		if l.peekChar() == '=' && l.readPosition+1 < len(l.input) && l.input[l.readPosition+1] == '=' {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.CaseEq, Literal: "===", Line: l.line}
+		} else if l.peekChar() == '=' {
 			currentByte := l.ch
 			l.readChar()
 			tok = token.Token{Type: token.Eq, Literal: string(currentByte) + string(l.ch), Line: l.line}
+		} else if l.peekChar() == '~' {
+			l.readChar()
+			tok = token.Token{Type: token.Match, Literal: "=~", Line: l.line}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.HashRocket, Literal: "=>", Line: l.line}
 		} else {
 			tok = newToken(token.Assign, l.ch, l.line)
 		}
@@ -73,6 +122,15 @@ func (l *Lexer) NextToken() token.Token {
 			l.readChar()
 			l.readChar()
 			return tok
+		} else if l.peekChar() == '>' {
+			tok.Literal = "->"
+			tok.Line = l.line
+			tok.Type = token.Lambda
+			l.readChar()
+			l.readChar()
+			return tok
+		} else if l.peekChar() == '@' && l.FSM.Is("method") {
+			return l.readOperatorMethodName("-@")
 		}
 		tok = newToken(token.Minus, l.ch, l.line)
 	case '!':
@@ -84,11 +142,25 @@ func (l *Lexer) NextToken() token.Token {
 			tok = newToken(token.Bang, l.ch, l.line)
 		}
 	case '/':
-		tok = newToken(token.Slash, l.ch, l.line)
+		if l.regexpAllowed() {
+			tok.Literal = l.readRegexp()
+			tok.Type = token.Regexp
+			tok.Line = l.line
+			return tok
+		}
+		if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.Token{Type: token.DivEq, Literal: "/=", Line: l.line}
+		} else {
+			tok = newToken(token.Slash, l.ch, l.line)
+		}
 	case '*':
 		if l.peekChar() == '*' {
 			l.readChar()
 			tok = token.Token{Type: token.Pow, Literal: "**", Line: l.line}
+		} else if l.peekChar() == '=' {
+			l.readChar()
+			tok = token.Token{Type: token.MulEq, Literal: "*=", Line: l.line}
 		} else {
 			tok = newToken(token.Asterisk, l.ch, l.line)
 		}
@@ -101,6 +173,11 @@ func (l *Lexer) NextToken() token.Token {
 			} else {
 				tok = token.Token{Type: token.LTE, Literal: "<=", Line: l.line}
 			}
+		} else if l.peekChar() == '<' && l.isHeredoc() {
+			return l.readHeredoc()
+		} else if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.Token{Type: token.LShift, Literal: "<<", Line: l.line}
 		} else {
 			tok = newToken(token.LT, l.ch, l.line)
 		}
@@ -108,6 +185,9 @@ func (l *Lexer) NextToken() token.Token {
 		if l.peekChar() == '=' {
 			l.readChar()
 			tok = token.Token{Type: token.GTE, Literal: ">=", Line: l.line}
+		} else if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.RShift, Literal: ">>", Line: l.line}
 		} else {
 			tok = newToken(token.GT, l.ch, l.line)
 		}
@@ -136,11 +216,18 @@ func (l *Lexer) NextToken() token.Token {
 			return tok
 		}
 		tok = newToken(token.Plus, l.ch, l.line)
+	case '?':
+		// Ternary operator like `a ? b : c`, and the branches can be symbols
+		tok = newToken(token.Question, l.ch, l.line)
+		l.FSM.Event("initial")
 	case '{':
 		tok = newToken(token.LBrace, l.ch, l.line)
 	case '}':
 		tok = newToken(token.RBrace, l.ch, l.line)
 	case '[':
+		if l.peekChar() == ']' && l.FSM.Is("method") {
+			return l.readOperatorMethodName("[]")
+		}
 		tok = newToken(token.LBracket, l.ch, l.line)
 	case ']':
 		tok = newToken(token.RBracket, l.ch, l.line)
@@ -149,6 +236,13 @@ func (l *Lexer) NextToken() token.Token {
 			tok = token.Token{Type: token.Range, Literal: "..", Line: l.line}
 			l.readChar()
 			l.readChar()
+
+			// Three-dot range excludes its end
+			if l.ch == '.' {
+				tok.Literal = "..."
+				l.readChar()
+			}
+
 			return tok
 		}
 		tok = newToken(token.Dot, l.ch, l.line)
@@ -166,10 +260,16 @@ func (l *Lexer) NextToken() token.Token {
 
 			} else if isLetter(l.peekChar()) {
 				tok.Literal = string(l.readSymbol())
-				tok.Type = token.String
+				tok.Type = token.Symbol
 				tok.Line = l.line
 				return tok
 
+			} else if op := l.symbolOperator(); op != "" {
+				for range op {
+					l.readChar()
+				}
+				tok = token.Token{Type: token.Symbol, Literal: op, Line: l.line}
+
 			} else {
 				tok = newToken(token.Colon, l.ch, l.line)
 			}
@@ -189,9 +289,23 @@ func (l *Lexer) NextToken() token.Token {
 	case '&':
 		if l.peekChar() == '&' {
 			l.readChar()
-			tok = token.Token{Type: token.And, Literal: "&&", Line: l.line}
+			if l.peekChar() == '=' {
+				l.readChar()
+				tok = token.Token{Type: token.AndEq, Literal: "&&=", Line: l.line}
+			} else {
+				tok = token.Token{Type: token.And, Literal: "&&", Line: l.line}
+			}
+		} else {
+			tok = newToken(token.BitAnd, l.ch, l.line)
 		}
+	case '^':
+		tok = newToken(token.BitXor, l.ch, l.line)
+	case '~':
+		tok = newToken(token.BitNot, l.ch, l.line)
 	case '%':
+		if l.isPercentLiteral() {
+			return l.readPercentLiteral()
+		}
 		tok = newToken(token.Modulo, l.ch, l.line)
 	case '#':
 		tok.Literal = string(l.absorbComment())
@@ -234,6 +348,20 @@ func (l *Lexer) NextToken() token.Token {
 			}
 			return tok
 		} else if isInstanceVariable(l.ch) {
+			if isInstanceVariable(l.peekChar()) {
+				// Class variable like `@@foo`
+				l.readChar()
+
+				if isLetter(l.peekChar()) {
+					tok.Literal = "@" + string(l.readInstanceVariable())
+					tok.Type = token.ClassVariable
+					tok.Line = l.line
+					return tok
+				}
+
+				return newToken(token.Illegal, l.ch, l.line)
+			}
+
 			if isLetter(l.peekChar()) {
 				tok.Literal = string(l.readInstanceVariable())
 				tok.Type = token.InstanceVariable
@@ -241,10 +369,20 @@ func (l *Lexer) NextToken() token.Token {
 				return tok
 			}
 
+			return newToken(token.Illegal, l.ch, l.line)
+		} else if isGlobalVariable(l.ch) {
+			if isLetter(l.peekChar()) || isDigit(l.peekChar()) || l.peekChar() == '?' {
+				tok.Literal = string(l.readGlobalVariable())
+				tok.Type = token.GlobalVariable
+				tok.Line = l.line
+				return tok
+			}
+
 			return newToken(token.Illegal, l.ch, l.line)
 		} else if isDigit(l.ch) {
-			tok.Literal = string(l.readNumber())
-			tok.Type = token.Int
+			literal, tokenType := l.readNumber()
+			tok.Literal = string(literal)
+			tok.Type = tokenType
 			tok.Line = l.line
 			return tok
 		}
@@ -259,12 +397,27 @@ func (l *Lexer) NextToken() token.Token {
 func (l *Lexer) skipWhitespace() {
 	for l.ch == ' ' || l.ch == '\t' || l.ch == '\r' || l.ch == '\n' {
 		if l.ch == '\n' {
-			l.line++
+			l.line += 1 + l.heredocLines
+			l.heredocLines = 0
 		}
 		l.readChar()
 	}
 }
 
+// readOperatorMethodName reads the name of a method defined with `def` that isn't a single operator token,
+// like `-@` or `[]`. `[]=` is read as `[]` followed by `=`, just like other setter methods.
+func (l *Lexer) readOperatorMethodName(name string) token.Token {
+	tok := token.Token{Type: token.Ident, Literal: name, Line: l.line}
+
+	for range name {
+		l.readChar()
+	}
+
+	l.FSM.Event("initial")
+
+	return tok
+}
+
 func (l *Lexer) resetNosymbol() {
 
 	if !l.FSM.Is("method") && l.ch != ':' {
@@ -274,12 +427,71 @@ func (l *Lexer) resetNosymbol() {
 
 }
 
-func (l *Lexer) readNumber() []rune {
+func (l *Lexer) readNumber() ([]rune, token.Type) {
 	position := l.position
+
+	// Integers can be written in hexadecimal, binary or octal with a 0x, 0b or 0o prefix
+	if l.ch == '0' && l.readPosition+1 < len(l.input) {
+		isRadixDigit := radixDigitFunc(l.peekChar())
+
+		if isRadixDigit != nil && isRadixDigit(l.input[l.readPosition+1]) {
+			l.readChar() // '0'
+			l.readChar() // radix prefix
+
+			for isRadixDigit(l.ch) {
+				l.readChar()
+			}
+
+			return l.input[position:l.position], token.Int
+		}
+	}
+
+	var tokenType token.Type = token.Int
+
 	for isDigit(l.ch) {
 		l.readChar()
 	}
-	return l.input[position:l.position]
+
+	// A dot is only a decimal point if it's followed by a digit, so `1.times` and `1..5` still work
+	if l.ch == '.' && isDigit(l.peekChar()) {
+		tokenType = token.Float
+		l.readChar()
+
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+	}
+
+	// Rational literals like 3r or 0.75r
+	if l.ch == 'r' && !isLetter(l.peekChar()) && !isDigit(l.peekChar()) {
+		l.readChar()
+		return l.input[position:l.position], token.Rational
+	}
+
+	// Exponents like 1e10 or 1.5e-3
+	if l.ch == 'e' || l.ch == 'E' {
+		next := l.readPosition
+
+		if next < len(l.input) && (l.input[next] == '+' || l.input[next] == '-') {
+			next++
+		}
+
+		if next < len(l.input) && isDigit(l.input[next]) {
+			tokenType = token.Float
+
+			for l.readPosition < next {
+				l.readChar()
+			}
+
+			l.readChar()
+
+			for isDigit(l.ch) {
+				l.readChar()
+			}
+		}
+	}
+
+	return l.input[position:l.position], tokenType
 }
 
 func (l *Lexer) readIdentifier() []rune {
@@ -288,7 +500,7 @@ func (l *Lexer) readIdentifier() []rune {
 		l.readChar()
 	}
 
-	if l.ch == '?' {
+	if l.ch == '?' || (l.ch == '!' && l.peekChar() != '=') {
 		l.readChar()
 	}
 
@@ -311,6 +523,29 @@ func (l *Lexer) readInstanceVariable() []rune {
 	return l.input[position:l.position]
 }
 
+// readGlobalVariable reads a global variable like `$foo`, a numbered one like `$0`, or `$?`
+func (l *Lexer) readGlobalVariable() []rune {
+	position := l.position
+	l.readChar()
+
+	if l.ch == '?' {
+		l.readChar()
+		return l.input[position:l.position]
+	}
+
+	if isDigit(l.ch) {
+		for isDigit(l.ch) {
+			l.readChar()
+		}
+		return l.input[position:l.position]
+	}
+
+	for isLetter(l.ch) || isDigit(l.ch) {
+		l.readChar()
+	}
+	return l.input[position:l.position]
+}
+
 func (l *Lexer) readString(ch rune) string {
 	l.readChar()
 
@@ -342,6 +577,463 @@ func (l *Lexer) readString(ch rune) string {
 	return result
 }
 
+// regexpAllowed checks if a '/' should start a regexp literal instead of being a division operator.
+// This happens when the '/' can't be an infix operator, e.g. at the beginning of an expression.
+func (l *Lexer) regexpAllowed() bool {
+	switch l.lastType {
+	case "", token.Assign, token.OrEq, token.AndEq, token.Eq, token.CaseEq, token.NotEq, token.Match, token.LParen, token.LBracket, token.LBrace,
+		token.Comma, token.Semicolon, token.Colon, token.Bar, token.And, token.Or, token.Comment,
+		token.If, token.Unless, token.ElsIf, token.In, token.Return, token.While, token.Until, token.Do:
+		return true
+	}
+	return false
+}
+
+// readRegexp reads a regexp literal like `/pattern/flags`, escaped slashes in pattern will be unescaped
+func (l *Lexer) readRegexp() string {
+	result := "/"
+	l.readChar()
+
+	for l.ch != '/' && l.ch != 0 {
+		if isEscapedChar(l.ch) && l.peekChar() == '/' {
+			l.readChar()
+		} else if isEscapedChar(l.ch) {
+			result += string(l.ch)
+			l.readChar()
+		}
+		result += string(l.ch)
+		l.readChar()
+	}
+
+	result += "/"
+	l.readChar() // skip the closing slash
+
+	for isLetter(l.ch) {
+		result += string(l.ch)
+		l.readChar()
+	}
+
+	return result
+}
+
+// hasInterpolation checks if the double-quoted string starts from current position contains "#{"
+func (l *Lexer) hasInterpolation() bool {
+	for i := l.readPosition; i < len(l.input) && l.input[i] != '"'; i++ {
+		if isEscapedChar(l.input[i]) {
+			i++
+		} else if l.input[i] == '#' && i+1 < len(l.input) && l.input[i+1] == '{' {
+			return true
+		}
+	}
+	return false
+}
+
+// readInterpolatedString returns the raw content of a double-quoted string with interpolations, or a command
+// quoted by backticks. Quotes and braces inside the interpolated expressions won't terminate the string.
+func (l *Lexer) readInterpolatedString(terminator rune) string {
+	l.readChar()
+	position := l.position
+	depth := 0
+
+	for l.ch != 0 && (l.ch != terminator || depth > 0) {
+		switch {
+		case isEscapedChar(l.ch):
+			l.readChar()
+		case l.ch == '#' && l.peekChar() == '{':
+			depth++
+			l.readChar()
+		case l.ch == '{' && depth > 0:
+			depth++
+		case l.ch == '}' && depth > 0:
+			depth--
+		case (l.ch == '"' || l.ch == '\'') && depth > 0:
+			// skip strings inside the interpolated expression
+			quote := l.ch
+			l.readChar()
+
+			for l.ch != quote && l.ch != 0 {
+				if isEscapedChar(l.ch) {
+					l.readChar()
+				}
+				l.readChar()
+			}
+		}
+		l.readChar()
+	}
+
+	result := string(l.input[position:l.position])
+	l.readChar() // skip the closing quote
+
+	return result
+}
+
+// isHeredoc checks if the "<<" at current position starts a heredoc like `<<~SQL`, `<<-SQL` or `<<~'SQL'`
+func (l *Lexer) isHeredoc() bool {
+	i := l.position + 2
+
+	if i+1 >= len(l.input) || (l.input[i] != '~' && l.input[i] != '-') {
+		return false
+	}
+
+	c := l.input[i+1]
+	return 'A' <= c && c <= 'Z' || c == '\'' || c == '"'
+}
+
+// readHeredoc reads a heredoc, whose body starts from next line and ends at the line with only the delimiter:
+//
+//	sql = <<~SQL
+//	  SELECT *
+//	  FROM users
+//	SQL
+//
+// The delimiter can be indented. In a squiggly heredoc (`<<~`) the common indentation of body lines is removed.
+// A single-quoted delimiter like `<<~'SQL'` disables escaped characters and interpolations.
+// The body is removed from the input so the tokens following the heredoc on current line can be read normally.
+func (l *Lexer) readHeredoc() token.Token {
+	line := l.line
+	squiggly := l.input[l.position+2] == '~'
+	i := l.position + 3
+	quote := rune(0)
+
+	if l.input[i] == '\'' || l.input[i] == '"' {
+		quote = l.input[i]
+		i++
+	}
+
+	start := i
+	for i < len(l.input) && (isLetter(l.input[i]) || isDigit(l.input[i])) {
+		i++
+	}
+	delimiter := string(l.input[start:i])
+
+	if quote != 0 {
+		if i >= len(l.input) || l.input[i] != quote {
+			return token.Token{Type: token.Illegal, Literal: string(l.input[l.position:i]), Line: line}
+		}
+		i++
+	}
+
+	opening := string(l.input[l.position:i])
+
+	// Continue from the end of the heredoc's opening
+	l.readPosition = i
+	l.readChar()
+
+	lineEnd := i
+	for lineEnd < len(l.input) && l.input[lineEnd] != '\n' {
+		lineEnd++
+	}
+
+	lines := []string{}
+	pos := lineEnd + 1
+	terminated := false
+
+	for pos < len(l.input) {
+		end := pos
+		for end < len(l.input) && l.input[end] != '\n' {
+			end++
+		}
+
+		current := string(l.input[pos:end])
+		pos = end + 1
+
+		if strings.TrimSpace(current) == delimiter {
+			terminated = true
+			break
+		}
+
+		lines = append(lines, current+"\n")
+	}
+
+	if !terminated {
+		return token.Token{Type: token.Illegal, Literal: opening, Line: line}
+	}
+
+	if pos > len(l.input) {
+		pos = len(l.input)
+	}
+
+	// Remove the body and the closing delimiter from input
+	if lineEnd < len(l.input) {
+		l.input = append(l.input[:lineEnd+1], l.input[pos:]...)
+	}
+	l.heredocLines += len(lines) + 1
+
+	if squiggly {
+		lines = dedent(lines)
+	}
+
+	body := strings.Join(lines, "")
+
+	if quote == '\'' {
+		return token.Token{Type: token.String, Literal: body, Line: line}
+	}
+
+	// Tokenize the body as a double-quoted string, so it can have escaped characters and interpolations
+	tok := New("\"" + escapeQuotes(body) + "\"").NextToken()
+	tok.Line = line
+	return tok
+}
+
+// dedent removes the common leading whitespaces of the lines, lines with only whitespaces are ignored
+// when finding the common indentation
+func dedent(lines []string) []string {
+	indent := -1
+
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if indent < 0 || n < indent {
+			indent = n
+		}
+	}
+
+	result := []string{}
+
+	for _, line := range lines {
+		n := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if n > indent {
+			n = indent
+		}
+		if n < 0 {
+			n = 0
+		}
+
+		result = append(result, line[n:])
+	}
+
+	return result
+}
+
+// escapeQuotes escapes the double quotes outside interpolations in a heredoc body,
+// so the body can be tokenized as a double-quoted string
+func escapeQuotes(body string) string {
+	var out bytes.Buffer
+	depth := 0
+
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+
+		switch {
+		case c == '\\' && i+1 < len(body):
+			out.WriteByte(c)
+			i++
+			c = body[i]
+		case c == '#' && i+1 < len(body) && body[i+1] == '{':
+			depth++
+			out.WriteString("#{")
+			i++
+			continue
+		case c == '{' && depth > 0:
+			depth++
+		case c == '}' && depth > 0:
+			depth--
+		case (c == '"' || c == '\'') && depth > 0:
+			// copy strings inside the interpolated expression as they are
+			end := i + 1
+			for end < len(body) && body[end] != c {
+				if body[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(body) {
+				end = len(body) - 1
+			}
+			out.WriteString(body[i:end])
+			i = end
+			c = body[i]
+		case c == '"':
+			out.WriteByte('\\')
+		}
+
+		out.WriteByte(c)
+	}
+
+	return out.String()
+}
+
+// isPercentLiteral checks if a '%' starts a percent literal like `%w(a b)` instead of being a modulo operator.
+// Like regexps, it's only a literal where an operand is expected, at the beginning of a line, or as the argument
+// of a method call without parens like `puts %w(a b)`.
+func (l *Lexer) isPercentLiteral() bool {
+	if l.readPosition+1 >= len(l.input) || !isPercentDelimiter(l.input[l.readPosition+1]) {
+		return false
+	}
+
+	switch l.peekChar() {
+	case 'w', 'i', 'q', 'Q':
+	default:
+		return false
+	}
+
+	if l.regexpAllowed() {
+		return true
+	}
+
+	if l.atLineStart() {
+		return true
+	}
+
+	return l.lastType == token.Ident && l.input[l.position-1] == ' '
+}
+
+// atLineStart checks if the current character is the first non-whitespace character of its line
+func (l *Lexer) atLineStart() bool {
+	for i := l.position - 1; i >= 0 && l.input[i] != '\n'; i-- {
+		if l.input[i] != ' ' && l.input[i] != '\t' {
+			return false
+		}
+	}
+
+	return true
+}
+
+/*
+readPercentLiteral reads a percent literal, which is delimited by any pair of brackets or a non-alphanumeric character:
+
+```ruby
+%w(foo bar)  # => ["foo", "bar"]
+%i[foo bar]  # => [:foo, :bar]
+%q{it's 'q'} # => "it's 'q'"
+%Q|#{1 + 1}| # => "2"
+```
+
+`%q` works like a single-quoted string and `%Q` works like a double-quoted one. Word and symbol arrays are split by
+whitespaces, and returned as the tokens of an array literal.
+*/
+func (l *Lexer) readPercentLiteral() token.Token {
+	line := l.line
+	kind := l.peekChar()
+	start := l.position
+
+	l.readChar() // literal type
+	l.readChar() // opening delimiter
+
+	opening := l.ch
+	closing := closingDelimiter(opening)
+	body := []rune{}
+	depth := 0
+	newlines := 0
+
+	l.readChar()
+
+	for l.ch != closing || depth > 0 {
+		switch {
+		case l.ch == 0:
+			return token.Token{Type: token.Illegal, Literal: string(l.input[start:l.position]), Line: line}
+		case isEscapedChar(l.ch) && (l.peekChar() == opening || l.peekChar() == closing):
+			// escaped delimiters don't open or close the literal
+			l.readChar()
+		case isEscapedChar(l.ch):
+			body = append(body, l.ch)
+			l.readChar()
+		case l.ch == opening && opening != closing:
+			depth++
+		case l.ch == closing:
+			depth--
+		}
+
+		if l.ch == '\n' {
+			newlines++
+		}
+
+		body = append(body, l.ch)
+		l.readChar()
+	}
+
+	l.readChar() // move to the character after the closing delimiter
+	l.line += newlines
+
+	switch kind {
+	case 'q':
+		return token.Token{Type: token.String, Literal: string(body), Line: line}
+	case 'Q':
+		// Tokenize the body as a double-quoted string, so it can have escaped characters and interpolations
+		tok := New("\"" + escapeQuotes(string(body)) + "\"").NextToken()
+		tok.Line = line
+		return tok
+	}
+
+	var wordType token.Type = token.String
+	if kind == 'i' {
+		wordType = token.Symbol
+	}
+
+	for i, word := range percentWords(body) {
+		if i > 0 {
+			l.pendingTokens = append(l.pendingTokens, token.Token{Type: token.Comma, Literal: ",", Line: line})
+		}
+		l.pendingTokens = append(l.pendingTokens, token.Token{Type: wordType, Literal: word, Line: line})
+	}
+
+	l.pendingTokens = append(l.pendingTokens, token.Token{Type: token.RBracket, Literal: "]", Line: l.line})
+
+	return token.Token{Type: token.LBracket, Literal: "[", Line: line}
+}
+
+// percentWords splits the body of a word or symbol array literal by whitespaces. Escaped whitespaces and
+// backslashes are kept in the words.
+func percentWords(body []rune) []string {
+	words := []string{}
+	var word bytes.Buffer
+	inWord := false
+
+	for i := 0; i < len(body); i++ {
+		switch c := body[i]; {
+		case isEscapedChar(c) && i+1 < len(body) && (isWhitespace(body[i+1]) || isEscapedChar(body[i+1])):
+			i++
+			word.WriteRune(body[i])
+			inWord = true
+		case isWhitespace(c):
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+
+	return words
+}
+
+// symbolOperators are the operator method names that can be written as symbols like `:+` or `:[]=`,
+// longer names come first so `:<=>` isn't read as `:<=`
+var symbolOperators = []string{
+	"[]=", "<=>", "===", "**", "==", "!=", "<=", ">=", "=~", "<<", ">>", "[]", "+@", "-@",
+	"+", "-", "*", "/", "%", "<", ">", "!", "&", "|", "^", "~",
+}
+
+// symbolOperator returns the operator after the current ':' if it's an operator symbol. The operator must end
+// the expression, so the colon of a ternary expression like `a ? b :-1` isn't read as a symbol.
+func (l *Lexer) symbolOperator() string {
+	input := l.input[l.readPosition:]
+
+	for _, op := range symbolOperators {
+		if len(input) < len(op) || string(input[:len(op)]) != op {
+			continue
+		}
+
+		if len(input) == len(op) || isWhitespace(input[len(op)]) || strings.ContainsRune(",;.)]}", input[len(op)]) {
+			return op
+		}
+	}
+
+	return ""
+}
+
 func (l *Lexer) readSymbol() []rune {
 	l.readChar()
 
@@ -351,11 +1043,74 @@ func (l *Lexer) readSymbol() []rune {
 		l.readChar()
 	}
 
+	// Symbols like :empty? or :sub!
+	if l.peekChar() == '?' || l.peekChar() == '!' {
+		l.readChar()
+	}
+
 	l.readChar()                           // currently at string's last letter
 	result := l.input[position:l.position] // get full string
 	return result
 }
 
+// isBlockComment checks if the line starts a block comment with `=begin`, which must be at the first column
+func (l *Lexer) isBlockComment() bool {
+	return l.atFirstColumn() && isCommentDelimiter(l.input[l.position:], "=begin")
+}
+
+// atFirstColumn checks if the current character is the first one of its line
+func (l *Lexer) atFirstColumn() bool {
+	return l.position == 0 || l.input[l.position-1] == '\n'
+}
+
+/*
+readBlockComment reads a block comment as a comment token, it ends with the line that starts with `=end` at the first
+column:
+
+```ruby
+=begin
+Everything here is ignored
+=end
+```
+*/
+func (l *Lexer) readBlockComment() token.Token {
+	line := l.line
+	position := l.position
+
+	for {
+		for l.ch != '\n' && l.ch != 0 {
+			l.readChar()
+		}
+
+		if l.ch == 0 {
+			return token.Token{Type: token.Illegal, Literal: "=begin", Line: line}
+		}
+
+		l.line++
+		l.readChar()
+
+		if isCommentDelimiter(l.input[l.position:], "=end") {
+			break
+		}
+	}
+
+	// The rest of the `=end` line is also a part of the comment
+	for l.ch != '\n' && l.ch != 0 {
+		l.readChar()
+	}
+
+	return token.Token{Type: token.Comment, Literal: string(l.input[position:l.position]), Line: line}
+}
+
+// isCommentDelimiter checks if the input starts with the block comment delimiter as a whole word
+func isCommentDelimiter(input []rune, delimiter string) bool {
+	if len(input) < len(delimiter) || string(input[:len(delimiter)]) != delimiter {
+		return false
+	}
+
+	return len(input) == len(delimiter) || isWhitespace(input[len(delimiter)])
+}
+
 func (l *Lexer) absorbComment() []rune {
 	p := l.position
 	for l.ch != '\n' && l.ch != 0 {
@@ -389,6 +1144,52 @@ func isDigit(ch rune) bool {
 	return '0' <= ch && ch <= '9'
 }
 
+// radixDigitFunc returns the function that checks digits of the radix given by an integer prefix character,
+// or nil if the character isn't a radix prefix
+func radixDigitFunc(prefix rune) func(rune) bool {
+	switch prefix {
+	case 'x', 'X':
+		return func(ch rune) bool {
+			return isDigit(ch) || 'a' <= ch && ch <= 'f' || 'A' <= ch && ch <= 'F'
+		}
+	case 'b', 'B':
+		return func(ch rune) bool {
+			return ch == '0' || ch == '1'
+		}
+	case 'o', 'O':
+		return func(ch rune) bool {
+			return '0' <= ch && ch <= '7'
+		}
+	}
+
+	return nil
+}
+
+func isWhitespace(ch rune) bool {
+	return ch == ' ' || ch == '\t' || ch == '\r' || ch == '\n'
+}
+
+// isPercentDelimiter checks if the character can delimit a percent literal
+func isPercentDelimiter(ch rune) bool {
+	return ch != 0 && !isLetter(ch) && !isDigit(ch) && !isWhitespace(ch)
+}
+
+// closingDelimiter returns the character that closes a literal opened by the given delimiter
+func closingDelimiter(opening rune) rune {
+	switch opening {
+	case '(':
+		return ')'
+	case '[':
+		return ']'
+	case '{':
+		return '}'
+	case '<':
+		return '>'
+	}
+
+	return opening
+}
+
 func isLetter(ch rune) bool {
 	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' || ch == '_'
 }
@@ -397,6 +1198,10 @@ func isInstanceVariable(ch rune) bool {
 	return ch == '@'
 }
 
+func isGlobalVariable(ch rune) bool {
+	return ch == '$'
+}
+
 func isEscapedChar(ch rune) bool {
 	return ch == '\\'
 }
@@ -420,6 +1225,10 @@ func escapedCharResult(quotedChar rune, peeked rune) string {
 			return "\""
 		case '\'':
 			return "'"
+		case '#':
+			return "#"
+		case '`':
+			return "`"
 		default:
 			return "\\" + string(peeked)
 		}
@@ -131,6 +131,16 @@ func TestNextToken(t *testing.T) {
 	'\"string\"'
 	"\'string\'"
 	'\'string\''
+
+	a =~ /a\/b\d/i
+	10 / 2
+	a.sub!(b) != c
+	"a#{b["}"]}c\#{d}"
+	a << b <= c
+	a = <<~EOS.strip
+	  x "y"
+	EOS
+	b
 	`
 
 	tests := []struct {
@@ -381,7 +391,7 @@ func TestNextToken(t *testing.T) {
 		{token.String, "", 91},
 
 		{token.Next, "next", 93},
-		{token.String, "apple", 94},
+		{token.Symbol, "apple", 94},
 
 		{token.LBrace, "{", 95},
 		{token.Ident, "test", 95},
@@ -392,7 +402,7 @@ func TestNextToken(t *testing.T) {
 		{token.LBrace, "{", 96},
 		{token.Ident, "test", 96},
 		{token.Colon, ":", 96},
-		{token.String, "abc", 96},
+		{token.Symbol, "abc", 96},
 		{token.RBrace, "}", 96},
 
 		{token.LBrace, "{", 97},
@@ -455,7 +465,37 @@ func TestNextToken(t *testing.T) {
 		{token.String, "'string'", 123},
 		{token.String, "'string'", 124},
 
-		{token.EOF, "", 125},
+		{token.Ident, "a", 126},
+		{token.Match, "=~", 126},
+		{token.Regexp, "/a/b\\d/i", 126},
+		{token.Int, "10", 127},
+		{token.Slash, "/", 127},
+		{token.Int, "2", 127},
+		{token.Ident, "a", 128},
+		{token.Dot, ".", 128},
+		{token.Ident, "sub!", 128},
+		{token.LParen, "(", 128},
+		{token.Ident, "b", 128},
+		{token.RParen, ")", 128},
+		{token.NotEq, "!=", 128},
+		{token.Ident, "c", 128},
+
+		{token.InterpolatedString, `a#{b["}"]}c\#{d}`, 129},
+
+		{token.Ident, "a", 130},
+		{token.LShift, "<<", 130},
+		{token.Ident, "b", 130},
+		{token.LTE, "<=", 130},
+		{token.Ident, "c", 130},
+
+		{token.Ident, "a", 131},
+		{token.Assign, "=", 131},
+		{token.String, "x \"y\"\n", 131},
+		{token.Dot, ".", 131},
+		{token.Ident, "strip", 131},
+		{token.Ident, "b", 134},
+
+		{token.EOF, "", 135},
 	}
 	l := New(input)
 
@@ -474,3 +514,637 @@ func TestNextToken(t *testing.T) {
 		}
 	}
 }
+
+func TestIntegerLiteralWithRadixPrefix(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{`0xff`, token.Int, "0xff"},
+		{`0XFF`, token.Int, "0XFF"},
+		{`0b1010`, token.Int, "0b1010"},
+		{`0o755`, token.Int, "0o755"},
+		{`0`, token.Int, "0"},
+		{`0b2`, token.Int, "0"},
+		{`0x`, token.Int, "0"},
+	}
+
+	for i, tt := range tests {
+		tok := New(tt.input).NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestFloatAndRationalLiteral(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{`1.5`, token.Float, "1.5"},
+		{`10.25`, token.Float, "10.25"},
+		{`1e10`, token.Float, "1e10"},
+		{`1.5e-3`, token.Float, "1.5e-3"},
+		{`2E+8`, token.Float, "2E+8"},
+		{`1.times`, token.Int, "1"},
+		{`1..5`, token.Int, "1"},
+		{`1e`, token.Int, "1"},
+		{`0xe1`, token.Int, "0xe1"},
+		{`3r`, token.Rational, "3r"},
+		{`0.75r`, token.Rational, "0.75r"},
+		{`3rd`, token.Int, "3"},
+	}
+
+	for i, tt := range tests {
+		tok := New(tt.input).NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestRangeOperators(t *testing.T) {
+	input := `1..5 1...5 1.0...2.5`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Int, "1"},
+		{token.Range, ".."},
+		{token.Int, "5"},
+		{token.Int, "1"},
+		{token.Range, "..."},
+		{token.Int, "5"},
+		{token.Float, "1.0"},
+		{token.Range, "..."},
+		{token.Float, "2.5"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestBitwiseOperators(t *testing.T) {
+	input := `a & b | c ^ ~d << 1 >> 2 && e`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.BitAnd, "&"},
+		{token.Ident, "b"},
+		{token.Bar, "|"},
+		{token.Ident, "c"},
+		{token.BitXor, "^"},
+		{token.BitNot, "~"},
+		{token.Ident, "d"},
+		{token.LShift, "<<"},
+		{token.Int, "1"},
+		{token.RShift, ">>"},
+		{token.Int, "2"},
+		{token.And, "&&"},
+		{token.Ident, "e"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestCompoundAssignOperators(t *testing.T) {
+	input := `a *= b /= c &&= d ||= e`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.MulEq, "*="},
+		{token.Ident, "b"},
+		{token.DivEq, "/="},
+		{token.Ident, "c"},
+		{token.AndEq, "&&="},
+		{token.Ident, "d"},
+		{token.OrEq, "||="},
+		{token.Ident, "e"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestGlobalVariables(t *testing.T) {
+	input := `$foo = $bar_1 + $0
+$?.success?`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.GlobalVariable, "$foo"},
+		{token.Assign, "="},
+		{token.GlobalVariable, "$bar_1"},
+		{token.Plus, "+"},
+		{token.GlobalVariable, "$0"},
+		{token.GlobalVariable, "$?"},
+		{token.Dot, "."},
+		{token.Ident, "success?"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestClassVariables(t *testing.T) {
+	input := `@@foo = @bar + @@baz_1`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.ClassVariable, "@@foo"},
+		{token.Assign, "="},
+		{token.InstanceVariable, "@bar"},
+		{token.Plus, "+"},
+		{token.ClassVariable, "@@baz_1"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestPercentLiterals(t *testing.T) {
+	input := `
+	%w(foo bar)
+	%i[baz]
+	%q{it's (q)}
+	%Q|#{a}|
+	b % 2
+	`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.LBracket, "["},
+		{token.String, "foo"},
+		{token.Comma, ","},
+		{token.String, "bar"},
+		{token.RBracket, "]"},
+		{token.LBracket, "["},
+		{token.Symbol, "baz"},
+		{token.RBracket, "]"},
+		{token.String, "it's (q)"},
+		{token.InterpolatedString, "#{a}"},
+		{token.Ident, "b"},
+		{token.Modulo, "%"},
+		{token.Int, "2"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestBlockComments(t *testing.T) {
+	input := `a
+=begin
+a = 1
+  =end
+=end c
+b =begin
+  =begin
+d`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+		expectedLine    int
+	}{
+		{token.Ident, "a", 0},
+		{token.Comment, "=begin\na = 1\n  =end\n=end c", 1},
+		{token.Ident, "b", 5},
+		{token.Assign, "=", 5},
+		{token.Begin, "begin", 5},
+		{token.Assign, "=", 6},
+		{token.Begin, "begin", 6},
+		{token.Ident, "d", 7},
+		{token.EOF, "", 7},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+		if tok.Line != tt.expectedLine {
+			t.Fatalf("tests[%d] - line wrong. expected=%d, got=%d", i, tt.expectedLine, tok.Line)
+		}
+	}
+}
+
+func TestBeginRescueKeywords(t *testing.T) {
+	input := `
+	begin
+	  foo
+	rescue FooError => e
+	  retry
+	ensure
+	  bar
+	end
+	`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Begin, "begin"},
+		{token.Ident, "foo"},
+		{token.Rescue, "rescue"},
+		{token.Constant, "FooError"},
+		{token.HashRocket, "=>"},
+		{token.Ident, "e"},
+		{token.Retry, "retry"},
+		{token.Ensure, "ensure"},
+		{token.Ident, "bar"},
+		{token.End, "end"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestLambdaLiteral(t *testing.T) {
+	input := `sq = ->(x) { x * x }; a -= 1`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "sq"},
+		{token.Assign, "="},
+		{token.Lambda, "->"},
+		{token.LParen, "("},
+		{token.Ident, "x"},
+		{token.RParen, ")"},
+		{token.LBrace, "{"},
+		{token.Ident, "x"},
+		{token.Asterisk, "*"},
+		{token.Ident, "x"},
+		{token.RBrace, "}"},
+		{token.Semicolon, ";"},
+		{token.Ident, "a"},
+		{token.MinusEq, "-="},
+		{token.Int, "1"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTernaryOperatorAndModifiers(t *testing.T) {
+	input := `a ? :b : c; d unless e`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.Question, "?"},
+		{token.Symbol, "b"},
+		{token.Colon, ":"},
+		{token.Ident, "c"},
+		{token.Semicolon, ";"},
+		{token.Ident, "d"},
+		{token.Unless, "unless"},
+		{token.Ident, "e"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestUntilKeyword(t *testing.T) {
+	input := `until i > 10 do; i += 1; end`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Until, "until"},
+		{token.Ident, "i"},
+		{token.GT, ">"},
+		{token.Int, "10"},
+		{token.Do, "do"},
+		{token.Semicolon, ";"},
+		{token.Ident, "i"},
+		{token.PlusEq, "+="},
+		{token.Int, "1"},
+		{token.Semicolon, ";"},
+		{token.End, "end"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestOperatorMethodNames(t *testing.T) {
+	input := `def -@; end
+	def [](i); end
+	def []=(i, v); end
+	a[0]`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Def, "def"},
+		{token.Ident, "-@"},
+		{token.Semicolon, ";"},
+		{token.End, "end"},
+		{token.Def, "def"},
+		{token.Ident, "[]"},
+		{token.LParen, "("},
+		{token.Ident, "i"},
+		{token.RParen, ")"},
+		{token.Semicolon, ";"},
+		{token.End, "end"},
+		{token.Def, "def"},
+		{token.Ident, "[]"},
+		{token.Assign, "="},
+		{token.LParen, "("},
+		{token.Ident, "i"},
+		{token.Comma, ","},
+		{token.Ident, "v"},
+		{token.RParen, ")"},
+		{token.Semicolon, ";"},
+		{token.End, "end"},
+		{token.Ident, "a"},
+		{token.LBracket, "["},
+		{token.Int, "0"},
+		{token.RBracket, "]"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestOperatorSymbols(t *testing.T) {
+	input := `send(:+, 1); [:[], :[]=, :<=>, :-@]
+	a ? b :-1`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "send"},
+		{token.LParen, "("},
+		{token.Symbol, "+"},
+		{token.Comma, ","},
+		{token.Int, "1"},
+		{token.RParen, ")"},
+		{token.Semicolon, ";"},
+		{token.LBracket, "["},
+		{token.Symbol, "[]"},
+		{token.Comma, ","},
+		{token.Symbol, "[]="},
+		{token.Comma, ","},
+		{token.Symbol, "<=>"},
+		{token.Comma, ","},
+		{token.Symbol, "-@"},
+		{token.RBracket, "]"},
+		{token.Ident, "a"},
+		{token.Question, "?"},
+		{token.Ident, "b"},
+		{token.Colon, ":"},
+		{token.Minus, "-"},
+		{token.Int, "1"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestCaseInKeywords(t *testing.T) {
+	input := `case x
+in [a, *] if a === 1
+  a
+in /go/
+end`
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Case, "case"},
+		{token.Ident, "x"},
+		{token.In, "in"},
+		{token.LBracket, "["},
+		{token.Ident, "a"},
+		{token.Comma, ","},
+		{token.Asterisk, "*"},
+		{token.RBracket, "]"},
+		{token.If, "if"},
+		{token.Ident, "a"},
+		{token.CaseEq, "==="},
+		{token.Int, "1"},
+		{token.Ident, "a"},
+		{token.In, "in"},
+		{token.Regexp, "/go/"},
+		{token.End, "end"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestCommandLiteral(t *testing.T) {
+	input := "a = `ls #{dir.join(\"`\")} \\`x\\``\nputs(`pwd`)"
+
+	tests := []struct {
+		expectedType    token.Type
+		expectedLiteral string
+	}{
+		{token.Ident, "a"},
+		{token.Assign, "="},
+		{token.Command, "ls #{dir.join(\"`\")} \\`x\\`"},
+		{token.Ident, "puts"},
+		{token.LParen, "("},
+		{token.Command, "pwd"},
+		{token.RParen, ")"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -1,32 +1,49 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/goby-lang/goby/compiler/ast"
+	"github.com/goby-lang/goby/compiler/lexer"
 	"github.com/goby-lang/goby/compiler/token"
 )
 
 var arguments = map[token.Type]bool{
-	token.Int:              true,
-	token.String:           true,
-	token.True:             true,
-	token.False:            true,
-	token.Null:             true,
-	token.InstanceVariable: true,
-	token.Ident:            true,
-	token.Constant:         true,
+	token.Int:                true,
+	token.Float:              true,
+	token.Rational:           true,
+	token.String:             true,
+	token.Symbol:             true,
+	token.InterpolatedString: true,
+	token.Command:            true,
+	token.True:               true,
+	token.False:              true,
+	token.Null:               true,
+	token.InstanceVariable:   true,
+	token.GlobalVariable:     true,
+	token.ClassVariable:      true,
+	token.Ident:              true,
+	token.Constant:           true,
 }
 
 var precedence = map[token.Type]int{
 	token.Eq:                 EQUALS,
+	token.CaseEq:             EQUALS,
 	token.NotEq:              EQUALS,
+	token.Match:              EQUALS,
 	token.LT:                 COMPARE,
 	token.LTE:                COMPARE,
 	token.GT:                 COMPARE,
 	token.GTE:                COMPARE,
 	token.COMP:               COMPARE,
+	token.LShift:             SHIFT,
+	token.RShift:             SHIFT,
+	token.BitAnd:             BITAND,
+	token.Bar:                BITOR,
+	token.BitXor:             BITOR,
 	token.And:                LOGIC,
 	token.Or:                 LOGIC,
 	token.Range:              RANGE,
@@ -42,10 +59,14 @@ var precedence = map[token.Type]int{
 	token.Dot:                CALL,
 	token.LParen:             CALL,
 	token.ResolutionOperator: CALL,
+	token.Question:           TERNARY,
 	token.Assign:             ASSIGN,
 	token.PlusEq:             ASSIGN,
 	token.MinusEq:            ASSIGN,
+	token.MulEq:              ASSIGN,
+	token.DivEq:              ASSIGN,
 	token.OrEq:               ASSIGN,
+	token.AndEq:              ASSIGN,
 }
 
 // Constants for denoting precedence
@@ -54,10 +75,14 @@ const (
 	LOWEST
 	NORMAL
 	ASSIGN
+	TERNARY
 	LOGIC
 	RANGE
 	EQUALS
 	COMPARE
+	BITOR
+	BITAND
+	SHIFT
 	SUM
 	PRODUCT
 	PREFIX
@@ -179,11 +204,23 @@ func (p *Parser) parseInstanceVariable() ast.Expression {
 	return &ast.InstanceVariable{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
 }
 
+func (p *Parser) parseClassVariable() ast.Expression {
+	return &ast.ClassVariable{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+}
+
+func (p *Parser) parseGlobalVariable() ast.Expression {
+	return &ast.GlobalVariable{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+}
+
 func (p *Parser) parseIntegerLiteral() ast.Expression {
 	lit := &ast.IntegerLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}}
 
 	value, err := strconv.ParseInt(lit.TokenLiteral(), 0, 64)
 	if err != nil {
+		if err.(*strconv.NumError).Err == strconv.ErrRange {
+			return p.parseBigIntegerLiteral()
+		}
+
 		msg := fmt.Sprintf("could not parse %q as integer", lit.TokenLiteral())
 		panic(msg)
 	}
@@ -193,6 +230,65 @@ func (p *Parser) parseIntegerLiteral() ast.Expression {
 	return lit
 }
 
+// parseBigIntegerLiteral handles integer literals that are too large for Integer,
+// they're expanded to String#to_i calls which return BigInt:
+//
+//	18446744073709551616 => "18446744073709551616".to_i(10)
+//	0xffffffffffffffffff => "0xffffffffffffffffff".to_i(16)
+func (p *Parser) parseBigIntegerLiteral() ast.Expression {
+	literal := p.curToken.Literal
+	base := 10
+
+	if len(literal) > 1 && literal[0] == '0' {
+		switch literal[1] {
+		case 'x', 'X':
+			base = 16
+		case 'b', 'B':
+			base = 2
+		default:
+			base = 8
+		}
+	}
+
+	str := &ast.StringLiteral{BaseNode: &ast.BaseNode{Token: token.Token{Type: token.String, Literal: literal, Line: p.curToken.Line}}, Value: literal}
+	baseArg := &ast.IntegerLiteral{BaseNode: &ast.BaseNode{Token: token.Token{Type: token.Int, Literal: strconv.Itoa(base), Line: p.curToken.Line}}, Value: base}
+
+	return &ast.CallExpression{
+		BaseNode:  &ast.BaseNode{Token: p.curToken},
+		Receiver:  str,
+		Method:    "to_i",
+		Arguments: []ast.Expression{baseArg},
+	}
+}
+
+func (p *Parser) parseFloatLiteral() ast.Expression {
+	lit := &ast.FloatLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}}
+
+	value, err := strconv.ParseFloat(lit.TokenLiteral(), 64)
+	if err != nil && err.(*strconv.NumError).Err != strconv.ErrRange {
+		msg := fmt.Sprintf("could not parse %q as float", lit.TokenLiteral())
+		panic(msg)
+	}
+
+	lit.Value = value
+
+	return lit
+}
+
+// parseRationalLiteral expands rational literals to String#to_r calls:
+//
+//	0.75r => "0.75".to_r
+func (p *Parser) parseRationalLiteral() ast.Expression {
+	literal := strings.TrimSuffix(p.curToken.Literal, "r")
+	str := &ast.StringLiteral{BaseNode: &ast.BaseNode{Token: token.Token{Type: token.String, Literal: literal, Line: p.curToken.Line}}, Value: literal}
+
+	return &ast.CallExpression{
+		BaseNode: &ast.BaseNode{Token: p.curToken},
+		Receiver: str,
+		Method:   "to_r",
+	}
+}
+
 func (p *Parser) parseStringLiteral() ast.Expression {
 	lit := &ast.StringLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}}
 	lit.Value = p.curToken.Literal
@@ -200,6 +296,186 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 	return lit
 }
 
+func (p *Parser) parseSymbolLiteral() ast.Expression {
+	return &ast.SymbolLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+}
+
+/*
+parseInterpolatedString splits the raw string content into string literals and interpolated expressions, like:
+
+```
+"Hello #{name}, you have #{count * 2} items"
+```
+
+will be split into "Hello ", name, ", you have ", count * 2 and " items".
+Each interpolated expression is parsed by a new parser.
+*/
+func (p *Parser) parseInterpolatedString() ast.Expression {
+	is := p.parseInterpolatedContent(p.curToken.Literal)
+
+	if is == nil {
+		return nil
+	}
+
+	return is
+}
+
+/*
+parseCommand parses a command quoted by backticks, which can have interpolations like a double-quoted string:
+
+```
+`ls #{dir}`
+```
+
+It's parsed as calling the "`" method with the command string.
+*/
+func (p *Parser) parseCommand() ast.Expression {
+	cmd := p.parseInterpolatedContent(p.curToken.Literal)
+
+	if cmd == nil {
+		return nil
+	}
+
+	selfTok := token.Token{Type: token.Self, Literal: "self", Line: p.curToken.Line}
+
+	return &ast.CallExpression{
+		BaseNode:  &ast.BaseNode{Token: p.curToken},
+		Receiver:  &ast.SelfExpression{BaseNode: &ast.BaseNode{Token: selfTok}},
+		Method:    "`",
+		Arguments: []ast.Expression{cmd},
+	}
+}
+
+// parseInterpolatedContent splits the raw content of an interpolated string or a command into its segments
+func (p *Parser) parseInterpolatedContent(raw string) *ast.InterpolatedString {
+	is := &ast.InterpolatedString{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	var text bytes.Buffer
+
+	for i := 0; i < len(raw); i++ {
+		if raw[i] == '\\' && i+1 < len(raw) {
+			text.WriteString(raw[i : i+2])
+			i++
+			continue
+		}
+
+		if raw[i] != '#' || i+1 == len(raw) || raw[i+1] != '{' {
+			text.WriteByte(raw[i])
+			continue
+		}
+
+		if text.Len() > 0 {
+			is.Segments = append(is.Segments, p.parseInterpolationText(text.String()))
+			text.Reset()
+		}
+
+		end := interpolationEnd(raw, i+2)
+		exp := p.parseInterpolationExpression(raw[i+2 : end])
+
+		if p.error != nil {
+			return nil
+		}
+
+		if exp != nil {
+			is.Segments = append(is.Segments, exp)
+		}
+
+		i = end
+	}
+
+	if text.Len() > 0 {
+		is.Segments = append(is.Segments, p.parseInterpolationText(text.String()))
+	}
+
+	return is
+}
+
+// parseInterpolationText processes the escaped characters in text by tokenizing it as a double-quoted string.
+// The text of a command can have double quotes, which are escaped first.
+func (p *Parser) parseInterpolationText(text string) ast.Expression {
+	var quoted bytes.Buffer
+
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\\' && i+1 < len(text):
+			quoted.WriteString(text[i : i+2])
+			i++
+		case text[i] == '"':
+			quoted.WriteString("\\\"")
+		default:
+			quoted.WriteByte(text[i])
+		}
+	}
+
+	tok := lexer.New("\"" + quoted.String() + "\"").NextToken()
+	tok.Line = p.curToken.Line
+	return &ast.StringLiteral{BaseNode: &ast.BaseNode{Token: tok}, Value: tok.Literal}
+}
+
+func (p *Parser) parseInterpolationExpression(input string) ast.Expression {
+	// Prepend newlines so the expression's tokens have the same line number as the string
+	l := lexer.New(strings.Repeat("\n", p.curToken.Line) + input)
+	program, err := New(l).ParseProgram()
+
+	if err != nil {
+		p.error = err
+		return nil
+	}
+
+	if len(program.Statements) == 0 {
+		return nil
+	}
+
+	stmt, ok := program.Statements[len(program.Statements)-1].(*ast.ExpressionStatement)
+
+	if !ok || len(program.Statements) > 1 {
+		msg := fmt.Sprintf("expect a single expression in string interpolation. got=%s. Line: %d", input, p.curToken.Line)
+		p.error = &Error{Message: msg, errType: SyntaxError}
+		return nil
+	}
+
+	stmt.Expression.MarkAsExp()
+	return stmt.Expression
+}
+
+// interpolationEnd returns the index of the "}" which closes the interpolation starts from index start
+func interpolationEnd(raw string, start int) int {
+	depth := 0
+
+	for i := start; i < len(raw); i++ {
+		switch raw[i] {
+		case '\\':
+			i++
+		case '"', '\'':
+			quote := raw[i]
+
+			for i++; i < len(raw) && raw[i] != quote; i++ {
+				if raw[i] == '\\' {
+					i++
+				}
+			}
+		case '{':
+			depth++
+		case '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+
+	return len(raw)
+}
+
+func (p *Parser) parseRegexpLiteral() ast.Expression {
+	lit := &ast.RegexpLiteral{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	// Literal looks like "/pattern/flags"
+	i := strings.LastIndex(lit.TokenLiteral(), "/")
+	lit.Value = lit.TokenLiteral()[1:i]
+	lit.Flags = lit.TokenLiteral()[i+1:]
+
+	return lit
+}
+
 func (p *Parser) parseBooleanLiteral() ast.Expression {
 	lit := &ast.BooleanExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
 
@@ -285,17 +561,10 @@ func (p *Parser) parseArrayExpression() ast.Expression {
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	callExpression := &ast.CallExpression{Receiver: left, Method: "[]", BaseNode: &ast.BaseNode{Token: p.curToken}}
 
-	if p.peekTokenIs(token.RBracket) {
-		callExpression.Arguments = []ast.Expression{}
-		p.nextToken()
-		return callExpression
-	}
+	// Index can take multiple arguments, like `str[2, 5]`
+	callExpression.Arguments = p.parseArrayElements()
 
-	p.nextToken()
-
-	callExpression.Arguments = []ast.Expression{p.parseExpression(NORMAL)}
-
-	if !p.expectPeek(token.RBracket) {
+	if callExpression.Arguments == nil {
 		return nil
 	}
 
@@ -379,31 +648,99 @@ func (p *Parser) parseAssignExpression(v ast.Expression) ast.Expression {
 	oldState := p.fsm.Current()
 	p.fsm.Event(parseAssignment)
 
+	// getter is the variable's value in operator assignments like `a += 1`
+	getter := v
+
 	switch v := v.(type) {
+	case *ast.ClassVariable:
+		// `@@foo ||= 1` can read the class variable before it's initialized
+		if p.curTokenIs(token.OrEq) {
+			getter = &ast.ClassVariable{BaseNode: v.BaseNode, Value: v.Value, IsOptional: true}
+		}
+
+		exp.Variables = []ast.Expression{v}
+	case *ast.Constant:
+		/*
+			for cases like: `Foo ||= 1`
+			which needs to be expand to
+
+			Foo || Foo = 1
+
+			because a constant can only be assigned when it's not initialized yet
+		*/
+		if p.curTokenIs(token.OrEq) {
+			getter := &ast.Constant{BaseNode: v.BaseNode, Value: v.Value, IsOptional: true}
+			exp.Variables = []ast.Expression{v}
+			exp.Token = token.Token{Type: token.Assign, Literal: "=", Line: p.curToken.Line}
+
+			result := p.parseConditionalAssignment(getter, func(value ast.Expression) ast.Expression {
+				exp.Value = value
+				return exp
+			})
+
+			if oldState != parsingFuncCall {
+				result.MarkAsStmt()
+			}
+
+			p.fsm.Event(eventTable[oldState])
+			return result
+		}
+
+		exp.Variables = []ast.Expression{v}
 	case ast.Variable:
 		exp.Variables = []ast.Expression{v}
 	case *ast.MultiVariableExpression:
 		exp.Variables = v.Variables
 	case *ast.CallExpression:
 		/*
-			for cases like: `a[i] += b`
-			which needs to be expand to
+			for cases like: `a[i] += b` or `a.b += c`
+			which works like
 
 			a[i] = a[i] + b
+			a.b = a.b + c
 
 			CallExp = CallExp + Expression
+
+			`||=` and `&&=` only call the setter when it's needed:
+
+			a[i] || a[i] = b
+			a.b && a.b = c
+
+			but `a` and `i` are only evaluated once, so they're kept in a CallAssignExpression instead of being
+			expanded into two calls
 		*/
 
-		if v.Method == "[]" {
-			value = p.expandAssignmentValue(v)
+		if setterName, ok := setterMethodName(v); ok {
+			var result ast.Expression
+
+			if p.curTokenIs(token.Assign) {
+				args := append([]ast.Expression{}, v.Arguments...)
+
+				result = &ast.CallExpression{
+					BaseNode:  &ast.BaseNode{Token: v.Token},
+					Method:    setterName,
+					Arguments: append(args, p.expandAssignmentValue(v)),
+					Receiver:  v.Receiver,
+				}
+			} else {
+				operator := compoundAssignOperator(p.curToken)
+				p.nextToken()
+
+				result = &ast.CallAssignExpression{
+					BaseNode: &ast.BaseNode{Token: operator},
+					Call:     v,
+					Setter:   setterName,
+					Operator: operator.Literal,
+					Value:    p.parseExpression(LOWEST),
+				}
+			}
 
-			callExp := &ast.CallExpression{
-				BaseNode:  &ast.BaseNode{},
-				Method:    "[]=",
-				Arguments: []ast.Expression{v.Arguments[0], value},
-				Receiver:  v.Receiver,
+			if oldState != parsingFuncCall {
+				result.MarkAsStmt()
 			}
-			return callExp
+
+			p.fsm.Event(eventTable[oldState])
+			return result
 		}
 
 		p.error = &Error{Message: fmt.Sprintf("Can't assign value to %s. Line: %d", v.String(), p.curToken.Line), errType: InvalidAssignmentError}
@@ -413,7 +750,7 @@ func (p *Parser) parseAssignExpression(v ast.Expression) ast.Expression {
 
 	if len(exp.Variables) == 1 {
 		tok = token.Token{Type: token.Assign, Literal: "=", Line: p.curToken.Line}
-		value = p.expandAssignmentValue(v)
+		value = p.expandAssignmentValue(getter)
 	} else {
 		tok = p.curToken
 		precedence := p.curPrecedence()
@@ -436,20 +773,9 @@ func (p *Parser) expandAssignmentValue(value ast.Expression) ast.Expression {
 		precedence := p.curPrecedence()
 		p.nextToken()
 		return p.parseExpression(precedence)
-	case token.MinusEq, token.PlusEq, token.OrEq:
+	case token.MinusEq, token.PlusEq, token.MulEq, token.DivEq, token.OrEq, token.AndEq:
 		// Syntax Surgar: Assignment with operator case
-		infixOperator := token.Token{Line: p.curToken.Line}
-		switch p.curToken.Type {
-		case token.PlusEq:
-			infixOperator.Type = token.Plus
-			infixOperator.Literal = "+"
-		case token.MinusEq:
-			infixOperator.Type = token.Minus
-			infixOperator.Literal = "-"
-		case token.OrEq:
-			infixOperator.Type = token.Or
-			infixOperator.Literal = "||"
-		}
+		infixOperator := compoundAssignOperator(p.curToken)
 
 		p.nextToken()
 
@@ -465,6 +791,62 @@ func (p *Parser) expandAssignmentValue(value ast.Expression) ast.Expression {
 	}
 }
 
+// parseConditionalAssignment parses the value of `||=` or `&&=` into `getter || assignment` or
+// `getter && assignment`, so the assignment only happens when it's needed
+func (p *Parser) parseConditionalAssignment(getter ast.Expression, assign func(value ast.Expression) ast.Expression) ast.Expression {
+	operator := compoundAssignOperator(p.curToken)
+	p.nextToken()
+
+	return &ast.InfixExpression{
+		BaseNode: &ast.BaseNode{Token: operator},
+		Left:     getter,
+		Operator: operator.Literal,
+		Right:    assign(p.parseExpression(LOWEST)),
+	}
+}
+
+// setterMethodName returns the setter of a call that can be assigned to, like `[]=` for `a[i]` or `b=` for `a.b`
+func setterMethodName(call *ast.CallExpression) (string, bool) {
+	if call.Method == "[]" {
+		return "[]=", true
+	}
+
+	if call.Receiver == nil || len(call.Arguments) != 0 || call.Block != nil {
+		return "", false
+	}
+
+	last := call.Method[len(call.Method)-1]
+
+	if last == '?' || last == '!' || last == '=' {
+		return "", false
+	}
+
+	return call.Method + "=", true
+}
+
+// compoundAssignOperator returns the infix operator of a compound assignment token like `+=`
+func compoundAssignOperator(tok token.Token) token.Token {
+	operator := token.Token{Line: tok.Line}
+
+	switch tok.Type {
+	case token.PlusEq:
+		operator.Type = token.Plus
+	case token.MinusEq:
+		operator.Type = token.Minus
+	case token.MulEq:
+		operator.Type = token.Asterisk
+	case token.DivEq:
+		operator.Type = token.Slash
+	case token.OrEq:
+		operator.Type = token.Or
+	case token.AndEq:
+		operator.Type = token.And
+	}
+
+	operator.Literal = string(operator.Type)
+	return operator
+}
+
 func (p *Parser) parseGroupedExpression() ast.Expression {
 	p.nextToken()
 
@@ -491,6 +873,54 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return ie
 }
 
+// parseUnlessExpression parses `unless ... else ... end`, which can't have elsif clauses
+func (p *Parser) parseUnlessExpression() ast.Expression {
+	ue := &ast.UnlessExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	p.nextToken()
+	ue.Condition = p.parseExpression(NORMAL)
+
+	ue.Consequence = p.parseBlockStatement()
+	ue.Consequence.KeepLastValue()
+
+	if p.curTokenIs(token.ElsIf) && p.error == nil {
+		p.error = &Error{Message: fmt.Sprintf("Unexpected elsif in unless expression. Line: %d", p.curToken.Line), errType: UnexpectedTokenError}
+		return nil
+	}
+
+	// curToken is now ELSE or END
+	if p.curTokenIs(token.Else) {
+		ue.Alternative = p.parseBlockStatement()
+		ue.Alternative.KeepLastValue()
+	}
+
+	return ue
+}
+
+// parseTernaryExpression parses `condition ? a : b`, which is parsed as an if expression
+func (p *Parser) parseTernaryExpression(condition ast.Expression) ast.Expression {
+	ie := &ast.IfExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	ce := &ast.ConditionalExpression{BaseNode: &ast.BaseNode{Token: p.curToken}, Condition: condition}
+
+	p.nextToken()
+	ce.Consequence = p.newValueBlock(p.parseExpression(ASSIGN))
+
+	if !p.expectPeek(token.Colon) {
+		return nil
+	}
+
+	p.nextToken()
+	ie.Alternative = p.newValueBlock(p.parseExpression(ASSIGN))
+	ie.Conditionals = []*ast.ConditionalExpression{ce}
+
+	return ie
+}
+
+// newValueBlock returns a block statement that only contains given expression
+func (p *Parser) newValueBlock(exp ast.Expression) *ast.BlockStatement {
+	stmt := &ast.ExpressionStatement{BaseNode: &ast.BaseNode{Token: p.curToken}, Expression: exp}
+	return &ast.BlockStatement{BaseNode: &ast.BaseNode{Token: p.curToken}, Statements: []ast.Statement{stmt}}
+}
+
 func (p *Parser) parseConditionalExpressions() []*ast.ConditionalExpression {
 	// first conditional expression should start with if
 	cs := []*ast.ConditionalExpression{p.parseConditionalExpression()}
@@ -513,6 +943,65 @@ func (p *Parser) parseConditionalExpression() *ast.ConditionalExpression {
 	return ce
 }
 
+func (p *Parser) parseBeginExpression() ast.Expression {
+	be := &ast.BeginExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	be.Body = p.parseBlockStatement()
+	be.Body.KeepLastValue()
+
+	// curToken is now RESCUE, ENSURE or END
+	for p.curTokenIs(token.Rescue) {
+		be.Rescues = append(be.Rescues, p.parseRescueClause())
+	}
+
+	if p.curTokenIs(token.Ensure) {
+		be.Ensure = p.parseBlockStatement()
+	}
+
+	if !p.curTokenIs(token.End) && p.error == nil {
+		p.error = &Error{Message: fmt.Sprintf("Unexpected %s in begin expression. Line: %d", p.curToken.Literal, p.curToken.Line), errType: UnexpectedTokenError}
+	}
+
+	return be
+}
+
+func (p *Parser) parseRescueClause() *ast.RescueClause {
+	rc := &ast.RescueClause{BaseNode: &ast.BaseNode{Token: p.curToken}}
+
+	// rescue FooError, BarError
+	if p.peekTokenIs(token.Constant) && p.peekTokenAtSameLine() {
+		p.nextToken()
+		rc.ExceptionClasses = append(rc.ExceptionClasses, p.parseExpression(NORMAL))
+
+		for p.peekTokenIs(token.Comma) {
+			p.nextToken()
+
+			if !p.expectPeek(token.Constant) {
+				return rc
+			}
+
+			rc.ExceptionClasses = append(rc.ExceptionClasses, p.parseExpression(NORMAL))
+		}
+	}
+
+	// rescue => e
+	if p.peekTokenIs(token.HashRocket) {
+		p.nextToken()
+
+		if !p.expectPeek(token.Ident) {
+			return rc
+		}
+
+		rc.Variable = &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+	}
+
+	p.rescueDepth++
+	rc.Body = p.parseBlockStatement()
+	rc.Body.KeepLastValue()
+	p.rescueDepth--
+
+	return rc
+}
+
 func (p *Parser) parseYieldExpression() ast.Expression {
 	ye := &ast.YieldExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
 
@@ -521,7 +1010,8 @@ func (p *Parser) parseYieldExpression() ast.Expression {
 		ye.Arguments = p.parseCallArgumentsWithParens()
 	}
 
-	if arguments[p.peekToken.Type] && p.peekTokenAtSameLine() { // yield 123
+	// `yield [1, 2]` yields an Array, since indexing the result of yield isn't useful
+	if (arguments[p.peekToken.Type] || p.peekTokenIs(token.LBracket)) && p.peekTokenAtSameLine() { // yield 123
 		p.nextToken()
 		ye.Arguments = p.parseCallArguments()
 	}
@@ -529,10 +1019,41 @@ func (p *Parser) parseYieldExpression() ast.Expression {
 	return ye
 }
 
+// parseSuperExpression parses `super` calls, which forward the method's arguments unless arguments or parens are given
+func (p *Parser) parseSuperExpression() ast.Expression {
+	selfTok := token.Token{Type: token.Self, Literal: "self", Line: p.curToken.Line}
+	exp := &ast.SuperExpression{
+		CallExpression: &ast.CallExpression{
+			BaseNode:  &ast.BaseNode{Token: p.curToken},
+			Receiver:  &ast.SelfExpression{BaseNode: &ast.BaseNode{Token: selfTok}},
+			Method:    "super",
+			Arguments: []ast.Expression{},
+		},
+		ForwardArgs: true,
+	}
+
+	if p.peekTokenIs(token.LParen) { // super() or super(x)
+		p.nextToken()
+		exp.Arguments = p.parseCallArgumentsWithParens()
+		exp.ForwardArgs = false
+	} else if arguments[p.peekToken.Type] && p.peekTokenAtSameLine() { // super x
+		p.nextToken()
+		exp.Arguments = p.parseCallArguments()
+		exp.ForwardArgs = false
+	}
+
+	if p.peekTokenIs(token.Do) && p.acceptBlock {
+		p.parseBlockArgument(exp.CallExpression)
+	}
+
+	return exp
+}
+
 func (p *Parser) parseRangeExpression(left ast.Expression) ast.Expression {
 	exp := &ast.RangeExpression{
-		BaseNode: &ast.BaseNode{Token: p.curToken},
-		Start:    left,
+		BaseNode:  &ast.BaseNode{Token: p.curToken},
+		Start:     left,
+		Exclusive: p.curToken.Literal == "...",
 	}
 
 	precedence := p.curPrecedence()
@@ -240,6 +240,36 @@ func TestArrayIndexExpression(t *testing.T) {
 	}
 }
 
+func TestMultipleArgumentsIndexExpression(t *testing.T) {
+	input := `foo[2, bar]`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	callExp, ok := stmt.Expression.(*ast.CallExpression)
+
+	if !ok {
+		t.Fatalf("expect expression to be ast.CallExpression. got=%T", stmt.Expression)
+	}
+
+	if callExp.Method != "[]" {
+		t.Fatalf("expect method to be []. got=%s", callExp.Method)
+	}
+
+	if len(callExp.Arguments) != 2 {
+		t.Fatalf("expect 2 arguments. got=%d", len(callExp.Arguments))
+	}
+
+	testIntegerLiteral(t, callExp.Arguments[0], 2)
+	testIdentifier(t, callExp.Arguments[1], "bar")
+}
+
 func TestIdentifierExpression(t *testing.T) {
 	input := `foobar;`
 
@@ -314,6 +344,138 @@ func TestIntegerLiteralExpression(t *testing.T) {
 	testIntegerLiteral(t, literal, 5)
 }
 
+func TestFloatLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+	}{
+		{`1.5;`, 1.5},
+		{`0.25;`, 0.25},
+		{`1e3;`, 1000},
+		{`2.5e-1;`, 0.25},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.FloatLiteral)
+		if !ok {
+			t.Fatalf("expression is not ast.FloatLiteral. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != tt.expected {
+			t.Fatalf("literal.Value is not %f. got=%f", tt.expected, literal.Value)
+		}
+	}
+}
+
+func TestBigIntegerLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		base     int
+	}{
+		{`18446744073709551616`, "18446744073709551616", 10},
+		{`0xffffffffffffffffff`, "0xffffffffffffffffff", 16},
+		{`0b11111111111111111111111111111111111111111111111111111111111111111`, "0b11111111111111111111111111111111111111111111111111111111111111111", 2},
+		{`0o7777777777777777777777`, "0o7777777777777777777777", 8},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		callExpression, ok := stmt.Expression.(*ast.CallExpression)
+
+		if !ok {
+			t.Fatalf("expect expression to be ast.CallExpression. got=%T", stmt.Expression)
+		}
+
+		if callExpression.Method != "to_i" {
+			t.Fatalf("expect method to be to_i. got=%s", callExpression.Method)
+		}
+
+		testStringLiteral(t, callExpression.Receiver, tt.expected)
+		testIntegerLiteral(t, callExpression.Arguments[0], tt.base)
+	}
+}
+
+func TestRationalLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`3r`, "3"},
+		{`0.75r`, "0.75"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		callExpression, ok := stmt.Expression.(*ast.CallExpression)
+
+		if !ok {
+			t.Fatalf("expect expression to be ast.CallExpression. got=%T", stmt.Expression)
+		}
+
+		if callExpression.Method != "to_r" {
+			t.Fatalf("expect method to be to_r. got=%s", callExpression.Method)
+		}
+
+		testStringLiteral(t, callExpression.Receiver, tt.expected)
+	}
+}
+
+func TestConstantNamedMethodCall(t *testing.T) {
+	input := `Rational(1, 3)`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	callExpression, ok := stmt.Expression.(*ast.CallExpression)
+
+	if !ok {
+		t.Fatalf("expect expression to be ast.CallExpression. got=%T", stmt.Expression)
+	}
+
+	if _, ok := callExpression.Receiver.(*ast.SelfExpression); !ok {
+		t.Fatalf("expect receiver to be self. got=%T", callExpression.Receiver)
+	}
+
+	if callExpression.Method != "Rational" {
+		t.Fatalf("expect method to be Rational. got=%s", callExpression.Method)
+	}
+
+	testIntegerLiteral(t, callExpression.Arguments[0], 1)
+	testIntegerLiteral(t, callExpression.Arguments[1], 3)
+}
+
 func TestStringLiteralExpression(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -347,6 +509,176 @@ func TestStringLiteralExpression(t *testing.T) {
 	}
 }
 
+func TestSymbolLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`:goby;`, "goby"},
+		{`:foo_bar2;`, "foo_bar2"},
+		{`:empty?;`, "empty?"},
+		{`:sub!;`, "sub!"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		literal, ok := stmt.Expression.(*ast.SymbolLiteral)
+		if !ok {
+			t.Fatalf("expression is not ast.SymbolLiteral. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != tt.expected {
+			t.Fatalf("literal.Value is not %q. got=%q", tt.expected, literal.Value)
+		}
+	}
+}
+
+func TestRegexpLiteralExpression(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedValue string
+		expectedFlags string
+	}{
+		{input: `/goby/;`, expectedValue: "goby", expectedFlags: ""},
+		{input: `/Go+by\d/im;`, expectedValue: `Go+by\d`, expectedFlags: "im"},
+		{input: `/a\/b/;`, expectedValue: "a/b", expectedFlags: ""},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program has wrong number of statements. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+		if !ok {
+			t.Fatalf("first program statement is not ast.ExpressionStatement. got=%T", program.Statements[0])
+		}
+
+		literal, ok := stmt.Expression.(*ast.RegexpLiteral)
+		if !ok {
+			t.Fatalf("expression is not ast.RegexpLiteral. got=%T", stmt.Expression)
+		}
+
+		if literal.Value != tt.expectedValue {
+			t.Fatalf("literal.Value is not %q. got=%q", tt.expectedValue, literal.Value)
+		}
+
+		if literal.Flags != tt.expectedFlags {
+			t.Fatalf("literal.Flags is not %q. got=%q", tt.expectedFlags, literal.Flags)
+		}
+	}
+}
+
+func TestInterpolatedStringExpression(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedSegments []string
+	}{
+		{input: `"Hello #{name}!"`, expectedSegments: []string{`"Hello "`, "name", `"!"`}},
+		{input: `"#{a + 1}#{b}"`, expectedSegments: []string{"(a + 1)", "b"}},
+		{input: `"#{h["}"]}\t"`, expectedSegments: []string{`h.[]("}")`, "\"\t\""}},
+		{input: `"#{}"`, expectedSegments: []string{}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		is, ok := stmt.Expression.(*ast.InterpolatedString)
+		if !ok {
+			t.Fatalf("expression is not ast.InterpolatedString. got=%T", stmt.Expression)
+		}
+
+		if len(is.Segments) != len(tt.expectedSegments) {
+			t.Fatalf("expect %d segments. got=%d", len(tt.expectedSegments), len(is.Segments))
+		}
+
+		for i, segment := range is.Segments {
+			if segment.String() != tt.expectedSegments[i] {
+				t.Fatalf("expect segment %d to be %s. got=%s", i, tt.expectedSegments[i], segment.String())
+			}
+		}
+	}
+}
+
+func TestInterpolatedStringExpressionFail(t *testing.T) {
+	l := lexer.New(`"#{a = 1; b}"`)
+	p := New(l)
+	_, err := p.ParseProgram()
+
+	if err == nil || err.Message != "expect a single expression in string interpolation. got=a = 1; b. Line: 0" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestCommandExpression(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedSegments []string
+	}{
+		{input: "`ls`", expectedSegments: []string{`"ls"`}},
+		{input: "`echo \"#{name}\" \\`x\\``", expectedSegments: []string{`"echo ""`, "name", "\"\" `x`\""}},
+		{input: "``", expectedSegments: []string{}},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		call, ok := stmt.Expression.(*ast.CallExpression)
+		if !ok {
+			t.Fatalf("expression is not ast.CallExpression. got=%T", stmt.Expression)
+		}
+
+		if call.Method != "`" || len(call.Arguments) != 1 {
+			t.Fatalf("expect calling ` with 1 argument. got=%s", call.String())
+		}
+
+		is, ok := call.Arguments[0].(*ast.InterpolatedString)
+		if !ok {
+			t.Fatalf("argument is not ast.InterpolatedString. got=%T", call.Arguments[0])
+		}
+
+		if len(is.Segments) != len(tt.expectedSegments) {
+			t.Fatalf("expect %d segments. got=%d", len(tt.expectedSegments), len(is.Segments))
+		}
+
+		for i, segment := range is.Segments {
+			if segment.String() != tt.expectedSegments[i] {
+				t.Fatalf("expect segment %d to be %s. got=%s", i, tt.expectedSegments[i], segment.String())
+			}
+		}
+	}
+}
+
 func TestParsingPrefixExpression(t *testing.T) {
 	prefixTests := []struct {
 		input    string
@@ -467,6 +799,86 @@ func TestParsingInfixExpression(t *testing.T) {
 	}
 }
 
+func TestTernaryExpression(t *testing.T) {
+	l := lexer.New(`x < y ? x : y`)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	exp, ok := stmt.Expression.(*ast.IfExpression)
+
+	if !ok {
+		t.Fatalf("expect ternary expression to be an IfExpression. got=%T", stmt.Expression)
+	}
+
+	if len(exp.Conditionals) != 1 {
+		t.Fatalf("expect the length of conditionals to be 1. got=%d", len(exp.Conditionals))
+	}
+
+	c := exp.Conditionals[0]
+	testInfixExpression(t, c.Condition, "x", "<", "y")
+	testIdentifier(t, c.Consequence.Statements[0].(*ast.ExpressionStatement).Expression, "x")
+	testIdentifier(t, exp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression, "y")
+}
+
+func TestStatementModifiers(t *testing.T) {
+	input := `
+	puts(x) if x > 1
+	puts(x) unless x > 1
+	x += 1 while x < 10
+	x -= 1 until x < 0
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	if len(program.Statements) != 4 {
+		t.Fatalf("expect program's statements to be 4. got=%d", len(program.Statements))
+	}
+
+	ifExp := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+	testInfixExpression(t, ifExp.Conditionals[0].Condition, "x", ">", 1)
+	testMethodName(t, ifExp.Conditionals[0].Consequence.Statements[0].(*ast.ExpressionStatement).Expression, "puts")
+
+	unlessExp, ok := program.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.UnlessExpression)
+
+	if !ok {
+		t.Fatalf("expect unless modifier to be an UnlessExpression. got=%T", program.Statements[1].(*ast.ExpressionStatement).Expression)
+	}
+
+	testInfixExpression(t, unlessExp.Condition, "x", ">", 1)
+	testMethodName(t, unlessExp.Consequence.Statements[0].(*ast.ExpressionStatement).Expression, "puts")
+
+	whileStmt, ok := program.Statements[2].(*ast.WhileStatement)
+
+	if !ok {
+		t.Fatalf("expect while modifier to be a WhileStatement. got=%T", program.Statements[2])
+	}
+
+	testInfixExpression(t, whileStmt.Condition, "x", "<", 10)
+
+	if len(whileStmt.Body.Statements) != 1 {
+		t.Fatalf("expect while modifier's body to have 1 statement. got=%d", len(whileStmt.Body.Statements))
+	}
+
+	untilStmt, ok := program.Statements[3].(*ast.UntilStatement)
+
+	if !ok {
+		t.Fatalf("expect until modifier to be an UntilStatement. got=%T", program.Statements[3])
+	}
+
+	testInfixExpression(t, untilStmt.Condition, "x", "<", 0)
+}
+
 func TestIfExpression(t *testing.T) {
 	input := `
 	if x < y
@@ -581,6 +993,268 @@ func TestIfExpression(t *testing.T) {
 	}
 }
 
+func TestUnlessExpression(t *testing.T) {
+	input := `
+	unless x < y
+	  x + 5
+	else
+	  y + 4
+	end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("expect program's statements to be 1. got=%d", len(program.Statements))
+	}
+
+	exp, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.UnlessExpression)
+
+	if !ok {
+		t.Fatalf("expect expression to be an UnlessExpression. got=%T", program.Statements[0].(*ast.ExpressionStatement).Expression)
+	}
+
+	testInfixExpression(t, exp.Condition, "x", "<", "y")
+	testInfixExpression(t, exp.Consequence.Statements[0].(*ast.ExpressionStatement).Expression, "x", "+", 5)
+	testInfixExpression(t, exp.Alternative.Statements[0].(*ast.ExpressionStatement).Expression, "y", "+", 4)
+}
+
+func TestUnlessExpressionFail(t *testing.T) {
+	input := `
+	unless x < y
+	  x + 5
+	elsif x == y
+	  y + 5
+	end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	_, err := p.ParseProgram()
+
+	if err == nil {
+		t.Fatal("Expect an error")
+	}
+
+	if err.Message != "Unexpected elsif in unless expression. Line: 3" {
+		t.Fatalf("Unexpected error message: %s", err.Message)
+	}
+}
+
+func TestBeginExpression(t *testing.T) {
+	input := `
+	begin
+	  x + 1
+	rescue FooError, BarError => e
+	  y
+	rescue
+	  retry
+	ensure
+	  z
+	end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+
+	if !ok {
+		t.Fatalf("expect program.Statements[0] to be *ast.ExpressionStatement. got=%T", program.Statements[0])
+	}
+
+	exp, ok := stmt.Expression.(*ast.BeginExpression)
+
+	if !ok {
+		t.Fatalf("expect statement to be a BeginExpression. got=%T", stmt.Expression)
+	}
+
+	body := exp.Body.Statements[0].(*ast.ExpressionStatement)
+
+	if !testInfixExpression(t, body.Expression, "x", "+", 1) {
+		return
+	}
+
+	if len(exp.Rescues) != 2 {
+		t.Fatalf("expect the length of rescue clauses to be 2. got=%d", len(exp.Rescues))
+	}
+
+	r0 := exp.Rescues[0]
+
+	if len(r0.ExceptionClasses) != 2 {
+		t.Fatalf("expect the first rescue clause to have 2 classes. got=%d", len(r0.ExceptionClasses))
+	}
+
+	testConstant(t, r0.ExceptionClasses[0], "FooError")
+	testConstant(t, r0.ExceptionClasses[1], "BarError")
+	testIdentifier(t, r0.Variable, "e")
+	testIdentifier(t, r0.Body.Statements[0].(*ast.ExpressionStatement).Expression, "y")
+
+	r1 := exp.Rescues[1]
+
+	if len(r1.ExceptionClasses) != 0 || r1.Variable != nil {
+		t.Fatalf("expect the second rescue clause to have no classes and variable. got=%s", r1.String())
+	}
+
+	if _, ok := r1.Body.Statements[0].(*ast.RetryStatement); !ok {
+		t.Fatalf("expect the second rescue clause to have a retry statement. got=%T", r1.Body.Statements[0])
+	}
+
+	testIdentifier(t, exp.Ensure.Statements[0].(*ast.ExpressionStatement).Expression, "z")
+}
+
+func TestBeginExpressionFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+		retry
+		`, "Can't use retry outside of rescue clause. Line: 1"},
+		{`
+		begin
+		  retry
+		rescue
+		end
+		`, "Can't use retry outside of rescue clause. Line: 2"},
+		{`
+		begin
+		  foo
+		else
+		  bar
+		end
+		`, "Unexpected else in begin expression. Line: 3"},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		_, err := p.ParseProgram()
+
+		if err == nil {
+			t.Fatalf("At case %d expect to have a parser error", i)
+		}
+
+		if err.Message != tt.expected {
+			t.Fatalf("At case %d expect error message to be:\n  %s. got: \n%s", i, tt.expected, err.Message)
+		}
+	}
+}
+
+func TestCaseExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+		case x
+		in 1 | 2.5 => n
+		  n
+		end
+		`, "case x\nin 1 | 2.5 => n\nn\nend"},
+		{`
+		case x
+		in [Integer => a, *rest] if a > 0
+		  a
+		in first, *, last
+		  first
+		else
+		  nil
+		end
+		`, "case x\nin [Integer => a, *rest] if (a > 0)\na\nin [first, *, last]\nfirst\nelse\nnil\nend"},
+		{`
+		case x
+		in { name: String => name, age: 18..60 }
+		  name
+		in name:, tags: []
+		  name
+		in {}
+		  nil
+		end
+		`, "case x\nin { name: String => name, age: (18..60) }\nname\nin { name:, tags: [] }\nname\nin {}\nnil\nend"},
+		{`
+		case x
+		in Point[^y, _] unless y
+		  y
+		in Point(x:, y: ^(y + 1))
+		  x
+		in ^@z
+		  z
+		end
+		`, "case x\nin Point[^y, _] unless y\ny\nin Point{ x:, y: ^(y + 1) }\nx\nin ^@z\nz\nend"},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatalf("At case %d got parser error: %s", i, err.Message)
+		}
+
+		exp, ok := program.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CaseExpression)
+
+		if !ok {
+			t.Fatalf("At case %d expect expression to be a CaseExpression. got=%T", i, program.Statements[0].(*ast.ExpressionStatement).Expression)
+		}
+
+		if exp.String() != tt.expected {
+			t.Fatalf("At case %d expect case expression to be:\n%q. got:\n%q", i, tt.expected, exp.String())
+		}
+	}
+}
+
+func TestCaseExpressionFail(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+		case x
+		  foo
+		end
+		`, "Expect in clause in case expression. got: foo. Line: 2"},
+		{`
+		case x
+		in [*a, *b]
+		  a
+		end
+		`, "Array pattern can't have more than one splat. Line: 2"},
+		{`
+		case x
+		in ^1
+		  x
+		end
+		`, "Can't pin 1 in pattern. Line: 2"},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		_, err := p.ParseProgram()
+
+		if err == nil {
+			t.Fatalf("At case %d expect to have a parser error", i)
+		}
+
+		if err.Message != tt.expected {
+			t.Fatalf("At case %d expect error message to be:\n  %s. got: \n%s", i, tt.expected, err.Message)
+		}
+	}
+}
+
 func TestMethodParameterParsing(t *testing.T) {
 	tests := []struct {
 		input          string
@@ -614,6 +1288,54 @@ func TestMethodParameterParsing(t *testing.T) {
 	}
 }
 
+func TestCallExpressionWithKeywordArguments(t *testing.T) {
+	input := `
+		connect("db", host: "localhost", port: 80)
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	callExpression := stmt.Expression.(*ast.CallExpression)
+
+	if len(callExpression.Arguments) != 2 {
+		t.Fatalf("expect %d arguments. got=%d", 2, len(callExpression.Arguments))
+	}
+
+	testStringLiteral(t, callExpression.Arguments[0], "db")
+
+	hash, ok := callExpression.Arguments[1].(*ast.HashExpression)
+
+	if !ok {
+		t.Fatalf("expect keyword arguments to be a HashExpression. got=%T", callExpression.Arguments[1])
+	}
+
+	testStringLiteral(t, hash.Data["host"], "localhost")
+	testIntegerLiteral(t, hash.Data["port"], 80)
+}
+
+func TestCallExpressionWithKeywordArgumentsFail(t *testing.T) {
+	l := lexer.New(`connect(host: "localhost", 80)`)
+	p := New(l)
+	_, err := p.ParseProgram()
+
+	if err == nil {
+		t.Fatal("expect positional argument after keyword arguments to fail")
+	}
+
+	expected := "Keyword arguments should be passed after other arguments. Line: 0"
+
+	if err.Message != expected {
+		t.Fatalf("expect error message to be:\n  %s. got: \n%s", expected, err.Message)
+	}
+}
+
 func TestCallExpression(t *testing.T) {
 	input := `
 		p.add(1, 2 * 3, 4 + 5)
@@ -711,6 +1433,122 @@ func TestCallExpressionWithBlock(t *testing.T) {
 	testMethodName(t, exp, "puts")
 }
 
+func TestCallExpressionWithBlockDefaultArguments(t *testing.T) {
+	input := `
+	foo do |i, j = 10|
+	  i + j
+	end
+	`
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	callExpression := stmt.Expression.(*ast.CallExpression)
+
+	if len(callExpression.BlockArguments) != 2 {
+		t.Fatalf("expect 2 block arguments. got=%d", len(callExpression.BlockArguments))
+	}
+
+	testIdentifier(t, callExpression.BlockArguments[0], "i")
+
+	assignment, ok := callExpression.BlockArguments[1].(*ast.AssignExpression)
+
+	if !ok {
+		t.Fatalf("expect block argument to be an AssignExpression. got=%T", callExpression.BlockArguments[1])
+	}
+
+	testIdentifier(t, assignment.Variables[0], "j")
+	testIntegerLiteral(t, assignment.Value, 10)
+
+	block := callExpression.Block
+	exp := block.Statements[0].(*ast.ExpressionStatement).Expression
+	testInfixExpression(t, exp, "i", "+", "j")
+}
+
+func TestCallExpressionWithProcAsBlock(t *testing.T) {
+	l := lexer.New(`[1, 2].map(&double)`)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	callExpression := stmt.Expression.(*ast.CallExpression)
+
+	if len(callExpression.Arguments) != 1 {
+		t.Fatalf("expect 1 argument. got=%d", len(callExpression.Arguments))
+	}
+
+	prefix, ok := callExpression.Arguments[0].(*ast.PrefixExpression)
+
+	if !ok || prefix.Operator != "&" {
+		t.Fatalf("expect argument to be a PrefixExpression with '&'. got=%s", callExpression.Arguments[0].String())
+	}
+
+	testIdentifier(t, prefix.Right, "double")
+
+	l = lexer.New(`foo(&block, 1)`)
+	p = New(l)
+	_, err = p.ParseProgram()
+
+	if err == nil || err.Message != "Block argument should be the last argument. Line: 0" {
+		t.Fatalf("expect block argument not followed by other arguments. got=%v", err)
+	}
+}
+
+func TestLambdaExpression(t *testing.T) {
+	tests := []struct {
+		input          string
+		expectedParams []string
+	}{
+		{`->(x, y) { x + y }`, []string{"x", "y"}},
+		{`
+		->(x, y) do
+		  x + y
+		end
+		`, []string{"x", "y"}},
+		{`-> { 1 + 2 }`, []string{}},
+	}
+
+	for i, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program, err := p.ParseProgram()
+
+		if err != nil {
+			t.Fatal(err.Message)
+		}
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		callExpression, ok := stmt.Expression.(*ast.CallExpression)
+
+		if !ok {
+			t.Fatalf("At case %d expect lambda literal to be a CallExpression. got=%T", i, stmt.Expression)
+		}
+
+		testMethodName(t, callExpression, "lambda")
+
+		if len(callExpression.BlockArguments) != len(tt.expectedParams) {
+			t.Fatalf("At case %d expect %d block arguments. got=%d", i, len(tt.expectedParams), len(callExpression.BlockArguments))
+		}
+
+		for j, param := range tt.expectedParams {
+			testIdentifier(t, callExpression.BlockArguments[j], param)
+		}
+
+		if len(callExpression.Block.Statements) != 1 {
+			t.Fatalf("At case %d expect lambda body to have 1 statement. got=%d", i, len(callExpression.Block.Statements))
+		}
+	}
+}
+
 func TestAssignInfixExpressionWithLiteralValue(t *testing.T) {
 	tests := []struct {
 		input              string
@@ -756,6 +1594,10 @@ func TestAssignIndexExpressionWithVariableValue(t *testing.T) {
 		{"Foo = @bar", "Foo", "@bar", testConstant, testInstanceVariable},
 		{"@bar = Foo", "@bar", "Foo", testInstanceVariable, testConstant},
 		{"@bar = @foo", "@bar", "@foo", testInstanceVariable, testInstanceVariable},
+		{"$foo = @bar", "$foo", "@bar", testGlobalVariable, testInstanceVariable},
+		{"@bar = $foo", "@bar", "$foo", testInstanceVariable, testGlobalVariable},
+		{"@@foo = @bar", "@@foo", "@bar", testClassVariable, testInstanceVariable},
+		{"@bar = @@foo", "@bar", "@@foo", testInstanceVariable, testClassVariable},
 	}
 
 	for _, tt := range tests {
@@ -1,12 +1,21 @@
 package parser
 
 import (
+	"fmt"
 	"github.com/goby-lang/goby/compiler/ast"
 	"github.com/goby-lang/goby/compiler/token"
 )
 
 func (p *Parser) parseCallExpressionWithoutReceiver(receiver ast.Expression) ast.Expression {
-	methodToken := receiver.(*ast.Identifier).Token
+	var methodToken token.Token
+
+	// Methods can also be named like constants, like Rational(1, 3)
+	switch receiver := receiver.(type) {
+	case *ast.Constant:
+		methodToken = receiver.Token
+	default:
+		methodToken = receiver.(*ast.Identifier).Token
+	}
 
 	exp := &ast.CallExpression{BaseNode: &ast.BaseNode{}}
 
@@ -100,18 +109,63 @@ func (p *Parser) parseCallArgumentsWithParens() []ast.Expression {
 	return args
 }
 
+// parseCallArguments parses the arguments of a method call.
+// Keyword arguments like `foo(a, b: 1, c: 2)` are passed as a trailing hash `{ b: 1, c: 2 }`.
 func (p *Parser) parseCallArguments() []ast.Expression {
 	args := []ast.Expression{}
+	var keywords *ast.HashExpression
+	var blockPassed bool
+
+	for {
+		if blockPassed {
+			p.error = &Error{Message: fmt.Sprintf("Block argument should be the last argument. Line: %d", p.curToken.Line), errType: SyntaxError}
+			return args
+		}
+
+		arg := p.parseCallArgument()
+		ident, ok := arg.(*ast.Identifier)
+
+		switch {
+		case ok && p.peekTokenIs(token.Colon):
+			if keywords == nil {
+				keywords = &ast.HashExpression{BaseNode: &ast.BaseNode{Token: p.curToken}, Data: map[string]ast.Expression{}}
+				args = append(args, keywords)
+			}
+
+			p.nextToken() // ":"
+			p.nextToken() // start of the value
+			keywords.Data[ident.Value] = p.parseExpression(NORMAL)
+		case isBlockPass(arg):
+			blockPassed = true
+			args = append(args, arg)
+		case keywords != nil:
+			p.error = &Error{Message: fmt.Sprintf("Keyword arguments should be passed after other arguments. Line: %d", p.curToken.Line), errType: SyntaxError}
+			return args
+		default:
+			args = append(args, arg)
+		}
 
-	args = append(args, p.parseExpression(NORMAL))
+		if !p.peekTokenIs(token.Comma) {
+			return args
+		}
 
-	for p.peekTokenIs(token.Comma) {
 		p.nextToken() // ","
 		p.nextToken() // start of next expression
-		args = append(args, p.parseExpression(NORMAL))
 	}
+}
 
-	return args
+// parseCallArgument parses an argument, which can also be a proc passed as block like `&block`
+func (p *Parser) parseCallArgument() ast.Expression {
+	if p.curTokenIs(token.BitAnd) {
+		return p.parsePrefixExpression()
+	}
+
+	return p.parseExpression(NORMAL)
+}
+
+func isBlockPass(arg ast.Expression) bool {
+	prefix, ok := arg.(*ast.PrefixExpression)
+	return ok && prefix.Operator == "&"
 }
 
 func (p *Parser) parseBlockArgument(exp *ast.CallExpression) {
@@ -119,19 +173,17 @@ func (p *Parser) parseBlockArgument(exp *ast.CallExpression) {
 
 	// Parse block arguments
 	if p.peekTokenIs(token.Bar) {
-		var params []*ast.Identifier
+		var params []ast.Expression
 
 		p.nextToken()
 		p.nextToken()
 
-		param := &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
-		params = append(params, param)
+		params = append(params, p.parseBlockParameter())
 
 		for p.peekTokenIs(token.Comma) {
 			p.nextToken()
 			p.nextToken()
-			param := &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
-			params = append(params, param)
+			params = append(params, p.parseBlockParameter())
 		}
 
 		if !p.expectPeek(token.Bar) {
@@ -144,3 +196,93 @@ func (p *Parser) parseBlockArgument(exp *ast.CallExpression) {
 	exp.Block = p.parseBlockStatement()
 	exp.Block.KeepLastValue()
 }
+
+// parseLambdaExpression parses lambda literals like `->(x) { x * 2 }` or `->(x) do x * 2 end`,
+// which are parsed as calling `lambda` with a block
+func (p *Parser) parseLambdaExpression() ast.Expression {
+	selfTok := token.Token{Type: token.Self, Literal: "self", Line: p.curToken.Line}
+	exp := &ast.CallExpression{
+		BaseNode:  &ast.BaseNode{Token: p.curToken},
+		Receiver:  &ast.SelfExpression{BaseNode: &ast.BaseNode{Token: selfTok}},
+		Method:    "lambda",
+		Arguments: []ast.Expression{},
+	}
+
+	if p.peekTokenIs(token.LParen) {
+		p.nextToken()
+
+		for !p.peekTokenIs(token.RParen) {
+			p.nextToken()
+			exp.BlockArguments = append(exp.BlockArguments, p.parseBlockParameter())
+
+			if !p.peekTokenIs(token.Comma) {
+				break
+			}
+
+			p.nextToken()
+		}
+
+		if !p.expectPeek(token.RParen) {
+			return nil
+		}
+	}
+
+	switch {
+	case p.peekTokenIs(token.Do):
+		p.nextToken()
+		exp.Block = p.parseBlockStatement()
+	case p.peekTokenIs(token.LBrace):
+		p.nextToken()
+		exp.Block = p.parseBraceBlockStatement()
+	default:
+		p.error = &Error{Message: fmt.Sprintf("expected next token to be %s or %s, got %s instead. Line: %d", token.LBrace, token.Do, p.peekToken.Type, p.peekToken.Line), errType: WrongTokenError}
+		return nil
+	}
+
+	exp.Block.KeepLastValue()
+
+	return exp
+}
+
+// parseBraceBlockStatement parses a block surrounded by braces, curToken is '{'
+func (p *Parser) parseBraceBlockStatement() *ast.BlockStatement {
+	bs := &ast.BlockStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	bs.Statements = []ast.Statement{}
+
+	p.nextToken()
+
+	for !p.curTokenIs(token.RBrace) {
+		if p.curTokenIs(token.EOF) {
+			p.error = &Error{Message: "Unexpected EOF", errType: EndOfFileError}
+			return bs
+		}
+
+		stmt := p.parseStatement()
+
+		if stmt != nil {
+			bs.Statements = append(bs.Statements, stmt)
+		}
+
+		p.nextToken()
+	}
+
+	return bs
+}
+
+// parseBlockParameter parses a block parameter like `x`, or `x = 10` which has a default value
+func (p *Parser) parseBlockParameter() ast.Expression {
+	param := &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+
+	if !p.peekTokenIs(token.Assign) {
+		return param
+	}
+
+	p.nextToken() // "="
+	exp := &ast.AssignExpression{BaseNode: &ast.BaseNode{Token: p.curToken}, Variables: []ast.Expression{param}, Optioned: 1}
+	p.nextToken()
+
+	// The closing "|" shouldn't be parsed as a bitwise or operator
+	exp.Value = p.parseExpression(BITOR)
+
+	return exp
+}
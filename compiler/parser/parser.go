@@ -59,6 +59,8 @@ type Parser struct {
 	// currently only used when parsing while statement.
 	// However, this is not a very good practice should change it in the future.
 	acceptBlock bool
+	// rescueDepth counts the rescue clauses being parsed, since `retry` can only be used in them
+	rescueDepth int
 	fsm         *fsm.FSM
 	Mode        int
 }
@@ -115,21 +117,35 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.Ident, p.parseIdentifier)
 	p.registerPrefix(token.Constant, p.parseConstant)
 	p.registerPrefix(token.InstanceVariable, p.parseInstanceVariable)
+	p.registerPrefix(token.GlobalVariable, p.parseGlobalVariable)
+	p.registerPrefix(token.ClassVariable, p.parseClassVariable)
 	p.registerPrefix(token.Int, p.parseIntegerLiteral)
+	p.registerPrefix(token.Float, p.parseFloatLiteral)
+	p.registerPrefix(token.Rational, p.parseRationalLiteral)
 	p.registerPrefix(token.String, p.parseStringLiteral)
+	p.registerPrefix(token.Symbol, p.parseSymbolLiteral)
+	p.registerPrefix(token.Regexp, p.parseRegexpLiteral)
+	p.registerPrefix(token.InterpolatedString, p.parseInterpolatedString)
+	p.registerPrefix(token.Command, p.parseCommand)
 	p.registerPrefix(token.True, p.parseBooleanLiteral)
 	p.registerPrefix(token.False, p.parseBooleanLiteral)
 	p.registerPrefix(token.Null, p.parseNilExpression)
 	p.registerPrefix(token.Minus, p.parsePrefixExpression)
 	p.registerPrefix(token.Asterisk, p.parsePrefixExpression)
 	p.registerPrefix(token.Bang, p.parsePrefixExpression)
+	p.registerPrefix(token.BitNot, p.parsePrefixExpression)
 	p.registerPrefix(token.LParen, p.parseGroupedExpression)
 	p.registerPrefix(token.If, p.parseIfExpression)
+	p.registerPrefix(token.Unless, p.parseUnlessExpression)
+	p.registerPrefix(token.Case, p.parseCaseExpression)
+	p.registerPrefix(token.Begin, p.parseBeginExpression)
 	p.registerPrefix(token.Self, p.parseSelfExpression)
 	p.registerPrefix(token.LBracket, p.parseArrayExpression)
 	p.registerPrefix(token.LBrace, p.parseHashExpression)
 	p.registerPrefix(token.Semicolon, p.parseSemicolon)
 	p.registerPrefix(token.Yield, p.parseYieldExpression)
+	p.registerPrefix(token.Super, p.parseSuperExpression)
+	p.registerPrefix(token.Lambda, p.parseLambdaExpression)
 
 	p.infixParseFns = make(map[token.Type]infixParseFn)
 	p.registerInfix(token.Plus, p.parseInfixExpression)
@@ -139,11 +155,19 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.MinusEq, p.parseAssignExpression)
 	p.registerInfix(token.Slash, p.parseInfixExpression)
 	p.registerInfix(token.Eq, p.parseInfixExpression)
+	p.registerInfix(token.CaseEq, p.parseInfixExpression)
 	p.registerInfix(token.Asterisk, p.parseInfixExpression)
 	p.registerInfix(token.Pow, p.parseInfixExpression)
 	p.registerInfix(token.NotEq, p.parseInfixExpression)
+	p.registerInfix(token.Match, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.LTE, p.parseInfixExpression)
+	p.registerInfix(token.LShift, p.parseInfixExpression)
+	p.registerInfix(token.RShift, p.parseInfixExpression)
+	p.registerInfix(token.Question, p.parseTernaryExpression)
+	p.registerInfix(token.BitAnd, p.parseInfixExpression)
+	p.registerInfix(token.Bar, p.parseInfixExpression)
+	p.registerInfix(token.BitXor, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
 	p.registerInfix(token.GTE, p.parseInfixExpression)
 	p.registerInfix(token.COMP, p.parseInfixExpression)
@@ -152,6 +176,9 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.And, p.parseInfixExpression)
 	p.registerInfix(token.Or, p.parseInfixExpression)
 	p.registerInfix(token.OrEq, p.parseAssignExpression)
+	p.registerInfix(token.AndEq, p.parseAssignExpression)
+	p.registerInfix(token.MulEq, p.parseAssignExpression)
+	p.registerInfix(token.DivEq, p.parseAssignExpression)
 	p.registerInfix(token.Comma, p.parseMultiVariables)
 	p.registerInfix(token.ResolutionOperator, p.parseInfixExpression)
 	p.registerInfix(token.Assign, p.parseAssignExpression)
@@ -91,6 +91,14 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"5 < 4 != 3 > 4",
 			"((5 < 4) != (3 > 4))",
 		},
+		{
+			"a << b + c << d",
+			"((a << (b + c)) << d)",
+		},
+		{
+			"a << b < c",
+			"((a << b) < c)",
+		},
 		{
 			"3 + 4 * 5 == 3 * 1 + 4 * 5",
 			"((3 + (4 * 5)) == ((3 * 1) + (4 * 5)))",
@@ -151,6 +159,66 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"n.add(a + b + c * d / f + g)",
 			"n.add((((a + b) + ((c * d) / f)) + g))",
 		},
+		{
+			"a | b & c",
+			"(a | (b & c))",
+		},
+		{
+			"a ^ b | c",
+			"((a ^ b) | c)",
+		},
+		{
+			"a & b << c >> d",
+			"(a & ((b << c) >> d))",
+		},
+		{
+			"a *= b + c",
+			"a = (a * (b + c))",
+		},
+		{
+			"a &&= b || c",
+			"a = (a && (b || c))",
+		},
+		{
+			"a.b /= c * d",
+			"a.b=((a.b() / (c * d)))",
+		},
+		{
+			"a[b, c] -= d",
+			"a.[]=(b, c, (a.[](b, c) - d))",
+		},
+		{
+			"a.b ||= c + d",
+			"(a.b() || a.b=((c + d)))",
+		},
+		{
+			"a[b] &&= c",
+			"(a.[](b) && a.[]=(b, c))",
+		},
+		{
+			"Foo ||= a + b",
+			"(Foo || Foo = (a + b))",
+		},
+		{
+			"a << b + c",
+			"(a << (b + c))",
+		},
+		{
+			"a & b == c | d",
+			"((a & b) == (c | d))",
+		},
+		{
+			"a < b | c",
+			"(a < (b | c))",
+		},
+		{
+			"~a & b",
+			"((~a) & b)",
+		},
+		{
+			"a && b | c",
+			"(a && (b | c))",
+		},
 	}
 
 	for _, tt := range tests {
@@ -288,6 +356,44 @@ func testInstanceVariable(t *testing.T, exp ast.Expression, value string) bool {
 	return true
 }
 
+func testGlobalVariable(t *testing.T, exp ast.Expression, value string) bool {
+	globalVar, ok := exp.(*ast.GlobalVariable)
+	if !ok {
+		t.Errorf("exp not *ast.GlobalVariable. got=%T", exp)
+		return false
+	}
+	if globalVar.Value != value {
+		t.Errorf("globalVar.Value not %s. got=%s", value, globalVar.Value)
+		return false
+	}
+
+	if globalVar.TokenLiteral() != value {
+		t.Errorf("globalVar.TokenLiteral not %s. got=%s", value, globalVar.TokenLiteral())
+		return false
+	}
+
+	return true
+}
+
+func testClassVariable(t *testing.T, exp ast.Expression, value string) bool {
+	classVar, ok := exp.(*ast.ClassVariable)
+	if !ok {
+		t.Errorf("exp not *ast.ClassVariable. got=%T", exp)
+		return false
+	}
+	if classVar.Value != value {
+		t.Errorf("classVar.Value not %s. got=%s", value, classVar.Value)
+		return false
+	}
+
+	if classVar.TokenLiteral() != value {
+		t.Errorf("classVar.TokenLiteral not %s. got=%s", value, classVar.TokenLiteral())
+		return false
+	}
+
+	return true
+}
+
 func testMethodName(t *testing.T, exp ast.Expression, value string) {
 	callExp, ok := exp.(*ast.CallExpression)
 
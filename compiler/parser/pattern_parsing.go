@@ -0,0 +1,350 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/goby-lang/goby/compiler/ast"
+	"github.com/goby-lang/goby/compiler/token"
+)
+
+// parseCaseExpression parses pattern matching, which executes the first in clause whose pattern matches the subject:
+//
+//	case response
+//	in { status: 200, body: [_, *] => body }
+//	  body
+//	in { status: 400..499 | 500..599 => status } if status != 404
+//	  status
+//	else
+//	  nil
+//	end
+func (p *Parser) parseCaseExpression() ast.Expression {
+	ce := &ast.CaseExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	p.nextToken()
+	ce.Subject = p.parseExpression(NORMAL)
+	p.nextToken()
+
+	if p.curTokenIs(token.Semicolon) {
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(token.In) && p.error == nil {
+		p.error = &Error{Message: fmt.Sprintf("Expect in clause in case expression. got: %s. Line: %d", p.curToken.Literal, p.curToken.Line), errType: UnexpectedTokenError}
+		return nil
+	}
+
+	for p.curTokenIs(token.In) && p.error == nil {
+		ce.Clauses = append(ce.Clauses, p.parseInClause())
+	}
+
+	// curToken is now ELSE or END
+	if p.curTokenIs(token.Else) {
+		ce.Alternative = p.parseBlockStatement()
+		ce.Alternative.KeepLastValue()
+	}
+
+	if !p.curTokenIs(token.End) && p.error == nil {
+		p.error = &Error{Message: fmt.Sprintf("Unexpected %s in case expression. Line: %d", p.curToken.Literal, p.curToken.Line), errType: UnexpectedTokenError}
+	}
+
+	return ce
+}
+
+func (p *Parser) parseInClause() *ast.InClause {
+	ic := &ast.InClause{BaseNode: &ast.BaseNode{Token: p.curToken}}
+	p.nextToken()
+	ic.Pattern = p.parseTopLevelPattern()
+
+	if (p.peekTokenIs(token.If) || p.peekTokenIs(token.Unless)) && p.peekTokenAtSameLine() {
+		p.nextToken()
+		ic.GuardIsUnless = p.curTokenIs(token.Unless)
+		p.nextToken()
+		ic.Guard = p.parseExpression(NORMAL)
+	}
+
+	ic.Consequence = p.parseBlockStatement()
+	ic.Consequence.KeepLastValue()
+
+	return ic
+}
+
+// parseTopLevelPattern parses the pattern of an in clause, where array and hash patterns don't need brackets or
+// braces, like `in first, *rest` or `in name:, age:`
+func (p *Parser) parseTopLevelPattern() ast.Expression {
+	if p.curTokenIs(token.Ident) && p.peekTokenIs(token.Colon) {
+		hp := &ast.HashPattern{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		p.parseHashPatternPairs(hp)
+
+		return hp
+	}
+
+	pattern := p.parsePatternElement()
+
+	if _, ok := pattern.(*ast.SplatPattern); !ok && !p.peekTokenIs(token.Comma) {
+		return pattern
+	}
+
+	ap := &ast.ArrayPattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Elements: []ast.Expression{pattern}}
+
+	for p.peekTokenIs(token.Comma) && p.error == nil {
+		p.nextToken()
+		p.nextToken()
+		ap.Elements = append(ap.Elements, p.parsePatternElement())
+	}
+
+	p.checkSplatPatterns(ap)
+
+	return ap
+}
+
+// parsePatternElement parses an element of array pattern, which can be a splat pattern like `*rest`
+func (p *Parser) parsePatternElement() ast.Expression {
+	if !p.curTokenIs(token.Asterisk) {
+		return p.parsePattern()
+	}
+
+	sp := &ast.SplatPattern{BaseNode: &ast.BaseNode{Token: p.curToken}}
+
+	if p.peekTokenIs(token.Ident) {
+		p.nextToken()
+		sp.Variable = &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+	}
+
+	return sp
+}
+
+// parsePattern parses a pattern with alternatives and captures, like `Integer | Float => number`
+func (p *Parser) parsePattern() ast.Expression {
+	pattern := p.parsePrimaryPattern()
+
+	if p.peekTokenIs(token.Bar) {
+		ap := &ast.AlternativePattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Alternatives: []ast.Expression{pattern}}
+
+		for p.peekTokenIs(token.Bar) && p.error == nil {
+			p.nextToken()
+			p.nextToken()
+			ap.Alternatives = append(ap.Alternatives, p.parsePrimaryPattern())
+		}
+
+		pattern = ap
+	}
+
+	for p.peekTokenIs(token.HashRocket) && p.error == nil {
+		p.nextToken()
+		cp := &ast.CapturePattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Pattern: pattern}
+
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		cp.Variable = &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+		pattern = cp
+	}
+
+	return pattern
+}
+
+func (p *Parser) parsePrimaryPattern() ast.Expression {
+	switch p.curToken.Type {
+	case token.LBracket:
+		return p.parseArrayPattern(nil, token.RBracket)
+	case token.LBrace:
+		return p.parseHashPattern(nil, token.RBrace)
+	case token.LParen:
+		p.nextToken()
+		pattern := p.parsePattern()
+
+		if !p.expectPeek(token.RParen) {
+			return nil
+		}
+
+		return pattern
+	case token.Ident:
+		return &ast.VariablePattern{
+			BaseNode: &ast.BaseNode{Token: p.curToken},
+			Variable: &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal},
+		}
+	case token.BitXor:
+		return p.parsePinnedPattern()
+	case token.Constant:
+		vp := &ast.ValuePattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.parseConstant()}
+
+		// Patterns with a constant like `Point[x, y]` or `Point(x:, y:)`
+		switch {
+		case p.peekTokenIs(token.LBracket) && p.peekTokenAtSameLine():
+			p.nextToken()
+			return p.parseArrayOrHashPattern(vp.Value, token.RBracket)
+		case p.peekTokenIs(token.LParen) && p.peekTokenAtSameLine():
+			p.nextToken()
+			return p.parseArrayOrHashPattern(vp.Value, token.RParen)
+		}
+
+		return p.parseRangePattern(vp)
+	}
+
+	vp := &ast.ValuePattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.parseExpression(BITOR)}
+
+	return p.parseRangePattern(vp)
+}
+
+// parseRangePattern parses the range pattern like `1..5` if the value is followed by a range operator. The bounds
+// are parsed separately so `|` after the range isn't parsed as an operator.
+func (p *Parser) parseRangePattern(vp *ast.ValuePattern) ast.Expression {
+	if !p.peekTokenIs(token.Range) {
+		return vp
+	}
+
+	p.nextToken()
+	re := &ast.RangeExpression{BaseNode: &ast.BaseNode{Token: p.curToken}, Start: vp.Value, Exclusive: p.curToken.Literal == "..."}
+	p.nextToken()
+	re.End = p.parseExpression(BITOR)
+	vp.Value = re
+
+	return vp
+}
+
+// parsePinnedPattern parses a pinned value like `^x`, `^@x` or `^(x + 1)`, which is matched with the value
+// instead of assigning a variable
+func (p *Parser) parsePinnedPattern() ast.Expression {
+	vp := &ast.ValuePattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Pinned: true}
+	p.nextToken()
+
+	switch p.curToken.Type {
+	case token.Ident:
+		vp.Value = &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+	case token.InstanceVariable, token.GlobalVariable, token.ClassVariable:
+		vp.Value = p.parseExpression(CALL)
+	case token.LParen:
+		p.nextToken()
+		vp.Value = p.parseExpression(LOWEST)
+
+		if !p.expectPeek(token.RParen) {
+			return nil
+		}
+	default:
+		p.error = &Error{Message: fmt.Sprintf("Can't pin %s in pattern. Line: %d", p.curToken.Literal, p.curToken.Line), errType: UnexpectedTokenError}
+		return nil
+	}
+
+	return vp
+}
+
+// parseArrayOrHashPattern parses the pattern after a constant, which is a hash pattern if it starts with a key
+func (p *Parser) parseArrayOrHashPattern(constant ast.Expression, closing token.Type) ast.Expression {
+	if !p.peekTokenIs(token.Ident) {
+		return p.parseArrayPattern(constant, closing)
+	}
+
+	tok := p.curToken
+	p.nextToken()
+
+	if p.peekTokenIs(token.Colon) {
+		hp := &ast.HashPattern{BaseNode: &ast.BaseNode{Token: tok}, Constant: constant}
+		p.parseHashPatternPairs(hp)
+
+		if !p.expectPeek(closing) {
+			return nil
+		}
+
+		return hp
+	}
+
+	ap := &ast.ArrayPattern{BaseNode: &ast.BaseNode{Token: tok}, Constant: constant}
+	return p.parseArrayPatternElements(ap, closing)
+}
+
+func (p *Parser) parseArrayPattern(constant ast.Expression, closing token.Type) ast.Expression {
+	ap := &ast.ArrayPattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Constant: constant}
+
+	if p.peekTokenIs(closing) {
+		p.nextToken()
+		return ap
+	}
+
+	p.nextToken()
+	return p.parseArrayPatternElements(ap, closing)
+}
+
+// parseArrayPatternElements parses the elements of array pattern from the first element to the closing token
+func (p *Parser) parseArrayPatternElements(ap *ast.ArrayPattern, closing token.Type) ast.Expression {
+	ap.Elements = append(ap.Elements, p.parsePatternElement())
+
+	for p.peekTokenIs(token.Comma) && p.error == nil {
+		p.nextToken()
+		p.nextToken()
+		ap.Elements = append(ap.Elements, p.parsePatternElement())
+	}
+
+	if !p.expectPeek(closing) {
+		return nil
+	}
+
+	p.checkSplatPatterns(ap)
+
+	return ap
+}
+
+// checkSplatPatterns reports an error if the array pattern has more than one splat pattern
+func (p *Parser) checkSplatPatterns(ap *ast.ArrayPattern) {
+	count := 0
+
+	for _, e := range ap.Elements {
+		if _, ok := e.(*ast.SplatPattern); ok {
+			count++
+		}
+	}
+
+	if count > 1 && p.error == nil {
+		p.error = &Error{Message: fmt.Sprintf("Array pattern can't have more than one splat. Line: %d", ap.Line()), errType: SyntaxError}
+	}
+}
+
+func (p *Parser) parseHashPattern(constant ast.Expression, closing token.Type) ast.Expression {
+	hp := &ast.HashPattern{BaseNode: &ast.BaseNode{Token: p.curToken}, Constant: constant}
+
+	if p.peekTokenIs(closing) {
+		p.nextToken()
+		return hp
+	}
+
+	if !p.expectPeek(token.Ident) {
+		return nil
+	}
+
+	p.parseHashPatternPairs(hp)
+
+	if !p.expectPeek(closing) {
+		return nil
+	}
+
+	return hp
+}
+
+// parseHashPatternPairs parses the pairs of hash pattern like `name: String, age:` from the first key
+func (p *Parser) parseHashPatternPairs(hp *ast.HashPattern) {
+	for p.error == nil {
+		hp.Keys = append(hp.Keys, p.curToken.Literal)
+
+		if !p.expectPeek(token.Colon) {
+			return
+		}
+
+		var value ast.Expression
+
+		if !p.peekTokenIs(token.Comma) && !p.peekTokenIs(token.RBrace) && !p.peekTokenIs(token.RParen) && p.peekTokenAtSameLine() {
+			p.nextToken()
+			value = p.parsePattern()
+		}
+
+		hp.Values = append(hp.Values, value)
+
+		if !p.peekTokenIs(token.Comma) {
+			return
+		}
+
+		p.nextToken()
+
+		if !p.expectPeek(token.Ident) {
+			return
+		}
+	}
+}
@@ -9,38 +9,123 @@ import (
 func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.Return:
-		return p.parseReturnStatement()
+		return p.parseStatementModifier(p.parseReturnStatement())
 	case token.Def:
 		return p.parseDefMethodStatement()
 	case token.Comment:
 		return nil
 	case token.While:
 		return p.parseWhileStatement()
+	case token.Until:
+		return p.parseUntilStatement()
 	case token.Class:
 		return p.parseClassStatement()
 	case token.Module:
 		return p.parseModuleStatement()
 	case token.Next:
-		return &ast.NextStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		stmt := &ast.NextStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		stmt.Value = p.parseJumpValue()
+		return p.parseStatementModifier(stmt)
 	case token.Break:
-		return &ast.BreakStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		stmt := &ast.BreakStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		stmt.Value = p.parseJumpValue()
+		return p.parseStatementModifier(stmt)
+	case token.Redo:
+		return p.parseStatementModifier(&ast.RedoStatement{BaseNode: &ast.BaseNode{Token: p.curToken}})
+	case token.Retry:
+		return p.parseRetryStatement()
 	default:
 		exp := p.parseExpressionStatement()
 
 		// If parseExpressionStatement got error exp.Expression would be nil
 		if exp.Expression != nil {
-			// In REPL mode everything should return a value.
-			if p.Mode == REPLMode {
-				exp.Expression.MarkAsExp()
-			} else {
-				exp.Expression.MarkAsStmt()
-			}
+			p.markExpressionStatement(exp)
 		}
 
-		return exp
+		return p.parseStatementModifier(exp)
+	}
+}
+
+func (p *Parser) markExpressionStatement(exp *ast.ExpressionStatement) {
+	// In REPL mode everything should return a value.
+	if p.Mode == REPLMode {
+		exp.Expression.MarkAsExp()
+	} else {
+		exp.Expression.MarkAsStmt()
 	}
 }
 
+// parseStatementModifier parses the modifier after a statement, which executes the statement conditionally or repeatedly:
+//
+//	return x if x > 10
+//	puts(x) unless x.nil?
+//	x += 1 while x < 10
+//	x -= 1 until x.zero?
+//
+// Modifiers are parsed as the if expression, unless expression, while statement or until statement
+// that only contains the modified statement.
+func (p *Parser) parseStatementModifier(stmt ast.Statement) ast.Statement {
+	if stmt == nil || p.error != nil || !p.peekTokenAtSameLine() {
+		return stmt
+	}
+
+	switch p.peekToken.Type {
+	case token.If:
+		p.nextToken()
+		ce := &ast.ConditionalExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		ce.Consequence = p.newModifiedBlock(stmt)
+		ce.Consequence.KeepLastValue()
+		ce.Condition = p.parseModifierCondition()
+
+		ie := &ast.IfExpression{BaseNode: &ast.BaseNode{Token: ce.Token}, Conditionals: []*ast.ConditionalExpression{ce}}
+
+		return p.parseStatementModifier(p.newModifiedExpressionStatement(ce.Token, ie))
+	case token.Unless:
+		p.nextToken()
+		ue := &ast.UnlessExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		ue.Consequence = p.newModifiedBlock(stmt)
+		ue.Consequence.KeepLastValue()
+		ue.Condition = p.parseModifierCondition()
+
+		return p.parseStatementModifier(p.newModifiedExpressionStatement(ue.Token, ue))
+	case token.While:
+		p.nextToken()
+		ws := &ast.WhileStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		ws.Body = p.newModifiedBlock(stmt)
+		ws.Condition = p.parseModifierCondition()
+
+		return p.parseStatementModifier(ws)
+	case token.Until:
+		p.nextToken()
+		us := &ast.UntilStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		us.Body = p.newModifiedBlock(stmt)
+		us.Condition = p.parseModifierCondition()
+
+		return p.parseStatementModifier(us)
+	}
+
+	return stmt
+}
+
+// parseModifierCondition parses the condition after a modifier
+func (p *Parser) parseModifierCondition() ast.Expression {
+	p.nextToken()
+	return p.parseExpression(NORMAL)
+}
+
+// newModifiedBlock returns a block statement that only contains the statement before a modifier
+func (p *Parser) newModifiedBlock(stmt ast.Statement) *ast.BlockStatement {
+	return &ast.BlockStatement{BaseNode: &ast.BaseNode{Token: p.curToken}, Statements: []ast.Statement{stmt}}
+}
+
+// newModifiedExpressionStatement wraps the expression built from a modifier, so it can be modified again
+func (p *Parser) newModifiedExpressionStatement(modifier token.Token, exp ast.Expression) *ast.ExpressionStatement {
+	stmt := &ast.ExpressionStatement{BaseNode: &ast.BaseNode{Token: modifier}, Expression: exp}
+	p.markExpressionStatement(stmt)
+
+	return stmt
+}
+
 func (p *Parser) parseDefMethodStatement() *ast.DefStatement {
 	var params []ast.Expression
 	stmt := &ast.DefStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
@@ -109,7 +194,7 @@ func (p *Parser) parseParameters() []ast.Expression {
 	params := []ast.Expression{}
 
 	p.nextToken()
-	param := p.parseExpression(NORMAL)
+	param := p.parseParameter()
 	params = append(params, param)
 
 	for p.peekTokenIs(token.Comma) {
@@ -121,7 +206,7 @@ func (p *Parser) parseParameters() []ast.Expression {
 			break
 		}
 
-		param := p.parseExpression(NORMAL)
+		param := p.parseParameter()
 		params = append(params, param)
 	}
 
@@ -131,18 +216,58 @@ func (p *Parser) parseParameters() []ast.Expression {
 	return params
 }
 
+// parseParameter parses a parameter, which is a keyword parameter if its name is followed by a colon
+func (p *Parser) parseParameter() ast.Expression {
+	// Block parameter like `&block`
+	if p.curTokenIs(token.BitAnd) {
+		if !p.expectPeek(token.Ident) {
+			return nil
+		}
+
+		ident := &ast.Identifier{BaseNode: &ast.BaseNode{Token: p.curToken}, Value: p.curToken.Literal}
+		return &ast.PrefixExpression{BaseNode: ident.BaseNode, Operator: "&", Right: ident}
+	}
+
+	param := p.parseExpression(NORMAL)
+	ident, ok := param.(*ast.Identifier)
+
+	if !ok || !p.peekTokenIs(token.Colon) {
+		return param
+	}
+
+	p.nextToken() // ":"
+	pair := &ast.ArgumentPairExpression{BaseNode: &ast.BaseNode{Token: ident.Token}, Key: ident}
+
+	// Required keyword parameter like `host:`
+	if p.peekTokenIs(token.Comma) || p.peekTokenIs(token.RParen) {
+		return pair
+	}
+
+	p.nextToken()
+	pair.Value = p.parseExpression(NORMAL)
+
+	return pair
+}
+
 func (p *Parser) checkMethodParameters(params []ast.Expression) {
 
 	/*
 		0 means previous arg is normal argument
 		1 means previous arg is optioned argument
 		2 means previous arg is splat argument
+		3 means previous arg is keyword argument
+		4 means previous arg is block argument
 	*/
 	argState := 0
 
 	checkedParams := []ast.Expression{}
 
 	for _, param := range params {
+		if argState == 4 {
+			p.error = &Error{Message: fmt.Sprintf("Block argument should be the last argument. Line: %d", p.curToken.Line), errType: SyntaxError}
+			break
+		}
+
 		switch exp := param.(type) {
 		case *ast.Identifier:
 			switch argState {
@@ -150,20 +275,33 @@ func (p *Parser) checkMethodParameters(params []ast.Expression) {
 				p.error = &Error{Message: fmt.Sprintf("Normal argument \"%s\" should be defined before optioned argument. Line: %d", exp.Value, p.curToken.Line), errType: SyntaxError}
 			case 2:
 				p.error = &Error{Message: fmt.Sprintf("Normal argument \"%s\" should be defined before splat argument. Line: %d", exp.Value, p.curToken.Line), errType: SyntaxError}
+			case 3:
+				p.error = &Error{Message: fmt.Sprintf("Normal argument \"%s\" should be defined before keyword argument. Line: %d", exp.Value, p.curToken.Line), errType: SyntaxError}
 			}
 		case *ast.AssignExpression:
 			switch argState {
 			case 2:
 				p.error = &Error{Message: fmt.Sprintf("Optioned argument \"%s\" should be defined before splat argument. Line: %d", exp.String(), p.curToken.Line), errType: SyntaxError}
+			case 3:
+				p.error = &Error{Message: fmt.Sprintf("Optioned argument \"%s\" should be defined before keyword argument. Line: %d", exp.String(), p.curToken.Line), errType: SyntaxError}
 			}
 			argState = 1
 		case *ast.PrefixExpression:
+			if exp.Operator == "&" {
+				argState = 4
+				break
+			}
+
 			switch argState {
 			case 2:
 				p.error = &Error{Message: fmt.Sprintf("Can't define splat argument more than once. Line: %d", p.curToken.Line), errType: SyntaxError}
+			case 3:
+				p.error = &Error{Message: fmt.Sprintf("Splat argument should be defined before keyword argument. Line: %d", p.curToken.Line), errType: SyntaxError}
 			}
 
 			argState = 2
+		case *ast.ArgumentPairExpression:
+			argState = 3
 		}
 
 		if p.error != nil {
@@ -222,6 +360,12 @@ func (p *Parser) parseModuleStatement() *ast.ModuleStatement {
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	stmt := &ast.ReturnStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
 
+	// Return without value, like `return if x`
+	if p.peekTokenIs(token.If) || p.peekTokenIs(token.Unless) || p.peekTokenIs(token.While) || p.peekTokenIs(token.Until) {
+		stmt.ReturnValue = &ast.NilExpression{BaseNode: &ast.BaseNode{Token: p.curToken}}
+		return stmt
+	}
+
 	p.nextToken()
 
 	stmt.ReturnValue = p.parseExpression(NORMAL)
@@ -233,9 +377,27 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+// parseJumpValue parses the optional value after `next` or `break`, like `break x * 2`.
+// It returns nil if the statement ends or is followed by a modifier.
+func (p *Parser) parseJumpValue() ast.Expression {
+	if !p.peekTokenAtSameLine() {
+		return nil
+	}
+
+	switch p.peekToken.Type {
+	case token.Semicolon, token.End, token.RBrace, token.If, token.Unless, token.While, token.Until:
+		return nil
+	}
+
+	p.nextToken()
+
+	return p.parseExpression(NORMAL)
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 	stmt := &ast.ExpressionStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
-	if p.curTokenIs(token.Ident) || p.curTokenIs(token.InstanceVariable) {
+	if p.curTokenIs(token.Ident) || p.curTokenIs(token.InstanceVariable) || p.curTokenIs(token.ClassVariable) ||
+		p.curTokenIs(token.GlobalVariable) {
 		// This is used for identifying method call without parens
 		// Or multiple variable assignment
 		stmt.Expression = p.parseExpression(LOWEST)
@@ -258,7 +420,7 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		p.nextToken()
 	}
 
-	for !p.curTokenIs(token.End) && !p.curTokenIs(token.Else) && !p.curTokenIs(token.ElsIf) {
+	for !p.curTokenIs(token.End) && !p.curTokenIs(token.Else) && !p.curTokenIs(token.ElsIf) && !p.curTokenIs(token.Rescue) && !p.curTokenIs(token.Ensure) && !p.curTokenIs(token.In) {
 
 		if p.curTokenIs(token.EOF) {
 			p.error = &Error{Message: "Unexpected EOF", errType: EndOfFileError}
@@ -275,6 +437,14 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 	return bs
 }
 
+func (p *Parser) parseRetryStatement() *ast.RetryStatement {
+	if p.rescueDepth == 0 {
+		p.error = &Error{Message: fmt.Sprintf("Can't use retry outside of rescue clause. Line: %d", p.curToken.Line), errType: SyntaxError}
+	}
+
+	return &ast.RetryStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+}
+
 func (p *Parser) parseWhileStatement() *ast.WhileStatement {
 	ws := &ast.WhileStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
 
@@ -294,6 +464,25 @@ func (p *Parser) parseWhileStatement() *ast.WhileStatement {
 	return ws
 }
 
+func (p *Parser) parseUntilStatement() *ast.UntilStatement {
+	us := &ast.UntilStatement{BaseNode: &ast.BaseNode{Token: p.curToken}}
+
+	p.nextToken()
+	// Prevent expression's method call to consume until's block as argument.
+	p.acceptBlock = false
+	us.Condition = p.parseExpression(NORMAL)
+	p.acceptBlock = true
+
+	// `do` after the condition is optional
+	if p.peekTokenIs(token.Do) {
+		p.nextToken()
+	}
+
+	us.Body = p.parseBlockStatement()
+
+	return us
+}
+
 func paramDuplicated(params []ast.Expression, param ast.Expression) bool {
 	for _, p := range params {
 		if getArgName(param) == getArgName(p) {
@@ -304,11 +493,12 @@ func paramDuplicated(params []ast.Expression, param ast.Expression) bool {
 }
 
 func getArgName(exp ast.Expression) string {
-	assignExp, ok := exp.(*ast.AssignExpression)
-
-	if ok {
-		return assignExp.Variables[0].TokenLiteral()
+	switch exp := exp.(type) {
+	case *ast.AssignExpression:
+		return exp.Variables[0].TokenLiteral()
+	case *ast.PrefixExpression:
+		return exp.Right.TokenLiteral()
+	default:
+		return exp.TokenLiteral()
 	}
-
-	return exp.TokenLiteral()
 }
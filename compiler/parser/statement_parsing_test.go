@@ -43,6 +43,53 @@ func TestReturnStatements(t *testing.T) {
 
 }
 
+func TestNextAndBreakStatements(t *testing.T) {
+	input := `
+	next
+	next x * 2
+	break
+	break 10
+	break if x
+	redo unless y
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	if len(program.Statements) != 6 {
+		t.Fatalf("expect program's statements to be 6. got=%d", len(program.Statements))
+	}
+
+	if v := program.Statements[0].(*ast.NextStatement).Value; v != nil {
+		t.Fatalf("expect next's value to be nil. got=%s", v.String())
+	}
+
+	testInfixExpression(t, program.Statements[1].(*ast.NextStatement).Value, "x", "*", 2)
+
+	if v := program.Statements[2].(*ast.BreakStatement).Value; v != nil {
+		t.Fatalf("expect break's value to be nil. got=%s", v.String())
+	}
+
+	testIntegerLiteral(t, program.Statements[3].(*ast.BreakStatement).Value, 10)
+
+	ifExp := program.Statements[4].(*ast.ExpressionStatement).Expression.(*ast.IfExpression)
+
+	if v := ifExp.Conditionals[0].Consequence.Statements[0].(*ast.BreakStatement).Value; v != nil {
+		t.Fatalf("expect break's value to be nil. got=%s", v.String())
+	}
+
+	unlessExp := program.Statements[5].(*ast.ExpressionStatement).Expression.(*ast.UnlessExpression)
+
+	if _, ok := unlessExp.Consequence.Statements[0].(*ast.RedoStatement); !ok {
+		t.Fatalf("expect a redo statement. got=%T", unlessExp.Consequence.Statements[0])
+	}
+}
+
 func TestClassStatement(t *testing.T) {
 	input := `
 	class Foo
@@ -197,6 +244,82 @@ func TestDefStatement(t *testing.T) {
 	testIntegerLiteral(t, secondExpressionStmt.Expression, 123)
 }
 
+func TestDefStatementWithKeywordParameters(t *testing.T) {
+	input := `
+	def connect(addr, host:, port: 80)
+	end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.DefStatement)
+
+	if len(stmt.Parameters) != 3 {
+		t.Fatalf("expect 3 parameters. got=%d", len(stmt.Parameters))
+	}
+
+	testIdentifier(t, stmt.Parameters[0], "addr")
+
+	host, ok := stmt.Parameters[1].(*ast.ArgumentPairExpression)
+
+	if !ok {
+		t.Fatalf("expect parameter to be an ArgumentPairExpression. got=%T", stmt.Parameters[1])
+	}
+
+	if host.Key.Value != "host" || host.Value != nil {
+		t.Fatalf("expect required keyword parameter 'host'. got=%s", host.String())
+	}
+
+	port, ok := stmt.Parameters[2].(*ast.ArgumentPairExpression)
+
+	if !ok {
+		t.Fatalf("expect parameter to be an ArgumentPairExpression. got=%T", stmt.Parameters[2])
+	}
+
+	if port.Key.Value != "port" {
+		t.Fatalf("expect keyword parameter 'port'. got=%s", port.Key.Value)
+	}
+
+	testIntegerLiteral(t, port.Value, 80)
+}
+
+func TestDefStatementWithBlockParameter(t *testing.T) {
+	input := `
+	def register(name, &block)
+	end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.DefStatement)
+
+	if len(stmt.Parameters) != 2 {
+		t.Fatalf("expect 2 parameters. got=%d", len(stmt.Parameters))
+	}
+
+	testIdentifier(t, stmt.Parameters[0], "name")
+
+	block, ok := stmt.Parameters[1].(*ast.PrefixExpression)
+
+	if !ok || block.Operator != "&" {
+		t.Fatalf("expect block parameter to be a PrefixExpression with '&'. got=%s", stmt.Parameters[1].String())
+	}
+
+	testIdentifier(t, block.Right, "block")
+}
+
 func TestDefStatementArgumentDefinitionError(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -247,6 +370,30 @@ func TestDefStatementArgumentDefinitionError(t *testing.T) {
 			a + b
 		end
 		`, "Can't define splat argument more than once. Line: 1"},
+		{`
+		def connect(host:, port)
+		end
+		`, "Normal argument \"port\" should be defined before keyword argument. Line: 1"},
+		{`
+		def connect(host:, port = 80)
+		end
+		`, "Optioned argument \"port = 80\" should be defined before keyword argument. Line: 1"},
+		{`
+		def connect(host:, *rest)
+		end
+		`, "Splat argument should be defined before keyword argument. Line: 1"},
+		{`
+		def connect(host:, host: 80)
+		end
+		`, "Duplicate argument name: \"host\". Line: 1"},
+		{`
+		def register(&block, name)
+		end
+		`, "Block argument should be the last argument. Line: 1"},
+		{`
+		def register(block, &block)
+		end
+		`, "Duplicate argument name: \"block\". Line: 1"},
 	}
 
 	for i, tt := range tests {
@@ -300,6 +447,98 @@ func TestDefStatementWithYield(t *testing.T) {
 	}
 }
 
+func TestYieldWithoutParens(t *testing.T) {
+	input := `
+	def foo
+	  yield 1, bar
+	  yield [1, 2]
+	end
+	`
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	block := program.Statements[0].(*ast.DefStatement).BlockStatement
+	firstYield := block.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.YieldExpression)
+
+	testIntegerLiteral(t, firstYield.Arguments[0], 1)
+	testIdentifier(t, firstYield.Arguments[1], "bar")
+
+	secondYield, ok := block.Statements[1].(*ast.ExpressionStatement).Expression.(*ast.YieldExpression)
+
+	if !ok {
+		t.Fatalf("Expect yield with an Array to be an YieldExpression. got=%T", block.Statements[1].(*ast.ExpressionStatement).Expression)
+	}
+
+	if len(secondYield.Arguments) != 1 {
+		t.Fatalf("Expect yield to have 1 argument. got=%d", len(secondYield.Arguments))
+	}
+
+	if _, ok := secondYield.Arguments[0].(*ast.ArrayExpression); !ok {
+		t.Fatalf("Expect yield's argument to be an ArrayExpression. got=%T", secondYield.Arguments[0])
+	}
+}
+
+func TestDefStatementWithSuper(t *testing.T) {
+	input := `
+	def foo(a)
+	  super
+	  super()
+	  super(a, 1)
+	  super(a) do |x|
+	    x
+	  end
+	end
+	`
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	stmt := program.Statements[0].(*ast.DefStatement)
+	block := stmt.BlockStatement
+	tests := []struct {
+		forwardArgs bool
+		argCount    int
+		hasBlock    bool
+	}{
+		{true, 0, false},
+		{false, 0, false},
+		{false, 2, false},
+		{false, 1, true},
+	}
+
+	for i, tt := range tests {
+		exp, ok := block.Statements[i].(*ast.ExpressionStatement).Expression.(*ast.SuperExpression)
+
+		if !ok {
+			t.Fatalf("At case %d expect expression to be a SuperExpression. got=%T", i, block.Statements[i])
+		}
+
+		if exp.ForwardArgs != tt.forwardArgs {
+			t.Fatalf("At case %d expect ForwardArgs to be %t. got=%t", i, tt.forwardArgs, exp.ForwardArgs)
+		}
+
+		if len(exp.Arguments) != tt.argCount {
+			t.Fatalf("At case %d expect %d arguments. got=%d", i, tt.argCount, len(exp.Arguments))
+		}
+
+		if (exp.Block != nil) != tt.hasBlock {
+			t.Fatalf("At case %d expect block to be given: %t", i, tt.hasBlock)
+		}
+	}
+
+	testIdentifier(t, block.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.SuperExpression).Arguments[0], "a")
+	testIntegerLiteral(t, block.Statements[2].(*ast.ExpressionStatement).Expression.(*ast.SuperExpression).Arguments[1], 1)
+}
+
 func TestWhileStatement(t *testing.T) {
 	input := `
 	while i < a.length do
@@ -350,3 +589,56 @@ func TestWhileStatement(t *testing.T) {
 	testIdentifier(t, secondCall.Receiver, "i")
 	testMethodName(t, secondCall, "++")
 }
+
+func TestUntilStatement(t *testing.T) {
+	input := `
+	until i > a.length
+	  puts(i)
+	  i++
+	end
+
+	until i > 10 do
+	  i++
+	end
+	`
+
+	l := lexer.New(input)
+	p := New(l)
+	program, err := p.ParseProgram()
+
+	if err != nil {
+		t.Fatal(err.Message)
+	}
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("expect program's statements to be 2. got=%d", len(program.Statements))
+	}
+
+	untilStatement := program.Statements[0].(*ast.UntilStatement)
+	infix := untilStatement.Condition.(*ast.InfixExpression)
+	testIdentifier(t, infix.Left, "i")
+
+	callExp := infix.Right.(*ast.CallExpression)
+	testMethodName(t, callExp, "length")
+
+	if callExp.Block != nil {
+		t.Fatalf("Condition expression shouldn't have block")
+	}
+
+	block := untilStatement.Body
+
+	if len(block.Statements) != 2 {
+		t.Fatalf("expect until's body to have 2 statements. got=%d", len(block.Statements))
+	}
+
+	firstCall := block.Statements[0].(*ast.ExpressionStatement).Expression.(*ast.CallExpression)
+	testMethodName(t, firstCall, "puts")
+	testIdentifier(t, firstCall.Arguments[0], "i")
+
+	untilStatement = program.Statements[1].(*ast.UntilStatement)
+	testInfixExpression(t, untilStatement.Condition, "i", ">", 10)
+
+	if len(untilStatement.Body.Statements) != 1 {
+		t.Fatalf("expect until's body to have 1 statement. got=%d", len(untilStatement.Body.Statements))
+	}
+}
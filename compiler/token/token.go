@@ -15,12 +15,20 @@ const (
 	Illegal = "ILLEGAL"
 	EOF     = "EOF"
 
-	Constant         = "CONSTANT"
-	Ident            = "IDENT"
-	InstanceVariable = "INSTANCE_VAR"
-	Int              = "INT"
-	String           = "STRING"
-	Comment          = "COMMENT"
+	Constant           = "CONSTANT"
+	Ident              = "IDENT"
+	InstanceVariable   = "INSTANCE_VAR"
+	GlobalVariable     = "GLOBAL_VAR"
+	ClassVariable      = "CLASS_VAR"
+	Int                = "INT"
+	Float              = "FLOAT"
+	Rational           = "RATIONAL"
+	String             = "STRING"
+	Symbol             = "SYMBOL"
+	Regexp             = "REGEXP"
+	InterpolatedString = "INTERPOLATED_STRING"
+	Command            = "COMMAND"
+	Comment            = "COMMENT"
 
 	Assign   = "="
 	Plus     = "+"
@@ -29,26 +37,38 @@ const (
 	MinusEq  = "-="
 	Bang     = "!"
 	Asterisk = "*"
+	MulEq    = "*="
 	Pow      = "**"
 	Slash    = "/"
+	DivEq    = "/="
 	Dot      = "."
 	Incr     = "++"
 	Decr     = "--"
 	And      = "&&"
+	AndEq    = "&&="
 	Or       = "||"
 	OrEq     = "||="
 	Modulo   = "%"
+	BitAnd   = "&"
+	BitXor   = "^"
+	BitNot   = "~"
 
-	LT   = "<"
-	LTE  = "<="
-	GT   = ">"
-	GTE  = ">="
-	COMP = "<=>"
+	LT     = "<"
+	LTE    = "<="
+	GT     = ">"
+	GTE    = ">="
+	COMP   = "<=>"
+	LShift = "<<"
+	RShift = ">>"
+
+	HashRocket = "=>"
+	Lambda     = "->"
 
 	Comma     = ","
 	Semicolon = ";"
 	Colon     = ":"
 	Bar       = "|"
+	Question  = "?"
 
 	LParen   = "("
 	RParen   = ")"
@@ -57,14 +77,17 @@ const (
 	LBracket = "["
 	RBracket = "]"
 
-	Eq    = "=="
-	NotEq = "!="
-	Range = ".."
+	Eq     = "=="
+	CaseEq = "==="
+	NotEq  = "!="
+	Match  = "=~"
+	Range  = ".."
 
 	True   = "TRUE"
 	False  = "FALSE"
 	Null   = "Null"
 	If     = "IF"
+	Unless = "UNLESS"
 	ElsIf  = "ELSIF"
 	Else   = "ELSE"
 	Return = "RETURN"
@@ -74,10 +97,19 @@ const (
 	Self   = "SELF"
 	End    = "END"
 	While  = "WHILE"
+	Until  = "UNTIL"
 	Do     = "DO"
 	Yield  = "YIELD"
+	Super  = "SUPER"
 	Class  = "CLASS"
 	Module = "MODULE"
+	Begin  = "BEGIN"
+	Rescue = "RESCUE"
+	Ensure = "ENSURE"
+	Retry  = "RETRY"
+	Redo   = "REDO"
+	Case   = "CASE"
+	In     = "IN"
 
 	ResolutionOperator = "::"
 )
@@ -88,18 +120,28 @@ var keywords = map[string]Type{
 	"false":  False,
 	"nil":    Null,
 	"if":     If,
+	"unless": Unless,
 	"elsif":  ElsIf,
 	"else":   Else,
 	"return": Return,
 	"self":   Self,
 	"end":    End,
 	"while":  While,
+	"until":  Until,
 	"do":     Do,
 	"yield":  Yield,
+	"super":  Super,
 	"next":   Next,
 	"class":  Class,
 	"module": Module,
 	"break":  Break,
+	"begin":  Begin,
+	"rescue": Rescue,
+	"ensure": Ensure,
+	"retry":  Retry,
+	"redo":   Redo,
+	"case":   Case,
+	"in":     In,
 }
 
 // LookupIdent is used for keyword identification
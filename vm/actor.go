@@ -0,0 +1,334 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// actorMailboxSize is the number of messages an actor's mailbox holds before `tell` blocks
+const actorMailboxSize = 100
+
+// ActorObject runs an object's `handle` method on its own goroutine. Messages are put into the actor's mailbox
+// by `tell` and `ask`, and the actor handles them one by one in order, so the handler's state is never accessed
+// by two threads at the same time. It's provided by the "actor" library.
+//
+// ```ruby
+// require "actor"
+//
+// class Counter
+//   def initialize
+//     @count = 0
+//   end
+//
+//   def handle(message)
+//     @count += message
+//   end
+// end
+//
+// counter = Actor.new(Counter.new)
+// counter.tell(1)
+// counter.tell(2)
+// counter.ask(3).value # => 6
+// counter.stop
+// ```
+//
+// Errors raised by `handle` are raised by `Future#value` for `ask`, and written to `$stderr` for `tell`.
+type ActorObject struct {
+	*baseObj
+	handler Object
+	mailbox chan *actorMessage
+	// stopped is closed when the actor has handled all messages after `stop`
+	stopped chan struct{}
+}
+
+// actorMessage is a message in an actor's mailbox, future is nil if the message is sent by `tell`
+type actorMessage struct {
+	body   Object
+	future *FutureObject
+}
+
+// FutureObject is the result of `Actor#ask`, which is available once the actor has handled the message.
+//
+// ```ruby
+// future = actor.ask("ping")
+// future.done? # => false
+// future.value # => "pong"
+// ```
+type FutureObject struct {
+	*baseObj
+	// done is closed when the value is set
+	done  chan struct{}
+	value Object
+}
+
+// Class methods --------------------------------------------------------
+func builtinActorClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Starts an actor that handles messages with the given object's `handle` method.
+			//
+			// ```ruby
+			// actor = Actor.new(Counter.new)
+			// ```
+			//
+			// @param handler [Object]
+			// @return [Actor]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if args[0].findMethod("handle") == nil {
+						return t.initErrorObject(errors.ArgumentError, "Expect handler to respond to 'handle'. got: %s", args[0].toString())
+					}
+
+					a := &ActorObject{
+						baseObj: &baseObj{class: receiver.(*RClass)},
+						handler: args[0],
+						mailbox: make(chan *actorMessage, actorMailboxSize),
+						stopped: make(chan struct{}),
+					}
+
+					go t.vm.runActor(a)
+
+					return a
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinActorInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the actor hasn't stopped.
+			//
+			// ```ruby
+			// actor.alive? # => true
+			// actor.stop
+			// actor.alive? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "alive?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					select {
+					case <-receiver.(*ActorObject).stopped:
+						return FALSE
+					default:
+						return TRUE
+					}
+				}
+			},
+		},
+		{
+			// Sends the message to the actor and returns a Future of the result of handling it.
+			//
+			// ```ruby
+			// actor.ask("ping").value # => "pong"
+			// ```
+			//
+			// @param message [Object]
+			// @return [Actor::Future]
+			Name: "ask",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					a := receiver.(*ActorObject)
+					f := &FutureObject{
+						baseObj: &baseObj{class: a.class.getClassConstant("Future")},
+						done:    make(chan struct{}),
+					}
+
+					if !a.send(&actorMessage{body: args[0], future: f}) {
+						return t.initErrorObject(errors.ThreadError, "Can't send messages to a stopped actor")
+					}
+
+					return f
+				}
+			},
+		},
+		{
+			// Stops the actor after it handles the messages in its mailbox, and waits until it finishes.
+			//
+			// ```ruby
+			// actor.tell("bye")
+			// actor.stop
+			// ```
+			//
+			// @return [Actor]
+			Name: "stop",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					a := receiver.(*ActorObject)
+					a.close()
+					<-a.stopped
+
+					return a
+				}
+			},
+		},
+		{
+			// Sends the message to the actor without waiting for it to be handled.
+			//
+			// ```ruby
+			// actor.tell("hello")
+			// ```
+			//
+			// @param message [Object]
+			// @return [Actor]
+			Name: "tell",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					a := receiver.(*ActorObject)
+
+					if !a.send(&actorMessage{body: args[0]}) {
+						return t.initErrorObject(errors.ThreadError, "Can't send messages to a stopped actor")
+					}
+
+					return a
+				}
+			},
+		},
+	}
+}
+
+func builtinFutureInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the result is available.
+			//
+			// ```ruby
+			// f = actor.ask("ping")
+			// f.value
+			// f.done? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "done?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					select {
+					case <-receiver.(*FutureObject).done:
+						return TRUE
+					default:
+						return FALSE
+					}
+				}
+			},
+		},
+		{
+			// Waits for the actor to handle the message and returns the result. The error raised by the handler
+			// is raised again.
+			//
+			// ```ruby
+			// actor.ask("ping").value # => "pong"
+			// ```
+			//
+			// @return [Object]
+			Name: "value",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f := receiver.(*FutureObject)
+					<-f.done
+
+					return f.value
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initActorClass(vm *VM) {
+	actor := vm.initializeClass("Actor", false)
+	actor.setBuiltinMethods(builtinActorClassMethods(), true)
+	actor.setBuiltinMethods(builtinActorInstanceMethods(), false)
+
+	future := vm.initializeClass("Future", false)
+	future.setBuiltinMethods(builtinFutureInstanceMethods(), false)
+	future.scope = actor
+	actor.setClassConstant(future)
+
+	vm.objectClass.setClassConstant(actor)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (a *ActorObject) toString() string {
+	return fmt.Sprintf("#<Actor:%p handler: %s>", a.mailbox, a.handler.toString())
+}
+
+// Alias of toString
+func (a *ActorObject) toJSON() string {
+	return a.toString()
+}
+
+// send puts the message into the mailbox, it returns false if the actor has stopped
+func (a *ActorObject) send(m *actorMessage) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	a.mailbox <- m
+	return true
+}
+
+// close closes the mailbox, it's fine to close it again
+func (a *ActorObject) close() {
+	defer func() {
+		recover()
+	}()
+
+	close(a.mailbox)
+}
+
+// Returns the object's name as the string format
+func (f *FutureObject) toString() string {
+	return fmt.Sprintf("#<Future:%p>", f.done)
+}
+
+// Alias of toString
+func (f *FutureObject) toJSON() string {
+	return f.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// runActor handles the actor's messages until its mailbox is closed. Every message is handled on a new thread,
+// so an error raised by a message doesn't affect the next one.
+func (vm *VM) runActor(a *ActorObject) {
+	defer close(a.stopped)
+
+	for m := range a.mailbox {
+		result := vm.newThread().sendMethod("handle", a.handler, m.body)
+
+		if m.future != nil {
+			m.future.value = result
+			close(m.future.done)
+			continue
+		}
+
+		if err, ok := result.(*Error); ok && !err.rescued {
+			fmt.Fprintln(vm.stderr(), err.Message)
+		}
+	}
+}
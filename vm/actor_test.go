@@ -0,0 +1,128 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestActorClass(t *testing.T) {
+	counter := `
+	require "actor"
+
+	class Counter
+	  def initialize
+	    @count = 0
+	  end
+
+	  def handle(message)
+	    raise(ArgumentError, "Negative message") if message < 0
+	    @count += message
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		a = Actor.new(Counter.new)
+		a.tell(1)
+		a.tell(2)
+		a.ask(3).value
+		`, 6},
+		{`
+		a = Actor.new(Counter.new)
+		a.tell(1).class.name
+		`, "Actor"},
+		{`
+		a = Actor.new(Counter.new)
+		f = a.ask(1)
+		f.value
+		f.done?
+		`, true},
+		{`Actor.new(Counter.new).ask(1).class.name`, "Future"},
+		{`
+		a = Actor.new(Counter.new)
+
+		begin
+		  a.ask(-1).value
+		rescue ArgumentError => e
+		  e.message
+		end
+		`, "Negative message"},
+		{`
+		a = Actor.new(Counter.new)
+
+		begin
+		  a.ask(-1).value
+		rescue ArgumentError
+		end
+
+		a.ask(5).value
+		`, 5},
+		{`
+		a = Actor.new(Counter.new)
+		a.alive?
+		`, true},
+		{`
+		a = Actor.new(Counter.new)
+		a.tell(1)
+		a.stop
+		a.alive?
+		`, false},
+		{`
+		a = Actor.new(Counter.new)
+		wg = WaitGroup.new
+
+		10.times do
+		  wg.add
+		  thread do
+		    a.tell(1)
+		    wg.done
+		  end
+		end
+
+		wg.wait
+		a.ask(0).value
+		`, 10},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, counter+tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestActorClassFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "actor"
+		Actor.new`, "ArgumentError: Expect 1 argument. got: 0", 2},
+		{`require "actor"
+		Actor.new(1)`, "ArgumentError: Expect handler to respond to 'handle'. got: 1", 2},
+		{`require "actor"
+		class Foo
+		  def handle(m); end
+		end
+		a = Actor.new(Foo.new)
+		a.stop
+		a.tell(1)`, "ThreadError: Can't send messages to a stopped actor", 7},
+		{`require "actor"
+		class Foo
+		  def handle(m); end
+		end
+		a = Actor.new(Foo.new)
+		a.stop
+		a.ask(1)`, "ThreadError: Can't send messages to a stopped actor", 7},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
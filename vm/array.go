@@ -2,7 +2,11 @@ package vm
 
 import (
 	"bytes"
+	"fmt"
+	"runtime"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -11,10 +15,13 @@ import (
 // ArrayObject represents instance from Array class.
 // An array is a collection of different objects that are ordered and indexed.
 // Elements in an array can belong to any class.
+// Array includes Enumerable, which provides `map`, `select`, `reduce` and other methods based on `#each`.
 type ArrayObject struct {
 	*baseObj
 	Elements []Object
 	splat    bool
+	// lock is held while the array's methods are called if it's a Concurrent::Array
+	lock *reentrantLock
 }
 
 // Class methods --------------------------------------------------------
@@ -51,14 +58,14 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 arguments. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 arguments. got=%d", len(args))
 					}
 
 					i := args[0]
 					index, ok := i.(*IntegerObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -94,10 +101,14 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					// First arg is index
 					// Second arg is assigned value
 					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
 					}
 
 					i := args[0]
@@ -105,7 +116,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					indexValue := index.value
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -113,7 +124,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					// Negative index value condition
 					if indexValue < 0 {
 						if len(arr.Elements) < -indexValue {
-							return t.vm.initErrorObject(errors.ArgumentError, "Index is too small for array. got=%s", i.Class().Name)
+							return t.initErrorObject(errors.ArgumentError, "Index is too small for array. got=%s", i.Class().Name)
 						}
 						arr.Elements[len(arr.Elements)+indexValue] = args[1]
 						return arr.Elements[len(arr.Elements)+indexValue]
@@ -150,14 +161,14 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
 					}
 
 					i := args[0]
 					index, ok := i.(*IntegerObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -188,8 +199,12 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Name: "clear",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -210,13 +225,17 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Name: "concat",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					arr := receiver.(*ArrayObject)
 
 					for _, arg := range args {
 						addAr, ok := arg.(*ArrayObject)
 
 						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ArrayClass, arg.Class().Name)
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ArrayClass, arg.Class().Name)
 						}
 
 						for _, el := range addAr.Elements {
@@ -247,7 +266,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					var count int
 
 					if len(args) > 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument, got=%v", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument, got=%v", len(args))
 					}
 
 					if blockFrame != nil {
@@ -312,11 +331,11 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "each", args)
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -333,11 +352,11 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "each_index", args)
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -362,7 +381,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -381,7 +400,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) > 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -398,11 +417,11 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					arg, ok := args[0].(*IntegerObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
 					if arg.value < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect argument to be positive value. got=%d", arg.value)
+						return t.initErrorObject(errors.ArgumentError, "Expect argument to be positive value. got=%d", arg.value)
 					}
 
 					if arrLength > arg.value {
@@ -428,7 +447,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					arr := receiver.(*ArrayObject)
 
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					newElements := arr.flatten()
@@ -437,6 +456,84 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Loop through each element with the given block.
+			// Returns a hash whose keys are the block's results and values are arrays of the elements
+			// that produced each result. The results are compared with `hash` and `eql?`.
+			//
+			// ```ruby
+			// a = [1, 2, 3, 4, 5]
+			// a.group_by do |i|
+			//   i.even?
+			// end
+			// # => { true => [2, 4], false => [1, 3, 5] }
+			// ```
+			// @return [Hash]
+			Name: "group_by",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					arr := receiver.(*ArrayObject)
+					h := t.vm.initHashObject(map[string]Object{})
+
+					for _, obj := range arr.Elements {
+						key := t.builtinMethodYield(blockFrame, obj).Target
+
+						k, found, err := h.lookupKey(t, key)
+						if err != nil {
+							return err
+						}
+
+						if !found {
+							h.set(k, key, t.vm.initArrayObject([]Object{}))
+						}
+
+						h.Pairs[k].(*ArrayObject).push([]Object{obj})
+					}
+
+					return h
+				}
+			},
+		},
+		{
+			// Returns true if any element of the array is equal to the given object, elements are compared with `==`.
+			//
+			// ```ruby
+			// [1, 2, 3].include?(2)          # => true
+			// [[1, 2], "a"].include?([1, 2]) # => true
+			// [1, 2, 3].include?("2")        # => false
+			// ```
+			// @return [Boolean]
+			Name: "include?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+
+					for _, e := range arr.Elements {
+						eq, err := t.objectsEqual(e, args[0])
+						if err != nil {
+							return err
+						}
+						if eq {
+							return TRUE
+						}
+					}
+
+					return FALSE
+				}
+			},
+		},
 		{
 			// Returns a string by concatenating each element to string, separated by given separator.
 			// If separator is nil, it uses empty string.
@@ -459,17 +556,22 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 						arg, ok := args[0].(*StringObject)
 
 						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 						}
 
 						sep = arg.value
 					} else {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 or 1 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 or 1 argument. got=%d", len(args))
 					}
 
 					elements := []string{}
 					for _, e := range arr.flatten() {
-						elements = append(elements, e.toString())
+						s, err := t.objectToString(e)
+						if err != nil {
+							return err
+						}
+
+						elements = append(elements, s)
 					}
 
 					return t.vm.initStringObject(strings.Join(elements, sep))
@@ -482,7 +584,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) > 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -495,11 +597,11 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					arg, ok := args[0].(*IntegerObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
 					if arg.value < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect argument to be positive value. got=%d", arg.value)
+						return t.initErrorObject(errors.ArgumentError, "Expect argument to be positive value. got=%d", arg.value)
 					}
 
 					if arrLength > arg.value {
@@ -521,7 +623,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -530,32 +632,74 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Loop through each element with the given block. Return a new array with each yield element.
+			// Like `map`, but calls the block with the elements in parallel on a pool of threads, and returns the
+			// results in the order of the elements. The number of threads is given by `workers`, which is the
+			// number of CPUs by default.
 			//
 			// ```ruby
-			// a = ["a", "b", "c"]
-			//
-			// a.map do |e|
-			//   e + e
+			// urls.pmap(workers: 4) do |url|
+			//   Net::HTTP.get(url)
 			// end
-			// # => ["aa", "bb", "cc"]
+			//
+			// [1, 2, 3].pmap do |x|
+			//   x * 2
+			// end # => [2, 4, 6]
 			// ```
-			Name: "map",
+			//
+			// All elements are handled even if the block raises errors. If it raises one error, the error is
+			// raised again. If it raises more, a ParallelError that lists them is raised.
+			//
+			// @param workers [Integer]
+			// @return [Array]
+			Name: "pmap",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					arr := receiver.(*ArrayObject)
-					var elements = make([]Object, len(arr.Elements))
+					workers := runtime.NumCPU()
+
+					switch len(args) {
+					case 0:
+					case 1:
+						h, ok := args[0].(*HashObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+						}
+
+						for k, v := range h.Pairs {
+							if k != "workers" {
+								return t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+							}
+
+							n, ok := v.(*IntegerObject)
+
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, v.Class().Name)
+							}
+
+							if n.value < 1 {
+								return t.initErrorObject(errors.ArgumentError, "Expect workers to be positive. got: %d", n.value)
+							}
+
+							workers = n.value
+						}
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
 					}
 
-					for i, obj := range arr.Elements {
-						result := t.builtinMethodYield(blockFrame, obj)
-						elements[i] = result.Target
+					// The block is called on other threads, so its frame has to be popped from this thread manually
+					if blockFrame.goBlock == nil {
+						t.callFrameStack.pop()
 					}
 
-					return t.vm.initArrayObject(elements)
+					arr := receiver.(*ArrayObject)
+					elements := make([]Object, len(arr.Elements))
+					copy(elements, arr.Elements)
+
+					return t.parallelMap(elements, workers, blockFrame)
 				}
 			},
 		},
@@ -571,8 +715,12 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -591,54 +739,12 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					arr := receiver.(*ArrayObject)
-					return arr.push(args)
-				}
-			},
-		},
-		{
-			// Loop through each elements and accumulate each results of given block in the first argument of the block
-			// If you do not give an argument, the first element of collection is used as an initial value
-			//
-			// ```ruby
-			// a = [1, 2, 7]
-			//
-			// a.reduce do |sum, n|
-			//   sum + n
-			// end
-			// # => 10
-			//
-			// a.reduce(10) do |sum, n|
-			//   sum + n
-			// end
-			// # => 20
-			// ```
-			Name: "reduce",
-			Fn: func(receiver Object) builtinMethodBody {
-				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					arr := receiver.(*ArrayObject)
-					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					var prev Object
-					var start int
-					if len(args) == 0 {
-						prev = arr.Elements[0]
-						start = 1
-					} else if len(args) == 1 {
-						prev = args[0]
-						start = 0
-					} else {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 or 1 argument. got=%d", len(args))
-					}
-
-					for i := start; i < len(arr.Elements); i++ {
-						result := t.builtinMethodYield(blockFrame, prev, arr.Elements[i])
-						prev = result.Target
-					}
-
-					return prev
+					arr := receiver.(*ArrayObject)
+					return arr.push(args)
 				}
 			},
 		},
@@ -657,7 +763,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) > 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
@@ -668,7 +774,7 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 					if len(args) != 0 {
 						arg, ok := args[0].(*IntegerObject)
 						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 						}
 						rotate = arg.value
 					}
@@ -683,55 +789,180 @@ func builtinArrayInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Loop through each element with the given block.
-			// Return a new array with each element that returns true from yield.
+			// Removes the first element in the array and returns it.
 			//
 			// ```ruby
-			// a = [1, 2, 3, 4, 5]
+			// a = [1, 2, 3]
+			// a.shift # => 1
+			// a       # => [2, 3]
+			// ```
+			Name: "shift",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					return arr.shift()
+				}
+			},
+		},
+		{
+			// Returns a new array with the elements sorted. Elements are compared with their `<=>` method,
+			// or with the given block, which should return a negative Integer, 0 or a positive Integer
+			// like `<=>` does.
 			//
-			// a.select do |e|
-			//   e + 1 > 3
+			// ```ruby
+			// ["b", "c", "a"].sort # => ["a", "b", "c"]
+			// [3, 1, 2].sort       # => [1, 2, 3]
+			//
+			// [3, 1, 2].sort do |a, b|
+			//   b <=> a
 			// end
-			// # => [3, 4, 5]
+			// # => [3, 2, 1]
 			// ```
-			Name: "select",
+			Name: "sort",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					arr := receiver.(*ArrayObject)
-					var elements []Object
+					elems := make([]Object, len(arr.Elements))
+					copy(elems, arr.Elements)
 
-					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					if blockFrame != nil && len(elems) < 2 {
+						// if block is not used, it should be popped
+						t.callFrameStack.pop()
+					}
+
+					var err *Error
+
+					sort.SliceStable(elems, func(i, j int) bool {
+						if err != nil {
+							return false
+						}
+
+						var c int
+
+						if blockFrame == nil {
+							c, err = compare(t, elems[i], elems[j])
+							return c < 0
+						}
+
+						switch r := t.builtinMethodYield(blockFrame, elems[i], elems[j]).Target.(type) {
+						case *Error:
+							err = r
+						case *IntegerObject:
+							c = r.value
+						default:
+							err = t.initErrorObject(errors.ArgumentError, "Comparison of %s with %s failed", elems[i].Class().Name, elems[j].Class().Name)
+						}
+
+						return c < 0
+					})
+
+					if err != nil {
+						return err
 					}
 
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
+		{
+			// Returns a hash whose keys are the array's elements and values are the number of
+			// times each element occurs in the array. Elements are compared with `hash` and `eql?`.
+			//
+			// ```ruby
+			// a = ["a", "b", "a", "c", "a"]
+			// a.tally          # => { a: 3, b: 1, c: 1 }
+			// [1, 1, 2].tally  # => { 1 => 2, 2 => 1 }
+			// ["1", 1].tally   # => { 1 => 1, 1: 1 }
+			// ```
+			// @return [Hash]
+			Name: "tally",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					arr := receiver.(*ArrayObject)
+					h := t.vm.initHashObject(map[string]Object{})
+
 					for _, obj := range arr.Elements {
-						result := t.builtinMethodYield(blockFrame, obj)
-						if result.Target.(*BooleanObject).value {
-							elements = append(elements, obj)
+						k, found, err := h.lookupKey(t, obj)
+						if err != nil {
+							return err
+						}
+
+						count := 1
+						if found {
+							count += h.Pairs[k].(*IntegerObject).value
 						}
+
+						h.set(k, obj, t.vm.initIntegerObject(count))
 					}
 
-					return t.vm.initArrayObject(elements)
+					return h
 				}
 			},
 		},
 		{
-			// Removes the first element in the array and returns it.
+			// Returns a hash converted from an array of `[key, value]` pairs, which is the format
+			// returned by `Hash#to_a`. Keys can be any object, and are compared with `hash` and `eql?`.
+			//
+			// If a block is given, each element is yielded to the block and the block should return
+			// a `[key, value]` pair instead.
 			//
 			// ```ruby
-			// a = [1, 2, 3]
-			// a.shift # => 1
-			// a       # => [2, 3]
+			// [["a", 1], ["b", 2]].to_h # => { a: 1, b: 2 }
+			// { a: 1, b: 2 }.to_a.to_h  # => { a: 1, b: 2 }
+			// [[1, "one"]].to_h         # => { 1 => "one" }
+			//
+			// ["a", "b"].to_h do |s|
+			//   [s, s + s]
+			// end
+			// # => { a: "aa", b: "bb" }
 			// ```
-			Name: "shift",
+			// @return [Hash]
+			Name: "to_h",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					arr := receiver.(*ArrayObject)
-					return arr.shift()
+					h := t.vm.initHashObject(map[string]Object{})
+
+					for _, obj := range arr.Elements {
+						if blockFrame != nil {
+							obj = t.builtinMethodYield(blockFrame, obj).Target
+						}
+
+						pair, ok := obj.(*ArrayObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ArrayClass, obj.Class().Name)
+						}
+
+						if len(pair.Elements) != 2 {
+							return t.initErrorObject(errors.ArgumentError, "Expect element to be a pair of key and value. got=%s", pair.toString())
+						}
+
+						k, _, err := h.lookupKey(t, pair.Elements[0])
+						if err != nil {
+							return err
+						}
+
+						h.set(k, pair.Elements[0], pair.Elements[1])
+					}
+
+					return h
 				}
 			},
 		},
@@ -753,6 +984,7 @@ func (vm *VM) initArrayClass() *RClass {
 	ac := vm.initializeClass(classes.ArrayClass, false)
 	ac.setBuiltinMethods(builtinArrayInstanceMethods(), false)
 	ac.setBuiltinMethods(builtinArrayClassMethods(), true)
+	ac.include(vm.topLevelClass(classes.EnumerableModule))
 	return ac
 }
 
@@ -763,25 +995,35 @@ func (a *ArrayObject) Value() interface{} {
 	return a.Elements
 }
 
+// Returns the lock of a Concurrent::Array, or nil
+func (a *ArrayObject) synchronizer() *reentrantLock {
+	return a.lock
+}
+
 // Returns the object's elements as the string format
 func (a *ArrayObject) toString() string {
+	s, _ := a.inspectElements(defaultInspect)
+	return s
+}
+
+// inspectElements formats the array with each element formatted by inspect
+func (a *ArrayObject) inspectElements(inspect inspector) (string, *Error) {
 	var out bytes.Buffer
 
 	elements := []string{}
 	for _, e := range a.Elements {
-		_, isString := e.(*StringObject)
-		if isString {
-			elements = append(elements, "\""+e.toString()+"\"")
-		} else {
-			elements = append(elements, e.toString())
+		s, err := inspect(e)
+		if err != nil {
+			return "", err
 		}
+		elements = append(elements, s)
 	}
 
 	out.WriteString("[")
 	out.WriteString(strings.Join(elements, ", "))
 	out.WriteString("]")
 
-	return out.String()
+	return out.String(), nil
 }
 
 // Returns the object's elements as the JSON string format
@@ -848,6 +1090,85 @@ func (a *ArrayObject) shift() Object {
 	return value
 }
 
+// Other helper functions -----------------------------------------------
+
+// parallelMap calls the block with each element on the given number of goroutines, every call runs on a new thread
+// so an error raised by the block doesn't affect other calls. The threads run with the caller's timeout.
+func (t *thread) parallelMap(elements []Object, workers int, blockFrame *callFrame) Object {
+	results := make([]Object, len(elements))
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i] = t.yieldOnNewThread(blockFrame, elements[i])
+			}
+		}()
+	}
+
+	for i := range elements {
+		jobs <- i
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	// The calls are stopped by the caller's timeout, which is raised instead of their errors
+	if t.timeout != nil && t.timeout.Err() != nil {
+		return t.timeoutError()
+	}
+
+	failed := []string{}
+	var firstErr *Error
+
+	for i, result := range results {
+		if err, ok := result.(*Error); ok && !err.rescued {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			failed = append(failed, fmt.Sprintf("[%d] %s: %s", i, err.Class().Name, err.message))
+		}
+	}
+
+	switch len(failed) {
+	case 0:
+		return t.vm.initArrayObject(results)
+	case 1:
+		return firstErr
+	default:
+		err := t.initErrorObject(errors.ParallelError, "%d errors occurred: %s", len(failed), strings.Join(failed, ", "))
+		// The cause is a value of the error instead of a raised error
+		firstErr.rescued = true
+		err.cause = firstErr
+		return err
+	}
+}
+
+// yieldOnNewThread calls the block on a new thread and returns the result, a `break` in the block becomes an error
+// since there's no method call to break out of on the new thread
+func (t *thread) yieldOnNewThread(blockFrame *callFrame, args ...Object) (result Object) {
+	newT := t.vm.newThread()
+	newT.timeout = t.timeout
+
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*blockBreak); !ok {
+				panic(r)
+			}
+
+			result = t.initErrorObject(errors.LocalJumpError, "Can't break out of a block running on another thread")
+		}
+	}()
+
+	return newT.builtinMethodYield(blockFrame, args...).Target
+}
+
 // Returns the duplicate of the Array object
 func (a *ArrayObject) copy() Object {
 	elems := make([]Object, len(a.Elements))
@@ -859,5 +1180,9 @@ func (a *ArrayObject) copy() Object {
 		Elements: elems,
 	}
 
+	if a.lock != nil {
+		newArr.lock = newReentrantLock()
+	}
+
 	return newArr
 }
@@ -402,7 +402,6 @@ func TestArrayEachMethod(t *testing.T) {
 
 func TestArrayEachMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`['M', 'A', 'X', 'W', 'E', 'L', 'L'].each`, "InternalError: Can't yield without a block", 1},
 		{`
 		['T', 'A', 'I', 'P', 'E', 'I'].each(101) do |char|
 		  puts char
@@ -444,7 +443,6 @@ func TestArrayEachIndexMethod(t *testing.T) {
 
 func TestArrayEachIndexMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`['M', 'A', 'X', 'W', 'E', 'L', 'L'].each_index`, "InternalError: Can't yield without a block", 1},
 		{`
 		['T', 'A', 'I', 'P', 'E', 'I'].each_index(101) do |char|
 		  puts char
@@ -622,6 +620,123 @@ func TestArrayFlattenMethodFail(t *testing.T) {
 	}
 }
 
+func TestArrayGroupByMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		a = [1, 2, 3, 4, 5]
+		h = a.group_by do |i|
+			i % 2
+		end
+		h[0].length
+		`, 2},
+		{`
+		a = [1, 2, 3, 4, 5]
+		h = a.group_by do |i|
+			i % 2
+		end
+		h[1][2]
+		`, 5},
+		{`
+		a = [1, 2, 3, 4, 5]
+		h = a.group_by do |i|
+			i % 2
+		end
+		h["1"]
+		`, nil},
+		{`
+		a = [1, 2, 3]
+		h = a.group_by do |i|
+			i.even?
+		end
+		h[false].length
+		`, 2},
+		{`
+		a = ["apple", "avocado", "banana"]
+		h = a.group_by do |s|
+			s[0]
+		end
+		h["a"][1]
+		`, "avocado"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayGroupByMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`a = [1, 2]
+		a.group_by
+		`, "InternalError: Can't yield without a block", 2},
+		{`a = [1, 2]
+		a.group_by(1) do |i|
+			i
+		end
+		`, "ArgumentError: Expect 0 argument. got=1", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayIncludeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3].include?(2)`, true},
+		{`[1, 2, 3].include?("2")`, false},
+		{`[].include?(nil)`, false},
+		{`[nil].include?(nil)`, true},
+		{`[[1, 2], { a: 1 }].include?([1, 2])`, true},
+		{`[[1, 2], { a: 1 }].include?({ a: 1 })`, true},
+		{`
+		class Foo
+		  def ==(other)
+		    true
+		  end
+		end
+		[Foo.new].include?(1)
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayIncludeMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1].include?`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`[1].include?(1, 2)`, "ArgumentError: Expect 1 argument. got=2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestArrayJoinMethod(t *testing.T) {
 	testsInt := []struct {
 		input    string
@@ -804,6 +919,108 @@ func TestArrayMapMethod(t *testing.T) {
 	}
 }
 
+func TestArrayPmapMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		[1, 2, 3].pmap do |x|
+		  x * 2
+		end.to_s
+		`, "[2, 4, 6]"},
+		{`
+		[3, 2, 1].pmap(workers: 3) do |x|
+		  sleep(x * 0.01)
+		  x
+		end.to_s
+		`, "[3, 2, 1]"},
+		{`
+		(1..20).to_a.pmap(workers: 1) do |x|
+		  x
+		end.last
+		`, 20},
+		{`
+		[].pmap do |x|
+		  x
+		end.length
+		`, 0},
+		{`
+		begin
+		  [1, 2, 3].pmap do |x|
+		    raise(ArgumentError, "Bad " + x.to_s) if x == 2
+		    x
+		  end
+		rescue ArgumentError => e
+		  e.message
+		end
+		`, "Bad 2"},
+		{`
+		begin
+		  [1, 2, 3].pmap do |x|
+		    raise(ArgumentError, "Bad " + x.to_s) if x > 1
+		    x
+		  end
+		rescue ParallelError => e
+		  e.message
+		end
+		`, "2 errors occurred: [1] ArgumentError: Bad 2, [2] ArgumentError: Bad 3"},
+		{`
+		begin
+		  [1, 2].pmap do |x|
+		    raise(ArgumentError, "Bad " + x.to_s)
+		  end
+		rescue ParallelError => e
+		  e.cause.message
+		end
+		`, "Bad 1"},
+		{`
+		begin
+		  [1].pmap do |x|
+		    break x
+		  end
+		rescue LocalJumpError => e
+		  e.message
+		end
+		`, "Can't break out of a block running on another thread"},
+		{`
+		begin
+		  Timeout.timeout(0.05) do
+		    [1, 2].pmap do |x|
+		      sleep(1)
+		    end
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayPmapMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1].pmap`, "InternalError: Can't yield without a block", 1},
+		{`[1].pmap(1) do |x| x end`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`[1].pmap(workers: 0) do |x| x end`, "ArgumentError: Expect workers to be positive. got: 0", 1},
+		{`[1].pmap(workers: "1") do |x| x end`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`[1].pmap(size: 1) do |x| x end`, "ArgumentError: Unknown keyword: size", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+	}
+}
+
 func TestArrayPopMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1062,3 +1279,149 @@ func TestArrayShiftMethodFail(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestArraySortMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`["b", "c", "a"].sort.to_s`, `["a", "b", "c"]`},
+		{`[3, 1, 2].sort.to_s`, "[1, 2, 3]"},
+		{`["🍺", "一", "🍣"].sort.to_s`, `["一", "🍣", "🍺"]`},
+		{`[].sort.to_s`, "[]"},
+		{`
+		a = [3, 1, 2]
+		a.sort
+		a.to_s
+		`, "[3, 1, 2]"},
+		{`
+		a = [3, 1, 2].sort do |x, y|
+		  y <=> x
+		end
+		a.to_s
+		`, "[3, 2, 1]"},
+		{`
+		a = ["bb", "a", "ccc"].sort do |x, y|
+		  y.length <=> x.length
+		end
+		a.to_s
+		`, `["ccc", "bb", "a"]`},
+		{`
+		a = [1].sort do |x, y|
+		  y <=> x
+		end
+		a.to_s
+		`, "[1]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArraySortMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`["b", 1].sort`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`[nil, nil].sort`, "UndefinedMethodError: Undefined Method '<=>' for nil", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayTallyMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`["a", "b", "a", "c", "a"].tally["a"]`, 3},
+		{`["a", "b", "a", "c", "a"].tally["c"]`, 1},
+		{`["a", "b", "a", "c", "a"].tally.length`, 3},
+		{`[1, 1, 2].tally[1]`, 2},
+		{`[1, 1, 2].tally["1"]`, nil},
+		{`["1", 1].tally["1"]`, 1},
+		{`["1", 1].tally[1]`, 1},
+		{`["1", 1].tally.length`, 2},
+		{`[[1], [1], 1].tally[[1]]`, 2},
+		{`[true, nil, true].tally[nil]`, 1},
+		{`[true, nil, true].tally.length`, 2},
+		{`[].tally.length`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayTallyMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].tally(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayToHashMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[["a", 1], ["b", 2]].to_h["b"]`, 2},
+		{`[["a", 1], ["b", 2], ["a", 3]].to_h["a"]`, 3},
+		{`[["a", 1], ["b", 2]].to_h.length`, 2},
+		{`[].to_h.length`, 0},
+		{`{ a: 1, b: "2" }.to_a.to_h["b"]`, "2"},
+		{`[[1, 2]].to_h[1]`, 2},
+		{`[[1, 2], ["1", 3]].to_h.length`, 2},
+		{`[[[1], 2], [[1], 3]].to_h[[1]]`, 3},
+		{`
+		h = ["a", "b"].to_h do |s|
+			[s, s + s]
+		end
+		h["b"]
+		`, "bb"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestArrayToHashMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[["a", 1]].to_h(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`[1, 2].to_h`, "TypeError: Expect argument to be Array. got: Integer", 1},
+		{`[["a", 1, 2]].to_h`, "ArgumentError: Expect element to be a pair of key and value. got=[\"a\", 1, 2]", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
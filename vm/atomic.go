@@ -0,0 +1,274 @@
+package vm
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// AtomicObject is an Integer that can be updated by many threads without locks, it's backed by golang's
+// `sync/atomic`.
+//
+// ```ruby
+// count = Atomic.new(0)
+// wg = WaitGroup.new
+//
+// 100.times do
+//   wg.add
+//   thread do
+//     count.increment
+//     wg.done
+//   end
+// end
+//
+// wg.wait
+// count.get # => 100
+// ```
+type AtomicObject struct {
+	// value is the first field to keep it 64-bit aligned on 32-bit platforms, which sync/atomic requires
+	value int64
+	*baseObj
+}
+
+// Class methods --------------------------------------------------------
+func builtinAtomicClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new Atomic with the given value, which is 0 by default.
+			//
+			// ```ruby
+			// Atomic.new     # => #<Atomic: 0>
+			// Atomic.new(10) # => #<Atomic: 10>
+			// ```
+			//
+			// @param value [Integer]
+			// @return [Atomic]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					switch len(args) {
+					case 0:
+						return t.vm.initAtomicObject(0)
+					case 1:
+						i, ok := args[0].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						return t.vm.initAtomicObject(int64(i.value))
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinAtomicInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Sets the value to the new one if it's equal to the expected one, and returns true if it's set.
+			//
+			// ```ruby
+			// a = Atomic.new(1)
+			// a.compare_and_set(1, 2) # => true
+			// a.compare_and_set(1, 3) # => false
+			// a.get                   # => 2
+			// ```
+			//
+			// @param expected [Integer]
+			// @param new [Integer]
+			// @return [Boolean]
+			Name: "compare_and_set",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					expected, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					newValue, ok := args[1].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+					}
+
+					a := receiver.(*AtomicObject)
+
+					return toBooleanObject(atomic.CompareAndSwapInt64(&a.value, int64(expected.value), int64(newValue.value)))
+				}
+			},
+		},
+		{
+			// Subtracts the given number, which is 1 by default, and returns the new value.
+			//
+			// ```ruby
+			// a = Atomic.new(10)
+			// a.decrement    # => 9
+			// a.decrement(4) # => 5
+			// ```
+			//
+			// @param delta [Integer]
+			// @return [Integer]
+			Name: "decrement",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					delta, err := t.atomicDelta(args)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initIntegerObject(int(atomic.AddInt64(&receiver.(*AtomicObject).value, -delta)))
+				}
+			},
+		},
+		{
+			// Returns the current value.
+			//
+			// ```ruby
+			// Atomic.new(3).get # => 3
+			// ```
+			//
+			// @return [Integer]
+			Name: "get",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initIntegerObject(int(atomic.LoadInt64(&receiver.(*AtomicObject).value)))
+				}
+			},
+		},
+		{
+			// Adds the given number, which is 1 by default, and returns the new value.
+			//
+			// ```ruby
+			// a = Atomic.new
+			// a.increment    # => 1
+			// a.increment(5) # => 6
+			// ```
+			//
+			// @param delta [Integer]
+			// @return [Integer]
+			Name: "increment",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					delta, err := t.atomicDelta(args)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initIntegerObject(int(atomic.AddInt64(&receiver.(*AtomicObject).value, delta)))
+				}
+			},
+		},
+		{
+			// Sets the value and returns it.
+			//
+			// ```ruby
+			// a = Atomic.new
+			// a.set(10) # => 10
+			// ```
+			//
+			// @param value [Integer]
+			// @return [Integer]
+			Name: "set",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					i, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					atomic.StoreInt64(&receiver.(*AtomicObject).value, int64(i.value))
+
+					return i
+				}
+			},
+		},
+		{
+			// Returns the atomic's string representation.
+			//
+			// ```ruby
+			// Atomic.new(1).to_s # => "#<Atomic: 1>"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initAtomicObject(value int64) *AtomicObject {
+	return &AtomicObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.AtomicClass)},
+		value:   value,
+	}
+}
+
+func (vm *VM) initAtomicClass() *RClass {
+	class := vm.initializeClass(classes.AtomicClass, false)
+	class.setBuiltinMethods(builtinAtomicClassMethods(), true)
+	class.setBuiltinMethods(builtinAtomicInstanceMethods(), false)
+	return class
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (a *AtomicObject) toString() string {
+	return fmt.Sprintf("#<Atomic: %d>", atomic.LoadInt64(&a.value))
+}
+
+// Alias of toString
+func (a *AtomicObject) toJSON() string {
+	return fmt.Sprintf("%d", atomic.LoadInt64(&a.value))
+}
+
+// Other helper functions -----------------------------------------------
+
+// atomicDelta returns the argument of Atomic#increment and Atomic#decrement, which is 1 by default
+func (t *thread) atomicDelta(args []Object) (int64, *Error) {
+	switch len(args) {
+	case 0:
+		return 1, nil
+	case 1:
+		i, ok := args[0].(*IntegerObject)
+
+		if !ok {
+			return 0, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		return int64(i.value), nil
+	default:
+		return 0, t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+	}
+}
@@ -0,0 +1,734 @@
+package vm
+
+import (
+	"math/big"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+const (
+	maxInt = int(^uint(0) >> 1)
+	minInt = -maxInt - 1
+)
+
+// BigIntObject represents integers that are too large for Integer, which is a 64-bit signed integer.
+// Integer arithmetic is promoted to BigInt automatically when the result overflows, and BigInt results
+// are converted back to Integer when they fit, so the two classes can be used together seamlessly.
+//
+// ```ruby
+// 9223372036854775807 + 1             # => 9223372036854775808
+// (9223372036854775807 + 1).class     # => BigInt
+// (9223372036854775807 + 1 - 1).class # => Integer
+// 2 ** 100                            # => 1267650600228229401496703205376
+// ```
+//
+// Integer literals that are too large for Integer and `String#to_i` also return BigInt.
+//
+// **Note:**
+//
+// - BigInt includes Comparable, so `<`, `<=`, `>` and `>=` are based on `#<=>`.
+// - Methods of other classes that expect an Integer argument, like `Array#[]`, don't accept BigInt.
+// - `BigInt.new` is not supported.
+type BigIntObject struct {
+	*baseObj
+	value *big.Int
+}
+
+// Class methods --------------------------------------------------------
+func builtinBigIntClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinBigIntInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the sum of self and an Integer or a BigInt.
+			//
+			// ```ruby
+			// 9223372036854775807 + 9223372036854775807 # => 18446744073709551614
+			// ```
+			//
+			// @return [Integer]
+			Name: "+",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bigIntOperation(t, receiver, args, "+")
+				}
+			},
+		},
+		{
+			// Returns the remainder of self divided by an Integer or a BigInt.
+			//
+			// ```ruby
+			// 18446744073709551615 % 10 # => 5
+			// ```
+			//
+			// @return [Integer]
+			Name: "%",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bigIntOperation(t, receiver, args, "%")
+				}
+			},
+		},
+		{
+			// Returns the subtraction of an Integer or a BigInt from self.
+			//
+			// ```ruby
+			// 18446744073709551616 - 1 # => 18446744073709551615
+			// ```
+			//
+			// @return [Integer]
+			Name: "-",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bigIntOperation(t, receiver, args, "-")
+				}
+			},
+		},
+		{
+			// Returns the negation of self, which is what unary minus calls.
+			//
+			// ```ruby
+			// -18446744073709551616 # => -18446744073709551616
+			// ```
+			//
+			// @return [Integer]
+			Name: "-@",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerFromBigInt(new(big.Int).Neg(receiver.(*BigIntObject).value))
+				}
+			},
+		},
+		{
+			// Returns self multiplied by an Integer or a BigInt.
+			//
+			// ```ruby
+			// 18446744073709551616 * 2 # => 36893488147419103232
+			// ```
+			//
+			// @return [Integer]
+			Name: "*",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bigIntOperation(t, receiver, args, "*")
+				}
+			},
+		},
+		{
+			// Returns self raised to the power of an Integer. Negative exponents result in 0.
+			//
+			// ```ruby
+			// 18446744073709551616 ** 2 # => 340282366920938463463374607431768211456
+			// ```
+			//
+			// @return [Integer]
+			Name: "**",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					switch args[0].(type) {
+					case *FloatObject, *RationalObject:
+						return floatOperation(t, receiver, args, "**")
+					}
+
+					e := args[0]
+					exponent, ok := e.(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, e.Class().Name)
+					}
+
+					if exponent.value < 0 {
+						return t.vm.initIntegerObject(0)
+					}
+
+					base := receiver.(*BigIntObject).value
+					return t.vm.initIntegerFromBigInt(new(big.Int).Exp(base, big.NewInt(int64(exponent.value)), nil))
+				}
+			},
+		},
+		{
+			// Returns self divided by an Integer or a BigInt, the result is truncated toward zero.
+			//
+			// ```ruby
+			// 18446744073709551616 / 2 # => 9223372036854775808
+			// ```
+			//
+			// @return [Integer]
+			Name: "/",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bigIntOperation(t, receiver, args, "/")
+				}
+			},
+		},
+		{
+			// Returns the bitwise AND of self and an Integer.
+			//
+			// ```ruby
+			// 18446744073709551617 & 3 # => 1
+			// ```
+			// @return [Integer]
+			Name: "&",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "&")
+				}
+			},
+		},
+		{
+			// Returns the bitwise OR of self and an Integer.
+			//
+			// ```ruby
+			// 18446744073709551616 | 1 # => 18446744073709551617
+			// ```
+			// @return [Integer]
+			Name: "|",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "|")
+				}
+			},
+		},
+		{
+			// Returns the bitwise exclusive OR of self and an Integer.
+			//
+			// ```ruby
+			// 18446744073709551617 ^ 1 # => 18446744073709551616
+			// ```
+			// @return [Integer]
+			Name: "^",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "^")
+				}
+			},
+		},
+		{
+			// Returns the bitwise complement of self, which is -self - 1.
+			//
+			// ```ruby
+			// ~18446744073709551616 # => -18446744073709551617
+			// ```
+			// @return [Integer]
+			Name: "~",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "~")
+				}
+			},
+		},
+		{
+			// Returns self shifted left by the given number of bits, or right if it's negative.
+			//
+			// ```ruby
+			// 18446744073709551616 << 1 # => 36893488147419103232
+			// ```
+			// @return [Integer]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "<<")
+				}
+			},
+		},
+		{
+			// Returns self shifted right by the given number of bits, or left if it's negative.
+			//
+			// ```ruby
+			// 18446744073709551616 >> 1 # => 9223372036854775808
+			// ```
+			// @return [Integer]
+			Name: ">>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, ">>")
+				}
+			},
+		},
+		{
+			// Returns 1 if self is larger than the argument, -1 if smaller. Otherwise 0.
+			//
+			// ```ruby
+			// 18446744073709551616 <=> 1                    # => 1
+			// 18446744073709551616 <=> 18446744073709551616 # => 0
+			// ```
+			//
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					c, ok := compareNumbers(receiver, args[0])
+
+					if !ok {
+						return NULL
+					}
+
+					return t.vm.initIntegerObject(c)
+				}
+			},
+		},
+		{
+			// Returns if self is equal to an Integer or a BigInt.
+			//
+			// ```ruby
+			// 18446744073709551616 == 18446744073709551616 # => true
+			// 18446744073709551616 == 1                    # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c, ok := compareNumbers(receiver, args[0])
+
+					if ok && c == 0 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns if self is not equal to the argument.
+			//
+			// ```ruby
+			// 18446744073709551616 != 1 # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "!=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c, ok := compareNumbers(receiver, args[0])
+
+					if ok && c == 0 {
+						return FALSE
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns the digits of self in the given base, which defaults to 10, from the least significant one.
+			// Returns a DomainError if self is negative.
+			//
+			// ```ruby
+			// 18446744073709551616.digits(1000) # => [616, 551, 709, 73, 744, 446, 18]
+			// ```
+			//
+			// @return [Array]
+			Name: "digits",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerDigits(t, receiver, args)
+				}
+			},
+		},
+		{
+			// Returns if self is even.
+			//
+			// ```ruby
+			// 18446744073709551616.even? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "even?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if receiver.(*BigIntObject).value.Bit(0) == 0 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns the greatest common divisor of self and an Integer, which is always positive.
+			//
+			// ```ruby
+			// 18446744073709551616.gcd(24) # => 8
+			// ```
+			//
+			// @return [Integer]
+			Name: "gcd",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerGcd(t, receiver, args, false)
+				}
+			},
+		},
+		{
+			// Returns the least common multiple of self and an Integer, which is always positive.
+			//
+			// ```ruby
+			// 18446744073709551616.lcm(3) # => 55340232221128654848
+			// ```
+			//
+			// @return [Integer]
+			Name: "lcm",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerGcd(t, receiver, args, true)
+				}
+			},
+		},
+		{
+			// Returns if self is odd.
+			//
+			// ```ruby
+			// 18446744073709551617.odd? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "odd?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if receiver.(*BigIntObject).value.Bit(0) == 1 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns self raised to the power of e, like `**`.
+			// With a modulus m it returns `(self ** e) % m` without calculating the full power, which
+			// keeps modular exponentiation fast for large numbers. The result has the same sign as m.
+			//
+			// ```ruby
+			// 18446744073709551616.pow(2, 1000) # => 456
+			// ```
+			//
+			// @return [Integer]
+			Name: "pow",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerPow(t, receiver, args)
+				}
+			},
+		},
+		{
+			// Returns self converted to a Float, which may lose precision.
+			//
+			// ```ruby
+			// 18446744073709551616.to_f # => 1.8446744073709552e+19
+			// ```
+			//
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f, _ := toFloat64(receiver)
+					return t.vm.initFloatObject(f)
+				}
+			},
+		},
+		{
+			// Returns self as a Rational.
+			//
+			// ```ruby
+			// 18446744073709551616.to_r # => 18446744073709551616/1
+			// ```
+			//
+			// @return [Rational]
+			Name: "to_r",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					r, _ := toBigRat(receiver)
+					return t.vm.initRationalObject(r)
+				}
+			},
+		},
+		{
+			// Returns self.
+			//
+			// ```ruby
+			// 18446744073709551616.to_i # => 18446744073709551616
+			// ```
+			//
+			// @return [BigInt]
+			Name: "to_i",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns a String representation of self in the given base, which defaults to 10 and must be
+			// between 2 and 36.
+			//
+			// ```ruby
+			// 18446744073709551616.to_s     # => "18446744073709551616"
+			// 18446744073709551616.to_s(16) # => "10000000000000000"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					base, err := parseRadix(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(receiver.(*BigIntObject).value.Text(base))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+// initIntegerFromBigInt returns an Integer if n fits in Integer, or a BigInt otherwise
+func (vm *VM) initIntegerFromBigInt(n *big.Int) Object {
+	if n.IsInt64() {
+		if v := n.Int64(); int64(int(v)) == v {
+			return vm.initIntegerObject(int(v))
+		}
+	}
+
+	return &BigIntObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.BigIntClass), frozen: true},
+		value:   n,
+	}
+}
+
+func (vm *VM) initBigIntClass() *RClass {
+	bc := vm.initializeClass(classes.BigIntClass, false)
+	bc.setBuiltinMethods(builtinBigIntInstanceMethods(), false)
+	bc.setBuiltinMethods(builtinBigIntClassMethods(), true)
+	bc.include(vm.topLevelClass(classes.ComparableModule))
+	return bc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (b *BigIntObject) Value() interface{} {
+	return b.value
+}
+
+// Returns the decimal representation of the integer
+func (b *BigIntObject) toString() string {
+	return b.value.String()
+}
+
+// Alias of toString
+func (b *BigIntObject) toJSON() string {
+	return b.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// toBigInt returns the value of an Integer or a BigInt as a big.Int, ok is false for other objects
+func toBigInt(obj Object) (*big.Int, bool) {
+	switch obj := obj.(type) {
+	case *IntegerObject:
+		return big.NewInt(int64(obj.value)), true
+	case *BigIntObject:
+		return obj.value, true
+	default:
+		return nil, false
+	}
+}
+
+// compareIntegers compares two Integers or BigInts and returns -1, 0 or 1, ok is false if either of them
+// isn't an Integer or a BigInt
+func compareIntegers(left, right Object) (int, bool) {
+	if l, ok := left.(*IntegerObject); ok {
+		if r, ok := right.(*IntegerObject); ok {
+			switch {
+			case l.value < r.value:
+				return -1, true
+			case l.value > r.value:
+				return 1, true
+			default:
+				return 0, true
+			}
+		}
+	}
+
+	l, ok := toBigInt(left)
+
+	if !ok {
+		return 0, false
+	}
+
+	r, ok := toBigInt(right)
+
+	if !ok {
+		return 0, false
+	}
+
+	return l.Cmp(r), true
+}
+
+// bigIntOperation applies the arithmetic operator to the receiver and the argument as big.Ints, the result is
+// converted to Integer if it fits. Float and Rational arguments are handed to floatOperation and
+// rationalOperation instead.
+func bigIntOperation(t *thread, receiver Object, args []Object, operator string) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	switch args[0].(type) {
+	case *FloatObject:
+		return floatOperation(t, receiver, args, operator)
+	case *RationalObject:
+		return rationalOperation(t, receiver, args, operator)
+	}
+
+	left, _ := toBigInt(receiver)
+	right, ok := toBigInt(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	result := new(big.Int)
+
+	switch operator {
+	case "+":
+		result.Add(left, right)
+	case "-":
+		result.Sub(left, right)
+	case "*":
+		result.Mul(left, right)
+	case "/", "%":
+		if right.Sign() == 0 {
+			return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+		}
+
+		if operator == "/" {
+			result.Quo(left, right)
+		} else {
+			result.Rem(left, right)
+		}
+	}
+
+	return t.vm.initIntegerFromBigInt(result)
+}
+
+// bitwiseOperation applies the bitwise operator to the receiver and the Integer or BigInt argument,
+// operations that overflow Integer are done with big.Ints
+func bitwiseOperation(t *thread, receiver Object, args []Object, operator string) Object {
+	if operator == "~" {
+		if len(args) != 0 {
+			return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+		}
+
+		if i, ok := receiver.(*IntegerObject); ok {
+			return t.vm.initIntegerObject(^i.value)
+		}
+
+		return t.vm.initIntegerFromBigInt(new(big.Int).Not(receiver.(*BigIntObject).value))
+	}
+
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	right, ok := toBigInt(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	leftInt, leftIsInt := receiver.(*IntegerObject)
+	rightInt, rightIsInt := args[0].(*IntegerObject)
+
+	if operator == "<<" || operator == ">>" {
+		if !rightIsInt {
+			return t.initErrorObject(errors.ArgumentError, "Shift width too big: %s", right.String())
+		}
+
+		shift := rightInt.value
+
+		if operator == ">>" {
+			shift = -shift
+		}
+
+		if leftIsInt && shift < 0 {
+			if shift <= -63 {
+				if leftInt.value < 0 {
+					return t.vm.initIntegerObject(-1)
+				}
+
+				return t.vm.initIntegerObject(0)
+			}
+
+			return t.vm.initIntegerObject(leftInt.value >> uint(-shift))
+		}
+
+		if leftIsInt && shift < 63 && (leftInt.value<<uint(shift))>>uint(shift) == leftInt.value {
+			return t.vm.initIntegerObject(leftInt.value << uint(shift))
+		}
+
+		left, _ := toBigInt(receiver)
+
+		if shift < 0 {
+			return t.vm.initIntegerFromBigInt(new(big.Int).Rsh(left, uint(-shift)))
+		}
+
+		return t.vm.initIntegerFromBigInt(new(big.Int).Lsh(left, uint(shift)))
+	}
+
+	if leftIsInt && rightIsInt {
+		switch operator {
+		case "&":
+			return t.vm.initIntegerObject(leftInt.value & rightInt.value)
+		case "|":
+			return t.vm.initIntegerObject(leftInt.value | rightInt.value)
+		case "^":
+			return t.vm.initIntegerObject(leftInt.value ^ rightInt.value)
+		}
+	}
+
+	left, _ := toBigInt(receiver)
+	result := new(big.Int)
+
+	switch operator {
+	case "&":
+		result.And(left, right)
+	case "|":
+		result.Or(left, right)
+	case "^":
+		result.Xor(left, right)
+	}
+
+	return t.vm.initIntegerFromBigInt(result)
+}
@@ -0,0 +1,211 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestBigIntPromotion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(9223372036854775807 + 1).class.name`, "BigInt"},
+		{`(9223372036854775807 + 1).to_s`, "9223372036854775808"},
+		{`(-9223372036854775807 - 2).to_s`, "-9223372036854775809"},
+		{`(4294967296 * 4294967296).to_s`, "18446744073709551616"},
+		{`((-9223372036854775807 - 1) / -1).to_s`, "9223372036854775808"},
+		{`(2 ** 100).to_s`, "1267650600228229401496703205376"},
+		{`(9223372036854775807 + 1 - 1).class.name`, "Integer"},
+		{`9223372036854775807 + 1 - 1`, 9223372036854775807},
+		{`(2 ** 64) / (2 ** 60)`, 16},
+		{`2 ** 62`, 4611686018427387904},
+		{`
+		def fact(n)
+		  r = 1
+		  n.times do |i|
+		    r = r * (i + 1)
+		  end
+		  r
+		end
+
+		fact(30).to_s
+		`, "265252859812191058636308480000000"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`18446744073709551616.class.name`, "BigInt"},
+		{`18446744073709551616.to_s`, "18446744073709551616"},
+		{`(-18446744073709551616).to_s`, "-18446744073709551616"},
+		{`0xffffffffffffffffff.to_s(16)`, "ffffffffffffffffff"},
+		{`0b10000000000000000000000000000000000000000000000000000000000000000.to_s`, "18446744073709551616"},
+		{`0o4000000000000000000000.to_s`, "36893488147419103232"},
+		{`"123456789012345678901234567890".to_i.to_s`, "123456789012345678901234567890"},
+		{`"-ffffffffffffffffff".hex.to_s`, "-4722366482869645213695"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntArithmeticOperation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(18446744073709551616 + 18446744073709551616).to_s`, "36893488147419103232"},
+		{`(18446744073709551616 - 1).to_s`, "18446744073709551615"},
+		{`(18446744073709551616 * 2).to_s`, "36893488147419103232"},
+		{`(18446744073709551616 / 3).to_s`, "6148914691236517205"},
+		{`(-18446744073709551616 / 3).to_s`, "-6148914691236517205"},
+		{`18446744073709551616 % 7`, 2},
+		{`(18446744073709551616 ** 2).to_s`, "340282366920938463463374607431768211456"},
+		{`18446744073709551616 ** -1`, 0},
+		{`(1 + 18446744073709551616).to_s`, "18446744073709551617"},
+		{`(1 - 18446744073709551616).to_s`, "-18446744073709551615"},
+		{`(2 * 18446744073709551616).to_s`, "36893488147419103232"},
+		{`100 / 18446744073709551616`, 0},
+		{`100 % 18446744073709551616`, 100},
+		{`18446744073709551616 - 18446744073709551615`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntArithmeticOperationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`18446744073709551616 + "p"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`18446744073709551616 ** "p"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`18446744073709551616 / 0`, "ZeroDivisionError: Divided by 0", 1},
+		{`18446744073709551616 % 0`, "ZeroDivisionError: Divided by 0", 1},
+		{`1 / (18446744073709551616 - 18446744073709551616)`, "ZeroDivisionError: Divided by 0", 1},
+		{`BigInt.new`, "UnsupportedMethodError: Unsupported Method #new for BigInt", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntBitwiseOperation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`18446744073709551617 & 3`, 1},
+		{`(18446744073709551616 | 1).to_s`, "18446744073709551617"},
+		{`(18446744073709551617 ^ 1).to_s`, "18446744073709551616"},
+		{`(~18446744073709551616).to_s`, "-18446744073709551617"},
+		{`(18446744073709551616 << 1).to_s`, "36893488147419103232"},
+		{`(18446744073709551616 >> 1).to_s`, "9223372036854775808"},
+		{`18446744073709551616 >> 64`, 1},
+		{`(18446744073709551616 & 18446744073709551617).to_s`, "18446744073709551616"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntNumberTheoryMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`18446744073709551616.gcd(24)`, 8},
+		{`(18446744073709551616.lcm(3)).to_s`, "55340232221128654848"},
+		{`18446744073709551616.digits(1000).to_s`, "[616, 551, 709, 73, 744, 446, 18]"},
+		{`18446744073709551616.pow(2, 1000)`, 456},
+		{`(18446744073709551616.pow(2)).to_s`, "340282366920938463463374607431768211456"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`18446744073709551616 > 1`, true},
+		{`18446744073709551616 < 1`, false},
+		{`18446744073709551616 >= 18446744073709551616`, true},
+		{`18446744073709551616 <= 18446744073709551615`, false},
+		{`1 < 18446744073709551616`, true},
+		{`1 > -18446744073709551616`, true},
+		{`18446744073709551616 <=> 18446744073709551617`, -1},
+		{`18446744073709551616 <=> 18446744073709551616`, 0},
+		{`1 <=> 18446744073709551616`, -1},
+		{`18446744073709551616 == 18446744073709551616`, true},
+		{`18446744073709551616 == 1`, false},
+		{`18446744073709551616 == "1"`, false},
+		{`18446744073709551616 != 1`, true},
+		{`1 == 18446744073709551616`, false},
+		{`2 ** 64 == 18446744073709551616`, true},
+		{`18446744073709551616.even?`, true},
+		{`18446744073709551617.odd?`, true},
+		{`(2 ** 64).to_s(2).length`, 65},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestBigIntComparisonFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`18446744073709551616 > "1"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1 < "1"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`18446744073709551616 <=> "1"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`18446744073709551616.to_s(99)`, "ArgumentError: Invalid radix: 99", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -111,12 +111,20 @@ func (vm *VM) initBoolClass() *RClass {
 	b.setBuiltinMethods(builtinBooleanInstanceMethods(), false)
 	b.setBuiltinMethods(builtinBooleanClassMethods(), true)
 
-	TRUE = &BooleanObject{value: true, baseObj: &baseObj{class: b}}
-	FALSE = &BooleanObject{value: false, baseObj: &baseObj{class: b}}
+	TRUE = &BooleanObject{value: true, baseObj: &baseObj{class: b, frozen: true}}
+	FALSE = &BooleanObject{value: false, baseObj: &baseObj{class: b, frozen: true}}
 
 	return b
 }
 
+// toBooleanObject returns the shared boolean object of the value
+func toBooleanObject(value bool) *BooleanObject {
+	if value {
+		return TRUE
+	}
+	return FALSE
+}
+
 // Polymorphic helper functions -----------------------------------------
 
 // Returns the object
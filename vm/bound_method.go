@@ -0,0 +1,209 @@
+package vm
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/goby-lang/goby/vm/classes"
+)
+
+// BoundMethodObject represents a method bound to its receiver, which is returned by `Object#method`.
+// It can be called later like a proc, even if the method is private or redefined.
+//
+// ```ruby
+// class Greeter
+//   def hello(name)
+//     "Hello, " + name
+//   end
+// end
+//
+// m = Greeter.new.method(:hello)
+// m.call("Goby") # => "Hello, Goby"
+// m.arity        # => 1
+// m.owner        # => Greeter
+// ```
+//
+// **Note:**
+//
+// - `Method.new` is not supported.
+type BoundMethodObject struct {
+	*baseObj
+	name string
+	// owner is the class or module that defines the method
+	owner    *RClass
+	method   Object
+	receiver Object
+}
+
+// Class methods --------------------------------------------------------
+func builtinBoundMethodClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinBoundMethodInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the number of the method's required parameters. If the method also has optional or splat
+			// parameters, it returns -n-1, where n is the number of the required ones. Required keyword
+			// parameters count as one parameter. Builtin methods return -1.
+			//
+			// ```ruby
+			// def foo(a, b = 1); end
+			//
+			// method(:foo).arity   # => -2
+			// 1.method(:+).arity   # => -1
+			// ```
+			//
+			// @return [Integer]
+			Name: "arity",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(methodArity(receiver.(*BoundMethodObject).method))
+				}
+			},
+		},
+		{
+			// Calls the method on its receiver with the given arguments and block.
+			//
+			// ```ruby
+			// m = [1, 2].method(:map)
+			// m.call do |i|
+			//   i * 2
+			// end # => [2, 4]
+			//
+			// 2.method(:+).call(3) # => 5
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Object]
+			Name: "call",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*BoundMethodObject)
+					return t.callMethod(m.method, m.receiver, blockFrame, args...)
+				}
+			},
+		},
+		{
+			// Returns the name of the method.
+			//
+			// ```ruby
+			// 1.method(:+).name # => :+
+			// ```
+			//
+			// @return [Symbol]
+			Name: "name",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initSymbolObject(receiver.(*BoundMethodObject).name)
+				}
+			},
+		},
+		{
+			// Returns the class or module that defines the method.
+			//
+			// ```ruby
+			// "Goby".method(:to_s).owner # => String
+			// "Goby".method(:tap).owner  # => Object
+			// ```
+			//
+			// @return [Class]
+			Name: "owner",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver.(*BoundMethodObject).owner
+				}
+			},
+		},
+		{
+			// Returns the object the method is bound to.
+			//
+			// ```ruby
+			// "Goby".method(:size).receiver # => "Goby"
+			// ```
+			//
+			// @return [Object]
+			Name: "receiver",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver.(*BoundMethodObject).receiver
+				}
+			},
+		},
+		{
+			// Returns the string representation of the method with its owner.
+			//
+			// ```ruby
+			// [].method(:push).to_s # => "#<Method: Array#push>"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+		{
+			// Returns the method as an UnboundMethod, which can be bound to another object of the owner.
+			//
+			// ```ruby
+			// um = "a".method(:upcase).unbind
+			// um.bind("b").call # => "B"
+			// ```
+			//
+			// @return [UnboundMethod]
+			Name: "unbind",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*BoundMethodObject)
+					return t.vm.initUnboundMethodObject(m.name, m.owner, m.method)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initBoundMethodObject(name string, owner *RClass, method Object, receiver Object) *BoundMethodObject {
+	return &BoundMethodObject{
+		baseObj:  &baseObj{class: vm.topLevelClass(classes.BoundMethodClass)},
+		name:     name,
+		owner:    owner,
+		method:   method,
+		receiver: receiver,
+	}
+}
+
+func (vm *VM) initBoundMethodClass() *RClass {
+	mc := vm.initializeClass(classes.BoundMethodClass, false)
+	mc.setBuiltinMethods(builtinBoundMethodInstanceMethods(), false)
+	mc.setBuiltinMethods(builtinBoundMethodClassMethods(), true)
+	return mc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the method's owner and name
+func (bm *BoundMethodObject) toString() string {
+	return fmt.Sprintf("#<Method: %s#%s>", bm.owner.Name, bm.name)
+}
+
+// Returns the method's string representation as a JSON string
+func (bm *BoundMethodObject) toJSON() string {
+	return strconv.Quote(bm.toString())
+}
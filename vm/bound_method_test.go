@@ -0,0 +1,174 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestMethodObject(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo
+		  def add(a, b)
+		    a + b
+		  end
+		end
+
+		Foo.new.method(:add).call(1, 2)
+		`, 3},
+		{`[1, 2].method("push").call(3).to_s`, "[1, 2, 3]"},
+		{`
+		class Foo
+		  def twice
+		    yield + yield
+		  end
+		end
+
+		Foo.new.method(:twice).call do
+		  1
+		end
+		`, 2},
+		{`
+		class Foo
+		  private
+
+		  def secret
+		    42
+		  end
+		end
+
+		Foo.new.method(:secret).call
+		`, 42},
+		{`
+		class Foo
+		  def a; end
+		  def b(x, y); end
+		  def c(x, y = 1); end
+		  def d(*x); end
+		  def e(x, y:); end
+		  def f(x, y: 1); end
+		end
+
+		f = Foo.new
+		[f.method(:a).arity, f.method(:b).arity, f.method(:c).arity, f.method(:d).arity, f.method(:e).arity, f.method(:f).arity].to_s
+		`, "[0, 2, -2, -1, 2, -2]"},
+		{`1.method("+").arity`, -1},
+		{`5.method(:+).call(2)`, 7},
+		{`[1, 2].method(:[]).call(0)`, 1},
+		{`5.method(:<=>).name.to_s`, "<=>"},
+		{`
+		module Greet
+		  def hi; end
+		end
+
+		class Foo
+		  include Greet
+		end
+
+		Foo.new.method(:hi).owner.name
+		`, "Greet"},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		f = Foo.new
+		f.method(:bar).receiver == f
+		`, true},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		Foo.new.method(:bar).to_s
+		`, "#<Method: Foo#bar>"},
+		{`1.method(:to_s).name.to_s`, "to_s"},
+		{`
+		class Foo
+		  def self.bar
+		    10
+		  end
+		end
+
+		Foo.method(:bar).call
+		`, 10},
+		{`
+		class Foo
+		  def name
+		    "foo"
+		  end
+		end
+
+		class Bar < Foo
+		  def name
+		    "bar"
+		  end
+		end
+
+		Foo.new.method(:name).unbind.bind(Bar.new).call
+		`, "foo"},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		Foo.new.method(:bar).unbind.to_s
+		`, "#<UnboundMethod: Foo#bar>"},
+		{`
+		module Greet
+		  def hi
+		    "hi " + to_s
+		  end
+		end
+
+		Greet.instance_method(:hi).bind(1).call
+		`, "hi 1"},
+		{`
+		class Foo
+		  def bar(x, y = 1); end
+		end
+
+		Foo.instance_method(:bar).arity
+		`, -2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMethodObjectFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.method`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`1.method(1)`, "TypeError: Expect method name to be String or Symbol. got: Integer", 1},
+		{`1.method(:foo)`, "UndefinedMethodError: Undefined Method 'foo' for 1", 1},
+		{`Method.new`, "UnsupportedMethodError: Unsupported Method #new for Method", 1},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		Foo.instance_method(:bar).bind(1)
+		`, "TypeError: Expect argument to be an instance of Foo. got: Integer", 6},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		Foo.instance_method(:bar).bind
+		`, "ArgumentError: Expect 1 argument. got: 0", 6},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
@@ -19,9 +19,96 @@ type callFrame struct {
 	lPr        int
 	isBlock    bool
 	blockFrame *callFrame
+	// isLambda marks the block frame of a lambda, `return` in the block returns from the lambda instead of the method
+	isLambda bool
+	// method is the Goby method the frame executes, `super` uses it to find the method in superclasses
+	method *MethodObject
+	// goBlock is the block's body when it's implemented in Go, see thread.iterate
+	goBlock func(t *thread, args []Object) Object
+	// rescue handlers registered by the begin expressions being executed, the innermost one is the last
+	rescues []*rescueHandler
 	sync.RWMutex
 }
 
+// rescueHandler is registered by `set_rescue` instruction, it records where to jump when an error is raised
+// and the state to restore before jumping
+type rescueHandler struct {
+	// start is the first instruction the handler covers, and handler is where the covered instructions end
+	start   int
+	handler int
+	sp      int
+	cfp     int
+	// rescuedErrors is the number of errors being handled by rescue clauses
+	rescuedErrors int
+	// ensure is true if the handler is an ensure clause, which also runs when `break` or `return` unwinds the frame
+	ensure bool
+}
+
+// covers returns true if the error raised by the instruction being executed should be rescued by the handler.
+// Jumps out of the begin expression remove the handler with `pop_rescue`, but it's also ignored when the frame
+// is executing an instruction out of the begin expression
+func (h *rescueHandler) covers(cf *callFrame) bool {
+	return h.start <= cf.pc-1 && cf.pc-1 < h.handler
+}
+
+// sourceLine returns the one-indexed source line of the instruction being executed
+func (cf *callFrame) sourceLine() int {
+	if cf.pc == 0 {
+		return 0
+	}
+
+	return cf.instructionSet.instructions[cf.pc-1].sourceLine + 1
+}
+
+// setRescue registers a handler, and removes the one left by previous execution of the same begin expression
+func (cf *callFrame) setRescue(h *rescueHandler) {
+	for i, r := range cf.rescues {
+		if r.handler == h.handler {
+			cf.rescues = cf.rescues[:i]
+			break
+		}
+	}
+
+	cf.rescues = append(cf.rescues, h)
+}
+
+// popRescue removes and returns the innermost handler that covers current instruction, or nil if there's none
+func (cf *callFrame) popRescue() *rescueHandler {
+	for len(cf.rescues) > 0 {
+		h := cf.rescues[len(cf.rescues)-1]
+		cf.rescues = cf.rescues[:len(cf.rescues)-1]
+
+		if h.covers(cf) {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// popEnsure removes the handlers that cover current instruction until the innermost one of an ensure clause, and
+// returns it or nil if there's none
+func (cf *callFrame) popEnsure() *rescueHandler {
+	for h := cf.popRescue(); h != nil; h = cf.popRescue() {
+		if h.ensure {
+			return h
+		}
+	}
+
+	return nil
+}
+
+// hasRescue returns true if the frame has a handler that covers current instruction
+func (cf *callFrame) hasRescue() bool {
+	for _, h := range cf.rescues {
+		if h.covers(cf) {
+			return true
+		}
+	}
+
+	return false
+}
+
 // We use lock on every local variable retrieval and insertion.
 // The main scenario is when multiple threads want to access local variables outside it's block
 // Since they share same block frame, they will all access to that frame's locals.
@@ -2,12 +2,33 @@ package vm
 
 import (
 	"fmt"
+	"reflect"
 	"sync"
+	"time"
 
 	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
 )
 
-// ChannelObject represents a goby channel, which carries a golang channel
+// ChannelObject represents a goby channel, which carries a golang channel.
+// A channel created with a capacity is buffered, so delivering to it doesn't block until the buffer is full.
+//
+// ```ruby
+// c = Channel.new(2)
+// c.send(1)
+// c.send(2)
+// c.close
+//
+// c.receive # => 1
+// c.receive # => 2
+// c.receive # => nil, the channel is closed
+// ```
+//
+// `Channel.select` waits on multiple channels and returns the first one that receives a value:
+//
+// ```ruby
+// ch, value = Channel.select(c1, c2, timeout: 1)
+// ```
 type ChannelObject struct {
 	*baseObj
 	Chan chan int
@@ -17,14 +38,144 @@ type ChannelObject struct {
 func builtinChannelClassMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
+			// Returns a new channel, which is buffered with the given capacity. The capacity is 0 by default,
+			// which means delivering blocks until the value is received.
+			//
+			// ```ruby
+			// Channel.new    # unbuffered
+			// Channel.new(5) # buffered
+			// ```
+			//
+			// @param capacity [Integer]
+			// @return [Channel]
 			Name: "new",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					c := &ChannelObject{baseObj: &baseObj{class: t.vm.topLevelClass(classes.ChannelClass)}, Chan: make(chan int)}
+					capacity := 0
+
+					switch len(args) {
+					case 0:
+					case 1:
+						i, ok := args[0].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						if i.value < 0 {
+							return t.initErrorObject(errors.ArgumentError, "Expect capacity to be positive. got: %d", i.value)
+						}
+
+						capacity = i.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					c := &ChannelObject{baseObj: &baseObj{class: t.vm.topLevelClass(classes.ChannelClass)}, Chan: make(chan int, capacity)}
 					return c
 				}
 			},
 		},
+		{
+			// Waits until one of the channels receives a value, and returns the channel and the value.
+			// Returns nil if the `timeout` (sec) passes first. A closed channel is returned with nil.
+			//
+			// ```ruby
+			// c1 = Channel.new
+			// c2 = Channel.new
+			//
+			// thread do
+			//   c2.send("hello")
+			// end
+			//
+			// ch, value = Channel.select(c1, c2)
+			// ch == c2 # => true
+			// value    # => "hello"
+			//
+			// Channel.select(c1, timeout: 0.1) # => nil
+			// ```
+			//
+			// @param channels [Channel]
+			// @param timeout [Integer/Float]
+			// @return [Array]
+			Name: "select",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					var timeout Object
+
+					if len(args) > 0 {
+						if h, ok := args[len(args)-1].(*HashObject); ok {
+							for k := range h.Pairs {
+								if k != "timeout" {
+									return t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+								}
+							}
+
+							timeout = h.Pairs["timeout"]
+							args = args[:len(args)-1]
+						}
+					}
+
+					if len(args) == 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 channel. got: 0")
+					}
+
+					cases := []reflect.SelectCase{}
+
+					for _, arg := range args {
+						c, ok := arg.(*ChannelObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ChannelClass, arg.Class().Name)
+						}
+
+						cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(c.Chan)})
+					}
+
+					if timeout != nil {
+						var seconds float64
+
+						switch sec := timeout.(type) {
+						case *IntegerObject:
+							seconds = float64(sec.value)
+						case *FloatObject:
+							seconds = sec.value
+						default:
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", timeout.Class().Name)
+						}
+
+						timer := time.After(time.Duration(seconds * float64(time.Second)))
+						cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(timer)})
+					}
+
+					// Stop waiting when the Timeout.timeout block that runs the select is expired
+					timedOutCase := -1
+
+					if done := t.timedOut(); done != nil {
+						timedOutCase = len(cases)
+						cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(done)})
+					}
+
+					chosen, received, ok := reflect.Select(cases)
+
+					if chosen == timedOutCase {
+						return t.timeoutError()
+					}
+
+					if chosen >= len(args) {
+						return NULL
+					}
+
+					var value Object = NULL
+
+					if ok {
+						value = t.vm.channelObjectMap.retrieveObj(int(received.Int()))
+					}
+
+					return t.vm.initArrayObject([]Object{args[chosen], value})
+				}
+			},
+		},
 	}
 }
 
@@ -32,43 +183,115 @@ func builtinChannelClassMethods() []*BuiltinMethodObject {
 func builtinChannelInstanceMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
+			// Returns the channel's capacity, which is 0 for an unbuffered channel.
+			//
+			// ```ruby
+			// Channel.new(3).capacity # => 3
+			// ```
+			//
+			// @return [Integer]
+			Name: "capacity",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(cap(receiver.(*ChannelObject).Chan))
+				}
+			},
+		},
+		{
+			// Closes the channel. Receiving from a closed channel returns nil once its buffer is empty, and
+			// delivering to it raises a ClosedChannelError.
+			//
+			// ```ruby
+			// c = Channel.new
+			// c.close
+			// c.receive # => nil
+			// ```
+			//
+			// @return [Null]
 			Name: "close",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					c := receiver.(*ChannelObject)
 
-					close(c.Chan)
+					if !c.close() {
+						return t.initErrorObject(errors.ClosedChannelError, "Can't close a closed channel")
+					}
 
 					return NULL
 				}
 			},
 		},
 		{
+			// Delivers the object to the channel, which blocks until it's received or buffered.
+			//
+			// ```ruby
+			// c = Channel.new
+			//
+			// thread do
+			//   c.deliver("hello")
+			// end
+			//
+			// c.receive # => "hello"
+			// ```
+			//
+			// @param object [Object]
+			// @return [Object] the delivered object
 			Name: "deliver",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					id := t.vm.channelObjectMap.storeObj(args[0])
-
-					c := receiver.(*ChannelObject)
-
-					c.Chan <- id
-
-					return args[0]
+					return t.deliverToChannel(receiver.(*ChannelObject), args)
 				}
 			},
 		},
 		{
+			// Waits until the channel receives an object and returns it. Returns nil if the channel is closed.
+			//
+			// ```ruby
+			// c = Channel.new(1)
+			// c.send(1)
+			// c.receive # => 1
+			// ```
+			//
+			// @return [Object]
 			Name: "receive",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					c := receiver.(*ChannelObject)
 
-					num := <-c.Chan
+					var num int
+					var ok bool
+
+					select {
+					case num, ok = <-c.Chan:
+					case <-t.timedOut():
+						return t.timeoutError()
+					}
+
+					if !ok {
+						return NULL
+					}
 
 					return t.vm.channelObjectMap.retrieveObj(num)
 				}
 			},
 		},
+		{
+			// Same as `deliver`.
+			//
+			// ```ruby
+			// c = Channel.new(1)
+			// c.send("hello")
+			// ```
+			//
+			// @param object [Object]
+			// @return [Object] the delivered object
+			Name: "send",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.deliverToChannel(receiver.(*ChannelObject), args)
+				}
+			},
+		},
 	}
 }
 
@@ -102,10 +325,51 @@ func (co *ChannelObject) toJSON() string {
 
 // Returns the duplicate of the Array object
 func (co *ChannelObject) copy() Object {
-	newC := &ChannelObject{baseObj: &baseObj{class: co.class}, Chan: make(chan int)}
+	newC := &ChannelObject{baseObj: &baseObj{class: co.class}, Chan: make(chan int, cap(co.Chan))}
 	return newC
 }
 
+// send delivers the object's id to the golang channel, it returns false if the channel is closed
+func (co *ChannelObject) send(id int) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	co.Chan <- id
+	return true
+}
+
+// close closes the golang channel, it returns false if the channel has been closed
+func (co *ChannelObject) close() (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	close(co.Chan)
+	return true
+}
+
+// Other helper functions -----------------------------------------------
+
+// deliverToChannel implements Channel#deliver and Channel#send
+func (t *thread) deliverToChannel(c *ChannelObject, args []Object) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	id := t.vm.channelObjectMap.storeObj(args[0])
+
+	if !c.send(id) {
+		return t.initErrorObject(errors.ClosedChannelError, "Can't deliver to a closed channel")
+	}
+
+	return args[0]
+}
+
 // objectMap ==========================================================
 
 type objectMap struct {
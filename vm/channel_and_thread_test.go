@@ -159,3 +159,439 @@ func TestObjectDeliveryBetweenThread(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestThreadClass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Thread.superclass.name`, "Object"},
+		{`
+		t = Thread.new(1, 2) do |a, b|
+		  a + b
+		end
+		t.value
+		`, 3},
+		{`
+		c = Channel.new
+
+		t = Thread.new do
+		  c.deliver(10)
+		  20
+		end
+
+		c.receive + t.value
+		`, 30},
+		{`
+		module Foo
+		  class Bar
+		  end
+		end
+
+		t = Thread.new do
+		  Foo::Bar.name
+		end
+		t.value
+		`, "Bar"},
+		{`
+		t = Thread.new do
+		  1
+		end
+		t.join == t
+		`, true},
+		{`
+		t = Thread.new do
+		  1
+		end
+		t.join
+		t.alive?
+		`, false},
+		// The error is located on the thread that raises it
+		{`
+		t = Thread.new do
+		  sleep(0.01)
+		  raise ArgumentError, "Oops"
+		end
+
+		begin
+		  t.value
+		rescue => e
+		  e.backtrace.first.split(":").last
+		end
+		`, "4"},
+		{`
+		c = Channel.new
+
+		t = Thread.new do
+		  c.receive
+		end
+
+		alive = t.alive?
+		c.deliver(1)
+		t.join
+		alive
+		`, true},
+		{`
+		c = Channel.new
+
+		t = Thread.new do
+		  c.receive
+		end
+
+		result = t.join(0.01)
+		c.deliver(1)
+		t.join
+		result
+		`, nil},
+		{`Thread.current.alive?`, true},
+		{`Thread.current == Thread.current`, true},
+		{`
+		t = Thread.new do
+		  Thread.current
+		end
+		t.value == t
+		`, true},
+		{`
+		t = thread do
+		  Thread.current
+		end
+		t.value == t
+		`, true},
+		{`
+		t = Thread.new do
+		  raise(ArgumentError, "Oops")
+		end
+
+		begin
+		  t.join
+		rescue ArgumentError => e
+		  e.message
+		end
+		`, "Oops"},
+		{`
+		threads = []
+
+		5.times do |i|
+		  threads.push(Thread.new(i) do |n|
+		    n * n
+		  end)
+		end
+
+		threads.map do |t|
+		  t.value
+		end.to_s
+		`, "[0, 1, 4, 9, 16]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestThreadClassFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Thread.new`, "InternalError: Can't yield without a block", 1},
+		{`Thread.current.join`, "ThreadError: Can't join the current thread", 1},
+		{`Thread.current.value`, "ThreadError: Can't join the current thread", 1},
+		{`
+		t = Thread.new do
+		  1
+		end
+		t.join("1")
+		`, "TypeError: Expect argument to be Integer or Float. got: String", 5},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestChannelBufferingAndClosing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Channel.new.capacity`, 0},
+		{`Channel.new(3).capacity`, 3},
+		{`
+		c = Channel.new(2)
+		c.send(1)
+		c.send(2)
+		c.receive + c.receive
+		`, 3},
+		{`
+		c = Channel.new(1)
+		c.deliver("a")
+		c.close
+		c.receive
+		`, "a"},
+		{`
+		c = Channel.new
+		c.close
+		c.receive
+		`, nil},
+		{`
+		c = Channel.new
+
+		thread do
+		  c.send(10)
+		end
+
+		c.receive
+		`, 10},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestChannelBufferingAndClosingFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Channel.new("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Channel.new(-1)`, "ArgumentError: Expect capacity to be positive. got: -1", 1},
+		{`Channel.new(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`Channel.new(1).send`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`
+		c = Channel.new(1)
+		c.close
+		c.send(1)
+		`, "ClosedChannelError: Can't deliver to a closed channel", 4},
+		{`
+		c = Channel.new
+		c.close
+		c.close
+		`, "ClosedChannelError: Can't close a closed channel", 4},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestChannelSelect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		c1 = Channel.new
+		c2 = Channel.new
+
+		thread do
+		  c2.send("hello")
+		end
+
+		ch, value = Channel.select(c1, c2)
+		ch == c2
+		`, true},
+		{`
+		c1 = Channel.new(1)
+		c2 = Channel.new
+		c1.send("hello")
+
+		ch, value = Channel.select(c1, c2)
+		value
+		`, "hello"},
+		{`
+		c = Channel.new
+		Channel.select(c, timeout: 0.01)
+		`, nil},
+		{`
+		c = Channel.new(1)
+		c.send(1)
+		Channel.select(c, timeout: 1)[1]
+		`, 1},
+		{`
+		c1 = Channel.new
+		c2 = Channel.new
+		c2.close
+		Channel.select(c1, c2)[1]
+		`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestChannelSelectFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Channel.select`, "ArgumentError: Expect at least 1 channel. got: 0", 1},
+		{`Channel.select(timeout: 1)`, "ArgumentError: Expect at least 1 channel. got: 0", 1},
+		{`Channel.select(1)`, "TypeError: Expect argument to be Channel. got: Integer", 1},
+		{`Channel.select(Channel.new, timeout: "1")`, "TypeError: Expect argument to be Integer or Float. got: String", 1},
+		{`Channel.select(Channel.new, wait: 1)`, "ArgumentError: Unknown keyword: wait", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestWaitGroupClass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`WaitGroup.new.wait.class.name`, "WaitGroup"},
+		{`
+		wg = WaitGroup.new
+		results = Channel.new(3)
+
+		3.times do |i|
+		  wg.add
+		  thread do
+		    results.send(i * 10)
+		    wg.done
+		  end
+		end
+
+		wg.wait
+		results.receive + results.receive + results.receive
+		`, 30},
+		{`
+		wg = WaitGroup.new
+		wg.add(2)
+		c = Channel.new(2)
+
+		thread do
+		  c.send(1)
+		  wg.done
+		end
+
+		thread do
+		  c.send(2)
+		  wg.done
+		end
+
+		wg.wait
+		c.capacity
+		`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestWaitGroupClassFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`WaitGroup.new(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`WaitGroup.new.add("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`WaitGroup.new.add(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`WaitGroup.new.add(-1)`, "ThreadError: Negative WaitGroup counter", 1},
+		{`WaitGroup.new.done`, "ThreadError: Negative WaitGroup counter", 1},
+		{`WaitGroup.new.wait(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestAtomicClass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Atomic.new.get`, 0},
+		{`Atomic.new(10).get`, 10},
+		{`Atomic.new(1).to_s`, "#<Atomic: 1>"},
+		{`Atomic.new.increment`, 1},
+		{`Atomic.new(1).increment(5)`, 6},
+		{`Atomic.new(10).decrement`, 9},
+		{`Atomic.new(10).decrement(4)`, 6},
+		{`Atomic.new.set(7)`, 7},
+		{`
+		a = Atomic.new
+		a.set(3)
+		a.get
+		`, 3},
+		{`Atomic.new(1).compare_and_set(1, 2)`, true},
+		{`Atomic.new(1).compare_and_set(2, 3)`, false},
+		{`
+		a = Atomic.new(1)
+		a.compare_and_set(1, 2)
+		a.compare_and_set(1, 3)
+		a.get
+		`, 2},
+		{`
+		count = Atomic.new
+		wg = WaitGroup.new
+
+		100.times do
+		  wg.add
+		  thread do
+		    count.increment
+		    wg.done
+		  end
+		end
+
+		wg.wait
+		count.get
+		`, 100},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestAtomicClassFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Atomic.new("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Atomic.new(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`Atomic.new.increment(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`Atomic.new.decrement(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`Atomic.new.get(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`Atomic.new.set`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`Atomic.new.set(nil)`, "TypeError: Expect argument to be Integer. got: Null", 1},
+		{`Atomic.new.compare_and_set(1)`, "ArgumentError: Expect 2 arguments. got: 1", 1},
+		{`Atomic.new.compare_and_set(1, "2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
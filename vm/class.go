@@ -1,12 +1,19 @@
 package vm
 
 import (
+	"bytes"
 	"fmt"
 	"io/ioutil"
+	"math"
+	"math/big"
 	"path"
-	"reflect"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+	"unicode"
 
+	"github.com/goby-lang/goby/compiler/bytecode"
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 )
@@ -27,6 +34,10 @@ type RClass struct {
 	isModule    bool
 	constants   map[string]*Pointer
 	scope       *RClass
+	// classVariables contains the class variables defined in the class, which are shared with its subclasses
+	classVariables map[string]Object
+	// includedModule is the module a proxy class in the method lookup chain is created for, see include
+	includedModule *RClass
 	*baseObj
 }
 
@@ -38,6 +49,42 @@ type builtinType interface {
 // Class methods --------------------------------------------------------
 func builtinClassCommonClassMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
+		{
+			// Returns the classes and modules in the order the VM looks up methods for the instances of the class.
+			// It starts with the class itself, followed by its included modules and superclasses.
+			//
+			// ```ruby
+			// module Walk; end
+			//
+			// class Animal
+			//   include(Walk)
+			// end
+			//
+			// class Dog < Animal; end
+			//
+			// Dog.ancestors                 # => [Dog, Animal, Walk, Object]
+			// Dog.singleton_class.ancestors # => [#<Class:Dog>, #<Class:Animal>, #<Class:Object>, Class]
+			// Walk.ancestors                # => [Walk]
+			// ```
+			//
+			// @return [Array]
+			Name: "ancestors",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					elems := []Object{}
+
+					for _, c := range receiver.(*RClass).ancestors() {
+						elems = append(elems, c)
+					}
+
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
 		{
 			// Creates instance variables and corresponding methods that return the value of
 			// each instance variable and assign an argument to each instance variable.
@@ -73,7 +120,13 @@ func builtinClassCommonClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					r := receiver.(*RClass)
-					r.setAttrAccessor(args)
+					names, err := attrNames(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					r.setAttrAccessor(names)
 
 					return r
 				}
@@ -109,7 +162,13 @@ func builtinClassCommonClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					r := receiver.(*RClass)
-					r.setAttrReader(args)
+					names, err := attrNames(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					r.setAttrReader(names)
 
 					return r
 				}
@@ -145,664 +204,2584 @@ func builtinClassCommonClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					r := receiver.(*RClass)
-					r.setAttrWriter(args)
+					names, err := attrNames(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					r.setAttrWriter(names)
 
 					return r
 				}
 			},
 		},
 		{
-			// Includes a module for mixin, which inherits only methods and constants from the module.
-			// The included module is inserted into the path of the inheritance tree, between the class
-			// and the superclass so that the methods of the module is prioritized to superclasses.
-			//
-			// The order of `include` affects: the modules that included later are prioritized.
-			// If multiple modules include the same methods, the method will only come from
-			// the last included module.
+			// Returns true if the class variable is defined in the class or module, its superclasses or its
+			// included modules.
 			//
 			// ```ruby
-			// module Foo
-			// def ten
-			//    10
-			// end
-			// end
-			//
-			// module Bar
-			//   def ten
-			//     "ten"
-			//   end
-			// end
-			//
-			// class Baz
-			//   include(Foo)
-			//   include(Bar) # method `ten` is only included from this module
+			// class Foo
+			//   @@bar = 1
 			// end
 			//
-			// a = Baz.new
-			// puts(a.ten) # => ten (overriden)
-			// ```
-			//
-			// **Note**:
-			//
-			// You cannot use string literal, or pass two or more arguments to `include`.
-			//
-			// ```ruby
-			//   include("Foo")    # => error
-			//   include(Foo, Bar) # => error
+			// Foo.class_variable_defined?("@@bar") # => true
+			// Foo.class_variable_defined?("@@baz") # => false
 			// ```
 			//
-			// Including modules into built-in classes such as String are not supported:
+			// @param name [String/Symbol]
+			// @return [Boolean]
+			Name: "class_variable_defined?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					name, err := t.classVariableNameArgument(args)
+					if err != nil {
+						return err
+					}
+
+					_, ok := receiver.(*RClass).classVariable(name)
+					return toBooleanObject(ok)
+				}
+			},
+		},
+		{
+			// Returns the value of the class variable in the class or module, its superclasses or its included
+			// modules. A NameError is raised if it's not defined.
 			//
 			// ```ruby
-			// module Foo
-			//   def ten
-			//     10
-			//   end
-			// end
-			// class String
-			//   include(Foo) # => error
+			// class Foo
+			//   @@bar = 1
 			// end
+			//
+			// Foo.class_variable_get("@@bar") # => 1
 			// ```
 			//
-			// @param module [Class] Module name to include
-			// @return [Null]
-			Name: "include",
+			// @param name [String/Symbol]
+			// @return [Object]
+			Name: "class_variable_get",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					var class *RClass
-					module, ok := args[0].(*RClass)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect argument to be a module. got=%v", args[0].Class().Name)
+					name, err := t.classVariableNameArgument(args)
+					if err != nil {
+						return err
 					}
 
-					switch r := receiver.(type) {
-					case *RClass:
-						class = r
-					default:
-						class = r.SingletonClass()
-					}
+					c := receiver.(*RClass)
 
-					if class.alreadyInherit(module) {
-						return class
+					v, ok := c.classVariable(name)
+					if !ok {
+						return t.initErrorObject(errors.NameError, "uninitialized class variable %s in %s", name, c.Name)
 					}
 
-					module.superClass = class.superClass
-					class.superClass = module
-
-					return class
+					return v
 				}
 			},
 		},
 		{
-			Name: "extend",
+			// Assigns the class variable and returns the value. Like `@@bar = value` in the class body, it's
+			// assigned in the superclass or included module that already defines it.
+			//
+			// ```ruby
+			// class Foo; end
+			//
+			// Foo.class_variable_set("@@bar", 1) # => 1
+			// Foo.class_variable_get("@@bar")    # => 1
+			// ```
+			//
+			// @param name [String/Symbol], value [Object]
+			// @return [Object]
+			Name: "class_variable_set",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					var class *RClass
-					module, ok := args[0].(*RClass)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect argument to be a module. got=%v", args[0].Class().Name)
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
 					}
 
-					class = receiver.SingletonClass()
-
-					if class.alreadyInherit(module) {
-						return class
+					name, err := t.classVariableNameArgument(args[:1])
+					if err != nil {
+						return err
 					}
 
-					module.superClass = class.superClass
-					class.superClass = module
-
-					return class
+					receiver.(*RClass).setClassVariable(name, args[1])
+					return args[1]
 				}
 			},
 		},
 		{
-			// Returns the name of the class (receiver).
+			// Returns the names of the class variables in the class or module, its superclasses and its included
+			// modules as Symbols.
 			//
 			// ```ruby
-			// puts(Array.name)  # => Array
-			// puts(Class.name)  # => Class
-			// puts(Object.name) # => Object
+			// class Foo
+			//   @@bar = 1
+			// end
+			//
+			// class Baz < Foo
+			//   @@qux = 2
+			// end
+			//
+			// Baz.class_variables # => [:@@bar, :@@qux]
 			// ```
-			// @param class [Class] Receiver
-			// @return [String] Converted receiver name
-			Name: "name",
+			//
+			// @return [Array]
+			Name: "class_variables",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
-					n, ok := receiver.(*RClass)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", "#name", receiver.toString())
-					}
-
-					name := n.ReturnName()
-					nameString := t.vm.initStringObject(name)
-					return nameString
+					return t.vm.initSymbolArray(receiver.(*RClass).classVariableNames())
 				}
 			},
 		},
 		{
-			// Creates and returns a new anonymous class from a receiver.
-			// You can use any classes you defined as the receiver:
+			// Returns the constant with the given name in the class or module. The name can be a path
+			// like "Foo::Bar". Constants of superclasses and included modules are also looked up unless
+			// false is given as the second argument.
 			//
 			// ```ruby
-			// class Foo
+			// module Foo
+			//   class Bar
+			//     Baz = 10
+			//   end
 			// end
-			// a = Foo.new
-			// ```
-			//
-			// Note that the built-in classes such as Class or String are not open for creating instances
-			// and you can't call `new` against them.
 			//
-			// ```ruby
-			// a = Class.new  # => error
-			// a = String.new # => error
+			// Object.const_get("Foo::Bar::Baz") # => 10
+			// Foo.const_get(:Bar)               # => Foo::Bar
 			// ```
-			// @param class [Class] Receiver
-			// @return [Object] Created object
-			Name: "new",
+			//
+			// @param name [String/Symbol], inherit [Boolean]
+			// @return [Object]
+			Name: "const_get",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					class, ok := receiver.(*RClass)
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+					}
 
+					path, ok := stringOrSymbol(args[0])
 					if !ok {
-						return t.unsupportedMethodError("#new", receiver)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					instance := class.initializeInstance()
-					initMethod := class.lookupMethod("initialize")
+					inherit := true
+					if len(args) == 2 {
+						b, ok := args[1].(*BooleanObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, args[1].Class().Name)
+						}
+						inherit = b.value
+					}
 
-					if initMethod != nil {
-						instance.InitializeMethod = initMethod.(*MethodObject)
+					var constant Object = receiver
+
+					for _, name := range strings.Split(path, "::") {
+						namespace, ok := constant.(*RClass)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, "%s is not a class/module", constant.toString())
+						}
+
+						if !isConstantName(name) {
+							return t.initErrorObject(errors.NameError, "wrong constant name %s", path)
+						}
+
+						ptr, ok := namespace.constants[name]
+						if !ok && inherit {
+							ptr = namespace.lookupConstant(name, false)
+						}
+
+						if ptr == nil {
+							return t.initErrorObject(errors.NameError, "uninitialized constant %s", qualifiedConstantName(namespace, name))
+						}
+
+						constant = ptr.Target
 					}
 
-					return instance
+					return constant
 				}
 			},
 		},
 		{
-			// Returns the superclass object of the receiver.
+			// Defines a constant with the given name in the class or module, and returns the value.
 			//
 			// ```ruby
-			// puts(Array.superclass)  # => <Class:Object>
-			// puts(String.superclass) # => <Class:Object>
+			// class Foo; end
 			//
-			// class Foo;end
-			// class Bar < Foo
-			// end
-			// puts(Foo.superclass)    # => <Class:Object>
-			// puts(Bar.superclass)    # => <Class:Foo>
+			// Foo.const_set(:Bar, 10) # => 10
+			// Foo::Bar                # => 10
 			// ```
 			//
-			// **Note**: the following is not supported:
-			//
-			// - Class class
-			//
-			// - Object class
-			//
-			// - instance objects or object literals
-			//
-			// ```ruby
-			// puts("string".superclass) # => error
-			// puts(Class.superclass)    # => error
-			// puts(Object.superclass)   # => error
-			// ```
-			// @param class [Class] Receiver
-			// @return [Object] Superclass object of the receiver
-			Name: "superclass",
+			// @param name [String/Symbol], value [Object]
+			// @return [Object]
+			Name: "const_set",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
 					}
 
-					c, ok := receiver.(*RClass)
-
+					name, ok := stringOrSymbol(args[0])
 					if !ok {
-						return t.vm.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", "#superclass", receiver.toString())
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					superClass := c.returnSuperClass()
+					if !isConstantName(name) {
+						return t.initErrorObject(errors.NameError, "wrong constant name %s", name)
+					}
 
-					if superClass == nil {
-						return NULL
+					c := receiver.(*RClass)
+
+					if _, ok := c.constants[name]; ok {
+						return t.initErrorObject(errors.ConstantAlreadyInitializedError, "Constant %s already been initialized. Can't assign value to a constant twice.", name)
 					}
 
-					return superClass
-				}
-			},
-		},
-	}
-}
+					c.constants[name] = &Pointer{Target: args[1]}
 
-// Instance methods -----------------------------------------------------
-func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
-	return []*BuiltinMethodObject{
-		{
-			Name: "singleton_class",
-			Fn: func(receiver Object) builtinMethodBody {
-				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					return receiver.SingletonClass()
+					if class, ok := args[1].(*RClass); ok && class.scope == nil {
+						class.scope = c
+					}
+
+					return args[1]
 				}
 			},
 		},
 		{
-			// General method for comparing equalty of the objects
+			// Returns the names of the constants in the class or module as Symbols, including the ones in
+			// its superclasses and included modules unless false is given.
 			//
 			// ```ruby
-			// 123 == 123   # => true
-			// 123 == "123" # => false
-			//
-			// # Hash will not concern about the key-value pair order
-			// { a: 1, b: 2 } == { a: 1, b: 2 } # => true
-			// { a: 1, b: 2 } == { b: 2, a: 1 } # => true
+			// module Foo
+			//   Bar = 1
+			// end
 			//
-			// # Hash key will be override if the key duplicated
-			// { a: 1, b: 2 } == { a: 2, b: 2, a: 1 } # => true
-			// { a: 1, b: 2 } == { a: 1, b: 2, a: 2 } # => false
+			// class Baz
+			//   include Foo
+			//   Qux = 2
+			// end
 			//
-			// # Array will concern about the order of the elements
-			// [1, 2, 3] == [1, 2, 3] # => true
-			// [1, 2, 3] == [3, 2, 1] # => false
+			// Baz.constants        # => [:Bar, :Qux]
+			// Baz.constants(false) # => [:Qux]
 			// ```
 			//
-			// @return [@boolean]
-			Name: "==",
+			// @param inherit [Boolean]
+			// @return [Array]
+			Name: "constants",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					className := receiver.Class().Name
-					compareClassName := args[0].Class().Name
+					inherit := true
+
+					switch len(args) {
+					case 0:
+					case 1:
+						b, ok := args[0].(*BooleanObject)
 
-					if className == compareClassName && reflect.DeepEqual(receiver, args[0]) {
-						return TRUE
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, args[0].Class().Name)
+						}
+
+						inherit = b.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
 					}
-					return FALSE
+
+					return t.vm.initSymbolArray(receiver.(*RClass).constantNames(inherit))
 				}
 			},
-		}, {
-			// General method for comparing inequalty of the objects
+		},
+		{
+			// Defines an instance method with the given name, whose body is the given block or Proc.
+			// The method's arguments are passed to the block, and the block keeps the local variables
+			// of the scope it's created in.
 			//
 			// ```ruby
-			// 123 != 123   # => false
-			// 123 != "123" # => true
+			// class Foo
+			//   prefix = "get_"
 			//
-			// # Hash will not concern about the key-value pair order
-			// { a: 1, b: 2 } != { a: 1, b: 2 } # => false
-			// { a: 1, b: 2 } != { b: 2, a: 1 } # => false
+			//   ["bar", "baz"].each do |name|
+			//     define_method(prefix + name) do
+			//       name.upcase
+			//     end
+			//   end
+			//
+			//   define_method(:add) do |a, b|
+			//     a + b
+			//   end
+			// end
+			//
+			// Foo.new.get_bar   # => "BAR"
+			// Foo.new.add(1, 2) # => 3
+			// ```
+			//
+			// @param name [String/Symbol], body [Proc]
+			// @return [Symbol]
+			Name: "define_method",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame != nil {
+						// The block isn't executed now, so its frame needs to be popped manually.
+						t.callFrameStack.pop()
+					}
+
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+					}
+
+					name, ok := stringOrSymbol(args[0])
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", args[0].Class().Name)
+					}
+
+					body := blockFrame
+
+					if len(args) == 2 {
+						proc, ok := args[1].(*ProcObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ProcClass, args[1].Class().Name)
+						}
+
+						body = proc.blockFrame
+					}
+
+					if body == nil {
+						return t.initErrorObject(errors.ArgumentError, "Can't define method '%s' without a block", name)
+					}
+
+					receiver.(*RClass).Methods.set(name, generateBlockMethod(name, body))
+
+					return t.vm.initSymbolObject(name)
+				}
+			},
+		},
+		{
+			// Returns the names of the instance methods of the class, including the inherited ones.
+			// Only the methods defined in the class itself are returned if false is given.
+			//
+			// ```ruby
+			// class Foo
+			//   def bar; end
+			// end
+			//
+			// Foo.instance_methods(false) # => [:bar]
+			// Foo.instance_methods        # => [:!, :!=, :==, ..., :bar, ...]
+			// ```
+			//
+			// @param inherited [Boolean]
+			// @return [Array]
+			Name: "instance_methods",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					inherited := true
+
+					switch len(args) {
+					case 0:
+					case 1:
+						b, ok := args[0].(*BooleanObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, args[0].Class().Name)
+						}
+
+						inherited = b.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					return t.vm.initSymbolArray(receiver.(*RClass).methodNames(inherited))
+				}
+			},
+		},
+		{
+			// Includes a module for mixin, which inherits only methods and constants from the module.
+			// The included module is inserted into the path of the inheritance tree, between the class
+			// and the superclass so that the methods of the module is prioritized to superclasses.
+			//
+			// The order of `include` affects: the modules that included later are prioritized.
+			// If multiple modules include the same methods, the method will only come from
+			// the last included module.
+			//
+			// ```ruby
+			// module Foo
+			// def ten
+			//    10
+			// end
+			// end
+			//
+			// module Bar
+			//   def ten
+			//     "ten"
+			//   end
+			// end
+			//
+			// class Baz
+			//   include(Foo)
+			//   include(Bar) # method `ten` is only included from this module
+			// end
+			//
+			// a = Baz.new
+			// puts(a.ten) # => ten (overriden)
+			// ```
+			//
+			// **Note**:
+			//
+			// You cannot use string literal, or pass two or more arguments to `include`.
+			//
+			// ```ruby
+			//   include("Foo")    # => error
+			//   include(Foo, Bar) # => error
+			// ```
+			//
+			// @param module [Class] Module name to include
+			// @return [Null]
+			Name: "include",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					var class *RClass
+					module, ok := args[0].(*RClass)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, "Expect argument to be a module. got=%v", args[0].Class().Name)
+					}
+
+					switch r := receiver.(type) {
+					case *RClass:
+						class = r
+					default:
+						class = r.SingletonClass()
+					}
+
+					class.include(module)
+
+					return class
+				}
+			},
+		},
+		{
+			// Returns true if the module is included in the class or its ancestors.
+			//
+			// ```ruby
+			// module Walk; end
+			//
+			// class Animal
+			//   include(Walk)
+			// end
+			//
+			// class Dog < Animal; end
+			//
+			// Dog.include?(Walk)        # => true
+			// Dog.include?(Comparable)  # => false
+			// Array.include?(Enumerable) # => true
+			// ```
+			//
+			// @param module [Class]
+			// @return [Boolean]
+			Name: "include?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					module, ok := args[0].(*RClass)
+
+					if !ok || !module.isModule {
+						return t.initErrorObject(errors.TypeError, "Expect argument to be a module. got: %s", args[0].toString())
+					}
+
+					c := receiver.(*RClass)
+
+					for _, ancestor := range c.ancestors() {
+						if ancestor == module && ancestor != c {
+							return TRUE
+						}
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns the instance method with the given name as an UnboundMethod, which can be inherited or
+			// included from a module. Private methods can also be returned.
+			//
+			// ```ruby
+			// class Foo
+			//   def bar; end
+			// end
+			//
+			// Foo.instance_method(:bar)       # => #<UnboundMethod: Foo#bar>
+			// Foo.instance_method(:to_s).owner # => Object
+			// Foo.instance_method(:baz)       # => UndefinedMethodError
+			// ```
+			//
+			// @param name [String/Symbol]
+			// @return [UnboundMethod]
+			Name: "instance_method",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					name, ok := stringOrSymbol(args[0])
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", args[0].Class().Name)
+					}
+
+					c := receiver.(*RClass)
+					method, owner := c.lookupMethodOwner(name)
+
+					if method == nil {
+						return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", name, c.toString())
+					}
+
+					return t.vm.initUnboundMethodObject(name, owner, method)
+				}
+			},
+		},
+		{
+			// Returns true if the instances of the class have a public or protected method with the given name,
+			// which can be inherited or included from a module.
+			//
+			// ```ruby
+			// class Foo
+			//   def bar; end
+			//
+			//   private
+			//
+			//   def baz; end
+			// end
+			//
+			// Foo.method_defined?(:bar)  # => true
+			// Foo.method_defined?(:to_s) # => true
+			// Foo.method_defined?(:baz)  # => false
+			// ```
+			//
+			// @param name [String/Symbol]
+			// @return [Boolean]
+			Name: "method_defined?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					name, ok := stringOrSymbol(args[0])
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", args[0].Class().Name)
+					}
+
+					method := receiver.(*RClass).lookupMethod(name)
+
+					return toBooleanObject(method != nil && methodVisibility(method) != bytecode.Private)
+				}
+			},
+		},
+		{
+			// Makes the module's methods callable on the module itself, while they are still mixed in as private
+			// instance methods when the module is included.
+			// Without arguments, it applies to the methods defined after it in module body.
+			//
+			// ```ruby
+			// module Greeting
+			//   module_function
+			//
+			//   def hello(name)
+			//     "Hello, " + name
+			//   end
+			// end
+			//
+			// Greeting.hello("Goby") # => "Hello, Goby"
+			//
+			// module Util
+			//   def twice(n)
+			//     n * 2
+			//   end
+			//
+			//   module_function :twice
+			// end
+			//
+			// Util.twice(2) # => 4
+			// ```
+			//
+			// @param *names [String/Symbol]
+			// @return [Object] nil, the name, or an array of the names
+			Name: "module_function",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					module, ok := receiver.(*RClass)
+
+					if !ok || !module.isModule {
+						return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", "module_function", receiver.toString())
+					}
+
+					if len(args) == 0 {
+						return NULL
+					}
+
+					for _, arg := range args {
+						name, ok := stringOrSymbol(arg)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", arg.Class().Name)
+						}
+
+						method := module.lookupMethod(name)
+
+						if method == nil {
+							return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", name, module.toString())
+						}
+
+						module.defineModuleFunction(name, method)
+					}
+
+					if len(args) == 1 {
+						return args[0]
+					}
+
+					return t.vm.initArrayObject(args)
+				}
+			},
+		},
+		{
+			// Returns the name of the class (receiver).
+			//
+			// ```ruby
+			// puts(Array.name)  # => Array
+			// puts(Class.name)  # => Class
+			// puts(Object.name) # => Object
+			// ```
+			// @param class [Class] Receiver
+			// @return [String] Converted receiver name
+			Name: "name",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					n, ok := receiver.(*RClass)
+
+					if !ok {
+						return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", "#name", receiver.toString())
+					}
+
+					name := n.ReturnName()
+					nameString := t.vm.initStringObject(name)
+					return nameString
+				}
+			},
+		},
+		{
+			// Creates and returns a new anonymous class from a receiver.
+			// You can use any classes you defined as the receiver:
+			//
+			// ```ruby
+			// class Foo
+			// end
+			// a = Foo.new
+			// ```
+			//
+			// Note that the built-in classes such as Class or String are not open for creating instances
+			// and you can't call `new` against them.
+			//
+			// ```ruby
+			// a = Class.new  # => error
+			// a = String.new # => error
+			// ```
+			// @param class [Class] Receiver
+			// @return [Object] Created object
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					class, ok := receiver.(*RClass)
+
+					if !ok {
+						return t.unsupportedMethodError("#new", receiver)
+					}
+
+					instance := class.initializeInstance()
+					initMethod := class.lookupMethod("initialize")
+
+					if initMethod != nil {
+						instance.InitializeMethod = initMethod.(*MethodObject)
+					}
+
+					return instance
+				}
+			},
+		},
+		{
+			// Makes the methods defined after it private when it's called without arguments in class body.
+			// Private methods can only be called on self, either implicitly or with `self.`.
+			// The given methods are made private if there are any arguments.
+			//
+			// ```ruby
+			// class Foo
+			//   def bar
+			//     secret
+			//   end
+			//
+			//   private
+			//
+			//   def secret
+			//     42
+			//   end
+			// end
+			//
+			// Foo.new.bar    # => 42
+			// Foo.new.secret # => UndefinedMethodError
+			//
+			// class Foo
+			//   private :bar
+			// end
+			// ```
+			//
+			// @param *names [String/Symbol]
+			// @return [Object] nil, the name, or an array of the names
+			Name: "private",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return setMethodVisibility(t, receiver, args, bytecode.Private)
+				}
+			},
+		},
+		{
+			// Makes the methods defined after it protected when it's called without arguments in class body.
+			// Protected methods can be called with an explicit receiver, but only by objects that have the same method.
+			// The given methods are made protected if there are any arguments.
+			//
+			// ```ruby
+			// class Account
+			//   def initialize(balance)
+			//     @balance = balance
+			//   end
+			//
+			//   def richer_than?(other)
+			//     balance > other.balance
+			//   end
+			//
+			//   protected
+			//
+			//   def balance
+			//     @balance
+			//   end
+			// end
+			//
+			// Account.new(2).richer_than?(Account.new(1)) # => true
+			// Account.new(2).balance                      # => UndefinedMethodError
+			// ```
+			//
+			// @param *names [String/Symbol]
+			// @return [Object] nil, the name, or an array of the names
+			Name: "protected",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return setMethodVisibility(t, receiver, args, bytecode.Protected)
+				}
+			},
+		},
+		{
+			// Makes the methods defined after it public when it's called without arguments in class body,
+			// or makes the given methods public.
+			//
+			// ```ruby
+			// class Foo
+			//   private
+			//
+			//   def bar
+			//     42
+			//   end
+			//
+			//   public :bar
+			// end
+			//
+			// Foo.new.bar # => 42
+			// ```
+			//
+			// @param *names [String/Symbol]
+			// @return [Object] nil, the name, or an array of the names
+			Name: "public",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return setMethodVisibility(t, receiver, args, bytecode.Public)
+				}
+			},
+		},
+		{
+			// Returns the superclass object of the receiver.
+			//
+			// ```ruby
+			// puts(Array.superclass)  # => <Class:Object>
+			// puts(String.superclass) # => <Class:Object>
+			//
+			// class Foo;end
+			// class Bar < Foo
+			// end
+			// puts(Foo.superclass)    # => <Class:Object>
+			// puts(Bar.superclass)    # => <Class:Foo>
+			// ```
+			//
+			// **Note**: the following is not supported:
+			//
+			// - Class class
+			//
+			// - Object class
+			//
+			// - instance objects or object literals
+			//
+			// ```ruby
+			// puts("string".superclass) # => error
+			// puts(Class.superclass)    # => error
+			// puts(Object.superclass)   # => error
+			// ```
+			// @param class [Class] Receiver
+			// @return [Object] Superclass object of the receiver
+			Name: "superclass",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					c, ok := receiver.(*RClass)
+
+					if !ok {
+						return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", "#superclass", receiver.toString())
+					}
+
+					superClass := c.returnSuperClass()
+
+					if superClass == nil {
+						return NULL
+					}
+
+					return superClass
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinClassCommonInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the object's singleton class, which holds the methods defined only for the object.
+			// It's created if the object doesn't have one yet.
+			//
+			// ```ruby
+			// Foo.singleton_class           # => #<Class:Foo>
+			// Foo.new.singleton_class.class # => Class
+			// ```
+			//
+			// @return [Class]
+			Name: "singleton_class",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.singletonClassOf(receiver)
+				}
+			},
+		},
+		{
+			// Returns the names of the methods defined in the object's singleton class.
+			//
+			// ```ruby
+			// class Foo
+			//   def self.bar; end
+			// end
+			//
+			// Foo.singleton_methods     # => [:bar]
+			// Foo.new.singleton_methods # => []
+			// ```
+			//
+			// @return [Array]
+			Name: "singleton_methods",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					singletonClass := receiver.SingletonClass()
+
+					if singletonClass == nil {
+						return t.vm.initArrayObject([]Object{})
+					}
+
+					return t.vm.initSymbolArray(singletonClass.methodNames(false))
+				}
+			},
+		},
+		{
+			// General method for comparing equalty of the objects
+			//
+			// ```ruby
+			// 123 == 123   # => true
+			// 123 == "123" # => false
+			//
+			// # Hash will not concern about the key-value pair order
+			// { a: 1, b: 2 } == { a: 1, b: 2 } # => true
+			// { a: 1, b: 2 } == { b: 2, a: 1 } # => true
+			//
+			// # Hash key will be override if the key duplicated
+			// { a: 1, b: 2 } == { a: 2, b: 2, a: 1 } # => true
+			// { a: 1, b: 2 } == { a: 1, b: 2, a: 2 } # => false
+			//
+			// # Array will concern about the order of the elements
+			// [1, 2, 3] == [1, 2, 3] # => true
+			// [1, 2, 3] == [3, 2, 1] # => false
+			//
+			// # Elements and values are compared with their own `==`, other objects are equal only to themselves
+			// Foo.new == Foo.new # => false
+			// ```
+			//
+			// @return [@boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					var eq bool
+					var err *Error
+
+					switch r := receiver.(type) {
+					case *ArrayObject:
+						if compare, ok := args[0].(*ArrayObject); ok {
+							eq, err = t.arraysEqual(r, compare)
+						}
+					case *HashObject:
+						if compare, ok := args[0].(*HashObject); ok {
+							eq, err = t.hashesEqual(r, compare)
+						}
+					default:
+						eq = receiver == args[0]
+					}
+
+					if err != nil {
+						return err
+					}
+					return toBooleanObject(eq)
+				}
+			},
+		}, {
+			// General method for comparing inequalty of the objects.
+			// It returns the opposite of `==`, so it also works with the classes that define their own `==`.
+			//
+			// ```ruby
+			// 123 != 123   # => false
+			// 123 != "123" # => true
+			//
+			// # Hash will not concern about the key-value pair order
+			// { a: 1, b: 2 } != { a: 1, b: 2 } # => false
+			// { a: 1, b: 2 } != { b: 2, a: 1 } # => false
 			//
 			// # Hash key will be override if the key duplicated
 			// { a: 1, b: 2 } != { a: 2, b: 2, a: 1 } # => false
 			// { a: 1, b: 2 } != { a: 1, b: 2, a: 2 } # => true
 			//
-			// # Array will concern about the order of the elements
-			// [1, 2, 3] != [1, 2, 3] # => false
-			// [1, 2, 3] != [3, 2, 1] # => true
+			// # Array will concern about the order of the elements
+			// [1, 2, 3] != [1, 2, 3] # => false
+			// [1, 2, 3] != [3, 2, 1] # => true
+			// ```
+			//
+			// @return [@boolean]
+			Name: "!=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					result := t.sendMethod("==", receiver, args[0])
+
+					if err, ok := result.(*Error); ok {
+						return err
+					}
+
+					if isTruthy(result) {
+						return FALSE
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns true if the given object matches the receiver, which is used by pattern matching.
+			// By default it's the same as `==`, while a class matches its instances, and ranges and regexps
+			// match their members.
+			//
+			// ```ruby
+			// 1 === 1             # => true
+			// "a" === "b"         # => false
+			// Integer === 1       # => true
+			// Integer === "1"     # => false
+			// (1..5) === 3        # => true
+			// /G.by/ === "Goby"   # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "===",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if c, ok := receiver.(*RClass); ok {
+						return t.sendMethod("is_a?", args[0], c)
+					}
+
+					return t.sendMethod("==", receiver, args[0])
+				}
+			},
+		},
+		{
+			// Returns true if the objects are equal as hash keys. Instances of Goby classes are only `eql?` to
+			// themselves, so a class that's used as keys of Hash should define `eql?` and `hash` together.
+			// Builtin values like ranges are `eql?` to equal values of the same class, and arrays compare their
+			// elements with `eql?`.
+			//
+			// ```ruby
+			// 1.eql?(1)                   # => true
+			// 1.eql?(1.0)                 # => false
+			// [1, "a"].eql?([1, "a"])     # => true
+			// [1].eql?([1.0])             # => false
+			// (1..2).eql?(1..2)           # => true
+			// Object.new.eql?(Object.new) # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "eql?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					var eq bool
+					var err *Error
+
+					switch r := receiver.(type) {
+					case *ArrayObject:
+						if compare, ok := args[0].(*ArrayObject); ok {
+							eq, err = t.arraysEql(r, compare)
+						}
+					case *RObject:
+						eq = receiver == args[0]
+					default:
+						if receiver.Class() == args[0].Class() {
+							eq, err = t.objectsEqual(receiver, args[0])
+						}
+					}
+
+					if err != nil {
+						return err
+					}
+					return toBooleanObject(eq)
+				}
+			},
+		},
+		{
+			// Returns an Integer hash value of the object, objects that are `eql?` must have the same hash value.
+			// Arrays and hashes combine the hash values of their contents, and instances of Goby classes
+			// are hashed by identity unless their class defines `hash`.
+			//
+			// ```ruby
+			// "foo".hash == "foo".hash  # => true
+			// [1, 2].hash == [1, 2].hash # => true
+			// ```
+			//
+			// @return [Integer]
+			Name: "hash",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					h, err := t.builtinHash(receiver)
+					if err != nil {
+						return err
+					}
+					return t.vm.initIntegerObject(h)
+				}
+			},
+		},
+		{
+			// Prevents further modifications of the object and returns it. Modifying a frozen object,
+			// like assigning its instance variables or pushing to a frozen Array, raises a FrozenError.
+			//
+			// ```ruby
+			// a = [1, 2].freeze
+			// a.push(3) # => FrozenError: Can't modify frozen Array: [1, 2]
+			// ```
+			//
+			// @return [Object]
+			Name: "freeze",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					receiver.freeze()
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns true if the object is frozen. Integers, Floats, Symbols, `true`, `false` and `nil` are always frozen.
+			//
+			// ```ruby
+			// "foo".frozen?        # => false
+			// "foo".freeze.frozen? # => true
+			// 1.frozen?            # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "frozen?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return toBooleanObject(receiver.isFrozen())
+				}
+			},
+		},
+		{
+			// Returns a shallow copy of the object, whose instance variables refer to the same objects as
+			// the receiver's. If the class defines `initialize_copy`, it's called on the copy with the receiver.
+			// Unlike `clone`, the copy isn't frozen and doesn't have the receiver's singleton methods.
+			//
+			// ```ruby
+			// class Foo
+			//   attr_accessor :bar
+			//
+			//   def initialize_copy(original)
+			//     @bar = original.bar.dup
+			//   end
+			// end
+			//
+			// f = Foo.new
+			// f.bar = [1]
+			// d = f.dup
+			// d.bar.push(2)
+			// f.bar # => [1]
+			// ```
+			//
+			// @return [Object]
+			Name: "dup",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return copyObject(t, receiver, false)
+				}
+			},
+		},
+		{
+			// Returns a shallow copy of the object like `dup`, but the copy also keeps the receiver's
+			// singleton methods and is frozen if the receiver is frozen.
+			//
+			// ```ruby
+			// s = "foo"
+			// def s.shout
+			//   upcase + "!"
+			// end
+			//
+			// s.clone.shout              # => "FOO!"
+			// s.dup.shout                # => UndefinedMethodError
+			// "foo".freeze.clone.frozen? # => true
+			// ```
+			//
+			// @return [Object]
+			Name: "clone",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return copyObject(t, receiver, true)
+				}
+			},
+		},
+		{
+			// Loads the given Goby library name without extension (mainly for modules), returning `true`
+			// if successful and `false` if the feature is already loaded.
+			//
+			// Currently, only the following embedded Goby libraries are targeted:
+			//
+			// - "file"
+			// - "net/http"
+			// - "net/simple_server"
+			// - "uri"
+			//
+			// ```ruby
+			// require("file")
+			// File.extname("foo.rb")
+			// ```
+			//
+			// TBD: the load paths for `require`
+			//
+			// @param filename [String] Quoted file name of the library, without extension
+			// @return [Boolean] Result of loading module
+			Name: "require",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					libName := args[0].(*StringObject).value
+					initFunc, ok := standardLibraries[libName]
+
+					if !ok {
+						return t.initErrorObject(errors.InternalError, "Can't require \"%s\"", libName)
+					}
+
+					initFunc(t.vm)
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Loads the Goby library (mainly for modules) from the given local path plus name
+			// without extension from the current directory, returning `true` if successful,
+			// and `false` if the feature is already loaded.
+			//
+			// ```ruby
+			// require_relative("../test_fixtures/require_test/foo")
+			// fifty = Foo.bar(5)
+			// ```
+			//
+			// @param path/name [String] Quoted file path to library plus name, without extension
+			// @return [Boolean] Result of loading module
+			Name: "require_relative",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					callerDir := path.Dir(t.vm.currentFilePath())
+					filepath := args[0].(*StringObject).value
+
+					filepath = path.Join(callerDir, filepath) + ".gb"
+
+					file, err := ioutil.ReadFile(filepath)
+
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					t.vm.execRequiredFile(filepath, file)
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns the path of the file where it's called.
+			//
+			// ```ruby
+			// # /home/goby/foo.gb
+			// __FILE__ # => "/home/goby/foo.gb"
+			// ```
+			//
+			// @return [String]
+			Name: "__FILE__",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initStringObject(t.callFrameStack.top().instructionSet.filename)
+				}
+			},
+		},
+		{
+			// Returns the line number where it's called.
+			//
+			// ```ruby
+			// puts(__LINE__) # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "__LINE__",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initIntegerObject(t.callFrameStack.top().sourceLine())
+				}
+			},
+		},
+		{
+			// Returns the absolute path of the directory of the file where it's called. It's useful for building
+			// paths relative to the current file.
+			//
+			// ```ruby
+			// # /home/goby/foo.gb
+			// __dir__ # => "/home/goby"
+			// ```
+			//
+			// @return [String]
+			Name: "__dir__",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					dir, err := filepath.Abs(path.Dir(t.callFrameStack.top().instructionSet.filename))
+
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initStringObject(dir)
+				}
+			},
+		},
+		{
+			// Returns the name of the method where it's called as a Symbol, or nil outside of methods.
+			//
+			// ```ruby
+			// def foo
+			//   __method__
+			// end
+			//
+			// foo        # => :foo
+			// __method__ # => nil
+			// ```
+			//
+			// @return [Symbol]
+			Name: "__method__",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					cf := t.callFrameStack.top().methodFrame()
+
+					if cf == nil {
+						return NULL
+					}
+
+					return t.vm.initSymbolObject(cf.method.Name)
+				}
+			},
+		},
+		{
+			// Puts string literals or objects into stdout with a tailing line feed, converting into String
+			// if needed.
+			//
+			// ```ruby
+			// puts("foo", "bar")
+			// # => foo
+			// # => bar
+			// puts("baz", String.name)
+			// # => baz
+			// # => String
+			// puts("foo" + "bar")
+			// # => foobar
+			// puts("#{1 + 1} apples")
+			// # => 2 apples
+			// ```
+			//
+			// @param *args [Class] String literals, or other objects that can be converted into String.
+			// @return [Null]
+			Name: "puts",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.puts(t.vm.stdout(), args)
+				}
+			},
+		},
+		{
+			// Prints the given objects into `$stdout` without adding newlines.
+			//
+			// ```ruby
+			// print("foo", "bar")
+			// # => foobar
+			// print(1, "\n")
+			// # => 1
+			// ```
+			//
+			// @param *args [Class] String literals, or other objects that can be converted into String.
+			// @return [Null]
+			Name: "print",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.print(t.vm.stdout(), args)
+				}
+			},
+		},
+		{
+			// Writes the given objects formatted by `inspect` into `$stdout`, each followed by a newline, which is
+			// handy for debugging. Returns the object, or an Array of the objects if more than one is given.
+			//
+			// ```ruby
+			// p("foo", [1, :a])
+			// # => "foo"
+			// # => [1, a]
+			// x = p(1 + 1) # => 2
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Object]
+			Name: "p",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.p(t.vm.stdout(), args, t.inspectObject)
+				}
+			},
+		},
+		{
+			// Same as `p`, but nested Arrays and Hashes that don't fit in 80 columns are broken into lines
+			// with their elements indented.
+			//
+			// ```ruby
+			// pp({ name: "goby", tags: ["ruby-like", "concurrent", "vm"], authors: [{ name: "st0012", since: 2016 }] })
+			// # => {
+			// # =>   authors: [{ name: "st0012", since: 2016 }],
+			// # =>   name: "goby",
+			// # =>   tags: ["ruby-like", "concurrent", "vm"]
+			// # => }
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Object]
+			Name: "pp",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.p(t.vm.stdout(), args, t.prettyInspect)
+				}
+			},
+		},
+		{
+			// Reads the next line from `$stdin`, including the trailing newline. Returns nil at the end of the input.
+			//
+			// ```ruby
+			// print("What's your name? ")
+			// name = gets.chomp
+			// puts("Hello, " + name)
+			// ```
+			//
+			// @return [String]
+			Name: "gets",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.readLine(t.vm.stdin().bufferedReader())
+				}
+			},
+		},
+		{
+			// Returns a string formatted with given arguments, format directives look like
+			// `%[flags][width][.precision]type`:
+			//
+			// - flags: "-" to left justify, "+" to show plus sign, " " to leave a space for plus sign, "0" to pad with zeros, "#" for alternate format
+			// - width: minimum width of the result
+			// - precision: number of digits after the decimal point for floating point types, or maximum characters for `%s`
+			// - type: `d`/`i` for Integer, `f`/`e`/`g` for floating point number, `x`/`o`/`b` for hexadecimal/octal/binary,
+			//   `c` for character, `s` for String (other objects are converted with `to_s`) and `%%` for a literal "%"
+			//
+			// Arguments are validated against the directives: the integer types accept Integer and BigInt objects, and
+			// the floating point types also accept Float objects.
+			//
+			// ```ruby
+			// format("%05d", 42)               # => "00042"
+			// format("%-5s|%5s", "ab", "cd")   # => "ab   |   cd"
+			// format("%.2f", 3)                # => "3.00"
+			// format("%.2f", 3.14159)          # => "3.14"
+			// format("%x %o %b", 255, 8, 5)    # => "ff 10 101"
+			// format("%s is 100%% done", "Goby") # => "Goby is 100% done"
+			// ```
+			//
+			// @param format [String], *args [Object]
+			// @return [String]
+			Name: "format",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got: %d", len(args))
+					}
+
+					f := args[0]
+					format, ok := f.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, f.Class().Name)
+					}
+
+					result, err := sprintf(t, format.value, args[1:])
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(result)
+				}
+			},
+		},
+		{
+			// An alias of #format.
+			//
+			// ```ruby
+			// sprintf("%3d|%-3d|", 1, 2) # => "  1|2  |"
+			// ```
+			//
+			// @param format [String], *args [Object]
+			// @return [String]
+			Name: "sprintf",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got: %d", len(args))
+					}
+
+					f := args[0]
+					format, ok := f.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, f.Class().Name)
+					}
+
+					result, err := sprintf(t, format.value, args[1:])
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(result)
+				}
+			},
+		},
+		{
+			// Returns the class of the object. Receiver cannot be omitted.
+			//
+			// FYI: You can convert the class into String with `#name`.
+			//
+			// ```ruby
+			// puts(100.class)         # => <Class:Integer>
+			// puts(100.class.name)    # => Integer
+			// puts("123".class)       # => <Class:String>
+			// puts("123".class.name)  # => String
+			// ```
+			//
+			// @param object [Object] Receiver (required)
+			// @return [Class] The class of the receiver
+			Name: "class",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					switch r := receiver.(type) {
+					case Object:
+						return r.Class()
+					default:
+						return &Error{Message: "Can't call class on %T" + string(r.Class().ReturnName())}
+					}
+				}
+			},
+		},
+		{
+			// Inverts the boolean value.
+			//
+			// ```ruby
+			// !true  # => false
+			// !false # => true
+			// ```
+			//
+			// @param object [Object] object that return boolean value to invert
+			// @return [Object] Inverted boolean value
+			Name: "!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns a Rational of the numerator divided by the denominator, which defaults to 1.
+			// Both of them can be Integers, Floats, Rationals or Strings like "1/3" and "0.75".
+			//
+			// ```ruby
+			// Rational(1, 3)   # => 1/3
+			// Rational(4, 6)   # => 2/3
+			// Rational("0.75") # => 3/4
+			// Rational(0.5)    # => 1/2
+			// ```
+			//
+			// @return [Rational]
+			Name: "Rational",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+					}
+
+					num, err := convertToRational(t, args[0])
+
+					if err != nil {
+						return err
+					}
+
+					if len(args) == 1 {
+						return t.vm.initRationalObject(num)
+					}
+
+					denom, err := convertToRational(t, args[1])
+
+					if err != nil {
+						return err
+					}
+
+					if denom.Sign() == 0 {
+						return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+					}
+
+					return t.vm.initRationalObject(new(big.Rat).Quo(num, denom))
+				}
+			},
+		},
+		{
+			// Raises an error, which stops the program unless it's rescued by `begin ... rescue ... end`.
+			// With a String it raises a RuntimeError with the message, and with an error class it raises an error
+			// of that class with an optional message. An error object is raised again, and so is the last rescued
+			// error when it's called without arguments.
+			//
+			// ```ruby
+			// raise "Oops"                            # => RuntimeError: Oops
+			// raise ArgumentError                     # => ArgumentError: ArgumentError
+			// raise ArgumentError, "Invalid argument" # => ArgumentError: Invalid argument
+			//
+			// begin
+			//   10 / 0
+			// rescue => e
+			//   raise e # raises the ZeroDivisionError again
+			// end
+			// ```
+			//
+			// @return [Error]
+			Name: "raise",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					var err *Error
+
+					switch len(args) {
+					case 0:
+						err = t.currentError()
+
+						if err == nil {
+							return t.initErrorObject(errors.RuntimeError, "unhandled exception")
+						}
+					case 1:
+						switch e := args[0].(type) {
+						case *StringObject:
+							return t.initErrorObject(errors.RuntimeError, "%s", e.value)
+						case *Error:
+							err = e
+						case *RClass:
+							if !t.vm.isErrorClass(e) {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "an error class or an error", e.Name)
+							}
+
+							return t.initErrorObjectWithClass(e, e.Name)
+						default:
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "an error class or an error", e.Class().Name)
+						}
+					case 2:
+						c, ok := args[0].(*RClass)
+
+						if !ok || !t.vm.isErrorClass(c) {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "an error class", args[0].toString())
+						}
+
+						message, ok := args[1].(*StringObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+						}
+
+						return t.initErrorObjectWithClass(c, message.value)
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..2 arguments. got=%d", len(args))
+					}
+
+					// Errors created by `new` get their backtrace when they're raised
+					if err.backtrace == nil {
+						t.locateError(err)
+					}
+
+					err.rescued = false
+					return err
+				}
+			},
+		},
+		{
+			// Returns a random number from the global generator.
+			// Without an argument, or with nil, it returns a Float between 0.0 and 1.0.
+			// With an Integer max it returns an Integer between 0 and max - 1, and with a Float max
+			// it returns a Float between 0.0 and max. With a Range it returns an Integer within the range.
+			//
+			// ```ruby
+			// rand       # => 0.6046602879796196
+			// rand(10)   # => 7
+			// rand(2.5)  # => 1.5116380392968836
+			// rand(1..6) # => 4
+			// ```
+			//
+			// @return [Number]
+			Name: "rand",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					t.vm.Lock()
+					r := t.vm.randomGenerator
+					t.vm.Unlock()
+
+					return randomNumber(t, r, args)
+				}
+			},
+		},
+		{
+			// Seeds the global generator used by `rand` with the given Integer, or with a seed based on the
+			// current time if it's omitted. Returns the previous seed.
+			//
+			// ```ruby
+			// srand(1234)
+			// a = rand(100)
+			// srand(1234) # => 1234
+			// a == rand(100) # => true
+			// ```
+			//
+			// @return [Integer]
+			Name: "srand",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					seed, err := randomSeed(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					t.vm.Lock()
+					previous := t.vm.randomGenerator.seed
+					t.vm.randomGenerator = t.vm.initRandomObject(seed)
+					t.vm.Unlock()
+
+					return t.vm.initIntegerObject(int(previous))
+				}
+			},
+		},
+		{
+			// Suspends the current thread for duration (sec), which can be an Integer or a Float.
+			//
+			// **Note:** currently, parameter cannot be omitted.
+			//
+			// ```ruby
+			// a = sleep(2)
+			// puts(a)     # => 2
+			// sleep(0.5)  # => 1
+			// ```
+			//
+			// @param sec [Integer/Float] time to wait in sec
+			// @return [Integer] actual time slept in sec, which is rounded
+			Name: "sleep",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					var seconds float64
+
+					switch sec := args[0].(type) {
+					case *IntegerObject:
+						seconds = float64(sec.value)
+					case *FloatObject:
+						seconds = sec.value
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", args[0].Class().Name)
+					}
+
+					if seconds < 0 {
+						return t.initErrorObject(errors.ArgumentError, "Time interval must not be negative. got: %s", args[0].toString())
+					}
+
+					start := time.Now()
+
+					select {
+					case <-time.After(time.Duration(seconds * float64(time.Second))):
+					case <-t.timedOut():
+						return t.timeoutError()
+					}
+
+					return t.vm.initIntegerObject(int(math.Round(time.Since(start).Seconds())))
+				}
+			},
+		},
+		{
+			// Stops the program by raising a SystemExit with the given status, after running the at_exit blocks.
+			// The status can be an Integer, or `true` (0) and `false` (1), and it's 0 by default. The SystemExit
+			// can be rescued with `rescue SystemExit`.
+			//
+			// ```ruby
+			// exit      # the program exits with 0
+			// exit(2)   # the program exits with 2
+			// exit(false)
+			//
+			// begin
+			//   exit(3)
+			// rescue SystemExit => e
+			//   e.status # => 3
+			// end
+			// ```
+			//
+			// @param status [Integer/Boolean]
+			// @return [SystemExit]
+			Name: "exit",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					status := 0
+
+					if len(args) == 1 {
+						switch s := args[0].(type) {
+						case *IntegerObject:
+							status = s.value
+						case *BooleanObject:
+							if !s.value {
+								status = 1
+							}
+						default:
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Boolean", args[0].Class().Name)
+						}
+					}
+
+					return t.initSystemExit(status, "exit")
+				}
+			},
+		},
+		{
+			// Writes the message into `$stderr` and stops the program with status 1, like `exit(false)`.
+			//
+			// ```ruby
+			// abort("Config file is missing") if config.nil?
+			// ```
+			//
+			// @param message [String]
+			// @return [SystemExit]
+			Name: "abort",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					switch len(args) {
+					case 0:
+						return t.initSystemExit(1, "exit")
+					case 1:
+						message, ok := args[0].(*StringObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						}
+
+						fmt.Fprintln(t.vm.stderr(), message.value)
+
+						return t.initSystemExit(1, message.value)
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+				}
+			},
+		},
+		{
+			// Runs the command and waits for it to finish. The command's output goes to `$stdout` and `$stderr`.
+			// Returns true if the command succeeded, false if it failed, or nil if it couldn't be run.
+			// `$?` is set to the command's `Process::Status`.
+			//
+			// A single command line is run by `sh -c`, and multiple arguments are run as a program and its
+			// arguments without a shell. The options `chdir:` and `env:` set the working directory and
+			// additional environment variables.
+			//
+			// ```ruby
+			// system("echo hello")                        # => true
+			// system("ls", "no such file")                # => false
+			// system("no-such-program", "foo")            # => nil
+			// system("make", chdir: "src", env: { CC: "clang" })
+			// ```
+			//
+			// @param command [String]..., options [Hash]
+			// @return [Boolean]
+			Name: "system",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					cmd, err := t.newCommand(args)
+					if err != nil {
+						return err
+					}
+
+					cmd.Stdin = t.vm.stdin().File
+					cmd.Stdout = t.vm.stdout()
+					cmd.Stderr = t.vm.stderr()
+
+					if t.startCommand(cmd) != nil {
+						return NULL
+					}
+
+					if err := t.waitCommand(cmd); err != nil {
+						return err
+					}
+
+					return toBooleanObject(cmd.ProcessState.Success())
+				}
+			},
+		},
+		{
+			// Runs the command by `sh -c` and returns its stdout as a String. Commands quoted by backticks call this
+			// method, and they can have interpolations like double-quoted strings. `$?` is set to the command's
+			// `Process::Status`.
+			//
+			// ```ruby
+			// branch = `git rev-parse --abbrev-ref HEAD`.chomp
+			// `ls #{dir} | wc -l`
+			// $?.success? # => true
+			// ```
+			//
+			// @param command [String]
+			// @return [String]
+			Name: "`",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					cmd, err := t.newCommand(args)
+					if err != nil {
+						return err
+					}
+
+					var out bytes.Buffer
+					cmd.Stdout = &out
+					cmd.Stderr = t.vm.stderr()
+
+					if err := t.startCommand(cmd); err != nil {
+						return err
+					}
+
+					if err := t.waitCommand(cmd); err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(out.String())
+				}
+			},
+		},
+		{
+			// Starts the command in the background and returns a `Process::Child` without waiting for it.
+			// The command's stdout and stderr are captured, and can be read from the child after it finishes.
+			// It takes the same arguments and options as `system`.
+			//
+			// ```ruby
+			// child = spawn("go", "test", "./...", chdir: "src")
+			// child.pid    # => 1234
+			// status = child.wait
+			// status.success?
+			// child.stdout # => "ok  ..."
+			// ```
+			//
+			// @param command [String]..., options [Hash]
+			// @return [Process::Child]
+			Name: "spawn",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					cmd, err := t.newCommand(args)
+					if err != nil {
+						return err
+					}
+
+					return t.spawnCommand(cmd)
+				}
+			},
+		},
+		{
+			// Registers the block to be run when the program ends, including when it's stopped by an uncaught
+			// error. The blocks are run in reverse order of registration.
+			//
+			// ```ruby
+			// at_exit do
+			//   puts("bye")
+			// end
+			//
+			// at_exit do
+			//   puts("see you")
+			// end
+			//
+			// puts("hello")
+			// # => hello
+			// # => see you
+			// # => bye
+			// ```
+			//
+			// @param block [Block]
+			// @return [Proc]
+			Name: "at_exit",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					p := t.initProcFromBlock(blockFrame, false)
+
+					t.vm.Lock()
+					t.vm.atExitBlocks = append(t.vm.atExitBlocks, blockFrame)
+					t.vm.Unlock()
+
+					return p
+				}
+			},
+		},
+		{
+			// Returns object's string representation.
+			// @param n/a []
+			// @return [String] Object's string representation.
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					s, err := t.builtinString(receiver)
+					if err != nil {
+						return err
+					}
+					return t.vm.initStringObject(s)
+				}
+			},
+		},
+		{
+			// Returns a string that describes the object, it's used by the REPL and by Array#to_s and Hash#to_s to
+			// format elements. Strings are quoted, and objects whose class defines `to_s` are formatted with it.
+			//
+			// ```ruby
+			// "foo".inspect      # => "\"foo\""
+			// [1, "a"].inspect   # => "[1, \"a\"]"
 			// ```
 			//
-			// @return [@boolean]
-			Name: "!=",
+			// @return [String]
+			Name: "inspect",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					className := receiver.Class().Name
-					compareClassName := args[0].Class().Name
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
 
-					if className == compareClassName && reflect.DeepEqual(receiver, args[0]) {
-						return FALSE
+					s, err := t.builtinInspect(receiver)
+					if err != nil {
+						return err
 					}
-					return TRUE
+					return t.vm.initStringObject(s)
 				}
 			},
 		},
 		{
-			// Loads the given Goby library name without extension (mainly for modules), returning `true`
-			// if successful and `false` if the feature is already loaded.
+			// Returns true if a block is given to the current method and `yield` is ready to call.
+			// It returns false outside of methods.
 			//
-			// Currently, only the following embedded Goby libraries are targeted:
+			// ```ruby
+			// class File
+			//   def self.open(filename, mode, perm)
+			//     file = new(filename, mode, perm)
 			//
-			// - "file"
-			// - "net/http"
-			// - "net/simple_server"
-			// - "uri"
+			//     if block_given?
+			//       yield(file)
+			//     end
+			//
+			//     file.close
+			//   end
+			// end
+			// ```
+			//
+			// @param n/a []
+			// @return [Boolean] true/false
+			Name: "block_given?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					cf := t.callFrameStack.top().methodFrame()
+
+					return toBooleanObject(cf != nil && cf.blockFrame != nil)
+				}
+			},
+		},
+		{
+			// Returns a new lambda from the given block. A lambda can also be created with the `->` literal.
 			//
 			// ```ruby
-			// require("file")
-			// File.extname("foo.rb")
+			// double = lambda do |x|
+			//   x * 2
+			// end
+			// double.call(2) # => 4
+			//
+			// triple = ->(x) { x * 3 }
+			// triple.call(2) # => 6
 			// ```
 			//
-			// TBD: the load paths for `require`
+			// @return [Proc]
+			Name: "lambda",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.initProcFromBlock(blockFrame, true)
+				}
+			},
+		},
+		{
+			// Executes the block repeatedly until `break` is called in it, and returns break's value.
 			//
-			// @param filename [String] Quoted file name of the library, without extension
-			// @return [Boolean] Result of loading module
-			Name: "require",
+			// ```ruby
+			// i = 0
+			// result = loop do
+			//   i += 1
+			//   break i * 10 if i == 3
+			// end
+			// result # => 30
+			// ```
+			//
+			// @return [Object]
+			Name: "loop",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					libName := args[0].(*StringObject).value
-					initFunc, ok := standardLibraries[libName]
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
 
-					if !ok {
-						return t.vm.initErrorObject(errors.InternalError, "Can't require \"%s\"", libName)
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
 					}
 
-					initFunc(t.vm)
+					for {
+						result := t.builtinMethodYield(blockFrame)
 
-					return TRUE
+						if err, ok := result.Target.(*Error); ok && !err.rescued {
+							return err
+						}
+					}
 				}
 			},
 		},
 		{
-			// Loads the Goby library (mainly for modules) from the given local path plus name
-			// without extension from the current directory, returning `true` if successful,
-			// and `false` if the feature is already loaded.
+			// Yields self to the block and returns self, which is useful for inspecting or configuring an object
+			// in the middle of a method chain.
 			//
 			// ```ruby
-			// require_relative("../test_fixtures/require_test/foo")
-			// fifty = Foo.bar(5)
+			// [3, 1, 2].sort.tap do |a|
+			//   puts(a.to_s) # => [1, 2, 3]
+			// end.map do |i|
+			//   i * 2
+			// end # => [2, 4, 6]
 			// ```
 			//
-			// @param path/name [String] Quoted file path to library plus name, without extension
-			// @return [Boolean] Result of loading module
-			Name: "require_relative",
+			// @return [Object]
+			Name: "tap",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					callerDir := path.Dir(t.vm.currentFilePath())
-					filepath := args[0].(*StringObject).value
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
 
-					filepath = path.Join(callerDir, filepath)
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
 
-					file, err := ioutil.ReadFile(filepath + ".gb")
+					result := t.builtinMethodYield(blockFrame, receiver)
 
-					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+					if err, ok := result.Target.(*Error); ok && !err.rescued {
+						return err
 					}
 
-					t.vm.execRequiredFile(filepath, file)
-
-					return TRUE
+					return receiver
 				}
 			},
 		},
 		{
-			// Puts string literals or objects into stdout with a tailing line feed, converting into String
-			// if needed.
+			// Yields self to the block and returns the block's result, so a value can be piped through a chain
+			// of blocks. Without a block, an Enumerator is returned.
 			//
 			// ```ruby
-			// puts("foo", "bar")
-			// # => foo
-			// # => bar
-			// puts("baz", String.name)
-			// # => baz
-			// # => String
-			// puts("foo" + "bar")
-			// # => foobar
+			// 5.then do |i|
+			//   i * 2
+			// end.then do |i|
+			//   i + 1
+			// end # => 11
 			// ```
-			// TODO: interpolation is needed to be implemented.
 			//
-			// @param *args [Class] String literals, or other objects that can be converted into String.
-			// @return [Null]
-			Name: "puts",
+			// @return [Object]
+			Name: "then",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
 
-					for _, arg := range args {
-						fmt.Println(arg.toString())
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "then", args)
 					}
 
-					return NULL
+					return t.builtinMethodYield(blockFrame, receiver).Target
 				}
 			},
 		},
 		{
-			// Returns the class of the object. Receiver cannot be omitted.
-			//
-			// FYI: You can convert the class into String with `#name`.
-			//
-			// ```ruby
-			// puts(100.class)         # => <Class:Integer>
-			// puts(100.class.name)    # => Integer
-			// puts("123".class)       # => <Class:String>
-			// puts("123".class.name)  # => String
-			// ```
+			// Same as `then`.
 			//
-			// @param object [Object] Receiver (required)
-			// @return [Class] The class of the receiver
-			Name: "class",
+			// @return [Object]
+			Name: "yield_self",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
 
-					switch r := receiver.(type) {
-					case Object:
-						return r.Class()
-					default:
-						return &Error{Message: "Can't call class on %T" + string(r.Class().ReturnName())}
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "yield_self", args)
 					}
+
+					return t.builtinMethodYield(blockFrame, receiver).Target
 				}
 			},
 		},
 		{
-			// Inverts the boolean value.
+			// Runs the block on a new thread, same as `Thread.new`.
 			//
 			// ```ruby
-			// !true  # => false
-			// !false # => true
+			// t = thread do
+			//   1 + 1
+			// end
+			// t.value # => 2
 			// ```
 			//
-			// @param object [Object] object that return boolean value to invert
-			// @return [Object] Inverted boolean value
-			Name: "!",
+			// @return [Thread]
+			Name: "thread",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
 
-					return FALSE
+					return t.startThread(blockFrame, args)
 				}
 			},
 		},
 		{
-			// Suspends the current thread for duration (sec).
-			//
-			// **Note:** currently, parameter cannot be omitted, and only Integer can be specified.
+			// Mixes the module's methods into the object's singleton class, so they are only available to the object.
+			// When the receiver is a class, the methods become its class methods.
 			//
 			// ```ruby
-			// a = sleep(2)
-			// puts(a)     # => 2
+			// module Loud
+			//   def shout
+			//     to_s + "!"
+			//   end
+			// end
+			//
+			// s = "hi"
+			// s.extend(Loud)
+			// s.shout      # => "hi!"
+			// "hey".shout  # => UndefinedMethodError
+			//
+			// class Foo
+			//   extend Loud
+			// end
+			//
+			// Foo.shout # => "Foo!"
 			// ```
 			//
-			// @param sec [Integer] time to wait in sec
-			// @return [Integer] actual time slept in sec
-			Name: "sleep",
+			// @param module [Class] Module to extend
+			// @return [Object] The receiver
+			Name: "extend",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					module, ok := args[0].(*RClass)
+
+					if !ok || !module.isModule {
+						return t.initErrorObject(errors.TypeError, "Expect argument to be a module. got=%v", args[0].Class().Name)
 					}
 
-					int := args[0].(*IntegerObject)
-					seconds := int.value
-					time.Sleep(time.Duration(seconds) * time.Second)
-					return int
+					t.vm.singletonClassOf(receiver).include(module)
+
+					return receiver
 				}
 			},
 		},
 		{
-			// Returns object's string representation.
+			// Returns true if Object class is equal to the input argument class
+			//
+			// ```ruby
+			// "Hello".is_a?(String)            # => true
+			// 123.is_a?(Integer)               # => true
+			// [1, true, "String"].is_a?(Array) # => true
+			// { a: 1, b: 2 }.is_a?(Hash)       # => true
+			// "Hello".is_a?(Integer)           # => false
+			// 123.is_a?(Range)                 # => false
+			// (2..4).is_a?(Hash)               # => false
+			// nil.is_a?(Integer)               # => false
+			// ```
+			//
 			// @param n/a []
-			// @return [String] Object's string representation.
-			Name: "to_s",
+			// @return [Boolean]
+			Name: "is_a?",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					return t.vm.initStringObject(receiver.toString())
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					c := args[0]
+					gobyClass, ok := c.(*RClass)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ClassClass, c.Class().Name)
+					}
+
+					receiverClass := receiver.Class()
+
+					for {
+						if receiverClass.Name == gobyClass.Name {
+							return TRUE
+						}
+
+						if receiverClass.Name == classes.ObjectClass {
+							break
+						}
+
+						receiverClass = receiverClass.superClass
+					}
+					return FALSE
 				}
 			},
 		},
 		{
-			// Returns true if a block is given in the current context and `yield` is ready to call.
-			//
-			// **Note:** The method name does not end with '?' because the sign is unavalable in Goby for now.
+			// Returns true if Object is nil
 			//
 			// ```ruby
-			// class File
-			//   def self.open(filename, mode, perm)
-			//     file = new(filename, mode, perm)
-			//
-			//     if block_given?
-			//       yield(file)
-			//     end
-			//
-			//     file.close
-			//   end
-			// end
+			// 123.nil?            # => false
+			// "String".nil?       # => false
+			// { a: 1, b: 2 }.nil? # => false
+			// (3..5).nil?         # => false
+			// nil.nil?            # => true  (See the implementation of Null#nil? in vm/null.go file)
 			// ```
 			//
 			// @param n/a []
-			// @return [Boolean] true/false
-			Name: "block_given?",
+			// @return [Boolean]
+			Name: "nil?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+					return FALSE
+				}
+			},
+		},
+		{
+			Name: "instance_variable_get",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					cf := t.callFrameStack.top()
+					arg, isStr := stringOrSymbol(args[0])
 
-					if cf.blockFrame == nil {
-						return FALSE
+					if !isStr {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					return TRUE
+					obj, ok := receiver.instanceVariableGet(arg)
+
+					if !ok {
+						return NULL
+					}
+
+					return obj
 				}
 			},
 		},
 		{
-			Name: "thread",
+			Name: "instance_variable_set",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if blockFrame == nil {
-						t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
 					}
 
-					newT := t.vm.newThread()
+					argName, isStr := stringOrSymbol(args[0])
+					obj := args[1]
 
-					go func() {
-						newT.builtinMethodYield(blockFrame, args...)
-					}()
+					if !isStr {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
 
-					// We need to pop this frame from main thread manually,
-					// because the block's 'leave' instruction is running on other process
-					t.callFrameStack.pop()
+					receiver.instanceVariableSet(argName, obj)
 
-					return NULL
+					return obj
 				}
 			},
 		},
 		{
-			// Returns true if Object class is equal to the input argument class
+			// Returns the object's method with the given name as a Method, which remembers the object and can
+			// be called later. Private methods can also be returned.
 			//
 			// ```ruby
-			// "Hello".is_a?(String)            # => true
-			// 123.is_a?(Integer)               # => true
-			// [1, true, "String"].is_a?(Array) # => true
-			// { a: 1, b: 2 }.is_a?(Hash)       # => true
-			// "Hello".is_a?(Integer)           # => false
-			// 123.is_a?(Range)                 # => false
-			// (2..4).is_a?(Hash)               # => false
-			// nil.is_a?(Integer)               # => false
+			// m = [1, 2].method(:push)
+			// m.call(3)
+			// m.receiver # => [1, 2, 3]
+			//
+			// 5.method(:+).call(2) # => 7
+			//
+			// 1.method(:foo) # => UndefinedMethodError
 			// ```
 			//
-			// @param n/a []
-			// @return [Boolean]
-			Name: "is_a?",
+			// @param name [String/Symbol]
+			// @return [Method]
+			Name: "method",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
-					c := args[0]
-					gobyClass, ok := c.(*RClass)
+					name, ok := stringOrSymbol(args[0])
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ClassClass, c.Class().Name)
+						return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", args[0].Class().Name)
 					}
 
-					receiverClass := receiver.Class()
-
-					for {
-						if receiverClass.Name == gobyClass.Name {
-							return TRUE
-						}
-
-						if receiverClass.Name == classes.ObjectClass {
-							break
-						}
+					method, owner := findMethodOwner(receiver, name)
 
-						receiverClass = receiverClass.superClass
+					if method == nil {
+						return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", name, receiver.toString())
 					}
-					return FALSE
+
+					return t.vm.initBoundMethodObject(name, owner, method, receiver)
 				}
 			},
 		},
 		{
-			// Returns true if Object is nil
+			// Returns the names of the methods the object responds to, including the ones of its singleton class.
 			//
 			// ```ruby
-			// 123.nil?            # => false
-			// "String".nil?       # => false
-			// { a: 1, b: 2 }.nil? # => false
-			// (3..5).nil?         # => false
-			// nil.nil?            # => true  (See the implementation of Null#nil? in vm/null.go file)
+			// class Foo
+			//   def bar; end
+			// end
+			//
+			// Foo.new.methods # => [:!, :!=, :==, ..., :bar, ...]
 			// ```
 			//
-			// @param n/a []
-			// @return [Boolean]
-			Name: "nil?",
+			// @return [Array]
+			Name: "methods",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
-					return FALSE
+
+					return t.vm.initSymbolArray(objectMethodNames(receiver))
 				}
 			},
 		},
 		{
-			Name: "instance_variable_get",
+			// Returns true if the object has a public method with the given name, which is looked up the same way as
+			// calling the method.
+			//
+			// ```ruby
+			// [1, 2].respond_to?(:push)  # => true
+			// [1, 2].respond_to?("fly")  # => false
+			// 5.respond_to?(:+)          # => true
+			// ```
+			//
+			// @param name [String/Symbol]
+			// @return [Boolean]
+			Name: "respond_to?",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					arg, isStr := args[0].(*StringObject)
-
-					if !isStr {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
-					obj, ok := receiver.instanceVariableGet(arg.value)
+					name, ok := stringOrSymbol(args[0])
 
 					if !ok {
-						return NULL
+						return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", args[0].Class().Name)
 					}
 
-					return obj
+					method := receiver.findMethod(name)
+
+					if method == nil || methodVisibility(method) != bytecode.Public {
+						return FALSE
+					}
+
+					return TRUE
 				}
 			},
 		},
 		{
-			Name: "instance_variable_set",
+			// Calls the public method with the given name, the rest of arguments and the block are passed to the method.
+			// Unlike `send`, it returns an error if the method is private or protected.
+			//
+			// ```ruby
+			// [1, 2].public_send(:push, 3) # => [1, 2, 3]
+			// ```
+			//
+			// @param name [String/Symbol], *args [Object]
+			// @return [Object]
+			Name: "public_send",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
-					}
-
-					argName, isStr := args[0].(*StringObject)
-					obj := args[1]
-
-					if !isStr {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
-					}
-
-					receiver.instanceVariableSet(argName.value, obj)
-
-					return obj
+					return sendByName(t, receiver, args, blockFrame, true)
+				}
+			},
+		},
+		{
+			// Calls the method with the given name, the rest of arguments and the block are passed to the method.
+			//
+			// ```ruby
+			// [1, 2].send("push", 3) # => [1, 2, 3]
+			// 10.send(:+, 5)         # => 15
+			// [1, 2].send(:[], 1)    # => 2
+			//
+			// [1, 2].send(:map) do |i|
+			//   i * 2
+			// end # => [2, 4]
+			// ```
+			//
+			// @param name [String/Symbol], *args [Object]
+			// @return [Object]
+			Name: "send",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return sendByName(t, receiver, args, blockFrame, false)
 				}
 			},
 		},
@@ -841,6 +2820,22 @@ func (vm *VM) createRClass(className string) *RClass {
 	}
 }
 
+// singletonClassOf returns the object's singleton class, and creates it if the object doesn't have one yet.
+// The object's class is the singleton class' superclass, so the methods defined in it take precedence.
+func (vm *VM) singletonClassOf(obj Object) *RClass {
+	if c := obj.SingletonClass(); c != nil {
+		return c
+	}
+
+	c := vm.createRClass(fmt.Sprintf("#<Class:#<%s:%d>>", obj.Class().Name, obj.id()))
+	c.superClass = obj.Class()
+	c.pseudoSuperClass = obj.Class()
+	c.isSingleton = true
+	obj.SetSingletonClass(c)
+
+	return c
+}
+
 func initClassClass() *RClass {
 	classClass := &RClass{
 		Name:      classes.ClassClass,
@@ -921,6 +2916,33 @@ func (c *RClass) inherits(sc *RClass) {
 	c.singletonClass.pseudoSuperClass = sc.singletonClass
 }
 
+// include inserts module into the class's method lookup chain, right above the class itself.
+// What gets inserted is a proxy class that shares the module's methods and constants, so a module
+// can be included by many classes without linking their lookup chains together.
+func (c *RClass) include(module *RClass) {
+	if c.alreadyInherit(module) {
+		return
+	}
+
+	c.superClass = &RClass{
+		Name:             module.Name,
+		Methods:          module.Methods,
+		pseudoSuperClass: module.pseudoSuperClass,
+		superClass:       c.superClass,
+		isModule:         true,
+		constants:        module.constants,
+		scope:            module.scope,
+		includedModule:   module,
+		baseObj:          module.baseObj,
+	}
+}
+
+// defineModuleFunction defines the method as a private instance method and a public singleton method of the module
+func (c *RClass) defineModuleFunction(name string, method Object) {
+	c.Methods.set(name, withVisibility(method, bytecode.Private))
+	c.SingletonClass().Methods.set(name, withVisibility(method, bytecode.Public))
+}
+
 func (c *RClass) setBuiltinMethods(methodList []*BuiltinMethodObject, classMethods bool) {
 	for _, m := range methodList {
 		c.Methods.set(m.Name, m)
@@ -961,6 +2983,81 @@ func (c *RClass) lookupMethod(methodName string) Object {
 	return method
 }
 
+// lookupMethodOwner is like lookupMethod, but it also returns the class or module that defines the method
+func (c *RClass) lookupMethodOwner(methodName string) (Object, *RClass) {
+	for class := c; class != nil; class = class.superClass {
+		if method, ok := class.Methods.get(methodName); ok {
+			if class.includedModule != nil {
+				return method, class.includedModule
+			}
+
+			return method, class
+		}
+
+		if class.superClass == class || class.Name == classes.ClassClass {
+			break
+		}
+	}
+
+	return nil, nil
+}
+
+// ancestors returns the classes and modules in the class's method lookup chain in the order lookupMethod searches
+// them, with included modules instead of their proxy classes. A module's ancestors are itself and the modules it
+// includes, and a singleton class's ancestors end with Class, where the lookup for class methods stops.
+func (c *RClass) ancestors() []*RClass {
+	result := []*RClass{}
+
+	for class := c; class != nil; class = class.superClass {
+		if c.isModule && !class.isModule {
+			break
+		}
+
+		if class.includedModule != nil {
+			result = append(result, class.includedModule)
+		} else {
+			result = append(result, class)
+		}
+
+		if class.superClass == class || (class.Name == classes.ClassClass && class != c) {
+			break
+		}
+	}
+
+	return result
+}
+
+// methodNames returns the names of the non-private methods defined in the class, and the ones can be found in its
+// superclasses by lookupMethod if inherited is true
+func (c *RClass) methodNames(inherited bool) []string {
+	found := map[string]bool{}
+	c.collectMethodNames(inherited, map[string]bool{}, found)
+
+	return sortedNames(found)
+}
+
+// collectMethodNames adds the names of non-private methods to found. A method that's already seen in a
+// subclass hides the superclass' one with the same name, just like lookupMethod.
+func (c *RClass) collectMethodNames(inherited bool, seen, found map[string]bool) {
+	for class := c; class != nil; class = class.superClass {
+		for name, method := range class.Methods.store {
+			if seen[name] {
+				continue
+			}
+
+			seen[name] = true
+
+			if methodVisibility(method) != bytecode.Private {
+				found[name] = true
+			}
+		}
+
+		if !inherited || class.superClass == class || class.Name == classes.ClassClass {
+			break
+		}
+	}
+}
+
 func (c *RClass) lookupConstant(constName string, findInScope bool) *Pointer {
 	constant, ok := c.constants[constName]
 
@@ -979,6 +3076,92 @@ func (c *RClass) lookupConstant(constName string, findInScope bool) *Pointer {
 	return constant
 }
 
+// constantNames returns the sorted names of the class's constants, and the ones of its superclasses and included
+// modules if inherited is true. Like lookupConstant, the constants of Object are only included for Object itself.
+func (c *RClass) constantNames(inherited bool) []string {
+	found := map[string]bool{}
+
+	for class := c; class != nil; class = class.superClass {
+		for name := range class.constants {
+			found[name] = true
+		}
+
+		if !inherited || class.superClass == class || class.superClass == nil || class.superClass.Name == classes.ObjectClass {
+			break
+		}
+	}
+
+	return sortedNames(found)
+}
+
+// classVariableOwner returns the class or module that defines the class variable in the class's lookup chain,
+// or nil if it's not defined.
+func (c *RClass) classVariableOwner(name string) *RClass {
+	for class := c; class != nil; class = class.superClass {
+		owner := class
+		if class.includedModule != nil {
+			owner = class.includedModule
+		}
+
+		if _, ok := owner.classVariables[name]; ok {
+			return owner
+		}
+
+		if class.superClass == class {
+			break
+		}
+	}
+
+	return nil
+}
+
+// classVariable returns the value of the class variable from the class's lookup chain
+func (c *RClass) classVariable(name string) (Object, bool) {
+	owner := c.classVariableOwner(name)
+	if owner == nil {
+		return nil, false
+	}
+
+	return owner.classVariables[name], true
+}
+
+// setClassVariable assigns the class variable in the class or module that already defines it, or defines it in
+// the class itself.
+func (c *RClass) setClassVariable(name string, value Object) {
+	owner := c.classVariableOwner(name)
+	if owner == nil {
+		owner = c
+	}
+
+	if owner.classVariables == nil {
+		owner.classVariables = map[string]Object{}
+	}
+
+	owner.classVariables[name] = value
+}
+
+// classVariableNames returns the sorted names of the class variables in the class's lookup chain
+func (c *RClass) classVariableNames() []string {
+	found := map[string]bool{}
+
+	for class := c; class != nil; class = class.superClass {
+		owner := class
+		if class.includedModule != nil {
+			owner = class.includedModule
+		}
+
+		for name := range owner.classVariables {
+			found[name] = true
+		}
+
+		if class.superClass == class {
+			break
+		}
+	}
+
+	return sortedNames(found)
+}
+
 func (c *RClass) setClassConstant(constant *RClass) {
 	c.constants[constant.Name] = &Pointer{Target: constant}
 }
@@ -995,7 +3178,7 @@ func (c *RClass) getClassConstant(constName string) (class *RClass) {
 }
 
 func (c *RClass) alreadyInherit(constant *RClass) bool {
-	if c.superClass == constant {
+	if c.superClass == constant || c.superClass.includedModule == constant {
 		return true
 	}
 
@@ -1056,11 +3239,246 @@ func (c *RClass) setAttrAccessor(args interface{}) {
 
 // Other helper functions -----------------------------------------------
 
+// attrNames converts the String or Symbol arguments of attr_reader, attr_writer and attr_accessor into attribute names
+func attrNames(t *thread, args []Object) ([]string, *Error) {
+	names := []string{}
+
+	for _, arg := range args {
+		name, ok := stringOrSymbol(arg)
+
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, "Expect attribute name to be String or Symbol. got: %s", arg.Class().Name)
+		}
+
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// objectMethodNames returns the names of the methods the object responds to, which are collected from
+// the classes findMethod looks up
+func objectMethodNames(obj Object) []string {
+	var classes []*RClass
+
+	switch o := obj.(type) {
+	case *RClass:
+		if o.isSingleton {
+			classes = append(classes, o.superClass)
+		} else {
+			classes = append(classes, o.SingletonClass())
+		}
+	default:
+		if o.SingletonClass() != nil {
+			classes = append(classes, o.SingletonClass())
+		}
+
+		classes = append(classes, o.Class())
+	}
+
+	seen := map[string]bool{}
+	found := map[string]bool{}
+
+	for _, class := range classes {
+		class.collectMethodNames(true, seen, found)
+	}
+
+	return sortedNames(found)
+}
+
+func sortedNames(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+
+	for name := range set {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// initSymbolArray returns an Array of the Symbols with the given names
+func (vm *VM) initSymbolArray(names []string) *ArrayObject {
+	elems := make([]Object, len(names))
+
+	for i, name := range names {
+		elems[i] = vm.initSymbolObject(name)
+	}
+
+	return vm.initArrayObject(elems)
+}
+
+// classVariableNameArgument returns the class variable name given as the only argument, which must be a String or
+// a Symbol like `:@@foo`
+func (t *thread) classVariableNameArgument(args []Object) (string, *Error) {
+	if len(args) != 1 {
+		return "", t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	name, ok := stringOrSymbol(args[0])
+	if !ok {
+		return "", t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	if !strings.HasPrefix(name, "@@") || len(name) == 2 {
+		return "", t.initErrorObject(errors.NameError, "'%s' is not allowed as a class variable name", name)
+	}
+
+	return name, nil
+}
+
+// isConstantName returns true if the name starts with an uppercase letter and contains only letters, digits and
+// underscores
+func isConstantName(name string) bool {
+	for i, r := range name {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+
+	return name != ""
+}
+
+// qualifiedConstantName returns the name of the constant in the namespace, like "Foo::Bar"
+func qualifiedConstantName(namespace *RClass, name string) string {
+	if namespace.Name == classes.ObjectClass {
+		return name
+	}
+
+	return namespace.Name + "::" + name
+}
+
+// sendByName calls the receiver's method named by the first argument with the rest of arguments and the block
+func sendByName(t *thread, receiver Object, args []Object, blockFrame *callFrame, publicOnly bool) Object {
+	if len(args) < 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got: %d", len(args))
+	}
+
+	name, ok := stringOrSymbol(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", args[0].Class().Name)
+	}
+
+	if publicOnly {
+		if method := receiver.findMethod(name); method != nil {
+			if err := t.checkVisibility(nil, receiver, method, name); err != nil {
+				return err
+			}
+		}
+	}
+
+	return t.sendMethodWithBlock(name, receiver, blockFrame, args[1:]...)
+}
+
+// setMethodVisibility changes the visibility of the named methods in the class. Inherited methods are copied into
+// the class, so the superclass isn't affected. Without names it only returns nil, since the compiler applies the
+// visibility to the methods defined after the call.
+func setMethodVisibility(t *thread, receiver Object, args []Object, visibility string) Object {
+	if len(args) == 0 {
+		return NULL
+	}
+
+	c, ok := receiver.(*RClass)
+
+	if !ok {
+		return t.unsupportedMethodError("#"+visibility, receiver)
+	}
+
+	for _, arg := range args {
+		name, ok := stringOrSymbol(arg)
+
+		if !ok {
+			return t.initErrorObject(errors.TypeError, "Expect method name to be String or Symbol. got: %s", arg.Class().Name)
+		}
+
+		method := c.lookupMethod(name)
+
+		if method == nil {
+			return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%s' for %s", name, c.toString())
+		}
+
+		c.Methods.set(name, withVisibility(method, visibility))
+	}
+
+	if len(args) == 1 {
+		return args[0]
+	}
+
+	return t.vm.initArrayObject(args)
+}
+
+// generateBlockMethod returns the method defined by define_method, which executes the block with the receiver as self
+// copyObject returns the shallow copy of `dup` and `clone`, which gets the instance variables of the receiver and
+// is passed to `initialize_copy` if it's defined. The copy of `clone` also gets the receiver's singleton methods
+// and frozen state. Objects that are always frozen, like Integers, are returned as they are.
+func copyObject(t *thread, receiver Object, clone bool) Object {
+	var c Object
+
+	switch r := receiver.(type) {
+	case *IntegerObject, *FloatObject, *BigIntObject, *RationalObject, *SymbolObject, *BooleanObject, *NullObject:
+		return receiver
+	case *RObject:
+		c = &RObject{baseObj: r.baseObj.copy(), InitializeMethod: r.InitializeMethod}
+	case *StringObject:
+		c = &StringObject{baseObj: r.baseObj.copy(), value: r.value, encoding: r.encoding}
+	case *ArrayObject:
+		a := r.copy().(*ArrayObject)
+		a.baseObj = r.baseObj.copy()
+		c = a
+	case *HashObject:
+		h := r.copy().(*HashObject)
+		h.baseObj = r.baseObj.copy()
+		c = h
+	default:
+		return t.initErrorObject(errors.TypeError, "Can't copy %s", receiver.Class().Name)
+	}
+
+	if singletonClass := receiver.SingletonClass(); clone && singletonClass != nil {
+		s := t.vm.singletonClassOf(c)
+		s.Methods = singletonClass.Methods.copy()
+		// The superclasses include the modules the receiver is extended with
+		s.superClass = singletonClass.superClass
+	}
+
+	if c.findMethod("initialize_copy") != nil {
+		if err, ok := t.sendMethod("initialize_copy", c, receiver).(*Error); ok {
+			return err
+		}
+	}
+
+	if clone && receiver.isFrozen() {
+		c.freeze()
+	}
+
+	return c
+}
+
+func generateBlockMethod(name string, blockFrame *callFrame) *BuiltinMethodObject {
+	return &BuiltinMethodObject{
+		Name: name,
+		Fn: func(receiver Object) builtinMethodBody {
+			return func(t *thread, args []Object, _ *callFrame) Object {
+				return t.yieldWithSelf(blockFrame, receiver, args...).Target
+			}
+		},
+	}
+}
+
 func generateAttrWriteMethod(attrName string) *BuiltinMethodObject {
 	return &BuiltinMethodObject{
 		Name: attrName + "=",
 		Fn: func(receiver Object) builtinMethodBody {
 			return func(t *thread, args []Object, blockFrame *callFrame) Object {
+				if err := t.checkFrozen(receiver); err != nil {
+					return err
+				}
+
 				v := receiver.instanceVariableSet("@"+attrName, args[0])
 				return v
 			}
@@ -1,6 +1,12 @@
 package vm
 
-import "testing"
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
 
 func TestClassClassSuperclass(t *testing.T) {
 	tests := []struct {
@@ -96,6 +102,104 @@ func TestAttrReaderAndWriter(t *testing.T) {
 	}
 }
 
+func TestDefineMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo
+		  define_method(:add) do |a, b|
+		    a + b
+		  end
+		end
+
+		Foo.new.add(1, 2)
+		`, 3},
+		{`
+		class Foo
+		  prefix = "get_"
+
+		  ["bar", "baz"].each do |name|
+		    define_method(prefix + name) do
+		      name + @suffix
+		    end
+		  end
+
+		  def initialize
+		    @suffix = "!"
+		  end
+		end
+
+		f = Foo.new
+		f.get_bar + f.get_baz
+		`, "bar!baz!"},
+		{`
+		class Foo; end
+
+		count = 0
+		increment = Proc.new do |n|
+		  count += n
+		end
+
+		Foo.define_method(:increment, increment)
+		f = Foo.new
+		f.increment(2)
+		f.increment(3)
+		count
+		`, 5},
+		{`
+		class Foo
+		  define_method("name") do
+		    self.class.name
+		  end
+		end
+
+		Foo.new.name
+		`, "Foo"},
+		{`
+		class Foo
+		  define_method(:bar) do
+		    10
+		  end
+		end
+
+		Foo.define_method(:baz) do
+		  20
+		end.to_s
+		`, "baz"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDefineMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Class.new.define_method(:bar)`, "ArgumentError: Can't define method 'bar' without a block", 1},
+		{`
+		Class.new.define_method(1) do
+		end`, "TypeError: Expect method name to be String or Symbol. got: Integer", 2},
+		{`Class.new.define_method(:bar, 1)`, "TypeError: Expect argument to be Proc. got: Integer", 1},
+		{`
+		Class.new.define_method do
+		end`, "ArgumentError: Expect 1..2 arguments. got=0", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestClassInheritModule(t *testing.T) {
 	input := `module Foo
 end
@@ -146,6 +250,254 @@ func TestClassInstanceVariable(t *testing.T) {
 	}
 }
 
+func TestClassVariable(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo
+		  @@count = 0
+
+		  def self.count
+		    @@count
+		  end
+
+		  def increment
+		    @@count += 1
+		  end
+		end
+
+		class Bar < Foo; end
+
+		Foo.new.increment
+		Bar.new.increment
+		Foo.count
+		`, 2},
+		{`
+		class Foo
+		  @@name = "foo"
+		end
+
+		class Bar < Foo
+		  @@name = "bar"
+		end
+
+		Foo.class_variable_get("@@name")
+		`, "bar"},
+		{`
+		class Foo; end
+
+		class Bar < Foo
+		  @@name = "bar"
+		end
+
+		Foo.class_variable_defined?("@@name")
+		`, false},
+		{`
+		class Foo
+		  @foo = 1
+		  @@foo = 2
+
+		  def self.foo
+		    @foo + @@foo
+		  end
+		end
+
+		Foo.foo
+		`, 3},
+		{`
+		module Counter
+		  @@count ||= 10
+
+		  def count
+		    @@count
+		  end
+		end
+
+		class Foo
+		  include Counter
+		end
+
+		Foo.new.count
+		`, 10},
+		{`
+		class Foo
+		  @@sum = 0
+
+		  [1, 2, 3].each do |i|
+		    @@sum = @@sum + i
+		  end
+
+		  def self.sum
+		    @@sum
+		  end
+		end
+
+		Foo.sum
+		`, 6},
+		{`
+		class Foo
+		  def self.bar
+		    @@bar ||= 5
+		  end
+		end
+
+		Foo.bar
+		Foo.bar
+		`, 5},
+		{`
+		class Foo
+		  @@foo = 1
+		end
+
+		class Bar < Foo
+		  @@bar = 2
+		end
+
+		Bar.class_variables.to_s
+		`, "[:@@bar, :@@foo]"},
+		{`
+		class Foo; end
+
+		Foo.class_variable_set("@@foo", 1)
+		Foo.class_variable_get("@@foo")
+		`, 1},
+		{`
+		class Foo
+		  a = (@@foo = 3)
+		  A = a
+		end
+
+		Foo::A
+		`, 3},
+		{`
+		class Foo
+		  def foo
+		    @@foo
+		  end
+		end
+
+		begin
+		  Foo.new.foo
+		rescue NameError => e
+		  e.message
+		end
+		`, "uninitialized class variable @@foo in Foo"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestClassVariableFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`class Foo; end
+		Foo.class_variable_get("@@foo")`, "NameError: uninitialized class variable @@foo in Foo", 2},
+		{`class Foo; end
+		Foo.class_variable_get(:foo)`, "NameError: 'foo' is not allowed as a class variable name", 2},
+		{`class Foo; end
+		Foo.class_variable_set("@@foo")`, "ArgumentError: Expect 2 arguments. got: 1", 2},
+		{`class Foo; end
+		Foo.class_variable_defined?(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestTopLevelClassVariableWarning(t *testing.T) {
+	f, err := ioutil.TempFile("", "goby_stderr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	v := initTestVM()
+	v.globalVariables.Store("$stderr", v.initFileObject(f))
+
+	evaluated := v.testEval(t, `@@foo = 1
+	@@foo + 1`, getFilename())
+	checkExpected(t, 0, evaluated, 2)
+
+	f.Close()
+	output, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(string(output), ":2: warning: class variable access from toplevel") {
+		t.Fatalf("Expect a warning about class variable access from toplevel. got: %q", output)
+	}
+}
+
+func TestTapAndThenMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		a = []
+		b = [3, 1, 2].sort.tap do |arr|
+		  a.push(arr.first)
+		end
+		a.to_s + b.to_s
+		`, "[1][1, 2, 3]"},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		Foo.new.tap do |f|
+		  f.bar = 10
+		end.bar
+		`, 10},
+		{`1.tap do end`, 1},
+		{`
+		5.then do |i|
+		  i * 2
+		end.then do |i|
+		  i + 1
+		end
+		`, 11},
+		{`"a".yield_self do |s| s + "b" end`, "ab"},
+		{`5.then.class.name`, "Enumerator"},
+		{`5.yield_self.to_a.to_s`, "[5]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTapAndThenMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.tap`, "InternalError: Can't yield without a block", 1},
+		{`1.tap(2) do end`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`1.then(2) do end`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
 func TestCustomClassConstructor(t *testing.T) {
 	input := `
 		class Foo
@@ -404,62 +756,165 @@ func TestClassNamespace(t *testing.T) {
 	}
 }
 
-func TestPrimitiveType(t *testing.T) {
+func TestConstantReflection(t *testing.T) {
 	tests := []struct {
 		input    string
-		expected string
+		expected interface{}
 	}{
-		{
-			`100.class.name
-			`,
-			"Integer",
-		},
-		{
-			`Integer.name
-			`,
-			"Integer",
-		},
-		{
-			`"123".class.name
-			`,
-			"String",
-		},
-		{
-			`String.name
-			`,
-			"String",
-		},
-		{
-			`true.class.name
-			`,
-			"Boolean",
-		},
-		{
-			`Boolean.name
-			`,
-			"Boolean",
-		},
-		{
-			`
-			nil.class.name
-			`,
-			"Null",
-		},
-		{
-			`
-			Integer.name
-			`,
-			"Integer",
-		},
-		{
-			`
-			Object.class.name
-			`, "Class",
-		},
-		{
-			`
-			Class.class.name
-			`, "Class",
+		{`
+		module Foo
+		  class Bar
+		    Baz = 10
+		  end
+		end
+
+		Object.const_get("Foo::Bar::Baz")
+		`, 10},
+		{`
+		module Foo
+		  Bar = 10
+		end
+
+		Foo.const_get(:Bar)
+		`, 10},
+		{`
+		module Foo
+		  Bar = 10
+		end
+
+		class Baz
+		  include Foo
+		end
+
+		Baz.const_get("Bar")
+		`, 10},
+		{`
+		class Foo; end
+
+		Foo.const_set(:Bar, 10)
+		Foo::Bar
+		`, 10},
+		{`
+		class Foo; end
+
+		Foo.const_set("Bar", "baz")
+		Foo.const_get(:Bar)
+		`, "baz"},
+		{`
+		module Foo
+		  Bar = 1
+		end
+
+		class Baz
+		  include Foo
+		  Qux = 2
+		end
+
+		Baz.constants.to_s
+		`, "[:Bar, :Qux]"},
+		{`
+		module Foo
+		  Bar = 1
+		end
+
+		class Baz
+		  include Foo
+		  Qux = 2
+		end
+
+		Baz.constants(false).to_s
+		`, "[:Qux]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestConstantReflectionFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Object.const_get("Foo")`, "NameError: uninitialized constant Foo", 1},
+		{`Object.const_get("String::Foo")`, "NameError: uninitialized constant String::Foo", 1},
+		{`Array.const_get("String", false)`, "NameError: uninitialized constant Array::String", 1},
+		{`Object.const_get("foo")`, "NameError: wrong constant name foo", 1},
+		{`Object.const_get(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Object.const_get("Foo", 1)`, "TypeError: Expect argument to be Boolean. got: Integer", 1},
+		{`Object.const_get`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`Object.const_set("foo", 1)`, "NameError: wrong constant name foo", 1},
+		{`Object.const_set("String", 1)`, "ConstantAlreadyInitializedError: Constant String already been initialized. Can't assign value to a constant twice.", 1},
+		{`Object.const_set("Foo")`, "ArgumentError: Expect 2 arguments. got: 1", 1},
+		{`Object.constants(1)`, "TypeError: Expect argument to be Boolean. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestPrimitiveType(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`100.class.name
+			`,
+			"Integer",
+		},
+		{
+			`Integer.name
+			`,
+			"Integer",
+		},
+		{
+			`"123".class.name
+			`,
+			"String",
+		},
+		{
+			`String.name
+			`,
+			"String",
+		},
+		{
+			`true.class.name
+			`,
+			"Boolean",
+		},
+		{
+			`Boolean.name
+			`,
+			"Boolean",
+		},
+		{
+			`
+			nil.class.name
+			`,
+			"Null",
+		},
+		{
+			`
+			Integer.name
+			`,
+			"Integer",
+		},
+		{
+			`
+			Object.class.name
+			`, "Class",
+		},
+		{
+			`
+			Class.class.name
+			`, "Class",
 		},
 	}
 
@@ -490,6 +945,80 @@ func TestRequireRelative(t *testing.T) {
 	v.checkSP(t, 0, 1)
 }
 
+func TestSourceLocationMethods(t *testing.T) {
+	filename := getFilename()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`__FILE__`, filename},
+		{`__dir__`, filepath.Dir(filename)},
+		{`
+		require_relative("../test_fixtures/require_test/foo")
+
+		Foo.file
+		`, filepath.Join(filepath.Dir(filepath.Dir(filename)), "test_fixtures/require_test/foo.gb")},
+		{`
+		a = 1
+		__LINE__
+		`, 3},
+		{`
+		def foo
+		  [1].map do |i|
+		    __LINE__
+		  end.first
+		end
+
+		foo
+		`, 4},
+		{`__method__`, nil},
+		{`
+		def foo
+		  __method__
+		end
+
+		foo.to_s
+		`, "foo"},
+		{`
+		class Foo
+		  def bar
+		    [1].map do |i|
+		      __method__
+		    end.first
+		  end
+		end
+
+		Foo.new.bar.to_s
+		`, "bar"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, filename)
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSourceLocationMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`__FILE__(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`__LINE__(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`__dir__(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`__method__(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestRequireStandardLibSuccess(t *testing.T) {
 	input := `
 	require "uri"
@@ -692,19 +1221,47 @@ func TestGeneralIsAMethodFail(t *testing.T) {
 	}
 }
 
-func TestClassNameClassMethod(t *testing.T) {
+func TestGeneralSendMethod(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{`Integer.name`, "Integer"},
-		{`String.name`, "String"},
-		{`Boolean.name`, "Boolean"},
-		{`Range.name`, "Range"},
-		{`Hash.name`, "Hash"},
-		{`Array.name`, "Array"},
-		{`Class.name`, "Class"},
-		{`Object.name`, "Object"},
+		{`[1, 2].send(:push, 3).to_s`, "[1, 2, 3]"},
+		{`[1, 2].send("length")`, 2},
+		{`10.send("+", 5)`, 15},
+		{`10.send(:+, 5)`, 15},
+		{`[1, 2].send(:[], 1)`, 2},
+		{`10.public_send(:<=>, 5)`, 1},
+		{`[1, 2].public_send(:push, 3).to_s`, "[1, 2, 3]"},
+		{`
+		result = [1, 2].send(:map) do |i|
+		  i * 2
+		end
+
+		result.to_s
+		`, "[2, 4]"},
+		{`
+		class Foo
+		  def bar(x, y: 2)
+		    yield(x + y)
+		  end
+		end
+
+		Foo.new.public_send(:bar, 1, y: 5) do |v|
+		  v * 10
+		end
+		`, 60},
+		{`
+		[1, 2, 3].send(:each) do |v|
+		  break v * 7 if v == 2
+		end
+		`, 14},
+		{`
+		class Foo
+		end
+
+		Foo.send(:new).class.name
+		`, "Foo"},
 	}
 
 	for i, tt := range tests {
@@ -716,13 +1273,12 @@ func TestClassNameClassMethod(t *testing.T) {
 	}
 }
 
-func TestClassNameClassMethodFail(t *testing.T) {
+func TestGeneralSendMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Taipei".name`, "UndefinedMethodError: Undefined Method 'name' for Taipei", 1},
-		{`123.name`, "UndefinedMethodError: Undefined Method 'name' for 123", 1},
-		{`true.name`, "UndefinedMethodError: Undefined Method 'name' for true", 1},
-		{`Integer.name(Integer)`, "ArgumentError: Expect 0 argument. got: 1", 1},
-		{`String.name(Hash, Array)`, "ArgumentError: Expect 0 argument. got: 2", 1},
+		{`[1, 2].send`, "ArgumentError: Expect at least 1 argument. got: 0", 1},
+		{`[1, 2].send(1)`, "TypeError: Expect method name to be String or Symbol. got: Integer", 1},
+		{`[1, 2].send(:foo)`, "UndefinedMethodError: Undefined Method 'foo' for [1, 2]", 1},
+		{`[1, 2].public_send(:foo)`, "UndefinedMethodError: Undefined Method 'foo' for [1, 2]", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -734,26 +1290,55 @@ func TestClassNameClassMethodFail(t *testing.T) {
 	}
 }
 
-func TestClassSuperclassClassMethod(t *testing.T) {
+func TestGeneralRespondToMethod(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{`Integer.superclass.name`, "Object"},
-		{`String.superclass.name`, "Object"},
-		{`Boolean.superclass.name`, "Object"},
-		{`Range.superclass.name`, "Object"},
-		{`Hash.superclass.name`, "Object"},
-		{`Array.superclass.name`, "Object"},
-		{`Object.superclass.name`, "Object"},
-		{`Class.superclass.name`, "Object"},
+		{`[1, 2].respond_to?(:push)`, true},
+		{`[1, 2].respond_to?("push")`, true},
+		{`[1, 2].respond_to?(:fly)`, false},
+		{`5.respond_to?(:+)`, true},
+		{`[1, 2].respond_to?(:[]=)`, true},
+		{`5.respond_to?(:[]=)`, false},
 		{`
-		module Bar; end
+		class Foo
+		  def bar; end
+		  def self.baz; end
+		end
+
+		Foo.new.respond_to?(:bar)
+		`, true},
+		{`
+		class Foo
+		  def self.baz; end
+		end
+
+		Foo.respond_to?(:baz)
+		`, true},
+		{`
+		class Foo
+		  def self.baz; end
+		end
+
+		Foo.new.respond_to?(:baz)
+		`, false},
+		{`
+		module Bar
+		  def bar; end
+		end
+
 		class Foo
 		  include Bar
 		end
-		Foo.superclass.name
-		`, "Object"},
+
+		Foo.new.respond_to?(:bar)
+		`, true},
+		{`
+		f = Object.new
+		def f.bar; end
+		f.respond_to?(:bar)
+		`, true},
 	}
 
 	for i, tt := range tests {
@@ -765,13 +1350,10 @@ func TestClassSuperclassClassMethod(t *testing.T) {
 	}
 }
 
-func TestClassSuperclassClassMethodFail(t *testing.T) {
+func TestGeneralRespondToMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Taipei".superclass`, "UndefinedMethodError: Undefined Method 'superclass' for Taipei", 1},
-		{`123.superclass`, "UndefinedMethodError: Undefined Method 'superclass' for 123", 1},
-		{`true.superclass`, "UndefinedMethodError: Undefined Method 'superclass' for true", 1},
-		{`Integer.superclass(Integer)`, "ArgumentError: Expect 0 argument. got: 1", 1},
-		{`String.superclass(Hash, Array)`, "ArgumentError: Expect 0 argument. got: 2", 1},
+		{`1.respond_to?`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`1.respond_to?(1)`, "TypeError: Expect method name to be String or Symbol. got: Integer", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -783,22 +1365,54 @@ func TestClassSuperclassClassMethodFail(t *testing.T) {
 	}
 }
 
-func TestClassSingletonClassClassMethod(t *testing.T) {
+func TestAncestors(t *testing.T) {
 	tests := []struct {
 		input    string
 		expected interface{}
 	}{
-		{`Integer.singleton_class.name`, "#<Class:Integer>"},
-		{`Integer.singleton_class.superclass.name`, "#<Class:Object>"},
+		{`Object.ancestors.to_s`, "[Object]"},
+		{`Class.ancestors.to_s`, "[Class, Object]"},
+		{`Array.ancestors.to_s`, "[Array, Enumerable, Object]"},
 		{`
-		class Bar; end
-		Bar.singleton_class.name
-		`, "#<Class:Bar>"},
+		module Walk; end
+		module Swim; end
+
+		class Animal
+		  include(Walk)
+		end
+
+		class Duck < Animal
+		  include(Walk)
+		  include(Swim)
+		end
+
+		Duck.ancestors.to_s
+		`, "[Duck, Swim, Animal, Walk, Object]"},
 		{`
-		class Bar; end
-		class Foo < Bar; end
-		Foo.singleton_class.superclass.name
-		`, "#<Class:Bar>"},
+		class Animal; end
+		class Dog < Animal; end
+
+		Dog.singleton_class.ancestors.to_s
+		`, "[#<Class:Dog>, #<Class:Animal>, #<Class:Object>, Class]"},
+		{`
+		module Walk; end
+		module Run
+		  include(Walk)
+		end
+
+		Run.ancestors.to_s
+		`, "[Run, Walk]"},
+		{`
+		module Walk; end
+
+		class Animal
+		  include(Walk)
+		end
+
+		class Dog < Animal; end
+
+		[Dog.include?(Walk), Dog.include?(Comparable), Walk.include?(Walk), Integer.include?(Comparable)].to_s
+		`, "[true, false, false, true]"},
 	}
 
 	for i, tt := range tests {
@@ -809,3 +1423,1110 @@ func TestClassSingletonClassClassMethod(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestAncestorsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Object.ancestors(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`Object.include?(Object)`, "TypeError: Expect argument to be a module. got: Object", 1},
+		{`Object.include?(1)`, "TypeError: Expect argument to be a module. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMethodReflection(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo
+		  def bar; end
+		  def baz; end
+		end
+
+		Foo.instance_methods(false).to_s
+		`, "[:bar, :baz]"},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		class Baz < Foo
+		  def qux; end
+		end
+
+		names = Baz.instance_methods.select do |m|
+		  m == :bar || m == :qux || m == :to_s
+		end
+		names.to_s
+		`, "[:bar, :qux, :to_s]"},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		names = Foo.new.methods.select do |m|
+		  m == :bar || m == :class
+		end
+		names.to_s
+		`, "[:bar, :class]"},
+		{`
+		class Foo
+		  def self.bar; end
+		end
+
+		names = Foo.methods.select do |m|
+		  m == :bar || m == :new
+		end
+		names.to_s
+		`, "[:bar, :new]"},
+		{`
+		class Foo
+		  def self.bar; end
+		  def self.baz; end
+		end
+
+		Foo.singleton_methods.to_s
+		`, "[:bar, :baz]"},
+		{`
+		f = Object.new
+		f.singleton_methods.length
+		`, 0},
+		{`
+		f = Object.new
+		def f.bar; end
+		f.singleton_methods.to_s
+		`, "[:bar]"},
+		{`
+		module Walk
+		  def walk; end
+		end
+
+		class Foo
+		  include(Walk)
+		  def bar; end
+
+		  private
+
+		  def baz; end
+		end
+
+		[Foo.method_defined?(:bar), Foo.method_defined?("walk"), Foo.method_defined?(:to_s), Foo.method_defined?(:baz), Foo.method_defined?(:qux)].to_s
+		`, "[true, true, true, false, false]"},
+		{`
+		module Walk
+		  def walk; end
+		end
+
+		class Foo
+		  include(Walk)
+		  def bar; end
+		end
+
+		class Baz < Foo
+		  private
+
+		  def qux; end
+		end
+
+		[Baz.instance_method(:bar).owner, Baz.instance_method("walk").owner, Baz.instance_method(:to_s).owner, Baz.instance_method(:qux).owner].to_s
+		`, "[Foo, Walk, Object, Baz]"},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		m = Foo.instance_method(:bar)
+		[m.class.name, m.name, m.to_s].to_s
+		`, `["UnboundMethod", :bar, "#<UnboundMethod: Foo#bar>"]`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMethodReflectionFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Object.instance_methods(1)`, "TypeError: Expect argument to be Boolean. got: Integer", 1},
+		{`Object.instance_method(:foo)`, "UndefinedMethodError: Undefined Method 'foo' for Object", 1},
+		{`Object.instance_method(1)`, "TypeError: Expect method name to be String or Symbol. got: Integer", 1},
+		{`Object.method_defined?`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`UnboundMethod.new`, "UnsupportedMethodError: Unsupported Method #new for UnboundMethod", 1},
+		{`Object.instance_methods(true, false)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`1.methods(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`1.singleton_methods(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralEqualityMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo
+		end
+		Foo.new == Foo.new
+		`, false},
+		{`
+		class Foo
+		end
+		f = Foo.new
+		f == f
+		`, true},
+		// eql? doesn't use == for instances of Goby classes
+		{`
+		class Foo
+		  def ==(other)
+		    other.is_a?(Foo)
+		  end
+		end
+		Foo.new.eql?(Foo.new)
+		`, false},
+		{`
+		class Foo
+		end
+		f = Foo.new
+		f.eql?(f)
+		`, true},
+		{`
+		class Foo
+		  def ==(other)
+		    other.is_a?(Foo)
+		  end
+		end
+		[Foo.new, 1] == [Foo.new, 1]
+		`, true},
+		{`[1, "a"].eql?([1, "a"])`, true},
+		{`[1, "a"].eql?([1, "b"])`, false},
+		{`1.eql?(1)`, true},
+		{`1.eql?(1.0)`, false},
+		{`1.0.eql?(1)`, false},
+		{`1.0.eql?(1.0)`, true},
+		{`[1].eql?([1.0])`, false},
+		{`[1] == [1.0]`, true},
+		{`(1..2).eql?(1..2)`, true},
+		{`(1..2).eql?(1..3)`, false},
+		{`1.hash == 1.0.hash`, false},
+		{`0.0.hash == (-0.0).hash`, true},
+		{`"foo".hash == "foo".hash`, true},
+		{`"foo".hash == "bar".hash`, false},
+		{`[1, [2, "a"]].hash == [1, [2, "a"]].hash`, true},
+		{`{ a: 1, b: 2 }.hash == { b: 2, a: 1 }.hash`, true},
+		{`1.hash.is_a?(Integer)`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralEqualityMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.eql?`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`1.hash(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralFreezeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"foo".frozen?`, false},
+		{`"foo".freeze.frozen?`, true},
+		{`[1].freeze.frozen?`, true},
+		{`1.frozen?`, true},
+		{`nil.frozen?`, true},
+		{`:foo.frozen?`, true},
+		{`
+		s = "foo".freeze
+		s.upcase
+		`, "FOO"},
+		{`
+		a = [1, 2].freeze
+		a.map do |i| i * 2 end.push(3).length
+		`, 3},
+		{`
+		class Foo
+		  attr_accessor :bar
+
+		  def set_bar
+		    @bar = 1
+		  end
+		end
+
+		f = Foo.new
+		f.bar = 2
+		f.freeze
+		begin
+		  f.set_bar
+		rescue FrozenError => e
+		  e.message
+		end
+		`, "Can't modify frozen Foo: <Instance of: Foo>"},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		f = Foo.new.freeze
+		begin
+		  f.bar = 1
+		rescue FrozenError
+		  f.bar
+		end
+		`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralFreezeMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1].freeze.push(2)`, "FrozenError: Can't modify frozen Array: [1]", 1},
+		{`{ a: 1 }.freeze["b"] = 2`, "FrozenError: Can't modify frozen Hash: { a: 1 }", 1},
+		{`{ a: 1 }.freeze.delete("a")`, "FrozenError: Can't modify frozen Hash: { a: 1 }", 1},
+		{`"foo".freeze << "bar"`, "FrozenError: Can't modify frozen String: \"foo\"", 1},
+		{`"foo".freeze.upcase!`, "FrozenError: Can't modify frozen String: \"foo\"", 1},
+		{`Object.new.freeze.instance_variable_set("@foo", 1)`, "FrozenError: Can't modify frozen Object: <Instance of: Object>", 1},
+		{`1.freeze(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`1.frozen?(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralDupAndCloneMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		a = [1, 2]
+		b = a.dup
+		b.push(3)
+		a.length
+		`, 2},
+		{`
+		h = { a: 1 }
+		h2 = h.clone
+		h2["b"] = 2
+		h.length
+		`, 1},
+		{`
+		s = "foo"
+		d = s.dup
+		d << "bar"
+		s
+		`, "foo"},
+		{`1.dup`, 1},
+		{`nil.clone`, nil},
+		{`"foo".freeze.dup.frozen?`, false},
+		{`"foo".freeze.clone.frozen?`, true},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		f = Foo.new
+		f.bar = [1]
+		d = f.dup
+		d.bar.push(2)
+		f.bar.length
+		`, 2},
+		{`
+		class Foo
+		  attr_accessor :bar
+
+		  def initialize_copy(original)
+		    @bar = original.bar.dup
+		  end
+		end
+
+		f = Foo.new
+		f.bar = [1]
+		d = f.dup
+		d.bar.push(2)
+		f.bar.length
+		`, 1},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		f = Foo.new
+		f.bar = 1
+		d = f.dup
+		d.bar = 2
+		f.bar
+		`, 1},
+		{`
+		s = "foo"
+		def s.shout
+		  upcase + "!"
+		end
+
+		s.clone.shout
+		`, "FOO!"},
+		{`
+		s = "foo"
+		def s.shout
+		  upcase + "!"
+		end
+
+		s.dup.respond_to?(:shout)
+		`, false},
+		{`
+		module Greeter
+		  def greet
+		    "hi"
+		  end
+		end
+
+		o = Object.new
+		o.extend(Greeter)
+		o.clone.greet
+		`, "hi"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralDupAndCloneMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Object.dup`, "TypeError: Can't copy Class", 1},
+		{`1.dup(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`1.clone(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralToSAndInspectMethod(t *testing.T) {
+	pointClass := `
+	class Point
+	  def initialize(x, y)
+	    @x = x
+	    @y = y
+	  end
+
+	  def to_s
+	    "(" + @x.to_s + ", " + @y.to_s + ")"
+	  end
+	end
+
+	class Tag
+	  def inspect
+	    "#tag"
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{pointClass + `Point.new(1, 2).to_s`, "(1, 2)"},
+		{pointClass + `"at #{Point.new(1, 2)}"`, "at (1, 2)"},
+		{pointClass + `[Point.new(1, 2), "a", 3].to_s`, `[(1, 2), "a", 3]`},
+		{pointClass + `{ a: Point.new(1, 2), b: [Point.new(3, 4)] }.to_s`, "{ a: (1, 2), b: [(3, 4)] }"},
+		{pointClass + `Point.new(1, 2).inspect`, "(1, 2)"},
+		{pointClass + `[Tag.new, Point.new(0, 0)].to_s`, "[#tag, (0, 0)]"},
+		{pointClass + `{ t: Tag.new }.inspect`, "{ t: #tag }"},
+		{`"foo".inspect`, `"foo"`},
+		{`"a\nb".inspect`, `"a\nb"`},
+		{`["a\"b", "tab\t"].to_s`, `["a\"b", "tab\t"]`},
+		{`{ a: "x\\y" }.inspect`, `{ a: "x\\y" }`},
+		{pointClass + `format("at %s", Point.new(1, 2))`, "at (1, 2)"},
+		{pointClass + `"%s and %s" % [Point.new(1, 2), Point.new(3, 4)]`, "(1, 2) and (3, 4)"},
+		{pointClass + `[Point.new(1, 2), [Point.new(3, 4)]].join(" ")`, "(1, 2) (3, 4)"},
+		{`[1, "a", nil, ["b"]].inspect`, `[1, "a", nil, ["b"]]`},
+		{`{ a: "b" }.to_s`, `{ a: "b" }`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralInspectMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.inspect(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMethodVisibility(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo
+		  def bar
+		    secret + self.secret
+		  end
+
+		  private
+
+		  def secret
+		    21
+		  end
+		end
+
+		Foo.new.bar
+		`, 42},
+		{`
+		class Foo
+		  def bar
+		    [1, 2].map do |i|
+		      secret + i
+		    end
+		  end
+
+		  private
+
+		  def secret
+		    10
+		  end
+		end
+
+		Foo.new.bar.to_s
+		`, "[11, 12]"},
+		{`
+		class Account
+		  def initialize(balance)
+		    @balance = balance
+		  end
+
+		  def richer_than?(other)
+		    balance > other.balance
+		  end
+
+		  protected
+
+		  def balance
+		    @balance
+		  end
+		end
+
+		Account.new(2).richer_than?(Account.new(1))
+		`, true},
+		{`
+		class Foo
+		  private
+
+		  def bar
+		    1
+		  end
+
+		  public
+
+		  def baz
+		    bar + 1
+		  end
+		end
+
+		Foo.new.baz
+		`, 2},
+		{`
+		class Foo
+		  def bar
+		    1
+		  end
+
+		  private :bar
+		  public :bar
+		end
+
+		Foo.new.bar
+		`, 1},
+		{`
+		class Foo
+		  def bar
+		    1
+		  end
+		end
+
+		class Baz < Foo
+		  private :bar
+		end
+
+		Foo.new.bar
+		`, 1},
+		{`
+		class Foo
+		  private
+
+		  def bar
+		    1
+		  end
+		end
+
+		Foo.new.send(:bar)
+		`, 1},
+		{`
+		class Foo
+		  def bar; end
+		  def baz; end
+		  private :bar, "baz"
+		end
+
+		Foo.new.respond_to?(:baz)
+		`, false},
+		{`
+		class Foo
+		  private
+
+		  def bar; end
+
+		  protected
+
+		  def baz; end
+		end
+
+		Foo.new.respond_to?(:bar) || Foo.new.respond_to?(:baz)
+		`, false},
+		{`
+		class Foo
+		  def bar; end
+		  def baz; end
+		  private :bar
+		end
+
+		Foo.instance_methods(false).to_s
+		`, "[:baz]"},
+		{`
+		class Foo
+		  def bar; end
+		end
+
+		class Baz < Foo
+		  private :bar
+		end
+
+		Baz.new.methods.select do |m|
+		  m.to_s == "bar"
+		end.to_s
+		`, "[]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMethodVisibilityFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`class Foo
+		  private
+
+		  def bar; end
+		end
+
+		Foo.new.bar`, "UndefinedMethodError: Private method 'bar' called for <Instance of: Foo>", 7},
+		{`class Foo
+		  protected
+
+		  def bar; end
+		end
+
+		Foo.new.bar`, "UndefinedMethodError: Protected method 'bar' called for <Instance of: Foo>", 7},
+		{`class Foo
+		  protected
+
+		  def bar; end
+		end
+
+		Foo.new.public_send(:bar)`, "UndefinedMethodError: Protected method 'bar' called for <Instance of: Foo>", 7},
+		{`class Foo
+		end
+
+		Foo.private(:bar)`, "UndefinedMethodError: Undefined Method 'bar' for Foo", 4},
+		{`class Foo
+		end
+
+		Foo.protected(1)`, "TypeError: Expect method name to be String or Symbol. got: Integer", 4},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestClassNameClassMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Integer.name`, "Integer"},
+		{`String.name`, "String"},
+		{`Boolean.name`, "Boolean"},
+		{`Range.name`, "Range"},
+		{`Hash.name`, "Hash"},
+		{`Array.name`, "Array"},
+		{`Class.name`, "Class"},
+		{`Object.name`, "Object"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestClassNameClassMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Taipei".name`, "UndefinedMethodError: Undefined Method 'name' for Taipei", 1},
+		{`123.name`, "UndefinedMethodError: Undefined Method 'name' for 123", 1},
+		{`true.name`, "UndefinedMethodError: Undefined Method 'name' for true", 1},
+		{`Integer.name(Integer)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`String.name(Hash, Array)`, "ArgumentError: Expect 0 argument. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestClassSuperclassClassMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Integer.superclass.name`, "Object"},
+		{`String.superclass.name`, "Object"},
+		{`Boolean.superclass.name`, "Object"},
+		{`Range.superclass.name`, "Object"},
+		{`Hash.superclass.name`, "Object"},
+		{`Array.superclass.name`, "Object"},
+		{`Object.superclass.name`, "Object"},
+		{`Class.superclass.name`, "Object"},
+		{`
+		module Bar; end
+		class Foo
+		  include Bar
+		end
+		Foo.superclass.name
+		`, "Object"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestClassSuperclassClassMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Taipei".superclass`, "UndefinedMethodError: Undefined Method 'superclass' for Taipei", 1},
+		{`123.superclass`, "UndefinedMethodError: Undefined Method 'superclass' for 123", 1},
+		{`true.superclass`, "UndefinedMethodError: Undefined Method 'superclass' for true", 1},
+		{`Integer.superclass(Integer)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`String.superclass(Hash, Array)`, "ArgumentError: Expect 0 argument. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestClassSingletonClassClassMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Integer.singleton_class.name`, "#<Class:Integer>"},
+		{`Integer.singleton_class.superclass.name`, "#<Class:Object>"},
+		{`
+		class Bar; end
+		Bar.singleton_class.name
+		`, "#<Class:Bar>"},
+		{`
+		class Bar; end
+		class Foo < Bar; end
+		Foo.singleton_class.superclass.name
+		`, "#<Class:Bar>"},
+		{`
+		o = Object.new
+		def o.foo; end
+		o.singleton_class.class.name
+		`, "Class"},
+		{`Object.new.singleton_class.class.name`, "Class"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralFormatMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`format("Goby")`, "Goby"},
+		{`format("%d", 42)`, "42"},
+		{`format("%05d", 42)`, "00042"},
+		{`format("%+d %i", 42, -1)`, "+42 -1"},
+		{`format("%-5s|%5s", "ab", "cd")`, "ab   |   cd"},
+		{`format("%.2s", "Goby")`, "Go"},
+		{`format("%s %s %s", nil, [1, 2], true)`, "nil [1, 2] true"},
+		{`format("%.2f", 3)`, "3.00"},
+		{`format("%.2f", 3.5)`, "3.50"},
+		{`format("%e", 1.5)`, "1.500000e+00"},
+		{`format("%E %g %G", 0.00001, 2.5, 1e20)`, "1.000000E-05 2.5 1E+20"},
+		{`format("%08.3f|%-8.1f|", -3.14159, 2.25)`, "-003.142|2.2     |"},
+		{`format("%.1f", 2 ** 64)`, "18446744073709551616.0"},
+		{`format("%d", 2 ** 64)`, "18446744073709551616"},
+		{`format("%+d %x %b", 2 ** 64, 2 ** 64, -(2 ** 64))`, "+18446744073709551616 10000000000000000 -10000000000000000000000000000000000000000000000000000000000000000"},
+		{`format("%x %X %o %b", 255, 255, 8, 5)`, "ff FF 10 101"},
+		{`format("%#x", 255)`, "0xff"},
+		{`format("%c%c", 71, "oby")`, "Go"},
+		{`format("%s is 100%% done", "Goby")`, "Goby is 100% done"},
+		{`sprintf("%3d|%-3d|", 1, 2)`, "  1|2  |"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGeneralFormatMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`format`, "ArgumentError: Expect at least 1 argument. got: 0", 1},
+		{`format(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`format("%d", "1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`format("%f", nil)`, "TypeError: Expect argument to be Float or Integer. got: Null", 1},
+		{`format("%e", "1.5")`, "TypeError: Expect argument to be Float or Integer. got: String", 1},
+		{`format("%d", 1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`format("%c", [])`, "TypeError: Expect argument to be Integer or String. got: Array", 1},
+		{`format("%d %d", 1)`, "ArgumentError: Too few arguments for format string: %d %d", 1},
+		{`format("%d", 1, 2)`, "ArgumentError: Too many arguments for format string: %d", 1},
+		{`format("%5", 1)`, "ArgumentError: Incomplete format specifier: %5", 1},
+		{`format("%y", 1)`, "ArgumentError: Malformed format string: %y", 1},
+		{`sprintf("%d", "1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestAtExitMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+		$log = []
+		at_exit do
+		  $log.push(1)
+		end
+		at_exit do
+		  $log.push(2)
+		end
+		$log.push(0)
+		`, "[0, 2, 1]"},
+		{`
+		$log = []
+		at_exit do
+		  at_exit do
+		    $log.push("nested")
+		  end
+		  $log.push("outer")
+		end
+		`, `["outer", "nested"]`},
+		{`
+		$log = []
+		x = 1
+		at_exit do
+		  $log.push(x)
+		end
+		x = 2
+		`, "[2]"},
+		{`
+		$log = []
+		at_exit do
+		  $log.push("first")
+		end
+		at_exit do
+		  raise("oops")
+		end
+		`, `["first"]`},
+		{`
+		$log = []
+		at_exit do
+		  $log.push("cleanup")
+		end
+		raise("boom")
+		`, `["cleanup"]`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		v.testEval(t, tt.input, getFilename())
+
+		if log := v.globalVariable("$log").toString(); log != tt.expected {
+			t.Errorf("At case %d expect $log to be %s. got: %s", i, tt.expected, log)
+		}
+	}
+}
+
+func TestAtExitMethodReturnValue(t *testing.T) {
+	input := `
+	p = at_exit do
+	  1
+	end
+	p.class.name
+	`
+
+	v := initTestVM()
+	evaluated := v.testEval(t, input, getFilename())
+	checkExpected(t, 0, evaluated, "Proc")
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
+func TestAtExitMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`at_exit`, "InternalError: Can't yield without a block", 1},
+		{`at_exit(1) do; end`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+	}
+}
+
+func TestSleepMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`sleep(0)`, 0},
+		{`sleep(0.01)`, 0},
+		{`sleep(0.6)`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSleepMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`sleep`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`sleep("1")`, "TypeError: Expect argument to be Integer or Float. got: String", 1},
+		{`sleep(-0.5)`, "ArgumentError: Time interval must not be negative. got: -0.5", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestExitAndAbortMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		begin
+		  exit
+		rescue SystemExit => e
+		  e.status
+		end
+		`, 0},
+		{`
+		begin
+		  exit(3)
+		rescue SystemExit => e
+		  e.status
+		end
+		`, 3},
+		{`
+		begin
+		  exit(false)
+		rescue SystemExit => e
+		  e.success?
+		end
+		`, false},
+		{`
+		begin
+		  exit(true)
+		rescue SystemExit => e
+		  e.success?
+		end
+		`, true},
+		{`
+		begin
+		  abort
+		rescue SystemExit => e
+		  e.status
+		end
+		`, 1},
+		{`
+		begin
+		  exit
+		rescue SystemExit => e
+		  e.message
+		end
+		`, "exit"},
+		{`
+		result = begin
+		  begin
+		    exit
+		  rescue
+		    "rescued by StandardError"
+		  end
+		rescue SystemExit
+		  "rescued by SystemExit"
+		end
+		result
+		`, "rescued by SystemExit"},
+		{`SystemExit.superclass.name`, "Exception"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestExitAndAbortMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`exit`, "SystemExit: exit", 1},
+		{`
+		exit(2)
+		1
+		`, "SystemExit: exit", 2},
+		{`exit(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`exit("1")`, "TypeError: Expect argument to be Integer or Boolean. got: String", 1},
+		{`abort(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`abort("a", "b")`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
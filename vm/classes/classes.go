@@ -1,18 +1,49 @@
 package classes
 
 const (
-	ObjectClass   = "Object"
-	ClassClass    = "Class"
-	IntegerClass  = "Integer"
-	StringClass   = "String"
-	ArrayClass    = "Array"
-	HashClass     = "Hash"
-	BooleanClass  = "Boolean"
-	NullClass     = "Null"
-	ChannelClass  = "Channel"
-	RangeClass    = "Range"
-	MethodClass   = "method"
-	PluginClass   = "Plugin"
-	GoObjectClass = "GoObject"
-	FileClass     = "File"
+	ObjectClass        = "Object"
+	ClassClass         = "Class"
+	IntegerClass       = "Integer"
+	BigIntClass        = "BigInt"
+	FloatClass         = "Float"
+	RationalClass      = "Rational"
+	StringClass        = "String"
+	SymbolClass        = "Symbol"
+	ArrayClass         = "Array"
+	HashClass          = "Hash"
+	BooleanClass       = "Boolean"
+	NullClass          = "Null"
+	ChannelClass       = "Channel"
+	ThreadClass        = "Thread"
+	WaitGroupClass     = "WaitGroup"
+	AtomicClass        = "Atomic"
+	QueueClass         = "Queue"
+	SizedQueueClass    = "SizedQueue"
+	RangeClass         = "Range"
+	MethodClass        = "method"
+	BoundMethodClass   = "Method"
+	UnboundMethodClass = "UnboundMethod"
+	PluginClass        = "Plugin"
+	GoObjectClass      = "GoObject"
+	IOClass            = "IO"
+	FileClass          = "File"
+	DirClass           = "Dir"
+	TimeClass          = "Time"
+	RegexpClass        = "Regexp"
+	MatchDataClass     = "MatchData"
+	EncodingClass      = "Encoding"
+	RandomClass        = "Random"
+	ProcClass          = "Proc"
+	EnumeratorClass    = "Enumerator"
+	LazyClass          = "Lazy"
+
+	ComparableModule  = "Comparable"
+	EnumerableModule  = "Enumerable"
+	MathModule        = "Math"
+	ObjectSpaceModule = "ObjectSpace"
+	GCModule          = "GC"
+	ConcurrentModule  = "Concurrent"
+	TimeoutModule     = "Timeout"
+	ProcessModule     = "Process"
+	MarshalModule     = "Marshal"
 )
@@ -0,0 +1,282 @@
+package vm
+
+import (
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Comparable is a module that provides comparison operators to the classes that include it.
+// All operators are based on the `<=>` method of the receiver, which should return a negative
+// Integer, 0 or a positive Integer when the receiver is less than, equal to or greater than
+// the argument.
+//
+// ```ruby
+// class Version
+//   include(Comparable)
+//
+//   attr_reader(:major, :minor)
+//
+//   def initialize(major, minor)
+//     @major = major
+//     @minor = minor
+//   end
+//
+//   def <=>(other)
+//     if major == other.major
+//       minor <=> other.minor
+//     else
+//       major <=> other.major
+//     end
+//   end
+// end
+//
+// Version.new(1, 2) < Version.new(1, 10)                         # => true
+// Version.new(1, 2).between?(Version.new(1, 0), Version.new(2, 0)) # => true
+// "b".clamp("c", "e")                                             # => "c"
+// ```
+//
+// Integer, BigInt, Float, Rational and String include Comparable.
+// Integer and Float keep their own comparison operators for speed, and get `between?` and `clamp` from it.
+
+// Instance methods -----------------------------------------------------
+func builtinComparableInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the receiver is less than the argument, based on `<=>`
+			//
+			// ```ruby
+			// "a" < "b" # => true
+			// "b" < "a" # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return compareWith(t, receiver, args, func(c int) bool { return c < 0 })
+				}
+			},
+		},
+		{
+			// Returns true if the receiver is less than or equal to the argument, based on `<=>`
+			//
+			// ```ruby
+			// "a" <= "b" # => true
+			// "a" <= "a" # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "<=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return compareWith(t, receiver, args, func(c int) bool { return c <= 0 })
+				}
+			},
+		},
+		{
+			// Returns true if `<=>` returns 0, or if the argument is the receiver itself.
+			// Returns false if the objects can't be compared.
+			//
+			// ```ruby
+			// Version.new(1, 2) == Version.new(1, 2) # => true
+			// Version.new(1, 2) == 1                 # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					if receiver == args[0] {
+						return TRUE
+					}
+
+					result := t.sendMethod("<=>", receiver, args[0])
+
+					switch r := result.(type) {
+					case *Error:
+						return r
+					case *IntegerObject:
+						if r.value == 0 {
+							return TRUE
+						}
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns true if the receiver is greater than the argument, based on `<=>`
+			//
+			// ```ruby
+			// "b" > "a" # => true
+			// "a" > "b" # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: ">",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return compareWith(t, receiver, args, func(c int) bool { return c > 0 })
+				}
+			},
+		},
+		{
+			// Returns true if the receiver is greater than or equal to the argument, based on `<=>`
+			//
+			// ```ruby
+			// "b" >= "a" # => true
+			// "a" >= "a" # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: ">=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return compareWith(t, receiver, args, func(c int) bool { return c >= 0 })
+				}
+			},
+		},
+		{
+			// Returns true if the receiver is between min and max inclusively, based on `<=>`
+			//
+			// ```ruby
+			// 3.between?(1, 5)       # => true
+			// "b".between?("c", "e") # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "between?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+					}
+
+					c, err := compare(t, receiver, args[0])
+
+					if err != nil {
+						return err
+					}
+
+					if c < 0 {
+						return FALSE
+					}
+
+					c, err = compare(t, receiver, args[1])
+
+					if err != nil {
+						return err
+					}
+
+					if c > 0 {
+						return FALSE
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns min if the receiver is less than min, max if the receiver is greater than max,
+			// or the receiver itself otherwise, based on `<=>`
+			//
+			// ```ruby
+			// 12.clamp(0, 10)     # => 10
+			// (-1.5).clamp(0, 10) # => 0
+			// "d".clamp("a", "c") # => "c"
+			// ```
+			//
+			// @return [Object]
+			Name: "clamp",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+					}
+
+					c, err := compare(t, args[0], args[1])
+
+					if err != nil {
+						return err
+					}
+
+					if c > 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect min argument to be less than or equal to max argument")
+					}
+
+					c, err = compare(t, receiver, args[0])
+
+					if err != nil {
+						return err
+					}
+
+					if c < 0 {
+						return args[0]
+					}
+
+					c, err = compare(t, receiver, args[1])
+
+					if err != nil {
+						return err
+					}
+
+					if c > 0 {
+						return args[1]
+					}
+
+					return receiver
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initComparableModule() *RClass {
+	m := vm.initializeClass(classes.ComparableModule, true)
+	m.setBuiltinMethods(builtinComparableInstanceMethods(), false)
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// compare calls `<=>` of left with right, and returns the result as an int
+func compare(t *thread, left, right Object) (int, *Error) {
+	result := t.sendMethod("<=>", left, right)
+
+	switch r := result.(type) {
+	case *Error:
+		return 0, r
+	case *IntegerObject:
+		return r.value, nil
+	default:
+		return 0, t.initErrorObject(errors.ArgumentError, "Comparison of %s with %s failed", left.Class().Name, right.Class().Name)
+	}
+}
+
+// compareWith compares the receiver with the only argument, and returns whether the result satisfies fn
+func compareWith(t *thread, receiver Object, args []Object, fn func(int) bool) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	c, err := compare(t, receiver, args[0])
+
+	if err != nil {
+		return err
+	}
+
+	if fn(c) {
+		return TRUE
+	}
+
+	return FALSE
+}
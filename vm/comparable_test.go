@@ -0,0 +1,131 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestComparableModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Comparable.name`, "Comparable"},
+		{`Comparable.class.name`, "Class"},
+		{`String.superclass.name`, "Object"},
+		{`Integer.superclass.name`, "Object"},
+		{`3.is_a?(Comparable)`, true},
+		{`1.5.is_a?(Comparable)`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestComparableMethods(t *testing.T) {
+	versionClass := `
+	class Version
+	  include(Comparable)
+
+	  attr_reader(:major, :minor)
+
+	  def initialize(major, minor)
+	    @major = major
+	    @minor = minor
+	  end
+
+	  def <=>(other)
+	    if other.is_a?(Version)
+	      if major == other.major
+	        minor <=> other.minor
+	      else
+	        major <=> other.major
+	      end
+	    end
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{versionClass + `Version.new(1, 2) < Version.new(1, 10)`, true},
+		{versionClass + `Version.new(2, 0) <= Version.new(1, 10)`, false},
+		{versionClass + `Version.new(2, 0) > Version.new(1, 10)`, true},
+		{versionClass + `Version.new(1, 10) >= Version.new(1, 10)`, true},
+		{versionClass + `Version.new(1, 2) == Version.new(1, 2)`, true},
+		{versionClass + `Version.new(1, 2) == Version.new(1, 3)`, false},
+		{versionClass + `Version.new(1, 2) == 1`, false},
+		{versionClass + `Version.new(1, 2) != Version.new(1, 3)`, true},
+		{versionClass + `Version.new(1, 2).between?(Version.new(1, 0), Version.new(2, 0))`, true},
+		{versionClass + `Version.new(3, 2).between?(Version.new(1, 0), Version.new(2, 0))`, false},
+		{versionClass + `Version.new(3, 2).clamp(Version.new(1, 0), Version.new(2, 0)).major`, 2},
+		{versionClass + `Version.new(1, 5).clamp(Version.new(1, 0), Version.new(2, 0)).minor`, 5},
+		{`3.between?(1, 5)`, true},
+		{`3.between?(3, 3)`, true},
+		{`6.between?(1, 5)`, false},
+		{`1.5.between?(1, 2)`, true},
+		{`"b".between?("c", "e")`, false},
+		{`12.clamp(0, 10)`, 10},
+		{`(-1).clamp(0, 10)`, 0},
+		{`5.clamp(0, 10)`, 5},
+		{`(-1.5).clamp(0, 10)`, 0},
+		{`2.5.clamp(0, 10)`, 2.5},
+		{`"d".clamp("a", "c")`, "c"},
+		{`18446744073709551616.clamp(0, 10)`, 10},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestComparableMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		class String
+		  def <=>(other)
+		    nil
+		  end
+		end
+
+		"a" < "b"
+		`, "ArgumentError: Comparison of String with String failed", 8},
+		{`3.between?(1)`, "ArgumentError: Expect 2 arguments. got=1", 1},
+		{`3.clamp(1, 2, 3)`, "ArgumentError: Expect 2 arguments. got=3", 1},
+		{`3.clamp(5, 1)`, "ArgumentError: Expect min argument to be less than or equal to max argument", 1},
+		{`3.between?("a", 5)`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`"a".clamp(1, 2)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		// Including Comparable in one class doesn't change the lookup chain of another
+		{`
+		class Base
+		  def name
+		    "base"
+		  end
+		end
+
+		class Child < Base
+		  include(Comparable)
+		end
+
+		"a".name
+		`, "UndefinedMethodError: Undefined Method 'name' for a", 12},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
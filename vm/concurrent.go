@@ -0,0 +1,189 @@
+package vm
+
+import (
+	"sync"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Concurrent is a module that holds the thread-safe variants of Array and Hash, which are `Concurrent::Array` and
+// `Concurrent::Hash`. They inherit from Array and Hash and have all of their methods, but a method call on them
+// holds the collection's lock, so threads can share them without corrupting them.
+//
+// ```ruby
+// h = Concurrent::Hash.new
+// wg = WaitGroup.new
+//
+// 10.times do |i|
+//   wg.add
+//   thread do
+//     h[i.to_s] = i
+//     wg.done
+//   end
+// end
+//
+// wg.wait
+// h.length # => 10
+// ```
+//
+// The lock is held by the thread while the method yields, so the block can call the collection's methods too.
+// Methods that return new collections, like `map`, return plain Arrays and Hashes.
+
+// Class methods --------------------------------------------------------
+func builtinConcurrentArrayClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new Concurrent::Array with the given array's elements, or an empty one.
+			//
+			// ```ruby
+			// Concurrent::Array.new         # => []
+			// Concurrent::Array.new([1, 2]) # => [1, 2]
+			// ```
+			//
+			// @param array [Array]
+			// @return [Concurrent::Array]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					a := t.vm.initArrayObject([]Object{})
+
+					switch len(args) {
+					case 0:
+					case 1:
+						arr, ok := args[0].(*ArrayObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ArrayClass, args[0].Class().Name)
+						}
+
+						a = arr.copy().(*ArrayObject)
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					a.class = receiver.(*RClass)
+					a.lock = newReentrantLock()
+					return a
+				}
+			},
+		},
+	}
+}
+
+func builtinConcurrentHashClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new Concurrent::Hash with the given hash's pairs, or an empty one.
+			//
+			// ```ruby
+			// Concurrent::Hash.new.length   # => 0
+			// Concurrent::Hash.new(a: 1)   # => { a: 1 }
+			// ```
+			//
+			// @param hash [Hash]
+			// @return [Concurrent::Hash]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					h := t.vm.initHashObject(map[string]Object{})
+
+					switch len(args) {
+					case 0:
+					case 1:
+						hash, ok := args[0].(*HashObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+						}
+
+						h = hash.copy().(*HashObject)
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					h.class = receiver.(*RClass)
+					h.lock = newReentrantLock()
+					return h
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initConcurrentModule() *RClass {
+	m := vm.initializeClass(classes.ConcurrentModule, true)
+
+	ac := vm.initializeClass(classes.ArrayClass, false)
+	ac.inherits(vm.topLevelClass(classes.ArrayClass))
+	ac.setBuiltinMethods(builtinConcurrentArrayClassMethods(), true)
+	ac.scope = m
+	m.setClassConstant(ac)
+
+	hc := vm.initializeClass(classes.HashClass, false)
+	hc.inherits(vm.topLevelClass(classes.HashClass))
+	hc.setBuiltinMethods(builtinConcurrentHashClassMethods(), true)
+	hc.scope = m
+	m.setClassConstant(hc)
+
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// synchronizedObject is an object whose builtin methods are called with its lock held, the lock is nil if the
+// object isn't shared by threads
+type synchronizedObject interface {
+	synchronizer() *reentrantLock
+}
+
+// reentrantLock is a lock that the thread holding it can lock again, so a method of a synchronized object can
+// yield a block that calls the object's methods
+type reentrantLock struct {
+	mutex *sync.Mutex
+	cond  *sync.Cond
+	owner *thread
+	count int
+}
+
+func newReentrantLock() *reentrantLock {
+	m := &sync.Mutex{}
+	return &reentrantLock{mutex: m, cond: sync.NewCond(m)}
+}
+
+func (l *reentrantLock) lock(t *thread) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	for l.owner != nil && l.owner != t {
+		l.cond.Wait()
+	}
+
+	l.owner = t
+	l.count++
+}
+
+func (l *reentrantLock) unlock() {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.count--
+
+	if l.count == 0 {
+		l.owner = nil
+		l.cond.Signal()
+	}
+}
+
+// synchronize calls fn with the lock held by the thread, the lock is released even if fn panics, like when a
+// block breaks or an error is rescued
+func (l *reentrantLock) synchronize(t *thread, fn func() Object) Object {
+	l.lock(t)
+	defer l.unlock()
+
+	return fn()
+}
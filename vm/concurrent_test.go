@@ -0,0 +1,115 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestConcurrentCollections(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Concurrent::Array.superclass.name`, "Array"},
+		{`Concurrent::Hash.superclass.name`, "Hash"},
+		{`Concurrent::Array.new.to_s`, "[]"},
+		{`Concurrent::Array.new([1, 2]).to_s`, "[1, 2]"},
+		{`Concurrent::Hash.new.length`, 0},
+		{`Concurrent::Hash.new({ a: 1 })["a"]`, 1},
+		{`Concurrent::Array.new.is_a?(Array)`, true},
+		{`
+		a = [1]
+		c = Concurrent::Array.new(a)
+		c.push(2)
+		a.length
+		`, 1},
+		{`
+		h = Concurrent::Hash.new
+		a = Concurrent::Array.new
+		wg = WaitGroup.new
+
+		100.times do |i|
+		  wg.add
+		  thread do
+		    h[i.to_s] = i
+		    a.push(i)
+		    wg.done
+		  end
+		end
+
+		wg.wait
+		h.length + a.length
+		`, 200},
+		{`
+		a = Concurrent::Array.new([1, 2])
+		a.each do |x|
+		  a.push(x * 10)
+		end
+		a.to_s
+		`, "[1, 2, 10, 20]"},
+		{`
+		a = Concurrent::Array.new([1, 2])
+
+		a.each do |x|
+		  break x
+		end
+
+		t = Thread.new do
+		  a.push(3)
+		end
+		t.join
+		a.length
+		`, 3},
+		{`
+		a = Concurrent::Array.new([1, 2])
+
+		begin
+		  a.each do |x|
+		    raise("Oops")
+		  end
+		rescue
+		end
+
+		t = Thread.new do
+		  a.push(3)
+		end
+		t.join
+		a.length
+		`, 3},
+		{`
+		a = Concurrent::Array.new([1])
+		b = a.dup
+		b.push(2)
+
+		t = Thread.new do
+		  b.push(3)
+		end
+		t.join
+		[a.length, b.length, b.class == a.class].to_s
+		`, "[1, 3, true]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestConcurrentCollectionsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Concurrent::Array.new(1)`, "TypeError: Expect argument to be Array. got: Integer", 1},
+		{`Concurrent::Array.new([], [])`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`Concurrent::Hash.new([])`, "TypeError: Expect argument to be Hash. got: Array", 1},
+		{`Concurrent::Hash.new({}, {})`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
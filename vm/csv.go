@@ -0,0 +1,481 @@
+package vm
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// CSVObject is loaded by `require "csv"`, it reads and writes comma-separated values with Go's `encoding/csv`.
+// Rows are read as arrays of strings, or hashes keyed by the first row when the `headers: true` option is given.
+// The `col_sep:` option changes the separator of the fields.
+//
+// ```ruby
+// require "csv"
+//
+// CSV.parse("name,age\nStan,23\n")                # => [["name", "age"], ["Stan", "23"]]
+// CSV.parse("name,age\nStan,23\n", headers: true) # => [{ name: "Stan", age: "23" }]
+//
+// CSV.foreach("users.csv", headers: true) do |row|
+//   puts(row["name"])
+// end
+//
+// CSV.generate do |csv|
+//   csv << ["name", "age"]
+//   csv << ["Stan", 23]
+// end
+// # => "name,age\nStan,23\n"
+// ```
+//
+// A CSV instance is the writer `CSV.generate` yields.
+type CSVObject struct {
+	*baseObj
+	buffer *bytes.Buffer
+	writer *csv.Writer
+}
+
+// csvOptions are the keyword arguments of CSV's methods
+type csvOptions struct {
+	headers bool
+	comma   rune
+}
+
+// Class methods --------------------------------------------------------
+func builtinCSVClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Reads the CSV file and yields each row to the block. Returns an enumerator if no block is given.
+			//
+			// ```ruby
+			// CSV.foreach("users.csv") do |row|
+			//   row # => ["Stan", "23"]
+			// end
+			//
+			// CSV.foreach("users.csv", headers: true) do |row|
+			//   row # => { name: "Stan", age: "23" }
+			// end
+			// ```
+			//
+			// @param filename [String], headers: [Boolean], col_sep: [String]
+			// @return [Null]
+			Name: "foreach",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "foreach", args)
+					}
+
+					rows, err := t.readCSVFile(args)
+					if err != nil {
+						return err
+					}
+
+					for _, row := range rows {
+						t.builtinMethodYield(blockFrame, row)
+					}
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Yields a CSV writer to the block and returns the rows added to it as a string.
+			//
+			// ```ruby
+			// CSV.generate do |csv|
+			//   csv << ["name", "note"]
+			//   csv << ["Stan", "likes \"Goby\", and Ruby"]
+			// end
+			// # => "name,note\nStan,\"likes \"\"Goby\"\", and Ruby\"\n"
+			//
+			// CSV.generate(col_sep: ";") do |csv|
+			//   csv << [1, nil, 3]
+			// end
+			// # => "1;;3\n"
+			// ```
+			//
+			// @param col_sep: [String]
+			// @return [String]
+			Name: "generate",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					opts, err := t.csvOptions(args, 0)
+					if err != nil {
+						return err
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					c := t.vm.initCSVObject(opts)
+					t.builtinMethodYield(blockFrame, c)
+
+					return c.flush(t)
+				}
+			},
+		},
+		{
+			// Returns the row as a line of CSV.
+			//
+			// ```ruby
+			// CSV.generate_line(["Stan", 23, nil]) # => "Stan,23,\n"
+			// ```
+			//
+			// @param row [Array], col_sep: [String]
+			// @return [String]
+			Name: "generate_line",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+					}
+
+					opts, err := t.csvOptions(args, 1)
+					if err != nil {
+						return err
+					}
+
+					c := t.vm.initCSVObject(opts)
+					if err := c.addRow(t, args[0]); err != nil {
+						return err
+					}
+
+					return c.flush(t)
+				}
+			},
+		},
+		{
+			// Parses the string as CSV and returns the rows. If a block is given, each row is yielded to the block
+			// and nil is returned instead.
+			//
+			// ```ruby
+			// CSV.parse("a,b\n1,2")                     # => [["a", "b"], ["1", "2"]]
+			// CSV.parse("a;b\n1;2", col_sep: ";")      # => [["a", "b"], ["1", "2"]]
+			// CSV.parse("a,b\n1,2", headers: true)[0] # => { a: "1", b: "2" }
+			// ```
+			//
+			// @param string [String], headers: [Boolean], col_sep: [String]
+			// @return [Array]
+			Name: "parse",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+					}
+
+					s, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					opts, err := t.csvOptions(args, 1)
+					if err != nil {
+						return err
+					}
+
+					rows, err := t.readCSV(strings.NewReader(s.value), opts)
+					if err != nil {
+						return err
+					}
+
+					if blockFrame == nil {
+						return t.vm.initArrayObject(rows)
+					}
+
+					for _, row := range rows {
+						t.builtinMethodYield(blockFrame, row)
+					}
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Reads the CSV file and returns the rows.
+			//
+			// ```ruby
+			// CSV.read("users.csv")                # => [["name", "age"], ["Stan", "23"]]
+			// CSV.read("users.csv", headers: true) # => [{ name: "Stan", age: "23" }]
+			// ```
+			//
+			// @param filename [String], headers: [Boolean], col_sep: [String]
+			// @return [Array]
+			Name: "read",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					rows, err := t.readCSVFile(args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initArrayObject(rows)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinCSVInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Adds the array as a row, its elements are converted to strings and nil becomes an empty field.
+			// Returns self so calls can be chained.
+			//
+			// ```ruby
+			// CSV.generate do |csv|
+			//   csv << ["a", "b"] << [1, 2]
+			// end
+			// # => "a,b\n1,2\n"
+			// ```
+			//
+			// @param row [Array]
+			// @return [CSV]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err := receiver.(*CSVObject).addRow(t, args[0]); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Same as `<<`.
+			//
+			// ```ruby
+			// CSV.generate do |csv|
+			//   csv.add_row(["a", "b"])
+			// end
+			// # => "a,b\n"
+			// ```
+			//
+			// @param row [Array]
+			// @return [CSV]
+			Name: "add_row",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err := receiver.(*CSVObject).addRow(t, args[0]); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initCSVObject(opts csvOptions) *CSVObject {
+	buffer := new(bytes.Buffer)
+	writer := csv.NewWriter(buffer)
+	writer.Comma = opts.comma
+
+	return &CSVObject{
+		baseObj: &baseObj{class: vm.topLevelClass("CSV")},
+		buffer:  buffer,
+		writer:  writer,
+	}
+}
+
+func initCSVClass(vm *VM) {
+	class := vm.initializeClass("CSV", false)
+	class.setBuiltinMethods(builtinCSVClassMethods(), true)
+	class.setBuiltinMethods(builtinCSVInstanceMethods(), false)
+	vm.objectClass.setClassConstant(class)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// addRow writes the array as a row
+func (c *CSVObject) addRow(t *thread, row Object) *Error {
+	arr, ok := row.(*ArrayObject)
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ArrayClass, row.Class().Name)
+	}
+
+	fields := make([]string, len(arr.Elements))
+
+	for i, elem := range arr.Elements {
+		if elem == NULL {
+			continue
+		}
+
+		s, err := t.objectToString(elem)
+		if err != nil {
+			return err
+		}
+
+		fields[i] = s
+	}
+
+	if err := c.writer.Write(fields); err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return nil
+}
+
+// flush returns the rows written so far as a string
+func (c *CSVObject) flush(t *thread) Object {
+	c.writer.Flush()
+
+	if err := c.writer.Error(); err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return t.vm.initStringObject(c.buffer.String())
+}
+
+// Returns the object's name as the string format
+func (c *CSVObject) toString() string {
+	return "<CSV>"
+}
+
+// Alias of toString
+func (c *CSVObject) toJSON() string {
+	return c.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// csvOptions reads the keyword arguments that follow the given number of positional arguments
+func (t *thread) csvOptions(args []Object, positional int) (csvOptions, *Error) {
+	opts := csvOptions{comma: ','}
+
+	if len(args) <= positional {
+		return opts, nil
+	}
+
+	if len(args) > positional+1 {
+		return opts, t.initErrorObject(errors.ArgumentError, "Expect %d..%d arguments. got: %d", positional, positional+1, len(args))
+	}
+
+	h, ok := args[positional].(*HashObject)
+	if !ok {
+		return opts, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, args[positional].Class().Name)
+	}
+
+	for k, v := range h.Pairs {
+		switch k {
+		case "headers":
+			b, ok := v.(*BooleanObject)
+			if !ok {
+				return opts, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, v.Class().Name)
+			}
+
+			opts.headers = b.value
+		case "col_sep":
+			s, ok := v.(*StringObject)
+			if !ok {
+				return opts, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, v.Class().Name)
+			}
+
+			if utf8.RuneCountInString(s.value) != 1 {
+				return opts, t.initErrorObject(errors.ArgumentError, "Expect col_sep to be a single character. got: %s", s.value)
+			}
+
+			opts.comma, _ = utf8.DecodeRuneInString(s.value)
+		default:
+			return opts, t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+		}
+	}
+
+	return opts, nil
+}
+
+// readCSVFile reads the rows of the file given by the arguments of `CSV.foreach` and `CSV.read`
+func (t *thread) readCSVFile(args []Object) ([]Object, *Error) {
+	if len(args) < 1 {
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+	}
+
+	filename, ok := args[0].(*StringObject)
+	if !ok {
+		return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	opts, e := t.csvOptions(args, 1)
+	if e != nil {
+		return nil, e
+	}
+
+	f, err := os.Open(filename.value)
+	if err != nil {
+		return nil, t.initErrorObject(errors.InternalError, err.Error())
+	}
+	defer f.Close()
+
+	return t.readCSV(f, opts)
+}
+
+// readCSV reads all the rows, they're arrays of strings or hashes keyed by the header row
+func (t *thread) readCSV(r io.Reader, opts csvOptions) ([]Object, *Error) {
+	reader := csv.NewReader(r)
+	reader.Comma = opts.comma
+	reader.FieldsPerRecord = -1
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, t.initErrorObject(errors.InternalError, "Can't parse csv: %s", err.Error())
+	}
+
+	rows := []Object{}
+
+	if opts.headers {
+		if len(records) == 0 {
+			return rows, nil
+		}
+
+		headers := records[0]
+
+		for _, record := range records[1:] {
+			pairs := map[string]Object{}
+
+			for i, header := range headers {
+				if i < len(record) {
+					pairs[header] = t.vm.initStringObject(record[i])
+				} else {
+					pairs[header] = NULL
+				}
+			}
+
+			rows = append(rows, t.vm.initHashObject(pairs))
+		}
+
+		return rows, nil
+	}
+
+	for _, record := range records {
+		fields := []Object{}
+
+		for _, field := range record {
+			fields = append(fields, t.vm.initStringObject(field))
+		}
+
+		rows = append(rows, t.vm.initArrayObject(fields))
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,178 @@
+package vm
+
+import "testing"
+
+func TestCSVParseMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "csv"
+		CSV.parse("name,age\nStan,23\n").to_s
+		`, `[["name", "age"], ["Stan", "23"]]`},
+		{`
+		require "csv"
+		CSV.parse("a;\"b;c\"", col_sep: ";").to_s
+		`, `[["a", "b;c"]]`},
+		{`
+		require "csv"
+		CSV.parse("name,age\nStan,23\nGoby\n", headers: true)[0]["name"]
+		`, "Stan"},
+		{`
+		require "csv"
+		CSV.parse("name,age\nStan,23\nGoby\n", headers: true)[1]["age"]
+		`, nil},
+		{`
+		require "csv"
+		CSV.parse("name,age", headers: true).length
+		`, 0},
+		{`
+		require "csv"
+		CSV.parse("").length
+		`, 0},
+		{`
+		require "csv"
+		names = []
+		CSV.parse("Stan,23\nGoby,5") do |row|
+		  names.push(row[0])
+		end
+		names.join(",")
+		`, "Stan,Goby"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCSVReadingFileMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "csv"
+		CSV.read("../test_fixtures/csv_test/users.csv").to_s
+		`, `[["name", "age"], ["Stan", "23"], ["Goby", "5"]]`},
+		{`
+		require "csv"
+		CSV.read("../test_fixtures/csv_test/users.csv", headers: true)[1]["name"]
+		`, "Goby"},
+		{`
+		require "csv"
+		names = []
+		CSV.foreach("../test_fixtures/csv_test/users.csv", headers: true) do |row|
+		  names.push(row["name"] + ":" + row["age"])
+		end
+		names.join(",")
+		`, "Stan:23,Goby:5"},
+		{`
+		require "csv"
+		CSV.foreach("../test_fixtures/csv_test/users.csv").to_a.length
+		`, 3},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCSVGeneratingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "csv"
+		CSV.generate do |csv|
+		  csv << ["name", "age"]
+		  csv << ["Stan", 23]
+		end
+		`, "name,age\nStan,23\n"},
+		{`
+		require "csv"
+		CSV.generate do |csv|
+		  csv << ["a", nil] << [1.5, true]
+		  csv.add_row(["say \"hi\", Goby"])
+		end
+		`, "a,\n1.5,true\n\"say \"\"hi\"\", Goby\"\n"},
+		{`
+		require "csv"
+		CSV.generate(col_sep: ";") do |csv|
+		  csv << [1, 2]
+		end
+		`, "1;2\n"},
+		{`
+		require "csv"
+		CSV.generate do |csv|
+		end
+		`, ""},
+		{`
+		require "csv"
+		CSV.generate_line(["Stan", 23, nil])
+		`, "Stan,23,\n"},
+		{`
+		require "csv"
+		CSV.generate_line([1, 2], col_sep: "\t")
+		`, "1\t2\n"},
+		{`
+		require "csv"
+		s = CSV.generate do |csv|
+		  csv << ["a,b", "c"]
+		end
+		CSV.parse(s)[0][0]
+		`, "a,b"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCSVMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "csv"
+		CSV.parse`, "ArgumentError: Expect 1..2 arguments. got: 0", 2},
+		{`require "csv"
+		CSV.parse(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "csv"
+		CSV.parse("a", 1)`, "TypeError: Expect argument to be Hash. got: Integer", 2},
+		{`require "csv"
+		CSV.parse("a", {}, {})`, "ArgumentError: Expect 1..2 arguments. got: 3", 2},
+		{`require "csv"
+		CSV.parse("a", headers: 1)`, "TypeError: Expect argument to be Boolean. got: Integer", 2},
+		{`require "csv"
+		CSV.parse("a", col_sep: ";;")`, "ArgumentError: Expect col_sep to be a single character. got: ;;", 2},
+		{`require "csv"
+		CSV.parse("a", sep: ";")`, "ArgumentError: Unknown keyword: sep", 2},
+		{`require "csv"
+		CSV.parse("x,\"y")`, "InternalError: Can't parse csv: parse error on line 1, column 5: extraneous or missing \" in quoted-field", 2},
+		{`require "csv"
+		CSV.read("../test_fixtures/csv_test/no_such_file.csv")`, "InternalError: open ../test_fixtures/csv_test/no_such_file.csv: no such file or directory", 2},
+		{`require "csv"
+		CSV.generate`, "InternalError: Can't yield without a block", 2},
+		{`require "csv"
+		CSV.generate_line("a")`, "TypeError: Expect argument to be Array. got: String", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
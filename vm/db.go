@@ -2,15 +2,32 @@ package vm
 
 import (
 	"fmt"
+	"strings"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq"
 )
 
+// DBTransactionObject represents a transaction started by `DB#transaction`. It's committed after the block
+// is executed, or rolled back if the block raises an error.
+type DBTransactionObject struct {
+	*baseObj
+	tx       *sqlx.Tx
+	finished bool
+}
+
 func initDBClass(vm *VM) {
 	pg := vm.initializeClass("DB", false)
 	pg.setBuiltinMethods(builtinDBClassMethods(), true)
 	pg.setBuiltinMethods(builtinDBInstanceMethods(), false)
+
+	tx := vm.initializeClass("Transaction", false)
+	tx.setBuiltinMethods(builtinDBTransactionInstanceMethods(), false)
+	pg.setClassConstant(tx)
+
 	vm.objectClass.setClassConstant(pg)
 
 	vm.execGobyLib("db.gb")
@@ -36,18 +53,23 @@ func getDBConn(t *thread, receiver Object) (*sqlx.DB, error) {
 func builtinDBClassMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
-			// The get_connection method returns a connection object which requires the name of the driver
-			// and the source which specifies the parameter including the name of the database and the
-			// username ...etc.
+			// The get_connection method returns a connection object which requires the data source, and optionally
+			// the name of the driver before it. When the driver name is omitted, it's inferred from the data source:
+			// "postgres://" and "postgresql://" URLs and "key=value" parameters use the 'postgres' driver, and
+			// "sqlite3://path", "sqlite://path" and "file:" URIs use the 'sqlite3' driver.
+			//
+			// Connections are pooled, and the pool can be configured with the `max_open_conns:`, `max_idle_conns:`
+			// and `conn_max_lifetime:` (in seconds) options.
 			//
-			// Currently supported DB driver is 'postgres'
+			// Currently supported DB driver is 'postgres', the 'sqlite3' driver is available in binaries built
+			// with the `sqlite` build tag.
 			//
 			// (The example is the DB#open class method which is implemented in db.gb file)
 			//
 			// ```ruby
 			// class DB
-			//   def self.open(driver_name, data_source)
-			//	   conn_obj = get_connection(driver_name, data_source) # => Returns the Conn object
+			//   def self.open(*args)
+			//	   conn_obj = get_connection(*args) # => Returns the Conn object
 			//	   connection = Connection.new(conn_obj)
 			//	   new(connection)
 			//   end
@@ -60,26 +82,55 @@ func builtinDBClassMethods() []*BuiltinMethodObject {
 			Name: "get_connection",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					var options *HashObject
+
+					if len(args) > 0 {
+						if h, ok := args[len(args)-1].(*HashObject); ok {
+							options = h
+							args = args[:len(args)-1]
+						}
 					}
 
-					driverName, ok := args[0].(*StringObject)
+					var driverName, dataSource string
 
-					if !ok {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect database's driver name to be a String object. got: %s", args[0].Class().Name)
-					}
+					switch len(args) {
+					case 1:
+						source, ok := args[0].(*StringObject)
+
+						if !ok {
+							return t.initErrorObject(errors.ArgumentError, "Expect database's data source to be a String object. got: %s", args[0].Class().Name)
+						}
+
+						driverName, dataSource = dbDriverName(source.value)
+					case 2:
+						name, ok := args[0].(*StringObject)
+
+						if !ok {
+							return t.initErrorObject(errors.ArgumentError, "Expect database's driver name to be a String object. got: %s", args[0].Class().Name)
+						}
 
-					dataSource, ok := args[1].(*StringObject)
+						source, ok := args[1].(*StringObject)
 
-					if !ok {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect database's data source to be a String object. got: %s", args[1].Class().Name)
+						if !ok {
+							return t.initErrorObject(errors.ArgumentError, "Expect database's data source to be a String object. got: %s", args[1].Class().Name)
+						}
+
+						driverName, dataSource = name.value, source.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
 					}
 
-					conn, err := sqlx.Open(driverName.value, dataSource.value)
+					conn, err := sqlx.Open(driverName, dataSource)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					if options != nil {
+						if err := t.setDBPoolOptions(conn, options); err != nil {
+							conn.Close()
+							return err
+						}
 					}
 
 					connObj := t.vm.initObjectFromGoType(conn)
@@ -111,14 +162,14 @@ func builtinDBInstanceMethods() []*BuiltinMethodObject {
 					conn, err := getDBConn(t, receiver)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					err = conn.Close()
 
 					if err != nil {
 						if err != nil {
-							return t.vm.initErrorObject(errors.InternalError, "Error happens when closing DB connection: %s", err.Error())
+							return t.initErrorObject(errors.InternalError, "Error happens when closing DB connection: %s", err.Error())
 						}
 					}
 
@@ -130,35 +181,19 @@ func builtinDBInstanceMethods() []*BuiltinMethodObject {
 			Name: "run",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect at least 1 argument.")
-					}
-
 					conn, err := getDBConn(t, receiver)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
-					queryString := args[0].(*StringObject).value
-					execArgs := []interface{}{}
-
-					for _, arg := range args[1:] {
-						execArgs = append(execArgs, arg.(builtinType).Value())
-					}
-
-					_, err = conn.Exec(queryString, execArgs...)
-
-					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
-					}
-
-					return TRUE
+					return t.dbRun(conn, args)
 				}
 			},
 		},
 		{
-			// The exec method executes the Psql and automatically returns the data's primary key value
+			// The exec method executes the Psql and automatically returns the data's primary key value.
+			// With drivers other than 'postgres', it returns the last inserted id reported by the driver.
 			//
 			// ```ruby
 			// require "db"
@@ -188,33 +223,13 @@ func builtinDBInstanceMethods() []*BuiltinMethodObject {
 			Name: "exec",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect at least 1 argument.")
-					}
-
 					conn, err := getDBConn(t, receiver)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
-					}
-
-					queryString := args[0].(*StringObject).value
-					execArgs := []interface{}{}
-
-					for _, arg := range args[1:] {
-						execArgs = append(execArgs, arg.(builtinType).Value())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
-					// The reason I implement this way: https://github.com/lib/pq/issues/24
-					var id int
-
-					err = conn.QueryRow(fmt.Sprintf("%s RETURNING id", queryString), execArgs...).Scan(&id)
-
-					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
-					}
-
-					return t.vm.initIntegerObject(id)
+					return t.dbExec(conn, args)
 				}
 			},
 		},
@@ -251,53 +266,407 @@ func builtinDBInstanceMethods() []*BuiltinMethodObject {
 			Name: "query",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect at least 1 argument.")
+					conn, err := getDBConn(t, receiver)
+
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.dbQuery(conn, args)
+				}
+			},
+		},
+		{
+			// The stats method returns the statistics of the connection pool
+			//
+			// ```ruby
+			// require "db"
+			//
+			// db = DB.open("postgres://postgres@localhost/goby_doc?sslmode=disable", max_open_conns: 10)
+			// db.stats # => { idle: 1, in_use: 0, max_open_connections: 10, open_connections: 1, wait_count: 0 }
+			// ```
+			//
+			// @return [Hash]
+			//
+			Name: "stats",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					conn, err := getDBConn(t, receiver)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
-					queryString := args[0].(*StringObject).value
-					execArgs := []interface{}{}
+					stats := conn.Stats()
+
+					return t.vm.initHashObject(map[string]Object{
+						"in_use":               t.vm.initIntegerObject(stats.InUse),
+						"idle":                 t.vm.initIntegerObject(stats.Idle),
+						"max_open_connections": t.vm.initIntegerObject(stats.MaxOpenConnections),
+						"open_connections":     t.vm.initIntegerObject(stats.OpenConnections),
+						"wait_count":           t.vm.initIntegerObject(int(stats.WaitCount)),
+					})
+				}
+			},
+		},
+		{
+			// The transaction method runs the block in a transaction and returns the block's result. The
+			// transaction is committed after the block is executed, or rolled back if the block raises an error,
+			// which is raised again. It can also be finished in the block with Transaction#commit or
+			// Transaction#rollback.
+			//
+			// ```ruby
+			// require "db"
+			//
+			// db = DB.open("postgres", "user=postgres dbname=goby_doc sslmode=disable")
+			//
+			// db.transaction do |tx|
+			//   id = tx.exec("INSERT INTO users (name, age) VALUES ($1, $2)", "Stan", 23)
+			//   tx.exec("UPDATE accounts SET owner_id = $1 WHERE id = $2", id, 1)
+			// end
+			// ```
+			//
+			// @return [Object]
+			//
+			Name: "transaction",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
 
-					for _, arg := range args[1:] {
-						execArgs = append(execArgs, arg.(builtinType).Value())
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
 					}
 
-					rows, err := conn.Queryx(queryString, execArgs...)
+					conn, err := getDBConn(t, receiver)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
-					results := []Object{}
+					tx, err := conn.Beginx()
 
-					for rows.Next() {
-						row := make(map[string]interface{})
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
 
-						err = rows.MapScan(row)
+					txObj := &DBTransactionObject{
+						baseObj: &baseObj{class: t.vm.topLevelClass("DB").getClassConstant("Transaction")},
+						tx:      tx,
+					}
 
-						if err != nil {
-							return t.vm.initErrorObject(errors.InternalError, err.Error())
+					// The error raised in the block may be rescued outside, which unwinds this method by panicking
+					defer func() {
+						if !txObj.finished {
+							txObj.finished = true
+							tx.Rollback()
 						}
+					}()
+
+					result := t.builtinMethodYield(blockFrame, txObj)
+
+					if e, ok := result.Target.(*Error); ok && !e.rescued {
+						return e
+					}
 
-						data := map[string]Object{}
+					if !txObj.finished {
+						txObj.finished = true
 
-						for k, v := range row {
-							data[k] = t.vm.initObjectFromGoType(v)
+						if err := tx.Commit(); err != nil {
+							return t.initErrorObject(errors.InternalError, err.Error())
 						}
+					}
+
+					return result.Target
+				}
+			},
+		},
+	}
+}
+
+func builtinDBTransactionInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// The commit method commits the transaction, the block of DB#transaction won't commit it again
+			//
+			// @return [Boolean]
+			//
+			Name: "commit",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					tx := receiver.(*DBTransactionObject)
+					tx.finished = true
 
-						result := t.vm.initHashObject(data)
-						results = append(results, result)
+					if err := tx.tx.Commit(); err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
-					return t.vm.initArrayObject(results)
+					return TRUE
 				}
 			},
 		},
+		{
+			// Same as DB#exec, but runs in the transaction
+			//
+			// @return [Integer]
+			//
+			Name: "exec",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.dbExec(receiver.(*DBTransactionObject).tx, args)
+				}
+			},
+		},
+		{
+			// Same as DB#query, but runs in the transaction
+			//
+			// @return [Array]
+			//
+			Name: "query",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.dbQuery(receiver.(*DBTransactionObject).tx, args)
+				}
+			},
+		},
+		{
+			// The rollback method rolls back the transaction, the block of DB#transaction won't commit it
+			//
+			// ```ruby
+			// db.transaction do |tx|
+			//   tx.exec("DELETE FROM users")
+			//   tx.rollback
+			// end
+			// ```
+			//
+			// @return [Boolean]
+			//
+			Name: "rollback",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					tx := receiver.(*DBTransactionObject)
+					tx.finished = true
+
+					if err := tx.tx.Rollback(); err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Same as DB#run, but runs in the transaction
+			//
+			// @return [Boolean]
+			//
+			Name: "run",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.dbRun(receiver.(*DBTransactionObject).tx, args)
+				}
+			},
+		},
+	}
+}
+
+// Returns the object's name as the string format
+func (tx *DBTransactionObject) toString() string {
+	return "#<DB::Transaction>"
+}
+
+// Alias of toString
+func (tx *DBTransactionObject) toJSON() string {
+	return tx.toString()
+}
+
+// Returns the object
+func (tx *DBTransactionObject) Value() interface{} {
+	return tx.tx
+}
+
+// dbDriverName infers the name of the driver from the data source, and returns it with the data source the
+// driver accepts
+func dbDriverName(dataSource string) (string, string) {
+	switch {
+	case strings.HasPrefix(dataSource, "sqlite3://"):
+		return "sqlite3", strings.TrimPrefix(dataSource, "sqlite3://")
+	case strings.HasPrefix(dataSource, "sqlite://"):
+		return "sqlite3", strings.TrimPrefix(dataSource, "sqlite://")
+	case strings.HasPrefix(dataSource, "file:"):
+		return "sqlite3", dataSource
+	case strings.HasPrefix(dataSource, "postgresql://"):
+		return "postgres", dataSource
+	case strings.Contains(dataSource, "://"):
+		return dataSource[:strings.Index(dataSource, "://")], dataSource
+	default:
+		return "postgres", dataSource
+	}
+}
+
+// setDBPoolOptions configures the connection pool with the `max_open_conns:`, `max_idle_conns:` and
+// `conn_max_lifetime:` options
+func (t *thread) setDBPoolOptions(conn *sqlx.DB, options *HashObject) *Error {
+	for _, k := range options.sortedKeys() {
+		v := options.Pairs[k]
+
+		switch k {
+		case "max_open_conns", "max_idle_conns":
+			n, ok := v.(*IntegerObject)
+
+			if !ok {
+				return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, v.Class().Name)
+			}
+
+			if k == "max_open_conns" {
+				conn.SetMaxOpenConns(n.value)
+			} else {
+				conn.SetMaxIdleConns(n.value)
+			}
+		case "conn_max_lifetime":
+			var seconds float64
+
+			switch sec := v.(type) {
+			case *IntegerObject:
+				seconds = float64(sec.value)
+			case *FloatObject:
+				seconds = sec.value
+			default:
+				return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", v.Class().Name)
+			}
+
+			conn.SetConnMaxLifetime(time.Duration(seconds * float64(time.Second)))
+		default:
+			return t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+		}
+	}
+
+	return nil
+}
+
+// dbArguments returns the query string and its parameters from the arguments of run, exec and query
+func (t *thread) dbArguments(args []Object) (string, []interface{}, *Error) {
+	if len(args) < 1 {
+		return "", nil, t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument.")
+	}
+
+	query, ok := args[0].(*StringObject)
+
+	if !ok {
+		return "", nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	execArgs := []interface{}{}
+
+	for _, arg := range args[1:] {
+		execArgs = append(execArgs, arg.(builtinType).Value())
+	}
+
+	return query.value, execArgs, nil
+}
+
+// dbRun executes the statement without returning any rows
+func (t *thread) dbRun(ext sqlx.Ext, args []Object) Object {
+	queryString, execArgs, e := t.dbArguments(args)
+
+	if e != nil {
+		return e
+	}
+
+	_, err := ext.Exec(queryString, execArgs...)
+
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return TRUE
+}
+
+// dbExec executes the statement and returns the id of the affected row
+func (t *thread) dbExec(ext sqlx.Ext, args []Object) Object {
+	queryString, execArgs, e := t.dbArguments(args)
+
+	if e != nil {
+		return e
+	}
+
+	if ext.DriverName() == "postgres" {
+		// The reason I implement this way: https://github.com/lib/pq/issues/24
+		var id int
+
+		err := ext.QueryRowx(fmt.Sprintf("%s RETURNING id", queryString), execArgs...).Scan(&id)
+
+		if err != nil {
+			return t.initErrorObject(errors.InternalError, err.Error())
+		}
+
+		return t.vm.initIntegerObject(id)
 	}
+
+	result, err := ext.Exec(queryString, execArgs...)
+
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	id, err := result.LastInsertId()
+
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return t.vm.initIntegerObject(int(id))
+}
+
+// dbQuery runs the query and returns the rows as an array of hashes
+func (t *thread) dbQuery(ext sqlx.Ext, args []Object) Object {
+	queryString, execArgs, e := t.dbArguments(args)
+
+	if e != nil {
+		return e
+	}
+
+	rows, err := ext.Queryx(queryString, execArgs...)
+
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	defer rows.Close()
+
+	results := []Object{}
+
+	for rows.Next() {
+		row := make(map[string]interface{})
+
+		err = rows.MapScan(row)
+
+		if err != nil {
+			return t.initErrorObject(errors.InternalError, err.Error())
+		}
+
+		data := map[string]Object{}
+
+		for k, v := range row {
+			if tm, ok := v.(time.Time); ok {
+				data[k] = t.vm.initTimeObject(tm)
+			} else {
+				data[k] = t.vm.initObjectFromGoType(v)
+			}
+		}
+
+		result := t.vm.initHashObject(data)
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return t.vm.initArrayObject(results)
 }
@@ -0,0 +1,7 @@
+//go:build sqlite
+// +build sqlite
+
+package vm
+
+// The sqlite3 driver requires cgo, so it's only linked into binaries built with `go build -tags sqlite`
+import _ "github.com/mattn/go-sqlite3"
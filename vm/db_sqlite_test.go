@@ -0,0 +1,53 @@
+//go:build sqlite
+// +build sqlite
+
+package vm
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSQLiteDB(t *testing.T) {
+	dir, err := os.MkdirTemp("", "goby_sqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "db"
+
+		db = DB.open("sqlite3://%s")
+		db.run("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+		db.exec("INSERT INTO users (name) VALUES (?)", "Stan")
+		db.query("SELECT name FROM users WHERE id = ?", 1).first[:name]
+		`, "Stan"},
+		{`
+		require "db"
+
+		db = DB.open("sqlite3", "%s")
+		db.run("CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT)")
+		db.transaction do |tx|
+		  tx.exec("INSERT INTO users (name) VALUES (?)", "Stan")
+		  tx.rollback
+		end
+		db.query("SELECT name FROM users").length
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := fmt.Sprintf(tt.input, filepath.Join(dir, fmt.Sprintf("test%d.db", i)))
+		evaluated := v.testEval(t, input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -1,11 +1,134 @@
 package vm
 
 import (
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/jmoiron/sqlx"
 )
 
+// logDriver is a database/sql driver that logs the statements executed in each database, and returns the log
+// for the "SELECT log" query
+type logDriver struct {
+	sync.Mutex
+	logs map[string][]string
+}
+
+type logConn struct {
+	driver *logDriver
+	name   string
+}
+
+type logStmt struct {
+	conn  *logConn
+	query string
+}
+
+type logRows struct {
+	statements []string
+}
+
+// logResult is the number of the logged statements, which is also used as the last inserted id
+type logResult int64
+
+func (d *logDriver) Open(name string) (driver.Conn, error) {
+	return &logConn{driver: d, name: name}, nil
+}
+
+func (c *logConn) log(statement string) int {
+	c.driver.Lock()
+	defer c.driver.Unlock()
+
+	c.driver.logs[c.name] = append(c.driver.logs[c.name], statement)
+	return len(c.driver.logs[c.name])
+}
+
+func (c *logConn) Prepare(query string) (driver.Stmt, error) {
+	return &logStmt{conn: c, query: query}, nil
+}
+
+func (c *logConn) Close() error {
+	return nil
+}
+
+func (c *logConn) Begin() (driver.Tx, error) {
+	c.log("BEGIN")
+	return c, nil
+}
+
+func (c *logConn) Commit() error {
+	c.log("COMMIT")
+	return nil
+}
+
+func (c *logConn) Rollback() error {
+	c.log("ROLLBACK")
+	return nil
+}
+
+func (s *logStmt) Close() error {
+	return nil
+}
+
+func (s *logStmt) NumInput() int {
+	return -1
+}
+
+func (s *logStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if strings.HasPrefix(s.query, "FAIL") {
+		return nil, fmt.Errorf("syntax error at %s", s.query)
+	}
+
+	statement := s.query
+	for _, arg := range args {
+		statement += fmt.Sprintf(" %v", arg)
+	}
+
+	return logResult(s.conn.log(statement)), nil
+}
+
+func (s *logStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.conn.driver.Lock()
+	defer s.conn.driver.Unlock()
+
+	return &logRows{statements: append([]string{}, s.conn.driver.logs[s.conn.name]...)}, nil
+}
+
+func (r logResult) LastInsertId() (int64, error) {
+	return int64(r), nil
+}
+
+func (r logResult) RowsAffected() (int64, error) {
+	return 1, nil
+}
+
+func (r *logRows) Columns() []string {
+	return []string{"statement"}
+}
+
+func (r *logRows) Close() error {
+	return nil
+}
+
+func (r *logRows) Next(dest []driver.Value) error {
+	if len(r.statements) == 0 {
+		return io.EOF
+	}
+
+	dest[0] = r.statements[0]
+	r.statements = r.statements[1:]
+	return nil
+}
+
+func init() {
+	sql.Register("goby_log", &logDriver{logs: map[string][]string{}})
+}
+
 func setupDB(t *testing.T) *sqlx.DB {
 	db, _ := sqlx.Open("postgres", "user=postgres dbname=goby_test sslmode=disable")
 	_, err := db.Exec(`
@@ -162,3 +285,191 @@ func TestDBExec(t *testing.T) {
 		cleanTable()
 	}
 }
+
+func TestDBTransaction(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "commit")
+		db.transaction do |tx|
+		  tx.run("INSERT 1")
+		  tx.exec("INSERT", 2)
+		end
+		`, 3},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "query")
+		db.transaction do |tx|
+		  tx.run("INSERT 1")
+		end
+		db.query("SELECT log").map do |row|
+		  row[:statement]
+		end.join(", ")
+		`, "BEGIN, INSERT 1, COMMIT"},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "error")
+		begin
+		  db.transaction do |tx|
+		    tx.run("INSERT 1")
+		    raise "Stop"
+		  end
+		rescue => e
+		  e.message
+		end
+		`, "Stop"},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "raise")
+		begin
+		  db.transaction do |tx|
+		    tx.run("INSERT 1")
+		    tx.run("FAIL")
+		  end
+		rescue
+		end
+		db.query("SELECT log").map do |row|
+		  row[:statement]
+		end.join(", ")
+		`, "BEGIN, INSERT 1, ROLLBACK"},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "rescue")
+		db.transaction do |tx|
+		  begin
+		    tx.run("FAIL")
+		  rescue
+		  end
+		  tx.run("INSERT 1")
+		end
+		db.query("SELECT log").map do |row|
+		  row[:statement]
+		end.join(", ")
+		`, "BEGIN, INSERT 1, COMMIT"},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "rollback")
+		db.transaction do |tx|
+		  tx.run("INSERT 1")
+		  tx.rollback
+		end
+		db.query("SELECT log").map do |row|
+		  row[:statement]
+		end.join(", ")
+		`, "BEGIN, INSERT 1, ROLLBACK"},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "commit_in_block")
+		db.transaction do |tx|
+		  tx.commit
+		end
+		db.query("SELECT log").map do |row|
+		  row[:statement]
+		end.join(", ")
+		`, "BEGIN, COMMIT"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDBConnectionPool(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "pool", max_open_conns: 5, max_idle_conns: 2, conn_max_lifetime: 1.5)
+		db.stats[:max_open_connections]
+		`, 5},
+		{`
+		require "db"
+
+		db = DB.open("goby_log", "pool")
+		db.run("INSERT 1")
+		db.stats[:open_connections]
+		`, 1},
+		{`
+		require "db"
+
+		db = DB.open("goby_log://pool")
+		db.ping
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDBDriverName(t *testing.T) {
+	tests := []struct {
+		dataSource string
+		driverName string
+		source     string
+	}{
+		{"postgres://postgres@localhost/goby_test?sslmode=disable", "postgres", "postgres://postgres@localhost/goby_test?sslmode=disable"},
+		{"postgresql://localhost/goby_test", "postgres", "postgresql://localhost/goby_test"},
+		{"user=postgres sslmode=disable", "postgres", "user=postgres sslmode=disable"},
+		{"sqlite3://goby.db", "sqlite3", "goby.db"},
+		{"sqlite:///tmp/goby.db", "sqlite3", "/tmp/goby.db"},
+		{"file:goby.db?cache=shared", "sqlite3", "file:goby.db?cache=shared"},
+		{"goby_log://pool", "goby_log", "goby_log://pool"},
+	}
+
+	for i, tt := range tests {
+		driverName, source := dbDriverName(tt.dataSource)
+
+		if driverName != tt.driverName || source != tt.source {
+			t.Errorf("At case %d expect %s driver with %q. got: %s driver with %q", i, tt.driverName, tt.source, driverName, source)
+		}
+	}
+}
+
+func TestDBMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "db"
+		DB.get_connection("goby_log", "pool", pool: 1)`, "ArgumentError: Unknown keyword: pool", 2},
+		{`require "db"
+		DB.get_connection("goby_log", "pool", max_open_conns: "1")`, "TypeError: Expect argument to be Integer. got: String", 2},
+		{`require "db"
+		DB.get_connection(1)`, "ArgumentError: Expect database's data source to be a String object. got: Integer", 2},
+		{`require "db"
+		DB.get_connection("goby_log", "a", "b")`, "ArgumentError: Expect 1..2 arguments. got: 3", 2},
+		{`require "db"
+		DB.open("goby_log", "fail").transaction`, "InternalError: Can't yield without a block", 2},
+		{`require "db"
+		DB.open("goby_log", "fail").run(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "db"
+		DB.open("goby_log", "fail").run("FAIL")`, "InternalError: syntax error at FAIL", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,233 @@
+package vm
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"hash"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// digestAlgorithms are the hash functions of the `Digest` classes, which are also the digests `OpenSSL::HMAC` supports
+var digestAlgorithms = map[string]func() hash.Hash{
+	"MD5":    md5.New,
+	"SHA1":   sha1.New,
+	"SHA256": sha256.New,
+	"SHA384": sha512.New384,
+	"SHA512": sha512.New,
+}
+
+// Class methods --------------------------------------------------------
+
+// `Digest::MD5`, `Digest::SHA1`, `Digest::SHA256`, `Digest::SHA384` and `Digest::SHA512` share these methods.
+// Use `require "digest"` to load them.
+//
+// ```ruby
+// require "digest"
+//
+// Digest::SHA256.hexdigest("goby") # => "6be3cc69f46455fe0e94ce3034427916d71d17862d12bc9a5d1c7ce0e3e1734e"
+// Digest::MD5.base64digest("goby")
+// ```
+func builtinDigestClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the base64-encoded digest of the string.
+			//
+			// @param string [String]
+			// @return [String]
+			Name: "base64digest",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sum, err := t.digestSum(receiver, args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(base64.StdEncoding.EncodeToString(sum))
+				}
+			},
+		},
+		{
+			// Returns the digest of the string as raw bytes.
+			//
+			// @param string [String]
+			// @return [String]
+			Name: "digest",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sum, err := t.digestSum(receiver, args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(string(sum))
+				}
+			},
+		},
+		{
+			// Returns the hex-encoded digest of the string.
+			//
+			// @param string [String]
+			// @return [String]
+			Name: "hexdigest",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sum, err := t.digestSum(receiver, args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(hex.EncodeToString(sum))
+				}
+			},
+		},
+	}
+}
+
+// `OpenSSL::HMAC` signs messages with a key, for example to generate the signatures of API requests.
+// The digest can be a name like "SHA256" or a `Digest` class. Use `require "openssl"` to load it.
+//
+// ```ruby
+// require "openssl"
+//
+// OpenSSL::HMAC.hexdigest("SHA256", "secret", "GET /orders") # => "6437ed8bb9899709b3d01886ef60e4f7acbf8a8bf5817b12a3b1df41c737a890"
+// ```
+func builtinHMACClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the base64-encoded HMAC of the data.
+			//
+			// @param digest [String/Class], key [String], data [String]
+			// @return [String]
+			Name: "base64digest",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sum, err := t.hmacSum(args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(base64.StdEncoding.EncodeToString(sum))
+				}
+			},
+		},
+		{
+			// Returns the HMAC of the data as raw bytes.
+			//
+			// @param digest [String/Class], key [String], data [String]
+			// @return [String]
+			Name: "digest",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sum, err := t.hmacSum(args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(string(sum))
+				}
+			},
+		},
+		{
+			// Returns the hex-encoded HMAC of the data.
+			//
+			// @param digest [String/Class], key [String], data [String]
+			// @return [String]
+			Name: "hexdigest",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sum, err := t.hmacSum(args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(hex.EncodeToString(sum))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initDigestModule(vm *VM) {
+	digest := vm.initializeClass("Digest", true)
+
+	for name := range digestAlgorithms {
+		class := vm.initializeClass(name, false)
+		class.setBuiltinMethods(builtinDigestClassMethods(), true)
+		digest.setClassConstant(class)
+	}
+
+	vm.objectClass.setClassConstant(digest)
+}
+
+func initOpenSSLModule(vm *VM) {
+	openssl := vm.initializeClass("OpenSSL", true)
+	hmacClass := vm.initializeClass("HMAC", false)
+	hmacClass.setBuiltinMethods(builtinHMACClassMethods(), true)
+	openssl.setClassConstant(hmacClass)
+	vm.objectClass.setClassConstant(openssl)
+}
+
+// Other helper functions -----------------------------------------------
+
+// digestSum hashes the only argument with the receiver's algorithm
+func (t *thread) digestSum(receiver Object, args []Object) ([]byte, *Error) {
+	if len(args) != 1 {
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	s, ok := args[0].(*StringObject)
+	if !ok {
+		return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	h := digestAlgorithms[receiver.(*RClass).Name]()
+	h.Write([]byte(s.value))
+
+	return h.Sum(nil), nil
+}
+
+// hmacSum signs the data with the key and the digest, which are given as (digest, key, data)
+func (t *thread) hmacSum(args []Object) ([]byte, *Error) {
+	if len(args) != 3 {
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 3 arguments. got: %d", len(args))
+	}
+
+	var name string
+
+	switch d := args[0].(type) {
+	case *StringObject:
+		name = strings.ToUpper(strings.Replace(d.value, "-", "", -1))
+	case *RClass:
+		name = d.Name
+	default:
+		return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	newHash, ok := digestAlgorithms[name]
+	if !ok {
+		return nil, t.initErrorObject(errors.ArgumentError, "Unknown digest: %s", args[0].toString())
+	}
+
+	for _, arg := range args[1:] {
+		if _, ok := arg.(*StringObject); !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+		}
+	}
+
+	mac := hmac.New(newHash, []byte(args[1].(*StringObject).value))
+	mac.Write([]byte(args[2].(*StringObject).value))
+
+	return mac.Sum(nil), nil
+}
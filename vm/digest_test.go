@@ -0,0 +1,105 @@
+package vm
+
+import "testing"
+
+func TestDigestClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "digest"
+		Digest::MD5.hexdigest("goby")
+		`, "b94a3fce1552eedfe1caab8775922bd2"},
+		{`
+		require "digest"
+		Digest::SHA1.hexdigest("goby")
+		`, "2b33d6ddce37422ac54dc57ae062dc49aa430979"},
+		{`
+		require "digest"
+		Digest::SHA256.hexdigest("goby")
+		`, "6be3cc69f46455fe0e94ce3034427916d71d17862d12bc9a5d1c7ce0e3e1734e"},
+		{`
+		require "digest"
+		Digest::SHA1.base64digest("goby")
+		`, "KzPW3c43QirFTcV64GLcSapDCXk="},
+		{`
+		require "digest"
+		Digest::SHA512.hexdigest("").size
+		`, 128},
+		{`
+		require "digest"
+		Digest::SHA384.hexdigest("").size
+		`, 96},
+		{`
+		require "digest"
+		Digest::MD5.digest("goby") == Digest::MD5.digest("goby")
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHMACClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "openssl"
+		OpenSSL::HMAC.hexdigest("SHA256", "secret", "GET /orders")
+		`, "6437ed8bb9899709b3d01886ef60e4f7acbf8a8bf5817b12a3b1df41c737a890"},
+		{`
+		require "openssl"
+		OpenSSL::HMAC.hexdigest("sha-256", "secret", "GET /orders")
+		`, "6437ed8bb9899709b3d01886ef60e4f7acbf8a8bf5817b12a3b1df41c737a890"},
+		{`
+		require "openssl"
+		OpenSSL::HMAC.base64digest("SHA256", "secret", "GET /orders")
+		`, "ZDfti7mJlwmz0BiG72Dk96y/iov1gXsSo7HfQcc3qJA="},
+		{`
+		require "digest"
+		require "openssl"
+		OpenSSL::HMAC.hexdigest(Digest::SHA1, "secret", "GET /orders")
+		`, "07644b62e484970592873a23418560b4bd0e24fe"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDigestMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "digest"
+		Digest::SHA256.hexdigest`, "ArgumentError: Expect 1 argument. got: 0", 2},
+		{`require "digest"
+		Digest::SHA256.hexdigest(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "openssl"
+		OpenSSL::HMAC.hexdigest("SHA256", "secret")`, "ArgumentError: Expect 3 arguments. got: 2", 2},
+		{`require "openssl"
+		OpenSSL::HMAC.hexdigest("SHA3", "secret", "data")`, "ArgumentError: Unknown digest: SHA3", 2},
+		{`require "openssl"
+		OpenSSL::HMAC.hexdigest(256, "secret", "data")`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "openssl"
+		OpenSSL::HMAC.hexdigest("SHA256", "secret", 1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,332 @@
+package vm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Dir provides class methods for listing, creating and changing directories, which makes it possible to
+// write build or automation scripts in Goby. Relative paths are resolved against the current working directory.
+//
+// ```ruby
+// Dir.mkdir("build")
+// Dir.chdir("build") do
+//   File.write("VERSION", "0.1.0")
+// end
+// Dir.glob("**/*.gb") # => ["lib/file.gb", "samples/hello.gb"]
+// ```
+
+// Class methods --------------------------------------------------------
+func builtinDirClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Changes the current working directory to the given path, or the home directory if the path is omitted.
+			// If a block is given, the original working directory is restored after the block is executed, and the
+			// block's return value is returned. Otherwise it returns 0.
+			//
+			// ```ruby
+			// Dir.chdir("/tmp")
+			// Dir.pwd # => "/tmp"
+			//
+			// Dir.chdir("/home/goby") do
+			//   Dir.pwd # => "/home/goby"
+			// end
+			// Dir.pwd # => "/tmp"
+			// ```
+			//
+			// @param path [String]
+			// @return [Object]
+			Name: "chdir",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					var dir string
+
+					switch len(args) {
+					case 0:
+						home, err := os.UserHomeDir()
+						if err != nil {
+							return t.initErrorObject(errors.InternalError, err.Error())
+						}
+
+						dir = home
+					case 1:
+						path, ok := args[0].(*StringObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						}
+
+						dir = path.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					original, err := os.Getwd()
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					if err := os.Chdir(dir); err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					if blockFrame == nil {
+						return t.vm.initIntegerObject(0)
+					}
+
+					defer os.Chdir(original)
+
+					return t.builtinMethodYield(blockFrame).Target
+				}
+			},
+		},
+		{
+			// Returns the names of all entries in the given directory, including "." and "..", in lexical order.
+			//
+			// ```ruby
+			// Dir.entries("lib") # => [".", "..", "db.gb", "file.gb", "net", "plugin.gb"]
+			// ```
+			//
+			// @param path [String]
+			// @return [Array]
+			Name: "entries",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					dir, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					files, err := ioutil.ReadDir(dir)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					entries := []Object{t.vm.initStringObject("."), t.vm.initStringObject("..")}
+					for _, f := range files {
+						entries = append(entries, t.vm.initStringObject(f.Name()))
+					}
+
+					return t.vm.initArrayObject(entries)
+				}
+			},
+		},
+		{
+			// Returns true if the given path is an existing directory, false otherwise.
+			//
+			// ```ruby
+			// Dir.exist?("lib")         # => true
+			// Dir.exist?("lib/file.gb") # => false
+			// ```
+			//
+			// @param path [String]
+			// @return [Boolean]
+			Name: "exist?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					dir, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					info, err := os.Stat(dir)
+
+					return toBooleanObject(err == nil && info.IsDir())
+				}
+			},
+		},
+		{
+			// Returns the paths that match the given pattern, in lexical order. Besides the patterns supported by
+			// Go's `filepath.Match`, a "**" segment matches any number of directories, including none.
+			//
+			// ```ruby
+			// Dir.glob("lib/*.gb")    # => ["lib/db.gb", "lib/file.gb", "lib/plugin.gb"]
+			// Dir.glob("lib/**/*.gb") # => ["lib/db.gb", "lib/file.gb", "lib/net/http.gb", "lib/plugin.gb"]
+			// ```
+			//
+			// @param pattern [String]
+			// @return [Array]
+			Name: "glob",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					pattern, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					matches, err := glob(pattern)
+					if err != nil {
+						return t.initErrorObject(errors.ArgumentError, "Invalid glob pattern: %s", pattern)
+					}
+
+					paths := []Object{}
+					for _, m := range matches {
+						paths = append(paths, t.vm.initStringObject(m))
+					}
+
+					return t.vm.initArrayObject(paths)
+				}
+			},
+		},
+		{
+			// Creates a directory with the given path and permission, which is 0755 by default. It returns 0.
+			//
+			// ```ruby
+			// Dir.mkdir("build")       # => 0
+			// Dir.mkdir("cache", 0700) # => 0
+			// ```
+			//
+			// @param path [String], permission [Integer]
+			// @return [Integer]
+			Name: "mkdir",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+					}
+
+					dir, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					perm := os.FileMode(0755)
+
+					if len(args) == 2 {
+						p, ok := args[1].(*IntegerObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+						}
+
+						perm = os.FileMode(p.value)
+					}
+
+					if err := os.Mkdir(dir.value, perm); err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initIntegerObject(0)
+				}
+			},
+		},
+		{
+			// Returns the current working directory.
+			//
+			// ```ruby
+			// Dir.pwd # => "/home/goby"
+			// ```
+			//
+			// @return [String]
+			Name: "pwd",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					dir, err := os.Getwd()
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initStringObject(dir)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initDirClass() *RClass {
+	dc := vm.initializeClass(classes.DirClass, false)
+	dc.setBuiltinMethods(builtinDirClassMethods(), true)
+	return dc
+}
+
+// Other helper functions -----------------------------------------------
+
+// glob returns the paths that match pattern, where a "**" segment matches any number of directories.
+// Patterns without "**" are handled by filepath.Glob directly.
+func glob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		return filepath.Glob(pattern)
+	}
+
+	// Check the pattern's syntax up front since filepath.Match only reports it when the matching reaches a bad part
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+
+	// Walk from the longest prefix that doesn't contain any meta characters
+	i := 0
+	for i < len(segments)-1 && !strings.ContainsAny(segments[i], "*?[\\") {
+		i++
+	}
+
+	root := strings.Join(segments[:i], "/")
+	if root == "" && i > 0 {
+		root = "/"
+	}
+
+	walkRoot := root
+	if walkRoot == "" {
+		walkRoot = "."
+	}
+
+	matches := []string{}
+
+	err := filepath.Walk(walkRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(walkRoot, path)
+		if rel == "." {
+			return nil
+		}
+
+		if matchSegments(segments[i:], strings.Split(filepath.ToSlash(rel), "/")) {
+			matches = append(matches, filepath.Join(root, rel))
+		}
+
+		return nil
+	})
+
+	return matches, err
+}
+
+// matchSegments reports whether the path segments match the pattern segments, "**" matches zero or more segments.
+func matchSegments(patterns, segments []string) bool {
+	if len(patterns) == 0 {
+		return len(segments) == 0
+	}
+
+	if patterns[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(patterns[1:], segments[i:]) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+
+	if ok, _ := filepath.Match(patterns[0], segments[0]); !ok {
+		return false
+	}
+
+	return matchSegments(patterns[1:], segments[1:])
+}
@@ -0,0 +1,143 @@
+package vm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDirEntriesMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Dir.entries("../test_fixtures/dir_test").to_s`, `[".", "..", "a.gb", "b.txt", "nested"]`},
+		{`Dir.entries("../test_fixtures/dir_test/nested").to_s`, `[".", "..", "c.gb"]`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDirExistMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Dir.exist?("../test_fixtures/dir_test")`, true},
+		{`Dir.exist?("../test_fixtures/dir_test/a.gb")`, false},
+		{`Dir.exist?("../test_fixtures/no_such_dir")`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDirGlobMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Dir.glob("../test_fixtures/dir_test/*.gb").to_s`, `["../test_fixtures/dir_test/a.gb"]`},
+		{`Dir.glob("../test_fixtures/dir_test/*").to_s`, `["../test_fixtures/dir_test/a.gb", "../test_fixtures/dir_test/b.txt", "../test_fixtures/dir_test/nested"]`},
+		{`Dir.glob("../test_fixtures/dir_test/**/*.gb").to_s`, `["../test_fixtures/dir_test/a.gb", "../test_fixtures/dir_test/nested/c.gb"]`},
+		{`Dir.glob("../test_fixtures/**/c.gb").to_s`, `["../test_fixtures/dir_test/nested/c.gb"]`},
+		{`Dir.glob("../test_fixtures/dir_test/*.rb").length`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDirMkdirAndChdirMethods(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+
+	tmp, err := ioutil.TempDir("", "goby_dir_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	// Resolve symlinks like /tmp on macOS so it can be compared with Dir.pwd
+	tmp, _ = filepath.EvalSymlinks(tmp)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{fmt.Sprintf(`
+		Dir.mkdir("%s/build")
+		`, tmp), 0},
+		{fmt.Sprintf(`
+		Dir.mkdir("%s/cache", 0700)
+		Dir.exist?("%s/cache")
+		`, tmp, tmp), true},
+		{fmt.Sprintf(`
+		Dir.chdir("%s") do
+		  Dir.pwd
+		end
+		`, tmp), tmp},
+		{fmt.Sprintf(`
+		pwd = Dir.pwd
+		Dir.chdir("%s") do
+		  File.write("VERSION", "0.1.0")
+		end
+		Dir.pwd == pwd
+		`, tmp), true},
+		{fmt.Sprintf(`
+		File.read("%s/VERSION")
+		`, tmp), "0.1.0"},
+		{fmt.Sprintf(`
+		Dir.chdir("%s")
+		`, tmp), 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestDirMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Dir.entries`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`Dir.entries(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Dir.entries("../test_fixtures/no_such_dir")`, "InternalError: open ../test_fixtures/no_such_dir: no such file or directory", 1},
+		{`Dir.glob("../test_fixtures/[")`, "ArgumentError: Invalid glob pattern: ../test_fixtures/[", 1},
+		{`Dir.mkdir`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`Dir.mkdir("../test_fixtures/dir_test", "0755")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Dir.mkdir("../test_fixtures/dir_test")`, "InternalError: mkdir ../test_fixtures/dir_test: file exists", 1},
+		{`Dir.chdir("a", "b")`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`Dir.chdir("../test_fixtures/no_such_dir")`, "InternalError: chdir ../test_fixtures/no_such_dir: no such file or directory", 1},
+		{`Dir.pwd(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
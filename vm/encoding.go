@@ -0,0 +1,212 @@
+package vm
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+const (
+	utf8Encoding    = "UTF-8"
+	binaryEncoding  = "ASCII-8BIT"
+	usASCIIEncoding = "US-ASCII"
+)
+
+// EncodingObject represents a character encoding
+// Every String has an encoding, which is `Encoding::UTF_8` by default.
+// The encoding of a String can be changed with `String#force_encoding`, which only changes how the
+// bytes are labeled, the bytes themselves are kept as they are.
+//
+// ```ruby
+// "Goby".encoding                              # => Encoding::UTF_8
+// "Goby".force_encoding("ASCII-8BIT").encoding # => Encoding::ASCII_8BIT
+// Encoding.find("utf-8") == Encoding::UTF_8    # => true
+// ```
+//
+// Available encodings are:
+//
+// - `Encoding::UTF_8`
+// - `Encoding::ASCII_8BIT` (also available as `Encoding::BINARY`)
+// - `Encoding::US_ASCII`
+//
+// **Note:**
+//
+// - String manipulations are always based on UTF-8 characters, regardless of the encoding.
+// - `Encoding.new` is not supported.
+type EncodingObject struct {
+	*baseObj
+	name string
+}
+
+// Class methods --------------------------------------------------------
+func builtinEncodingClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the encoding of given name, the name is case insensitive.
+			//
+			// ```ruby
+			// Encoding.find("utf-8")  # => Encoding::UTF_8
+			// Encoding.find("BINARY") # => Encoding::ASCII_8BIT
+			// ```
+			//
+			// @return [Encoding]
+			Name: "find",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					n := args[0]
+					name, ok := n.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, n.Class().Name)
+					}
+
+					e := t.vm.findEncoding(name.value)
+
+					if e == nil {
+						return t.initErrorObject(errors.ArgumentError, "Unknown encoding name: %s", name.value)
+					}
+
+					return e
+				}
+			},
+		},
+		{
+			// Returns an array of all available encodings.
+			//
+			// ```ruby
+			// Encoding.list # => [Encoding::UTF_8, Encoding::ASCII_8BIT, Encoding::US_ASCII]
+			// ```
+			//
+			// @return [Array]
+			Name: "list",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					elems := []Object{}
+
+					for _, name := range []string{utf8Encoding, binaryEncoding, usASCIIEncoding} {
+						elems = append(elems, t.vm.findEncoding(name))
+					}
+
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinEncodingInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the name of the encoding.
+			//
+			// ```ruby
+			// Encoding::UTF_8.name # => "UTF-8"
+			// ```
+			//
+			// @return [String]
+			Name: "name",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*EncodingObject).name)
+				}
+			},
+		},
+		{
+			// Returns the name of the encoding.
+			//
+			// ```ruby
+			// Encoding::BINARY.to_s # => "ASCII-8BIT"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initEncodingClass() *RClass {
+	ec := vm.initializeClass(classes.EncodingClass, false)
+	ec.setBuiltinMethods(builtinEncodingInstanceMethods(), false)
+	ec.setBuiltinMethods(builtinEncodingClassMethods(), true)
+
+	utf8Enc := &EncodingObject{baseObj: &baseObj{class: ec}, name: utf8Encoding}
+	binaryEnc := &EncodingObject{baseObj: &baseObj{class: ec}, name: binaryEncoding}
+	usASCIIEnc := &EncodingObject{baseObj: &baseObj{class: ec}, name: usASCIIEncoding}
+
+	ec.constants["UTF_8"] = &Pointer{Target: utf8Enc}
+	ec.constants["ASCII_8BIT"] = &Pointer{Target: binaryEnc}
+	ec.constants["BINARY"] = &Pointer{Target: binaryEnc}
+	ec.constants["US_ASCII"] = &Pointer{Target: usASCIIEnc}
+
+	return ec
+}
+
+// findEncoding returns the encoding of given name (case insensitive), or nil if there's no such encoding
+func (vm *VM) findEncoding(name string) *EncodingObject {
+	if strings.EqualFold(name, "BINARY") {
+		name = binaryEncoding
+	}
+
+	for _, p := range vm.topLevelClass(classes.EncodingClass).constants {
+		e, ok := p.Target.(*EncodingObject)
+
+		if ok && strings.EqualFold(e.name, name) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the encoding's name
+func (e *EncodingObject) toString() string {
+	return e.name
+}
+
+// Returns the encoding's name as a JSON string
+func (e *EncodingObject) toJSON() string {
+	return strconv.Quote(e.toString())
+}
+
+// valid returns true if str is a valid byte sequence in the encoding
+func (e *EncodingObject) valid(str string) bool {
+	switch e.name {
+	case utf8Encoding:
+		return utf8.ValidString(str)
+	case usASCIIEncoding:
+		for i := 0; i < len(str); i++ {
+			if str[i] >= utf8.RuneSelf {
+				return false
+			}
+		}
+	}
+
+	return true
+}
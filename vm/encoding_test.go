@@ -0,0 +1,50 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestEncodingClass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Encoding.class.name`, "Class"},
+		{`Encoding::UTF_8.class.name`, "Encoding"},
+		{`Encoding::UTF_8.name`, "UTF-8"},
+		{`Encoding::ASCII_8BIT.to_s`, "ASCII-8BIT"},
+		{`Encoding::BINARY.name`, "ASCII-8BIT"},
+		{`Encoding::US_ASCII.name`, "US-ASCII"},
+		{`Encoding::BINARY == Encoding::ASCII_8BIT`, true},
+		{`Encoding::UTF_8 == Encoding::US_ASCII`, false},
+		{`Encoding.find("utf-8") == Encoding::UTF_8`, true},
+		{`Encoding.find("binary").name`, "ASCII-8BIT"},
+		{`Encoding.list.length`, 3},
+		{`Encoding.list[0].name`, "UTF-8"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEncodingClassFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Encoding.find("Big5")`, "ArgumentError: Unknown encoding name: Big5", 1},
+		{`Encoding.find(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Encoding.find`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`Encoding.new`, "UnsupportedMethodError: Unsupported Method #new for Encoding", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
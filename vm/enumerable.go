@@ -0,0 +1,911 @@
+package vm
+
+import (
+	"sort"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Enumerable is a module that provides collection methods to the classes that include it.
+// All methods are based on the `each` method of the receiver, which should yield every element
+// of the collection to the given block.
+//
+// ```ruby
+// class NumberList
+//   include(Enumerable)
+//
+//   def initialize(*numbers)
+//     @numbers = numbers
+//   end
+//
+//   def each
+//     @numbers.each do |n|
+//       yield(n)
+//     end
+//   end
+// end
+//
+// list = NumberList.new(3, 1, 2)
+// list.map do |n|
+//   n * 2
+// end                 # => [6, 2, 4]
+// list.find do |n|
+//   n < 3
+// end                 # => 1
+// ```
+//
+// When `each` yields more than one value, like `Hash#each` does, the values are passed to the block as they are,
+// and the element is an array of them:
+//
+// ```ruby
+// h = { a: 1, b: 2 }
+// h.find do |k, v|
+//   v > 1
+// end # => ["b", 2]
+// ```
+//
+// Array, Hash and Range include Enumerable.
+
+// Instance methods -----------------------------------------------------
+func builtinEnumerableInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the block never returns false or nil. Without a block, returns true if
+			// none of the elements is false or nil.
+			//
+			// ```ruby
+			// [2, 4].all? do |n|
+			//   n % 2 == 0
+			// end                # => true
+			// [1, nil].all?      # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "all?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					result := TRUE
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						test, err := t.testElement(blockFrame, values)
+
+						if err != nil {
+							return false, err
+						}
+
+						if !test {
+							result = FALSE
+						}
+
+						return test, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return result
+				}
+			},
+		},
+		{
+			// Returns true if the block returns a value other than false or nil for any element. Without a block,
+			// returns true if any of the elements is not false or nil.
+			//
+			// ```ruby
+			// [1, 2].any? do |n|
+			//   n > 1
+			// end              # => true
+			// [nil, false].any? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "any?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					result := FALSE
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						test, err := t.testElement(blockFrame, values)
+
+						if err != nil {
+							return false, err
+						}
+
+						if test {
+							result = TRUE
+						}
+
+						return !test, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return result
+				}
+			},
+		},
+		{
+			// Returns the number of elements. With an argument, returns the number of elements that are equal to it,
+			// which are compared with `==`. With a block, returns the number of elements for which the block returns
+			// a value other than false or nil.
+			//
+			// ```ruby
+			// (1..4).count          # => 4
+			// { a: 1, b: 2 }.count do |k, v|
+			//   v > 1
+			// end                   # => 1
+			// (1..4).each.count(2)  # => 1
+			// ```
+			//
+			// @param object [Object]
+			// @return [Integer]
+			Name: "count",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+					}
+
+					count := 0
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						var test bool
+						var err *Error
+
+						switch {
+						case len(args) == 1:
+							test, err = t.objectsEqual(t.vm.enumerableElement(values), args[0])
+						case blockFrame != nil:
+							test, err = t.testElement(blockFrame, values)
+						default:
+							test = true
+						}
+
+						if err != nil {
+							return false, err
+						}
+
+						if test {
+							count++
+						}
+
+						return true, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initIntegerObject(count)
+				}
+			},
+		},
+		{
+			// Yields every element and its index to the block, and returns the receiver.
+			//
+			// ```ruby
+			// sum = 0
+			// (5..7).each_with_index do |n, i|
+			//   sum += n * i
+			// end
+			// sum # => 19
+			// ```
+			//
+			// @return [Object]
+			Name: "each_with_index",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_with_index", args)
+					}
+
+					index := 0
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						_, err := t.yieldElement(blockFrame, t.vm.enumerableElement(values), t.vm.initIntegerObject(index))
+						index++
+						return err == nil, err
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns the first element for which the block doesn't return false or nil.
+			// Returns nil if there's no such element.
+			//
+			// ```ruby
+			// [1, 2, 3].find do |n|
+			//   n > 1
+			// end # => 2
+			// ```
+			//
+			// @return [Object]
+			Name: "find",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "find", args)
+					}
+
+					var result Object = NULL
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						test, err := t.testElement(blockFrame, values)
+
+						if err != nil {
+							return false, err
+						}
+
+						if test {
+							result = t.vm.enumerableElement(values)
+						}
+
+						return !test, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return result
+				}
+			},
+		},
+		{
+			// Returns the first element, or an array of the first n elements if n is given.
+			// It stops iterating once it gets enough elements, so it works with endless collections.
+			//
+			// ```ruby
+			// (1..5).each_with_index.first    # => [1, 0]
+			// { a: 1, b: 2 }.first(1)          # => [["a", 1]]
+			// (1..Float::INFINITY).lazy.first(2) # => [1, 2]
+			// ```
+			//
+			// @param n [Integer]
+			// @return [Object]
+			Name: "first",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+					}
+
+					limit := 1
+
+					if len(args) == 1 {
+						n, ok := args[0].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						if n.value < 0 {
+							return t.initErrorObject(errors.ArgumentError, "Expect argument to be positive value. got: %d", n.value)
+						}
+
+						limit = n.value
+					}
+
+					elements := []Object{}
+
+					if limit > 0 {
+						err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+							elements = append(elements, t.vm.enumerableElement(values))
+							return len(elements) < limit, nil
+						})
+
+						if err != nil {
+							return err
+						}
+					}
+
+					if len(args) == 1 {
+						return t.vm.initArrayObject(elements)
+					}
+
+					if len(elements) == 0 {
+						return NULL
+					}
+
+					return elements[0]
+				}
+			},
+		},
+		{
+			// Returns true if any element is equal to the given object, elements are compared with `==`.
+			// It stops iterating once it finds the element.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.include?(3) # => true
+			// { a: 1 }.include?(["a", 1])           # => true
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "include?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					result := FALSE
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						eq, err := t.objectsEqual(t.vm.enumerableElement(values), args[0])
+
+						if err != nil {
+							return false, err
+						}
+
+						if eq {
+							result = TRUE
+						}
+
+						return !eq, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return result
+				}
+			},
+		},
+		{
+			// Returns a lazy enumerator of the elements. Methods like `map` and `select` of the lazy enumerator
+			// don't iterate the elements, but return another lazy enumerator. The elements are only evaluated
+			// when methods like `first` or `to_a` need them, so it works with endless collections.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.map do |n|
+			//   n * 2
+			// end.select do |n|
+			//   n % 3 == 0
+			// end.first(2) # => [6, 12]
+			// ```
+			//
+			// @return [Lazy]
+			Name: "lazy",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					return t.vm.initLazyObject(receiver, nil)
+				}
+			},
+		},
+		{
+			// Returns a new array with the results of running the block once for every element.
+			//
+			// ```ruby
+			// [1, 2, 7].map do |i|
+			//   i + 3
+			// end # => [4, 5, 10]
+			// ```
+			//
+			// @return [Array]
+			Name: "map",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "map", args)
+					}
+
+					elements := []Object{}
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						result, err := t.yieldElement(blockFrame, values...)
+
+						if err != nil {
+							return false, err
+						}
+
+						elements = append(elements, result)
+						return true, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initArrayObject(elements)
+				}
+			},
+		},
+		{
+			// Returns the largest element, or nil if there's no element. Elements are compared with `<=>`, or with
+			// the block, which should return a negative Integer, 0 or a positive Integer like `<=>` does.
+			//
+			// ```ruby
+			// (1..3).max             # => 3
+			// ["bb", "a", "ccc"].each.max do |a, b|
+			//   a.length <=> b.length
+			// end                    # => "ccc"
+			// ```
+			//
+			// @return [Object]
+			Name: "max",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.extremeElement(receiver, args, blockFrame, 1)
+				}
+			},
+		},
+		{
+			// Returns the smallest element, or nil if there's no element. Elements are compared with `<=>`, or with
+			// the block, which should return a negative Integer, 0 or a positive Integer like `<=>` does.
+			//
+			// ```ruby
+			// (1..3).min             # => 1
+			// { a: 2, b: 1 }.min do |a, b|
+			//   a[1] <=> b[1]
+			// end                    # => ["b", 1]
+			// ```
+			//
+			// @return [Object]
+			Name: "min",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.extremeElement(receiver, args, blockFrame, -1)
+				}
+			},
+		},
+		{
+			// Loop through each elements and accumulate each results of given block in the first argument of the block
+			// If you do not give an argument, the first element of collection is used as an initial value
+			//
+			// ```ruby
+			// a = [1, 2, 7]
+			//
+			// a.reduce do |sum, n|
+			//   sum + n
+			// end
+			// # => 10
+			//
+			// a.reduce(10) do |sum, n|
+			//   sum + n
+			// end
+			// # => 20
+			// ```
+			//
+			// @return [Object]
+			Name: "reduce",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					var prev Object
+
+					switch len(args) {
+					case 0:
+					case 1:
+						prev = args[0]
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 or 1 argument. got=%d", len(args))
+					}
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						element := t.vm.enumerableElement(values)
+
+						if prev == nil {
+							prev = element
+							return true, nil
+						}
+
+						result, err := t.yieldElement(blockFrame, prev, element)
+
+						if err != nil {
+							return false, err
+						}
+
+						prev = result
+						return true, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					if prev == nil {
+						return NULL
+					}
+
+					return prev
+				}
+			},
+		},
+		{
+			// Returns a new array with the elements for which the block returns false or nil.
+			//
+			// ```ruby
+			// [1, 2, 3, 4].reject do |n|
+			//   n % 2 == 0
+			// end # => [1, 3]
+			// ```
+			//
+			// @return [Array]
+			Name: "reject",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "reject", args)
+					}
+
+					return t.filterElements(receiver, blockFrame, false)
+				}
+			},
+		},
+		{
+			// Loop through each element with the given block.
+			// Return a new array with each element that returns true from yield.
+			//
+			// ```ruby
+			// a = [1, 2, 3, 4, 5]
+			//
+			// a.select do |e|
+			//   e + 1 > 3
+			// end
+			// # => [3, 4, 5]
+			// ```
+			//
+			// @return [Array]
+			Name: "select",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "select", args)
+					}
+
+					return t.filterElements(receiver, blockFrame, true)
+				}
+			},
+		},
+		{
+			// Returns a new array of the elements sorted by the results of the block, which are compared with `<=>`.
+			// Elements with the same result keep their order.
+			//
+			// ```ruby
+			// ["ccc", "a", "bb"].sort_by do |s|
+			//   s.length
+			// end # => ["a", "bb", "ccc"]
+			// ```
+			//
+			// @return [Array]
+			Name: "sort_by",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "sort_by", args)
+					}
+
+					elements := []Object{}
+					keys := []Object{}
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						key, err := t.yieldElement(blockFrame, values...)
+
+						if err != nil {
+							return false, err
+						}
+
+						elements = append(elements, t.vm.enumerableElement(values))
+						keys = append(keys, key)
+						return true, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					indexes := make([]int, len(elements))
+
+					for i := range indexes {
+						indexes[i] = i
+					}
+
+					sort.SliceStable(indexes, func(i, j int) bool {
+						if err != nil {
+							return false
+						}
+
+						var c int
+						c, err = compare(t, keys[indexes[i]], keys[indexes[j]])
+						return c < 0
+					})
+
+					if err != nil {
+						return err
+					}
+
+					sorted := make([]Object, len(indexes))
+
+					for i, index := range indexes {
+						sorted[i] = elements[index]
+					}
+
+					return t.vm.initArrayObject(sorted)
+				}
+			},
+		},
+		{
+			// Returns an array of the elements.
+			//
+			// ```ruby
+			// { a: 1 }.each_with_index.to_a # => [[["a", 1], 0]]
+			// (1..3).lazy.map do |n|
+			//   n * 2
+			// end.to_a                      # => [2, 4, 6]
+			// ```
+			//
+			// @return [Array]
+			Name: "to_a",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					elements := []Object{}
+
+					err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+						elements = append(elements, t.vm.enumerableElement(values))
+						return true, nil
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initArrayObject(elements)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initEnumerableModule() *RClass {
+	m := vm.initializeClass(classes.EnumerableModule, true)
+	m.setBuiltinMethods(builtinEnumerableInstanceMethods(), false)
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// iterate calls the receiver's `each` with a block implemented in Go, which passes the yielded values to fn.
+// The iteration stops when fn returns false or an error. The given block is popped if `each` never yields it.
+func (t *thread) iterate(receiver Object, blockFrame *callFrame, fn func(values []Object) (bool, *Error)) *Error {
+	_, err := t.sendIterator(receiver, "each", nil, blockFrame, func(values []Object) (Object, bool, *Error) {
+		next, err := fn(values)
+		return NULL, next, err
+	})
+
+	return err
+}
+
+// sendIterator calls the receiver's iterator method with a block implemented in Go like iterate, but fn also returns
+// the block's result to the method. It returns the method's result, which is nil if fn stops the iteration.
+func (t *thread) sendIterator(receiver Object, methodName string, args []Object, blockFrame *callFrame, fn func(values []Object) (Object, bool, *Error)) (result Object, err *Error) {
+	cfp := t.cfp
+	sp := t.sp
+	goBlock := &callFrame{}
+	result = NULL
+
+	goBlock.goBlock = func(t *thread, args []Object) Object {
+		value, next, e := fn(args)
+
+		if e != nil {
+			err = e
+		}
+
+		if !next || e != nil {
+			t.breakBlock(goBlock, NULL)
+		}
+
+		return value
+	}
+
+	defer func() {
+		if blockFrame != nil && t.callFrameStack.top() == blockFrame {
+			t.callFrameStack.pop()
+		}
+	}()
+
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		b, ok := r.(*blockBreak)
+
+		if !ok || b.blockFrame != goBlock {
+			panic(r)
+		}
+
+		for t.cfp > cfp {
+			t.callFrameStack.pop()
+		}
+
+		t.sp = sp
+	}()
+
+	result = t.sendMethodWithBlock(methodName, receiver, goBlock, args...)
+
+	if e, ok := result.(*Error); ok {
+		return nil, e
+	}
+
+	return
+}
+
+// yieldElement yields the values to the block, and returns the block's result or the error it returns
+func (t *thread) yieldElement(blockFrame *callFrame, values ...Object) (Object, *Error) {
+	result := t.builtinMethodYield(blockFrame, values...).Target
+
+	if err, ok := result.(*Error); ok && !err.rescued {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// testElement returns whether the block's result for the values is truthy, or whether the element itself is
+// truthy if there's no block
+func (t *thread) testElement(blockFrame *callFrame, values []Object) (bool, *Error) {
+	if blockFrame == nil {
+		return isTruthy(t.vm.enumerableElement(values)), nil
+	}
+
+	result, err := t.yieldElement(blockFrame, values...)
+
+	if err != nil {
+		return false, err
+	}
+
+	return isTruthy(result), nil
+}
+
+// filterElements returns an array of the elements whose test results match the expected value
+func (t *thread) filterElements(receiver Object, blockFrame *callFrame, expected bool) Object {
+	elements := []Object{}
+
+	err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+		test, err := t.testElement(blockFrame, values)
+
+		if err != nil {
+			return false, err
+		}
+
+		if test == expected {
+			elements = append(elements, t.vm.enumerableElement(values))
+		}
+
+		return true, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	return t.vm.initArrayObject(elements)
+}
+
+// extremeElement returns the element that compares to the others with the given sign, which is 1 for the largest
+// element and -1 for the smallest one. Elements are compared by the block if it's given, or by `<=>`.
+func (t *thread) extremeElement(receiver Object, args []Object, blockFrame *callFrame, sign int) Object {
+	if len(args) != 0 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+	}
+
+	var result Object
+
+	err := t.iterate(receiver, blockFrame, func(values []Object) (bool, *Error) {
+		element := t.vm.enumerableElement(values)
+
+		if result == nil {
+			result = element
+			return true, nil
+		}
+
+		c, err := t.compareElements(blockFrame, element, result)
+
+		if err != nil {
+			return false, err
+		}
+
+		if c*sign > 0 {
+			result = element
+		}
+
+		return true, nil
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if result == nil {
+		return NULL
+	}
+
+	return result
+}
+
+// compareElements compares the elements with the block's result if it's given, or with `<=>`
+func (t *thread) compareElements(blockFrame *callFrame, left, right Object) (int, *Error) {
+	if blockFrame == nil {
+		return compare(t, left, right)
+	}
+
+	result, err := t.yieldElement(blockFrame, left, right)
+
+	if err != nil {
+		return 0, err
+	}
+
+	c, ok := result.(*IntegerObject)
+
+	if !ok {
+		return 0, t.initErrorObject(errors.ArgumentError, "Comparison of %s with %s failed", left.Class().Name, right.Class().Name)
+	}
+
+	return c.value, nil
+}
+
+// enumerableElement returns the element represented by the values yielded by `each`, which is an array
+// if there are more than one value
+func (vm *VM) enumerableElement(values []Object) Object {
+	switch len(values) {
+	case 0:
+		return NULL
+	case 1:
+		return values[0]
+	default:
+		return vm.initArrayObject(values)
+	}
+}
+
+// isTruthy returns false for false and nil, and true for any other object
+func isTruthy(obj Object) bool {
+	switch o := obj.(type) {
+	case *BooleanObject:
+		return o.value
+	case *NullObject:
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,220 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestEnumerableModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Enumerable.name`, "Enumerable"},
+		{`[1].is_a?(Enumerable)`, true},
+		{`{ a: 1 }.is_a?(Enumerable)`, true},
+		{`(1..2).is_a?(Enumerable)`, true},
+		{`"a".is_a?(Enumerable)`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnumerableMethods(t *testing.T) {
+	listClass := `
+	class NumberList
+	  include(Enumerable)
+
+	  def initialize(*numbers)
+	    @numbers = numbers
+	  end
+
+	  def each
+	    @numbers.each do |n|
+	      yield(n)
+	    end
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`NumberList.new(3, 1, 2).map do |n|
+		  n * 2
+		end.to_s`, "[6, 2, 4]"},
+		{`NumberList.new(3, 1, 2).select do |n|
+		  n > 1
+		end.to_s`, "[3, 2]"},
+		{`NumberList.new(3, 1, 2).reject do |n|
+		  n > 1
+		end.to_s`, "[1]"},
+		{`NumberList.new(3, 1, 2).find do |n|
+		  n < 3
+		end`, 1},
+		{`NumberList.new(3, 1, 2).find do |n|
+		  n > 3
+		end`, nil},
+		{`NumberList.new(3, 1, 2).reduce do |sum, n|
+		  sum + n
+		end`, 6},
+		{`NumberList.new(3, 1, 2).reduce(10) do |sum, n|
+		  sum + n
+		end`, 16},
+		{`NumberList.new(3, 1, 2).any? do |n|
+		  n > 2
+		end`, true},
+		{`NumberList.new(3, 1, 2).all? do |n|
+		  n > 2
+		end`, false},
+		{`NumberList.new(3, 1, 2).sort_by do |n|
+		  n
+		end.to_s`, "[1, 2, 3]"},
+		{`NumberList.new(3, 1, 2).include?(2)`, true},
+		{`NumberList.new(3, 1, 2).include?(4)`, false},
+		{`NumberList.new(3, 1, 2).min`, 1},
+		{`NumberList.new(3, 1, 2).max`, 3},
+		{`NumberList.new(3, 1, 2).max do |a, b|
+		  b <=> a
+		end`, 1},
+		{`NumberList.new(3, 1, 2, 3).count`, 4},
+		{`NumberList.new(3, 1, 2, 3).count(3)`, 2},
+		{`NumberList.new(3, 1, 2, 3).count do |n|
+		  n > 1
+		end`, 3},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, listClass+tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnumerableMethodsOnBuiltinClasses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2, 3, 4].reject do |n|
+		  n % 2 == 0
+		end.to_s`, "[1, 3]"},
+		{`[1, 2, 3].find do |n|
+		  n > 1
+		end`, 2},
+		{`[1, nil].all?`, false},
+		{`[].reduce do |sum, n|
+		  sum + n
+		end`, nil},
+		{`[].map do |n|
+		  n
+		end.to_s`, "[]"},
+		{`[nil, false].any?`, false},
+		{`[].all?`, true},
+		{`["ccc", "a", "bb"].sort_by do |s|
+		  s.length
+		end.to_s`, `["a", "bb", "ccc"]`},
+		{`[[2, "b"], [1, "a"], [2, "a"]].sort_by do |pair|
+		  pair[0]
+		end.to_s`, `[[1, "a"], [2, "b"], [2, "a"]]`},
+		{`{ a: 1, b: 2 }.select do |k, v|
+		  v > 1
+		end.to_s`, `{ b: 2 }`},
+		{`{ a: 1, b: 2 }.reject do |k, v|
+		  v > 1
+		end.to_s`, `{ a: 1 }`},
+		{`{ a: 1, b: 2 }.include?(["b", 2])`, true},
+		{`{ a: 1, b: 2 }.count do |k, v|
+		  v > 1
+		end`, 1},
+		{`{ a: 2, b: 1 }.min do |x, y|
+		  x[1] <=> y[1]
+		end.to_s`, `["b", 1]`},
+		{`(1..Float::INFINITY).lazy.include?(3)`, true},
+		{`(1..4).count(2)`, 1},
+		{`["bb", "a", "ccc"].each.max do |a, b|
+		  a.length <=> b.length
+		end`, "ccc"},
+		{`(1..3).each.min`, 1},
+		{`[].each.max`, nil},
+		{`{ a: 1, b: 2 }.map do |k, v|
+		  k + v.to_s
+		end.to_s`, `["a1", "b2"]`},
+		{`{ a: 1, b: 2 }.find do |k, v|
+		  v == 1
+		end.to_s`, `["a", 1]`},
+		{`{ a: 1, b: 2 }.reduce(0) do |sum, pair|
+		  sum + pair[1]
+		end`, 3},
+		{`(1..6).select do |n|
+		  n % 3 == 0
+		end.to_s`, "[3, 6]"},
+		{`(1..4).reduce do |product, n|
+		  product * n
+		end`, 24},
+		{`(1..3).any? do |n|
+		  n > 2
+		end`, true},
+		{`
+		[1, 2, 3].map do |n|
+		  break 10 if n == 2
+		  n
+		end
+		`, 10},
+		{`
+		result = []
+		[1, 2, 3, 4].find do |n|
+		  result.push(n)
+		  n == 2
+		end
+		result.to_s
+		`, "[1, 2]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnumerableMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`(1..2).any?(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`(1..2).each.include?`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`(1..2).count(1, 2)`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`(1..2).max(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`[1, "a"].each.min`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`(1..2).min do |a, b|
+		  "a"
+		end`, "ArgumentError: Comparison of Integer with Integer failed", 1},
+		{`[1, "a"].sort_by do |x|
+		  x
+		end`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`class Foo
+		  include(Enumerable)
+		end
+
+		Foo.new.map do |x|
+		  x
+		end`, "UndefinedMethodError: Undefined Method 'each' for <Instance of: Foo>", 5},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
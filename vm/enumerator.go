@@ -0,0 +1,631 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// EnumeratorObject represents an iterator method call without a block, like `[1, 2].each` or `{ a: 1 }.map`.
+// It includes Enumerable, and its `each` calls the method with the given block, so the iteration can be
+// chained with other methods:
+//
+// ```ruby
+// [1, 2, 3].map.with_index do |n, i|
+//   n * i
+// end # => [0, 2, 6]
+//
+// ["a", "b"].each_with_index.to_a # => [["a", 0], ["b", 1]]
+// 3.times.map do |i|
+//   i * 2
+// end # => [0, 2, 4]
+// ```
+//
+// Enumerators also support external iteration with `next`, `peek` and `rewind`. `next` raises StopIteration at
+// the end:
+//
+// ```ruby
+// e = [1, 2].each
+// e.next # => 1
+// e.peek # => 2
+// e.next # => 2
+// e.next # => StopIteration
+// ```
+type EnumeratorObject struct {
+	*baseObj
+	receiver Object
+	method   string
+	args     []Object
+	cursor   *enumeratorCursor
+}
+
+// enumeratorCursor is the state of an enumerator's external iteration. The method runs on another thread and
+// sends the yielded values one by one, it waits for the next `resume` before continuing the iteration.
+type enumeratorCursor struct {
+	values    chan Object
+	resume    chan bool
+	err       *Error
+	finished  bool
+	peeked    Object
+	hasPeeked bool
+}
+
+// LazyObject is a lazy enumerator returned by `Enumerable#lazy`. Its `map`, `select`, `reject`, `take_while` and
+// `take` return another lazy enumerator instead of iterating the elements. The elements are evaluated one by one
+// when they're needed by methods like `first`, `to_a` or `each`, so it works with endless collections:
+//
+// ```ruby
+// (1..Float::INFINITY).lazy.map do |n|
+//   n * n
+// end.select do |n|
+//   n.even?
+// end.first(3) # => [4, 16, 36]
+// ```
+type LazyObject struct {
+	*baseObj
+	source     Object
+	operations []*lazyOperation
+}
+
+// lazyOperation is a step of a lazy enumerator, which is applied to every element when the enumerator is iterated
+type lazyOperation struct {
+	name       string
+	blockFrame *callFrame
+	limit      int
+}
+
+// Instance methods -----------------------------------------------------
+func builtinEnumeratorInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Calls the enumerator's method with the given block and returns the method's result.
+			// Returns the enumerator itself if there's no block.
+			//
+			// ```ruby
+			// e = [1, 2].each
+			// e.each do |n|
+			//   puts(n)
+			// end # => [1, 2]
+			// ```
+			//
+			// @return [Object]
+			Name: "each",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					if blockFrame == nil {
+						return receiver
+					}
+
+					e := receiver.(*EnumeratorObject)
+
+					return t.sendMethodWithBlock(e.method, e.receiver, blockFrame, e.args...)
+				}
+			},
+		},
+		{
+			// Calls the enumerator's method with a block that yields the values and their index to the given block,
+			// and returns the method's result. The index starts from the given offset, which is 0 by default.
+			//
+			// ```ruby
+			// ["a", "b"].map.with_index(1) do |s, i|
+			//   s * i
+			// end # => ["a", "bb"]
+			// ```
+			//
+			// @param offset [Integer]
+			// @return [Object]
+			Name: "with_index",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+					}
+
+					index := 0
+
+					if len(args) == 1 {
+						offset, ok := args[0].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						index = offset.value
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "with_index", args)
+					}
+
+					e := receiver.(*EnumeratorObject)
+
+					result, err := t.sendIterator(e.receiver, e.method, e.args, blockFrame, func(values []Object) (Object, bool, *Error) {
+						value, err := t.yieldElement(blockFrame, t.vm.enumerableElement(values), t.vm.initIntegerObject(index))
+						index++
+						return value, err == nil, err
+					})
+
+					if err != nil {
+						return err
+					}
+
+					return result
+				}
+			},
+		},
+		{
+			// Returns the next element of the enumerator and moves the position forward.
+			// Raises StopIteration if the enumerator has reached its end.
+			//
+			// ```ruby
+			// e = 3.times
+			// e.next # => 0
+			// e.next # => 1
+			// e.next # => 2
+			// e.next # => StopIteration
+			// ```
+			//
+			// @return [Object]
+			Name: "next",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					e := receiver.(*EnumeratorObject)
+					value, err := e.fetch(t)
+
+					if err != nil {
+						return err
+					}
+
+					e.cursor.hasPeeked = false
+					e.cursor.peeked = nil
+
+					return value
+				}
+			},
+		},
+		{
+			// Returns the next element of the enumerator without moving the position forward.
+			// Raises StopIteration if the enumerator has reached its end.
+			//
+			// ```ruby
+			// e = [1, 2].each
+			// e.peek # => 1
+			// e.next # => 1
+			// e.peek # => 2
+			// ```
+			//
+			// @return [Object]
+			Name: "peek",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					value, err := receiver.(*EnumeratorObject).fetch(t)
+
+					if err != nil {
+						return err
+					}
+
+					return value
+				}
+			},
+		},
+		{
+			// Moves the position of the external iteration back to the beginning and returns the enumerator.
+			//
+			// ```ruby
+			// e = [1, 2].each
+			// e.next # => 1
+			// e.rewind
+			// e.next # => 1
+			// ```
+			//
+			// @return [Enumerator]
+			Name: "rewind",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					receiver.(*EnumeratorObject).rewind()
+
+					return receiver
+				}
+			},
+		},
+	}
+}
+
+func builtinLazyInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Evaluates the elements one by one and yields them to the block, and returns the receiver.
+			// Returns the receiver itself if there's no block.
+			//
+			// ```ruby
+			// (1..3).lazy.map do |n|
+			//   n * 2
+			// end.each do |n|
+			//   puts(n)
+			// end
+			// ```
+			//
+			// @return [Lazy]
+			Name: "each",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					if blockFrame == nil {
+						return receiver
+					}
+
+					if err := receiver.(*LazyObject).each(t, blockFrame); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns an array of the evaluated elements, it's an alias of `to_a`.
+			//
+			// ```ruby
+			// (1..3).lazy.map do |n|
+			//   n * 2
+			// end.force # => [2, 4, 6]
+			// ```
+			//
+			// @return [Array]
+			Name: "force",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("to_a", receiver, args...)
+				}
+			},
+		},
+		{
+			// Returns the receiver itself.
+			//
+			// @return [Lazy]
+			Name: "lazy",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns a lazy enumerator that evaluates the block's result for every element.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.map do |n|
+			//   n * 2
+			// end.first(3) # => [2, 4, 6]
+			// ```
+			//
+			// @return [Lazy]
+			Name: "map",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.addLazyOperation(receiver, "map", args, blockFrame)
+				}
+			},
+		},
+		{
+			// Returns a lazy enumerator that skips the elements for which the block returns true.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.reject do |n|
+			//   n.even?
+			// end.first(3) # => [1, 3, 5]
+			// ```
+			//
+			// @return [Lazy]
+			Name: "reject",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.addLazyOperation(receiver, "reject", args, blockFrame)
+				}
+			},
+		},
+		{
+			// Returns a lazy enumerator of the elements for which the block returns true.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.select do |n|
+			//   n.even?
+			// end.first(3) # => [2, 4, 6]
+			// ```
+			//
+			// @return [Lazy]
+			Name: "select",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.addLazyOperation(receiver, "select", args, blockFrame)
+				}
+			},
+		},
+		{
+			// Returns a lazy enumerator of the first n elements, it stops the iteration after getting them.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.map do |n|
+			//   n * 2
+			// end.take(3).to_a # => [2, 4, 6]
+			// ```
+			//
+			// @param n [Integer]
+			// @return [Lazy]
+			Name: "take",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					n, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if n.value < 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect argument to be positive value. got: %d", n.value)
+					}
+
+					l := receiver.(*LazyObject)
+
+					operations := append(append([]*lazyOperation{}, l.operations...), &lazyOperation{name: "take", limit: n.value})
+
+					return t.vm.initLazyObject(l.source, operations)
+				}
+			},
+		},
+		{
+			// Returns a lazy enumerator of the elements before the first one for which the block returns false or nil.
+			//
+			// ```ruby
+			// (1..Float::INFINITY).lazy.take_while do |n|
+			//   n < 4
+			// end.to_a # => [1, 2, 3]
+			// ```
+			//
+			// @return [Lazy]
+			Name: "take_while",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.addLazyOperation(receiver, "take_while", args, blockFrame)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initEnumeratorObject(receiver Object, method string, args []Object) *EnumeratorObject {
+	return &EnumeratorObject{
+		baseObj:  &baseObj{class: vm.topLevelClass(classes.EnumeratorClass)},
+		receiver: receiver,
+		method:   method,
+		args:     args,
+	}
+}
+
+func (vm *VM) initLazyObject(source Object, operations []*lazyOperation) *LazyObject {
+	lc := vm.topLevelClass(classes.EnumeratorClass).getClassConstant(classes.LazyClass)
+
+	return &LazyObject{
+		baseObj:    &baseObj{class: lc},
+		source:     source,
+		operations: operations,
+	}
+}
+
+func (vm *VM) initEnumeratorClass() *RClass {
+	ec := vm.initializeClass(classes.EnumeratorClass, false)
+	ec.setBuiltinMethods(builtinEnumeratorInstanceMethods(), false)
+	ec.include(vm.topLevelClass(classes.EnumerableModule))
+
+	lc := vm.initializeClass(classes.LazyClass, false)
+	lc.setBuiltinMethods(builtinLazyInstanceMethods(), false)
+	lc.include(vm.topLevelClass(classes.EnumerableModule))
+	ec.setClassConstant(lc)
+
+	return ec
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (e *EnumeratorObject) Value() interface{} {
+	return e.receiver
+}
+
+// Returns the enumerator's string representation
+func (e *EnumeratorObject) toString() string {
+	return fmt.Sprintf("<Enumerator: %s:%s>", e.receiver.toString(), e.method)
+}
+
+// Alias of toString
+func (e *EnumeratorObject) toJSON() string {
+	return e.toString()
+}
+
+// Returns the object
+func (l *LazyObject) Value() interface{} {
+	return l.source
+}
+
+// Returns the lazy enumerator's string representation
+func (l *LazyObject) toString() string {
+	return fmt.Sprintf("<Enumerator::Lazy: %s>", l.source.toString())
+}
+
+// Alias of toString
+func (l *LazyObject) toJSON() string {
+	return l.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// fetch returns the element at the enumerator's position and keeps it until `next` consumes it. It starts the
+// iteration on another thread when it's called for the first time.
+func (e *EnumeratorObject) fetch(t *thread) (Object, *Error) {
+	c := e.cursor
+
+	if c == nil {
+		c = &enumeratorCursor{values: make(chan Object), resume: make(chan bool)}
+		e.cursor = c
+		e.start(t)
+	} else if c.hasPeeked {
+		return c.peeked, nil
+	} else if !c.finished {
+		c.resume <- true
+	}
+
+	if !c.finished {
+		value, ok := <-c.values
+
+		if ok {
+			c.peeked = value
+			c.hasPeeked = true
+			return value, nil
+		}
+
+		c.finished = true
+	}
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return nil, t.initErrorObject(errors.StopIteration, "iteration reached an end")
+}
+
+// start calls the enumerator's method on a new thread, which sends every yielded value to the cursor
+func (e *EnumeratorObject) start(t *thread) {
+	c := e.cursor
+	newT := t.vm.newThread()
+	newT.timeout = t.timeout
+
+	go func() {
+		defer close(c.values)
+
+		defer func() {
+			if r := recover(); r != nil {
+				if _, ok := r.(*blockBreak); !ok {
+					panic(r)
+				}
+
+				c.err = newT.initErrorObject(errors.LocalJumpError, "Can't break out of a block running on another thread")
+			}
+		}()
+
+		_, err := newT.sendIterator(e.receiver, e.method, e.args, nil, func(values []Object) (Object, bool, *Error) {
+			c.values <- t.vm.enumerableElement(values)
+			return NULL, <-c.resume, nil
+		})
+
+		c.err = err
+	}()
+}
+
+// rewind stops the running iteration and resets the position to the beginning
+func (e *EnumeratorObject) rewind() {
+	c := e.cursor
+	e.cursor = nil
+
+	if c == nil || c.finished {
+		return
+	}
+
+	c.resume <- false
+
+	for range c.values {
+	}
+}
+
+// addLazyOperation returns a new lazy enumerator with the operation that calls the block. The block is kept
+// for later iterations, so its frame is popped like the one of a Proc.
+func (t *thread) addLazyOperation(receiver Object, name string, args []Object, blockFrame *callFrame) Object {
+	if len(args) != 0 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+	}
+
+	if blockFrame == nil {
+		return t.initErrorObject(errors.ArgumentError, "Tried to call lazy %s without a block", name)
+	}
+
+	t.callFrameStack.pop()
+
+	l := receiver.(*LazyObject)
+	operations := append(append([]*lazyOperation{}, l.operations...), &lazyOperation{name: name, blockFrame: blockFrame})
+
+	return t.vm.initLazyObject(l.source, operations)
+}
+
+// each iterates the source, applies the operations to every element and yields the results to the block
+func (l *LazyObject) each(t *thread, blockFrame *callFrame) *Error {
+	taken := make([]int, len(l.operations))
+
+	return t.iterate(l.source, blockFrame, func(values []Object) (bool, *Error) {
+		last := false
+
+		for i, op := range l.operations {
+			switch op.name {
+			case "map":
+				value, err := t.yieldElement(op.blockFrame, values...)
+
+				if err != nil {
+					return false, err
+				}
+
+				values = []Object{value}
+			case "select", "reject", "take_while":
+				test, err := t.testElement(op.blockFrame, values)
+
+				if err != nil {
+					return false, err
+				}
+
+				if op.name == "take_while" && !test {
+					return false, nil
+				}
+
+				if op.name != "take_while" && test != (op.name == "select") {
+					return true, nil
+				}
+			case "take":
+				if taken[i] >= op.limit {
+					return false, nil
+				}
+
+				taken[i]++
+				last = last || taken[i] == op.limit
+			}
+		}
+
+		_, err := t.yieldElement(blockFrame, values...)
+
+		return !last && err == nil, err
+	})
+}
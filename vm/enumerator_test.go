@@ -0,0 +1,184 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestEnumerator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`[1, 2].each.class.name`, "Enumerator"},
+		{`[1, 2].each.to_s`, "<Enumerator: [1, 2]:each>"},
+		{`[1, 2, 3].each.to_a.to_s`, "[1, 2, 3]"},
+		{`
+		[1, 2, 3].map.with_index do |x, i|
+		  x * i
+		end.to_s
+		`, "[0, 2, 6]"},
+		{`
+		["a", "b"].map.with_index(1) do |x, i|
+		  x + i.to_s
+		end.to_s
+		`, `["a1", "b2"]`},
+		{`
+		sum = 0
+		[10, 20].each_with_index do |x, i|
+		  sum = sum + x * i
+		end
+		sum
+		`, 20},
+		{`["a", "b"].each_with_index.to_a.to_s`, `[["a", 0], ["b", 1]]`},
+		{`
+		[1, 2, 3, 4].select.with_index do |x, i|
+		  i.even?
+		end.to_s
+		`, "[1, 3]"},
+		{`
+		h = { a: 1, b: 2 }
+		h.each.map do |k, v|
+		  k + v.to_s
+		end.to_s
+		`, `["a1", "b2"]`},
+		{`"abc".each_char.to_a.to_s`, `["a", "b", "c"]`},
+		{`(1..3).each.to_a.to_s`, "[1, 2, 3]"},
+		{`[1, 2, 3].first(2).to_s`, "[1, 2]"},
+		{`[].first`, nil},
+		{`
+		e = 3.times
+		[e.next, e.next, e.peek, e.next].to_s
+		`, "[0, 1, 2, 2]"},
+		{`
+		e = { a: 1, b: 2 }.each
+		e.next.to_s
+		`, `["a", 1]`},
+		{`
+		e = [1, 2].each
+		e.next
+		e.next
+		e.rewind
+		e.next
+		`, 1},
+		{`
+		e = [1, 2, 3].map
+		e.next
+		e.rewind.peek
+		`, 1},
+		{`
+		e = [1, 2].each
+		result = []
+		begin
+		  while true do
+		    result.push(e.next)
+		  end
+		rescue StopIteration
+		end
+		result.to_s
+		`, "[1, 2]"},
+		{`
+		e = [1].each
+		e.next
+		begin
+		  e.next
+		rescue StopIteration => err
+		  err.message
+		end
+		`, "iteration reached an end"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnumeratorFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1, 2].each.with_index("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`[1, 2].each_with_index(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`[1, 2].first("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`[].each.next`, "StopIteration: iteration reached an end", 1},
+		{`
+		e = [1].each
+		e.next
+		e.peek`, "StopIteration: iteration reached an end", 4},
+		{`[1].each.next(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestLazyEnumerator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1..Float::INFINITY).lazy.class.name`, "Lazy"},
+		{`
+		(1..Float::INFINITY).lazy.map do |x|
+		  x * 2
+		end.select do |x|
+		  x % 4 == 0
+		end.map do |x|
+		  x * x
+		end.first(3).to_s
+		`, "[16, 64, 144]"},
+		{`
+		(1..Float::INFINITY).lazy.take_while do |x|
+		  x < 4
+		end.to_a.to_s
+		`, "[1, 2, 3]"},
+		{`
+		[1, 2, 3, 4, 5].lazy.reject do |x|
+		  x.odd?
+		end.force.to_s
+		`, "[2, 4]"},
+		{`(1..Float::INFINITY).lazy.take(3).force.to_s`, "[1, 2, 3]"},
+		{`
+		count = 0
+		(1..Float::INFINITY).lazy.map do |x|
+		  count = count + 1
+		  x
+		end.first(2)
+		count
+		`, 2},
+		{`(1..Float::INFINITY).lazy.first`, 1},
+		{`(1..Float::INFINITY).size.to_s`, "Infinity"},
+		{`(1..Float::INFINITY).to_s`, "(1..Infinity)"},
+		{`(1..Float::INFINITY).each_with_index.first(2).to_s`, "[[1, 0], [2, 1]]"},
+		{`(1..Float::INFINITY).include?(100000)`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestLazyEnumeratorFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`[1].lazy.map`, "ArgumentError: Tried to call lazy map without a block", 1},
+		{`[1].lazy.take("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`(1..Float::INFINITY).to_a`, "ArgumentError: Can't convert endless Range into Array", 1},
+		{`(1..Float::INFINITY).last`, "ArgumentError: Can't get the last value of endless Range", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
@@ -22,3 +22,12 @@ func (e *environment) set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
+
+// copy returns a new environment with the same values and outer environment
+func (e *environment) copy() *environment {
+	s := make(map[string]Object, len(e.store))
+	for k, v := range e.store {
+		s[k] = v
+	}
+	return &environment{store: s, outer: e.outer}
+}
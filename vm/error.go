@@ -2,16 +2,32 @@ package vm
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
 )
 
-// Error class is actually a special struct to hold internal error types with messages.
-// Goby developers need not to take care of the struct.
-// Goby maintainers should consider using the appropriate error type.
-// Cannot create instances of Error class, or inherit Error class.
+// Error is the object of Goby's errors, which are raised by `raise` or by builtin methods, and can be rescued
+// by `begin ... rescue ... end`.
 //
-// The type of internal errors:
+// All error classes inherit from `Exception`, and the builtin ones inherit from `StandardError`, which is what
+// `rescue` without error classes rescues. Goby programs can define their own error classes by inheriting
+// them:
+//
+// ```ruby
+// class ConfigError < StandardError
+// end
+//
+// begin
+//   raise ConfigError, "Missing key"
+// rescue StandardError => e
+//   e.class.name # => "ConfigError"
+//   e.message    # => "Missing key"
+// end
+// ```
+//
+// The builtin error types:
 //
 // * `InternalError`: default error type
 // * `ArgumentError`: an argument-related error
@@ -19,43 +35,320 @@ import (
 // * `TypeError`: a type-related error
 // * `UndefinedMethodError`: undefined-method error
 // * `UnsupportedMethodError`: intentionally unsupported-method error
+// * `RuntimeError`: the default error type of `raise`
+// * `FrozenError`: modifying a frozen object
+// * `LocalJumpError`: returning from a method that has already returned
+// * `ThreadError`: an invalid operation on a thread, like joining the current thread
+// * `ClosedChannelError`: delivering to or closing a closed channel
+// * `ClosedQueueError`: pushing to a closed queue
+// * `StopIteration`: the end of an enumerator's external iteration with `next` or `peek`
+// * `ParallelError`: the errors raised by the blocks of `Array#pmap`
+// * `SystemExit`: raised by `exit` and `abort`, it inherits from `Exception` so `rescue` without classes doesn't
+//   rescue it
 //
 type Error struct {
 	*baseObj
+	// Message is printed when the error stops the program, it contains the error's class and source location
 	Message string
+	// message is the message that the error is created with
+	message   string
+	backtrace []string
+	cause     *Error
+	// rescued marks an error that is an ordinary value instead of a raised one, like a rescued error or an error
+	// created by `new`, so it doesn't stop the program
+	rescued bool
+	// status is the exit status of a SystemExit
+	status int
+}
+
+// Class methods --------------------------------------------------------
+func builtinErrorClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new error with the given message, which is the class name by default.
+			// The error is raised by passing it to `raise`.
+			//
+			// ```ruby
+			// e = ArgumentError.new("Invalid argument")
+			// e.message # => "Invalid argument"
+			// raise e
+			// ```
+			//
+			// @return [Error]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c := receiver.(*RClass)
+
+					switch len(args) {
+					case 0:
+						return &Error{baseObj: &baseObj{class: c, InstanceVariables: newEnvironment()}, message: c.Name, rescued: true}
+					case 1:
+						message, ok := args[0].(*StringObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						}
+
+						return &Error{baseObj: &baseObj{class: c, InstanceVariables: newEnvironment()}, message: message.value, rescued: true}
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+					}
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinErrorInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the source locations of the call frames when the error was raised, from the innermost one.
+			// Returns nil if the error hasn't been raised.
+			//
+			// ```ruby
+			// def foo
+			//   raise "Oops"
+			// end
+			//
+			// begin
+			//   foo
+			// rescue => e
+			//   e.backtrace # => ["main.gb:2", "main.gb:6"]
+			// end
+			// ```
+			//
+			// @return [Array]
+			Name: "backtrace",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					err := receiver.(*Error)
+
+					if err.backtrace == nil {
+						return NULL
+					}
+
+					lines := []Object{}
+
+					for _, line := range err.backtrace {
+						lines = append(lines, t.vm.initStringObject(line))
+					}
+
+					return t.vm.initArrayObject(lines)
+				}
+			},
+		},
+		{
+			// Returns the error that was being rescued when the error was raised, or nil if there was none.
+			//
+			// ```ruby
+			// begin
+			//   begin
+			//     10 / 0
+			//   rescue
+			//     raise "Calculation failed"
+			//   end
+			// rescue => e
+			//   e.cause.class.name # => "ZeroDivisionError"
+			// end
+			// ```
+			//
+			// @return [Error]
+			Name: "cause",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					err := receiver.(*Error)
+
+					if err.cause == nil {
+						return NULL
+					}
+
+					return err.cause
+				}
+			},
+		},
+		{
+			// Returns the error's message.
+			//
+			// ```ruby
+			// begin
+			//   raise ArgumentError, "Invalid argument"
+			// rescue => e
+			//   e.message # => "Invalid argument"
+			// end
+			// ```
+			//
+			// @return [String]
+			Name: "message",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*Error).message)
+				}
+			},
+		},
+		{
+			// Returns the error's message, same as `message`.
+			//
+			// ```ruby
+			// ArgumentError.new("Invalid argument").to_s # => "Invalid argument"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("message", receiver)
+				}
+			},
+		},
+	}
+}
+
+// SystemExit's instance methods
+func builtinSystemExitInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the status that the program exits with.
+			//
+			// ```ruby
+			// begin
+			//   exit(2)
+			// rescue SystemExit => e
+			//   e.status # => 2
+			// end
+			// ```
+			//
+			// @return [Integer]
+			Name: "status",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*Error).status)
+				}
+			},
+		},
+		{
+			// Returns true if the exit status is 0.
+			//
+			// ```ruby
+			// begin
+			//   exit
+			// rescue SystemExit => e
+			//   e.success? # => true
+			// end
+			// ```
+			//
+			// @return [Boolean]
+			Name: "success?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return toBooleanObject(receiver.(*Error).status == 0)
+				}
+			},
+		},
+	}
 }
 
 // Internal functions ===================================================
 
 // Functions for initialization -----------------------------------------
 
-func (vm *VM) initErrorObject(errorType, format string, args ...interface{}) *Error {
-	errClass := vm.objectClass.getClassConstant(errorType)
+// initErrorObject initializes an error of the given built-in class, located at where the thread is running
+func (t *thread) initErrorObject(errorType, format string, args ...interface{}) *Error {
+	errClass := t.vm.objectClass.getClassConstant(errorType)
+	message := fmt.Sprintf(errorType+": "+format, args...)
+	return t.initErrorObjectWithClass(errClass, strings.TrimPrefix(message, errorType+": "))
+}
+
+// initErrorObjectWithClass initializes an error of the given class, which can also be a user-defined error class.
+// The error is located on the thread that creates it, since other threads' call frames can't be read safely.
+func (t *thread) initErrorObjectWithClass(errClass *RClass, message string) *Error {
+	err := &Error{baseObj: &baseObj{class: errClass, InstanceVariables: newEnvironment()}, message: message}
+	t.locateError(err)
+	return err
+}
+
+var errTypes = []string{errors.InternalError, errors.ArgumentError, errors.NameError, errors.TypeError, errors.UndefinedMethodError, errors.UnsupportedMethodError, errors.ConstantAlreadyInitializedError, errors.ZeroDivisionError, errors.FloatDomainError, errors.DomainError, errors.RuntimeError, errors.FrozenError, errors.LocalJumpError, errors.NoMatchingPatternError, errors.ThreadError, errors.ClosedChannelError, errors.ClosedQueueError, errors.StopIteration, errors.ParallelError}
+
+func (vm *VM) initErrorClasses() {
+	ec := vm.initializeClass(errors.Exception, false)
+	ec.setBuiltinMethods(builtinErrorInstanceMethods(), false)
+	ec.setBuiltinMethods(builtinErrorClassMethods(), true)
+	vm.objectClass.setClassConstant(ec)
+
+	sc := vm.initializeClass(errors.StandardError, false)
+	sc.inherits(ec)
+	vm.objectClass.setClassConstant(sc)
+
+	for _, errType := range errTypes {
+		c := vm.initializeClass(errType, false)
+		c.inherits(sc)
+		vm.objectClass.setClassConstant(c)
+	}
+
+	se := vm.initializeClass(errors.SystemExit, false)
+	se.inherits(ec)
+	se.setBuiltinMethods(builtinSystemExitInstanceMethods(), false)
+	vm.objectClass.setClassConstant(se)
+}
+
+// initSystemExit returns a SystemExit error that stops the program with the given status
+func (t *thread) initSystemExit(status int, message string) *Error {
+	err := t.initErrorObject(errors.SystemExit, "%s", message)
+	err.status = status
+	return err
+}
+
+// isErrorClass returns true if the class is Exception or inherits from it
+func (vm *VM) isErrorClass(c *RClass) bool {
+	exception := vm.topLevelClass(errors.Exception)
+
+	for ; c != nil && c != vm.objectClass; c = c.superClass {
+		if c == exception {
+			return true
+		}
+	}
+
+	return false
+}
 
-	t := vm.mainThread
+// locateError sets the error's backtrace from the call frames, adds current source location to its message, and
+// sets the error being rescued as its cause
+func (t *thread) locateError(err *Error) {
 	cf := t.callFrameStack.top()
 
 	// If program counter is 0 means we need to trace back to previous call frame
-	if cf.pc == 0 {
+	if cf != nil && cf.pc == 0 {
 		t.callFrameStack.pop()
 		cf = t.callFrameStack.top()
 	}
 
+	// The main thread has no frames after the program ends, for example when the error is raised in an at_exit block
+	if cf == nil {
+		err.Message = fmt.Sprintf("%s: %s", err.Class().Name, err.message)
+		err.backtrace = []string{}
+		return
+	}
+
 	i := cf.instructionSet.instructions[cf.pc-1]
+	// Add 1 to source line because it's zero indexed
+	err.Message = fmt.Sprintf("%s: %s. At %s:%d", err.Class().Name, err.message, cf.instructionSet.filename, i.sourceLine+1)
+	err.backtrace = []string{}
 
-	return &Error{
-		baseObj: &baseObj{class: errClass},
-		// Add 1 to source line because it's zero indexed
-		Message: fmt.Sprintf("%s. At %s:%d", fmt.Sprintf(errorType+": "+format, args...), cf.instructionSet.filename, i.sourceLine+1),
-	}
-}
+	for n := t.cfp - 1; n >= 0; n-- {
+		f := t.callFrameStack.callFrames[n]
 
-func (vm *VM) initErrorClasses() {
-	errTypes := []string{errors.InternalError, errors.ArgumentError, errors.NameError, errors.TypeError, errors.UndefinedMethodError, errors.UnsupportedMethodError, errors.ConstantAlreadyInitializedError}
+		// Skip block frames that haven't been executed
+		if f.pc == 0 {
+			continue
+		}
 
-	for _, errType := range errTypes {
-		c := vm.initializeClass(errType, false)
-		vm.objectClass.setClassConstant(c)
+		line := f.instructionSet.instructions[f.pc-1].sourceLine + 1
+		err.backtrace = append(err.backtrace, fmt.Sprintf("%s:%d", f.instructionSet.filename, line))
+	}
+
+	if cause := t.currentError(); err.cause == nil && cause != err {
+		err.cause = cause
 	}
 }
 
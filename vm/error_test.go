@@ -149,6 +149,24 @@ func TestArgumentError(t *testing.T) {
 		`,
 			"ArgumentError: Expect at most 3 args for method 'foo'. got: 4",
 			6},
+		{`def connect(host:, port: 80)
+		end
+
+		connect(port: 8080)
+		`, "ArgumentError: Missing keyword argument 'host' for method 'connect'",
+			4},
+		{`def connect(host:, port: 80)
+		end
+
+		connect(host: "localhost", timeout: 10)
+		`, "ArgumentError: Unknown keyword argument 'timeout' for method 'connect'",
+			4},
+		{`def connect(host:)
+		end
+
+		connect("localhost", host: "localhost")
+		`, "ArgumentError: Expect at most 0 args for method 'connect'. got: 1",
+			4},
 	}
 
 	for i, tt := range tests {
@@ -185,6 +203,547 @@ func TestConstantAlreadyInitializedError(t *testing.T) {
 	}
 }
 
+func TestRescueErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		begin
+		  10 / 0
+		rescue ZeroDivisionError => e
+		  e.class.name
+		end
+		`, "ZeroDivisionError"},
+		{`
+		begin
+		  1 + 1
+		rescue
+		  0
+		end
+		`, 2},
+		{`
+		begin
+		rescue
+		end
+		`, nil},
+		{`
+		def foo
+		  raise ArgumentError, "Invalid"
+		end
+
+		begin
+		  foo
+		rescue TypeError
+		  "type"
+		rescue NameError, ArgumentError => e
+		  e.class.name
+		end
+		`, "ArgumentError"},
+		{`
+		a = []
+		b = begin
+		  raise "Oops"
+		rescue
+		  a.push("rescue")
+		  1
+		ensure
+		  a.push("ensure")
+		end
+		a.push(b)
+		a.join(",")
+		`, "rescue,ensure,1"},
+		{`
+		a = []
+
+		begin
+		  begin
+		    raise TypeError
+		  ensure
+		    a.push("ensure")
+		  end
+		rescue TypeError => e
+		  a.push(e.class.name)
+		end
+		a.join(",")
+		`, "ensure,TypeError"},
+		// The error raised in a block is rescued by the begin expression outside the block
+		{`
+		begin
+		  [1, 2, 3].each do |i|
+		    raise ArgumentError
+		  end
+		rescue ArgumentError
+		  "rescued"
+		end
+		`, "rescued"},
+		{`
+		count = 0
+		begin
+		  count += 1
+		  if count < 3
+		    raise "Retry"
+		  end
+		  count
+		rescue
+		  retry
+		end
+		`, 3},
+		{`
+		begin
+		  begin
+		    10 / 0
+		  rescue => e
+		    raise
+		  end
+		rescue ZeroDivisionError => e
+		  "re-raised"
+		end
+		`, "re-raised"},
+		{`
+		class MyError < ArgumentError
+		end
+
+		begin
+		  raise MyError
+		rescue ArgumentError => e
+		  e.class.name
+		end
+		`, "MyError"},
+		{`
+		e = begin
+		  raise "Oops"
+		rescue => e
+		  e
+		end
+
+		begin
+		  raise e
+		rescue RuntimeError
+		  "raised again"
+		end
+		`, "raised again"},
+		// A handler left by next doesn't rescue the errors raised after the begin expression
+		{`
+		i = 0
+		result = []
+		while i < 3 do
+		  i += 1
+		  begin
+		    if i == 1
+		      next
+		    end
+		    result.push(i)
+		  rescue
+		    result.push("rescued")
+		  end
+		end
+
+		begin
+		  raise "Oops"
+		rescue
+		  result.push("outer")
+		end
+		result.join(",")
+		`, "2,3,outer"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestEnsureWithJumps(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		// return in a method
+		{`
+		a = []
+		def foo(a)
+		  begin
+		    return 1
+		  ensure
+		    a.push("ensure")
+		  end
+		end
+		a.push(foo(a))
+		a.join(",")
+		`, "ensure,1"},
+		// return leaves the nested begin expressions from the innermost one
+		{`
+		a = []
+		def foo(a)
+		  begin
+		    begin
+		      return 1
+		    ensure
+		      a.push("inner")
+		    end
+		  ensure
+		    a.push("outer")
+		  end
+		end
+		a.push(foo(a))
+		a.join(",")
+		`, "inner,outer,1"},
+		// return in a block unwinds the method that has the ensure clause
+		{`
+		a = []
+		def foo(a)
+		  begin
+		    [1, 2].each do |i|
+		      return i
+		    end
+		  rescue
+		    a.push("rescue")
+		  ensure
+		    a.push("ensure")
+		  end
+		end
+		a.push(foo(a))
+		a.join(",")
+		`, "ensure,1"},
+		// break in a loop
+		{`
+		a = []
+		i = 0
+		while i < 3 do
+		  i += 1
+		  begin
+		    break if i == 2
+		  ensure
+		    a.push(i)
+		  end
+		end
+		a.join(",")
+		`, "1,2"},
+		// break in a block unwinds the method that yields it
+		{`
+		a = []
+		def foo(a)
+		  begin
+		    yield
+		    a.push("after yield")
+		  ensure
+		    a.push("ensure")
+		  end
+		end
+		r = foo(a) do
+		  break 10
+		end
+		a.push(r)
+		a.join(",")
+		`, "ensure,10"},
+		{`
+		a = []
+		[1, 2].each do |i|
+		  begin
+		    break
+		  ensure
+		    a.push(i)
+		  end
+		end
+		a.join(",")
+		`, "1"},
+		// next in a block and in a loop
+		{`
+		a = []
+		[1, 2].each do |i|
+		  begin
+		    next if i == 1
+		    a.push("body")
+		  ensure
+		    a.push(i)
+		  end
+		end
+		a.join(",")
+		`, "1,body,2"},
+		{`
+		a = []
+		i = 0
+		until i == 2 do
+		  i += 1
+		  begin
+		    next
+		  ensure
+		    a.push(i)
+		  end
+		end
+		a.join(",")
+		`, "1,2"},
+		// redo
+		{`
+		a = []
+		redone = false
+		[1].each do |i|
+		  begin
+		    if !redone
+		      redone = true
+		      redo
+		    end
+		  ensure
+		    a.push(i)
+		  end
+		end
+		a.join(",")
+		`, "1,1"},
+		// retry leaves the begin expression in the rescue clause, but not its own
+		{`
+		a = []
+		count = 0
+		begin
+		  count += 1
+		  raise "Retry" if count < 3
+		rescue
+		  begin
+		    retry
+		  ensure
+		    a.push("inner")
+		  end
+		ensure
+		  a.push("outer")
+		end
+		a.join(",")
+		`, "inner,inner,outer"},
+		// the error raised in the ensure clause replaces the jump
+		{`
+		def foo
+		  begin
+		    [1].each do |i|
+		      return i
+		    end
+		  ensure
+		    raise "Ensure"
+		  end
+		end
+
+		begin
+		  foo
+		rescue => e
+		  e.message
+		end
+		`, "Ensure"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRescueErrorsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		begin
+		  10 / 0
+		rescue ArgumentError
+		  1
+		end
+		`, "ZeroDivisionError: Divided by 0", 3},
+		{`
+		begin
+		  10 / 0
+		rescue
+		  raise "In rescue"
+		end
+		`, "RuntimeError: In rescue", 5},
+		{`raise`, "RuntimeError: unhandled exception", 1},
+		{`raise "Oops"`, "RuntimeError: Oops", 1},
+		{`raise ArgumentError`, "ArgumentError: ArgumentError", 1},
+		{`raise TypeError, "Invalid type"`, "TypeError: Invalid type", 1},
+		{`raise String`, "TypeError: Expect argument to be an error class or an error. got: String", 1},
+		{`raise 1`, "TypeError: Expect argument to be an error class or an error. got: Integer", 1},
+		{`raise ArgumentError, 1`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`raise ArgumentError, "a", "b"`, "ArgumentError: Expect 0..2 arguments. got=3", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestErrorClassHierarchy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`StandardError.superclass.name`, "Exception"},
+		{`ArgumentError.superclass.name`, "StandardError"},
+		{`ZeroDivisionError.new.is_a?(StandardError)`, true},
+		{`
+		class ConfigError < StandardError
+		end
+
+		class MissingKeyError < ConfigError
+		end
+
+		begin
+		  raise MissingKeyError, "Missing key"
+		rescue ConfigError => e
+		  e.class.name + ": " + e.message
+		end
+		`, "MissingKeyError: Missing key"},
+		{`
+		class ConfigError < StandardError
+		end
+
+		begin
+		  raise ConfigError
+		rescue => e
+		  e.message
+		end
+		`, "ConfigError"},
+		// rescue without error classes only rescues StandardError
+		{`
+		begin
+		  begin
+		    raise Exception, "Fatal"
+		  rescue
+		    "rescued by StandardError"
+		  end
+		rescue Exception => e
+		  e.message
+		end
+		`, "Fatal"},
+		{`
+		class ConfigError < StandardError
+		  def message
+		    "Invalid config"
+		  end
+		end
+
+		begin
+		  raise ConfigError
+		rescue => e
+		  e.to_s
+		end
+		`, "Invalid config"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestErrorObjectMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`ArgumentError.new("Invalid").message`, "Invalid"},
+		{`ArgumentError.new.message`, "ArgumentError"},
+		{`ArgumentError.new("Invalid").to_s`, "Invalid"},
+		{`ArgumentError.new.backtrace`, nil},
+		{`ArgumentError.new.cause`, nil},
+		{`
+		def foo
+		  bar
+		end
+
+		def bar
+		  raise "Oops"
+		end
+
+		begin
+		  foo
+		rescue => e
+		  e.backtrace.length
+		end
+		`, 3},
+		{`
+		e = ArgumentError.new("Invalid")
+
+		begin
+		  raise e
+		rescue => err
+		  err.backtrace.length
+		end
+		`, 1},
+		{`
+		begin
+		  begin
+		    10 / 0
+		  rescue
+		    raise ArgumentError, "Calculation failed"
+		  end
+		rescue => e
+		  e.cause.class.name + ": " + e.cause.message
+		end
+		`, "ZeroDivisionError: Divided by 0"},
+		// An error raised after the rescue clause has no cause
+		{`
+		begin
+		  10 / 0
+		rescue
+		end
+
+		begin
+		  raise "Oops"
+		rescue => e
+		  e.cause
+		end
+		`, nil},
+		{`
+		begin
+		  raise "Oops"
+		rescue => e
+		  e.cause
+		end
+		`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestErrorObjectMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`ArgumentError.new(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`ArgumentError.new("a", "b")`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`
+		class ConfigError < StandardError
+		end
+
+		e = ConfigError.new("Invalid config")
+		raise e
+		`, "ConfigError: Invalid config", 6},
+		{`
+		class Foo
+		end
+
+		raise Foo
+		`, "TypeError: Expect argument to be an error class or an error. got: Foo", 5},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
 func checkError(t *testing.T, index int, evaluated Object, expectedErrMsg, fn string, line int) {
 	err, ok := evaluated.(*Error)
 	if !ok {
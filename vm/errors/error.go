@@ -1,6 +1,10 @@
 package errors
 
 const (
+	// Exception is the root of all error classes
+	Exception = "Exception"
+	// StandardError is the parent of builtin error classes, which is rescued by `rescue` without error classes
+	StandardError = "StandardError"
 	// InternalError is the default error type
 	InternalError = "InternalError"
 	// ArgumentError is for an argument-related error
@@ -15,6 +19,32 @@ const (
 	UnsupportedMethodError = "UnsupportedMethodError"
 	// ConstantAlreadyInitializedError means user re-declares twice
 	ConstantAlreadyInitializedError = "ConstantAlreadyInitializedError"
+	// ZeroDivisionError is for a division by zero
+	ZeroDivisionError = "ZeroDivisionError"
+	// FloatDomainError is for converting an infinite or NaN Float to Integer
+	FloatDomainError = "FloatDomainError"
+	// DomainError is for calling a mathematical function with an argument outside of its domain
+	DomainError = "DomainError"
+	// RuntimeError is the default error type of `raise`
+	RuntimeError = "RuntimeError"
+	// FrozenError is for modifying a frozen object
+	FrozenError = "FrozenError"
+	// LocalJumpError is for returning from a method that has already returned
+	LocalJumpError = "LocalJumpError"
+	// NoMatchingPatternError is for a case expression whose in clauses don't match the value
+	NoMatchingPatternError = "NoMatchingPatternError"
+	// ThreadError is for an invalid operation on a thread, like joining the current thread
+	ThreadError = "ThreadError"
+	// ClosedChannelError is for delivering to or closing a closed channel
+	ClosedChannelError = "ClosedChannelError"
+	// ClosedQueueError is for pushing to a closed queue
+	ClosedQueueError = "ClosedQueueError"
+	// StopIteration is raised by `Enumerator#next` and `Enumerator#peek` when the enumerator reaches its end
+	StopIteration = "StopIteration"
+	// ParallelError is for the errors raised by the blocks of Array#pmap
+	ParallelError = "ParallelError"
+	// SystemExit is raised by `exit` and `abort`, it stops the program with its status when it's not rescued
+	SystemExit = "SystemExit"
 )
 
 /*
@@ -24,4 +54,5 @@ const (
 	WrongNumberOfArgumentFormat = "Expect %d arguments. got: %d"
 	WrongArgumentTypeFormat     = "Expect argument to be %s. got: %s"
 	CantYieldWithoutBlockFormat = "Can't yield without a block"
+	OutOfDomainFormat           = "Numerical argument is out of domain - \"%s\""
 )
@@ -94,6 +94,31 @@ func TestComment(t *testing.T) {
 	v.checkSP(t, 0, 1)
 }
 
+func TestBlockComment(t *testing.T) {
+	input := `
+	x = 1
+=begin
+	x = 2
+	def foo; end
+=end
+	class Foo
+=begin Comment
+	  x = 3
+=end Comment
+
+	  def bar
+	    __LINE__
+	  end
+	end
+	Foo.new.bar + x`
+
+	v := initTestVM()
+	evaluated := v.testEval(t, input, getFilename())
+	testIntegerObject(t, 0, evaluated, 14)
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
 func TestMethodCall(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -411,6 +436,192 @@ func TestMethodCallWithSplatArgument(t *testing.T) {
 	}
 }
 
+func TestMethodCallWithDefaultArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		def greet(name, greeting = "Hello")
+		  greeting + ", " + name
+		end
+
+		greet("Goby")
+		`, "Hello, Goby"},
+		{`
+		def greet(name, greeting = "Hello")
+		  greeting + ", " + name
+		end
+
+		greet("Goby", "Hi")
+		`, "Hi, Goby"},
+		{`
+		class Greeter
+		  def initialize
+		    @greeting = "Hey"
+		  end
+
+		  def punctuation
+		    "!"
+		  end
+
+		  def greet(name, greeting = @greeting, mark = punctuation)
+		    greeting + ", " + name + mark
+		  end
+		end
+
+		Greeter.new.greet("Goby")
+		`, "Hey, Goby!"},
+		{`
+		def foo(a, b = a * 2)
+		  a + b
+		end
+
+		foo(3)
+		`, 9},
+		{`
+		def foo(a = [])
+		  a.push(1)
+		  a.length
+		end
+
+		foo
+		foo
+		`, 1},
+		{`
+		def foo
+		  yield(1)
+		end
+
+		foo do |a, b = 10|
+		  a + b
+		end
+		`, 11},
+		{`
+		def foo
+		  yield(1, 2)
+		end
+
+		foo do |a, b = 10|
+		  a + b
+		end
+		`, 3},
+		{`
+		def foo
+		  yield(3)
+		end
+
+		foo do |a, b = a * 2, c = (a | b)|
+		  a + b + c
+		end
+		`, 16},
+		{`
+		sum = 0
+		[1, 2].each do |i, step = 100|
+		  sum = sum + i + step
+		end
+		sum
+		`, 203},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+
+		if isError(evaluated) {
+			t.Fatalf("got Error: %s", evaluated.(*Error).Message)
+		}
+
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMethodCallWithKeywordArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		def connect(host:, port: 80)
+		  host + ":" + port.to_s
+		end
+
+		connect(host: "localhost")
+		`, "localhost:80"},
+		{`
+		def connect(host:, port: 80)
+		  host + ":" + port.to_s
+		end
+
+		connect(port: 8080, host: "localhost")
+		`, "localhost:8080"},
+		{`
+		def connect(host:, port: 80)
+		  host + ":" + port.to_s
+		end
+
+		connect({ host: "localhost", port: 3000 })
+		`, "localhost:3000"},
+		{`
+		def foo(a, b = 2, *c, d: 4)
+		  a + b + c.length + d
+		end
+
+		foo(1, 10, 100, 1000, d: 5)
+		`, 18},
+		{`
+		def foo(a, d: a * 2)
+		  d
+		end
+
+		foo(4)
+		`, 8},
+		{`
+		def foo(h, k: 1)
+		  h[:a] + k
+		end
+
+		foo({ a: 10 })
+		`, 11},
+		{`
+		def foo(h, k: 1)
+		  h[:a] + k
+		end
+
+		foo({ a: 10 }, k: 5)
+		`, 15},
+		{`
+		def foo(a, opts = {})
+		  opts[:x] + opts[:y]
+		end
+
+		foo(1, x: 2, y: 3)
+		`, 5},
+		{`
+		def foo(opts)
+		  opts.to_s
+		end
+
+		foo(x: 1)
+		`, "{ x: 1 }"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+
+		if isError(evaluated) {
+			t.Fatalf("got Error: %s", evaluated.(*Error).Message)
+		}
+
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestMethodCallWithBlockArgument(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -562,6 +773,122 @@ func TestMethodCallWithBlockArgument(t *testing.T) {
 	}
 }
 
+func TestYieldMultipleValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		def each_pair
+		  yield 1, 2
+		  yield 3, 4
+		end
+
+		sum = 0
+		each_pair do |a, b|
+		  sum += a * b
+		end
+		sum
+		`, 14},
+		{`
+		def foo
+		  yield [1, 2]
+		end
+
+		foo do |a, b|
+		  a + b
+		end
+		`, 3},
+		{`
+		def foo
+		  yield [1, 2]
+		end
+
+		foo do |a|
+		  a.length
+		end
+		`, 2},
+		{`
+		def foo
+		  yield [1, 2, 3]
+		end
+
+		foo do |a, b = 10|
+		  a + b
+		end
+		`, 3},
+		{`
+		sum = 0
+		[[1, 2], [3, 4]].each do |a, b|
+		  sum += a * b
+		end
+		sum
+		`, 14},
+		{`
+		p = Proc.new do |a, b|
+		  b
+		end
+		p.call([1, 2])
+		`, 2},
+		{`
+		l = ->(a, b = 5) { b }
+		l.call([1, 2])
+		`, 5},
+		{`
+		def foo
+		  yield 1
+		end
+
+		foo do |a, b|
+		  b
+		end
+		`, nil},
+		{`
+		def foo
+		  block_given?
+		end
+
+		foo
+		`, false},
+		{`
+		def foo
+		  block_given?
+		end
+
+		foo do
+		end
+		`, true},
+		{`
+		def foo
+		  [1].map do
+		    block_given?
+		  end.first
+		end
+
+		foo
+		`, false},
+		{`
+		def foo
+		  [1].map do
+		    block_given?
+		  end.first
+		end
+
+		foo do
+		end
+		`, true},
+		{`block_given?`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestMethodCallWithNestedBlock(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1138,12 +1465,19 @@ func TestBangPrefixMethodCall(t *testing.T) {
 func TestMinusPrefixMethodCall(t *testing.T) {
 	tests := []struct {
 		input    string
-		expected int
+		expected interface{}
 	}{
 		{"-5", -5},
 		{"-10", -10},
 		{"-(-10)", 10},
 		{"-(-5)", 5},
+		{"-2.5", -2.5},
+		{`
+		a = 3
+		-a
+		`, -3},
+		{"(-(-9223372036854775807 - 1)).to_s", "9223372036854775808"},
+		{"(-(1/2r)).to_s", "-1/2"},
 	}
 
 	for i, tt := range tests {
@@ -1155,6 +1489,102 @@ func TestMinusPrefixMethodCall(t *testing.T) {
 	}
 }
 
+func TestOperatorOverloading(t *testing.T) {
+	vectorClass := `
+	class Vector
+	  attr_reader(:x, :y)
+
+	  def initialize(x, y)
+	    @x = x
+	    @y = y
+	  end
+
+	  def +(other)
+	    Vector.new(x + other.x, y + other.y)
+	  end
+
+	  def -(other)
+	    Vector.new(x - other.x, y - other.y)
+	  end
+
+	  def *(n)
+	    Vector.new(x * n, y * n)
+	  end
+
+	  def ==(other)
+	    x == other.x && y == other.y
+	  end
+
+	  def <=>(other)
+	    (x * x + y * y) <=> (other.x * other.x + other.y * other.y)
+	  end
+
+	  def [](i)
+	    if i == 0
+	      x
+	    else
+	      y
+	    end
+	  end
+
+	  def []=(i, value)
+	    if i == 0
+	      @x = value
+	    else
+	      @y = value
+	    end
+	  end
+
+	  def <<(n)
+	    @x = x + n
+	    @y = y + n
+	    self
+	  end
+
+	  def -@
+	    Vector.new(-x, -y)
+	  end
+
+	  def to_s
+	    "(" + x.to_s + ", " + y.to_s + ")"
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(Vector.new(1, 2) + Vector.new(3, 4)).to_s`, "(4, 6)"},
+		{`(Vector.new(3, 4) - Vector.new(1, 2)).to_s`, "(2, 2)"},
+		{`(Vector.new(1, 2) * 3).to_s`, "(3, 6)"},
+		{`Vector.new(1, 2) == Vector.new(1, 2)`, true},
+		{`Vector.new(1, 2) != Vector.new(1, 2)`, false},
+		{`Vector.new(1, 2) != Vector.new(2, 1)`, true},
+		{`Vector.new(1, 2) <=> Vector.new(3, 4)`, -1},
+		{`Vector.new(1, 2)[1]`, 2},
+		{`
+		v = Vector.new(1, 2)
+		v[0] = 10
+		v.to_s
+		`, "(10, 2)"},
+		{`
+		v = Vector.new(1, 2)
+		v << 1 << 2
+		v.to_s
+		`, "(4, 5)"},
+		{`(-Vector.new(1, -2)).to_s`, "(-1, 2)"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, vectorClass+tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestSelfExpressionEvaluation(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1441,6 +1871,15 @@ func TestAssignmentByOperationEvaluation(t *testing.T) {
 		{"a = 5; a += 2 * 3 + 5; a;", 16},
 		{"a = 5; a -= 2 * 3 + 5; a;", -6},
 		{"a = false; a ||= true; a;", true},
+		{"a = 5; a *= 2 + 1; a;", 15},
+		{"a = 15; a /= 2 + 1; a;", 5},
+		{"a = 1; a &&= 2; a;", 2},
+		{"a = nil; a &&= 2; a;", nil},
+		{"@a = 2; @a *= 3; @a;", 6},
+		{"@a ||= 3; @a &&= @a + 1; @a;", 4},
+		{"Foo ||= 1; Foo;", 1},
+		{"Foo = 1; Foo ||= 2; Foo;", 1},
+		{"a = (Foo = 2); a;", 2},
 	}
 
 	for i, tt := range tests {
@@ -1452,22 +1891,160 @@ func TestAssignmentByOperationEvaluation(t *testing.T) {
 	}
 }
 
-func TestIfExpressionEvaluation(t *testing.T) {
+func TestAssignmentByOperationOnCallsEvaluation(t *testing.T) {
 	tests := []struct {
-		input    string
-		expected interface{}
+		input         string
+		expectedValue interface{}
 	}{
-		{
-			`
-			if 10 > 5
-				100
-			elsif 10 == 9
-			  0
-			else
-				-10
-			end
-			`,
-			100,
+		{`
+		h = { a: 1 }
+		h["a"] += 2
+		h["a"]
+		`, 3},
+		{`
+		a = [2, 4]
+		a[1] *= 3
+		a[1] /= 2
+		a[1] -= 1
+		a.to_s
+		`, "[2, 5]"},
+		{`
+		h = {}
+		h["a"] ||= 1
+		h["a"] ||= 2
+		h["a"] &&= h["a"] + 10
+		h["a"]
+		`, 11},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		f = Foo.new
+		f.bar ||= 1
+		f.bar += 2
+		f.bar *= 3
+		f.bar
+		`, 9},
+		{`
+		class Foo
+		  attr_reader :calls
+
+		  def initialize
+		    @calls = []
+		  end
+
+		  def bar
+		    @calls.push("bar")
+		    @bar
+		  end
+
+		  def bar=(v)
+		    @calls.push("bar=")
+		    @bar = v
+		  end
+		end
+
+		f = Foo.new
+		f.bar ||= 1
+		f.bar ||= 2
+		f.bar &&= 3
+		f.calls.to_s
+		`, `["bar", "bar=", "bar", "bar", "bar="]`},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		f = Foo.new
+		f.bar = 1
+		a = (f.bar += 1)
+		a
+		`, 2},
+		// The receiver and the index are only evaluated once
+		{`
+		calls = []
+		a = [1, 2]
+		h = {}
+
+		arr = Proc.new do
+		  calls.push("arr")
+		  a
+		end
+
+		idx = Proc.new do |i|
+		  calls.push("idx")
+		  i
+		end
+
+		arr.call[idx.call(1)] += 10
+		arr.call[idx.call(0)] ||= 5
+		arr.call[idx.call(0)] &&= 7
+		x = (h["a"] ||= 3)
+		y = (h["a"] ||= 4)
+		calls.length.to_s + " " + a.to_s + " " + x.to_s + y.to_s
+		`, "6 [7, 12] 33"},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+
+		f = Foo.new
+		f.bar = 1
+		count = 0
+
+		make = Proc.new do
+		  count += 1
+		  f
+		end
+
+		make.call.bar += 1
+		make.call.bar ||= 5
+		make.call.bar &&= make.call.bar * 3
+		count.to_s + " " + f.bar.to_s
+		`, "4 6"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expectedValue)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestAssignmentByOperationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Foo = 1; Foo += 1`, "ConstantAlreadyInitializedError: Constant Foo already been initialized. Can't assign value to a constant twice.", 1},
+		{`Foo &&= 1`, "NameError: uninitialized constant Foo", 1},
+		{`1.to_s += "a"`, "UndefinedMethodError: Undefined Method 'to_s=' for 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestIfExpressionEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{
+			`
+			if 10 > 5
+				100
+			elsif 10 == 9
+			  0
+			else
+				-10
+			end
+			`,
+			100,
 		},
 		{
 			`
@@ -1620,6 +2197,381 @@ func TestIfExpressionEvaluation(t *testing.T) {
 	}
 }
 
+func TestTernaryExpressionEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`10 > 5 ? "yes" : "no"`, "yes"},
+		{`10 < 5 ? "yes" : "no"`, "no"},
+		{`nil ? 1 : 2`, 2},
+		{`
+		x = 5
+		y = x.nil? ? :a : :b
+		y.to_s
+		`, "b"},
+		{`
+		x = 6
+		x == 5 ? 1 : x == 6 ? 2 : 3
+		`, 2},
+		{`
+		a = 1
+		b = 2
+		c = a > b ? a : b
+		c
+		`, 2},
+		{`
+		def max(a, b)
+		  a > b ? a : b
+		end
+
+		max(3, 9) + max(10, 1)
+		`, 19},
+		{`{ a: 1 > 2 ? 1 : 2 }[:a]`, 2},
+		{`[1, 2].map do |i| i.even? ? "even" : "odd" end.to_s`, `["odd", "even"]`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestUnlessExpressionEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		unless 10 > 5
+		  100
+		else
+		  -10
+		end
+		`, -10},
+		{`
+		unless 10 < 5
+		  100
+		else
+		  -10
+		end
+		`, 100},
+		{`
+		unless 10 > 5
+		  100
+		end
+		`, nil},
+		{`
+		x = 1
+		unless x.nil?
+		  x = x + 1
+		  x * 10
+		end
+		`, 20},
+		{`
+		def foo(x)
+		  unless x
+		    "falsy"
+		  else
+		    "truthy"
+		  end
+		end
+
+		foo(nil) + foo(false) + foo(0)
+		`, "falsyfalsytruthy"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCaseExpressionEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		def match(v)
+		  case v
+		  in 0
+		    "zero"
+		  in Integer | Float => n if n > 100
+		    "big"
+		  in Integer
+		    "integer"
+		  in /go/
+		    "go"
+		  in String => s unless s.empty?
+		    s
+		  in nil
+		    "nil"
+		  else
+		    "other"
+		  end
+		end
+
+		[match(0), match(101), match(1.5e3), match(5), match("gopher"), match("ruby"), match(""), match(nil)].to_s
+		`, `["zero", "big", "big", "integer", "go", "ruby", "other", "nil"]`},
+		{`
+		case [1, [2, 3, 4], 5]
+		in [Integer => a, [b, *rest], c]
+		  [a, b, rest, c].to_s
+		end
+		`, "[1, 2, [3, 4], 5]"},
+		{`
+		case [1, 2, 3]
+		in [_, _]
+		  "two"
+		in first, *, last
+		  first + last
+		end
+		`, 4},
+		{`
+		case []
+		in [*]
+		  "empty"
+		end
+		`, "empty"},
+		{`
+		case { name: "Goby", tags: ["lang", "go"], version: 1 }
+		in { name: String => name, tags: [_, tag] }
+		  name + " " + tag
+		end
+		`, "Goby go"},
+		{`
+		case { status: 404 }
+		in { status: 200..299 }
+		  "success"
+		in status: 400..499 => code
+		  code
+		end
+		`, 404},
+		{`
+		result = case { name: "Goby", lang: "go" }
+		         in { name:, lang: "ruby" }
+		           "ruby"
+		         in { name:, lang: }
+		           name + lang
+		         end
+		result
+		`, "Gobygo"},
+		{`
+		case {}
+		in { a: }
+		  "a"
+		in {}
+		  "empty"
+		end
+		`, "empty"},
+		{`
+		expected = 5
+		@inner = [5, 6]
+
+		case [5, [5, 6]]
+		in [^expected, ^@inner]
+		  "pinned"
+		end
+		`, "pinned"},
+		{`
+		expected = 5
+
+		case 6
+		in ^expected
+		  "pinned"
+		in ^(expected + 1)
+		  "next"
+		end
+		`, "next"},
+		{`
+		class Point
+		  def initialize(x, y)
+		    @x = x
+		    @y = y
+		  end
+
+		  def deconstruct
+		    [@x, @y]
+		  end
+
+		  def deconstruct_keys(keys)
+		    { x: @x, y: @y }
+		  end
+		end
+
+		a = case Point.new(1, 2)
+		    in Point[x, 0]
+		      "x axis"
+		    in Point(x, y)
+		      x + y
+		    end
+
+		b = case Point.new(3, 4)
+		    in Point(x:, y: 4 => y)
+		      x * y
+		    end
+
+		c = case Point.new(3, 4)
+		    in String[*]
+		      "string"
+		    in [x, y]
+		      x - y
+		    end
+
+		[a, b, c].to_s
+		`, "[3, 12, -1]"},
+		{`
+		class Even
+		  def ===(other)
+		    other.even?
+		  end
+		end
+
+		EVEN = Even.new
+
+		case 4
+		in EVEN
+		  "even"
+		end
+		`, "even"},
+		{`
+		begin
+		  case 1
+		  in String
+		    "string"
+		  end
+		rescue NoMatchingPatternError => e
+		  e.message
+		end
+		`, "1"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCaseExpressionEvaluationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`case 1
+		in String
+		  "string"
+		end`, "NoMatchingPatternError: 1", 1},
+		{`case [1, "a"]
+		in [Integer, Integer]
+		  "integers"
+		end`, "NoMatchingPatternError: [1, \"a\"]", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestStatementModifierEvaluation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		x = 1
+		x = 10 if x > 0
+		x
+		`, 10},
+		{`
+		x = 1
+		x = 10 if x < 0
+		x
+		`, 1},
+		{`
+		x = 1
+		x = 10 unless x < 0
+		x
+		`, 10},
+		{`
+		x = 1
+		x = 10 unless x > 0
+		x
+		`, 1},
+		{`
+		x = 10 if false
+		x
+		`, nil},
+		{`
+		i = 0
+		i += 1 while i < 10
+		i
+		`, 10},
+		{`
+		def sign(n)
+		  return "negative" if n < 0
+		  return "zero" unless n > 0
+		  "positive"
+		end
+
+		sign(-1) + sign(0) + sign(1)
+		`, "negativezeropositive"},
+		{`
+		def foo
+		  return if true
+		  1
+		end
+
+		foo
+		`, nil},
+		{`
+		sum = 0
+		i = 0
+		while i < 4 do
+		  i += 1
+		  next if i.even?
+		  sum += i
+		end
+		sum
+		`, 4},
+		{`
+		i = 0
+		while true do
+		  i += 1
+		  break if i == 5
+		end
+		i
+		`, 5},
+		{`
+		x = 0
+		x += 1 if x < 5 while x < 3
+		x
+		`, 3},
+		{`
+		i = 10
+		i -= 3 until i < 0
+		i
+		`, -2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestClassInheritance(t *testing.T) {
 	input := `
 		class Bar
@@ -1641,6 +2593,214 @@ func TestClassInheritance(t *testing.T) {
 	v.checkSP(t, 0, 1)
 }
 
+func TestSuperMethodCall(t *testing.T) {
+	animalClass := `
+	class Animal
+	  def initialize(name)
+	    @name = name
+	  end
+
+	  def name
+	    @name
+	  end
+
+	  def speak(punct = ".")
+	    @name + " speaks" + punct
+	  end
+
+	  def each_twice
+	    yield(1)
+	    yield(2)
+	  end
+
+	  def greet(greeting, to: "you")
+	    greeting + ", " + to
+	  end
+
+	  def self.create(name)
+	    new(name)
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{animalClass + `
+		class Dog < Animal
+		  def initialize(name)
+		    super
+		    @name = @name + " the dog"
+		  end
+		end
+
+		Dog.new("Rex").name
+		`, "Rex the dog"},
+		{animalClass + `
+		class Dog < Animal
+		  def speak(punct = "!")
+		    super + " Woof"
+		  end
+		end
+
+		Dog.new("Rex").speak
+		`, "Rex speaks! Woof"},
+		{animalClass + `
+		class Dog < Animal
+		  def speak(punct = "!")
+		    super("?")
+		  end
+		end
+
+		Dog.new("Rex").speak
+		`, "Rex speaks?"},
+		{animalClass + `
+		class Dog < Animal
+		  def speak(punct = "!")
+		    super()
+		  end
+		end
+
+		Dog.new("Rex").speak
+		`, "Rex speaks."},
+		{animalClass + `
+		class Dog < Animal
+		  def speak(punct = "!")
+		    punct = "~"
+		    super
+		  end
+		end
+
+		Dog.new("Rex").speak
+		`, "Rex speaks~"},
+		{animalClass + `
+		class Dog < Animal
+		  def greet(greeting, to: "dogs")
+		    super
+		  end
+		end
+
+		Dog.new("Rex").greet("Hi")
+		`, "Hi, dogs"},
+		{animalClass + `
+		class Dog < Animal
+		  def each_twice
+		    super
+		  end
+		end
+
+		sum = 0
+		Dog.new("Rex").each_twice do |i|
+		  sum = sum + i
+		end
+		sum
+		`, 3},
+		{animalClass + `
+		class Dog < Animal
+		  def each_twice
+		    super do |i|
+		      yield(i * 10)
+		    end
+		  end
+		end
+
+		sum = 0
+		Dog.new("Rex").each_twice do |i|
+		  sum = sum + i
+		end
+		sum
+		`, 30},
+		{animalClass + `
+		class Dog < Animal
+		  def self.create(name)
+		    super(name + "!")
+		  end
+		end
+
+		Dog.create("Rex").name
+		`, "Rex!"},
+		{animalClass + `
+		module Loud
+		  def speak(punct = ".")
+		    super.upcase
+		  end
+		end
+
+		class Cat < Animal
+		  include Loud
+		end
+
+		Cat.new("Tom").speak
+		`, "TOM SPEAKS."},
+		{animalClass + `
+		class Dog < Animal
+		  def speak(punct = "!")
+		    [1].map do |i|
+		      super
+		    end.first
+		  end
+		end
+
+		Dog.new("Rex").speak
+		`, "Rex speaks!"},
+		{`
+		class Foo
+		  def to_s
+		    "Foo: " + super
+		  end
+		end
+
+		Foo.new.to_s
+		`, "Foo: <Instance of: Foo>"},
+		{`
+		class Foo
+		  def ==(other)
+		    super
+		  end
+		end
+
+		f = Foo.new
+		f == f
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSuperMethodCallFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`super`, "UndefinedMethodError: super called outside of method", 1},
+		{`
+		class Foo
+		  def bar
+		    super
+		  end
+		end
+
+		begin
+		  Foo.new.bar
+		rescue UndefinedMethodError => e
+		  raise e
+		end
+		`, "UndefinedMethodError: Superclass method 'bar' not found for <Instance of: Foo>", 4},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestMultiVarAssignment(t *testing.T) {
 	tests := []struct {
 		input    string
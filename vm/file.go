@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 	"syscall"
 
 	"github.com/goby-lang/goby/vm/classes"
@@ -12,16 +13,20 @@ import (
 )
 
 // FileObject is a special type that contains file pointer so we can keep track on target file.
+// It also represents the IO objects of the standard streams, see IO for the methods they share.
 type FileObject struct {
 	*baseObj
-	File *os.File
+	File   *os.File
+	reader *bufio.Reader
 }
 
 var fileModeTable = map[string]int{
 	"r":  syscall.O_RDONLY,
 	"r+": syscall.O_RDWR,
-	"w":  syscall.O_WRONLY,
-	"w+": syscall.O_RDWR,
+	"w":  syscall.O_WRONLY | syscall.O_CREAT | syscall.O_TRUNC,
+	"w+": syscall.O_RDWR | syscall.O_CREAT | syscall.O_TRUNC,
+	"a":  syscall.O_WRONLY | syscall.O_CREAT | syscall.O_APPEND,
+	"a+": syscall.O_RDWR | syscall.O_CREAT | syscall.O_APPEND,
 }
 
 // Class methods --------------------------------------------------------
@@ -65,7 +70,7 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 
 						err := os.Chmod(filename, os.FileMode(uint32(filemod)))
 						if err != nil {
-							return t.vm.initErrorObject(errors.InternalError, err.Error())
+							return t.initErrorObject(errors.InternalError, err.Error())
 						}
 					}
 
@@ -82,7 +87,7 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 						err := os.Remove(filename)
 
 						if err != nil {
-							return t.vm.initErrorObject(errors.InternalError, err.Error())
+							return t.initErrorObject(errors.InternalError, err.Error())
 						}
 					}
 
@@ -90,6 +95,26 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns all but the last element of path.
+			//
+			// ```ruby
+			// File.dirname("/home/goby/plugin/loop.gb") # => /home/goby/plugin
+			// ```
+			// @param filepath [String]
+			// @return [String]
+			Name: "dirname",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					filename, err := t.filenameArgument(args)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(filepath.Dir(filename))
+				}
+			},
+		},
 		{
 			Name: "exist?",
 			Fn: func(receiver Object) builtinMethodBody {
@@ -105,6 +130,49 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Converts path to an absolute path. Relative paths are resolved against the given
+			// directory, or the current working directory if it's omitted. A leading "~" is
+			// expanded to the home directory.
+			//
+			// ```ruby
+			// File.expand_path("plugin", "/home/goby")  # => /home/goby/plugin
+			// File.expand_path("../loop.gb", "/home")   # => /loop.gb
+			// File.expand_path("~/loop.gb")             # => /home/goby/loop.gb
+			// ```
+			// @param filepath [String], directory [String]
+			// @return [String]
+			Name: "expand_path",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+					}
+
+					var paths []string
+					for _, arg := range args {
+						s, ok := arg.(*StringObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+						}
+
+						paths = append(paths, s.value)
+					}
+
+					dir := ""
+					if len(paths) == 2 {
+						dir = paths[1]
+					}
+
+					path, err := expandPath(paths[0], dir)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initStringObject(path)
+				}
+			},
+		},
 		{
 			// Returns extension part of file.
 			//
@@ -141,6 +209,31 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the last modification time of the file.
+			//
+			// ```ruby
+			// File.mtime("loop.gb").to_s # => "2018-01-02 15:04:05 +0800"
+			// ```
+			// @param filename [String]
+			// @return [Time]
+			Name: "mtime",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					filename, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					fileStats, err := os.Stat(filename)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initTimeObject(fileStats.ModTime())
+				}
+			},
+		},
 		{
 			// Finds the file with given filename and initializes a file object with it.
 			//
@@ -157,7 +250,7 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 					var perm os.FileMode
 
 					if len(args) < 1 {
-						return t.vm.initErrorObject(errors.InternalError, "Expect at least a filename to open file")
+						return t.initErrorObject(errors.InternalError, "Expect at least a filename to open file")
 					}
 
 					if len(args) >= 1 {
@@ -170,11 +263,7 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 							md, ok := fileModeTable[m]
 
 							if !ok {
-								return t.vm.initErrorObject(errors.InternalError, "Unknown file mode: %s", m)
-							}
-
-							if md == syscall.O_RDWR || md == syscall.O_WRONLY {
-								os.Create(fn)
+								return t.initErrorObject(errors.InternalError, "Unknown file mode: %s", m)
 							}
 
 							mode = md
@@ -190,7 +279,7 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 					f, err := os.OpenFile(fn, mode, perm)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					// TODO: Refactor this class retrieval mess
@@ -200,6 +289,61 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the whole content of the file.
+			//
+			// ```ruby
+			// File.read("loop.gb") # => "i = 0\nwhile i < 10 ..."
+			// ```
+			// @param filename [String]
+			// @return [String]
+			Name: "read",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					filename, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					content, err := ioutil.ReadFile(filename)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initStringObject(string(content))
+				}
+			},
+		},
+		{
+			// Returns the lines of the file as an array. Each line keeps its trailing newline.
+			//
+			// ```ruby
+			// File.readlines("names.txt") # => ["Stan\n", "Goby\n"]
+			// ```
+			// @param filename [String]
+			// @return [Array]
+			Name: "readlines",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					filename, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					content, err := ioutil.ReadFile(filename)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					lines := []Object{}
+					for _, line := range splitLines(string(content)) {
+						lines = append(lines, t.vm.initStringObject(line))
+					}
+
+					return t.vm.initArrayObject(lines)
+				}
+			},
+		},
 		{
 			// Returns size of file in bytes.
 			//
@@ -211,14 +355,14 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 			Name: "size",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					filename := args[0].(*StringObject).value
-					if !filepath.IsAbs(filename) {
-						filename = filepath.Join(t.vm.fileDir, filename)
+					filename, e := t.filenameArgument(args)
+					if e != nil {
+						return e
 					}
 
 					fileStats, err := os.Stat(filename)
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					return t.vm.initIntegerObject(int(fileStats.Size()))
@@ -246,6 +390,40 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Writes the string to the file, creating the file if it doesn't exist and truncating it otherwise.
+			// Returns the number of bytes written.
+			//
+			// ```ruby
+			// File.write("/tmp/out.txt", "Goby") # => 4
+			// ```
+			// @param filename [String], content [String]
+			// @return [Integer]
+			Name: "write",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					for _, arg := range args {
+						if _, ok := arg.(*StringObject); !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+						}
+					}
+
+					filename := args[0].(*StringObject).value
+					data := args[1].(*StringObject).value
+
+					err := ioutil.WriteFile(filename, []byte(data), os.FileMode(0644))
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initIntegerObject(len(data))
+				}
+			},
+		},
 	}
 }
 
@@ -253,13 +431,21 @@ func builtinFileClassMethods() []*BuiltinMethodObject {
 func builtinFileInstanceMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
-			Name: "close",
+			// Returns the last modification time of the file.
+			//
+			// ```ruby
+			// File.new("loop.gb").mtime.to_s # => "2018-01-02 15:04:05 +0800"
+			// ```
+			// @return [Time]
+			Name: "mtime",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					file := receiver.(*FileObject).File
-					file.Close()
+					fileStats, err := receiver.(*FileObject).File.Stat()
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
 
-					return NULL
+					return t.vm.initTimeObject(fileStats.ModTime())
 				}
 			},
 		},
@@ -272,32 +458,6 @@ func builtinFileInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
-		{
-			Name: "read",
-			Fn: func(receiver Object) builtinMethodBody {
-				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					var result string
-					var f []byte
-					var err error
-
-					file := receiver.(*FileObject).File
-
-					if file.Name() == "/dev/stdin" {
-						reader := bufio.NewReader(os.Stdin)
-						result, err = reader.ReadString('\n')
-					} else {
-						f, err = ioutil.ReadFile(file.Name())
-						result = string(f)
-					}
-
-					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
-					}
-
-					return t.vm.initStringObject(result)
-				}
-			},
-		},
 		{
 			// Returns size of file in bytes.
 			//
@@ -312,29 +472,13 @@ func builtinFileInstanceMethods() []*BuiltinMethodObject {
 
 					fileStats, err := os.Stat(file.Name())
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					return t.vm.initIntegerObject(int(fileStats.Size()))
 				}
 			},
 		},
-		{
-			Name: "write",
-			Fn: func(receiver Object) builtinMethodBody {
-				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					file := receiver.(*FileObject).File
-					data := args[0].(*StringObject).value
-					length, err := file.Write([]byte(data))
-
-					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
-					}
-
-					return t.vm.initIntegerObject(length)
-				}
-			},
-		},
 	}
 }
 
@@ -351,7 +495,11 @@ func (vm *VM) initFileObject(f *os.File) *FileObject {
 
 func (vm *VM) initFileClass() *RClass {
 	fc := vm.initializeClass(classes.FileClass, false)
+	fc.inherits(vm.topLevelClass(classes.IOClass))
 	fc.setBuiltinMethods(builtinFileClassMethods(), true)
+	// Class methods are also set as instance methods, so IO's instance methods like read and write are set again
+	// to take precedence over File's class methods with the same names
+	fc.setBuiltinMethods(builtinIOInstanceMethods(), false)
 	fc.setBuiltinMethods(builtinFileInstanceMethods(), false)
 
 	vm.libFiles = append(vm.libFiles, "file.gb")
@@ -363,10 +511,60 @@ func (vm *VM) initFileClass() *RClass {
 
 // Returns the object's name as the string format
 func (f *FileObject) toString() string {
-	return "<File: " + f.File.Name() + ">"
+	return "<" + f.class.Name + ": " + f.File.Name() + ">"
 }
 
 // Alias of toString
 func (f *FileObject) toJSON() string {
 	return f.toString()
 }
+
+// Other helper functions -----------------------------------------------
+
+// filenameArgument returns the only argument as a filename, or an error object if the arguments are invalid.
+func (t *thread) filenameArgument(args []Object) (string, *Error) {
+	if len(args) != 1 {
+		return "", t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	filename, ok := args[0].(*StringObject)
+	if !ok {
+		return "", t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	return filename.value, nil
+}
+
+// expandPath converts path to an absolute path, resolving it against dir (or the working directory if dir is empty).
+func expandPath(path, dir string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		return filepath.Join(home, path[1:]), nil
+	}
+
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), nil
+	}
+
+	if dir == "" {
+		wd, err := os.Getwd()
+		if err != nil {
+			return "", err
+		}
+
+		dir = wd
+	} else {
+		d, err := expandPath(dir, "")
+		if err != nil {
+			return "", err
+		}
+
+		dir = d
+	}
+
+	return filepath.Join(dir, path), nil
+}
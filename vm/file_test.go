@@ -1,6 +1,7 @@
 package vm
 
 import (
+	"os"
 	"testing"
 )
 
@@ -138,6 +139,37 @@ func TestFileJoinMethod(t *testing.T) {
 	}
 }
 
+func TestFileOpenMethodClosesFile(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		file = nil
+		File.open("/tmp/out.txt", "w", 0755) do |f|
+		  file = f
+		end
+		file.write("Goby")
+		`, "InternalError: write /tmp/out.txt: file already closed", 6},
+		{`
+		file = nil
+		begin
+		  File.open("/tmp/out.txt", "w", 0755) do |f|
+		    file = f
+		    raise("oops")
+		  end
+		rescue
+		end
+		file.write("Goby")
+		`, "InternalError: write /tmp/out.txt: file already closed", 10},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestFileWriteMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -189,6 +221,37 @@ func TestFileSizeMethod(t *testing.T) {
 	v.checkSP(t, 0, 1)
 }
 
+func TestFileSizeMethodWithRelativePath(t *testing.T) {
+	input := `
+	File.write("file_size_test.txt", "Goby")
+	size = File.size("file_size_test.txt")
+	File.delete("file_size_test.txt")
+	size
+	`
+
+	v := initTestVM()
+	v.fileDir = os.TempDir()
+	evaluated := v.testEval(t, input, getFilename())
+	checkExpected(t, 0, evaluated, 4)
+	v.checkCFP(t, 0, 0)
+	v.checkSP(t, 0, 1)
+}
+
+func TestFileSizeMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`File.size`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`File.size(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestFileSplitMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -208,4 +271,197 @@ func TestFileSplitMethod(t *testing.T) {
 	}
 }
 
+func TestFileDirnameMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`File.dirname("/home/goby/plugin/test.gb")`, "/home/goby/plugin"},
+		{`File.dirname("test.gb")`, "."},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileExpandPathMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`File.expand_path("/home/goby/../plugin")`, "/home/plugin"},
+		{`File.expand_path("plugin", "/home/goby")`, "/home/goby/plugin"},
+		{`File.expand_path("../test.gb", "/home/goby")`, "/home/test.gb"},
+		{`File.expand_path("~/test.gb") == File.expand_path("test.gb", "~")`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileReadAndWriteMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		File.write("/tmp/out.txt", "Goby")
+		`, 4},
+		{`
+		File.write("/tmp/out.txt", "Goby is awesome!!!")
+		File.read("/tmp/out.txt")
+		`, "Goby is awesome!!!"},
+		{`
+		File.read("../test_fixtures/file_test/size.gb")
+		`, "this file's size is\n22"},
+		{`
+		File.write("/tmp/out.txt", "Goby\n")
+		File.open("/tmp/out.txt", "a") do |f|
+		  f.write("Ruby\n")
+		end
+		File.read("/tmp/out.txt")
+		`, "Goby\nRuby\n"},
+		{`
+		File.write("/tmp/out.txt", "Goby is awesome!!!")
+		File.open("/tmp/out.txt", "r+") do |f|
+		  f.write("Ruby")
+		end
+		File.read("/tmp/out.txt")
+		`, "Ruby is awesome!!!"},
+		{`
+		File.open("/tmp/out.txt", "w") do |f|
+		  f.write("Goby")
+		  10
+		end
+		`, 10},
+		{`
+		f = File.open("/tmp/out.txt")
+		content = f.read
+		f.close
+		content
+		`, "Goby"},
+		{`
+		File.write("/tmp/out.txt", "Stan\nGoby\n")
+		File.readlines("/tmp/out.txt").map do |line|
+		  line.chomp
+		end.join(",")
+		`, "Stan,Goby"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileEachLineMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		File.write("/tmp/out.txt", "Stan\nGoby\nRuby")
+		lines = []
+		File.open("/tmp/out.txt") do |f|
+		  f.each_line do |line|
+		    lines.push(line.chomp)
+		  end
+		end
+		lines.join(",")
+		`, "Stan,Goby,Ruby"},
+		{`
+		File.write("/tmp/out.txt", "Stan\nGoby\n")
+		File.open("/tmp/out.txt") do |f|
+		  f.each_line.to_a.length
+		end
+		`, 2},
+		{`
+		File.write("/tmp/out.txt", "")
+		count = 0
+		File.open("/tmp/out.txt") do |f|
+		  f.each_line do |line|
+		    count += 1
+		  end
+		end
+		count
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileMtimeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		File.write("/tmp/out.txt", "Goby")
+		File.mtime("/tmp/out.txt") > Time.now - 60
+		`, true},
+		{`
+		File.write("/tmp/out.txt", "Goby")
+		File.new("/tmp/out.txt").mtime == File.mtime("/tmp/out.txt")
+		`, true},
+		{`
+		File.write("/tmp/out.txt", "Goby")
+		File.mtime("/tmp/out.txt").class.name
+		`, "Time"},
+		{`
+		File.write("/tmp/out.txt", "Goby")
+		File.new("/tmp/out.txt").mtime.class.name
+		`, "Time"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileClassMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`File.read`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`File.read(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`File.read("/tmp/no_such_file.txt")`, "InternalError: open /tmp/no_such_file.txt: no such file or directory", 1},
+		{`File.readlines("a", "b")`, "ArgumentError: Expect 1 argument. got: 2", 1},
+		{`File.write("/tmp/out.txt")`, "ArgumentError: Expect 2 arguments. got: 1", 1},
+		{`File.write("/tmp/out.txt", 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`File.dirname(nil)`, "TypeError: Expect argument to be String. got: Null", 1},
+		{`File.expand_path`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`File.expand_path("a", 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`File.mtime("/tmp/no_such_file.txt")`, "InternalError: stat /tmp/no_such_file.txt: no such file or directory", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 //@TODO add test for chmod form a847c8b41f29657b380c1731ec36a660dbf49bc4
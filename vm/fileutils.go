@@ -0,0 +1,345 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// FileUtils provides shell-like commands for copying, moving and removing files, which are handy for build
+// scripts. Each method accepts a path or an Array of paths and returns the given argument.
+// Use `require "fileutils"` to load it.
+//
+// ```ruby
+// require "fileutils"
+//
+// FileUtils.mkdir_p("build/assets")
+// FileUtils.cp(["app.css", "app.js"], "build/assets")
+// FileUtils.touch("build/.keep")
+// FileUtils.rm_rf("build")
+// ```
+
+// Class methods --------------------------------------------------------
+func builtinFileUtilsClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Copies the files to the destination. If the destination is a directory, the files are copied into it,
+			// and it must be a directory when more than one file is given.
+			//
+			// ```ruby
+			// FileUtils.cp("config.yml", "config.yml.bak")
+			// FileUtils.cp(["a.gb", "b.gb"], "lib")
+			// ```
+			//
+			// @param src [String/Array], dest [String]
+			// @return [Object]
+			Name: "cp",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.copyFiles(args, copyFile)
+				}
+			},
+		},
+		{
+			// Copies the files and directories to the destination recursively.
+			//
+			// ```ruby
+			// FileUtils.cp_r("assets", "build/assets")
+			// ```
+			//
+			// @param src [String/Array], dest [String]
+			// @return [Object]
+			Name: "cp_r",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.copyFiles(args, copyTree)
+				}
+			},
+		},
+		{
+			// Creates the directories and their missing parents, existing directories are ignored.
+			//
+			// ```ruby
+			// FileUtils.mkdir_p("build/assets/images")
+			// ```
+			//
+			// @param list [String/Array]
+			// @return [Object]
+			Name: "mkdir_p",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.eachFileUtilsPath(args, func(path string) error {
+						return os.MkdirAll(path, 0755)
+					})
+				}
+			},
+		},
+		{
+			// Moves the files and directories to the destination. If the destination is a directory, they're moved
+			// into it, and it must be a directory when more than one path is given.
+			//
+			// ```ruby
+			// FileUtils.mv("build/goby", "bin/goby")
+			// FileUtils.mv(["a.log", "b.log"], "logs")
+			// ```
+			//
+			// @param src [String/Array], dest [String]
+			// @return [Object]
+			Name: "mv",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.copyFiles(args, moveFile)
+				}
+			},
+		},
+		{
+			// Removes the files.
+			//
+			// ```ruby
+			// FileUtils.rm(["a.log", "b.log"])
+			// ```
+			//
+			// @param list [String/Array]
+			// @return [Object]
+			Name: "rm",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.eachFileUtilsPath(args, os.Remove)
+				}
+			},
+		},
+		{
+			// Removes the files, missing files are ignored.
+			//
+			// ```ruby
+			// FileUtils.rm_f("goby.pid")
+			// ```
+			//
+			// @param list [String/Array]
+			// @return [Object]
+			Name: "rm_f",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.eachFileUtilsPath(args, func(path string) error {
+						if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+							return err
+						}
+
+						return nil
+					})
+				}
+			},
+		},
+		{
+			// Removes the files and directories recursively, missing paths are ignored.
+			//
+			// ```ruby
+			// FileUtils.rm_rf("build")
+			// ```
+			//
+			// @param list [String/Array]
+			// @return [Object]
+			Name: "rm_rf",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.eachFileUtilsPath(args, os.RemoveAll)
+				}
+			},
+		},
+		{
+			// Updates the modification times of the files to now, missing files are created.
+			//
+			// ```ruby
+			// FileUtils.touch("build/.keep")
+			// ```
+			//
+			// @param list [String/Array]
+			// @return [Object]
+			Name: "touch",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.eachFileUtilsPath(args, touchFile)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initFileUtilsModule(vm *VM) {
+	fu := vm.initializeClass("FileUtils", true)
+	fu.setBuiltinMethods(builtinFileUtilsClassMethods(), true)
+	vm.objectClass.setClassConstant(fu)
+}
+
+// Other helper functions -----------------------------------------------
+
+// fileUtilsPaths converts a path or an Array of paths to a slice of strings
+func (t *thread) fileUtilsPaths(arg Object) ([]string, *Error) {
+	switch list := arg.(type) {
+	case *StringObject:
+		return []string{list.value}, nil
+	case *ArrayObject:
+		paths := make([]string, len(list.Elements))
+
+		for i, el := range list.Elements {
+			s, ok := el.(*StringObject)
+			if !ok {
+				return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, el.Class().Name)
+			}
+
+			paths[i] = s.value
+		}
+
+		return paths, nil
+	default:
+		return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "String or Array", arg.Class().Name)
+	}
+}
+
+// eachFileUtilsPath calls fn with each of the paths in the only argument and returns the argument
+func (t *thread) eachFileUtilsPath(args []Object, fn func(string) error) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	paths, e := t.fileUtilsPaths(args[0])
+	if e != nil {
+		return e
+	}
+
+	for _, path := range paths {
+		if err := fn(path); err != nil {
+			return t.initErrorObject(errors.InternalError, "%s", err.Error())
+		}
+	}
+
+	return args[0]
+}
+
+// copyFiles calls fn with each of the sources and its destination, which is inside dest when dest is a directory.
+// It returns the sources.
+func (t *thread) copyFiles(args []Object, fn func(src, dest string) error) Object {
+	if len(args) != 2 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
+	}
+
+	srcs, e := t.fileUtilsPaths(args[0])
+	if e != nil {
+		return e
+	}
+
+	dest, ok := args[1].(*StringObject)
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+	}
+
+	info, err := os.Stat(dest.value)
+	isDir := err == nil && info.IsDir()
+
+	if len(srcs) > 1 && !isDir {
+		return t.initErrorObject(errors.ArgumentError, "Expect destination to be a directory: %s", dest.value)
+	}
+
+	for _, src := range srcs {
+		target := dest.value
+		if isDir {
+			target = filepath.Join(dest.value, filepath.Base(src))
+		}
+
+		if err := fn(src, target); err != nil {
+			return t.initErrorObject(errors.InternalError, "%s", err.Error())
+		}
+	}
+
+	return args[0]
+}
+
+// copyFile copies the content and the permission of the regular file src to dest
+func copyFile(src, dest string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+
+	if info.IsDir() {
+		return fmt.Errorf("%s is a directory", src)
+	}
+
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dest, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// copyTree copies src to dest, copying the contents of directories recursively
+func copyTree(src, dest string) error {
+	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, _ := filepath.Rel(src, path)
+		target := filepath.Join(dest, rel)
+
+		if info.IsDir() {
+			return os.MkdirAll(target, info.Mode().Perm())
+		}
+
+		return copyFile(path, target)
+	})
+}
+
+// moveFile renames src to dest, or copies and removes src when they're on different devices
+func moveFile(src, dest string) error {
+	err := os.Rename(src, dest)
+
+	if linkErr, ok := err.(*os.LinkError); ok && linkErr.Err == syscall.EXDEV {
+		if err := copyTree(src, dest); err != nil {
+			return err
+		}
+
+		return os.RemoveAll(src)
+	}
+
+	return err
+}
+
+// touchFile updates the modification time of the file, or creates it if it doesn't exist
+func touchFile(path string) error {
+	now := time.Now()
+
+	err := os.Chtimes(path, now, now)
+	if os.IsNotExist(err) {
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+
+		return f.Close()
+	}
+
+	return err
+}
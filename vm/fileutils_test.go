@@ -0,0 +1,127 @@
+package vm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func TestFileUtilsMethods(t *testing.T) {
+	tmp, err := ioutil.TempDir("", "goby_fileutils_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmp)
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "fileutils"
+		FileUtils.mkdir_p("%[1]s/build/assets")
+		Dir.exist?("%[1]s/build/assets")
+		`, true},
+		{`
+		require "fileutils"
+		FileUtils.mkdir_p(["%[1]s/build/assets", "%[1]s/build/lib"]).length
+		`, 2},
+		{`
+		require "fileutils"
+		FileUtils.touch("%[1]s/app.js")
+		File.exist?("%[1]s/app.js")
+		`, true},
+		{`
+		require "fileutils"
+		File.write("%[1]s/app.css", "body {}")
+		FileUtils.cp("%[1]s/app.css", "%[1]s/build/assets")
+		File.read("%[1]s/build/assets/app.css")
+		`, "body {}"},
+		{`
+		require "fileutils"
+		FileUtils.cp(["%[1]s/app.css", "%[1]s/app.js"], "%[1]s/build/lib")
+		Dir.entries("%[1]s/build/lib").length
+		`, 4},
+		{`
+		require "fileutils"
+		FileUtils.cp("%[1]s/app.css", "%[1]s/app.css.bak")
+		File.read("%[1]s/app.css.bak")
+		`, "body {}"},
+		{`
+		require "fileutils"
+		FileUtils.cp_r("%[1]s/build", "%[1]s/dist")
+		File.read("%[1]s/dist/assets/app.css")
+		`, "body {}"},
+		{`
+		require "fileutils"
+		FileUtils.mv("%[1]s/app.css.bak", "%[1]s/app.css.old")
+		File.exist?("%[1]s/app.css.bak") || !File.exist?("%[1]s/app.css.old")
+		`, false},
+		{`
+		require "fileutils"
+		FileUtils.mv(["%[1]s/app.css.old", "%[1]s/app.js"], "%[1]s/dist")
+		Dir.entries("%[1]s/dist").length
+		`, 6},
+		{`
+		require "fileutils"
+		FileUtils.rm("%[1]s/dist/app.js")
+		File.exist?("%[1]s/dist/app.js")
+		`, false},
+		{`
+		require "fileutils"
+		FileUtils.rm_f(["%[1]s/dist/app.css.old", "%[1]s/dist/no_such_file"])
+		File.exist?("%[1]s/dist/app.css.old")
+		`, false},
+		{`
+		require "fileutils"
+		FileUtils.rm_rf(["%[1]s/build", "%[1]s/dist", "%[1]s/no_such_dir"])
+		Dir.exist?("%[1]s/build") || Dir.exist?("%[1]s/dist")
+		`, false},
+		{`
+		require "fileutils"
+		FileUtils.rm_rf("%[1]s/build")
+		`, fmt.Sprintf("%s/build", tmp)},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, fmt.Sprintf(tt.input, tmp), getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFileUtilsMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "fileutils"
+		FileUtils.mkdir_p`, "ArgumentError: Expect 1 argument. got: 0", 2},
+		{`require "fileutils"
+		FileUtils.touch(1)`, "TypeError: Expect argument to be String or Array. got: Integer", 2},
+		{`require "fileutils"
+		FileUtils.rm_rf(["a", 1])`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "fileutils"
+		FileUtils.cp("a")`, "ArgumentError: Expect 2 arguments. got: 1", 2},
+		{`require "fileutils"
+		FileUtils.cp("a", 1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "fileutils"
+		FileUtils.cp(["a", "b"], "../test_fixtures/no_such_dir")`, "ArgumentError: Expect destination to be a directory: ../test_fixtures/no_such_dir", 2},
+		{`require "fileutils"
+		FileUtils.cp("../test_fixtures/no_such_file", "a")`, "InternalError: stat ../test_fixtures/no_such_file: no such file or directory", 2},
+		{`require "fileutils"
+		FileUtils.cp("../test_fixtures", "a")`, "InternalError: ../test_fixtures is a directory", 2},
+		{`require "fileutils"
+		FileUtils.mv("../test_fixtures/no_such_file", "a")`, "InternalError: rename ../test_fixtures/no_such_file a: no such file or directory", 2},
+		{`require "fileutils"
+		FileUtils.rm("../test_fixtures/no_such_file")`, "InternalError: remove ../test_fixtures/no_such_file: no such file or directory", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,890 @@
+package vm
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// FloatObject represents double-precision floating point numbers.
+// Float literals are written with a decimal point or an exponent, and Floats can be used together with
+// Integers in arithmetic and comparisons, the result is a Float.
+//
+// ```ruby
+// 1.5 + 1          # => 2.5
+// 3.14159.round(2) # => 3.14
+// 1e3              # => 1000.0
+// 1 / 2.0          # => 0.5
+// 1.0 == 1         # => true
+// ```
+//
+// Division by zero doesn't raise an error, it results in `Float::INFINITY` or `Float::NAN` instead.
+//
+// **Note:**
+//
+// - `1.` isn't a valid Float literal, use `1.0` instead.
+// - `Float.new` is not supported.
+type FloatObject struct {
+	*baseObj
+	value float64
+}
+
+// Class methods --------------------------------------------------------
+func builtinFloatClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinFloatInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the sum of self and a number.
+			//
+			// ```ruby
+			// 1.5 + 2   # => 3.5
+			// 0.1 + 0.2 # => 0.30000000000000004
+			// ```
+			//
+			// @return [Float]
+			Name: "+",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatOperation(t, receiver, args, "+")
+				}
+			},
+		},
+		{
+			// Returns the modulo of self divided by a number, the result has the same sign as the divisor.
+			//
+			// ```ruby
+			// 7.5 % 2  # => 1.5
+			// -7.5 % 2 # => 0.5
+			// ```
+			//
+			// @return [Float]
+			Name: "%",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatOperation(t, receiver, args, "%")
+				}
+			},
+		},
+		{
+			// Returns the subtraction of a number from self.
+			//
+			// ```ruby
+			// 2.5 - 1 # => 1.5
+			// ```
+			//
+			// @return [Float]
+			Name: "-",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatOperation(t, receiver, args, "-")
+				}
+			},
+		},
+		{
+			// Returns the negation of self, which is what unary minus calls.
+			//
+			// ```ruby
+			// a = 2.5
+			// -a # => -2.5
+			// ```
+			//
+			// @return [Float]
+			Name: "-@",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initFloatObject(-receiver.(*FloatObject).value)
+				}
+			},
+		},
+		{
+			// Returns self multiplied by a number.
+			//
+			// ```ruby
+			// 1.5 * 2 # => 3.0
+			// ```
+			//
+			// @return [Float]
+			Name: "*",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatOperation(t, receiver, args, "*")
+				}
+			},
+		},
+		{
+			// Returns self raised to the power of a number.
+			//
+			// ```ruby
+			// 1.5 ** 2   # => 2.25
+			// 4.0 ** 0.5 # => 2.0
+			// ```
+			//
+			// @return [Float]
+			Name: "**",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatOperation(t, receiver, args, "**")
+				}
+			},
+		},
+		{
+			// Returns self divided by a number. Dividing by zero results in Infinity or NaN.
+			//
+			// ```ruby
+			// 7.5 / 2  # => 3.75
+			// 1.0 / 0  # => Infinity
+			// -1.0 / 0 # => -Infinity
+			// ```
+			//
+			// @return [Float]
+			Name: "/",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatOperation(t, receiver, args, "/")
+				}
+			},
+		},
+		{
+			// Returns if self is larger than a number.
+			//
+			// ```ruby
+			// 1.5 > 1 # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: ">",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatComparison(t, receiver, args, func(c int) bool { return c > 0 })
+				}
+			},
+		},
+		{
+			// Returns if self is larger than or equal to a number.
+			//
+			// ```ruby
+			// 1.0 >= 1 # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: ">=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatComparison(t, receiver, args, func(c int) bool { return c >= 0 })
+				}
+			},
+		},
+		{
+			// Returns if self is smaller than a number.
+			//
+			// ```ruby
+			// 1.5 < 2 # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatComparison(t, receiver, args, func(c int) bool { return c < 0 })
+				}
+			},
+		},
+		{
+			// Returns if self is smaller than or equal to a number.
+			//
+			// ```ruby
+			// 1.0 <= 1 # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "<=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatComparison(t, receiver, args, func(c int) bool { return c <= 0 })
+				}
+			},
+		},
+		{
+			// Returns 1 if self is larger than the number, -1 if smaller. Otherwise 0.
+			// Returns nil if either of them is NaN.
+			//
+			// ```ruby
+			// 1.5 <=> 2          # => -1
+			// 1.0 <=> 1          # => 0
+			// Float::NAN <=> 1.0 # => nil
+			// ```
+			//
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.FloatClass, args[0].Class().Name)
+					}
+
+					c, ok := compareNumbers(receiver, args[0])
+
+					if !ok {
+						return NULL
+					}
+
+					return t.vm.initIntegerObject(c)
+				}
+			},
+		},
+		{
+			// Returns if self is equal to a number. NaN isn't equal to anything, including itself.
+			//
+			// ```ruby
+			// 1.0 == 1                 # => true
+			// 1.5 == "1.5"             # => false
+			// Float::NAN == Float::NAN # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c, ok := compareNumbers(receiver, args[0])
+
+					if ok && c == 0 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns if self is not equal to the argument.
+			//
+			// ```ruby
+			// 1.5 != 1 # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "!=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c, ok := compareNumbers(receiver, args[0])
+
+					if ok && c == 0 {
+						return FALSE
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns if self is a Float with the same value as the argument. Unlike `==`, an Integer isn't
+			// `eql?` to a Float, so they're different keys of Hash.
+			//
+			// ```ruby
+			// 1.0.eql?(1.0) # => true
+			// 1.0.eql?(1)   # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "eql?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					f, ok := args[0].(*FloatObject)
+					return toBooleanObject(ok && f.value == receiver.(*FloatObject).value)
+				}
+			},
+		},
+		{
+			// Returns the absolute value of self.
+			//
+			// ```ruby
+			// (-1.5).abs # => 1.5
+			// ```
+			//
+			// @return [Float]
+			Name: "abs",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initFloatObject(math.Abs(receiver.(*FloatObject).value))
+				}
+			},
+		},
+		{
+			// Returns the smallest number greater than or equal to self, with the given number of decimal digits.
+			// The digits default to 0, in which case an Integer is returned. Negative digits round to tens,
+			// hundreds and so on, and also return an Integer.
+			//
+			// ```ruby
+			// 1.2.ceil         # => 2
+			// (-1.2).ceil      # => -1
+			// 1.234.ceil(2)    # => 1.24
+			// 1234.5.ceil(-2)  # => 1300
+			// ```
+			//
+			// @return [Integer, Float]
+			Name: "ceil",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatRounding(t, receiver, args, math.Ceil)
+				}
+			},
+		},
+		{
+			// Returns an array of the floored quotient, as an Integer, and the modulo of self divided by a number.
+			//
+			// ```ruby
+			// 7.5.divmod(2)    # => [3, 1.5]
+			// (-7.5).divmod(2) # => [-4, 0.5]
+			// ```
+			//
+			// @return [Array]
+			Name: "divmod",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					left := receiver.(*FloatObject).value
+					right, ok := toFloat64(args[0])
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.FloatClass, args[0].Class().Name)
+					}
+
+					if right == 0 {
+						return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+					}
+
+					q := t.initIntegerFromFloat(math.Floor(left / right))
+
+					if err, ok := q.(*Error); ok {
+						return err
+					}
+
+					return t.vm.initArrayObject([]Object{q, t.vm.initFloatObject(floatModulo(left, right))})
+				}
+			},
+		},
+		{
+			// Returns true if self is neither infinite nor NaN.
+			//
+			// ```ruby
+			// 1.5.finite?             # => true
+			// Float::INFINITY.finite? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "finite?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f := receiver.(*FloatObject).value
+
+					if math.IsInf(f, 0) || math.IsNaN(f) {
+						return FALSE
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns the largest number less than or equal to self, with the given number of decimal digits.
+			// The digits default to 0, in which case an Integer is returned. Negative digits round to tens,
+			// hundreds and so on, and also return an Integer.
+			//
+			// ```ruby
+			// 1.8.floor        # => 1
+			// (-1.2).floor     # => -2
+			// 1.238.floor(2)   # => 1.23
+			// 1234.5.floor(-2) # => 1200
+			// ```
+			//
+			// @return [Integer, Float]
+			Name: "floor",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatRounding(t, receiver, args, math.Floor)
+				}
+			},
+		},
+		{
+			// Returns 1 if self is positive infinity, -1 if it's negative infinity. Otherwise nil.
+			//
+			// ```ruby
+			// 1.5.infinite?                # => nil
+			// Float::INFINITY.infinite?    # => 1
+			// (-Float::INFINITY).infinite? # => -1
+			// ```
+			//
+			// @return [Integer]
+			Name: "infinite?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f := receiver.(*FloatObject).value
+
+					switch {
+					case math.IsInf(f, 1):
+						return t.vm.initIntegerObject(1)
+					case math.IsInf(f, -1):
+						return t.vm.initIntegerObject(-1)
+					default:
+						return NULL
+					}
+				}
+			},
+		},
+		{
+			// Returns true if self is NaN (not a number).
+			//
+			// ```ruby
+			// 1.5.nan?       # => false
+			// (0.0 / 0).nan? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "nan?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if math.IsNaN(receiver.(*FloatObject).value) {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Rounds self to the given number of decimal digits, halves are rounded away from zero.
+			// The digits default to 0, in which case an Integer is returned. Negative digits round to tens,
+			// hundreds and so on, and also return an Integer.
+			//
+			// ```ruby
+			// 2.5.round        # => 3
+			// (-2.5).round     # => -3
+			// 3.14159.round(2) # => 3.14
+			// 1234.5.round(-2) # => 1200
+			// ```
+			//
+			// @return [Integer, Float]
+			Name: "round",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return floatRounding(t, receiver, args, math.Round)
+				}
+			},
+		},
+		{
+			// Returns self.
+			//
+			// ```ruby
+			// 1.5.to_f # => 1.5
+			// ```
+			//
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns self truncated to an Integer.
+			//
+			// ```ruby
+			// 1.9.to_i    # => 1
+			// (-1.9).to_i # => -1
+			// ```
+			//
+			// @return [Integer]
+			Name: "to_i",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.initIntegerFromFloat(math.Trunc(receiver.(*FloatObject).value))
+				}
+			},
+		},
+		{
+			// Returns the exact value of self as a Rational.
+			//
+			// ```ruby
+			// 0.75.to_r # => 3/4
+			// 0.1.to_r  # => 3602879701896397/36028797018963968
+			// ```
+			//
+			// @return [Rational]
+			Name: "to_r",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f := receiver.(*FloatObject).value
+					r := new(big.Rat).SetFloat64(f)
+
+					if r == nil {
+						return t.initErrorObject(errors.FloatDomainError, formatFloat(f))
+					}
+
+					return t.vm.initRationalObject(r)
+				}
+			},
+		},
+		{
+			// Returns a String representation of self. Very large and very small numbers are written with
+			// an exponent.
+			//
+			// ```ruby
+			// 1.5.to_s             # => "1.5"
+			// 1e20.to_s            # => "1.0e+20"
+			// Float::INFINITY.to_s # => "Infinity"
+			// Float::NAN.to_s      # => "NaN"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+		{
+			// Returns true if self is zero.
+			//
+			// ```ruby
+			// 0.0.zero? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "zero?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if receiver.(*FloatObject).value == 0 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initFloatObject(value float64) *FloatObject {
+	return &FloatObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.FloatClass), frozen: true},
+		value:   value,
+	}
+}
+
+func (vm *VM) initFloatClass() *RClass {
+	fc := vm.initializeClass(classes.FloatClass, false)
+	fc.setBuiltinMethods(builtinFloatInstanceMethods(), false)
+	fc.setBuiltinMethods(builtinFloatClassMethods(), true)
+	fc.include(vm.topLevelClass(classes.ComparableModule))
+
+	constants := map[string]float64{
+		"INFINITY": math.Inf(1),
+		"NAN":      math.NaN(),
+		"EPSILON":  math.Nextafter(1, 2) - 1,
+		"MAX":      math.MaxFloat64,
+		"MIN":      2.2250738585072014e-308,
+	}
+
+	for name, value := range constants {
+		fc.constants[name] = &Pointer{Target: &FloatObject{baseObj: &baseObj{class: fc, frozen: true}, value: value}}
+	}
+
+	return fc
+}
+
+// initIntegerFromFloat converts an integral float to an Integer, or a BigInt if it's too large.
+// It returns a FloatDomainError if f is infinite or NaN.
+func (t *thread) initIntegerFromFloat(f float64) Object {
+	switch {
+	case math.IsNaN(f) || math.IsInf(f, 0):
+		return t.initErrorObject(errors.FloatDomainError, formatFloat(f))
+	case f >= math.MinInt64 && f < math.MaxInt64:
+		return t.vm.initIntegerObject(int(f))
+	default:
+		n, _ := big.NewFloat(f).Int(nil)
+		return t.vm.initIntegerFromBigInt(n)
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (f *FloatObject) Value() interface{} {
+	return f.value
+}
+
+// Returns the string representation of the float
+func (f *FloatObject) toString() string {
+	return formatFloat(f.value)
+}
+
+// Returns the float as a JSON number, infinite and NaN values can't be represented in JSON so they become null
+func (f *FloatObject) toJSON() string {
+	if math.IsNaN(f.value) || math.IsInf(f.value, 0) {
+		return "null"
+	}
+
+	return f.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// formatFloat formats f like Ruby does: "1.0", "1.0e+20", "Infinity" or "NaN"
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+
+	if abs := math.Abs(f); abs >= 1e16 || (abs < 1e-4 && abs != 0) {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		mantissa, exponent := s[:strings.Index(s, "e")], s[strings.Index(s, "e"):]
+
+		if !strings.Contains(mantissa, ".") {
+			mantissa += ".0"
+		}
+
+		return mantissa + exponent
+	}
+
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+
+	return s
+}
+
+// toFloat64 returns the value of an Integer, a BigInt, a Float or a Rational as a float64, ok is false for
+// other objects
+func toFloat64(obj Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *IntegerObject:
+		return float64(obj.value), true
+	case *BigIntObject:
+		f, _ := new(big.Float).SetInt(obj.value).Float64()
+		return f, true
+	case *FloatObject:
+		return obj.value, true
+	case *RationalObject:
+		f, _ := obj.value.Float64()
+		return f, true
+	default:
+		return 0, false
+	}
+}
+
+// isNumber returns true if obj is an Integer, a BigInt, a Float or a Rational
+func isNumber(obj Object) bool {
+	_, ok := toFloat64(obj)
+	return ok
+}
+
+// compareNumbers compares two numbers and returns -1, 0 or 1. Numbers are compared as floats if either of
+// them is a Float, or exactly otherwise. ok is false if either of them isn't a number or is NaN.
+func compareNumbers(left, right Object) (int, bool) {
+	_, leftIsFloat := left.(*FloatObject)
+	_, rightIsFloat := right.(*FloatObject)
+	_, leftIsRational := left.(*RationalObject)
+	_, rightIsRational := right.(*RationalObject)
+
+	switch {
+	case leftIsFloat || rightIsFloat:
+	case leftIsRational || rightIsRational:
+		l, ok := toBigRat(left)
+
+		if !ok {
+			return 0, false
+		}
+
+		r, ok := toBigRat(right)
+
+		if !ok {
+			return 0, false
+		}
+
+		return l.Cmp(r), true
+	default:
+		return compareIntegers(left, right)
+	}
+
+	l, ok := toFloat64(left)
+
+	if !ok || math.IsNaN(l) {
+		return 0, false
+	}
+
+	r, ok := toFloat64(right)
+
+	if !ok || math.IsNaN(r) {
+		return 0, false
+	}
+
+	switch {
+	case l < r:
+		return -1, true
+	case l > r:
+		return 1, true
+	default:
+		return 0, true
+	}
+}
+
+// floatOperation applies the arithmetic operator to the receiver and the argument as floats
+func floatOperation(t *thread, receiver Object, args []Object, operator string) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	left, _ := toFloat64(receiver)
+	right, ok := toFloat64(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.FloatClass, args[0].Class().Name)
+	}
+
+	var result float64
+
+	switch operator {
+	case "+":
+		result = left + right
+	case "-":
+		result = left - right
+	case "*":
+		result = left * right
+	case "/":
+		result = left / right
+	case "%":
+		result = floatModulo(left, right)
+	case "**":
+		result = math.Pow(left, right)
+	}
+
+	return t.vm.initFloatObject(result)
+}
+
+// floatComparison compares the receiver with the argument and returns the result of test on it.
+// Comparisons with NaN are always false.
+func floatComparison(t *thread, receiver Object, args []Object, test func(int) bool) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	if !isNumber(args[0]) {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.FloatClass, args[0].Class().Name)
+	}
+
+	c, ok := compareNumbers(receiver, args[0])
+
+	if ok && test(c) {
+		return TRUE
+	}
+
+	return FALSE
+}
+
+// floatRounding rounds the receiver with round, which is math.Round, math.Floor or math.Ceil, to the number
+// of decimal digits given by the optional argument. The result is an Integer unless the digits are positive.
+func floatRounding(t *thread, receiver Object, args []Object, round func(float64) float64) Object {
+	digits := 0
+
+	switch len(args) {
+	case 0:
+	case 1:
+		d, ok := args[0].(*IntegerObject)
+
+		if !ok {
+			return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		digits = d.value
+	default:
+		return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+
+	f := receiver.(*FloatObject).value
+
+	if digits > 0 {
+		return t.vm.initFloatObject(roundFloat(f, digits, round))
+	}
+
+	return t.initIntegerFromFloat(roundFloat(f, digits, round))
+}
+
+// roundFloat rounds f to the given number of decimal digits with round
+func roundFloat(f float64, digits int, round func(float64) float64) float64 {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return f
+	}
+
+	if digits <= 0 {
+		p := math.Pow10(-digits)
+		return round(f/p) * p
+	}
+
+	// f is already as precise as required, rounding it again could only introduce errors like
+	// 2.3 * 100 = 229.99999999999997
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+
+	if i := strings.Index(s, "."); i < 0 || len(s)-i-1 <= digits {
+		return f
+	}
+
+	p := math.Pow10(digits)
+
+	if math.IsInf(f*p, 0) {
+		return f
+	}
+
+	return round(f*p) / p
+}
+
+// floatModulo returns the modulo of left divided by right, which has the same sign as right
+func floatModulo(left, right float64) float64 {
+	r := math.Mod(left, right)
+
+	if r != 0 && (r < 0) != (right < 0) {
+		r += right
+	}
+
+	return r
+}
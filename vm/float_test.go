@@ -0,0 +1,264 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestFloatArithmeticOperation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.5 + 2.25`, 3.75},
+		{`1.5 + 1`, 2.5},
+		{`1 + 1.5`, 2.5},
+		{`5.5 - 2`, 3.5},
+		{`2 - 0.5`, 1.5},
+		{`1.5 * 2`, 3.0},
+		{`3 * 0.5`, 1.5},
+		{`7.5 / 2`, 3.75},
+		{`1 / 2.0`, 0.5},
+		{`7.5 % 2`, 1.5},
+		{`-7.5 % 2`, 0.5},
+		{`7.5 % -2`, -0.5},
+		{`7 % 2.5`, 2.0},
+		{`1.5 ** 2`, 2.25},
+		{`4 ** 0.5`, 2.0},
+		{`1e3`, 1000.0},
+		{`2.5e-1`, 0.25},
+		{`(1.0 / 0).to_s`, "Infinity"},
+		{`(-1.0 / 0).to_s`, "-Infinity"},
+		{`(0.0 / 0).to_s`, "NaN"},
+		{`(18446744073709551616 + 0.5).to_s`, "1.8446744073709552e+19"},
+		{`(0.5 + 18446744073709551616).to_s`, "1.8446744073709552e+19"},
+		{`1.5.class.name`, "Float"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatArithmeticOperationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.5 + "p"`, "TypeError: Expect argument to be Float. got: String", 1},
+		{`1.5 / nil`, "TypeError: Expect argument to be Float. got: Null", 1},
+		{`Float.new`, "UnsupportedMethodError: Unsupported Method #new for Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.5 > 1`, true},
+		{`1.5 < 1`, false},
+		{`1.0 >= 1`, true},
+		{`0.5 <= 0.25`, false},
+		{`1 < 1.5`, true},
+		{`2 >= 2.0`, true},
+		{`1.0 == 1`, true},
+		{`1 == 1.0`, true},
+		{`1.5 == "1.5"`, false},
+		{`1.5 != 1`, true},
+		{`1.5 <=> 2`, -1},
+		{`1.0 <=> 1`, 0},
+		{`2 <=> 1.5`, 1},
+		{`Float::NAN == Float::NAN`, false},
+		{`Float::NAN != Float::NAN`, true},
+		{`Float::NAN > 1`, false},
+		{`Float::NAN < 1`, false},
+		{`1 > Float::NAN`, false},
+		{`Float::NAN <=> 1`, nil},
+		{`Float::INFINITY > 18446744073709551616`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatComparisonFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.5 > "1"`, "TypeError: Expect argument to be Float. got: String", 1},
+		{`1.5 <=> nil`, "TypeError: Expect argument to be Float. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatRoundingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`2.5.round`, 3},
+		{`(-2.5).round`, -3},
+		{`2.4.round`, 2},
+		{`3.14159.round(2)`, 3.14},
+		{`1.005.round(2)`, 1.0},
+		{`1.5.round(3)`, 1.5},
+		{`1234.5.round(-2)`, 1200},
+		{`1250.0.round(-2)`, 1300},
+		{`1.8.floor`, 1},
+		{`(-1.2).floor`, -2},
+		{`1.238.floor(2)`, 1.23},
+		{`2.3.floor(2)`, 2.3},
+		{`(-1.238).floor(2)`, -1.24},
+		{`1299.0.floor(-2)`, 1200},
+		{`1.2.ceil`, 2},
+		{`(-1.2).ceil`, -1},
+		{`1.234.ceil(2)`, 1.24},
+		{`1201.0.ceil(-2)`, 1300},
+		{`1.9.to_i`, 1},
+		{`(-1.9).to_i`, -1},
+		{`1e30.to_i.to_s`, "1000000000000000019884624838656"},
+		{`1e20.round.class.name`, "BigInt"},
+		{`Float::INFINITY.round(2).infinite?`, 1},
+		{`10.to_f`, 10.0},
+		{`1.5.to_f`, 1.5},
+		{`(-1.5).abs`, 1.5},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatRoundingMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.5.round("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1.5.floor(1, 2)`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`Float::NAN.round`, "FloatDomainError: NaN", 1},
+		{`Float::INFINITY.ceil`, "FloatDomainError: Infinity", 1},
+		{`(-Float::INFINITY).to_i`, "FloatDomainError: -Infinity", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatDivmodMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`7.5.divmod(2).to_s`, "[3, 1.5]"},
+		{`(-7.5).divmod(2).to_s`, "[-4, 0.5]"},
+		{`7.5.divmod(-2).to_s`, "[-4, -0.5]"},
+		{`1.0.divmod(0.25).to_s`, "[4, 0.0]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatDivmodMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`7.5.divmod(0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`7.5.divmod("2")`, "TypeError: Expect argument to be Float. got: String", 1},
+		{`Float::NAN.divmod(2)`, "FloatDomainError: NaN", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatSpecialValues(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.5.finite?`, true},
+		{`Float::INFINITY.finite?`, false},
+		{`Float::NAN.finite?`, false},
+		{`1.5.nan?`, false},
+		{`(0.0 / 0).nan?`, true},
+		{`1.5.infinite?`, nil},
+		{`Float::NAN.infinite?`, nil},
+		{`(1 / 0.0).infinite?`, 1},
+		{`(-Float::INFINITY).infinite?`, -1},
+		{`0.0.zero?`, true},
+		{`0.1.zero?`, false},
+		{`Float::EPSILON.to_s`, "2.220446049250313e-16"},
+		{`Float::MAX.to_s`, "1.7976931348623157e+308"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatFormatting(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`1.5.to_s`, "1.5"},
+		{`100.0.to_s`, "100.0"},
+		{`1e3.to_s`, "1000.0"},
+		{`0.1.to_s`, "0.1"},
+		{`1e16.to_s`, "1.0e+16"},
+		{`1.5e20.to_s`, "1.5e+20"},
+		{`0.0001.to_s`, "0.0001"},
+		{`0.00001.to_s`, "1.0e-05"},
+		{`[1.5, 2.0].to_s`, "[1.5, 2.0]"},
+		{`{ a: 1.5 }.to_json`, `{"a":1.5}`},
+		{`{ a: Float::NAN }.to_json`, `{"a":null}`},
+		{`"#{2.5}"`, "2.5"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,129 @@
+package vm
+
+import (
+	"runtime"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// GC is a module for controlling Go's garbage collector, which manages the memory of Goby objects, and reading
+// the runtime's memory statistics.
+//
+// ```ruby
+// GC.start
+// GC.count              # => 3
+// GC.stat["heap_alloc"] # => 1245184
+// ```
+
+// Class methods --------------------------------------------------------
+func builtinGCClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the number of garbage collections that have run.
+			//
+			// ```ruby
+			// GC.count # => 3
+			// ```
+			//
+			// @return [Integer]
+			Name: "count",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					var stats runtime.MemStats
+					runtime.ReadMemStats(&stats)
+
+					return t.vm.initIntegerObject(int(stats.NumGC))
+				}
+			},
+		},
+		{
+			// Runs a garbage collection and waits until it's done. Returns nil.
+			//
+			// ```ruby
+			// GC.start # => nil
+			// ```
+			//
+			// @return [Null]
+			Name: "start",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					runtime.GC()
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Returns a hash of the runtime's memory statistics in bytes, and the numbers of objects and garbage
+			// collections:
+			//
+			// - `heap_alloc`: bytes of the allocated heap objects
+			// - `heap_sys`: bytes of the heap memory obtained from the OS
+			// - `heap_objects`: the number of allocated heap objects
+			// - `total_alloc`: cumulative bytes allocated for heap objects
+			// - `mallocs` and `frees`: cumulative counts of allocated and freed heap objects
+			// - `sys`: total bytes of memory obtained from the OS
+			// - `num_gc`: the number of garbage collections
+			// - `pause_total_ns`: cumulative nanoseconds the program is paused by garbage collections
+			// - `next_gc`: the heap size the next garbage collection is triggered at
+			//
+			// ```ruby
+			// GC.stat["heap_alloc"] # => 1245184
+			// GC.stat["num_gc"]     # => 3
+			// ```
+			//
+			// @return [Hash]
+			Name: "stat",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					var stats runtime.MemStats
+					runtime.ReadMemStats(&stats)
+
+					values := map[string]uint64{
+						"heap_alloc":     stats.HeapAlloc,
+						"heap_sys":       stats.HeapSys,
+						"heap_objects":   stats.HeapObjects,
+						"total_alloc":    stats.TotalAlloc,
+						"mallocs":        stats.Mallocs,
+						"frees":          stats.Frees,
+						"sys":            stats.Sys,
+						"num_gc":         uint64(stats.NumGC),
+						"pause_total_ns": stats.PauseTotalNs,
+						"next_gc":        stats.NextGC,
+					}
+
+					pairs := map[string]Object{}
+
+					for name, value := range values {
+						pairs[name] = t.vm.initIntegerObject(int(value))
+					}
+
+					return t.vm.initHashObject(pairs)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initGCModule() *RClass {
+	m := vm.initializeClass(classes.GCModule, true)
+	m.setBuiltinMethods(builtinGCClassMethods(), true)
+	return m
+}
@@ -0,0 +1,94 @@
+package vm
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Functions for initialization -----------------------------------------
+
+// initGlobalVariables sets the global variables provided by the VM. Global variables like `$foo` are shared by
+// the whole program, and reading one that hasn't been assigned returns nil.
+//
+// ```ruby
+// $count = 0
+//
+// def increment
+//   $count += 1
+// end
+//
+// increment
+// $count # => 1
+// ```
+//
+// - `$0`: the path of the program being executed, it's set when the program starts
+// - `$stdout`, `$stderr` and `$stdin`: the standard streams, which are `STDOUT`, `STDERR` and `STDIN` at first.
+//   `$stdout` can be assigned another IO like a File, then `puts` writes into it. `gets` reads from `$stdin`.
+// - `$?`: the `Process::Status` of the last command run by `system`, backticks or `Process::Child#wait`
+func (vm *VM) initGlobalVariables() {
+	vm.globalVariables.Store("$stdout", vm.objectClass.constants["STDOUT"].Target)
+	vm.globalVariables.Store("$stderr", vm.objectClass.constants["STDERR"].Target)
+	vm.globalVariables.Store("$stdin", vm.objectClass.constants["STDIN"].Target)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// globalVariable returns the value of the global variable, or nil if it's not assigned yet
+func (vm *VM) globalVariable(name string) Object {
+	v, ok := vm.globalVariables.Load(name)
+
+	if !ok {
+		return NULL
+	}
+
+	return v.(Object)
+}
+
+// setGlobalVariable assigns the global variable, the standard streams can only be assigned IOs
+func (t *thread) setGlobalVariable(name string, value Object) *Error {
+	switch name {
+	case "$stdout", "$stderr", "$stdin":
+		if _, ok := value.(*FileObject); !ok {
+			return t.initErrorObject(errors.TypeError, "%s must be %s. got: %s", name, classes.IOClass, value.Class().Name)
+		}
+	}
+
+	t.vm.globalVariables.Store(name, value)
+	return nil
+}
+
+// stdin returns the object of `$stdin`
+func (vm *VM) stdin() *FileObject {
+	if f, ok := vm.globalVariable("$stdin").(*FileObject); ok {
+		return f
+	}
+
+	return vm.objectClass.constants["STDIN"].Target.(*FileObject)
+}
+
+// stdout returns the writer of `$stdout`
+func (vm *VM) stdout() io.Writer {
+	if f, ok := vm.globalVariable("$stdout").(*FileObject); ok {
+		return f.File
+	}
+
+	return os.Stdout
+}
+
+// stderr returns the writer of `$stderr`
+func (vm *VM) stderr() io.Writer {
+	if f, ok := vm.globalVariable("$stderr").(*FileObject); ok {
+		return f.File
+	}
+
+	return os.Stderr
+}
+
+// warn writes a warning about the code the frame is executing into `$stderr`
+func (vm *VM) warn(cf *callFrame, format string, args ...interface{}) {
+	fmt.Fprintf(vm.stderr(), "%s:%d: warning: %s\n", cf.instructionSet.filename, cf.sourceLine(), fmt.Sprintf(format, args...))
+}
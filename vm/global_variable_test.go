@@ -0,0 +1,77 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestGlobalVariable(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`$foo = 1; $foo`, 1},
+		{`$foo`, nil},
+		{`$foo = 1; $foo += 2; $foo`, 3},
+		{`$foo ||= "a"; $foo`, "a"},
+		{`a = ($foo = 5); a`, 5},
+		{`a, $foo = [1, 2]; $foo`, 2},
+		{`$foo = 2; "#{$foo}"`, "2"},
+		{`
+		$count = 0
+
+		def increment
+		  $count += 1
+		end
+
+		increment
+		increment
+		$count
+		`, 2},
+		{`
+		class Foo
+		  def set
+		    $foo = self.class.name
+		  end
+		end
+
+		Foo.new.set
+		$foo
+		`, "Foo"},
+		{`
+		$foo = []
+
+		[1, 2].each do |i|
+		  $foo.push(i)
+		end
+
+		$foo.to_s
+		`, "[1, 2]"},
+		{`$stdout == STDOUT`, true},
+		{`$stderr == STDERR`, true},
+		{`$stdin == STDIN`, true},
+		{`$0.class.name`, "String"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGlobalVariableFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`$stdout = 1`, "TypeError: $stdout must be IO. got: Integer", 1},
+		{`$stdin = nil`, "TypeError: $stdin must be IO. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
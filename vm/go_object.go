@@ -29,7 +29,7 @@ func builtinGoInstanceMethods() []*BuiltinMethodObject {
 					s, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
 					funcName := s.value
@@ -38,7 +38,7 @@ func builtinGoInstanceMethods() []*BuiltinMethodObject {
 					funcArgs, err := convertToGoFuncArgs(args[1:])
 
 					if err != nil {
-						t.vm.initErrorObject(errors.TypeError, err.Error())
+						t.initErrorObject(errors.TypeError, err.Error())
 					}
 
 					result := metago.CallFunc(r.data, funcName, funcArgs...)
@@ -3,7 +3,6 @@ package vm
 import (
 	"bytes"
 	"fmt"
-	"reflect"
 	"sort"
 	"strings"
 
@@ -21,7 +20,9 @@ import (
 // Underscore `_` can also be used within the key.
 // String literal like "mickey mouse" cannot be used as a hash key.
 // The internal key is actually a String and **not a Symbol** for now (TBD).
-// Thus only a String object or a string literal should be used when referencing with `[ ]`.
+//
+// Other objects can also be used as keys with `[ ]` and `[]=`, they are compared with `hash` and `eql?`,
+// so instances of classes that define both methods can be used as keys.
 //
 // ```ruby
 // a = { balthazar1: 100 } # valid
@@ -31,6 +32,9 @@ import (
 // a["balthazar1"]  # => 100
 // a[x]             # => 100
 // a[balthazar1]    # => error
+//
+// a[[1, 2]] = "pair"
+// a[[1, 2]]        # => "pair"
 // ```
 //
 // - **value:** String literal and objects (Integer, String, Array, Hash, nil, etc) can be used.
@@ -39,9 +43,14 @@ import (
 // - The order of key-value pairs are **not** preserved.
 // - Operator `=>` is not supported.
 // - `Hash.new` is not supported.
+// - Hash includes Enumerable, whose methods receive each key and value from `#each`.
 type HashObject struct {
 	*baseObj
 	Pairs map[string]Object
+	// keyObjects holds the keys that are not Strings or Symbols, by their internal keys in Pairs
+	keyObjects map[string]Object
+	// lock is held while the hash's methods are called if it's a Concurrent::Hash
+	lock *reentrantLock
 }
 
 // Class methods --------------------------------------------------------
@@ -79,14 +88,7 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
-					}
-
-					i := args[0]
-					key, ok := i.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, i.Class().Name)
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
@@ -95,13 +97,17 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 						return NULL
 					}
 
-					value, ok := h.Pairs[key.value]
+					key, found, err := h.lookupKey(t, args[0])
 
-					if !ok {
+					if err != nil {
+						return err
+					}
+
+					if !found {
 						return NULL
 					}
 
-					return value
+					return h.Pairs[key]
 				}
 			},
 		},
@@ -122,21 +128,24 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					// First arg is index
 					// Second arg is assigned value
 					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
 					}
 
-					k := args[0]
-					key, ok := k.(*StringObject)
+					h := receiver.(*HashObject)
+					key, _, err := h.lookupKey(t, args[0])
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, k.Class().Name)
+					if err != nil {
+						return err
 					}
 
-					h := receiver.(*HashObject)
-					h.Pairs[key.value] = args[1]
+					h.set(key, args[0], args[1])
 
 					return args[1]
 				}
@@ -155,13 +164,48 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					return t.vm.initHashObject(make(map[string]Object))
 				}
 			},
 		},
+		{
+			// Loop through each key and value of the hash in the alphabetical order of keys,
+			// and returns the hash itself.
+			//
+			// ```Ruby
+			// h = { b: 2, a: 1 }
+			// h.each do |k, v|
+			//   puts(k + ": " + v.to_s)
+			// end
+			// # => a: 1
+			// # => b: 2
+			// ```
+			//
+			// @return [Hash]
+			Name: "each",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each", args)
+					}
+
+					h := receiver.(*HashObject)
+
+					for _, k := range h.sortedKeys() {
+						t.builtinMethodYield(blockFrame, h.keyObject(t.vm, k), h.Pairs[k])
+					}
+
+					return h
+				}
+			},
+		},
 		{
 			// Loop through keys of the hash with given block frame. It also returns array of
 			// keys in alphabetical order.
@@ -182,11 +226,11 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "each_key", args)
 					}
 
 					h := receiver.(*HashObject)
@@ -194,7 +238,7 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 					var arrOfKeys []Object
 
 					for _, k := range keys {
-						obj := t.vm.initStringObject(k)
+						obj := h.keyObject(t.vm, k)
 						arrOfKeys = append(arrOfKeys, obj)
 						t.builtinMethodYield(blockFrame, obj)
 					}
@@ -222,11 +266,11 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "each_value", args)
 					}
 
 					h := receiver.(*HashObject)
@@ -256,7 +300,7 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
@@ -279,17 +323,21 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
-					c := args[0]
-					compare, ok := c.(*HashObject)
+					compare, ok := args[0].(*HashObject)
 
-					if ok && reflect.DeepEqual(h, compare) {
-						return TRUE
+					if !ok {
+						return FALSE
 					}
-					return FALSE
+
+					eq, err := t.hashesEqual(h, compare)
+					if err != nil {
+						return err
+					}
+					return toBooleanObject(eq)
 				}
 			},
 		},
@@ -305,35 +353,35 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Name: "delete",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
-					d := args[0]
-					deleteKey, ok := d.(*StringObject)
+					key, found, err := h.lookupKey(t, args[0])
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, d.Class().Name)
+					if err != nil {
+						return err
 					}
 
-					deleteKeyValue := deleteKey.value
-					if _, ok := h.Pairs[deleteKeyValue]; ok {
-						delete(h.Pairs, deleteKeyValue)
+					if found {
+						h.remove(key)
 					}
 					return h
 				}
 			},
 		},
 		{
-			// Returns true if the key exist in the hash. Currently, it can only input string
-			// type object.
+			// Returns true if the key exist in the hash. The key can be a String or a Symbol.
 			//
 			// ```Ruby
 			// h = { a: 1, b: "2", c: [1, 2, 3], d: { k: "v" } }
 			// h.has_key?("a") # => true
 			// h.has_key?("e") # => false
-			// # TODO: Support Symbol Type Key Input
 			// h.has_key?(:b)  # => true
 			// h.has_key?(:f)  # => false
 			// ```
@@ -343,21 +391,16 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
-					i := args[0]
-					input, ok := i.(*StringObject)
+					_, found, err := h.lookupKey(t, args[0])
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, i.Class().Name)
+					if err != nil {
+						return err
 					}
-
-					if _, ok := h.Pairs[input.value]; ok {
-						return TRUE
-					}
-					return FALSE
+					return toBooleanObject(found)
 				}
 			},
 		},
@@ -378,13 +421,17 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
 
 					for _, v := range h.Pairs {
-						if reflect.DeepEqual(v, args[0]) {
+						eq, err := t.objectsEqual(v, args[0])
+						if err != nil {
+							return err
+						}
+						if eq {
 							return TRUE
 						}
 					}
@@ -405,13 +452,13 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
 					var keys []Object
 					for k := range h.Pairs {
-						keys = append(keys, t.vm.initStringObject(k))
+						keys = append(keys, h.keyObject(t.vm, k))
 					}
 					return t.vm.initArrayObject(keys)
 				}
@@ -430,7 +477,7 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
@@ -455,12 +502,16 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Name: "map_values",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
 					}
 
 					h := receiver.(*HashObject)
@@ -486,26 +537,80 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
-					result := make(map[string]Object)
-					for k, v := range h.Pairs {
-						result[k] = v
-					}
+					result := h.copy().(*HashObject)
 
 					for _, obj := range args {
 						hashObj, ok := obj.(*HashObject)
 						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, obj.Class().Name)
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, obj.Class().Name)
 						}
 						for k, v := range hashObj.Pairs {
-							result[k] = v
+							keyObj := hashObj.keyObject(t.vm, k)
+							key, _, err := result.lookupKey(t, keyObj)
+							if err != nil {
+								return err
+							}
+							result.set(key, keyObj, v)
 						}
 					}
 
-					return t.vm.initHashObject(result)
+					return result
+				}
+			},
+		},
+		{
+			// Returns a new hash with the pairs for which the block returns false or nil.
+			//
+			// ```Ruby
+			// h = { a: 1, b: 2, c: 3 }
+			// h.reject do |k, v|
+			//   v > 1
+			// end # => { a: 1 }
+			// ```
+			//
+			// @return [Hash]
+			Name: "reject",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "reject", args)
+					}
+
+					return t.filterPairs(receiver.(*HashObject), blockFrame, false)
+				}
+			},
+		},
+		{
+			// Returns a new hash with the pairs for which the block returns a value other than false or nil.
+			//
+			// ```Ruby
+			// h = { a: 1, b: 2, c: 3 }
+			// h.select do |k, v|
+			//   v > 1
+			// end # => { b: 2, c: 3 }
+			// ```
+			//
+			// @return [Hash]
+			Name: "select",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "select", args)
+					}
+
+					return t.filterPairs(receiver.(*HashObject), blockFrame, true)
 				}
 			},
 		},
@@ -528,14 +633,14 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
 					sortedKeys := h.sortedKeys()
 					var keys []Object
 					for _, k := range sortedKeys {
-						keys = append(keys, t.vm.initStringObject(k))
+						keys = append(keys, h.keyObject(t.vm, k))
 					}
 					return t.vm.initArrayObject(keys)
 				}
@@ -567,12 +672,12 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 					if len(args) == 0 {
 						sorted = false
 					} else if len(args) > 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
 					} else {
 						s := args[0]
 						st, ok := s.(*BooleanObject)
 						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, s.Class().Name)
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, s.Class().Name)
 						}
 						sorted = st.value
 					}
@@ -581,14 +686,14 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 					if sorted {
 						for _, k := range h.sortedKeys() {
 							var pairArr []Object
-							pairArr = append(pairArr, t.vm.initStringObject(k))
+							pairArr = append(pairArr, h.keyObject(t.vm, k))
 							pairArr = append(pairArr, h.Pairs[k])
 							resultArr = append(resultArr, t.vm.initArrayObject(pairArr))
 						}
 					} else {
 						for k, v := range h.Pairs {
 							var pairArr []Object
-							pairArr = append(pairArr, t.vm.initStringObject(k))
+							pairArr = append(pairArr, h.keyObject(t.vm, k))
 							pairArr = append(pairArr, v)
 							resultArr = append(resultArr, t.vm.initArrayObject(pairArr))
 						}
@@ -611,7 +716,7 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					r := receiver.(*HashObject)
@@ -633,11 +738,14 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
-					h := receiver.(*HashObject)
-					return t.vm.initStringObject(h.toString())
+					s, err := t.builtinString(receiver)
+					if err != nil {
+						return err
+					}
+					return t.vm.initStringObject(s)
 				}
 			},
 		},
@@ -660,20 +768,20 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
 					}
 
 					h := receiver.(*HashObject)
-					resultHash := make(map[string]Object)
+					resultHash := h.copy().(*HashObject)
 					for k, v := range h.Pairs {
 						result := t.builtinMethodYield(blockFrame, v)
-						resultHash[k] = result.Target
+						resultHash.Pairs[k] = result.Target
 					}
-					return t.vm.initHashObject(resultHash)
+					return resultHash
 				}
 			},
 		},
@@ -690,7 +798,7 @@ func builtinHashInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 
 					h := receiver.(*HashObject)
@@ -720,6 +828,7 @@ func (vm *VM) initHashClass() *RClass {
 	hc := vm.initializeClass(classes.HashClass, false)
 	hc.setBuiltinMethods(builtinHashInstanceMethods(), false)
 	hc.setBuiltinMethods(builtinHashClassMethods(), true)
+	hc.include(vm.topLevelClass(classes.EnumerableModule))
 	return hc
 }
 
@@ -730,25 +839,46 @@ func (h *HashObject) Value() interface{} {
 	return h.Pairs
 }
 
+// Returns the lock of a Concurrent::Hash, or nil
+func (h *HashObject) synchronizer() *reentrantLock {
+	return h.lock
+}
+
 // Returns the object's name as the string format
 func (h *HashObject) toString() string {
+	s, _ := h.inspectPairs(defaultInspect)
+	return s
+}
+
+// inspectPairs formats the hash with each value formatted by inspect
+func (h *HashObject) inspectPairs(inspect inspector) (string, *Error) {
 	var out bytes.Buffer
 	var pairs []string
 
 	for _, key := range h.sortedKeys() {
-		// TODO: Improve this conditional statement
-		if _, isString := h.Pairs[key].(*StringObject); isString {
-			pairs = append(pairs, fmt.Sprintf("%s: \"%s\"", key, h.Pairs[key].toString()))
-		} else {
-			pairs = append(pairs, fmt.Sprintf("%s: %s", key, h.Pairs[key].toString()))
+		s, err := inspect(h.Pairs[key])
+		if err != nil {
+			return "", err
 		}
+
+		keyObj, ok := h.keyObjects[key]
+		if !ok {
+			pairs = append(pairs, fmt.Sprintf("%s: %s", key, s))
+			continue
+		}
+
+		k, err := inspect(keyObj)
+		if err != nil {
+			return "", err
+		}
+		pairs = append(pairs, fmt.Sprintf("%s => %s", k, s))
 	}
 
 	out.WriteString("{ ")
 	out.WriteString(strings.Join(pairs, ", "))
 	out.WriteString(" }")
 
-	return out.String()
+	return out.String(), nil
 }
 
 // Returns the object's name as the JSON string format
@@ -759,6 +889,9 @@ func (h *HashObject) toJSON() string {
 	out.WriteString("{")
 
 	for key, value := range pairs {
+		if keyObj, ok := h.keyObjects[key]; ok {
+			key = keyObj.toString()
+		}
 		values = append(values, generateJSONFromPair(key, value))
 	}
 
@@ -795,11 +928,118 @@ func (h *HashObject) copy() Object {
 		Pairs:   elems,
 	}
 
+	if h.lock != nil {
+		newHash.lock = newReentrantLock()
+	}
+
+	for k, v := range h.keyObjects {
+		newHash.set(k, v, elems[k])
+	}
+
 	return newHash
 }
 
+// lookupKey returns the key of Pairs that the given key is stored with, and whether it's in the hash.
+// Strings and Symbols are used as they are, other keys are compared with the stored ones by `hash` and `eql?`
+// and get a new internal key if they're not found.
+func (h *HashObject) lookupKey(t *thread, key Object) (string, bool, *Error) {
+	if s, ok := stringOrSymbol(key); ok {
+		_, found := h.Pairs[s]
+		return s, found, nil
+	}
+
+	hash, err := t.hashOf(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	prefix := fmt.Sprintf("%s%d#", objectKeyPrefix, hash)
+
+	for k, keyObj := range h.keyObjects {
+		if !strings.HasPrefix(k, prefix) {
+			continue
+		}
+
+		eql, err := t.objectsEql(keyObj, key)
+		if err != nil {
+			return "", false, err
+		}
+		if eql {
+			return k, true, nil
+		}
+	}
+
+	for i := 0; ; i++ {
+		k := fmt.Sprintf("%s%d", prefix, i)
+		if _, ok := h.keyObjects[k]; !ok {
+			return k, false, nil
+		}
+	}
+}
+
+// set stores the value with the internal key returned by lookupKey
+func (h *HashObject) set(k string, key Object, value Object) {
+	h.Pairs[k] = value
+
+	if _, ok := stringOrSymbol(key); ok {
+		return
+	}
+
+	if h.keyObjects == nil {
+		h.keyObjects = map[string]Object{}
+	}
+	h.keyObjects[k] = key
+}
+
+// remove deletes the pair with the internal key
+func (h *HashObject) remove(k string) {
+	delete(h.Pairs, k)
+	delete(h.keyObjects, k)
+}
+
+// keyObject returns the key object of the internal key, which is a String unless the key was another object
+func (h *HashObject) keyObject(vm *VM, k string) Object {
+	if keyObj, ok := h.keyObjects[k]; ok {
+		return keyObj
+	}
+
+	return vm.initStringObject(k)
+}
+
 // Other helper functions ----------------------------------------------
 
+// filterPairs returns a new hash with the pairs whose test results of the block match the expected value
+func (t *thread) filterPairs(h *HashObject, blockFrame *callFrame, expected bool) Object {
+	result := t.vm.initHashObject(map[string]Object{})
+
+	// The block's frame is left on the stack if the hash is empty and the block is never yielded
+	defer func() {
+		if t.callFrameStack.top() == blockFrame {
+			t.callFrameStack.pop()
+		}
+	}()
+
+	for _, k := range h.sortedKeys() {
+		key := h.keyObject(t.vm, k)
+		value := h.Pairs[k]
+
+		test, err := t.testElement(blockFrame, []Object{key, value})
+
+		if err != nil {
+			return err
+		}
+
+		if test == expected {
+			result.set(k, key, value)
+		}
+	}
+
+	return result
+}
+
+// objectKeyPrefix starts the internal keys of non-String keys, so they can't collide with String keys
+const objectKeyPrefix = "\x00"
+
 // Return the JSON style strings of the Hash object
 func generateJSONFromPair(key string, v Object) string {
 	var data string
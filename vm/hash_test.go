@@ -130,8 +130,14 @@ func TestHashAccessOperation(t *testing.T) {
 func TestHashAccessOperationFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`{ a: 1, b: 2 }[]`, "ArgumentError: Expect 1 argument. got: 0", 1},
-		{`{ a: 1, b: 2 }[true]`, "TypeError: Expect argument to be String. got: Boolean", 1},
-		{`{ a: 1, b: 2 }[true] = 1`, "TypeError: Expect argument to be String. got: Boolean", 1},
+		{`
+		class Foo
+		  def hash
+		    "foo"
+		  end
+		end
+		{ a: 1 }[Foo.new]
+		`, "TypeError: Expect hash to return Integer. got: String", 7},
 	}
 
 	for i, tt := range testsFail {
@@ -221,6 +227,51 @@ func TestHashClearMethodFail(t *testing.T) {
 	}
 }
 
+func TestHashEachMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+			arr = []
+			{ b: "World", a: "Hello", c: "Goby" }.each do |key, value|
+			  arr.push(key + ": " + value)
+			end
+			arr.to_s
+		`, `["a: Hello", "b: World", "c: Goby"]`},
+		{`
+			h = { a: 1 }
+			h.each do |key, value|
+			  # Empty Block
+			end.length
+		`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHashEachMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`{ a: 1 }.each(1) do |key, value|
+		end
+		`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestHashEachKeyMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -265,7 +316,6 @@ func TestHashEachKeyMethodFail(t *testing.T) {
 		  puts key
 		end
 		`, "ArgumentError: Expect 0 argument. got: 1", 1},
-		{`{ a: 1, b: 2, c: 3 }.each_key`, "InternalError: Can't yield without a block", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -349,7 +399,6 @@ func TestHashEachValueMethodFail(t *testing.T) {
 		  puts value
 		end
 		`, "ArgumentError: Expect 0 argument. got: 1", 1},
-		{`{ a: 1, b: 2, c: 3 }.each_value`, "InternalError: Can't yield without a block", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -493,8 +542,6 @@ func TestHashDeleteMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`{ a: 1, b: "Hello", c: true }.delete`, "ArgumentError: Expect 1 argument. got: 0", 1},
 		{`{ a: 1, b: "Hello", c: true }.delete("a", "b")`, "ArgumentError: Expect 1 argument. got: 2", 1},
-		{`{ a: 1, b: "Hello", c: true }.delete(123)`, "TypeError: Expect argument to be String. got: Integer", 1},
-		{`{ a: 1, b: "Hello", c: true }.delete(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -528,8 +575,6 @@ func TestHashHasKeyMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`{ a: 1, b: 2 }.has_key?`, "ArgumentError: Expect 1 argument. got: 0", 1},
 		{`{ a: 1, b: 2 }.has_key?(true, { hello: "World" })`, "ArgumentError: Expect 1 argument. got: 2", 1},
-		{`{ a: 1, b: 2 }.has_key?(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
-		{`{ a: 1, b: 2 }.has_key?(123)`, "TypeError: Expect argument to be String. got: Integer", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -564,6 +609,126 @@ func TestHashHasValueMethod(t *testing.T) {
 	}
 }
 
+func TestHashObjectKeys(t *testing.T) {
+	pointClass := `
+	class Point
+	  attr_reader :x, :y
+
+	  def initialize(x, y)
+	    @x = x
+	    @y = y
+	  end
+
+	  def ==(other)
+	    other.is_a?(Point) && x == other.x && y == other.y
+	  end
+
+	  def eql?(other)
+	    self == other
+	  end
+
+	  def hash
+	    x * 31 + y
+	  end
+	end
+	`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		h = {}
+		h[1] = "one"
+		h["1"] = "string one"
+		h[1]
+		`, "one"},
+		{`
+		h = {}
+		h[[1, 2]] = "pair"
+		h[[1, 2]]
+		`, "pair"},
+		{`
+		h = { a: 1 }
+		h[nil] = 2
+		h[true] = 3
+		h.length
+		`, 3},
+		{`
+		h = {}
+		h[1] = "a"
+		h[1] = "b"
+		h.to_s
+		`, `{ 1 => "b" }`},
+		{`
+		h = {}
+		h[1] = "a"
+		h[1.0] = "b"
+		h.length.to_s + h[1] + h[1.0]
+		`, "2ab"},
+		{`
+		h = {}
+		h[[1]] = "a"
+		h[[1.0]] = "b"
+		h.length
+		`, 2},
+		{`
+		h = { a: 1 }
+		h[2] = "b"
+		h.sorted_keys.to_s
+		`, `[2, "a"]`},
+		{pointClass + `
+		h = {}
+		h[Point.new(1, 2)] = "p"
+		h[Point.new(1, 2)]
+		`, "p"},
+		{pointClass + `
+		h = {}
+		h[Point.new(1, 2)] = "p"
+		h[Point.new(2, 1)]
+		`, nil},
+		{pointClass + `
+		h = {}
+		h[Point.new(1, 2)] = "p"
+		h[Point.new(1, 2)] = "q"
+		h.length
+		`, 1},
+		{pointClass + `
+		h = {}
+		h[Point.new(1, 2)] = "p"
+		h.has_key?(Point.new(1, 2))
+		`, true},
+		{pointClass + `
+		h = {}
+		h[Point.new(1, 2)] = "p"
+		h.delete(Point.new(1, 2))
+		h.empty?
+		`, true},
+		{pointClass + `
+		h = {}
+		h[Point.new(1, 2)] = "p"
+		h2 = { a: 1 }.merge(h)
+		h2[Point.new(1, 2)]
+		`, "p"},
+		{pointClass + `
+		a = {}
+		a[Point.new(1, 2)] = 1
+		b = {}
+		b[Point.new(1, 2)] = 1
+		a == b
+		`, true},
+		{pointClass + `{ a: Point.new(1, 2) }.has_value?(Point.new(1, 2))`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestHashHasValueMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`{ a: 1, b: 2 }.has_value?`, "ArgumentError: Expect 1 argument. got: 0", 1},
@@ -784,6 +949,69 @@ func TestHashMergeMethodFail(t *testing.T) {
 	}
 }
 
+func TestHashSelectAndRejectMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		h = { a: 1, b: 2, c: 3 }.select do |k, v|
+		  v > 1
+		end
+		h.to_s`, "{ b: 2, c: 3 }"},
+		{`
+		h = { a: 1, b: 2, c: 3 }.reject do |k, v|
+		  v > 1
+		end
+		h.to_s`, "{ a: 1 }"},
+		{`
+		h = {}
+		h[[1]] = 1
+		h[[2]] = 2
+		h.select do |k, v|
+		  k == [2]
+		end[[2]]`, 2},
+		{`
+		{}.select do |k, v|
+		  true
+		end.to_s`, "{  }"},
+		{`
+		h = { a: 1 }
+		h.reject do |k, v|
+		  true
+		end
+		h.to_s`, "{ a: 1 }"},
+		{`{ a: 1 }.select.class.name`, "Enumerator"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHashSelectAndRejectMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`{ a: 1 }.select(1) do |k, v|
+		  true
+		end`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`{ a: 1 }.reject(1) do |k, v|
+		  true
+		end`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestHashSortedKeysMethod(t *testing.T) {
 	tests := []struct {
 		input    string
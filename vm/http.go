@@ -12,8 +12,9 @@ import (
 )
 
 var (
-	httpRequestClass  *RClass
-	httpResponseClass *RClass
+	httpRequestClass      *RClass
+	httpResponseClass     *RClass
+	httpUploadedFileClass *RClass
 )
 
 // Class methods --------------------------------------------------------
@@ -37,20 +38,30 @@ func builtinHTTPClassMethods() []*BuiltinMethodObject {
 						uri.Path = path.Join(arr...)
 					}
 
-					resp, err := http.Get(uri.String())
+					req, err := http.NewRequestWithContext(t.context(), http.MethodGet, uri.String(), nil)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.ArgumentError, err.Error())
+					}
+
+					resp, err := http.DefaultClient.Do(req)
+
+					if err != nil {
+						if t.context().Err() != nil {
+							return t.timeoutError()
+						}
+
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 					if resp.StatusCode != http.StatusOK {
-						return t.vm.initErrorObject(errors.InternalError, resp.Status)
+						return t.initErrorObject(errors.InternalError, resp.Status)
 					}
 
 					content, err := ioutil.ReadAll(resp.Body)
 					resp.Body.Close()
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					return t.vm.initStringObject(string(content))
@@ -62,32 +73,43 @@ func builtinHTTPClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 3 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 3 arguments. got=%v", strconv.Itoa(len(args)))
+						return t.initErrorObject(errors.ArgumentError, "Expect 3 arguments. got=%v", strconv.Itoa(len(args)))
 					}
 
 					uri, err := url.Parse(args[0].(*StringObject).value)
 					if err != nil {
-						return t.vm.initErrorObject(errors.ArgumentError, err.Error())
+						return t.initErrorObject(errors.ArgumentError, err.Error())
 					}
 
 					contentType := args[1].(*StringObject).value
 
 					body := args[2].(*StringObject).value
 
-					resp, err := http.Post(uri.String(), contentType, strings.NewReader(body))
+					req, err := http.NewRequestWithContext(t.context(), http.MethodPost, uri.String(), strings.NewReader(body))
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.ArgumentError, err.Error())
+					}
+
+					req.Header.Set("Content-Type", contentType)
+					resp, err := http.DefaultClient.Do(req)
+
+					if err != nil {
+						if t.context().Err() != nil {
+							return t.timeoutError()
+						}
+
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 					if resp.StatusCode != http.StatusOK {
-						return t.vm.initErrorObject(errors.InternalError, resp.Status)
+						return t.initErrorObject(errors.InternalError, resp.Status)
 					}
 
 					content, err := ioutil.ReadAll(resp.Body)
 					resp.Body.Close()
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					return t.vm.initStringObject(string(content))
@@ -107,12 +129,15 @@ func initHTTPClass(vm *VM) {
 	http.setBuiltinMethods(builtinHTTPClassMethods(), true)
 	initRequestClass(vm, http)
 	initResponseClass(vm, http)
+	initUploadedFileClass(vm, http)
+	initHTTPClientClass(vm, http)
 
 	net.setClassConstant(http)
 
 	// Use Goby code to extend request and response classes.
 	vm.execGobyLib("net/http/response.gb")
 	vm.execGobyLib("net/http/request.gb")
+	vm.execGobyLib("net/http/uploaded_file.gb")
 }
 
 func initRequestClass(vm *VM, hc *RClass) *RClass {
@@ -136,3 +161,11 @@ func initResponseClass(vm *VM, hc *RClass) *RClass {
 	httpResponseClass = responseClass
 	return responseClass
 }
+
+func initUploadedFileClass(vm *VM, hc *RClass) *RClass {
+	uploadedFileClass := vm.initializeClass("UploadedFile", false)
+	hc.setClassConstant(uploadedFileClass)
+
+	httpUploadedFileClass = uploadedFileClass
+	return uploadedFileClass
+}
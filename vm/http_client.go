@@ -0,0 +1,698 @@
+package vm
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// HTTPClientObject sends HTTP requests with any verb and returns `Net::HTTP::Response` objects, which expose
+// the status, headers and body. Unlike `Net::HTTP.get`, responses with error statuses are returned instead of raised.
+// The client's options apply to all of its requests: `timeout:` is the seconds to wait for a response, which has
+// no limit by default, `follow_redirects:` can be false to return redirect responses as they are, and `headers:`
+// are sent with every request. Underscores in header names become dashes, so `content_type:` is sent as "Content-Type".
+//
+// For HTTPS, `ca_file:` is a PEM file of the certificate authorities to trust instead of the system's, `cert_file:` and
+// `key_file:` are the PEM files of the client certificate, and `skip_verify:` can be true to accept any server certificate,
+// which should only be used for testing.
+//
+// Each request accepts `params:` for the query string, `headers:` for extra headers and `body:` for the request body.
+//
+// ```ruby
+// require "net/http"
+//
+// client = Net::HTTP::Client.new(timeout: 5, headers: { user_agent: "goby" })
+// client.basic_auth("stan", "secret")
+//
+// res = client.get("https://api.example.com/users", params: { page: 2 })
+// res.status_code                 # => 200
+// res.get_header("Content-Type")  # => "text/html; charset=utf-8"
+// res.body                        # => "<html>..."
+//
+// client.post("https://api.example.com/users", "name=stan", headers: { content_type: "application/x-www-form-urlencoded" })
+// client.delete("https://api.example.com/users/1").status_code # => 204
+//
+// user = client.post_json("https://api.example.com/users", { name: "stan" })
+// user["id"] # => 1
+//
+// internal = Net::HTTP::Client.new(ca_file: "certs/ca.pem", cert_file: "certs/client.pem", key_file: "certs/client-key.pem")
+// ```
+type HTTPClientObject struct {
+	*baseObj
+	client   *http.Client
+	headers  http.Header
+	username string
+	password string
+	hasAuth  bool
+}
+
+// httpClientTLSFiles are the `ca_file:`, `cert_file:` and `key_file:` options of the client
+type httpClientTLSFiles map[string]string
+
+// httpRequestOptions are the keyword arguments of the client's request methods
+type httpRequestOptions struct {
+	params  url.Values
+	headers http.Header
+	body    *StringObject
+}
+
+// Class methods --------------------------------------------------------
+func builtinHTTPClientClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Initializes a client with the options `timeout:`, `follow_redirects:`, `headers:`, and the TLS options
+			// `ca_file:`, `cert_file:`, `key_file:` and `skip_verify:`.
+			//
+			// ```ruby
+			// Net::HTTP::Client.new
+			// Net::HTTP::Client.new(timeout: 2.5, follow_redirects: false)
+			// Net::HTTP::Client.new(ca_file: "certs/ca.pem")
+			// ```
+			//
+			// @param options [Hash]
+			// @return [Net::HTTP::Client]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					c := &HTTPClientObject{
+						baseObj: &baseObj{class: receiver.(*RClass)},
+						client:  &http.Client{},
+						headers: http.Header{},
+					}
+
+					if len(args) == 0 {
+						return c
+					}
+
+					opts, ok := args[0].(*HashObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+					}
+
+					tlsFiles := httpClientTLSFiles{}
+					var tlsConfig *tls.Config
+
+					for k, v := range opts.Pairs {
+						switch k {
+						case "ca_file", "cert_file", "key_file":
+							file, ok := v.(*StringObject)
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, v.Class().Name)
+							}
+
+							tlsFiles[k] = file.value
+						case "skip_verify":
+							skip, ok := v.(*BooleanObject)
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, v.Class().Name)
+							}
+
+							if tlsConfig == nil {
+								tlsConfig = &tls.Config{}
+							}
+
+							tlsConfig.InsecureSkipVerify = skip.value
+						case "timeout":
+							seconds, ok := toFloat64(v)
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Numeric", v.Class().Name)
+							}
+
+							c.client.Timeout = secondsToDuration(seconds)
+						case "follow_redirects":
+							follow, ok := v.(*BooleanObject)
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.BooleanClass, v.Class().Name)
+							}
+
+							if !follow.value {
+								c.client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+									return http.ErrUseLastResponse
+								}
+							}
+						case "headers":
+							headers, e := t.httpHeaders(v)
+							if e != nil {
+								return e
+							}
+
+							c.headers = headers
+						default:
+							return t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+						}
+					}
+
+					if len(tlsFiles) > 0 {
+						if tlsConfig == nil {
+							tlsConfig = &tls.Config{}
+						}
+
+						if e := t.loadClientTLSFiles(tlsConfig, tlsFiles); e != nil {
+							return e
+						}
+					}
+
+					if tlsConfig != nil {
+						transport := http.DefaultTransport.(*http.Transport).Clone()
+						transport.TLSClientConfig = tlsConfig
+						c.client.Transport = transport
+					}
+
+					return c
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinHTTPClientInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Sends the username and password with every request using HTTP basic authentication. Returns the client.
+			//
+			// ```ruby
+			// client.basic_auth("stan", "secret")
+			// ```
+			//
+			// @param username [String], password [String]
+			// @return [Net::HTTP::Client]
+			Name: "basic_auth",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					username, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					password, ok := args[1].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+					}
+
+					c := receiver.(*HTTPClientObject)
+					c.username = username.value
+					c.password = password.value
+					c.hasAuth = true
+
+					return c
+				}
+			},
+		},
+		{
+			// Sends a DELETE request and returns the response.
+			//
+			// ```ruby
+			// client.delete("https://api.example.com/users/1").status_code # => 204
+			// ```
+			//
+			// @param url [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "delete",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), http.MethodDelete, args, false)
+				}
+			},
+		},
+		{
+			// Sends a GET request and returns the response.
+			//
+			// ```ruby
+			// client.get("https://api.example.com/users", params: { page: 2 }).body
+			// ```
+			//
+			// @param url [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "get",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), http.MethodGet, args, false)
+				}
+			},
+		},
+		{
+			// Sends a GET request that accepts JSON, and returns the parsed response body.
+			// Raises an error if the response status isn't successful.
+			//
+			// ```ruby
+			// client.get_json("https://api.example.com/users/1")["name"] # => "stan"
+			// ```
+			//
+			// @param url [String], options [Hash]
+			// @return [Hash/Array]
+			Name: "get_json",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendJSONRequest(receiver.(*HTTPClientObject), http.MethodGet, args, nil)
+				}
+			},
+		},
+		{
+			// Sends a HEAD request and returns the response, whose body is empty.
+			//
+			// ```ruby
+			// client.head("https://example.com").get_header("Content-Length") # => "1270"
+			// ```
+			//
+			// @param url [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "head",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), http.MethodHead, args, false)
+				}
+			},
+		},
+		{
+			// Sends a PATCH request with the optional body and returns the response.
+			//
+			// ```ruby
+			// client.patch("https://api.example.com/users/1", "name=goby")
+			// ```
+			//
+			// @param url [String], body [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "patch",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), http.MethodPatch, args, true)
+				}
+			},
+		},
+		{
+			// Sends a POST request with the optional body and returns the response.
+			//
+			// ```ruby
+			// client.post("https://api.example.com/users", "name=stan").status_code # => 201
+			// ```
+			//
+			// @param url [String], body [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "post",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), http.MethodPost, args, true)
+				}
+			},
+		},
+		{
+			// Sends a POST request with the object encoded as JSON, and returns the parsed response body.
+			// Raises an error if the response status isn't successful.
+			//
+			// ```ruby
+			// client.post_json("https://api.example.com/users", { name: "stan" })["id"] # => 1
+			// ```
+			//
+			// @param url [String], object [Object], options [Hash]
+			// @return [Hash/Array]
+			Name: "post_json",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 2 || len(args) > 3 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2..3 arguments. got: %d", len(args))
+					}
+
+					body := t.vm.initStringObject(args[1].toJSON())
+					rest := append([]Object{args[0]}, args[2:]...)
+
+					return t.sendJSONRequest(receiver.(*HTTPClientObject), http.MethodPost, rest, body)
+				}
+			},
+		},
+		{
+			// Sends a PUT request with the optional body and returns the response.
+			//
+			// ```ruby
+			// client.put("https://api.example.com/users/1", "name=goby")
+			// ```
+			//
+			// @param url [String], body [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "put",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), http.MethodPut, args, true)
+				}
+			},
+		},
+		{
+			// Sends a request with the given verb and returns the response.
+			//
+			// ```ruby
+			// client.request("OPTIONS", "https://api.example.com/users").get_header("Allow") # => "GET, POST"
+			// ```
+			//
+			// @param method [String], url [String], options [Hash]
+			// @return [Net::HTTP::Response]
+			Name: "request",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2..3 arguments. got: %d", len(args))
+					}
+
+					method, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					return t.sendHTTPRequest(receiver.(*HTTPClientObject), strings.ToUpper(method.value), args[1:], false)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initHTTPClientClass(vm *VM, hc *RClass) *RClass {
+	clientClass := vm.initializeClass("Client", false)
+	clientClass.setBuiltinMethods(builtinHTTPClientClassMethods(), true)
+	clientClass.setBuiltinMethods(builtinHTTPClientInstanceMethods(), false)
+	hc.setClassConstant(clientClass)
+
+	return clientClass
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the client's class name
+func (c *HTTPClientObject) toString() string {
+	return "<" + c.class.Name + ">"
+}
+
+// Alias of toString
+func (c *HTTPClientObject) toJSON() string {
+	return c.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// sendHTTPRequest sends the request described by the url, the optional body and the options in args,
+// and returns the response object
+func (t *thread) sendHTTPRequest(c *HTTPClientObject, method string, args []Object, withBody bool) Object {
+	req, e := t.newHTTPRequest(c, method, args, withBody, nil)
+	if e != nil {
+		return e
+	}
+
+	resp, body, e := t.doHTTPRequest(c, req)
+	if e != nil {
+		return e
+	}
+
+	return t.initHTTPResponse(resp, body)
+}
+
+// sendJSONRequest sends the request with JSON headers and returns the parsed response body
+func (t *thread) sendJSONRequest(c *HTTPClientObject, method string, args []Object, body *StringObject) Object {
+	req, e := t.newHTTPRequest(c, method, args, false, body)
+	if e != nil {
+		return e
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, content, e := t.doHTTPRequest(c, req)
+	if e != nil {
+		return e
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return t.initErrorObject(errors.InternalError, resp.Status)
+	}
+
+	if strings.TrimSpace(content) == "" {
+		return NULL
+	}
+
+	return t.parseJSON(content)
+}
+
+// newHTTPRequest builds the request from the arguments of the client's methods: the url, the body if withBody is true,
+// and the options in the trailing hash. The given body is used instead if it isn't nil.
+func (t *thread) newHTTPRequest(c *HTTPClientObject, method string, args []Object, withBody bool, body *StringObject) (*http.Request, *Error) {
+	var opts httpRequestOptions
+
+	if len(args) > 1 {
+		if h, ok := args[len(args)-1].(*HashObject); ok {
+			o, e := t.httpRequestOptions(h)
+			if e != nil {
+				return nil, e
+			}
+
+			opts = o
+			args = args[:len(args)-1]
+		}
+	}
+
+	switch {
+	case withBody && (len(args) < 1 || len(args) > 2):
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 1..3 arguments. got: %d", len(args))
+	case !withBody && len(args) != 1:
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+	}
+
+	rawURL, ok := args[0].(*StringObject)
+	if !ok {
+		return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+	}
+
+	if len(args) == 2 {
+		b, ok := args[1].(*StringObject)
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+		}
+
+		opts.body = b
+	}
+
+	if body != nil {
+		opts.body = body
+	}
+
+	u, err := url.Parse(rawURL.value)
+	if err != nil {
+		return nil, t.initErrorObject(errors.ArgumentError, err.Error())
+	}
+
+	if len(opts.params) > 0 {
+		query := u.Query()
+		for k, values := range opts.params {
+			for _, v := range values {
+				query.Add(k, v)
+			}
+		}
+
+		u.RawQuery = query.Encode()
+	}
+
+	var reader io.Reader
+	if opts.body != nil {
+		reader = strings.NewReader(opts.body.value)
+	}
+
+	req, err := http.NewRequestWithContext(t.context(), method, u.String(), reader)
+	if err != nil {
+		return nil, t.initErrorObject(errors.ArgumentError, err.Error())
+	}
+
+	for k, values := range c.headers {
+		req.Header[k] = values
+	}
+
+	for k, values := range opts.headers {
+		req.Header[k] = values
+	}
+
+	if c.hasAuth {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	return req, nil
+}
+
+// doHTTPRequest sends the request and reads the response body
+func (t *thread) doHTTPRequest(c *HTTPClientObject, req *http.Request) (*http.Response, string, *Error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if t.context().Err() != nil {
+			return nil, "", t.timeoutError()
+		}
+
+		if e, ok := err.(net.Error); ok && e.Timeout() {
+			errClass := t.vm.topLevelClass(classes.TimeoutModule).getClassConstant("Error")
+			return nil, "", t.initErrorObjectWithClass(errClass, "request timed out after "+c.client.Timeout.String())
+		}
+
+		return nil, "", t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	content, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if err != nil {
+		return nil, "", t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return resp, string(content), nil
+}
+
+// initHTTPResponse initializes a `Net::HTTP::Response` with the response's status, headers and body.
+// Headers with multiple values are joined with commas.
+func (t *thread) initHTTPResponse(resp *http.Response, body string) *RObject {
+	res := httpResponseClass.initializeInstance()
+
+	headers := map[string]Object{}
+	for k, values := range resp.Header {
+		headers[k] = t.vm.initStringObject(strings.Join(values, ", "))
+	}
+
+	res.instanceVariableSet("@status", t.vm.initStringObject(resp.Status))
+	res.instanceVariableSet("@status_code", t.vm.initIntegerObject(resp.StatusCode))
+	res.instanceVariableSet("@protocol", t.vm.initStringObject(resp.Proto))
+	res.instanceVariableSet("@headers", t.vm.initHashObject(headers))
+	res.instanceVariableSet("@body", t.vm.initStringObject(body))
+
+	return res
+}
+
+// httpRequestOptions reads the `params:`, `headers:` and `body:` options of a request
+func (t *thread) httpRequestOptions(h *HashObject) (httpRequestOptions, *Error) {
+	var opts httpRequestOptions
+
+	for k, v := range h.Pairs {
+		switch k {
+		case "params":
+			params, ok := v.(*HashObject)
+			if !ok {
+				return opts, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, v.Class().Name)
+			}
+
+			opts.params = url.Values{}
+
+			for name, value := range params.Pairs {
+				values := []Object{value}
+				if arr, ok := value.(*ArrayObject); ok {
+					values = arr.Elements
+				}
+
+				for _, elem := range values {
+					s, e := t.objectToString(elem)
+					if e != nil {
+						return opts, e
+					}
+
+					opts.params.Add(name, s)
+				}
+			}
+		case "headers":
+			headers, e := t.httpHeaders(v)
+			if e != nil {
+				return opts, e
+			}
+
+			opts.headers = headers
+		case "body":
+			body, ok := v.(*StringObject)
+			if !ok {
+				return opts, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, v.Class().Name)
+			}
+
+			opts.body = body
+		default:
+			return opts, t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+		}
+	}
+
+	return opts, nil
+}
+
+// loadClientTLSFiles loads the certificate authorities and the client certificate into the config
+func (t *thread) loadClientTLSFiles(config *tls.Config, files httpClientTLSFiles) *Error {
+	if caFile, ok := files["ca_file"]; ok {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return t.initErrorObject(errors.InternalError, err.Error())
+		}
+
+		config.RootCAs = pool
+	}
+
+	certFile, hasCert := files["cert_file"]
+	keyFile, hasKey := files["key_file"]
+
+	if hasCert != hasKey {
+		return t.initErrorObject(errors.ArgumentError, "Expect cert_file and key_file to be given together")
+	}
+
+	if hasCert {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return t.initErrorObject(errors.InternalError, err.Error())
+		}
+
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return nil
+}
+
+// loadCertPool reads the PEM certificates in the file, it's shared by the client's `ca_file:` and the server's `client_ca_file`
+func loadCertPool(file string) (*x509.CertPool, error) {
+	content, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("no certificates found in %s", file)
+	}
+
+	return pool, nil
+}
+
+// httpHeaders converts a hash of strings into headers, the underscores in the names are replaced with dashes
+func (t *thread) httpHeaders(obj Object) (http.Header, *Error) {
+	h, ok := obj.(*HashObject)
+	if !ok {
+		return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, obj.Class().Name)
+	}
+
+	headers := http.Header{}
+
+	for k, v := range h.Pairs {
+		s, ok := v.(*StringObject)
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, v.Class().Name)
+		}
+
+		headers.Set(strings.Replace(k, "_", "-", -1), s.value)
+	}
+
+	return headers, nil
+}
@@ -0,0 +1,286 @@
+package vm
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func startHTTPClientTestServer() *httptest.Server {
+	m := http.NewServeMux()
+
+	m.HandleFunc("/echo", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		user, pass, _ := r.BasicAuth()
+
+		w.Header().Set("X-Reply", "ok")
+		w.Header().Add("X-Multi", "a")
+		w.Header().Add("X-Multi", "b")
+		fmt.Fprintf(w, "%s %s %s %s:%s %s", r.Method, r.URL.RawQuery, body, user, pass, strings.Join(r.Header["X-Test"], ","))
+	})
+
+	m.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Method == http.MethodPost {
+			body, _ := ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"content_type": "%s", "received": %s}`, r.Header.Get("Content-Type"), body)
+			return
+		}
+
+		fmt.Fprintf(w, `{"name": "stan", "accept": "%s"}`, r.Header.Get("Accept"))
+	})
+
+	m.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/echo", http.StatusFound)
+	})
+
+	m.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	})
+
+	m.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "not found", http.StatusNotFound)
+	})
+
+	return httptest.NewServer(m)
+}
+
+func TestHTTPClientRequestMethods(t *testing.T) {
+	ts := startHTTPClientTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Client.new.get("URL/echo").body`, "GET   : "},
+		{`Net::HTTP::Client.new.get("URL/echo", params: { page: 2, tags: ["a", "b"] }).body`, "GET page=2&tags=a&tags=b  : "},
+		{`Net::HTTP::Client.new.get("URL/echo?q=goby", params: { page: 2 }).body`, "GET page=2&q=goby  : "},
+		{`Net::HTTP::Client.new.post("URL/echo", "Hi").body`, "POST  Hi : "},
+		{`Net::HTTP::Client.new.put("URL/echo", "Hi").body`, "PUT  Hi : "},
+		{`Net::HTTP::Client.new.patch("URL/echo", "Hi", params: { id: 1 }).body`, "PATCH id=1 Hi : "},
+		{`Net::HTTP::Client.new.delete("URL/echo").body`, "DELETE   : "},
+		{`Net::HTTP::Client.new.post("URL/echo").body`, "POST   : "},
+		{`Net::HTTP::Client.new.head("URL/echo").body`, ""},
+		{`Net::HTTP::Client.new.head("URL/echo").get_header("X-Reply")`, "ok"},
+		{`Net::HTTP::Client.new.request("options", "URL/echo", body: "Hi").body`, "OPTIONS  Hi : "},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + strings.Replace(tt.input, "URL", ts.URL, -1)
+		evaluated := v.testEval(t, input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientOptions(t *testing.T) {
+	ts := startHTTPClientTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Client.new(headers: { x_test: "1" }).get("URL/echo").body`, "GET   : 1"},
+		{`Net::HTTP::Client.new(headers: { x_test: "1" }).get("URL/echo", headers: { X_Test: "2" }).body`, "GET   : 2"},
+		{`Net::HTTP::Client.new.basic_auth("stan", "secret").get("URL/echo").body`, "GET   stan:secret "},
+		{`Net::HTTP::Client.new.get("URL/redirect").body`, "GET   : "},
+		{`Net::HTTP::Client.new(follow_redirects: true).get("URL/redirect").status_code`, 200},
+		{`Net::HTTP::Client.new(follow_redirects: false).get("URL/redirect").status_code`, 302},
+		{`Net::HTTP::Client.new(follow_redirects: false).get("URL/redirect").get_header("Location")`, "/echo"},
+		{`
+		begin
+		  Net::HTTP::Client.new(timeout: 0.1).get("URL/slow")
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "request timed out after 100ms"},
+		{`Net::HTTP::Client.new(timeout: 2).get("URL/slow").status_code`, 200},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + strings.Replace(tt.input, "URL", ts.URL, -1)
+		evaluated := v.testEval(t, input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientResponse(t *testing.T) {
+	ts := startHTTPClientTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Client.new.get("URL/echo").class.name`, "Response"},
+		{`Net::HTTP::Client.new.get("URL/echo").status`, "200 OK"},
+		{`Net::HTTP::Client.new.get("URL/echo").status_code`, 200},
+		{`Net::HTTP::Client.new.get("URL/echo").protocol`, "HTTP/1.1"},
+		{`Net::HTTP::Client.new.get("URL/echo").headers["X-Multi"]`, "a, b"},
+		{`Net::HTTP::Client.new.get("URL/missing").status_code`, 404},
+		{`Net::HTTP::Client.new.get("URL/missing").body`, "not found\n"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + strings.Replace(tt.input, "URL", ts.URL, -1)
+		evaluated := v.testEval(t, input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientJSONMethods(t *testing.T) {
+	ts := startHTTPClientTestServer()
+	defer ts.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Client.new.get_json("URL/json")["name"]`, "stan"},
+		{`Net::HTTP::Client.new.get_json("URL/json")["accept"]`, "application/json"},
+		{`Net::HTTP::Client.new.post_json("URL/json", { name: "stan", age: 3 })["received"].to_s`, `{ age: 3, name: "stan" }`},
+		{`Net::HTTP::Client.new.post_json("URL/json", { name: "stan" })["content_type"]`, "application/json"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + strings.Replace(tt.input, "URL", ts.URL, -1)
+		evaluated := v.testEval(t, input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientMethodsFail(t *testing.T) {
+	ts := startHTTPClientTestServer()
+	defer ts.Close()
+
+	testsFail := []errorTestCase{
+		{`Net::HTTP::Client.new(1)`, "TypeError: Expect argument to be Hash. got: Integer", 2},
+		{`Net::HTTP::Client.new(retries: 1)`, "ArgumentError: Unknown keyword: retries", 2},
+		{`Net::HTTP::Client.new(timeout: "1")`, "TypeError: Expect argument to be Numeric. got: String", 2},
+		{`Net::HTTP::Client.new(headers: { x_test: 1 })`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`Net::HTTP::Client.new.basic_auth("stan")`, "ArgumentError: Expect 2 arguments. got: 1", 2},
+		{`Net::HTTP::Client.new.get`, "ArgumentError: Expect 1..2 arguments. got: 0", 2},
+		{`Net::HTTP::Client.new.get(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`Net::HTTP::Client.new.get("URL/echo", query: {})`, "ArgumentError: Unknown keyword: query", 2},
+		{`Net::HTTP::Client.new.post("URL/echo", 1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`Net::HTTP::Client.new.get_json("URL/missing")`, "InternalError: 404 Not Found", 2},
+		{`Net::HTTP::Client.new.get_json("URL/echo")`, "InternalError: Can't parse string GET   :  as json: invalid character 'G' looking for beginning of value", 2},
+		{`Net::HTTP::Client.new.post_json("URL/json")`, "ArgumentError: Expect 2..3 arguments. got: 1", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		input := "require \"net/http\"\n" + strings.Replace(tt.input, "URL", ts.URL, -1)
+		evaluated := v.testEval(t, input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func startHTTPClientTLSTestServer(t *testing.T, clientAuth tls.ClientAuthType) *httptest.Server {
+	cert, err := tls.LoadX509KeyPair("../test_fixtures/tls/server.pem", "../test_fixtures/tls/server-key.pem")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	pool, err := loadCertPool("../test_fixtures/tls/ca.pem")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) > 0 {
+			fmt.Fprint(w, "Hello, "+r.TLS.PeerCertificates[0].Subject.CommonName)
+			return
+		}
+
+		fmt.Fprint(w, "Hello, TLS")
+	}))
+	ts.TLS = &tls.Config{Certificates: []tls.Certificate{cert}, ClientCAs: pool, ClientAuth: clientAuth}
+	ts.StartTLS()
+
+	return ts
+}
+
+func TestHTTPClientTLSOptions(t *testing.T) {
+	ts := startHTTPClientTLSTestServer(t, tls.VerifyClientCertIfGiven)
+	defer ts.Close()
+
+	mtls := startHTTPClientTLSTestServer(t, tls.RequireAndVerifyClientCert)
+	defer mtls.Close()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Net::HTTP::Client.new(ca_file: "../test_fixtures/tls/ca.pem").get("URL").body`, "Hello, TLS"},
+		{`Net::HTTP::Client.new(skip_verify: true).get("URL").body`, "Hello, TLS"},
+		{`
+		client = Net::HTTP::Client.new(ca_file: "../test_fixtures/tls/ca.pem", cert_file: "../test_fixtures/tls/client.pem", key_file: "../test_fixtures/tls/client-key.pem")
+		client.get("MTLS_URL").body
+		`, "Hello, goby client"},
+		{`
+		begin
+		  Net::HTTP::Client.new.get("URL")
+		rescue InternalError => e
+		  e.message.include?("certificate")
+		end
+		`, true},
+		{`
+		begin
+		  Net::HTTP::Client.new(ca_file: "../test_fixtures/tls/ca.pem").get("MTLS_URL")
+		rescue InternalError => e
+		  e.message.include?("certificate")
+		end
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		input := strings.Replace(tt.input, "MTLS_URL", mtls.URL, -1)
+		input = "require \"net/http\"\n" + strings.Replace(input, "URL", ts.URL, -1)
+		evaluated := v.testEval(t, input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestHTTPClientTLSOptionsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Net::HTTP::Client.new(ca_file: 1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`Net::HTTP::Client.new(skip_verify: "true")`, "TypeError: Expect argument to be Boolean. got: String", 2},
+		{`Net::HTTP::Client.new(ca_file: "../test_fixtures/no_such_file.pem")`, "InternalError: open ../test_fixtures/no_such_file.pem: no such file or directory", 2},
+		{`Net::HTTP::Client.new(ca_file: "../test_fixtures/tls/server-key.pem")`, "InternalError: no certificates found in ../test_fixtures/tls/server-key.pem", 2},
+		{`Net::HTTP::Client.new(cert_file: "../test_fixtures/tls/client.pem")`, "ArgumentError: Expect cert_file and key_file to be given together", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, "require \"net/http\"\n"+tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
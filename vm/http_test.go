@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 )
 
 //chan parameter for blocking until server is prepared
@@ -28,6 +29,11 @@ func startTestServer(c chan bool) {
 
 	})
 
+	m.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+		fmt.Fprint(w, "Slow Hello World")
+	})
+
 	m.HandleFunc("/error", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(404)
 		fmt.Fprint(w, "oops")
@@ -65,6 +71,17 @@ func TestHTTPObject(t *testing.T) {
 
 		Net::HTTP.post("http://127.0.0.1:3000/index", "text/plain", "Hi Again")
 		`, "POST Hi Again"},
+		{`
+		require "net/http"
+
+		begin
+		  Timeout.timeout(0.1) do
+		    Net::HTTP.get("http://127.0.0.1:3000/slow")
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
 	}
 
 	//block until server is ready
@@ -1,10 +1,13 @@
 package vm
 
 import (
-	"fmt"
+	"encoding/json"
 	"github.com/goby-lang/goby/compiler/bytecode"
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
+	"math"
+	"math/big"
+	"strconv"
 	"strings"
 )
 
@@ -31,6 +34,7 @@ type instructionSet struct {
 	instructions []*instruction
 	filename     filename
 	argTypes     []int
+	argNames     []string
 }
 
 func (is *instructionSet) define(line int, a *action, params ...interface{}) *instruction {
@@ -53,6 +57,106 @@ var builtinActions = map[operationType]*action{
 			t.stack.push(&Pointer{Target: obj})
 		},
 	},
+	bytecode.DupN: {
+		name: bytecode.DupN,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			n := args[0].(int)
+			objs := make([]Object, n)
+
+			for i := range objs {
+				objs[i] = t.stack.Data[t.sp-n+i].Target
+			}
+
+			for _, obj := range objs {
+				t.stack.push(&Pointer{Target: obj})
+			}
+		},
+	},
+	bytecode.SetN: {
+		name: bytecode.SetN,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			// Sets the nth value below the top to the top's value
+			n := args[0].(int)
+			t.stack.set(t.sp-1-n, &Pointer{Target: t.stack.top().Target})
+		},
+	},
+	bytecode.CheckMatch: {
+		name: bytecode.CheckMatch,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			pattern := t.stack.pop().Target
+			subject := t.stack.pop().Target
+
+			result := t.sendMethod("===", pattern, subject)
+
+			if _, ok := result.(*Error); !ok {
+				result = toBooleanObject(isTruthy(result))
+			}
+
+			t.stack.push(&Pointer{Target: result})
+		},
+	},
+	bytecode.Deconstruct: {
+		name: bytecode.Deconstruct,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			subject := t.stack.pop().Target
+			arr, err := t.deconstruct(subject, args[0].(int), args[1].(int))
+
+			if err != nil {
+				t.stack.push(&Pointer{Target: err})
+				return
+			}
+
+			if arr == nil {
+				t.stack.push(&Pointer{Target: subject})
+				t.stack.push(&Pointer{Target: FALSE})
+				return
+			}
+
+			t.stack.push(&Pointer{Target: arr})
+			t.stack.push(&Pointer{Target: TRUE})
+		},
+	},
+	bytecode.DeconstructKeys: {
+		name: bytecode.DeconstructKeys,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			keys := []string{}
+
+			for _, arg := range args {
+				keys = append(keys, arg.(string))
+			}
+
+			subject := t.stack.pop().Target
+			h, err := t.deconstructKeys(subject, keys)
+
+			if err != nil {
+				t.stack.push(&Pointer{Target: err})
+				return
+			}
+
+			if h == nil {
+				t.stack.push(&Pointer{Target: subject})
+				t.stack.push(&Pointer{Target: FALSE})
+				return
+			}
+
+			t.stack.push(&Pointer{Target: h})
+			t.stack.push(&Pointer{Target: TRUE})
+		},
+	},
+	bytecode.NoMatchingPattern: {
+		name: bytecode.NoMatchingPattern,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			subject := t.stack.pop().Target
+			s, err := t.inspectObject(subject)
+
+			if err != nil {
+				t.stack.push(&Pointer{Target: err})
+				return
+			}
+
+			t.returnError(errors.NoMatchingPatternError, "%s", s)
+		},
+	},
 	bytecode.PutObject: {
 		name: bytecode.PutObject,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
@@ -64,21 +168,29 @@ var builtinActions = map[operationType]*action{
 		name: bytecode.GetConstant,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
 			constName := args[0].(string)
-			c := t.vm.lookupConstant(cf, constName)
+			c := t.vm.lookupConstant(t, cf, constName)
 
 			if c == nil {
-				err := t.vm.initErrorObject(errors.NameError, "uninitialized constant %s", constName)
+				// `Foo ||= x` reads the constant before it's initialized
+				if len(args) > 2 && args[2].(int) == 1 {
+					t.stack.push(&Pointer{Target: NULL})
+					return
+				}
+
+				err := t.initErrorObject(errors.NameError, "uninitialized constant %s", constName)
 				t.stack.push(&Pointer{Target: err})
 				return
 			}
 
-			c.isNamespace = args[1].(string) == "true"
+			// Push a new pointer so marking it as a namespace doesn't affect the constant's other references,
+			// like the receiver in `Foo.bar(Foo::Baz)`
+			isNamespace := args[1].(string) == "true"
 
 			if t.stack.top() != nil && t.stack.top().isNamespace {
 				t.stack.pop()
 			}
 
-			t.stack.push(c)
+			t.stack.push(&Pointer{Target: c.Target, isNamespace: isNamespace})
 		},
 	},
 	bytecode.GetLocal: {
@@ -111,11 +223,64 @@ var builtinActions = map[operationType]*action{
 			t.stack.push(p)
 		},
 	},
+	bytecode.GetGlobalVariable: {
+		name: bytecode.GetGlobalVariable,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			t.stack.push(&Pointer{Target: t.vm.globalVariable(args[0].(string))})
+		},
+	},
+	bytecode.SetGlobalVariable: {
+		name: bytecode.SetGlobalVariable,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			p := t.stack.pop()
+
+			if err := t.setGlobalVariable(args[0].(string), p.Target); err != nil {
+				t.stack.push(&Pointer{Target: err})
+				return
+			}
+
+			t.stack.push(&Pointer{Target: p.Target})
+		},
+	},
+	bytecode.GetClassVariable: {
+		name: bytecode.GetClassVariable,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			name := args[0].(string)
+			c := t.classVariableScope(cf)
+
+			v, ok := c.classVariable(name)
+
+			switch {
+			case ok:
+			case len(args) > 1 && args[1].(int) == 1:
+				v = NULL
+			default:
+				v = t.initErrorObject(errors.NameError, "uninitialized class variable %s in %s", name, c.Name)
+			}
+
+			t.stack.push(&Pointer{Target: v})
+		},
+	},
+	bytecode.SetClassVariable: {
+		name: bytecode.SetClassVariable,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			p := t.stack.pop()
+
+			t.classVariableScope(cf).setClassVariable(args[0].(string), p.Target)
+			t.stack.push(&Pointer{Target: p.Target})
+		},
+	},
 	bytecode.SetInstanceVariable: {
 		name: bytecode.SetInstanceVariable,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
 			variableName := args[0].(string)
 			p := t.stack.pop()
+
+			if err := t.checkFrozen(cf.self); err != nil {
+				t.stack.push(&Pointer{Target: err})
+				return
+			}
+
 			cf.self.instanceVariableSet(variableName, p.Target)
 
 			var obj Object
@@ -176,25 +341,71 @@ var builtinActions = map[operationType]*action{
 		name: bytecode.SetConstant,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
 			constName := args[0].(string)
-			c := t.vm.lookupConstant(cf, constName)
+			c := t.vm.lookupConstant(t, cf, constName)
 			v := t.stack.pop()
 
 			if c != nil {
-				err := t.vm.initErrorObject(errors.ConstantAlreadyInitializedError, "Constant %s already been initialized. Can't assign value to a constant twice.", constName)
+				err := t.initErrorObject(errors.ConstantAlreadyInitializedError, "Constant %s already been initialized. Can't assign value to a constant twice.", constName)
 				t.stack.push(&Pointer{Target: err})
 				return
 			}
 
 			cf.storeConstant(constName, v)
+			t.stack.push(&Pointer{Target: v.Target})
 		},
 	},
 	bytecode.NewRange: {
 		name: bytecode.NewRange,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
-			rangeEnd := t.stack.pop().Target.(*IntegerObject).value
-			rangeStart := t.stack.pop().Target.(*IntegerObject).value
+			rangeEnd := t.stack.pop().Target
+			rangeStart := t.stack.pop().Target
+			var r *RangeObject
+
+			switch start := rangeStart.(type) {
+			case *IntegerObject:
+				switch end := rangeEnd.(type) {
+				case *IntegerObject:
+					r = t.vm.initRangeObject(start.value, end.value)
+				case *FloatObject:
+					if math.IsInf(end.value, 1) {
+						r = t.vm.initEndlessRangeObject(start.value)
+					} else {
+						r = t.vm.initFloatRangeObject(float64(start.value), end.value)
+					}
+				}
+			case *FloatObject:
+				switch end := rangeEnd.(type) {
+				case *IntegerObject:
+					r = t.vm.initFloatRangeObject(start.value, float64(end.value))
+				case *FloatObject:
+					r = t.vm.initFloatRangeObject(start.value, end.value)
+				}
+			case *StringObject:
+				if end, ok := rangeEnd.(*StringObject); ok {
+					r = t.vm.initStringRangeObject(start.value, end.value)
+				}
+			}
+
+			if r == nil {
+				t.returnError(errors.ArgumentError, "Bad value for range: %s..%s", rangeStart.Class().Name, rangeEnd.Class().Name)
+				return
+			}
+
+			r.exclusive = args[0].(int) == 1
+			t.stack.push(&Pointer{Target: r})
+		},
+	},
+	bytecode.NewRegexp: {
+		name: bytecode.NewRegexp,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			re, err := t.vm.initRegexpObject(args[0].(string), args[1].(string))
 
-			t.stack.push(&Pointer{Target: t.vm.initRangeObject(rangeStart, rangeEnd)})
+			if err != nil {
+				t.returnError(errors.ArgumentError, "%s", err.Error())
+				return
+			}
+
+			t.stack.push(&Pointer{Target: re})
 		},
 	},
 	bytecode.NewArray: {
@@ -322,6 +533,42 @@ var builtinActions = map[operationType]*action{
 			cf.pc = args[0].(int)
 		},
 	},
+	bytecode.SetRescue: {
+		name: bytecode.SetRescue,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			ensure := len(args) > 1 && args[1] == bytecode.EnsureHandler
+			cf.setRescue(&rescueHandler{start: cf.pc, handler: args[0].(int), sp: t.sp, cfp: t.cfp, rescuedErrors: len(t.rescuedErrors), ensure: ensure})
+		},
+	},
+	bytecode.PopRescue: {
+		name: bytecode.PopRescue,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			cf.popRescue()
+		},
+	},
+	bytecode.EndRescue: {
+		name: bytecode.EndRescue,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			if len(t.rescuedErrors) > 0 {
+				t.rescuedErrors = t.rescuedErrors[:len(t.rescuedErrors)-1]
+			}
+		},
+	},
+	bytecode.Raise: {
+		name: bytecode.Raise,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			target := t.stack.pop().Target
+
+			// The ensure clause was executed by `break` or `return`, which keeps unwinding the frames
+			if g, ok := target.(*GoObject); ok {
+				panic(g.data)
+			}
+
+			err := target.(*Error)
+			err.rescued = false
+			t.stack.push(&Pointer{Target: err})
+		},
+	},
 	bytecode.PutSelf: {
 		name: bytecode.PutSelf,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
@@ -331,10 +578,23 @@ var builtinActions = map[operationType]*action{
 	bytecode.PutString: {
 		name: bytecode.PutString,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
-			object := t.vm.initObjectFromGoType(args[0])
+			object := t.vm.initStringObject(args[0].(string))
 			t.stack.push(&Pointer{Target: object})
 		},
 	},
+	bytecode.PutFloat: {
+		name: bytecode.PutFloat,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			value, _ := strconv.ParseFloat(args[0].(string), 64)
+			t.stack.push(&Pointer{Target: t.vm.initFloatObject(value)})
+		},
+	},
+	bytecode.PutSymbol: {
+		name: bytecode.PutSymbol,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			t.stack.push(&Pointer{Target: t.vm.initSymbolObject(args[0].(string))})
+		},
+	},
 	bytecode.PutNull: {
 		name: bytecode.PutNull,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
@@ -355,9 +615,19 @@ var builtinActions = map[operationType]*action{
 
 			method := &MethodObject{Name: methodName, argc: argCount, instructionSet: is, baseObj: &baseObj{class: t.vm.topLevelClass(classes.MethodClass)}}
 
+			// Methods defined after `private`, `protected` or `module_function` carry the visibility as the second argument
+			if len(args) > 1 {
+				method.visibility = args[1].(string)
+			}
+
 			v := t.stack.pop().Target
 			switch self := v.(type) {
 			case *RClass:
+				if method.visibility == bytecode.ModuleFunction {
+					self.defineModuleFunction(methodName, method)
+					return
+				}
+
 				self.Methods.set(methodName, method)
 			default:
 				self.Class().Methods.set(methodName, method)
@@ -378,10 +648,7 @@ var builtinActions = map[operationType]*action{
 			case *RClass:
 				v.SingletonClass().Methods.set(methodName, method)
 			default:
-				singletonClass := t.vm.createRClass(fmt.Sprintf("#<Class:#<%s:%s>>", v.Class().Name, v.id()))
-				singletonClass.Methods.set(methodName, method)
-				singletonClass.isSingleton = true
-				v.SetSingletonClass(singletonClass)
+				t.vm.singletonClassOf(v).Methods.set(methodName, method)
 			}
 		},
 	},
@@ -399,7 +666,7 @@ var builtinActions = map[operationType]*action{
 
 				if len(args) >= 2 {
 					superClassName := args[1].(string)
-					superClass := t.vm.lookupConstant(cf, superClassName)
+					superClass := t.vm.lookupConstant(t, cf, superClassName)
 					inheritedClass, ok := superClass.Target.(*RClass)
 
 					if !ok {
@@ -435,6 +702,13 @@ var builtinActions = map[operationType]*action{
 			methodName := args[0].(string)
 			argCount := args[1].(int)
 
+			// A proc passed with `&` is pushed after other arguments
+			var blockObject Object
+
+			if len(args) > 2 && args[2].(string) == "block:&" {
+				blockObject = t.stack.pop().Target
+			}
+
 			if arr, ok := t.stack.top().Target.(*ArrayObject); ok && arr.splat {
 				// Pop array
 				t.stack.pop()
@@ -452,13 +726,37 @@ var builtinActions = map[operationType]*action{
 			method = receiver.findMethod(methodName)
 
 			if method == nil {
-				err := t.vm.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%+v' for %+v", methodName, receiver.toString())
+				err := t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%+v' for %+v", methodName, receiver.toString())
 				t.stack.set(receiverPr, &Pointer{Target: err})
 				t.sp = argPr
 				return
 			}
 
-			blockFrame := t.retrieveBlock(cf, args)
+			if err := t.checkVisibility(cf.self, receiver, method, methodName); err != nil {
+				t.stack.set(receiverPr, &Pointer{Target: err})
+				t.sp = argPr
+				return
+			}
+
+			var blockFrame *callFrame
+			cfp := t.cfp
+
+			switch b := blockObject.(type) {
+			case nil:
+				blockFrame = t.retrieveBlock(cf, args)
+			case *ProcObject:
+				blockFrame = t.retrieveProcBlock(b)
+			case *NullObject:
+			default:
+				err := t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ProcClass, b.Class().Name)
+				t.stack.set(receiverPr, &Pointer{Target: err})
+				t.sp = argPr
+				return
+			}
+
+			if blockFrame != nil {
+				defer t.catchBreak(blockFrame, cfp, receiverPr)
+			}
 
 			switch m := method.(type) {
 			case *MethodObject:
@@ -507,17 +805,29 @@ var builtinActions = map[operationType]*action{
 				In this case the target frame is not first block frame we meet. It should be `bar`'s block.
 				And bar's frame is foo block frame's ep, so our target frame is ep's block frame.
 			*/
-			if cf.blockFrame.ep == cf.ep {
+			if cf.blockFrame.ep == cf.ep && cf.blockFrame.goBlock == nil {
 				blockFrame = cf.blockFrame.ep.blockFrame
 			}
 
+			blockArgs := make([]Object, argCount)
+
+			for i := range blockArgs {
+				blockArgs[i] = t.stack.Data[argPr+i].Target
+			}
+
+			if blockFrame.goBlock != nil {
+				t.stack.set(receiverPr, &Pointer{Target: blockFrame.goBlock(t, blockArgs)})
+				t.sp = receiverPr + 1
+				return
+			}
+
 			c := newCallFrame(blockFrame.instructionSet)
 			c.blockFrame = blockFrame
 			c.ep = blockFrame.ep
 			c.self = receiver
 
-			for i := 0; i < argCount; i++ {
-				c.locals[i] = t.stack.Data[argPr+i]
+			for i, arg := range blockFrame.blockArguments(blockArgs) {
+				c.insertLCL(i, 0, arg)
 			}
 
 			t.callFrameStack.push(c)
@@ -527,31 +837,123 @@ var builtinActions = map[operationType]*action{
 			t.sp = receiverPr + 1
 		},
 	},
-	bytecode.Leave: {
-		name: bytecode.Leave,
+	bytecode.InvokeSuper: {
+		name: bytecode.InvokeSuper,
 		operation: func(t *thread, cf *callFrame, args ...interface{}) {
-			//fmt.Println(t.callFrameStack.inspect())
-			//fmt.Println("Before leave--------------------------------")
-			cf = t.callFrameStack.pop()
-			cf.pc = len(cf.instructionSet.instructions)
-			//fmt.Println(t.callFrameStack.inspect())
+			argCount := args[0].(int)
 
-			/*
-				Remove top frame if it's a block frame
+			// A proc passed with `&` is pushed after other arguments
+			var blockObject Object
 
-				Block execution frame <- This was popped when executing leave
-				---------------------
-				Block frame           <- So this frame is useless
-				---------------------
-				Main frame
-			*/
-			topFrame := t.callFrameStack.top()
-			if topFrame != nil && topFrame.isBlock {
-				cf = t.callFrameStack.pop()
-				cf.pc = len(cf.instructionSet.instructions)
+			if len(args) > 2 && args[2].(string) == "block:&" {
+				blockObject = t.stack.pop().Target
+			}
+
+			argPr := t.sp - argCount
+			receiverPr := argPr - 1
+			mf := cf.methodFrame()
+
+			if mf == nil {
+				t.stack.set(receiverPr, &Pointer{Target: t.initErrorObject(errors.UndefinedMethodError, "super called outside of method")})
+				t.sp = argPr
+				return
+			}
+
+			receiver := mf.self
+			t.stack.set(receiverPr, &Pointer{Target: receiver})
+
+			if args[1].(string) == bytecode.SuperForwardArgs {
+				argCount = t.pushMethodArgs(mf)
+			}
+
+			method := superMethod(receiver, mf.method)
+
+			if method == nil {
+				err := t.initErrorObject(errors.UndefinedMethodError, "Superclass method '%s' not found for %s", mf.method.Name, receiver.toString())
+				t.stack.set(receiverPr, &Pointer{Target: err})
+				t.sp = argPr
+				return
+			}
+
+			// The block of current method is passed unless another one is given
+			blockFrame := mf.blockFrame
+			cfp := t.cfp
+
+			switch b := blockObject.(type) {
+			case nil:
+				if len(args) > 2 {
+					blockFrame = t.retrieveBlock(cf, args)
+				}
+			case *ProcObject:
+				blockFrame = t.retrieveProcBlock(b)
+			case *NullObject:
+				blockFrame = nil
+			default:
+				err := t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ProcClass, b.Class().Name)
+				t.stack.set(receiverPr, &Pointer{Target: err})
+				t.sp = argPr
+				return
+			}
+
+			if blockFrame != nil && blockFrame != mf.blockFrame {
+				defer t.catchBreak(blockFrame, cfp, receiverPr)
+			}
+
+			switch m := method.(type) {
+			case *MethodObject:
+				t.evalMethodObject(receiver, m, receiverPr, argCount, blockFrame)
+			case *BuiltinMethodObject:
+				t.evalBuiltinMethod(receiver, m, receiverPr, argCount, blockFrame)
+			}
+		},
+	},
+	bytecode.Break: {
+		name: bytecode.Break,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			value := t.stack.pop().Target
+			t.breakBlock(cf.blockFrame, value)
+		},
+	},
+	bytecode.Return: {
+		name: bytecode.Return,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			value := t.stack.pop().Target
+
+			if err := t.returnFromBlock(cf, value); err != nil {
+				t.stack.push(&Pointer{Target: err})
 			}
 		},
 	},
+	bytecode.Leave: {
+		name: bytecode.Leave,
+		operation: func(t *thread, cf *callFrame, args ...interface{}) {
+			t.leaveFrame()
+		},
+	},
+}
+
+// leaveFrame pops the frame being executed, the return value is left on the stack
+func (t *thread) leaveFrame() {
+	//fmt.Println(t.callFrameStack.inspect())
+	//fmt.Println("Before leave--------------------------------")
+	cf := t.callFrameStack.pop()
+	cf.pc = len(cf.instructionSet.instructions)
+	//fmt.Println(t.callFrameStack.inspect())
+
+	/*
+		Remove top frame if it's a block frame
+
+		Block execution frame <- This was popped when executing leave
+		---------------------
+		Block frame           <- So this frame is useless
+		---------------------
+		Main frame
+	*/
+	topFrame := t.callFrameStack.top()
+	if topFrame != nil && topFrame.isBlock {
+		cf = t.callFrameStack.pop()
+		cf.pc = len(cf.instructionSet.instructions)
+	}
 }
 
 func (vm *VM) initObjectFromGoType(value interface{}) Object {
@@ -565,7 +967,20 @@ func (vm *VM) initObjectFromGoType(value interface{}) Object {
 	case int32:
 		return vm.initIntegerObject(int(v))
 	case float64:
-		return vm.initIntegerObject(int(v))
+		return vm.initFloatObject(v)
+	case float32:
+		return vm.initFloatObject(float64(v))
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return vm.initIntegerObject(int(i))
+		}
+
+		if n, ok := new(big.Int).SetString(string(v), 10); ok {
+			return vm.initIntegerFromBigInt(n)
+		}
+
+		f, _ := v.Float64()
+		return vm.initFloatObject(f)
 	case []uint8:
 		bytes := []byte{}
 
@@ -74,6 +74,7 @@ func (it *instructionTranslator) transferInstructionSet(iss []*instructionSet, s
 	}
 
 	is.argTypes = set.ArgTypes()
+	is.argNames = set.ArgNames()
 
 	iss = append(iss, is)
 }
@@ -90,9 +91,11 @@ func (it *instructionTranslator) transferInstruction(is *instructionSet, i *byte
 	}
 
 	switch act {
-	case bytecode.PutString:
+	case bytecode.PutString, bytecode.PutFloat, bytecode.PutSymbol:
 		params = append(params, i.Params[0])
-	case bytecode.BranchUnless, bytecode.BranchIf, bytecode.Jump:
+	case bytecode.NewRegexp:
+		params = append(params, i.Params[0], i.Params[1])
+	case bytecode.BranchUnless, bytecode.BranchIf, bytecode.Jump, bytecode.SetRescue:
 		line, err := i.AnchorLine()
 
 		if err != nil {
@@ -100,6 +103,10 @@ func (it *instructionTranslator) transferInstruction(is *instructionSet, i *byte
 		}
 
 		params = append(params, line)
+
+		for _, param := range i.Params {
+			params = append(params, param)
+		}
 	default:
 		for _, param := range i.Params {
 			params = append(params, it.parseParam(param))
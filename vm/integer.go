@@ -2,6 +2,7 @@ package vm
 
 import (
 	"math"
+	"math/big"
 	"strconv"
 
 	"github.com/goby-lang/goby/vm/classes"
@@ -62,9 +63,11 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 	return []*BuiltinMethodObject{
 		{
 			// Returns the sum of self and another Integer.
+			// The result is promoted to BigInt if it overflows.
 			//
 			// ```Ruby
 			// 1 + 2 # => 3
+			// 9223372036854775807 + 1 # => 9223372036854775808
 			// ```
 			// @return [Integer]
 			Name: "+",
@@ -72,15 +75,20 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
 
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					switch right := args[0].(type) {
+					case *IntegerObject:
+						result := leftValue + right.value
+
+						if (result > leftValue) == (right.value > 0) {
+							return t.vm.initIntegerObject(result)
+						}
+					case *BigIntObject, *FloatObject, *RationalObject:
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
-					return t.vm.initIntegerObject(leftValue + rightValue)
+					return bigIntOperation(t, receiver, args, "+")
 				}
 			},
 		},
@@ -96,15 +104,19 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
 
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					switch right := args[0].(type) {
+					case *IntegerObject:
+						if right.value == 0 {
+							return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+						}
+
+						return t.vm.initIntegerObject(leftValue % right.value)
+					case *BigIntObject, *FloatObject, *RationalObject:
+						return bigIntOperation(t, receiver, args, "%")
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
-
-					rightValue := right.value
-					return t.vm.initIntegerObject(leftValue % rightValue)
 				}
 			},
 		},
@@ -120,20 +132,48 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
 
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					switch right := args[0].(type) {
+					case *IntegerObject:
+						result := leftValue - right.value
+
+						if (result < leftValue) == (right.value > 0) {
+							return t.vm.initIntegerObject(result)
+						}
+					case *BigIntObject, *FloatObject, *RationalObject:
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
-					return t.vm.initIntegerObject(leftValue - rightValue)
+					return bigIntOperation(t, receiver, args, "-")
+				}
+			},
+		},
+		{
+			// Returns the negation of self, which is what unary minus calls.
+			// The result is promoted to BigInt if it overflows.
+			//
+			// ```Ruby
+			// a = 5
+			// -a # => -5
+			// ```
+			// @return [Integer]
+			Name: "-@",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					value := receiver.(*IntegerObject).value
+
+					if value == math.MinInt64 {
+						return t.vm.initIntegerFromBigInt(new(big.Int).Neg(big.NewInt(int64(value))))
+					}
+
+					return t.vm.initIntegerObject(-value)
 				}
 			},
 		},
 		{
 			// Returns self multiplying another Integer.
+			// The result is promoted to BigInt if it overflows.
 			//
 			// ```Ruby
 			// 2 * 10 # => 20
@@ -144,45 +184,63 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
 
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					switch right := args[0].(type) {
+					case *IntegerObject:
+						result := leftValue * right.value
+
+						if leftValue == 0 || (result/leftValue == right.value && !(leftValue == -1 && right.value == minInt)) {
+							return t.vm.initIntegerObject(result)
+						}
+					case *BigIntObject, *FloatObject, *RationalObject:
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
-					return t.vm.initIntegerObject(leftValue * rightValue)
+					return bigIntOperation(t, receiver, args, "*")
 				}
 			},
 		},
 		{
 			// Returns self squaring another Integer.
+			// The result is promoted to BigInt if it overflows.
 			//
 			// ```Ruby
 			// 2 ** 8 # => 256
+			// 2 ** 64 # => 18446744073709551616
 			// ```
 			// @return [Integer]
 			Name: "**",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
+					switch args[0].(type) {
+					case *FloatObject, *RationalObject:
+						return floatOperation(t, receiver, args, "**")
+					}
+
 					leftValue := receiver.(*IntegerObject).value
 					right, ok := args[0].(*IntegerObject)
 
 					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
 					rightValue := right.value
-					result := math.Pow(float64(leftValue), float64(rightValue))
-					return t.vm.initIntegerObject(int(result))
+
+					if rightValue < 0 {
+						result := math.Pow(float64(leftValue), float64(rightValue))
+						return t.vm.initIntegerObject(int(result))
+					}
+
+					result := new(big.Int).Exp(big.NewInt(int64(leftValue)), big.NewInt(int64(rightValue)), nil)
+					return t.vm.initIntegerFromBigInt(result)
 				}
 			},
 		},
 		{
 			// Returns self divided by another Integer.
+			// Returns a ZeroDivisionError if the divisor is 0.
 			//
 			// ```Ruby
 			// 6 / 3 # => 2
@@ -193,15 +251,109 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
 
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					switch right := args[0].(type) {
+					case *IntegerObject:
+						if right.value == 0 {
+							return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+						}
+
+						if leftValue != minInt || right.value != -1 {
+							return t.vm.initIntegerObject(leftValue / right.value)
+						}
+					case *BigIntObject, *FloatObject, *RationalObject:
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
-					return t.vm.initIntegerObject(leftValue / rightValue)
+					return bigIntOperation(t, receiver, args, "/")
+				}
+			},
+		},
+		{
+			// Returns the bitwise AND of self and an Integer.
+			//
+			// ```Ruby
+			// 12 & 10 # => 8
+			// ```
+			// @return [Integer]
+			Name: "&",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "&")
+				}
+			},
+		},
+		{
+			// Returns the bitwise OR of self and an Integer.
+			//
+			// ```Ruby
+			// 12 | 10 # => 14
+			// ```
+			// @return [Integer]
+			Name: "|",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "|")
+				}
+			},
+		},
+		{
+			// Returns the bitwise exclusive OR of self and an Integer.
+			//
+			// ```Ruby
+			// 12 ^ 10 # => 6
+			// ```
+			// @return [Integer]
+			Name: "^",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "^")
+				}
+			},
+		},
+		{
+			// Returns the bitwise complement of self, which is -self - 1.
+			//
+			// ```Ruby
+			// ~12 # => -13
+			// ```
+			// @return [Integer]
+			Name: "~",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "~")
+				}
+			},
+		},
+		{
+			// Returns self shifted left by the given number of bits, or right if it's negative.
+			// The result is promoted to BigInt if it overflows.
+			//
+			// ```Ruby
+			// 1 << 4  # => 16
+			// 1 << 64 # => 18446744073709551616
+			// ```
+			// @return [Integer]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, "<<")
+				}
+			},
+		},
+		{
+			// Returns self shifted right by the given number of bits, or left if it's negative.
+			//
+			// ```Ruby
+			// 16 >> 2 # => 4
+			// -16 >> 2 # => -4
+			// ```
+			// @return [Integer]
+			Name: ">>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return bitwiseOperation(t, receiver, args, ">>")
 				}
 			},
 		},
@@ -217,17 +369,13 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
-
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
+					c, ok := compareNumbers(receiver, args[0])
 
-					if leftValue > rightValue {
+					if ok && c > 0 {
 						return TRUE
 					}
 
@@ -247,17 +395,13 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
-
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
+					c, ok := compareNumbers(receiver, args[0])
 
-					if leftValue >= rightValue {
+					if ok && c >= 0 {
 						return TRUE
 					}
 
@@ -277,17 +421,13 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
-
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
+					c, ok := compareNumbers(receiver, args[0])
 
-					if leftValue < rightValue {
+					if ok && c < 0 {
 						return TRUE
 					}
 
@@ -307,17 +447,13 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
-
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
+					c, ok := compareNumbers(receiver, args[0])
 
-					if leftValue <= rightValue {
+					if ok && c <= 0 {
 						return TRUE
 					}
 
@@ -338,24 +474,17 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
-
-					if !ok {
-						err := t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
-						return err
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
 					}
 
-					rightValue := right.value
+					c, ok := compareNumbers(receiver, args[0])
 
-					if leftValue < rightValue {
-						return t.vm.initIntegerObject(-1)
-					}
-					if leftValue > rightValue {
-						return t.vm.initIntegerObject(1)
+					if !ok {
+						return NULL
 					}
 
-					return t.vm.initIntegerObject(0)
+					return t.vm.initIntegerObject(c)
 				}
 			},
 		},
@@ -371,16 +500,9 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
-
-					if !ok {
-						return FALSE
-					}
+					c, ok := compareNumbers(receiver, args[0])
 
-					rightValue := right.value
-
-					if leftValue == rightValue {
+					if ok && c == 0 {
 						return TRUE
 					}
 
@@ -400,20 +522,85 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					leftValue := receiver.(*IntegerObject).value
-					right, ok := args[0].(*IntegerObject)
+					c, ok := compareNumbers(receiver, args[0])
 
-					if !ok {
-						return TRUE
+					if ok && c == 0 {
+						return FALSE
 					}
 
-					rightValue := right.value
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns if self is an Integer with the same value as the argument. Unlike `==`, a Float isn't
+			// `eql?` to an Integer, so they're different keys of Hash.
+			//
+			// ```Ruby
+			// 1.eql?(1)   # => true
+			// 1.eql?(1.0) # => false
+			// ```
+			// @return [Boolean]
+			Name: "eql?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
 
-					if leftValue != rightValue {
-						return TRUE
+					i, ok := args[0].(*IntegerObject)
+					return toBooleanObject(ok && i.value == receiver.(*IntegerObject).value)
+				}
+			},
+		},
+		{
+			// Returns the digits of self in the given base, which defaults to 10, from the least significant one.
+			// Returns a DomainError if self is negative.
+			//
+			// ```Ruby
+			// 1234.digits    # => [4, 3, 2, 1]
+			// 255.digits(16) # => [15, 15]
+			// 0.digits       # => [0]
+			// ```
+			// @return [Array]
+			Name: "digits",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerDigits(t, receiver, args)
+				}
+			},
+		},
+		{
+			// Yields the integers from self down to the limit, inclusively.
+			// Returns self, or an Enumerator of the integers when no block is given.
+			//
+			// ```Ruby
+			// s = ""
+			// 3.downto(1) do |i|
+			//   s = s + i.to_s
+			// end
+			// s                # => "321"
+			// 3.downto(1).to_a # => [3, 2, 1]
+			// ```
+			// @return [Integer]
+			Name: "downto",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
 					}
 
-					return FALSE
+					limit, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "downto", args)
+					}
+
+					return integerIteration(t, receiver, receiver.(*IntegerObject).value, limit.value, -1, blockFrame)
 				}
 			},
 		},
@@ -440,6 +627,50 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns the greatest common divisor of self and an Integer, which is always positive.
+			//
+			// ```Ruby
+			// 12.gcd(18)  # => 6
+			// -12.gcd(18) # => 6
+			// ```
+			// @return [Integer]
+			Name: "gcd",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerGcd(t, receiver, args, false)
+				}
+			},
+		},
+		{
+			// Returns the least common multiple of self and an Integer, which is always positive.
+			//
+			// ```Ruby
+			// 4.lcm(6) # => 12
+			// 4.lcm(0) # => 0
+			// ```
+			// @return [Integer]
+			Name: "lcm",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerGcd(t, receiver, args, true)
+				}
+			},
+		},
+		{
+			// Returns self converted to a Float.
+			//
+			// ```Ruby
+			// 100.to_f # => 100.0
+			// ```
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initFloatObject(float64(receiver.(*IntegerObject).value))
+				}
+			},
+		},
 		{
 			// Returns self.
 			//
@@ -455,19 +686,42 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns a `String` representation of self.
+			// Returns self as a Rational.
+			//
+			// ```Ruby
+			// 3.to_r # => 3/1
+			// ```
+			// @return [Rational]
+			Name: "to_r",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initRationalObject(new(big.Rat).SetInt64(int64(receiver.(*IntegerObject).value)))
+				}
+			},
+		},
+		{
+			// Returns a `String` representation of self in the given base, which defaults to 10 and must be
+			// between 2 and 36.
 			//
 			// ```Ruby
-			// 100.to_s # => "100"
+			// 100.to_s     # => "100"
+			// 255.to_s(2)  # => "11111111"
+			// 255.to_s(16) # => "ff"
+			// (-255).to_s(16) # => "-ff"
 			// ```
 			// @return [String]
 			Name: "to_s",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					base, err := parseRadix(t, args)
+
+					if err != nil {
+						return err
+					}
 
 					int := receiver.(*IntegerObject)
 
-					return t.vm.initStringObject(strconv.Itoa(int.value))
+					return t.vm.initStringObject(strconv.FormatInt(int64(int.value), base))
 				}
 			},
 		},
@@ -508,6 +762,24 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Returns self raised to the power of e, like `**`.
+			// With a modulus m it returns `(self ** e) % m` without calculating the full power, which
+			// keeps modular exponentiation fast for large numbers. The result has the same sign as m.
+			//
+			// ```Ruby
+			// 2.pow(10)       # => 1024
+			// 2.pow(10, 1000) # => 24
+			// 3.pow(1000, 7)  # => 4
+			// ```
+			// @return [Integer]
+			Name: "pow",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return integerPow(t, receiver, args)
+				}
+			},
+		},
 		{
 			// Returns self - 1.
 			//
@@ -524,33 +796,83 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Yields a block a number of times equals to self.
+			// Yields the integers from self to the limit by the given step, which defaults to 1.
+			// A negative step counts down. Returns self, or an Enumerator of the integers when no block is given.
+			//
+			// ```Ruby
+			// sum = 0
+			// 0.step(100, 5) do |i|
+			//   sum += i
+			// end
+			// sum                 # => 1050
+			// 1.step(10, 3).to_a  # => [1, 4, 7, 10]
+			// 10.step(1, -4).to_a # => [10, 6, 2]
+			// ```
+			// @return [Integer]
+			Name: "step",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+					}
+
+					limit, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					step := 1
+
+					if len(args) == 2 {
+						s, ok := args[1].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+						}
+
+						if s.value == 0 {
+							return t.initErrorObject(errors.ArgumentError, "Step can't be 0")
+						}
+
+						step = s.value
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "step", args)
+					}
+
+					return integerIteration(t, receiver, receiver.(*IntegerObject).value, limit.value, step, blockFrame)
+				}
+			},
+		},
+		{
+			// Yields a block a number of times equals to self, passing the integers from 0 to self - 1.
+			// Returns self, or an Enumerator of the integers when no block is given.
 			//
 			// ```Ruby
 			// a = 0
 			// 3.times do
 			//    a += 1
 			// end
-			// a # => 3
+			// a            # => 3
+			// 3.times.to_a # => [0, 1, 2]
 			// ```
+			// @return [Integer]
 			Name: "times",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					n := receiver.(*IntegerObject)
 
 					if n.value < 0 {
-						return t.vm.initErrorObject(errors.InternalError, "Expect integer greater than or equal 0. got: %d", n.value)
+						return t.initErrorObject(errors.InternalError, "Expect integer greater than or equal 0. got: %d", n.value)
 					}
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "times", args)
 					}
 
-					for i := 0; i < n.value; i++ {
-						t.builtinMethodYield(blockFrame, t.vm.initIntegerObject(i))
-					}
-
-					return n
+					return integerIteration(t, n, 0, n.value-1, 1, blockFrame)
 				}
 			},
 		},
@@ -686,6 +1008,40 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 				}
 			},
 		},
+		{
+			// Yields the integers from self up to the limit, inclusively.
+			// Returns self, or an Enumerator of the integers when no block is given.
+			//
+			// ```Ruby
+			// sum = 0
+			// 1.upto(10) do |i|
+			//   sum += i
+			// end
+			// sum            # => 55
+			// 1.upto(3).to_a # => [1, 2, 3]
+			// ```
+			// @return [Integer]
+			Name: "upto",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					limit, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "upto", args)
+					}
+
+					return integerIteration(t, receiver, receiver.(*IntegerObject).value, limit.value, 1, blockFrame)
+				}
+			},
+		},
 	}
 }
 
@@ -695,7 +1051,7 @@ func builtinIntegerInstanceMethods() []*BuiltinMethodObject {
 
 func (vm *VM) initIntegerObject(value int) *IntegerObject {
 	return &IntegerObject{
-		baseObj: &baseObj{class: vm.topLevelClass(classes.IntegerClass)},
+		baseObj: &baseObj{class: vm.topLevelClass(classes.IntegerClass), frozen: true},
 		value:   value,
 		flag:    i,
 	}
@@ -705,6 +1061,7 @@ func (vm *VM) initIntegerClass() *RClass {
 	ic := vm.initializeClass(classes.IntegerClass, false)
 	ic.setBuiltinMethods(builtinIntegerInstanceMethods(), false)
 	ic.setBuiltinMethods(builtinIntegerClassMethods(), true)
+	ic.include(vm.topLevelClass(classes.ComparableModule))
 	return ic
 }
 
@@ -729,3 +1086,166 @@ func (i *IntegerObject) toJSON() string {
 func (i *IntegerObject) equal(e *IntegerObject) bool {
 	return i.value == e.value
 }
+
+// parseRadix returns the optional base argument of Integer#to_s and String#to_i, it defaults to 10
+func parseRadix(t *thread, args []Object) (int, *Error) {
+	if len(args) > 1 {
+		return 0, t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+
+	if len(args) == 0 {
+		return 10, nil
+	}
+
+	b := args[0]
+	base, ok := b.(*IntegerObject)
+
+	if !ok {
+		return 0, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, b.Class().Name)
+	}
+
+	if base.value < 2 || base.value > 36 {
+		return 0, t.initErrorObject(errors.ArgumentError, "Invalid radix: %d", base.value)
+	}
+
+	return base.value, nil
+}
+
+// integerGcd returns the greatest common divisor of the receiver and the argument, or their least common
+// multiple if lcm is true
+func integerGcd(t *thread, receiver Object, args []Object, lcm bool) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	right, ok := toBigInt(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	left, _ := toBigInt(receiver)
+	gcd := new(big.Int).GCD(nil, nil, left, right)
+
+	if !lcm {
+		return t.vm.initIntegerFromBigInt(gcd)
+	}
+
+	if gcd.Sign() == 0 {
+		return t.vm.initIntegerObject(0)
+	}
+
+	result := new(big.Int).Mul(left, right)
+	result.Abs(result)
+
+	return t.vm.initIntegerFromBigInt(result.Quo(result, gcd))
+}
+
+// integerDigits returns the digits of the receiver in the optional base, from the least significant one
+func integerDigits(t *thread, receiver Object, args []Object) Object {
+	if len(args) > 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+
+	base := big.NewInt(10)
+
+	if len(args) == 1 {
+		b, ok := toBigInt(args[0])
+
+		if !ok {
+			return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		if b.Cmp(big.NewInt(2)) < 0 {
+			return t.initErrorObject(errors.ArgumentError, "Invalid radix: %s", b.String())
+		}
+
+		base = b
+	}
+
+	n, _ := toBigInt(receiver)
+
+	if n.Sign() < 0 {
+		return t.initErrorObject(errors.DomainError, errors.OutOfDomainFormat, "digits")
+	}
+
+	if n.Sign() == 0 {
+		return t.vm.initArrayObject([]Object{t.vm.initIntegerObject(0)})
+	}
+
+	var digits []Object
+	n = new(big.Int).Set(n)
+	digit := new(big.Int)
+
+	for n.Sign() > 0 {
+		n.QuoRem(n, base, digit)
+		digits = append(digits, t.vm.initIntegerFromBigInt(new(big.Int).Set(digit)))
+	}
+
+	return t.vm.initArrayObject(digits)
+}
+
+// integerPow returns the receiver raised to the power of the first argument, modulo the optional second
+// argument. The result of modular exponentiation has the same sign as the modulus.
+func integerPow(t *thread, receiver Object, args []Object) Object {
+	if len(args) < 1 || len(args) > 2 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+	}
+
+	if len(args) == 1 {
+		return t.sendMethod("**", receiver, args[0])
+	}
+
+	exponent, ok := toBigInt(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	modulus, ok := toBigInt(args[1])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+	}
+
+	if exponent.Sign() < 0 {
+		return t.initErrorObject(errors.ArgumentError, "Expect exponent to be non-negative with a modulus. got: %s", exponent.String())
+	}
+
+	if modulus.Sign() == 0 {
+		return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+	}
+
+	base, _ := toBigInt(receiver)
+	m := new(big.Int).Abs(modulus)
+	result := new(big.Int).Exp(new(big.Int).Mod(base, m), exponent, m)
+
+	if modulus.Sign() < 0 && result.Sign() != 0 {
+		result.Add(result, modulus)
+	}
+
+	return t.vm.initIntegerFromBigInt(result)
+}
+
+// integerIteration yields the integers from start to limit by step to the block and returns the receiver
+func integerIteration(t *thread, receiver Object, start, limit, step int, blockFrame *callFrame) Object {
+	if blockFrame.goBlock == nil && ((step > 0 && start > limit) || (step < 0 && start < limit)) {
+		// if block is not used, it should be popped, blocks implemented in Go aren't pushed
+		t.callFrameStack.pop()
+	}
+
+	for i := start; (step > 0 && i <= limit) || (step < 0 && i >= limit); {
+		t.builtinMethodYield(blockFrame, t.vm.initIntegerObject(i))
+
+		next := i + step
+
+		// Stop when the counter overflows
+		if (next < i) != (step < 0) {
+			break
+		}
+
+		i = next
+	}
+
+	return receiver
+}
@@ -65,6 +65,9 @@ func TestIntegerArithmeticOperationFail(t *testing.T) {
 		{`1 - "m"`, "TypeError: Expect argument to be Integer. got: String", 1},
 		{`1 ** "p"`, "TypeError: Expect argument to be Integer. got: String", 1},
 		{`1 / "t"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1 / 0`, "ZeroDivisionError: Divided by 0", 1},
+		{`1 % 0`, "ZeroDivisionError: Divided by 0", 1},
+		{`1 % "m"`, "TypeError: Expect argument to be Integer. got: String", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -76,6 +79,68 @@ func TestIntegerArithmeticOperationFail(t *testing.T) {
 	}
 }
 
+func TestIntegerBitwiseOperation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`12 & 10`, 8},
+		{`12 | 10`, 14},
+		{`12 ^ 10`, 6},
+		{`~12`, -13},
+		{`~-1`, 0},
+		{`-12 & 10`, 0},
+		{`-12 | 10`, -2},
+		{`1 << 4`, 16},
+		{`16 >> 2`, 4},
+		{`-16 >> 2`, -4},
+		{`-1 >> 100`, -1},
+		{`1 >> 64`, 0},
+		{`1 << -2`, 0},
+		{`4 >> -1`, 8},
+		{`(1 << 64).to_s`, "18446744073709551616"},
+		{`(1 << 64).class.name`, "BigInt"},
+		{`(1 << 62).class.name`, "Integer"},
+		{`(-1 << 63).class.name`, "Integer"},
+		{`(1 << 63).class.name`, "BigInt"},
+		{`1 | 2 & 3`, 3},
+		{`1 + 2 << 3`, 24},
+		{`6 & 3 == 2`, true},
+		{`
+		flags = 0
+		flags = flags | 4
+		flags = flags | 1
+		flags & 4 != 0 && flags & 2 == 0
+		`, true},
+		{`(1 | 18446744073709551616).to_s`, "18446744073709551617"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerBitwiseOperationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1 & "1"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`1 | 1.5`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`1 ^ nil`, "TypeError: Expect argument to be Integer. got: Null", 1},
+		{`1 << 1.5`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`1 << 18446744073709551616`, "ArgumentError: Shift width too big: 18446744073709551616", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
 func TestIntegerComparison(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -146,6 +211,15 @@ func TestIntegerConversion(t *testing.T) {
 	}{
 		{`100.to_i`, 100},
 		{`100.to_s`, "100"},
+		{`255.to_s(2)`, "11111111"},
+		{`255.to_s(16)`, "ff"},
+		{`(-255).to_s(16)`, "-ff"},
+		{`35.to_s(36)`, "z"},
+		{`0xff`, 255},
+		{`0XFF + 1`, 256},
+		{`0b1010`, 10},
+		{`0o755`, 493},
+		{`0xff.to_s(2)`, "11111111"},
 	}
 
 	for i, tt := range tests {
@@ -157,6 +231,83 @@ func TestIntegerConversion(t *testing.T) {
 	}
 }
 
+func TestIntegerConversionFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`100.to_s(1)`, "ArgumentError: Invalid radix: 1", 1},
+		{`100.to_s(37)`, "ArgumentError: Invalid radix: 37", 1},
+		{`100.to_s("2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`100.to_s(2, 8)`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`"100".to_i(0)`, "ArgumentError: Invalid radix: 0", 1},
+		{`"100".to_i(nil)`, "TypeError: Expect argument to be Integer. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerNumberTheoryMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`12.gcd(18)`, 6},
+		{`(-12).gcd(18)`, 6},
+		{`12.gcd(0)`, 12},
+		{`0.gcd(0)`, 0},
+		{`4.lcm(6)`, 12},
+		{`(-4).lcm(6)`, 12},
+		{`4.lcm(0)`, 0},
+		{`(9223372036854775807.lcm(2)).to_s`, "18446744073709551614"},
+		{`1234.digits.to_s`, "[4, 3, 2, 1]"},
+		{`255.digits(16).to_s`, "[15, 15]"},
+		{`0.digits.to_s`, "[0]"},
+		{`2.pow(10)`, 1024},
+		{`2.pow(0.5)`, 1.4142135623730951},
+		{`2.pow(10, 1000)`, 24},
+		{`3.pow(1000, 7)`, 4},
+		{`3.pow(2, -5)`, -1},
+		{`(-3).pow(3, 5)`, 3},
+		{`2.pow(1, 1)`, 0},
+		{`2.pow(18446744073709551616, 7)`, 2},
+		{`4.pow(13, 497)`, 445},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerNumberTheoryMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`12.gcd`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`12.gcd(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`12.lcm("6")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`(-1).digits`, `DomainError: Numerical argument is out of domain - "digits"`, 1},
+		{`10.digits(1)`, "ArgumentError: Invalid radix: 1", 1},
+		{`10.digits(10, 2)`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`2.pow`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`2.pow(3, 0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`2.pow(-1, 5)`, "ArgumentError: Expect exponent to be non-negative with a modulus. got: -1", 1},
+		{`2.pow(3, 1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
 func TestIntegerEvenMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -240,6 +391,93 @@ func TestIntegerTimesMethod(t *testing.T) {
 			end
 			a
 			`, 3},
+		{`	a = 0
+			3.times do |i|
+				a += i
+			end
+			`, 3},
+		{`	a = 0
+			0.times do
+				a += 1
+			end
+			a
+			`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerIterationMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []interface{}
+	}{
+		{`3.times.to_a`, []interface{}{0, 1, 2}},
+		{`0.times.to_a`, []interface{}{}},
+		{`1.upto(3).to_a`, []interface{}{1, 2, 3}},
+		{`3.upto(1).to_a`, []interface{}{}},
+		{`	sum = 0
+			r = 1.upto(10) do |i|
+				sum += i
+			end
+			[r, sum]
+			`, []interface{}{1, 55}},
+		{`3.downto(1).to_a`, []interface{}{3, 2, 1}},
+		{`1.downto(3).to_a`, []interface{}{}},
+		{`	s = ""
+			r = 3.downto(1) do |i|
+				s = s + i.to_s
+			end
+			[r, s]
+			`, []interface{}{3, "321"}},
+		{`1.step(10, 3).to_a`, []interface{}{1, 4, 7, 10}},
+		{`10.step(1, -4).to_a`, []interface{}{10, 6, 2}},
+		{`1.step(3).to_a`, []interface{}{1, 2, 3}},
+		{`1.step(3, -1).to_a`, []interface{}{}},
+		{`	sum = 0
+			r = 0.step(100, 5) do |i|
+				sum += i
+			end
+			[r, sum]
+			`, []interface{}{0, 1050}},
+		{`9223372036854775806.upto(9223372036854775807).to_a`, []interface{}{9223372036854775806, 9223372036854775807}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		testArrayObject(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIntegerIterationMethodsWithoutBlock(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`3.times.class.name`, "Enumerator"},
+		{`1.upto(3).class.name`, "Enumerator"},
+		{`3.downto(1).class.name`, "Enumerator"},
+		{`1.step(10, 3).class.name`, "Enumerator"},
+		{`3.times.map do |i| i * 2 end.to_s`, "[0, 2, 4]"},
+		{`1.upto(3).with_index.to_a.to_s`, "[[1, 0], [2, 1], [3, 2]]"},
+		{`3.downto(1).select do |i| i.odd? end.to_s`, "[3, 1]"},
+		{`0.step(100, 5).lazy.map do |i| i * i end.first(3).to_s`, "[0, 25, 100]"},
+		{`
+		sum = 0
+		r = 3.times.each do |i|
+		  sum += i
+		end
+		[r, sum].to_s
+		`, "[3, 3]"},
 	}
 
 	for i, tt := range tests {
@@ -251,10 +489,29 @@ func TestIntegerTimesMethod(t *testing.T) {
 	}
 }
 
+func TestIntegerIterationMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1.upto`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`1.upto("3")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`3.downto(1, 2)`, "ArgumentError: Expect 1 argument. got=2", 1},
+		{`3.downto(nil)`, "TypeError: Expect argument to be Integer. got: Null", 1},
+		{`1.step`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`1.step(10, 0)`, "ArgumentError: Step can't be 0", 1},
+		{`1.step(10, 1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestIntegerTimesMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`(-2).times`, "InternalError: Expect integer greater than or equal 0. got: -2", 1},
-		{`2.times`, "InternalError: Can't yield without a block", 1},
 	}
 
 	for i, tt := range testsFail {
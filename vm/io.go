@@ -0,0 +1,381 @@
+package vm
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// IO is the superclass of File, its instances `STDIN`, `STDOUT` and `STDERR` represent the standard streams.
+// Reading methods like `gets` share a buffer per object, so they can be mixed to write interactive programs
+// or filters for pipes.
+//
+// ```ruby
+// STDOUT.print("What's your name? ")
+// name = STDIN.gets.chomp
+// STDOUT.puts("Hello, " + name)
+//
+// STDIN.each_line do |line|
+//   STDERR.write(line.upcase)
+// end
+// ```
+//
+// `Kernel#gets` and `Kernel#print` read from `$stdin` and write into `$stdout`, like `puts`.
+
+// Instance methods -----------------------------------------------------
+func builtinIOInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Closes the stream.
+			//
+			// ```ruby
+			// f = File.new("loop.gb")
+			// f.close # => nil
+			// ```
+			//
+			// @return [Null]
+			Name: "close",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					file := receiver.(*FileObject).File
+					file.Close()
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Yields each line of the stream, from the current position, to the block. Each line keeps its
+			// trailing newline. Returns an enumerator if no block is given.
+			//
+			// ```ruby
+			// STDIN.each_line do |line|
+			//   puts(line.upcase)
+			// end
+			//
+			// File.open("names.txt") do |f|
+			//   f.each_line.to_a # => ["Stan\n", "Goby\n"]
+			// end
+			// ```
+			//
+			// @return [IO]
+			Name: "each_line",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_line", args)
+					}
+
+					if err := t.eachLine(receiver.(*FileObject).bufferedReader(), blockFrame); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Reads the next line from the stream, including the trailing newline. Returns nil at the end of the stream.
+			//
+			// ```ruby
+			// STDIN.gets # => "Goby\n"
+			// STDIN.gets # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "gets",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.readLine(receiver.(*FileObject).bufferedReader())
+				}
+			},
+		},
+		{
+			// Writes the given objects into the stream without adding newlines.
+			//
+			// ```ruby
+			// STDOUT.print("foo", 1) # => foo1
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Null]
+			Name: "print",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.print(receiver.(*FileObject).File, args)
+				}
+			},
+		},
+		{
+			// Writes each of the given objects into the stream, followed by a newline.
+			//
+			// ```ruby
+			// STDERR.puts("foo", "bar")
+			// # => foo
+			// # => bar
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Null]
+			Name: "puts",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.puts(receiver.(*FileObject).File, args)
+				}
+			},
+		},
+		{
+			// Reads the rest of the stream.
+			//
+			// ```ruby
+			// STDIN.read # => "Stan\nGoby\n"
+			// ```
+			//
+			// @return [String]
+			Name: "read",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					content, err := ioutil.ReadAll(receiver.(*FileObject).bufferedReader())
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initStringObject(string(content))
+				}
+			},
+		},
+		{
+			// Writes the given objects into the stream and returns the number of bytes written.
+			//
+			// ```ruby
+			// STDOUT.write("Goby", "\n") # => 5
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Integer]
+			Name: "write",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.write(receiver.(*FileObject).File, args)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initIOObject(f *os.File) *FileObject {
+	return &FileObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.IOClass)},
+		File:    f,
+	}
+}
+
+func (vm *VM) initIOClass() *RClass {
+	ic := vm.initializeClass(classes.IOClass, false)
+	ic.setBuiltinMethods(builtinIOInstanceMethods(), false)
+	return ic
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// bufferedReader returns the reader which all the reading methods of the object share
+func (f *FileObject) bufferedReader() *bufio.Reader {
+	if f.reader == nil {
+		f.reader = bufio.NewReader(f.File)
+	}
+
+	return f.reader
+}
+
+// Other helper functions -----------------------------------------------
+
+// readLine reads the next line from the reader, it returns nil at the end of the stream.
+func (t *thread) readLine(r *bufio.Reader) Object {
+	line, err := r.ReadString('\n')
+
+	if err != nil && err != io.EOF {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	if len(line) == 0 {
+		return NULL
+	}
+
+	return t.vm.initStringObject(line)
+}
+
+// eachLine yields each line from the reader to the block until the end of the stream
+func (t *thread) eachLine(r *bufio.Reader, blockFrame *callFrame) *Error {
+	for {
+		line := t.readLine(r)
+
+		if err, ok := line.(*Error); ok {
+			return err
+		}
+
+		if line == NULL {
+			return nil
+		}
+
+		t.builtinMethodYield(blockFrame, line)
+	}
+}
+
+// write writes the string of each object into w and returns the number of bytes written,
+// it's shared by `IO#write` and `TCPSocket#write`.
+func (t *thread) write(w io.Writer, args []Object) Object {
+	length := 0
+
+	for _, arg := range args {
+		s, e := t.objectToString(arg)
+		if e != nil {
+			return e
+		}
+
+		n, err := io.WriteString(w, s)
+		length += n
+
+		if err != nil {
+			return t.initErrorObject(errors.InternalError, err.Error())
+		}
+	}
+
+	return t.vm.initIntegerObject(length)
+}
+
+// print writes the string of each object into w, it's shared by `Kernel#print` and `IO#print`.
+func (t *thread) print(w io.Writer, args []Object) Object {
+	for _, arg := range args {
+		s, err := t.objectToString(arg)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprint(w, s)
+	}
+
+	return NULL
+}
+
+// puts writes the string of each object into w, followed by a newline. It's shared by `Kernel#puts` and `IO#puts`.
+func (t *thread) puts(w io.Writer, args []Object) Object {
+	for _, arg := range args {
+		s, err := t.objectToString(arg)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, s)
+	}
+
+	return NULL
+}
+
+// p writes the string of each object formatted by inspect into w, followed by a newline. It returns nil for no
+// objects, the object for one object, and an Array of the objects otherwise. It's shared by `Kernel#p` and `Kernel#pp`.
+func (t *thread) p(w io.Writer, args []Object, inspect inspector) Object {
+	for _, arg := range args {
+		s, err := inspect(arg)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(w, s)
+	}
+
+	switch len(args) {
+	case 0:
+		return NULL
+	case 1:
+		return args[0]
+	default:
+		return t.vm.initArrayObject(args)
+	}
+}
+
+// ppWidth is the width `pp` fits the output in before breaking arrays and hashes into lines
+const ppWidth = 80
+
+// prettyInspect is like inspectObject, but arrays and hashes that don't fit in ppWidth are broken into lines with
+// their elements indented
+func (t *thread) prettyInspect(obj Object) (string, *Error) {
+	return t.prettyInspectAt(obj, "", 0)
+}
+
+// prettyInspectAt formats the object which starts at the column of the line, nested lines start with indent
+func (t *thread) prettyInspectAt(obj Object, indent string, column int) (string, *Error) {
+	s, err := t.inspectObject(obj)
+	if err != nil || column+len(s) <= ppWidth || userDefinedMethod(obj, "inspect") != nil || userDefinedMethod(obj, "to_s") != nil {
+		return s, err
+	}
+
+	inner := indent + "  "
+	var lines []string
+
+	switch o := obj.(type) {
+	case *ArrayObject:
+		if len(o.Elements) == 0 {
+			return s, nil
+		}
+
+		for _, el := range o.Elements {
+			line, err := t.prettyInspectAt(el, inner, len(inner))
+			if err != nil {
+				return "", err
+			}
+
+			lines = append(lines, inner+line)
+		}
+
+		return "[\n" + strings.Join(lines, ",\n") + "\n" + indent + "]", nil
+	case *HashObject:
+		if o.length() == 0 {
+			return s, nil
+		}
+
+		for _, k := range o.sortedKeys() {
+			key := k + ": "
+			if keyObj, ok := o.keyObjects[k]; ok {
+				ks, err := t.inspectObject(keyObj)
+				if err != nil {
+					return "", err
+				}
+
+				key = ks + " => "
+			}
+
+			value, err := t.prettyInspectAt(o.Pairs[k], inner, len(inner)+len(key))
+			if err != nil {
+				return "", err
+			}
+
+			lines = append(lines, inner+key+value)
+		}
+
+		return "{\n" + strings.Join(lines, ",\n") + "\n" + indent + "}", nil
+	}
+
+	return s, nil
+}
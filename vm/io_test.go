@@ -0,0 +1,169 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestIOObject(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`STDIN.class.name`, "IO"},
+		{`STDOUT.to_s`, "<IO: /dev/stdout>"},
+		{`File.superclass.name`, "IO"},
+		{`File.new("../test_fixtures/file_test/size.gb").is_a?(IO)`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIOReadingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		File.write("/tmp/in.txt", "Stan\nGoby")
+		$stdin = File.new("/tmp/in.txt")
+		gets + gets
+		`, "Stan\nGoby"},
+		{`
+		File.write("/tmp/in.txt", "Stan\n")
+		$stdin = File.new("/tmp/in.txt")
+		gets
+		gets
+		`, nil},
+		{`
+		File.write("/tmp/in.txt", "Stan\nGoby\nRuby\n")
+		f = File.new("/tmp/in.txt")
+		f.gets
+		f.read
+		`, "Goby\nRuby\n"},
+		{`
+		File.write("/tmp/in.txt", "Stan\nGoby\nRuby\n")
+		f = File.new("/tmp/in.txt")
+		f.gets
+		lines = []
+		f.each_line do |line|
+		  lines.push(line.chomp)
+		end
+		lines.join(",")
+		`, "Goby,Ruby"},
+		{`
+		File.write("/tmp/in.txt", "")
+		f = File.new("/tmp/in.txt")
+		f.gets
+		`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIOWritingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		File.open("/tmp/out.txt", "w") do |f|
+		  f.print("Goby", 1)
+		  f.puts("", "Stan")
+		  f.write(:Ruby, "\n")
+		end
+		File.read("/tmp/out.txt")
+		`, "Goby1\nStan\nRuby\n"},
+		{`
+		File.open("/tmp/out.txt", "w") do |f|
+		  f.write("Goby", "\n")
+		end
+		`, 5},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		print("Goby", 1)
+		puts(" Stan")
+		print
+		File.read("/tmp/out.txt")
+		`, "Goby1 Stan\n"},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		p("Goby", [1, "a"], { a: nil })
+		p
+		File.read("/tmp/out.txt")
+		`, "\"Goby\"\n[1, \"a\"]\n{ a: nil }\n"},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		p(1 + 1)
+		`, 2},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		p(1, 2).to_s
+		`, "[1, 2]"},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		p
+		`, nil},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		pp({ a: [1, 2] }, [])
+		File.read("/tmp/out.txt")
+		`, "{ a: [1, 2] }\n[]\n"},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		pp({ name: "goby", tags: ["ruby-like", "concurrent"], authors: [{ name: "st0012", since: 2016 }] })
+		File.read("/tmp/out.txt")
+		`, "{\n  authors: [{ name: \"st0012\", since: 2016 }],\n  name: \"goby\",\n  tags: [\"ruby-like\", \"concurrent\"]\n}\n"},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		pp([["aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbb", "cccccccccccccccccccccccccccccc"], 1])
+		File.read("/tmp/out.txt")
+		`, "[\n  [\n    \"aaaaaaaaaaaaaaaaaaaaaaaaaaaaaa\",\n    \"bbbbbbbbbbbbbbbbbbbbbbbbbbbbbb\",\n    \"cccccccccccccccccccccccccccccc\"\n  ],\n  1\n]\n"},
+		{`
+		$stdout = File.new("/tmp/out.txt", "w")
+		pp(1, 2).to_s
+		`, "[1, 2]"},
+		{`
+		File.open("/tmp/out.txt", "w") do |f|
+		  f.print(1)
+		end
+		`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestIOMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`gets(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`STDIN.gets(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`STDIN.read(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`STDIN.each_line(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`$stdin = 1`, "TypeError: $stdin must be IO. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
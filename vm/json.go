@@ -2,7 +2,10 @@ package vm
 
 import (
 	"encoding/json"
+	"fmt"
+	"io"
 	"strconv"
+	"strings"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -18,39 +21,16 @@ func builtinJSONClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
 					j, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
-					var obj jsonObj
-					var objs []jsonObj
-
-					jsonString := j.value
-
-					err := json.Unmarshal([]byte(jsonString), &obj)
-
-					if err != nil {
-						err = json.Unmarshal([]byte(jsonString), &objs)
-
-						if err != nil {
-							return t.vm.initErrorObject(errors.InternalError, "Can't parse string %s as json: %s", jsonString, err.Error())
-						}
-
-						var objects []Object
-
-						for _, obj := range objs {
-							objects = append(objects, t.vm.convertJSONToHashObj(obj))
-						}
-
-						return t.vm.initArrayObject(objects)
-					}
-
-					return t.vm.convertJSONToHashObj(obj)
+					return t.parseJSON(j.value)
 				}
 			},
 		},
@@ -59,13 +39,13 @@ func builtinJSONClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
 					j, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
 					var obj jsonObj
@@ -108,6 +88,49 @@ func initJSONClass(vm *VM) {
 	vm.objectClass.setClassConstant(class)
 }
 
+// unmarshalJSON is like json.Unmarshal, but it keeps numbers as json.Number so integers don't become floats
+func unmarshalJSON(data string, v interface{}) error {
+	d := json.NewDecoder(strings.NewReader(data))
+	d.UseNumber()
+
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+
+	if _, err := d.Token(); err != io.EOF {
+		return fmt.Errorf("invalid data after top-level value")
+	}
+
+	return nil
+}
+
+// parseJSON converts the JSON object, or array of objects, into a hash or an array of hashes.
+// It's shared by `JSON.parse` and the HTTP client's JSON helpers.
+func (t *thread) parseJSON(jsonString string) Object {
+	var obj jsonObj
+	var objs []jsonObj
+
+	err := unmarshalJSON(jsonString, &obj)
+
+	if err != nil {
+		err = unmarshalJSON(jsonString, &objs)
+
+		if err != nil {
+			return t.initErrorObject(errors.InternalError, "Can't parse string %s as json: %s", jsonString, err.Error())
+		}
+
+		var objects []Object
+
+		for _, obj := range objs {
+			objects = append(objects, t.vm.convertJSONToHashObj(obj))
+		}
+
+		return t.vm.initArrayObject(objects)
+	}
+
+	return t.vm.convertJSONToHashObj(obj)
+}
+
 // Polymorphic helper functions -----------------------------------------
 func (v *VM) convertJSONToHashObj(j jsonObj) Object {
 	objectMap := map[string]Object{}
@@ -68,6 +68,14 @@ func TestJSONObjectParsing(t *testing.T) {
 		  }
 		')
 		h["Project"]["Months"]`, 7},
+		{`
+		require "json"
+		h = JSON.parse('{"Price": 2.5, "Stock": [1, 1.5]}')
+		h["Price"]`, 2.5},
+		{`
+		require "json"
+		h = JSON.parse('{"Price": 2.5, "Stock": [1, 1.5]}')
+		h["Stock"].to_s`, "[1, 1.5]"},
 	}
 
 	for i, tt := range tests {
@@ -0,0 +1,568 @@
+package vm
+
+import (
+	"encoding/json"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Marshal converts objects into strings and back, so they can be saved into files or sent to other programs.
+// Objects of the classes defined in Goby are dumped with their instance variables, and loaded without calling
+// `initialize`. Objects that are referenced more than once, including the ones that reference themselves,
+// are still shared by their references after loading.
+//
+// ```ruby
+// class User
+//   attr_reader :name, :friends
+//
+//   def initialize(name)
+//     @name = name
+//     @friends = []
+//   end
+// end
+//
+// stan = User.new("Stan")
+// stan.friends.push(stan)
+//
+// data = Marshal.dump(stan)
+// user = Marshal.load(data)
+// user.name                    # => "Stan"
+// user.friends[0] == user      # => true
+// ```
+//
+// A class can define `marshal_dump` to choose what to dump, then its objects are loaded by calling
+// `marshal_load` with the dumped data on a new object.
+//
+// The data is a JSON document, and the classes of the dumped objects should be defined before loading it.
+// Procs, methods, threads, channels, IOs and other objects that hold resources can't be dumped.
+
+// marshalVersion is the version of the data format, which is checked when loading
+const marshalVersion = 1
+
+// marshalData is the document of dumped data
+type marshalData struct {
+	Version int          `json:"version"`
+	Root    *marshalNode `json:"root"`
+}
+
+// marshalNode is a dumped object. Arrays, hashes and objects get an id, and are dumped as references
+// of the id if they appear again.
+type marshalNode struct {
+	Type string `json:"t"`
+	// Value is the value of scalars, the class of objects, or the id of references
+	Value string `json:"v,omitempty"`
+	ID    int    `json:"id,omitempty"`
+	// Elements are the elements of arrays, the keys and values of hashes, the bounds of ranges,
+	// or the dumped data of objects with `marshal_dump`
+	Elements []*marshalNode `json:"e,omitempty"`
+	// Names are the instance variables' names of objects, their values are in Elements
+	Names     []string `json:"n,omitempty"`
+	Exclusive bool     `json:"x,omitempty"`
+}
+
+// Class methods --------------------------------------------------------
+func builtinMarshalClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Converts the object into a String, which can be loaded by `Marshal.load`.
+			//
+			// ```ruby
+			// Marshal.dump({ name: "Stan", tags: [:admin] })
+			// ```
+			//
+			// @param object [Object]
+			// @return [String]
+			Name: "dump",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					d := &marshalDumper{t: t, ids: map[Object]int{}}
+					root, err := d.dump(args[0])
+					if err != nil {
+						return err
+					}
+
+					data, _ := json.Marshal(&marshalData{Version: marshalVersion, Root: root})
+
+					return t.vm.initStringObject(string(data))
+				}
+			},
+		},
+		{
+			// Converts the String returned by `Marshal.dump` back into an object.
+			//
+			// ```ruby
+			// h = Marshal.load(Marshal.dump({ name: "Stan" }))
+			// h["name"] # => "Stan"
+			// ```
+			//
+			// @param data [String]
+			// @return [Object]
+			Name: "load",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					s, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					var data marshalData
+
+					if err := json.Unmarshal([]byte(s.value), &data); err != nil || data.Root == nil {
+						return t.initErrorObject(errors.ArgumentError, "Invalid marshal data")
+					}
+
+					if data.Version != marshalVersion {
+						return t.initErrorObject(errors.ArgumentError, "Unsupported marshal version: %d", data.Version)
+					}
+
+					l := &marshalLoader{t: t, objects: map[int]Object{}}
+					obj, err := l.load(data.Root)
+					if err != nil {
+						return err
+					}
+
+					return obj
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initMarshalModule() *RClass {
+	m := vm.initializeClass(classes.MarshalModule, true)
+	m.setBuiltinMethods(builtinMarshalClassMethods(), true)
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// marshalDumper converts objects into nodes, and remembers the ids of the arrays, hashes and objects it has dumped
+type marshalDumper struct {
+	t   *thread
+	ids map[Object]int
+}
+
+func (d *marshalDumper) dump(obj Object) (*marshalNode, *Error) {
+	switch o := obj.(type) {
+	case *NullObject:
+		return &marshalNode{Type: "nil"}, nil
+	case *BooleanObject:
+		return &marshalNode{Type: strconv.FormatBool(o.value)}, nil
+	case *IntegerObject:
+		return &marshalNode{Type: "int", Value: strconv.Itoa(o.value)}, nil
+	case *BigIntObject:
+		return &marshalNode{Type: "bigint", Value: o.value.String()}, nil
+	case *FloatObject:
+		return &marshalNode{Type: "float", Value: strconv.FormatFloat(o.value, 'g', -1, 64)}, nil
+	case *RationalObject:
+		return &marshalNode{Type: "rational", Value: o.value.String()}, nil
+	case *StringObject:
+		return &marshalNode{Type: "string", Value: o.value}, nil
+	case *SymbolObject:
+		return &marshalNode{Type: "symbol", Value: o.value}, nil
+	case *TimeObject:
+		return &marshalNode{Type: "time", Value: o.value.Format(time.RFC3339Nano)}, nil
+	case *RangeObject:
+		return d.dumpRange(o), nil
+	case *RClass:
+		path, err := d.classPath(o)
+		if err != nil {
+			return nil, err
+		}
+
+		return &marshalNode{Type: "class", Value: path}, nil
+	}
+
+	if id, ok := d.ids[obj]; ok {
+		return &marshalNode{Type: "ref", Value: strconv.Itoa(id)}, nil
+	}
+
+	switch o := obj.(type) {
+	case *ArrayObject:
+		node := d.newNode("array", obj)
+
+		for _, e := range o.Elements {
+			en, err := d.dump(e)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Elements = append(node.Elements, en)
+		}
+
+		return node, nil
+	case *HashObject:
+		node := d.newNode("hash", obj)
+
+		for _, k := range o.sortedKeys() {
+			kn, err := d.dump(o.keyObject(d.t.vm, k))
+			if err != nil {
+				return nil, err
+			}
+
+			vn, err := d.dump(o.Pairs[k])
+			if err != nil {
+				return nil, err
+			}
+
+			node.Elements = append(node.Elements, kn, vn)
+		}
+
+		return node, nil
+	case *RObject:
+		path, err := d.classPath(o.class)
+		if err != nil {
+			return nil, err
+		}
+
+		if userDefinedMethod(o, "marshal_dump") != nil {
+			node := d.newNode("user", obj)
+			node.Value = path
+
+			result := d.t.sendMethod("marshal_dump", o)
+			if err, ok := result.(*Error); ok {
+				return nil, err
+			}
+
+			rn, err := d.dump(result)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Elements = []*marshalNode{rn}
+			return node, nil
+		}
+
+		node := d.newNode("object", obj)
+		node.Value = path
+
+		names := []string{}
+		for name := range o.InstanceVariables.store {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			v, _ := o.InstanceVariables.get(name)
+
+			vn, err := d.dump(v)
+			if err != nil {
+				return nil, err
+			}
+
+			node.Names = append(node.Names, name)
+			node.Elements = append(node.Elements, vn)
+		}
+
+		return node, nil
+	}
+
+	return nil, d.t.initErrorObject(errors.TypeError, "Can't dump %s", obj.Class().Name)
+}
+
+// newNode returns the node of an array, hash or object with a new id
+func (d *marshalDumper) newNode(nodeType string, obj Object) *marshalNode {
+	id := len(d.ids) + 1
+	d.ids[obj] = id
+
+	return &marshalNode{Type: nodeType, ID: id}
+}
+
+// dumpRange dumps the range's bounds, an endless range has a nil end
+func (d *marshalDumper) dumpRange(r *RangeObject) *marshalNode {
+	node := &marshalNode{Type: "range", Exclusive: r.exclusive}
+
+	switch {
+	case r.isString:
+		node.Elements = []*marshalNode{{Type: "string", Value: r.strStart}, {Type: "string", Value: r.strEnd}}
+	case r.isFloat:
+		node.Elements = []*marshalNode{
+			{Type: "float", Value: strconv.FormatFloat(r.floatStart, 'g', -1, 64)},
+			{Type: "float", Value: strconv.FormatFloat(r.floatEnd, 'g', -1, 64)},
+		}
+	case r.endless:
+		node.Elements = []*marshalNode{{Type: "int", Value: strconv.Itoa(r.Start)}, {Type: "nil"}}
+	default:
+		node.Elements = []*marshalNode{{Type: "int", Value: strconv.Itoa(r.Start)}, {Type: "int", Value: strconv.Itoa(r.End)}}
+	}
+
+	return node
+}
+
+// classPath returns the class's name with its namespaces like "Foo::Bar". Anonymous classes can't be dumped
+// because they can't be found by their names when loading.
+func (d *marshalDumper) classPath(c *RClass) (string, *Error) {
+	if c == d.t.vm.objectClass {
+		return classes.ObjectClass, nil
+	}
+
+	names := []string{}
+
+	for scope := c; scope != nil && scope != d.t.vm.objectClass; scope = scope.scope {
+		names = append([]string{scope.Name}, names...)
+	}
+
+	path := strings.Join(names, "::")
+
+	if c.isSingleton || d.t.vm.lookupClassPath(path) != c {
+		return "", d.t.initErrorObject(errors.TypeError, "Can't dump anonymous class %s", c.Name)
+	}
+
+	return path, nil
+}
+
+// marshalLoader converts nodes back into objects, and keeps the loaded arrays, hashes and objects by their ids
+type marshalLoader struct {
+	t       *thread
+	objects map[int]Object
+}
+
+func (l *marshalLoader) load(node *marshalNode) (Object, *Error) {
+	vm := l.t.vm
+
+	switch node.Type {
+	case "nil":
+		return NULL, nil
+	case "true":
+		return TRUE, nil
+	case "false":
+		return FALSE, nil
+	case "int":
+		i, err := strconv.Atoi(node.Value)
+		if err != nil {
+			return nil, l.invalid()
+		}
+
+		return vm.initIntegerObject(i), nil
+	case "bigint":
+		n, ok := new(big.Int).SetString(node.Value, 10)
+		if !ok {
+			return nil, l.invalid()
+		}
+
+		return vm.initIntegerFromBigInt(n), nil
+	case "float":
+		f, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil {
+			return nil, l.invalid()
+		}
+
+		return vm.initFloatObject(f), nil
+	case "rational":
+		r, ok := new(big.Rat).SetString(node.Value)
+		if !ok {
+			return nil, l.invalid()
+		}
+
+		return vm.initRationalObject(r), nil
+	case "string":
+		return vm.initStringObject(node.Value), nil
+	case "symbol":
+		return vm.initSymbolObject(node.Value), nil
+	case "time":
+		tm, err := time.Parse(time.RFC3339Nano, node.Value)
+		if err != nil {
+			return nil, l.invalid()
+		}
+
+		return vm.initTimeObject(tm), nil
+	case "range":
+		return l.loadRange(node)
+	case "class":
+		return l.lookupClass(node.Value)
+	case "ref":
+		id, _ := strconv.Atoi(node.Value)
+
+		obj, ok := l.objects[id]
+		if !ok {
+			return nil, l.invalid()
+		}
+
+		return obj, nil
+	case "array":
+		a := vm.initArrayObject([]Object{})
+		l.objects[node.ID] = a
+
+		for _, en := range node.Elements {
+			e, err := l.load(en)
+			if err != nil {
+				return nil, err
+			}
+
+			a.Elements = append(a.Elements, e)
+		}
+
+		return a, nil
+	case "hash":
+		if len(node.Elements)%2 != 0 {
+			return nil, l.invalid()
+		}
+
+		h := vm.initHashObject(map[string]Object{})
+		l.objects[node.ID] = h
+
+		for i := 0; i < len(node.Elements); i += 2 {
+			k, err := l.load(node.Elements[i])
+			if err != nil {
+				return nil, err
+			}
+
+			v, err := l.load(node.Elements[i+1])
+			if err != nil {
+				return nil, err
+			}
+
+			key, _, err := h.lookupKey(l.t, k)
+			if err != nil {
+				return nil, err
+			}
+
+			h.set(key, k, v)
+		}
+
+		return h, nil
+	case "object", "user":
+		c, err := l.lookupClass(node.Value)
+		if err != nil {
+			return nil, err
+		}
+
+		o := c.initializeInstance()
+		l.objects[node.ID] = o
+
+		if node.Type == "user" {
+			if len(node.Elements) != 1 {
+				return nil, l.invalid()
+			}
+
+			data, err := l.load(node.Elements[0])
+			if err != nil {
+				return nil, err
+			}
+
+			if result, ok := l.t.sendMethod("marshal_load", o, data).(*Error); ok {
+				return nil, result
+			}
+
+			return o, nil
+		}
+
+		if len(node.Names) != len(node.Elements) {
+			return nil, l.invalid()
+		}
+
+		for i, name := range node.Names {
+			v, err := l.load(node.Elements[i])
+			if err != nil {
+				return nil, err
+			}
+
+			o.InstanceVariables.set(name, v)
+		}
+
+		return o, nil
+	}
+
+	return nil, l.invalid()
+}
+
+// loadRange loads a range from its bounds, which are Integers, Floats or Strings, or an Integer and nil
+func (l *marshalLoader) loadRange(node *marshalNode) (Object, *Error) {
+	if len(node.Elements) != 2 {
+		return nil, l.invalid()
+	}
+
+	start, err := l.load(node.Elements[0])
+	if err != nil {
+		return nil, err
+	}
+
+	end, err := l.load(node.Elements[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var r *RangeObject
+
+	switch s := start.(type) {
+	case *IntegerObject:
+		switch e := end.(type) {
+		case *IntegerObject:
+			r = l.t.vm.initRangeObject(s.value, e.value)
+		case *NullObject:
+			r = l.t.vm.initEndlessRangeObject(s.value)
+		}
+	case *FloatObject:
+		if e, ok := end.(*FloatObject); ok {
+			r = l.t.vm.initFloatRangeObject(s.value, e.value)
+		}
+	case *StringObject:
+		if e, ok := end.(*StringObject); ok {
+			r = l.t.vm.initStringRangeObject(s.value, e.value)
+		}
+	}
+
+	if r == nil {
+		return nil, l.invalid()
+	}
+
+	r.exclusive = node.Exclusive
+	return r, nil
+}
+
+// lookupClass finds the class by its path like "Foo::Bar"
+func (l *marshalLoader) lookupClass(path string) (*RClass, *Error) {
+	c := l.t.vm.lookupClassPath(path)
+	if c == nil {
+		return nil, l.t.initErrorObject(errors.ArgumentError, "Undefined class/module %s", path)
+	}
+
+	return c, nil
+}
+
+func (l *marshalLoader) invalid() *Error {
+	return l.t.initErrorObject(errors.ArgumentError, "Invalid marshal data")
+}
+
+// lookupClassPath returns the class of the path like "Foo::Bar", or nil if it's not defined
+func (vm *VM) lookupClassPath(path string) *RClass {
+	c := vm.objectClass
+
+	if path == classes.ObjectClass {
+		return c
+	}
+
+	for _, name := range strings.Split(path, "::") {
+		ptr, ok := c.constants[name]
+		if !ok {
+			return nil
+		}
+
+		class, ok := ptr.Target.(*RClass)
+		if !ok {
+			return nil
+		}
+
+		c = class
+	}
+
+	return c
+}
@@ -0,0 +1,151 @@
+package vm
+
+import "testing"
+
+func TestMarshalRoundTrip(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Marshal.load(Marshal.dump(nil))`, nil},
+		{`Marshal.load(Marshal.dump(true))`, true},
+		{`Marshal.load(Marshal.dump(-42))`, -42},
+		{`Marshal.load(Marshal.dump(2.5))`, 2.5},
+		{`Marshal.load(Marshal.dump(Float::INFINITY)) == Float::INFINITY`, true},
+		{`Marshal.load(Marshal.dump(10**30)) == 10**30`, true},
+		{`Marshal.load(Marshal.dump("Hello\n世界"))`, "Hello\n世界"},
+		{`Marshal.load(Marshal.dump(:goby)) == :goby`, true},
+		{`Marshal.load(Marshal.dump([1, "a", [nil]])).to_s`, `[1, "a", [nil]]`},
+		{`Marshal.load(Marshal.dump({ a: 1, b: [2] })).to_s`, `{ a: 1, b: [2] }`},
+		{`
+		h = {}
+		h[[1]] = "one"
+		Marshal.load(Marshal.dump(h))[[1]]
+		`, "one"},
+		{`Marshal.load(Marshal.dump(1...5)).to_a.to_s`, "[1, 2, 3, 4]"},
+		{`Marshal.load(Marshal.dump("a".."c")).to_a.to_s`, `["a", "b", "c"]`},
+		{`Marshal.load(Marshal.dump(1..Float::INFINITY)).lazy.map do |i| i * 2 end.first(2).to_s`, "[2, 4]"},
+		{`Marshal.load(Marshal.dump(Time.at(1.5))).to_f`, 1.5},
+		{`Marshal.load(Marshal.dump(Integer)) == Integer`, true},
+		{`
+		a = [1]
+		b = Marshal.load(Marshal.dump([a, a]))
+		b[0].push(2)
+		b.to_s
+		`, "[[1, 2], [1, 2]]"},
+		{`
+		a = []
+		a.push(a)
+		b = Marshal.load(Marshal.dump(a))
+		b[0].push(1)
+		b.length
+		`, 2},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMarshalUserDefinedObjects(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class User
+		  attr_reader :name, :friends
+
+		  def initialize(name)
+		    @name = name
+		    @friends = []
+		  end
+		end
+
+		stan = User.new("Stan")
+		stan.friends.push(stan)
+		u = Marshal.load(Marshal.dump(stan))
+		[u.class.name, u.name, u.friends[0] == u, u == stan].to_s
+		`, `["User", "Stan", true, false]`},
+		{`
+		class Counter
+		  attr_reader :value
+
+		  def initialize
+		    $initialized = ($initialized || 0) + 1
+		    @value = 1
+		  end
+		end
+
+		c = Marshal.load(Marshal.dump(Counter.new))
+		[$initialized, c.value].to_s
+		`, "[1, 1]"},
+		{`
+		module Shop
+		  class Item
+		    attr_reader :price
+
+		    def initialize(price)
+		      @price = price
+		    end
+		  end
+		end
+
+		item = Marshal.load(Marshal.dump([Shop::Item.new(3)]))[0]
+		item.price
+		`, 3},
+		{`
+		class Secret
+		  attr_reader :value
+
+		  def initialize(value)
+		    @value = value
+		  end
+
+		  def marshal_dump
+		    [@value * 2]
+		  end
+
+		  def marshal_load(data)
+		    @value = data[0] / 2
+		  end
+		end
+
+		data = Marshal.dump(Secret.new(21))
+		[data.include?("42"), Marshal.load(data).value].to_s
+		`, "[true, 21]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMarshalMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Marshal.dump`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`Marshal.dump(-> { 1 })`, "TypeError: Can't dump Proc", 1},
+		{`Marshal.dump([1, { a: Channel.new }])`, "TypeError: Can't dump Channel", 1},
+		{`Marshal.load(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Marshal.load("goby")`, "ArgumentError: Invalid marshal data", 1},
+		{`Marshal.load("{\"version\":2,\"root\":{\"t\":\"nil\"}}")`, "ArgumentError: Unsupported marshal version: 2", 1},
+		{`Marshal.load("{\"version\":1,\"root\":{\"t\":\"ref\",\"v\":\"3\"}}")`, "ArgumentError: Invalid marshal data", 1},
+		{`Marshal.load("{\"version\":1,\"root\":{\"t\":\"object\",\"v\":\"Nope\",\"id\":1}}")`, "ArgumentError: Undefined class/module Nope", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
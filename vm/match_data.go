@@ -0,0 +1,297 @@
+package vm
+
+import (
+	"strconv"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// MatchDataObject represents the result of a successful Regexp match
+// It's returned by `String#match` and `Regexp#match`, and contains the matched string
+// as well as all captured groups.
+//
+// ```ruby
+// m = "Goby 0.1.0".match(/(?<major>\d+)\.(?<minor>\d+)/)
+// m[0]        # => "0.1"
+// m[1]        # => "0"
+// m["minor"]  # => "1"
+// m.pre_match # => "Goby "
+// ```
+//
+// **Note:**
+//
+// - `MatchData.new` is not supported.
+type MatchDataObject struct {
+	*baseObj
+	regexp *RegexpObject
+	str    string
+	// indexes holds byte offset pairs of the whole match and each group, unmatched groups have -1
+	indexes []int
+}
+
+// Class methods --------------------------------------------------------
+func builtinMatchDataClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinMatchDataInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the matched string of given group index or group name, which can be a String or a Symbol.
+			// Index 0 is the whole match. Returns nil if the group didn't participate in the match.
+			//
+			// ```ruby
+			// m = "Goby Lang".match(/(?<name>\w+) (\w+)/)
+			// m[0]      # => "Goby Lang"
+			// m[2]      # => "Lang"
+			// m["name"] # => "Goby"
+			// m[:name]  # => "Goby"
+			// m[3]      # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "[]",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					m := receiver.(*MatchDataObject)
+
+					if name, ok := stringOrSymbol(args[0]); ok {
+						index := m.regexp.regexp.SubexpIndex(name)
+
+						if index < 0 {
+							return t.initErrorObject(errors.ArgumentError, "Undefined group name: %s", name)
+						}
+
+						return m.group(t, index)
+					}
+
+					switch i := args[0].(type) {
+					case *IntegerObject:
+						index := i.value
+
+						if index < 0 {
+							index += m.groupCount()
+						}
+
+						if index < 0 || index >= m.groupCount() {
+							return NULL
+						}
+
+						return m.group(t, index)
+					default:
+						return t.initErrorObject(errors.TypeError, "Expect index to be Integer, String or Symbol. got: %s", i.Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Returns an array of the captured groups, without the whole match
+			//
+			// ```ruby
+			// "Goby Lang".match(/(\w+) (\w+)/).captures # => ["Goby", "Lang"]
+			// ```
+			//
+			// @return [Array]
+			Name: "captures",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*MatchDataObject)
+					elems := []Object{}
+
+					for i := 1; i < m.groupCount(); i++ {
+						elems = append(elems, m.group(t, i))
+					}
+
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
+		{
+			// Returns the number of elements in the match array, which is the whole match plus captured groups
+			//
+			// ```ruby
+			// "Goby Lang".match(/(\w+) (\w+)/).length # => 3
+			// ```
+			//
+			// @return [Integer]
+			Name: "length",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*MatchDataObject).groupCount())
+				}
+			},
+		},
+		{
+			// Returns a hash of named groups and their captured strings
+			//
+			// ```ruby
+			// m = "2017-09".match(/(?<year>\d+)-(?<month>\d+)/)
+			// m.named_captures # => { year: "2017", month: "09" }
+			// ```
+			//
+			// @return [Hash]
+			Name: "named_captures",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*MatchDataObject)
+					pairs := map[string]Object{}
+
+					for i, name := range m.regexp.regexp.SubexpNames() {
+						if name != "" {
+							pairs[name] = m.group(t, i)
+						}
+					}
+
+					return t.vm.initHashObject(pairs)
+				}
+			},
+		},
+		{
+			// Returns the part of the string after the match
+			//
+			// ```ruby
+			// "Hello Goby Lang".match(/Goby/).post_match # => " Lang"
+			// ```
+			//
+			// @return [String]
+			Name: "post_match",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*MatchDataObject)
+					return t.vm.initStringObject(m.str[m.indexes[1]:])
+				}
+			},
+		},
+		{
+			// Returns the part of the string before the match
+			//
+			// ```ruby
+			// "Hello Goby Lang".match(/Goby/).pre_match # => "Hello "
+			// ```
+			//
+			// @return [String]
+			Name: "pre_match",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*MatchDataObject)
+					return t.vm.initStringObject(m.str[:m.indexes[0]])
+				}
+			},
+		},
+		{
+			// Returns the number of elements in the match array, which is the whole match plus captured groups
+			//
+			// ```ruby
+			// "Goby Lang".match(/(\w+) (\w+)/).size # => 3
+			// ```
+			//
+			// @return [Integer]
+			Name: "size",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*MatchDataObject).groupCount())
+				}
+			},
+		},
+		{
+			// Returns the match array, which is the whole match followed by captured groups
+			//
+			// ```ruby
+			// "Goby Lang".match(/(\w+) (\w+)/).to_a # => ["Goby Lang", "Goby", "Lang"]
+			// ```
+			//
+			// @return [Array]
+			Name: "to_a",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					m := receiver.(*MatchDataObject)
+					elems := []Object{}
+
+					for i := 0; i < m.groupCount(); i++ {
+						elems = append(elems, m.group(t, i))
+					}
+
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
+		{
+			// Returns the whole matched string
+			//
+			// ```ruby
+			// "Goby Lang".match(/G\w+/).to_s # => "Goby"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initMatchDataObject(re *RegexpObject, str string, indexes []int) *MatchDataObject {
+	return &MatchDataObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.MatchDataClass)},
+		regexp:  re,
+		str:     str,
+		indexes: indexes,
+	}
+}
+
+func (vm *VM) initMatchDataClass() *RClass {
+	mc := vm.initializeClass(classes.MatchDataClass, false)
+	mc.setBuiltinMethods(builtinMatchDataInstanceMethods(), false)
+	mc.setBuiltinMethods(builtinMatchDataClassMethods(), true)
+	return mc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the whole matched string
+func (m *MatchDataObject) toString() string {
+	return m.str[m.indexes[0]:m.indexes[1]]
+}
+
+// Returns the whole matched string as a JSON string
+func (m *MatchDataObject) toJSON() string {
+	return strconv.Quote(m.toString())
+}
+
+// groupCount returns the number of groups including the whole match
+func (m *MatchDataObject) groupCount() int {
+	return len(m.indexes) / 2
+}
+
+// group returns the captured string of given group index, or nil if the group is unmatched
+func (m *MatchDataObject) group(t *thread, i int) Object {
+	start, end := m.indexes[i*2], m.indexes[i*2+1]
+
+	if start < 0 {
+		return NULL
+	}
+
+	return t.vm.initStringObject(m.str[start:end])
+}
@@ -0,0 +1,311 @@
+package vm
+
+import (
+	"math"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Math is a module that provides basic trigonometric and transcendental functions, and the constants
+// `Math::PI` and `Math::E`.
+// All functions accept Integers, Floats and Rationals, and return Floats.
+//
+// ```ruby
+// Math.sqrt(16)      # => 4.0
+// Math.hypot(3, 4)   # => 5.0
+// Math.sin(Math::PI / 2) # => 1.0
+// Math.log(8, 2)     # => 3.0
+// ```
+//
+// Functions that are called with an argument outside of their domain, like `Math.sqrt(-1)`, return
+// a DomainError.
+
+// Class methods --------------------------------------------------------
+func builtinMathClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the cosine of x, which is in radians.
+			//
+			// ```ruby
+			// Math.cos(0)       # => 1.0
+			// Math.cos(Math::PI) # => -1.0
+			// ```
+			//
+			// @return [Float]
+			Name: "cos",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initFloatObject(math.Cos(x[0]))
+				}
+			},
+		},
+		{
+			// Returns e raised to the power of x.
+			//
+			// ```ruby
+			// Math.exp(0) # => 1.0
+			// Math.exp(1) # => 2.718281828459045
+			// ```
+			//
+			// @return [Float]
+			Name: "exp",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initFloatObject(math.Exp(x[0]))
+				}
+			},
+		},
+		{
+			// Returns the length of the hypotenuse of a right triangle with sides x and y.
+			//
+			// ```ruby
+			// Math.hypot(3, 4) # => 5.0
+			// ```
+			//
+			// @return [Float]
+			Name: "hypot",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 2)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initFloatObject(math.Hypot(x[0], x[1]))
+				}
+			},
+		},
+		{
+			// Returns the logarithm of x in the given base, which defaults to e.
+			//
+			// ```ruby
+			// Math.log(1)       # => 0.0
+			// Math.log(Math::E) # => 1.0
+			// Math.log(8, 2)    # => 3.0
+			// ```
+			//
+			// @return [Float]
+			Name: "log",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+					}
+
+					x, err := floatArguments(t, args, len(args))
+
+					if err != nil {
+						return err
+					}
+
+					for _, v := range x {
+						if v < 0 {
+							return t.initErrorObject(errors.DomainError, errors.OutOfDomainFormat, "log")
+						}
+					}
+
+					if len(x) == 2 {
+						return t.vm.initFloatObject(math.Log(x[0]) / math.Log(x[1]))
+					}
+
+					return t.vm.initFloatObject(math.Log(x[0]))
+				}
+			},
+		},
+		{
+			// Returns the base 10 logarithm of x.
+			//
+			// ```ruby
+			// Math.log10(1000) # => 3.0
+			// ```
+			//
+			// @return [Float]
+			Name: "log10",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					if x[0] < 0 {
+						return t.initErrorObject(errors.DomainError, errors.OutOfDomainFormat, "log10")
+					}
+
+					return t.vm.initFloatObject(math.Log10(x[0]))
+				}
+			},
+		},
+		{
+			// Returns the base 2 logarithm of x.
+			//
+			// ```ruby
+			// Math.log2(8) # => 3.0
+			// ```
+			//
+			// @return [Float]
+			Name: "log2",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					if x[0] < 0 {
+						return t.initErrorObject(errors.DomainError, errors.OutOfDomainFormat, "log2")
+					}
+
+					return t.vm.initFloatObject(math.Log2(x[0]))
+				}
+			},
+		},
+		{
+			// Returns x raised to the power of y.
+			//
+			// ```ruby
+			// Math.pow(2, 10)  # => 1024.0
+			// Math.pow(4, 0.5) # => 2.0
+			// ```
+			//
+			// @return [Float]
+			Name: "pow",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 2)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initFloatObject(math.Pow(x[0], x[1]))
+				}
+			},
+		},
+		{
+			// Returns the sine of x, which is in radians.
+			//
+			// ```ruby
+			// Math.sin(0)            # => 0.0
+			// Math.sin(Math::PI / 2) # => 1.0
+			// ```
+			//
+			// @return [Float]
+			Name: "sin",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initFloatObject(math.Sin(x[0]))
+				}
+			},
+		},
+		{
+			// Returns the non-negative square root of x.
+			//
+			// ```ruby
+			// Math.sqrt(16) # => 4.0
+			// Math.sqrt(2)  # => 1.4142135623730951
+			// ```
+			//
+			// @return [Float]
+			Name: "sqrt",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					if x[0] < 0 {
+						return t.initErrorObject(errors.DomainError, errors.OutOfDomainFormat, "sqrt")
+					}
+
+					return t.vm.initFloatObject(math.Sqrt(x[0]))
+				}
+			},
+		},
+		{
+			// Returns the tangent of x, which is in radians.
+			//
+			// ```ruby
+			// Math.tan(0)            # => 0.0
+			// Math.tan(Math::PI / 4) # => 0.9999999999999999
+			// ```
+			//
+			// @return [Float]
+			Name: "tan",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					x, err := floatArguments(t, args, 1)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initFloatObject(math.Tan(x[0]))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initMathModule() *RClass {
+	m := vm.initializeClass(classes.MathModule, true)
+	m.setBuiltinMethods(builtinMathClassMethods(), true)
+	m.constants["PI"] = &Pointer{Target: vm.initFloatObject(math.Pi)}
+	m.constants["E"] = &Pointer{Target: vm.initFloatObject(math.E)}
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// floatArguments checks that there are count arguments and converts them to float64s
+func floatArguments(t *thread, args []Object, count int) ([]float64, *Error) {
+	if len(args) != count {
+		if count == 1 {
+			return nil, t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+		}
+
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect %d arguments. got=%d", count, len(args))
+	}
+
+	values := make([]float64, count)
+
+	for i, arg := range args {
+		f, ok := toFloat64(arg)
+
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.FloatClass, arg.Class().Name)
+		}
+
+		values[i] = f
+	}
+
+	return values, nil
+}
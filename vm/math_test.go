@@ -0,0 +1,70 @@
+package vm
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMathModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Math::PI`, math.Pi},
+		{`Math::E`, math.E},
+		{`Math.class.name`, "Class"},
+		{`Math.sqrt(16)`, 4.0},
+		{`Math.sqrt(2.25)`, 1.5},
+		{`Math.sqrt(1/4r)`, 0.5},
+		{`Math.sqrt(0)`, 0.0},
+		{`Math.sin(0)`, 0.0},
+		{`Math.sin(Math::PI / 2)`, 1.0},
+		{`Math.cos(0)`, 1.0},
+		{`Math.cos(Math::PI)`, -1.0},
+		{`Math.tan(0)`, 0.0},
+		{`Math.log(1)`, 0.0},
+		{`Math.log(Math::E)`, 1.0},
+		{`Math.log(8, 2)`, 3.0},
+		{`Math.log(0).infinite?`, -1},
+		{`Math.log2(8)`, 3.0},
+		{`Math.log10(1000)`, 3.0},
+		{`Math.exp(0)`, 1.0},
+		{`Math.exp(1)`, math.E},
+		{`Math.pow(2, 10)`, 1024.0},
+		{`Math.pow(4, 0.5)`, 2.0},
+		{`Math.hypot(3, 4)`, 5.0},
+		{`Math.hypot(3, 4).class.name`, "Float"},
+		{`Math.sqrt(18446744073709551616)`, 4294967296.0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMathModuleFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Math.sqrt(-1)`, `DomainError: Numerical argument is out of domain - "sqrt"`, 1},
+		{`Math.log(-1)`, `DomainError: Numerical argument is out of domain - "log"`, 1},
+		{`Math.log(8, -2)`, `DomainError: Numerical argument is out of domain - "log"`, 1},
+		{`Math.log2(-1)`, `DomainError: Numerical argument is out of domain - "log2"`, 1},
+		{`Math.log10(-1)`, `DomainError: Numerical argument is out of domain - "log10"`, 1},
+		{`Math.sqrt`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`Math.sin(1, 2)`, "ArgumentError: Expect 1 argument. got=2", 1},
+		{`Math.hypot(3)`, "ArgumentError: Expect 2 arguments. got=1", 1},
+		{`Math.log(1, 2, 3)`, "ArgumentError: Expect 1..2 arguments. got=3", 1},
+		{`Math.sqrt("16")`, "TypeError: Expect argument to be Float. got: String", 1},
+		{`Math.pow(2, nil)`, "TypeError: Expect argument to be Float. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	"github.com/goby-lang/goby/compiler/bytecode"
 	"github.com/goby-lang/goby/vm/classes"
 )
 
@@ -13,6 +14,7 @@ type MethodObject struct {
 	Name           string
 	instructionSet *instructionSet
 	argc           int
+	visibility     string
 }
 
 // Internal functions ===================================================
@@ -37,13 +39,78 @@ func (m *MethodObject) toJSON() string {
 	return m.toString()
 }
 
+// arity returns the number of the method's required parameters, where required keyword parameters count as one.
+// If the method also has optional or splat parameters, it returns -n-1, where n is the number of the required ones.
+func (m *MethodObject) arity() int {
+	required := 0
+	optional := false
+	requiredKeyword := false
+	optionalKeyword := false
+
+	for _, at := range m.argTypes() {
+		switch at {
+		case bytecode.NormalArg:
+			required++
+		case bytecode.OptionedArg, bytecode.SplatArg:
+			optional = true
+		case bytecode.RequiredKeywordArg:
+			requiredKeyword = true
+		case bytecode.OptionalKeywordArg:
+			optionalKeyword = true
+		}
+	}
+
+	if requiredKeyword {
+		required++
+	} else if optionalKeyword {
+		optional = true
+	}
+
+	if optional {
+		return -required - 1
+	}
+
+	return required
+}
+
 func (m *MethodObject) argTypes() []int {
 	return m.instructionSet.argTypes
 }
 
-func (m *MethodObject) lastArgType() int {
-	if len(m.argTypes()) > 0 {
-		return m.argTypes()[len(m.argTypes())-1]
+func (m *MethodObject) argNames() []string {
+	return m.instructionSet.argNames
+}
+
+// keywordArgCount returns the number of keyword parameters, which are defined after other parameters
+func (m *MethodObject) keywordArgCount() int {
+	count := 0
+
+	for _, at := range m.argTypes() {
+		if at == bytecode.RequiredKeywordArg || at == bytecode.OptionalKeywordArg {
+			count++
+		}
+	}
+
+	return count
+}
+
+// blockArgIndex returns the index of the block parameter like `&block`, or -1 if there's none
+func (m *MethodObject) blockArgIndex() int {
+	for i, at := range m.argTypes() {
+		if at == bytecode.BlockArg {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// splatArgIndex returns the index of the splat parameter, or -1 if there's none
+func (m *MethodObject) splatArgIndex() int {
+	for i, at := range m.argTypes() {
+		if at == bytecode.SplatArg {
+			return i
+		}
 	}
 
 	return -1
@@ -54,8 +121,9 @@ func (m *MethodObject) lastArgType() int {
 // BuiltinMethodObject represents methods defined in go.
 type BuiltinMethodObject struct {
 	*baseObj
-	Name string
-	Fn   func(receiver Object) builtinMethodBody
+	Name       string
+	Fn         func(receiver Object) builtinMethodBody
+	visibility string
 }
 
 type builtinMethodBody func(*thread, []Object, *callFrame) Object
@@ -71,3 +139,65 @@ func (bim *BuiltinMethodObject) toString() string {
 func (bim *BuiltinMethodObject) toJSON() string {
 	return bim.toString()
 }
+
+// Other helper functions -----------------------------------------------
+
+// methodVisibility returns the method's visibility, which is public unless it's changed by `private` or `protected`
+func methodVisibility(method Object) string {
+	var visibility string
+
+	switch m := method.(type) {
+	case *MethodObject:
+		visibility = m.visibility
+	case *BuiltinMethodObject:
+		visibility = m.visibility
+	}
+
+	if visibility == "" {
+		return bytecode.Public
+	}
+
+	return visibility
+}
+
+// withVisibility returns a copy of the method with the given visibility, so the inherited method isn't affected
+func withVisibility(method Object, visibility string) Object {
+	switch m := method.(type) {
+	case *MethodObject:
+		copied := *m
+		copied.visibility = visibility
+		return &copied
+	case *BuiltinMethodObject:
+		copied := *m
+		copied.visibility = visibility
+		return &copied
+	}
+
+	return method
+}
+
+// methodArity returns the arity of the method, which is -1 for builtin methods since their parameters are unknown
+func methodArity(method Object) int {
+	if m, ok := method.(*MethodObject); ok {
+		return m.arity()
+	}
+
+	return -1
+}
+
+// findMethodOwner is like Object.findMethod, but it also returns the class or module that defines the method
+func findMethodOwner(receiver Object, methodName string) (Object, *RClass) {
+	if c, ok := receiver.(*RClass); ok {
+		if c.isSingleton {
+			return c.superClass.lookupMethodOwner(methodName)
+		}
+
+		return c.SingletonClass().lookupMethodOwner(methodName)
+	}
+
+	if s := receiver.SingletonClass(); s != nil {
+		return s.lookupMethodOwner(methodName)
+	}
+
+	return receiver.Class().lookupMethodOwner(methodName)
+}
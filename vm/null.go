@@ -78,7 +78,7 @@ func builtinNullInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					if _, ok := args[0].(*NullObject); ok {
@@ -100,7 +100,7 @@ func builtinNullInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
 					}
 
 					if _, ok := args[0].(*NullObject); !ok {
@@ -122,7 +122,7 @@ func builtinNullInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
 					}
 					return TRUE
 				}
@@ -139,7 +139,7 @@ func (vm *VM) initNullClass() *RClass {
 	nc := vm.initializeClass(classes.NullClass, false)
 	nc.setBuiltinMethods(builtinNullInstanceMethods(), false)
 	nc.setBuiltinMethods(builtinNullClassMethods(), true)
-	NULL = &NullObject{baseObj: &baseObj{class: nc}}
+	NULL = &NullObject{baseObj: &baseObj{class: nc, frozen: true}}
 	return nc
 }
 
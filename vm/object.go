@@ -16,6 +16,8 @@ type Object interface {
 	id() int
 	instanceVariableGet(string) (Object, bool)
 	instanceVariableSet(string, Object) Object
+	isFrozen() bool
+	freeze()
 }
 
 // baseObj ==============================================================
@@ -24,6 +26,8 @@ type baseObj struct {
 	class             *RClass
 	singletonClass    *RClass
 	InstanceVariables *environment
+	// frozen objects can't be modified, see Object#freeze
+	frozen bool
 }
 
 // Polymorphic helper functions -----------------------------------------
@@ -62,6 +66,31 @@ func (b *baseObj) instanceVariableSet(name string, value Object) Object {
 	return value
 }
 
+// instanceVariables returns the environment of the object's instance variables
+func (b *baseObj) instanceVariables() *environment {
+	return b.InstanceVariables
+}
+
+func (b *baseObj) isFrozen() bool {
+	return b.frozen
+}
+
+func (b *baseObj) freeze() {
+	b.frozen = true
+}
+
+// copy returns a baseObj of the same class with the copied instance variables, it's not frozen and has no
+// singleton class
+func (b *baseObj) copy() *baseObj {
+	c := &baseObj{class: b.class}
+
+	if b.InstanceVariables != nil {
+		c.InstanceVariables = b.InstanceVariables.copy()
+	}
+
+	return c
+}
+
 func (b *baseObj) findMethod(methodName string) (method Object) {
 	if b.SingletonClass() != nil {
 		method = b.SingletonClass().lookupMethod(methodName)
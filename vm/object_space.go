@@ -0,0 +1,231 @@
+package vm
+
+import (
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// ObjectSpace is a module for inspecting the objects a program holds, which helps debugging memory usage.
+// Since Go's garbage collector owns the memory, it only sees the objects reachable from the program: constants,
+// global variables, and the values and local variables of the methods and blocks being executed.
+//
+// ```ruby
+// class Foo
+// end
+// FOOS = [Foo.new, Foo.new]
+//
+// ObjectSpace.each_object(Foo) do |foo|
+//   puts(foo)
+// end # => 2
+//
+// ObjectSpace.count_objects["Foo"] # => 2
+// ```
+
+// Class methods --------------------------------------------------------
+func builtinObjectSpaceClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a hash of the numbers of reachable objects by their class names, and the total number
+			// with the key "TOTAL".
+			//
+			// ```ruby
+			// ObjectSpace.count_objects["TOTAL"]  # => 1350
+			// ObjectSpace.count_objects["String"] # => 34
+			// ```
+			//
+			// @return [Hash]
+			Name: "count_objects",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					counts := map[string]int{}
+					total := 0
+
+					t.eachObject(func(obj Object) {
+						counts[obj.Class().Name]++
+						total++
+					})
+
+					pairs := map[string]Object{"TOTAL": t.vm.initIntegerObject(total)}
+
+					for name, count := range counts {
+						pairs[name] = t.vm.initIntegerObject(count)
+					}
+
+					return t.vm.initHashObject(pairs)
+				}
+			},
+		},
+		{
+			// Yields each reachable object that is an instance of the given class or module to the block,
+			// and returns the number of the objects. Without a class, every reachable object is yielded.
+			//
+			// ```ruby
+			// class Foo
+			// end
+			// foos = [Foo.new, Foo.new]
+			//
+			// ObjectSpace.each_object(Foo) do |foo|
+			//   puts(foo)
+			// end # => 2
+			// ```
+			//
+			// @param class [Class]
+			// @return [Integer]
+			Name: "each_object",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					var class *RClass
+
+					switch len(args) {
+					case 0:
+					case 1:
+						c, ok := args[0].(*RClass)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ClassClass, args[0].Class().Name)
+						}
+
+						class = c
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					objects := []Object{}
+
+					t.eachObject(func(obj Object) {
+						if class == nil || isKindOf(obj, class) {
+							objects = append(objects, obj)
+						}
+					})
+
+					for _, obj := range objects {
+						result := t.builtinMethodYield(blockFrame, obj)
+
+						if err, ok := result.Target.(*Error); ok && !err.rescued {
+							return err
+						}
+					}
+
+					return t.vm.initIntegerObject(len(objects))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initObjectSpaceModule() *RClass {
+	m := vm.initializeClass(classes.ObjectSpaceModule, true)
+	m.setBuiltinMethods(builtinObjectSpaceClassMethods(), true)
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// eachObject calls fn once with each object reachable from the roots: Object and its constants, global variables,
+// the main object, and the values on the thread's stack and in its call frames
+func (t *thread) eachObject(fn func(obj Object)) {
+	roots := []Object{t.vm.objectClass, t.vm.mainObj}
+
+	t.vm.globalVariables.Range(func(name, value interface{}) bool {
+		roots = append(roots, value.(Object))
+		return true
+	})
+
+	t.stack.RLock()
+	for _, p := range t.stack.Data[:t.sp] {
+		if p != nil {
+			roots = append(roots, p.Target)
+		}
+	}
+	t.stack.RUnlock()
+
+	for _, cf := range t.callFrameStack.callFrames[:t.cfp] {
+		if cf == nil {
+			continue
+		}
+
+		roots = append(roots, cf.self)
+
+		for _, p := range cf.locals {
+			if p != nil {
+				roots = append(roots, p.Target)
+			}
+		}
+	}
+
+	seen := map[Object]bool{}
+
+	for len(roots) > 0 {
+		obj := roots[len(roots)-1]
+		roots = roots[:len(roots)-1]
+
+		if obj == nil || seen[obj] {
+			continue
+		}
+
+		seen[obj] = true
+		fn(obj)
+		roots = append(roots, referencedObjects(obj)...)
+	}
+}
+
+// referencedObjects returns the objects the object holds: instance variables, elements of arrays and hashes,
+// and constants and class variables of classes
+func referencedObjects(obj Object) []Object {
+	refs := []Object{}
+
+	if b, ok := obj.(interface{ instanceVariables() *environment }); ok {
+		if env := b.instanceVariables(); env != nil {
+			for _, v := range env.store {
+				refs = append(refs, v)
+			}
+		}
+	}
+
+	switch o := obj.(type) {
+	case *ArrayObject:
+		refs = append(refs, o.Elements...)
+	case *HashObject:
+		for _, v := range o.Pairs {
+			refs = append(refs, v)
+		}
+
+		for _, k := range o.keyObjects {
+			refs = append(refs, k)
+		}
+	case *RClass:
+		for _, p := range o.constants {
+			refs = append(refs, p.Target)
+		}
+
+		for _, v := range o.classVariables {
+			refs = append(refs, v)
+		}
+	}
+
+	return refs
+}
+
+// isKindOf returns true if the object is an instance of the class, its subclasses, or the classes that include
+// the module
+func isKindOf(obj Object, class *RClass) bool {
+	for _, c := range obj.Class().ancestors() {
+		if c == class {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,149 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestObjectSpaceEachObject(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo; end
+		class Bar < Foo; end
+
+		FOOS = [Foo.new, Bar.new]
+		$foo = { a: Foo.new }
+
+		def count_foos
+		  f = Foo.new
+		  names = []
+		  count = ObjectSpace.each_object(Foo) do |o|
+		    names.push(o.class.name)
+		  end
+		  [count, names.sort].to_s
+		end
+
+		count_foos
+		`, `[4, ["Bar", "Foo", "Foo", "Foo"]]`},
+		{`
+		class Foo
+		  def initialize(child)
+		    @child = child
+		  end
+		end
+
+		class Baz; end
+
+		a = Foo.new(Foo.new(Baz.new))
+		ObjectSpace.each_object(Baz) do |b| end
+		`, 1},
+		{`
+		module Named; end
+
+		class Foo
+		  include(Named)
+		end
+
+		f = Foo.new
+		ObjectSpace.each_object(Named) do |o|
+		  @found = o
+		end
+		@found == f
+		`, true},
+		{`
+		class Foo; end
+		ObjectSpace.each_object do |o| end > 1
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestObjectSpaceEachObjectFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`ObjectSpace.each_object(Object)`, "InternalError: Can't yield without a block", 1},
+		{`ObjectSpace.each_object(1) do |o| end`, "TypeError: Expect argument to be Class. got: Integer", 1},
+		{`ObjectSpace.each_object(Object, Object) do |o| end`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestObjectSpaceCountObjects(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		class Foo; end
+		FOOS = [Foo.new, Foo.new, Foo.new]
+		ObjectSpace.count_objects["Foo"]
+		`, 3},
+		{`ObjectSpace.count_objects["Foo"]`, nil},
+		{`
+		counts = ObjectSpace.count_objects
+		counts["TOTAL"] >= counts["Class"] + counts["Array"]
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGCModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`GC.start`, nil},
+		{`
+		count = GC.count
+		GC.start
+		GC.count > count
+		`, true},
+		{`GC.stat["heap_alloc"] > 0`, true},
+		{`GC.stat["sys"] >= GC.stat["heap_sys"]`, true},
+		{`GC.stat.keys.sort.to_s`, `["frees", "heap_alloc", "heap_objects", "heap_sys", "mallocs", "next_gc", "num_gc", "pause_total_ns", "sys", "total_alloc"]`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestGCModuleFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`GC.start(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`GC.count(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`GC.stat(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
@@ -0,0 +1,410 @@
+package vm
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// OpenStructObject is a data holder whose attributes are created when they're assigned, which is handy when
+// defining a class is overkill. The attributes are stored in a Hash, and their reader and writer methods are
+// looked up in it when the class doesn't define a method with the same name. Reading an attribute that hasn't
+// been assigned is an UndefinedMethodError, use `[]` to get `nil` instead. Use `require "ostruct"` to load it.
+//
+// ```ruby
+// require "ostruct"
+//
+// person = OpenStruct.new(name: "Stan")
+// person.age = 30
+// person.name              # => "Stan"
+// person.age               # => 30
+// person[:email]           # => nil
+// person.respond_to?(:age) # => true
+// person.to_h              # => { age: 30, name: "Stan" }
+// person.to_s              # => #<OpenStruct age=30, name="Stan">
+// ```
+type OpenStructObject struct {
+	*baseObj
+	table *HashObject
+}
+
+// Class methods --------------------------------------------------------
+func builtinOpenStructClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new OpenStruct, whose attributes are set from the given Hash.
+			//
+			// ```ruby
+			// OpenStruct.new                    # => #<OpenStruct>
+			// OpenStruct.new(name: "Stan").name # => "Stan"
+			// ```
+			//
+			// @param hash [Hash]
+			// @return [OpenStruct]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					o := t.vm.initOpenStructObject(receiver.(*RClass))
+
+					if len(args) == 0 || args[0] == NULL {
+						return o
+					}
+
+					h, ok := args[0].(*HashObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, args[0].Class().Name)
+					}
+
+					for k, v := range h.Pairs {
+						if keyObj, ok := h.keyObjects[k]; ok {
+							return t.initErrorObject(errors.TypeError, "Expect attribute name to be String or Symbol. got: %s", keyObj.Class().Name)
+						}
+
+						o.table.Pairs[k] = v
+					}
+
+					return o
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinOpenStructInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the other object is an OpenStruct with the same attributes.
+			//
+			// ```ruby
+			// OpenStruct.new(a: 1) == OpenStruct.new(a: 1) # => true
+			// OpenStruct.new(a: 1) == { a: 1 }             # => false
+			// ```
+			//
+			// @param other [Object]
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					other, ok := args[0].(*OpenStructObject)
+					if !ok {
+						return FALSE
+					}
+
+					eq, err := t.hashesEqual(receiver.(*OpenStructObject).table, other.table)
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(eq)
+				}
+			},
+		},
+		{
+			// Returns the value of the attribute, or nil if it isn't assigned.
+			//
+			// ```ruby
+			// o = OpenStruct.new(name: "Stan")
+			// o[:name] # => "Stan"
+			// o["age"] # => nil
+			// ```
+			//
+			// @param name [String/Symbol]
+			// @return [Object]
+			Name: "[]",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					name, err := t.attributeName(args[0])
+					if err != nil {
+						return err
+					}
+
+					v, ok := receiver.(*OpenStructObject).table.Pairs[name]
+					if !ok {
+						return NULL
+					}
+
+					return v
+				}
+			},
+		},
+		{
+			// Assigns the value to the attribute and returns the value.
+			//
+			// ```ruby
+			// o = OpenStruct.new
+			// o[:name] = "Stan"
+			// o.name # => "Stan"
+			// ```
+			//
+			// @param name [String/Symbol], value [Object]
+			// @return [Object]
+			Name: "[]=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got: %d", len(args))
+					}
+
+					name, err := t.attributeName(args[0])
+					if err != nil {
+						return err
+					}
+
+					return receiver.(*OpenStructObject).setAttribute(t, name, args[1])
+				}
+			},
+		},
+		{
+			// Removes the attribute and returns its value, or nil if it isn't assigned.
+			//
+			// ```ruby
+			// o = OpenStruct.new(name: "Stan")
+			// o.delete_field(:name) # => "Stan"
+			// o.respond_to?(:name)  # => false
+			// ```
+			//
+			// @param name [String/Symbol]
+			// @return [Object]
+			Name: "delete_field",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					name, err := t.attributeName(args[0])
+					if err != nil {
+						return err
+					}
+
+					table := receiver.(*OpenStructObject).table
+
+					v, ok := table.Pairs[name]
+					if !ok {
+						return NULL
+					}
+
+					table.remove(name)
+					return v
+				}
+			},
+		},
+		{
+			// Yields the name and the value of each attribute, and returns self.
+			// An Enumerator is returned if no block is given.
+			//
+			// ```ruby
+			// OpenStruct.new(a: 1, b: 2).each_pair do |name, value|
+			//   puts("#{name}: #{value}")
+			// end
+			// ```
+			//
+			// @return [OpenStruct]
+			Name: "each_pair",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_pair", args)
+					}
+
+					table := receiver.(*OpenStructObject).table
+
+					for _, k := range table.sortedKeys() {
+						v, ok := table.Pairs[k]
+						if !ok {
+							continue
+						}
+
+						t.builtinMethodYield(blockFrame, t.vm.initStringObject(k), v)
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns a Hash of the attributes.
+			//
+			// ```ruby
+			// OpenStruct.new(name: "Stan").to_h # => { name: "Stan" }
+			// ```
+			//
+			// @return [Hash]
+			Name: "to_h",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return receiver.(*OpenStructObject).table.copy()
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initOpenStructObject(class *RClass) *OpenStructObject {
+	return &OpenStructObject{
+		baseObj: &baseObj{class: class},
+		table:   vm.initHashObject(map[string]Object{}),
+	}
+}
+
+func initOpenStructClass(vm *VM) {
+	oc := vm.initializeClass("OpenStruct", false)
+	oc.setBuiltinMethods(builtinOpenStructClassMethods(), true)
+	oc.setBuiltinMethods(builtinOpenStructInstanceMethods(), false)
+	vm.objectClass.setClassConstant(oc)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the method with the given name, the attribute's reader or writer is returned if the class doesn't
+// define the method
+func (o *OpenStructObject) findMethod(methodName string) Object {
+	if method := o.baseObj.findMethod(methodName); method != nil {
+		return method
+	}
+
+	if name := strings.TrimSuffix(methodName, "="); name != methodName {
+		if !isAttributeName(name) {
+			return nil
+		}
+
+		return &BuiltinMethodObject{
+			Name: methodName,
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					return receiver.(*OpenStructObject).setAttribute(t, name, args[0])
+				}
+			},
+		}
+	}
+
+	if _, ok := o.table.Pairs[methodName]; !ok {
+		return nil
+	}
+
+	return &BuiltinMethodObject{
+		Name: methodName,
+		Fn: func(receiver Object) builtinMethodBody {
+			return func(t *thread, args []Object, blockFrame *callFrame) Object {
+				if len(args) != 0 {
+					return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+				}
+
+				v, ok := receiver.(*OpenStructObject).table.Pairs[methodName]
+				if !ok {
+					return NULL
+				}
+
+				return v
+			}
+		},
+	}
+}
+
+// Returns the object's name as the string format
+func (o *OpenStructObject) toString() string {
+	str, _ := o.inspectAttributes(defaultInspect)
+	return str
+}
+
+// Returns the attributes as a JSON object
+func (o *OpenStructObject) toJSON() string {
+	return o.table.toJSON()
+}
+
+// Returns the object
+func (o *OpenStructObject) Value() interface{} {
+	return o.table.Pairs
+}
+
+// Other helper functions -----------------------------------------------
+
+// inspectAttributes formats the struct with each value formatted by inspect
+func (o *OpenStructObject) inspectAttributes(inspect inspector) (string, *Error) {
+	attrs := []string{}
+
+	for _, k := range o.table.sortedKeys() {
+		s, err := inspect(o.table.Pairs[k])
+		if err != nil {
+			return "", err
+		}
+
+		attrs = append(attrs, k+"="+s)
+	}
+
+	if len(attrs) == 0 {
+		return "#<" + o.Class().Name + ">", nil
+	}
+
+	return "#<" + o.Class().Name + " " + strings.Join(attrs, ", ") + ">", nil
+}
+
+// setAttribute assigns the value to the attribute and returns the value
+func (o *OpenStructObject) setAttribute(t *thread, name string, value Object) Object {
+	if err := t.checkFrozen(o); err != nil {
+		return err
+	}
+
+	o.table.Pairs[name] = value
+	return value
+}
+
+// attributeName returns the attribute name of a String or Symbol
+func (t *thread) attributeName(obj Object) (string, *Error) {
+	name, ok := stringOrSymbol(obj)
+	if !ok {
+		return "", t.initErrorObject(errors.TypeError, "Expect attribute name to be String or Symbol. got: %s", obj.Class().Name)
+	}
+
+	return name, nil
+}
+
+// isAttributeName returns true if the name can be an attribute's method name, like `name` or `first_name`
+func isAttributeName(name string) bool {
+	if name == "" {
+		return false
+	}
+
+	for i, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && (i == 0 || !unicode.IsDigit(r)) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,165 @@
+package vm
+
+import "testing"
+
+func TestOpenStructClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "ostruct"
+		OpenStruct.new.to_s
+		`, "#<OpenStruct>"},
+		{`
+		require "ostruct"
+		OpenStruct.new(name: "Stan", age: 30).to_s
+		`, `#<OpenStruct age=30, name="Stan">`},
+		{`
+		require "ostruct"
+		OpenStruct.new(name: "Stan").name
+		`, "Stan"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestOpenStructInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "ostruct"
+		o = OpenStruct.new
+		o.name = "Stan"
+		o.name
+		`, "Stan"},
+		{`
+		require "ostruct"
+		o = OpenStruct.new
+		o.age = 30
+		o.age = o.age + 1
+		o.age
+		`, 31},
+		{`
+		require "ostruct"
+		o = OpenStruct.new
+		o.respond_to?(:name)
+		`, false},
+		{`
+		require "ostruct"
+		o = OpenStruct.new
+		o.name = nil
+		o.respond_to?(:name)
+		`, true},
+		{`
+		require "ostruct"
+		o = OpenStruct.new
+		o.respond_to?("name=")
+		`, true},
+		{`
+		require "ostruct"
+		o = OpenStruct.new
+		o[:name] = "Stan"
+		o["name"]
+		`, "Stan"},
+		{`
+		require "ostruct"
+		OpenStruct.new[:name]
+		`, nil},
+		{`
+		require "ostruct"
+		o = OpenStruct.new(name: "Stan")
+		o.delete_field(:name)
+		`, "Stan"},
+		{`
+		require "ostruct"
+		o = OpenStruct.new(name: "Stan")
+		o.delete_field(:name)
+		o.respond_to?(:name)
+		`, false},
+		{`
+		require "ostruct"
+		OpenStruct.new.delete_field(:name)
+		`, nil},
+		{`
+		require "ostruct"
+		o = OpenStruct.new(a: 1, b: 2)
+		s = ""
+		o.each_pair do |k, v|
+		  s = s + k + v.to_s
+		end
+		s
+		`, "a1b2"},
+		{`
+		require "ostruct"
+		OpenStruct.new(name: "Stan").to_h.to_s
+		`, `{ name: "Stan" }`},
+		{`
+		require "ostruct"
+		o = OpenStruct.new(name: "Stan")
+		o.to_h[:name] = "Kyle"
+		o.name
+		`, "Stan"},
+		{`
+		require "ostruct"
+		OpenStruct.new(a: 1) == OpenStruct.new(a: 1)
+		`, true},
+		{`
+		require "ostruct"
+		OpenStruct.new(a: 1) == OpenStruct.new(a: 2)
+		`, false},
+		{`
+		require "ostruct"
+		OpenStruct.new(a: 1) == { a: 1 }
+		`, false},
+		{`
+		require "ostruct"
+		OpenStruct.new(list: [1, "a"]).inspect
+		`, `#<OpenStruct list=[1, "a"]>`},
+		{`
+		require "ostruct"
+		{ o: OpenStruct.new(a: 1) }.to_json
+		`, `{"o":{"a":1}}`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestOpenStructMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "ostruct"
+		OpenStruct.new(1)`, "TypeError: Expect argument to be Hash. got: Integer", 2},
+		{`require "ostruct"
+		OpenStruct.new({}, {})`, "ArgumentError: Expect 0..1 argument. got: 2", 2},
+		{`require "ostruct"
+		OpenStruct.new.name`, "UndefinedMethodError: Undefined Method 'name' for #<OpenStruct>", 2},
+		{`require "ostruct"
+		OpenStruct.new(name: "Stan").name(1)`, "ArgumentError: Expect 0 argument. got: 1", 2},
+		{`require "ostruct"
+		OpenStruct.new[1]`, "TypeError: Expect attribute name to be String or Symbol. got: Integer", 2},
+		{`require "ostruct"
+		OpenStruct.new.freeze.name = "Stan"`, "FrozenError: Can't modify frozen OpenStruct: #<OpenStruct>", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -29,13 +29,13 @@ func builtinPluginClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 1, len(args))
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 1, len(args))
 					}
 
 					name, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "String", args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "String", args[0].Class().Name)
 					}
 
 					return &PluginObject{fn: name.value, baseObj: &baseObj{class: t.vm.topLevelClass(classes.PluginClass), InstanceVariables: newEnvironment()}}
@@ -54,7 +54,7 @@ func builtinPluginClassMethods() []*BuiltinMethodObject {
 					p, err := compileAndOpenPlugin(soName, pkgPath)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					return t.vm.initPluginObject(pkgPath, p)
@@ -84,7 +84,7 @@ func builtinPluginInstanceMethods() []*BuiltinMethodObject {
 					ok, err := fileExists(pluginDir)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					if !ok {
@@ -101,7 +101,7 @@ func builtinPluginInstanceMethods() []*BuiltinMethodObject {
 					file, err := os.OpenFile(fn+".go", os.O_RDWR|os.O_CREATE, 0755)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, "Error when creating plugin: %s", err.Error())
+						return t.initErrorObject(errors.InternalError, "Error when creating plugin: %s", err.Error())
 					}
 
 					file.WriteString(pluginContent)
@@ -111,7 +111,7 @@ func builtinPluginInstanceMethods() []*BuiltinMethodObject {
 					p, err := compileAndOpenPlugin(soName, file.Name())
 
 					if err != nil {
-						t.vm.initErrorObject(errors.InternalError, err.Error())
+						t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					r.plugin = p
@@ -127,7 +127,7 @@ func builtinPluginInstanceMethods() []*BuiltinMethodObject {
 					s, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
 					funcName := s.value
@@ -136,13 +136,13 @@ func builtinPluginInstanceMethods() []*BuiltinMethodObject {
 					f, err := p.Lookup(funcName)
 
 					if err != nil {
-						return t.vm.initErrorObject(errors.InternalError, err.Error())
+						return t.initErrorObject(errors.InternalError, err.Error())
 					}
 
 					funcArgs, err := convertToGoFuncArgs(args[1:])
 
 					if err != nil {
-						t.vm.initErrorObject(errors.TypeError, err.Error())
+						t.initErrorObject(errors.TypeError, err.Error())
 					}
 
 					funcValue := reflect.ValueOf(f)
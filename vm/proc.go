@@ -0,0 +1,441 @@
+package vm
+
+import (
+	"fmt"
+
+	"github.com/goby-lang/goby/compiler/bytecode"
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// ProcObject is a block that has been turned into an object, so it can be stored in a variable,
+// passed to other methods and called later. It keeps the local variables of the scope it's created in.
+//
+// ```ruby
+// counter = 0
+// increment = Proc.new do |n|
+//   counter = counter + n
+// end
+//
+// increment.call(2)
+// increment.call(3)
+// counter # => 5
+//
+// double = lambda do |x|
+//   x * 2
+// end
+// double.call(4) # => 8
+//
+// square = ->(x) { x * x }
+// square.call(3) # => 9
+// ```
+//
+// A method can capture its block as a Proc with a `&` parameter, and a Proc can be passed
+// to a method as its block with `&`:
+//
+// ```ruby
+// def register(&callback)
+//   @callback = callback
+// end
+//
+// [1, 2, 3].map(&double) # => [2, 4, 6]
+// ```
+//
+// A lambda checks the number of its arguments like a method, and `return` in it returns from the lambda.
+// `return` in a proc returns from the method that defines the proc:
+//
+// ```ruby
+// def find_first(arr)
+//   arr.each do |x|
+//     return x if x > 1
+//   end
+//   nil
+// end
+//
+// find_first([1, 2, 3]) # => 2
+// ```
+type ProcObject struct {
+	*baseObj
+	blockFrame *callFrame
+	isLambda   bool
+}
+
+// Class methods --------------------------------------------------------
+func builtinProcClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new Proc from the given block.
+			//
+			// ```ruby
+			// p = Proc.new do |x|
+			//   x + 1
+			// end
+			// p.call(1) # => 2
+			// ```
+			//
+			// @return [Proc]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.initProcFromBlock(blockFrame, false)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinProcInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a proc that calls the given callable object with the arguments first, then calls the
+			// receiver with the result. The callable object can be a Proc or any object that responds to `call`.
+			//
+			// ```ruby
+			// double = ->(x) { x * 2 }
+			// inc = ->(x) { x + 1 }
+			//
+			// (double << inc).call(3) # => 8
+			// ```
+			//
+			// @param callable [Object]
+			// @return [Proc]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					p := receiver.(*ProcObject)
+
+					return t.vm.composeProcs(p.isLambda, args[0], p)
+				}
+			},
+		},
+		{
+			// Returns a proc that calls the receiver with the arguments first, then calls the given callable
+			// object with the result. The callable object can be a Proc or any object that responds to `call`.
+			//
+			// ```ruby
+			// double = ->(x) { x * 2 }
+			// inc = ->(x) { x + 1 }
+			//
+			// (double >> inc).call(3) # => 7
+			// ```
+			//
+			// @param callable [Object]
+			// @return [Proc]
+			Name: ">>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					p := receiver.(*ProcObject)
+
+					return t.vm.composeProcs(p.isLambda, p, args[0])
+				}
+			},
+		},
+		{
+			// Returns the number of the proc's parameters. If the proc has optional parameters, it returns -n-1,
+			// where n is the number of the required ones.
+			//
+			// ```ruby
+			// ->(a, b) { a + b }.arity      # => 2
+			// ->(a, b = 1) { a + b }.arity  # => -2
+			// Proc.new do |a| end.arity     # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "arity",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initIntegerObject(receiver.(*ProcObject).arity())
+				}
+			},
+		},
+		{
+			// Executes the proc with the given arguments and returns the result.
+			// A lambda checks the number of the arguments like a method, while a proc ignores the extra arguments
+			// and sets missing ones to nil.
+			//
+			// ```ruby
+			// add = ->(a, b) { a + b }
+			// add.call(1, 2) # => 3
+			// ```
+			//
+			// ```ruby
+			// p = Proc.new do |a, b|
+			//   b
+			// end
+			// p.call(1)    # => nil
+			//
+			// l = ->(a, b) { b }
+			// l.call(1)    # => ArgumentError
+			// ```
+			//
+			// @return [Object]
+			Name: "call",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver.(*ProcObject).call(t, args)
+				}
+			},
+		},
+		{
+			// Returns a curried proc, which collects arguments across calls, and calls the receiver once it
+			// has got enough arguments. The number of arguments defaults to the receiver's required parameters.
+			//
+			// ```ruby
+			// add = ->(a, b, c) { a + b + c }
+			//
+			// add.curry.call(1).call(2).call(3) # => 6
+			// add.curry.call(1, 2).call(3)      # => 6
+			//
+			// sum = ->(a, b = 0, c = 0) { a + b + c }
+			// sum.curry(3).call(1).call(2).call(3) # => 6
+			// ```
+			//
+			// @param arity [Integer]
+			// @return [Proc]
+			Name: "curry",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					p := receiver.(*ProcObject)
+					arity := p.arity()
+					required := arity
+
+					if arity < 0 {
+						required = -arity - 1
+					}
+
+					switch len(args) {
+					case 0:
+					case 1:
+						n, ok := args[0].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						if p.isLambda && (n.value < required || arity >= 0 && n.value != arity) {
+							return t.initErrorObject(errors.ArgumentError, "Wrong number of arguments for curried lambda. Expect: %d. got: %d", arity, n.value)
+						}
+
+						required = n.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					return t.vm.curryProc(p, required, []Object{})
+				}
+			},
+		},
+		{
+			// Returns true if the proc is a lambda, which is created by `lambda` or `->`.
+			//
+			// ```ruby
+			// ->(x) { x }.lambda?          # => true
+			// Proc.new do |x| x end.lambda? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "lambda?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return toBooleanObject(receiver.(*ProcObject).isLambda)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initProcObject(blockFrame *callFrame, isLambda bool) *ProcObject {
+	return &ProcObject{
+		baseObj:    &baseObj{class: vm.topLevelClass(classes.ProcClass)},
+		blockFrame: blockFrame,
+		isLambda:   isLambda,
+	}
+}
+
+func (vm *VM) initProcClass() *RClass {
+	pc := vm.initializeClass(classes.ProcClass, false)
+	pc.setBuiltinMethods(builtinProcInstanceMethods(), false)
+	pc.setBuiltinMethods(builtinProcClassMethods(), true)
+	return pc
+}
+
+// initProcFromBlock turns the block given to a builtin method into a Proc.
+// The block isn't executed here, so its frame needs to be popped manually.
+func (t *thread) initProcFromBlock(blockFrame *callFrame, isLambda bool) Object {
+	if blockFrame == nil {
+		return t.initErrorObject(errors.ArgumentError, "Can't create Proc object without a block")
+	}
+
+	t.callFrameStack.pop()
+	blockFrame.isLambda = isLambda
+
+	return t.vm.initProcObject(blockFrame, isLambda)
+}
+
+// initGoProc returns a Proc whose body is implemented in Go
+func (vm *VM) initGoProc(isLambda bool, body func(t *thread, args []Object) Object) *ProcObject {
+	return vm.initProcObject(&callFrame{goBlock: body}, isLambda)
+}
+
+// curryProc returns a Proc that calls p once the given arguments and the ones it's called with add up to arity,
+// or returns another curried Proc with them
+func (vm *VM) curryProc(p *ProcObject, arity int, given []Object) *ProcObject {
+	return vm.initGoProc(p.isLambda, func(t *thread, args []Object) Object {
+		collected := append(append([]Object{}, given...), args...)
+
+		if len(collected) < arity {
+			return t.vm.curryProc(p, arity, collected)
+		}
+
+		return p.call(t, collected)
+	})
+}
+
+// composeProcs returns a Proc that calls first with the arguments, and then calls second with the result.
+// Both of them can be Procs or any objects that respond to `call`.
+func (vm *VM) composeProcs(isLambda bool, first, second Object) *ProcObject {
+	return vm.initGoProc(isLambda, func(t *thread, args []Object) Object {
+		result := t.callCallable(first, args)
+
+		if err, ok := result.(*Error); ok {
+			return err
+		}
+
+		return t.callCallable(second, []Object{result})
+	})
+}
+
+// callCallable calls the Proc with args, or sends `call` to other objects
+func (t *thread) callCallable(callable Object, args []Object) Object {
+	if p, ok := callable.(*ProcObject); ok {
+		return p.call(t, args)
+	}
+
+	return t.sendMethod("call", callable, args...)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (p *ProcObject) Value() interface{} {
+	return p.blockFrame
+}
+
+// Returns the proc's string representation
+func (p *ProcObject) toString() string {
+	if p.isLambda {
+		return fmt.Sprintf("<Proc: %p (lambda)>", p)
+	}
+
+	return fmt.Sprintf("<Proc: %p>", p)
+}
+
+// Alias of toString
+func (p *ProcObject) toJSON() string {
+	return p.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// arity returns the number of the proc's parameters, or -n-1 if it has optional parameters after n required ones.
+// Procs implemented in Go take any number of arguments, so their arity is -1.
+func (p *ProcObject) arity() int {
+	if p.blockFrame.instructionSet == nil {
+		return -1
+	}
+
+	required := 0
+	optional := false
+
+	for _, at := range p.blockFrame.instructionSet.argTypes {
+		switch at {
+		case bytecode.NormalArg:
+			required++
+		case bytecode.OptionedArg:
+			optional = true
+		}
+	}
+
+	if optional {
+		return -required - 1
+	}
+
+	return required
+}
+
+// checkArity returns an ArgumentError if the proc is a lambda and args don't match its parameters
+func (p *ProcObject) checkArity(t *thread, args []Object) *Error {
+	if !p.isLambda || p.blockFrame.instructionSet == nil {
+		return nil
+	}
+
+	arity := p.arity()
+
+	if arity >= 0 && len(args) != arity {
+		return t.initErrorObject(errors.ArgumentError, "Expect %d args for lambda. got: %d", arity, len(args))
+	}
+
+	if arity < 0 && len(args) < -arity-1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect at least %d args for lambda. got: %d", -arity-1, len(args))
+	}
+
+	if arity < 0 && len(args) > len(p.blockFrame.instructionSet.argTypes) {
+		return t.initErrorObject(errors.ArgumentError, "Expect at most %d args for lambda. got: %d", len(p.blockFrame.instructionSet.argTypes), len(args))
+	}
+
+	return nil
+}
+
+// call executes the proc with args, `break` in the proc stops it and returns break's value
+func (p *ProcObject) call(t *thread, args []Object) (result Object) {
+	if err := p.checkArity(t, args); err != nil {
+		return err
+	}
+
+	cfp := t.cfp
+	sp := t.sp
+
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		b, ok := r.(*blockBreak)
+
+		if !ok || b.blockFrame != p.blockFrame {
+			panic(r)
+		}
+
+		for t.cfp > cfp {
+			t.callFrameStack.pop()
+		}
+
+		t.sp = sp
+		result = b.value
+	}()
+
+	return t.builtinMethodYield(p.blockFrame, args...).Target
+}
@@ -0,0 +1,412 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestProc(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		p = Proc.new do |x|
+		  x + 1
+		end
+		p.class.name
+		`, "Proc"},
+		{`
+		p = Proc.new do |x|
+		  x + 1
+		end
+		p.call(1)
+		`, 2},
+		{`
+		counter = 0
+		increment = Proc.new do |n|
+		  counter = counter + n
+		end
+
+		increment.call(2)
+		increment.call(3)
+		counter
+		`, 5},
+		{`
+		def make_counter
+		  count = 0
+		  Proc.new do
+		    count = count + 1
+		  end
+		end
+
+		c = make_counter
+		c.call
+		c.call
+		c.call
+		`, 3},
+		{`
+		double = lambda do |x|
+		  x * 2
+		end
+		double.call(4)
+		`, 8},
+		{`
+		square = ->(x) { x * x }
+		square.call(3)
+		`, 9},
+		{`
+		add = ->(a, b = 10) do
+		  a + b
+		end
+		add.call(1)
+		`, 11},
+		{`
+		answer = -> { 42 }
+		answer.call
+		`, 42},
+		{`
+		callbacks = { double: ->(x) { x * 2 } }
+		callbacks[:double].call(5)
+		`, 10},
+		{`
+		class Button
+		  def on_click(&callback)
+		    @callback = callback
+		  end
+
+		  def click(n)
+		    @callback.call(n)
+		  end
+		end
+
+		b = Button.new
+		b.on_click do |n|
+		  n * 100
+		end
+		b.click(2)
+		`, 200},
+		{`
+		def run(a, &block)
+		  block.call(a) + yield(a)
+		end
+
+		run(5) do |x|
+		  x * 3
+		end
+		`, 30},
+		{`
+		def capture(&block)
+		  block
+		end
+
+		capture
+		`, nil},
+		{`
+		double = ->(x) { x * 2 }
+		[1, 2, 3].map(&double).to_s
+		`, "[2, 4, 6]"},
+		{`
+		def twice(x)
+		  yield(yield(x))
+		end
+
+		inc = Proc.new do |n|
+		  n + 1
+		end
+		twice(1, &inc)
+		`, 3},
+		{`
+		def forward(&block)
+		  [1, 2].map(&block).to_s
+		end
+
+		forward do |x|
+		  x * 10
+		end
+		`, "[10, 20]"},
+		{`
+		def yielder
+		  block_given?
+		end
+
+		yielder(&nil)
+		`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestProcFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Proc.new`, "ArgumentError: Can't create Proc object without a block", 1},
+		{`lambda`, "ArgumentError: Can't create Proc object without a block", 1},
+		{`[1, 2].map(&1)`, "TypeError: Expect argument to be Proc. got: Integer", 1},
+		{`
+		def capture(&block)
+		  block
+		end
+
+		capture(1)
+		`, "ArgumentError: Expect at most 0 args for method 'capture'. got: 1", 6},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestProcCurryAndComposition(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		add = ->(a, b, c) { a + b + c }
+		add.curry.call(1).call(2).call(3)
+		`, 6},
+		{`
+		add = ->(a, b, c) { a + b + c }
+		add.curry.call(1, 2).call(3)
+		`, 6},
+		{`
+		add = ->(a, b, c) { a + b + c }
+		add.curry.call(1).class.name
+		`, "Proc"},
+		{`
+		sum = ->(a, b = 0, c = 0) { a + b + c }
+		sum.curry.call(5)
+		`, 5},
+		{`
+		sum = ->(a, b = 0, c = 0) { a + b + c }
+		sum.curry(3).call(1).call(2).call(3)
+		`, 6},
+		{`
+		p = Proc.new do |a, b|
+		  a * b
+		end
+		p.curry.call(3).call(4)
+		`, 12},
+		{`
+		answer = -> { 42 }
+		answer.curry.call
+		`, 42},
+		{`
+		double = ->(x) { x * 2 }
+		inc = ->(x) { x + 1 }
+		(double << inc).call(3)
+		`, 8},
+		{`
+		double = ->(x) { x * 2 }
+		inc = ->(x) { x + 1 }
+		(double >> inc).call(3)
+		`, 7},
+		{`
+		double = ->(x) { x * 2 }
+		inc = ->(x) { x + 1 }
+		f = double >> inc >> double
+		f.call(1)
+		`, 6},
+		{`
+		class Negate
+		  def call(x)
+		    -x
+		  end
+		end
+
+		double = ->(x) { x * 2 }
+		(double >> Negate.new).call(3)
+		`, -6},
+		{`
+		double = ->(x) { x * 2 }
+		inc = ->(x) { x + 1 }
+		[1, 2].map(&(double >> inc)).to_s
+		`, "[3, 5]"},
+		{`
+		def twice
+		  yield(yield(1))
+		end
+
+		add = ->(a, b) { a + b }
+		twice(&add.curry.call(10))
+		`, 21},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestProcCurryAndCompositionFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`->(a, b) { a + b }.curry(3)`, "ArgumentError: Wrong number of arguments for curried lambda. Expect: 2. got: 3", 1},
+		{`->(a, b = 1) { a + b }.curry(0)`, "ArgumentError: Wrong number of arguments for curried lambda. Expect: -2. got: 0", 1},
+		{`->(a) { a }.curry("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`->(a) { a }.curry(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`->(a) { a }.send(">>")`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`->(a) { a }.send("<<", 1, 2)`, "ArgumentError: Expect 1 argument. got: 2", 1},
+		{`(->(a) { a } >> 1).call(2)`, "UndefinedMethodError: Undefined Method 'call' for 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestLambdaAndProcSemantics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`->(x) { x }.lambda?`, true},
+		{`
+		l = lambda do |x|
+		  x
+		end
+		l.lambda?
+		`, true},
+		{`Proc.new do |x| x end.lambda?`, false},
+		{`->(a, b) { a }.arity`, 2},
+		{`->(a, b = 1) { a }.arity`, -2},
+		{`-> { 1 }.arity`, 0},
+		{`Proc.new do |a| a end.arity`, 1},
+		{`Proc.new do end.arity`, 0},
+		{`
+		p = Proc.new do |a, b|
+		  b
+		end
+		p.call(1)
+		`, nil},
+		{`
+		p = Proc.new do |a, b|
+		  b
+		end
+		p.call(1, 2, 3)
+		`, 2},
+		{`
+		l = ->(x) do
+		  return x * 2
+		  100
+		end
+		l.call(3)
+		`, 6},
+		{`
+		def find_first(arr)
+		  arr.each do |x|
+		    return x if x > 1
+		  end
+
+		  -1
+		end
+
+		find_first([1, 2, 3])
+		`, 2},
+		{`
+		def foo
+		  p = Proc.new do |x|
+		    return x
+		  end
+
+		  p.call(7)
+		  99
+		end
+
+		foo
+		`, 7},
+		{`
+		def foo
+		  l = lambda do |x|
+		    [1].each do |y|
+		      return x + y
+		    end
+
+		    0
+		  end
+
+		  l.call(10) + 1000
+		end
+
+		foo
+		`, 1011},
+		{`
+		def foo
+		  yield
+		  2
+		end
+
+		foo(&-> { return 1 })
+		`, 2},
+		{`
+		def capture(&block)
+		  block
+		end
+
+		capture(&->(x) { x }).lambda?
+		`, true},
+		{`
+		def foo
+		  [1, 2].map do |x|
+		    [3].each do |y|
+		      return x + y
+		    end
+		  end
+		end
+
+		foo
+		`, 4},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestLambdaAndProcSemanticsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`->(a, b) { a }.call(1)`, "ArgumentError: Expect 2 args for lambda. got: 1", 1},
+		{`->(a) { a }.call(1, 2)`, "ArgumentError: Expect 1 args for lambda. got: 2", 1},
+		{`->(a, b = 1) { a }.call`, "ArgumentError: Expect at least 1 args for lambda. got: 0", 1},
+		{`->(a, b = 1) { a }.call(1, 2, 3)`, "ArgumentError: Expect at most 2 args for lambda. got: 3", 1},
+		{`-> { 1 }.arity(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`-> { 1 }.lambda?(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`
+		def make
+		  Proc.new do
+		    return 1
+		  end
+		end
+
+		begin
+		  make.call
+		rescue LocalJumpError => e
+		  raise e
+		end
+		`, "LocalJumpError: Can't return from method 'make', which has already returned", 4},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
@@ -0,0 +1,491 @@
+package vm
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Process is a module for the current process and the processes it runs. Commands can be run with `system`,
+// backticks and `spawn`:
+//
+// ```ruby
+// system("mkdir -p tmp")          # => true, the output goes to $stdout
+// files = `ls tmp`                # => the command's output as a String
+// $?.success?                     # => true
+//
+// child = spawn("git", "status", chdir: "tmp")
+// status = child.wait
+// status.exitstatus               # => 128
+// child.stderr                    # => "fatal: not a git repository ..."
+// ```
+//
+// A single command string is run by `sh -c`, so it can use pipes and redirections. With multiple arguments,
+// the command is run directly without a shell. `$?` is the `Process::Status` of the last finished command.
+
+// ProcessStatusObject represents how a finished process exited
+type ProcessStatusObject struct {
+	*baseObj
+	pid   int
+	state *os.ProcessState
+}
+
+// ProcessChildObject represents a process started by `spawn`, which runs in the background.
+// Its stdout and stderr are captured and can be read after it finishes.
+type ProcessChildObject struct {
+	*baseObj
+	cmd    *exec.Cmd
+	stdout *bytes.Buffer
+	stderr *bytes.Buffer
+	done   chan struct{}
+	status *ProcessStatusObject
+}
+
+// processSignals are the signals `Process.kill` accepts by name
+var processSignals = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"TERM": syscall.SIGTERM,
+	"CONT": syscall.SIGCONT,
+	"STOP": syscall.SIGSTOP,
+}
+
+// Class methods --------------------------------------------------------
+func builtinProcessClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Sends the signal to the processes, and returns the number of the processes.
+			// The signal can be a name like "TERM" or "SIGTERM", or a number.
+			//
+			// ```ruby
+			// child = spawn("sleep 10")
+			// Process.kill("TERM", child.pid) # => 1
+			// child.wait.signaled?            # => true
+			// ```
+			//
+			// @param signal [String/Integer], pid [Integer]...
+			// @return [Integer]
+			Name: "kill",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 or more arguments. got: %d", len(args))
+					}
+
+					var sig syscall.Signal
+
+					switch s := args[0].(type) {
+					case *StringObject:
+						name := strings.TrimPrefix(strings.ToUpper(s.value), "SIG")
+						signal, ok := processSignals[name]
+						if !ok {
+							return t.initErrorObject(errors.ArgumentError, "Unknown signal: %s", s.value)
+						}
+
+						sig = signal
+					case *IntegerObject:
+						sig = syscall.Signal(s.value)
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "String or Integer", args[0].Class().Name)
+					}
+
+					for _, arg := range args[1:] {
+						pid, ok := arg.(*IntegerObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+						}
+
+						if err := syscall.Kill(pid.value, sig); err != nil {
+							return t.initErrorObject(errors.InternalError, "Can't send signal to process %d: %s", pid.value, err.Error())
+						}
+					}
+
+					return t.vm.initIntegerObject(len(args) - 1)
+				}
+			},
+		},
+		{
+			// Returns the id of the current process.
+			//
+			// @return [Integer]
+			Name: "pid",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initIntegerObject(os.Getpid())
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinProcessStatusInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the exit status of the process, or nil if it was killed by a signal.
+			//
+			// ```ruby
+			// system("exit 3")
+			// $?.exitstatus # => 3
+			// ```
+			//
+			// @return [Integer]
+			Name: "exitstatus",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					code := receiver.(*ProcessStatusObject).state.ExitCode()
+					if code < 0 {
+						return NULL
+					}
+
+					return t.vm.initIntegerObject(code)
+				}
+			},
+		},
+		{
+			// Returns the id of the process.
+			//
+			// @return [Integer]
+			Name: "pid",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*ProcessStatusObject).pid)
+				}
+			},
+		},
+		{
+			// Returns true if the process was killed by a signal.
+			//
+			// @return [Boolean]
+			Name: "signaled?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					ws, ok := receiver.(*ProcessStatusObject).state.Sys().(syscall.WaitStatus)
+
+					return toBooleanObject(ok && ws.Signaled())
+				}
+			},
+		},
+		{
+			// Returns true if the process exited with status 0.
+			//
+			// @return [Boolean]
+			Name: "success?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return toBooleanObject(receiver.(*ProcessStatusObject).state.Success())
+				}
+			},
+		},
+		{
+			// Returns the process id and how it exited, like "pid 1234 exit 0".
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+	}
+}
+
+func builtinProcessChildInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the id of the process.
+			//
+			// @return [Integer]
+			Name: "pid",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*ProcessChildObject).cmd.Process.Pid)
+				}
+			},
+		},
+		{
+			// Waits for the process to finish, and returns what it wrote into stderr.
+			//
+			// @return [String]
+			Name: "stderr",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c := receiver.(*ProcessChildObject)
+
+					if err := t.waitChild(c); err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(c.stderr.String())
+				}
+			},
+		},
+		{
+			// Waits for the process to finish, and returns what it wrote into stdout.
+			//
+			// @return [String]
+			Name: "stdout",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c := receiver.(*ProcessChildObject)
+
+					if err := t.waitChild(c); err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(c.stdout.String())
+				}
+			},
+		},
+		{
+			// Waits for the process to finish, and returns its `Process::Status`, which is also assigned to `$?`.
+			//
+			// ```ruby
+			// child = spawn("make test")
+			// child.wait.success? # => true
+			// ```
+			//
+			// @return [Process::Status]
+			Name: "wait",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c := receiver.(*ProcessChildObject)
+
+					if err := t.waitChild(c); err != nil {
+						return err
+					}
+
+					t.vm.globalVariables.Store("$?", c.status)
+					return c.status
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initProcessModule() *RClass {
+	m := vm.initializeClass(classes.ProcessModule, true)
+	m.setBuiltinMethods(builtinProcessClassMethods(), true)
+
+	status := vm.initializeClass("Status", false)
+	status.setBuiltinMethods(builtinProcessStatusInstanceMethods(), false)
+	status.scope = m
+	m.setClassConstant(status)
+
+	child := vm.initializeClass("Child", false)
+	child.setBuiltinMethods(builtinProcessChildInstanceMethods(), false)
+	child.scope = m
+	m.setClassConstant(child)
+
+	return m
+}
+
+func (vm *VM) initProcessStatusObject(cmd *exec.Cmd) *ProcessStatusObject {
+	return &ProcessStatusObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.ProcessModule).getClassConstant("Status")},
+		pid:     cmd.ProcessState.Pid(),
+		state:   cmd.ProcessState,
+	}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the process id and how it exited
+func (s *ProcessStatusObject) toString() string {
+	if ws, ok := s.state.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+		return fmt.Sprintf("pid %d SIG%s (signal %d)", s.pid, signalName(ws.Signal()), ws.Signal())
+	}
+
+	return fmt.Sprintf("pid %d exit %d", s.pid, s.state.ExitCode())
+}
+
+// Alias of toString
+func (s *ProcessStatusObject) toJSON() string {
+	return s.toString()
+}
+
+// Returns the object
+func (s *ProcessStatusObject) Value() interface{} {
+	return s.state
+}
+
+// Returns the child's command line
+func (c *ProcessChildObject) toString() string {
+	return fmt.Sprintf("#<Process::Child pid=%d: %s>", c.cmd.Process.Pid, strings.Join(c.cmd.Args, " "))
+}
+
+// Alias of toString
+func (c *ProcessChildObject) toJSON() string {
+	return c.toString()
+}
+
+// Returns the object
+func (c *ProcessChildObject) Value() interface{} {
+	return c.cmd
+}
+
+// Other helper functions -----------------------------------------------
+
+// newCommand builds the command of `system` and `spawn` from the arguments, which are a command line run by
+// `sh -c`, or a program and its arguments. The last argument can be the options `chdir:` and `env:`.
+func (t *thread) newCommand(args []Object) (*exec.Cmd, *Error) {
+	var options *HashObject
+
+	if len(args) > 0 {
+		if h, ok := args[len(args)-1].(*HashObject); ok {
+			options = h
+			args = args[:len(args)-1]
+		}
+	}
+
+	if len(args) == 0 {
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 1 or more arguments. got: 0")
+	}
+
+	cmdArgs := []string{}
+
+	for _, arg := range args {
+		s, ok := arg.(*StringObject)
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+		}
+
+		cmdArgs = append(cmdArgs, s.value)
+	}
+
+	var cmd *exec.Cmd
+
+	if len(cmdArgs) == 1 {
+		cmd = exec.Command("sh", "-c", cmdArgs[0])
+	} else {
+		cmd = exec.Command(cmdArgs[0], cmdArgs[1:]...)
+	}
+
+	if options == nil {
+		return cmd, nil
+	}
+
+	for k, v := range options.Pairs {
+		switch k {
+		case "chdir":
+			dir, ok := v.(*StringObject)
+			if !ok {
+				return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, v.Class().Name)
+			}
+
+			cmd.Dir = dir.value
+		case "env":
+			env, ok := v.(*HashObject)
+			if !ok {
+				return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.HashClass, v.Class().Name)
+			}
+
+			cmd.Env = os.Environ()
+
+			for _, name := range env.sortedKeys() {
+				cmd.Env = append(cmd.Env, name+"="+env.Pairs[name].toString())
+			}
+		default:
+			return nil, t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+		}
+	}
+
+	return cmd, nil
+}
+
+// startCommand starts the command, or returns an error if it can't be run
+func (t *thread) startCommand(cmd *exec.Cmd) *Error {
+	if err := cmd.Start(); err != nil {
+		return t.initErrorObject(errors.InternalError, "Can't run command %s: %s", cmd.Args[0], err.Error())
+	}
+
+	return nil
+}
+
+// waitCommand waits for the started command to finish and assigns its status to `$?`.
+// If the thread's timeout is up, the command is killed and a `Timeout::Error` is returned without waiting,
+// because the processes the command started may still hold its output.
+func (t *thread) waitCommand(cmd *exec.Cmd) *Error {
+	done := make(chan struct{})
+
+	go func() {
+		cmd.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-t.timedOut():
+		cmd.Process.Kill()
+		return t.timeoutError()
+	}
+
+	t.vm.globalVariables.Store("$?", t.vm.initProcessStatusObject(cmd))
+	return nil
+}
+
+// spawnCommand starts the command in the background with its stdout and stderr captured
+func (t *thread) spawnCommand(cmd *exec.Cmd) Object {
+	c := &ProcessChildObject{
+		baseObj: &baseObj{class: t.vm.topLevelClass(classes.ProcessModule).getClassConstant("Child")},
+		cmd:     cmd,
+		stdout:  &bytes.Buffer{},
+		stderr:  &bytes.Buffer{},
+		done:    make(chan struct{}),
+	}
+
+	cmd.Stdout = c.stdout
+	cmd.Stderr = c.stderr
+
+	if err := t.startCommand(cmd); err != nil {
+		return err
+	}
+
+	go func() {
+		cmd.Wait()
+		c.status = t.vm.initProcessStatusObject(cmd)
+		close(c.done)
+	}()
+
+	return c
+}
+
+// waitChild waits for the spawned process to finish, or returns an error if the thread's timeout is up
+func (t *thread) waitChild(c *ProcessChildObject) *Error {
+	select {
+	case <-c.done:
+		return nil
+	case <-t.timedOut():
+		return t.timeoutError()
+	}
+}
+
+// signalName returns the name of the signal without "SIG", or its number if it's not a known signal
+func signalName(sig syscall.Signal) string {
+	for name, s := range processSignals {
+		if s == sig {
+			return name
+		}
+	}
+
+	return fmt.Sprint(int(sig))
+}
@@ -0,0 +1,126 @@
+package vm
+
+import "testing"
+
+func TestCommandMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"`echo hello`", "hello\n"},
+		{"name = \"goby\"\n`echo \"hi #{name}\"`", "hi goby\n"},
+		{"`printf '%s' a`", "a"},
+		{"``", ""},
+		{"`exit 2`\n$?.exitstatus", 2},
+		{"`true`\n$?.success?", true},
+		{`system("true")`, true},
+		{`system("false")`, false},
+		{`system("exit 3")
+		$?.exitstatus`, 3},
+		{`system("test", "-d", "/")`, true},
+		{`system("no-such-program-in-goby-test", "a")`, nil},
+		{`system("test -d vm", chdir: "..")`, true},
+		{`system("test \"$GOBY_FOO\" = bar", env: { GOBY_FOO: "bar" })`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSpawnMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		c = spawn("sh", "-c", "echo out; echo err >&2; exit 2")
+		c.wait.exitstatus
+		`, 2},
+		{`
+		c = spawn("sh", "-c", "echo out; echo err >&2; exit 2")
+		c.stdout + c.stderr
+		`, "out\nerr\n"},
+		{`
+		c = spawn("echo $GOBY_FOO", env: { GOBY_FOO: "bar" })
+		c.stdout
+		`, "bar\n"},
+		{`
+		c = spawn("true")
+		c.wait
+		$?.pid == c.pid
+		`, true},
+		{`
+		c = spawn("sleep 10")
+		Process.kill("TERM", c.pid)
+		s = c.wait
+		s.signaled?
+		`, true},
+		{`
+		c = spawn("sleep 10")
+		Process.kill(9, c.pid)
+		c.wait.to_s == "pid #{c.pid} SIGKILL (signal 9)"
+		`, true},
+		{`
+		c = spawn("exit 1")
+		c.wait.to_s == "pid #{c.pid} exit 1"
+		`, true},
+		{`Process.pid > 0`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestProcessMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`system`, "ArgumentError: Expect 1 or more arguments. got: 0", 1},
+		{`system(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`system("true", dir: "/")`, "ArgumentError: Unknown keyword: dir", 1},
+		{`system("true", chdir: 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`system("true", env: 1)`, "TypeError: Expect argument to be Hash. got: Integer", 1},
+		{`send("` + "`" + `")`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`spawn("no-such-program-in-goby-test", "a")`, "InternalError: Can't run command no-such-program-in-goby-test: exec: \"no-such-program-in-goby-test\": executable file not found in $PATH", 1},
+		{`Process.kill("TERM")`, "ArgumentError: Expect 2 or more arguments. got: 1", 1},
+		{`Process.kill("NOPE", 1)`, "ArgumentError: Unknown signal: NOPE", 1},
+		{`Process.kill(:TERM, 1)`, "TypeError: Expect argument to be String or Integer. got: Symbol", 1},
+		{`Process.kill("TERM", "1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Process.pid(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestCommandTimeout(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Timeout.timeout(0.1) do
+		  system("sleep 5 > /dev/null 2>&1")
+		end`, "Error: execution expired", 2},
+		{"Timeout.timeout(0.1) do\n`sleep 5 2> /dev/null`\nend", "Error: execution expired", 2},
+		{`c = spawn("sleep 5")
+		Timeout.timeout(0.1) do
+		  c.wait
+		end`, "Error: execution expired", 3},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+	}
+}
@@ -0,0 +1,574 @@
+package vm
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// QueueObject is a thread-safe FIFO queue for passing objects between threads. Unlike `Channel`, it holds any
+// number of objects without a fixed capacity, and can be inspected and closed by both sides. `pop` blocks until
+// an object is pushed, or returns nil once the queue is closed and empty.
+//
+// `SizedQueue` is a Queue with a maximum size, where `push` blocks while the queue is full. It's useful for
+// pipelines whose producers are faster than their consumers.
+//
+// ```ruby
+// jobs = SizedQueue.new(10)
+// results = Queue.new
+//
+// 3.times do
+//   thread do
+//     loop do
+//       job = jobs.pop
+//       if job.nil?
+//         break
+//       end
+//       results.push(job * 2)
+//     end
+//   end
+// end
+//
+// 1.upto(5) do |i|
+//   jobs.push(i)
+// end
+// jobs.close
+//
+// results.pop # => 2, 4, 6, 8 or 10
+// ```
+type QueueObject struct {
+	*baseObj
+	lock    sync.Mutex
+	items   []Object
+	max     int
+	closed  bool
+	waiting int
+	// changed is closed and replaced whenever the queue changes, so the waiting threads can check it again
+	changed chan struct{}
+}
+
+// Class methods --------------------------------------------------------
+func builtinQueueClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new queue, which holds the elements of the given array if any.
+			//
+			// ```ruby
+			// Queue.new
+			// Queue.new([1, 2]).length # => 2
+			// ```
+			//
+			// @param items [Array]
+			// @return [Queue]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					q := t.vm.initQueueObject(classes.QueueClass, 0)
+
+					switch len(args) {
+					case 0:
+					case 1:
+						items, ok := args[0].(*ArrayObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.ArrayClass, args[0].Class().Name)
+						}
+
+						q.items = append(q.items, items.Elements...)
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					return q
+				}
+			},
+		},
+	}
+}
+
+func builtinSizedQueueClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new queue that holds at most the given number of objects.
+			//
+			// ```ruby
+			// SizedQueue.new(10)
+			// ```
+			//
+			// @param max [Integer]
+			// @return [SizedQueue]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					max, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if max.value <= 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect queue size to be positive. got: %d", max.value)
+					}
+
+					return t.vm.initQueueObject(classes.SizedQueueClass, max.value)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinQueueInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Same as `push`.
+			//
+			// @param object [Object]
+			// @return [Queue]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.pushQueue(receiver.(*QueueObject), args)
+				}
+			},
+		},
+		{
+			// Removes all objects from the queue.
+			//
+			// ```ruby
+			// q = Queue.new([1, 2])
+			// q.clear
+			// q.empty? # => true
+			// ```
+			//
+			// @return [Queue]
+			Name: "clear",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					q := receiver.(*QueueObject)
+					q.lock.Lock()
+					q.items = nil
+					q.notify()
+					q.lock.Unlock()
+
+					return q
+				}
+			},
+		},
+		{
+			// Closes the queue. Pushing to a closed queue raises a ClosedQueueError, while the objects left in
+			// it can still be popped, and then `pop` returns nil instead of waiting. The threads waiting on the
+			// queue are woken up.
+			//
+			// ```ruby
+			// q = Queue.new([1])
+			// q.close
+			// q.pop # => 1
+			// q.pop # => nil
+			// ```
+			//
+			// @return [Queue]
+			Name: "close",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					q := receiver.(*QueueObject)
+					q.lock.Lock()
+					q.closed = true
+					q.notify()
+					q.lock.Unlock()
+
+					return q
+				}
+			},
+		},
+		{
+			// Returns true if the queue is closed.
+			//
+			// @return [Boolean]
+			Name: "closed?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					q := receiver.(*QueueObject)
+					q.lock.Lock()
+					defer q.lock.Unlock()
+
+					return toBooleanObject(q.closed)
+				}
+			},
+		},
+		{
+			// Returns true if the queue has no objects.
+			//
+			// @return [Boolean]
+			Name: "empty?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					q := receiver.(*QueueObject)
+					q.lock.Lock()
+					defer q.lock.Unlock()
+
+					return toBooleanObject(len(q.items) == 0)
+				}
+			},
+		},
+		{
+			// Returns the number of objects in the queue.
+			//
+			// @return [Integer]
+			Name: "length",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					q := receiver.(*QueueObject)
+					q.lock.Lock()
+					defer q.lock.Unlock()
+
+					return t.vm.initIntegerObject(len(q.items))
+				}
+			},
+		},
+		{
+			// Returns the number of threads waiting on the queue, to pop from it or to push to a full SizedQueue.
+			//
+			// ```ruby
+			// q = Queue.new
+			// thread do
+			//   q.pop
+			// end
+			// sleep(0.1)
+			// q.num_waiting # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "num_waiting",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					q := receiver.(*QueueObject)
+					q.lock.Lock()
+					defer q.lock.Unlock()
+
+					return t.vm.initIntegerObject(q.waiting)
+				}
+			},
+		},
+		{
+			// Removes and returns the first object of the queue. If the queue is empty, it waits until an object
+			// is pushed, or returns nil if the queue is closed.
+			//
+			// With `true` as the argument, it raises a ThreadError instead of waiting. With the `timeout:` option,
+			// it returns nil if no object is pushed in the given seconds.
+			//
+			// ```ruby
+			// q = Queue.new([1])
+			// q.pop             # => 1
+			// q.pop(timeout: 1) # => nil, after a second
+			// q.pop(true)       # => ThreadError: Queue is empty
+			// ```
+			//
+			// @param non_block [Boolean]
+			// @return [Object]
+			Name: "pop",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.popQueue(receiver.(*QueueObject), args)
+				}
+			},
+		},
+		{
+			// Adds the object to the end of the queue and returns the queue. For a SizedQueue that is full, it
+			// waits until an object is popped.
+			//
+			// With `true` as the second argument, it raises a ThreadError instead of waiting. With the `timeout:`
+			// option, it returns nil if the queue is still full after the given seconds. Pushing to a closed queue
+			// raises a ClosedQueueError.
+			//
+			// ```ruby
+			// q = SizedQueue.new(1)
+			// q.push(1)
+			// q.push(2, timeout: 1) # => nil, after a second
+			// q.push(2, true)       # => ThreadError: Queue is full
+			// ```
+			//
+			// @param object [Object], non_block [Boolean]
+			// @return [Queue]
+			Name: "push",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.pushQueue(receiver.(*QueueObject), args)
+				}
+			},
+		},
+		{
+			// Same as `pop`.
+			//
+			// @param non_block [Boolean]
+			// @return [Object]
+			Name: "shift",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.popQueue(receiver.(*QueueObject), args)
+				}
+			},
+		},
+		{
+			// Same as `length`.
+			//
+			// @return [Integer]
+			Name: "size",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("length", receiver, args...)
+				}
+			},
+		},
+	}
+}
+
+func builtinSizedQueueInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the maximum number of objects the queue holds.
+			//
+			// ```ruby
+			// SizedQueue.new(10).max # => 10
+			// ```
+			//
+			// @return [Integer]
+			Name: "max",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initIntegerObject(receiver.(*QueueObject).max)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initQueueObject(className string, max int) *QueueObject {
+	return &QueueObject{
+		baseObj: &baseObj{class: vm.topLevelClass(className)},
+		max:     max,
+		changed: make(chan struct{}),
+	}
+}
+
+func (vm *VM) initQueueClass() *RClass {
+	class := vm.initializeClass(classes.QueueClass, false)
+	class.setBuiltinMethods(builtinQueueClassMethods(), true)
+	class.setBuiltinMethods(builtinQueueInstanceMethods(), false)
+	return class
+}
+
+func (vm *VM) initSizedQueueClass() *RClass {
+	class := vm.initializeClass(classes.SizedQueueClass, false)
+	class.inherits(vm.topLevelClass(classes.QueueClass))
+	class.setBuiltinMethods(builtinSizedQueueClassMethods(), true)
+	class.setBuiltinMethods(builtinSizedQueueInstanceMethods(), false)
+	return class
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (q *QueueObject) toString() string {
+	return fmt.Sprintf("#<%s:%p>", q.class.Name, q)
+}
+
+// Alias of toString
+func (q *QueueObject) toJSON() string {
+	return q.toString()
+}
+
+// Returns the object
+func (q *QueueObject) Value() interface{} {
+	return q.items
+}
+
+// Other helper functions -----------------------------------------------
+
+// notify wakes up the threads waiting on the queue, it's called with the lock held
+func (q *QueueObject) notify() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// wait releases the lock and blocks until the queue changes, then it takes the lock again. It returns false if
+// the deadline passes first, or an error if the thread times out.
+func (q *QueueObject) wait(t *thread, deadline <-chan time.Time) (bool, *Error) {
+	changed := q.changed
+	q.waiting++
+	q.lock.Unlock()
+
+	defer func() {
+		q.lock.Lock()
+		q.waiting--
+	}()
+
+	select {
+	case <-changed:
+		return true, nil
+	case <-deadline:
+		return false, nil
+	case <-t.timedOut():
+		return false, t.timeoutError()
+	}
+}
+
+// queueOptions separates the `non_block` argument and the `timeout:` option from the other arguments of push and pop
+func (t *thread) queueOptions(args []Object, count int) ([]Object, bool, <-chan time.Time, *Error) {
+	var deadline <-chan time.Time
+
+	if len(args) > 0 {
+		if h, ok := args[len(args)-1].(*HashObject); ok {
+			args = args[:len(args)-1]
+
+			for _, k := range h.sortedKeys() {
+				if k != "timeout" {
+					return nil, false, nil, t.initErrorObject(errors.ArgumentError, "Unknown keyword: %s", k)
+				}
+
+				var seconds float64
+
+				switch sec := h.Pairs[k].(type) {
+				case *IntegerObject:
+					seconds = float64(sec.value)
+				case *FloatObject:
+					seconds = sec.value
+				case *NullObject:
+					continue
+				default:
+					return nil, false, nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", sec.Class().Name)
+				}
+
+				deadline = time.After(time.Duration(seconds * float64(time.Second)))
+			}
+		}
+	}
+
+	if len(args) < count || len(args) > count+1 {
+		if count == 0 {
+			return nil, false, nil, t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+		}
+
+		return nil, false, nil, t.initErrorObject(errors.ArgumentError, "Expect %d..%d arguments. got: %d", count, count+1, len(args))
+	}
+
+	nonBlock := len(args) > count && isTruthy(args[count])
+
+	if nonBlock && deadline != nil {
+		return nil, false, nil, t.initErrorObject(errors.ArgumentError, "Can't set a timeout if non_block is enabled")
+	}
+
+	return args[:count], nonBlock, deadline, nil
+}
+
+// pushQueue pushes the object to the queue, waiting while a SizedQueue is full
+func (t *thread) pushQueue(q *QueueObject, args []Object) Object {
+	args, nonBlock, deadline, err := t.queueOptions(args, 1)
+	if err != nil {
+		return err
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for {
+		if q.closed {
+			return t.initErrorObject(errors.ClosedQueueError, "Can't push to a closed queue")
+		}
+
+		if q.max == 0 || len(q.items) < q.max {
+			break
+		}
+
+		if nonBlock {
+			return t.initErrorObject(errors.ThreadError, "Queue is full")
+		}
+
+		ok, err := q.wait(t, deadline)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return NULL
+		}
+	}
+
+	q.items = append(q.items, args[0])
+	q.notify()
+
+	return q
+}
+
+// popQueue removes the first object of the queue, waiting while it's empty and open
+func (t *thread) popQueue(q *QueueObject, args []Object) Object {
+	_, nonBlock, deadline, err := t.queueOptions(args, 0)
+	if err != nil {
+		return err
+	}
+
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	for len(q.items) == 0 {
+		if q.closed {
+			return NULL
+		}
+
+		if nonBlock {
+			return t.initErrorObject(errors.ThreadError, "Queue is empty")
+		}
+
+		ok, err := q.wait(t, deadline)
+		if err != nil {
+			return err
+		}
+
+		if !ok {
+			return NULL
+		}
+	}
+
+	obj := q.items[0]
+	q.items = q.items[1:]
+	q.notify()
+
+	return obj
+}
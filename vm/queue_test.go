@@ -0,0 +1,144 @@
+package vm
+
+import "testing"
+
+func TestQueueMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		q = Queue.new
+		q.push(1)
+		q << 2
+		q.pop + q.shift * 10
+		`, 21},
+		{`
+		Queue.new([1, 2]).size
+		`, 2},
+		{`
+		q = Queue.new([1])
+		q.clear
+		q.empty?
+		`, true},
+		{`
+		q = Queue.new([1])
+		q.close
+		[q.closed?, q.pop, q.pop].to_s
+		`, "[true, 1, nil]"},
+		{`
+		Queue.new.pop(timeout: 0.01)
+		`, nil},
+		{`
+		q = Queue.new
+		thread do
+		  sleep(0.01)
+		  q.push("goby")
+		end
+		q.pop
+		`, "goby"},
+		{`
+		q = Queue.new
+		thread do
+		  q.pop
+		end
+		sleep(0.05)
+		waiting = q.num_waiting
+		q.push(1)
+		sleep(0.05)
+		[waiting, q.num_waiting].to_s
+		`, "[1, 0]"},
+		{`
+		q = Queue.new
+		result = Channel.new
+		thread do
+		  result.deliver(q.pop)
+		end
+		sleep(0.01)
+		q.close
+		result.receive
+		`, nil},
+		{`
+		jobs = SizedQueue.new(2)
+		results = Queue.new
+		wg = WaitGroup.new
+
+		3.times do
+		  wg.add
+		  thread do
+		    loop do
+		      job = jobs.pop
+		      if job.nil?
+		        break
+		      end
+		      results.push(job * 2)
+		    end
+		    wg.done
+		  end
+		end
+
+		1.upto(10) do |i|
+		  jobs.push(i)
+		end
+		jobs.close
+		wg.wait
+
+		sum = 0
+		results.size.times do
+		  sum += results.pop
+		end
+		sum
+		`, 110},
+		{`
+		q = SizedQueue.new(1)
+		q.push(1)
+		q.push(2, timeout: 0.01)
+		`, nil},
+		{`
+		q = SizedQueue.new(1)
+		q.push(1)
+		thread do
+		  sleep(0.01)
+		  q.pop
+		end
+		q.push(2)
+		q.pop
+		`, 2},
+		{`
+		q = SizedQueue.new(3)
+		[q.max, q.is_a?(Queue)].to_s
+		`, "[3, true]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestQueueMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Queue.new(1)`, "TypeError: Expect argument to be Array. got: Integer", 1},
+		{`Queue.new.pop(true)`, "ThreadError: Queue is empty", 1},
+		{`Queue.new.pop(1, 2)`, "ArgumentError: Expect 0..1 argument. got: 2", 1},
+		{`Queue.new.pop(true, timeout: 1)`, "ArgumentError: Can't set a timeout if non_block is enabled", 1},
+		{`Queue.new.pop(wait: 1)`, "ArgumentError: Unknown keyword: wait", 1},
+		{`Queue.new.pop(timeout: "1")`, "TypeError: Expect argument to be Integer or Float. got: String", 1},
+		{`Queue.new.push`, "ArgumentError: Expect 1..2 arguments. got: 0", 1},
+		{`Queue.new.close.push(1)`, "ClosedQueueError: Can't push to a closed queue", 1},
+		{`SizedQueue.new`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`SizedQueue.new(0)`, "ArgumentError: Expect queue size to be positive. got: 0", 1},
+		{`SizedQueue.new(1).push(1).push(2, true)`, "ThreadError: Queue is full", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,269 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// RandomObject is a pseudo-random number generator.
+// Generators created with the same seed produce the same sequence of numbers, which makes
+// simulations and tests reproducible.
+//
+// ```ruby
+// r = Random.new(42)
+// r.rand      # => a Float between 0.0 and 1.0
+// r.rand(6)   # => an Integer between 0 and 5
+// r.rand(1..6) # => an Integer between 1 and 6
+// r.bytes(4)  # => a String of 4 random bytes
+// r.seed      # => 42
+// ```
+//
+// `Kernel#rand` and `Kernel#srand` use a global generator, which can be reseeded with `srand`:
+//
+// ```ruby
+// srand(1234)
+// a = rand(100)
+// srand(1234)
+// a == rand(100) # => true
+// ```
+type RandomObject struct {
+	*baseObj
+	seed      int64
+	generator *rand.Rand
+	mutex     sync.Mutex
+}
+
+// Class methods --------------------------------------------------------
+func builtinRandomClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new generator with the given Integer seed.
+			// A seed based on the current time is used if it's omitted.
+			//
+			// ```ruby
+			// Random.new(42).seed # => 42
+			// ```
+			//
+			// @return [Random]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					seed, err := randomSeed(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initRandomObject(seed)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinRandomInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a String of the given number of random bytes.
+			//
+			// ```ruby
+			// Random.new(42).bytes(4).size # => 4
+			// ```
+			//
+			// @return [String]
+			Name: "bytes",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					n, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					if n.value < 0 {
+						return t.initErrorObject(errors.ArgumentError, "Negative string size: %d", n.value)
+					}
+
+					r := receiver.(*RandomObject)
+					b := make([]byte, n.value)
+
+					r.mutex.Lock()
+					r.generator.Read(b)
+					r.mutex.Unlock()
+
+					return t.vm.initStringObject(string(b))
+				}
+			},
+		},
+		{
+			// Returns a random number, see `Kernel#rand` for the accepted arguments.
+			//
+			// ```ruby
+			// r = Random.new(42)
+			// r.rand      # => a Float between 0.0 and 1.0
+			// r.rand(10)  # => an Integer between 0 and 9
+			// r.rand(1.5) # => a Float between 0.0 and 1.5
+			// r.rand(1..6) # => an Integer between 1 and 6
+			// ```
+			//
+			// @return [Number]
+			Name: "rand",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return randomNumber(t, receiver.(*RandomObject), args)
+				}
+			},
+		},
+		{
+			// Returns the seed of the generator.
+			//
+			// ```ruby
+			// Random.new(42).seed # => 42
+			// ```
+			//
+			// @return [Integer]
+			Name: "seed",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(int(receiver.(*RandomObject).seed))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initRandomObject(seed int64) *RandomObject {
+	return &RandomObject{
+		baseObj:   &baseObj{class: vm.topLevelClass(classes.RandomClass)},
+		seed:      seed,
+		generator: rand.New(rand.NewSource(seed)),
+	}
+}
+
+func (vm *VM) initRandomClass() *RClass {
+	rc := vm.initializeClass(classes.RandomClass, false)
+	rc.setBuiltinMethods(builtinRandomInstanceMethods(), false)
+	rc.setBuiltinMethods(builtinRandomClassMethods(), true)
+	return rc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (r *RandomObject) Value() interface{} {
+	return r.generator
+}
+
+// Returns the generator's string representation
+func (r *RandomObject) toString() string {
+	return fmt.Sprintf("<Random: %p>", r)
+}
+
+// Alias of toString
+func (r *RandomObject) toJSON() string {
+	return r.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// randomSeed returns the optional Integer seed in args, or a seed based on the current time
+func randomSeed(t *thread, args []Object) (int64, *Error) {
+	switch len(args) {
+	case 0:
+		return time.Now().UnixNano(), nil
+	case 1:
+		seed, ok := args[0].(*IntegerObject)
+
+		if !ok {
+			return 0, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		return int64(seed.value), nil
+	default:
+		return 0, t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+}
+
+// randomNumber returns a Float in [0, 1) without a limit, an Integer in [0, max) for an Integer or a BigInt,
+// a Float in [0, max) for a Float, or a number within a Range
+func randomNumber(t *thread, r *RandomObject, args []Object) Object {
+	if len(args) > 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if len(args) == 0 {
+		return t.vm.initFloatObject(r.generator.Float64())
+	}
+
+	switch max := args[0].(type) {
+	case *IntegerObject:
+		if max.value <= 0 {
+			return t.initErrorObject(errors.ArgumentError, "Invalid argument - %d", max.value)
+		}
+
+		return t.vm.initIntegerObject(int(r.generator.Int63n(int64(max.value))))
+	case *BigIntObject:
+		if max.value.Sign() <= 0 {
+			return t.initErrorObject(errors.ArgumentError, "Invalid argument - %s", max.value.String())
+		}
+
+		return t.vm.initIntegerFromBigInt(new(big.Int).Rand(r.generator, max.value))
+	case *FloatObject:
+		if !(max.value > 0) || math.IsInf(max.value, 1) {
+			return t.initErrorObject(errors.ArgumentError, "Invalid argument - %s", formatFloat(max.value))
+		}
+
+		return t.vm.initFloatObject(r.generator.Float64() * max.value)
+	case *RangeObject:
+		if max.isString {
+			return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, classes.StringClass)
+		}
+
+		if max.endless {
+			return t.initErrorObject(errors.ArgumentError, "Invalid argument - %s", max.toString())
+		}
+
+		if max.isFloat {
+			if max.floatStart > max.floatEnd {
+				return NULL
+			}
+
+			return t.vm.initFloatObject(max.floatStart + r.generator.Float64()*(max.floatEnd-max.floatStart))
+		}
+
+		end := max.End
+
+		if max.exclusive {
+			end--
+		}
+
+		if max.Start > end {
+			return NULL
+		}
+
+		return t.vm.initIntegerObject(max.Start + int(r.generator.Int63n(int64(end-max.Start)+1)))
+	case *NullObject:
+		return t.vm.initFloatObject(r.generator.Float64())
+	default:
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, max.Class().Name)
+	}
+}
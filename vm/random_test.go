@@ -0,0 +1,95 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestRandom(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Random.new(42).class.name`, "Random"},
+		{`Random.new(42).seed`, 42},
+		{`Random.new.seed.class.name`, "Integer"},
+		{`
+		a = Random.new(42)
+		b = Random.new(42)
+		a.rand(1000) == b.rand(1000) && a.rand == b.rand && a.bytes(8) == b.bytes(8)
+		`, true},
+		{`
+		r = Random.new(1)
+		n = r.rand
+		n >= 0 && n < 1 && n.class.name == "Float"
+		`, true},
+		{`
+		r = Random.new(1)
+		ok = true
+		i = 0
+		while i < 100 do
+		  n = r.rand(6)
+		  ok = ok && n >= 0 && n < 6
+		  m = r.rand(3..5)
+		  ok = ok && m >= 3 && m <= 5
+		  f = r.rand(1.5)
+		  ok = ok && f >= 0 && f < 1.5
+		  i += 1
+		end
+		ok
+		`, true},
+		{`Random.new(1).rand(3..3)`, 3},
+		{`Random.new(1).rand(5..1)`, nil},
+		{`Random.new(1).rand(3...4)`, 3},
+		{`Random.new(1).rand(3...3)`, nil},
+		{`Random.new(1).rand(1.0..1.5).class.name`, "Float"},
+		{`Random.new(1).rand(2.0..1.0)`, nil},
+		{`Random.new(1).rand(nil).class.name`, "Float"},
+		{`Random.new(1).rand(18446744073709551616).class.name`, "BigInt"},
+		{`Random.new(1).bytes(5).size`, 5},
+		{`Random.new(1).bytes(0)`, ""},
+		{`
+		srand(1234)
+		a = [rand(100), rand]
+		srand(1234)
+		a == [rand(100), rand]
+		`, true},
+		{`
+		srand(99)
+		srand(1)
+		`, 99},
+		{`srand.class.name`, "Integer"},
+		{`rand.class.name`, "Float"},
+		{`rand(1)`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRandomFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Random.new("1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Random.new(1, 2)`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`Random.new(1).rand(0)`, "ArgumentError: Invalid argument - 0", 1},
+		{`Random.new(1).rand(-1.5)`, "ArgumentError: Invalid argument - -1.5", 1},
+		{`Random.new(1).rand(1, 2)`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`Random.new(1).rand("a")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Random.new(1).rand(1..Float::INFINITY)`, "ArgumentError: Invalid argument - (1..Infinity)", 1},
+		{`Random.new(1).bytes(-1)`, "ArgumentError: Negative string size: -1", 1},
+		{`Random.new(1).bytes`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`rand(0)`, "ArgumentError: Invalid argument - 0", 1},
+		{`srand(1.5)`, "TypeError: Expect argument to be Integer. got: Float", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
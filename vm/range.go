@@ -2,6 +2,9 @@ package vm
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"unicode/utf8"
 
 	"github.com/goby-lang/goby/vm/classes"
 	"github.com/goby-lang/goby/vm/errors"
@@ -9,7 +12,7 @@ import (
 
 // RangeObject is the built in range class
 // Range represents an interval: a set of values from the beginning to the end specified.
-// Currently, only Integer objects or integer literal are supported.
+// Ranges of Integers and ranges of Strings are supported.
 //
 // ```ruby
 // r = 0
@@ -27,10 +30,57 @@ import (
 // end
 // ```
 //
+// A String range iterates over the strings generated by `String#succ`, from the start until it reaches
+// the end or gets longer than the end.
+//
+// ```ruby
+// ("a".."e").to_a   # => ["a", "b", "c", "d", "e"]
+// ("y".."ab").to_a  # => []
+// ("az".."bc").to_a # => ["az", "ba", "bb", "bc"]
+// ```
+//
+// A three-dot range excludes its end:
+//
+// ```ruby
+// (1...5).to_a     # => [1, 2, 3, 4]
+// ("a"..."c").to_a # => ["a", "b"]
+// ```
+//
+// A range with a Float bound can't be iterated, but it can be checked with `#include?` and `#cover?` or
+// walked with `#step`:
+//
+// ```ruby
+// (0.5..2.5).include?(1.2) # => true
+// (1.0..2.0).step(0.5) do |f|
+//   puts(f) # => 1.0, 1.5, 2.0
+// end
+// ```
+//
+// Range includes Enumerable, so methods like `map` and `select` iterate over the values from `#each`.
+//
+// A range that ends with `Float::INFINITY` is endless, it's iterated until `break` or used with `lazy`:
+//
+// ```ruby
+// (1..Float::INFINITY).lazy.map do |i|
+//   i * 2
+// end.first(3) # => [2, 4, 6]
+// ```
 type RangeObject struct {
 	*baseObj
 	Start int
 	End   int
+	// endless is true for Integer ranges that end with Float::INFINITY, End isn't used for them
+	endless bool
+	// exclusive is true for three-dot ranges, which don't include their end
+	exclusive bool
+	// isFloat is true for ranges with a Float bound, their bounds are kept in floatStart and floatEnd instead of Start and End
+	isFloat    bool
+	floatStart float64
+	floatEnd   float64
+	// isString is true for String ranges, their bounds are kept in strStart and strEnd instead of Start and End
+	isString bool
+	strStart string
+	strEnd   string
 }
 
 // Class methods --------------------------------------------------------
@@ -56,6 +106,7 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// ```ruby
 			// (1..5) == (1..5) # => true
 			// (1..5) == (1..6) # => false
+			// ("a".."e") == ("a".."e") # => true
 			// ```
 			//
 			// @return [Boolean]
@@ -71,7 +122,7 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 						return FALSE
 					}
 
-					if left.Start == right.Start && left.End == right.End {
+					if left.equal(right) {
 						return TRUE
 					}
 
@@ -100,7 +151,7 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 						return TRUE
 					}
 
-					if left.Start == right.Start && left.End == right.End {
+					if left.equal(right) {
 						return FALSE
 					}
 
@@ -148,20 +199,40 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// (0..4).bsearch {|i|  50 - ary[i] } #=> nil
 			// ```
 			//
+			// A range with a Float bound can't be searched.
+			//
 			// @return [Integer]
 			Name: "bsearch",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					ran := receiver.(*RangeObject)
 
-					if ran.Start > ran.End || ran.Start < 0 {
+					if ran.isString {
+						return t.initErrorObject(errors.TypeError, "Can't do binary search for String")
+					}
+
+					if ran.isFloat {
+						return t.initErrorObject(errors.TypeError, "Can't do binary search for Float")
+					}
+
+					if ran.endless {
+						return t.initErrorObject(errors.TypeError, "Can't do binary search for endless Range")
+					}
+
+					start := ran.Start
+					end := ran.End
+
+					if ran.exclusive {
+						end--
+					}
+
+					if start > end || start < 0 {
 						// if block is not used, it should be popped
 						t.callFrameStack.pop()
 						return NULL
 					}
 
-					start := ran.Start
-					end := ran.End
+					last := end
 					var mid int
 					pivot := -1
 
@@ -188,7 +259,7 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 
 							if r.value {
 								end = mid - 1
-							} else if mid+1 > ran.End {
+							} else if mid+1 > last {
 								return NULL
 							} else {
 								start = mid + 1
@@ -208,12 +279,69 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 								end = mid - 1
 							}
 						default:
-							return t.vm.initErrorObject(errors.TypeError, "Expect Integer or Boolean type. got=%s", r.Class().Name)
+							return t.initErrorObject(errors.TypeError, "Expect Integer or Boolean type. got=%s", r.Class().Name)
 						}
 					}
 				}
 			},
 		},
+		{
+			// Returns true if the given object is between the start and the end of the range. Unlike `#include?`,
+			// String ranges are compared by their bounds instead of their values.
+			//
+			// ```ruby
+			// (1..5).cover?(3)           # => true
+			// (1...5).cover?(5)          # => false
+			// (1..5).cover?(4.5)         # => true
+			// ("a".."e").cover?("cc")    # => true
+			// ("a".."e").include?("cc")  # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "cover?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					ran := receiver.(*RangeObject)
+
+					if !ran.isString {
+						return toBooleanObject(ran.coversNumber(args[0]))
+					}
+
+					str, ok := args[0].(*StringObject)
+
+					if !ok || str.value < ran.strStart {
+						return FALSE
+					}
+
+					if ran.exclusive {
+						return toBooleanObject(str.value < ran.strEnd)
+					}
+
+					return toBooleanObject(str.value <= ran.strEnd)
+				}
+			},
+		},
+		{
+			// Returns true if the given object is covered by the range, so a range can be used as a pattern.
+			//
+			// ```ruby
+			// (1..5) === 3     # => true
+			// (1..5) === 6     # => false
+			// (1..5) === "a"   # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "===",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("cover?", receiver, args...)
+				}
+			},
+		},
 		{
 			// Iterates over the elements of range, passing each in turn to the block.
 			// Returns `nil`.
@@ -230,11 +358,23 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			//   sum = sum + i
 			// end
 			// sum # => -15
+			//
+			// s = ""
+			// ("a".."e").each do |c|
+			//   s = s + c
+			// end
+			// s # => "abcde"
+			//
+			// sum = 0
+			// (1...5).each do |i|
+			//   sum = sum + i
+			// end
+			// sum # => 10
 			// ```
 			//
 			// **Note:**
 			// - Only `do`-`end` block is supported for now: `{ }` block is unavailable.
-			// - Three-dot range `...` is not supported yet.
+			// - A range with a Float bound can't be iterated.
 			//
 			// @return [Range]
 			Name: "each",
@@ -243,16 +383,30 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 					ran := receiver.(*RangeObject)
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "each", args)
 					}
 
-					if ran.Start <= ran.End {
-						for i := ran.Start; i <= ran.End; i++ {
-							obj := t.vm.initIntegerObject(i)
-							t.builtinMethodYield(blockFrame, obj)
+					if ran.isFloat {
+						return t.initErrorObject(errors.TypeError, "Can't iterate from Float")
+					}
+
+					if ran.isString {
+						for _, str := range ran.stringValues() {
+							t.builtinMethodYield(blockFrame, t.vm.initStringObject(str))
+						}
+					} else if ran.endless {
+						for i := ran.Start; ; i++ {
+							t.builtinMethodYield(blockFrame, t.vm.initIntegerObject(i))
 						}
 					} else {
-						for i := ran.End; i <= ran.Start; i++ {
+						low, high := ran.integerBounds()
+
+						if low > high {
+							// if block is not used, it should be popped
+							t.callFrameStack.pop()
+						}
+
+						for i := low; i <= high; i++ {
 							obj := t.vm.initIntegerObject(i)
 							t.builtinMethodYield(blockFrame, obj)
 						}
@@ -269,6 +423,8 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// (5..1).first   # => 5
 			// (-2..3).first  # => -2
 			// (-5..-7).first # => -5
+			// ("a".."e").first # => "a"
+			// (1.5..2).first   # => 1.5
 			// ```
 			//
 			// @return [Integer]
@@ -276,6 +432,15 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					ran := receiver.(*RangeObject)
+
+					if ran.isString {
+						return t.vm.initStringObject(ran.strStart)
+					}
+
+					if ran.isFloat {
+						return t.vm.initFloatObject(ran.floatStart)
+					}
+
 					return t.vm.initIntegerObject(ran.Start)
 				}
 			},
@@ -295,6 +460,11 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// (1..-5).include?(-2)  # => true
 			// (-2..-5).include?(-2) # => true
 			// (-3..-5).include?(-2) # => false
+			// ("a".."e").include?("c") # => true
+			// ("a".."e").include?("cc") # => false
+			// (1...5).include?(5)   # => false
+			// (1..2).include?(1.5)  # => true
+			// (0.5..1.5).include?(1) # => true
 			// ```
 			// @return [Boolean]
 			Name: "include?",
@@ -302,14 +472,23 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					ran := receiver.(*RangeObject)
 
-					value := args[0].(*IntegerObject).value
-					ascendRangeBool := ran.Start <= ran.End && value >= ran.Start && value <= ran.End
-					descendRangeBool := ran.End <= ran.Start && value <= ran.Start && value >= ran.End
+					if ran.isString {
+						str, ok := args[0].(*StringObject)
 
-					if ascendRangeBool || descendRangeBool {
-						return TRUE
+						if !ok {
+							return FALSE
+						}
+
+						for _, v := range ran.stringValues() {
+							if v == str.value {
+								return TRUE
+							}
+						}
+
+						return FALSE
 					}
-					return FALSE
+
+					return toBooleanObject(ran.coversNumber(args[0]))
 				}
 			},
 		},
@@ -321,6 +500,9 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// (5..1).last   # => 1
 			// (-2..3).last  # => 3
 			// (-5..-7).last # => -7
+			// ("a".."e").last # => "e"
+			// (1...5).last  # => 5
+			// (1..2.5).last # => 2.5
 			// ```
 			//
 			// @return [Integer]
@@ -328,6 +510,19 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					ran := receiver.(*RangeObject)
+
+					if ran.isString {
+						return t.vm.initStringObject(ran.strEnd)
+					}
+
+					if ran.isFloat {
+						return t.vm.initFloatObject(ran.floatEnd)
+					}
+
+					if ran.endless {
+						return t.initErrorObject(errors.ArgumentError, "Can't get the last value of endless Range")
+					}
+
 					return t.vm.initIntegerObject(ran.End)
 				}
 			},
@@ -340,6 +535,8 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// (3..9).size   # => 7
 			// (-1..-5).size # => 5
 			// (-1..7).size  # => 9
+			// (1...5).size   # => 4
+			// ("a".."e").size # => nil
 			// ```
 			// @return [Integer]
 			Name: "size",
@@ -347,10 +544,24 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					ran := receiver.(*RangeObject)
 
-					if ran.Start <= ran.End {
-						return t.vm.initIntegerObject(ran.End - ran.Start + 1)
+					if ran.isString {
+						return NULL
+					}
+
+					if ran.isFloat {
+						return t.initErrorObject(errors.TypeError, "Can't iterate from Float")
 					}
-					return t.vm.initIntegerObject(ran.Start - ran.End + 1)
+
+					if ran.endless {
+						return t.vm.initFloatObject(math.Inf(1))
+					}
+
+					low, high := ran.integerBounds()
+
+					if low > high {
+						return t.vm.initIntegerObject(0)
+					}
+					return t.vm.initIntegerObject(high - low + 1)
 				}
 			},
 		},
@@ -382,6 +593,28 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			//   sum = sum + 1
 			// end
 			// sum # => 0
+			//
+			// s = ""
+			// ("a".."e").step(2) do |c|
+			//   s = s + c
+			// end
+			// s # => "ace"
+			//
+			// sum = 0
+			// (1...7).step(3) do |i|
+			//   sum = sum + i
+			// end
+			// sum # => 5
+			// ```
+			//
+			// A Float step or a range with a Float bound yields Floats:
+			//
+			// ```ruby
+			// a = []
+			// (1..2).step(0.5) do |f|
+			//   a.push(f)
+			// end
+			// a # => [1.0, 1.5, 2.0]
 			// ```
 			//
 			// @return [Range]
@@ -391,19 +624,80 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 					ran := receiver.(*RangeObject)
 
 					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+						return t.vm.initEnumeratorObject(receiver, "step", args)
+					}
+
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if _, ok := args[0].(*FloatObject); (ok && !ran.isString) || ran.isFloat {
+						stepValue, ok := toFloat64(args[0])
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.FloatClass, args[0].Class().Name)
+						}
+
+						if stepValue == 0 {
+							return newError("Step can't be 0")
+						} else if stepValue < 0 {
+							return newError("Step can't be negative")
+						}
+
+						start, end := ran.floatBounds()
+
+						if start > end || (ran.exclusive && start == end) {
+							// if block is not used, it should be popped
+							t.callFrameStack.pop()
+							return ran
+						}
+
+						// Multiplying the step instead of adding it up keeps the values from drifting
+						for i := 0; ; i++ {
+							v := start + float64(i)*stepValue
+
+							if v > end || (ran.exclusive && v == end) {
+								break
+							}
+
+							t.builtinMethodYield(blockFrame, t.vm.initFloatObject(v))
+						}
+
+						return ran
 					}
 
-					stepValue := args[0].(*IntegerObject).value
+					s, ok := args[0].(*IntegerObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					stepValue := s.value
 					if stepValue == 0 {
 						return newError("Step can't be 0")
 					} else if stepValue < 0 {
 						return newError("Step can't be negative")
 					}
 
+					if ran.isString {
+						for i, str := range ran.stringValues() {
+							if i%stepValue == 0 {
+								t.builtinMethodYield(blockFrame, t.vm.initStringObject(str))
+							}
+						}
+
+						return ran
+					}
+
+					if ran.endless {
+						for i := ran.Start; ; i += stepValue {
+							t.builtinMethodYield(blockFrame, t.vm.initIntegerObject(i))
+						}
+					}
+
 					// range end must greater or equal than range start to execute the block
-					if ran.End >= ran.Start {
-						for i := ran.Start; i <= ran.End; i += stepValue {
+					if _, high := ran.integerBounds(); ran.End >= ran.Start && high >= ran.Start {
+						for i := ran.Start; i <= high; i += stepValue {
 							obj := t.vm.initIntegerObject(i)
 							t.builtinMethodYield(blockFrame, obj)
 						}
@@ -426,6 +720,8 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// (1..5).to_a[2]  # => 3
 			// (-1..-5).to_a   # => [-1, -2, -3, -4, -5]
 			// (-1..3).to_a    # => [-1, 0, 1, 2, 3]
+			// ("a".."c").to_a # => ["a", "b", "c"]
+			// (1...4).to_a    # => [1, 2, 3]
 			// ```
 			//
 			// @return [Array]
@@ -434,14 +730,24 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					ro := receiver.(*RangeObject)
 
+					if ro.isFloat {
+						return t.initErrorObject(errors.TypeError, "Can't iterate from Float")
+					}
+
+					if ro.endless {
+						return t.initErrorObject(errors.ArgumentError, "Can't convert endless Range into Array")
+					}
+
 					elems := []Object{}
 
-					if ro.Start <= ro.End {
-						for i := ro.Start; i <= ro.End; i++ {
-							elems = append(elems, t.vm.initIntegerObject(i))
+					if ro.isString {
+						for _, str := range ro.stringValues() {
+							elems = append(elems, t.vm.initStringObject(str))
 						}
 					} else {
-						for i := ro.End; i <= ro.Start; i++ {
+						low, high := ro.integerBounds()
+
+						for i := low; i <= high; i++ {
 							elems = append(elems, t.vm.initIntegerObject(i))
 						}
 					}
@@ -456,6 +762,9 @@ func builtinRangeInstanceMethods() []*BuiltinMethodObject {
 			// ```ruby
 			// (1..5).to_s   # "(1..5)"
 			// (-1..-3).to_s # "(-1..-3)"
+			// ("a".."c").to_s # "(\"a\"..\"c\")"
+			// (1...5).to_s  # "(1...5)"
+			// (1..2.5).to_s # "(1.0..2.5)"
 			// ```
 			// @return [String]
 			Name: "to_s",
@@ -482,10 +791,37 @@ func (vm *VM) initRangeObject(start, end int) *RangeObject {
 	}
 }
 
+// initEndlessRangeObject returns an Integer range from start to Float::INFINITY
+func (vm *VM) initEndlessRangeObject(start int) *RangeObject {
+	r := vm.initRangeObject(start, start)
+	r.endless = true
+	return r
+}
+
+// initFloatRangeObject returns a range with Float bounds
+func (vm *VM) initFloatRangeObject(start, end float64) *RangeObject {
+	return &RangeObject{
+		baseObj:    &baseObj{class: vm.topLevelClass(classes.RangeClass)},
+		isFloat:    true,
+		floatStart: start,
+		floatEnd:   end,
+	}
+}
+
+func (vm *VM) initStringRangeObject(start, end string) *RangeObject {
+	return &RangeObject{
+		baseObj:  &baseObj{class: vm.topLevelClass(classes.RangeClass)},
+		isString: true,
+		strStart: start,
+		strEnd:   end,
+	}
+}
+
 func (vm *VM) initRangeClass() *RClass {
 	rc := vm.initializeClass(classes.RangeClass, false)
 	rc.setBuiltinMethods(builtinRangeInstanceMethods(), false)
 	rc.setBuiltinMethods(builtinRangeClassMethods(), true)
+	rc.include(vm.topLevelClass(classes.EnumerableModule))
 	return rc
 }
 
@@ -493,10 +829,135 @@ func (vm *VM) initRangeClass() *RClass {
 
 // Returns the object's name
 func (ro *RangeObject) toString() string {
-	return fmt.Sprintf("(%d..%d)", ro.Start, ro.End)
+	dots := ".."
+
+	if ro.exclusive {
+		dots = "..."
+	}
+
+	if ro.isString {
+		return fmt.Sprintf("(%s%s%s)", strconv.Quote(ro.strStart), dots, strconv.Quote(ro.strEnd))
+	}
+
+	if ro.isFloat {
+		return fmt.Sprintf("(%s%s%s)", formatFloat(ro.floatStart), dots, formatFloat(ro.floatEnd))
+	}
+
+	if ro.endless {
+		return fmt.Sprintf("(%d%sInfinity)", ro.Start, dots)
+	}
+
+	return fmt.Sprintf("(%d%s%d)", ro.Start, dots, ro.End)
 }
 
 // Alias of toString
 func (ro *RangeObject) toJSON() string {
 	return ro.toString()
 }
+
+// equal returns true if the two ranges have the same type and bounds
+func (ro *RangeObject) equal(other *RangeObject) bool {
+	if ro.isString != other.isString || ro.isFloat != other.isFloat || ro.exclusive != other.exclusive {
+		return false
+	}
+
+	if ro.isString {
+		return ro.strStart == other.strStart && ro.strEnd == other.strEnd
+	}
+
+	if ro.isFloat {
+		return ro.floatStart == other.floatStart && ro.floatEnd == other.floatEnd
+	}
+
+	if ro.endless || other.endless {
+		return ro.endless == other.endless && ro.Start == other.Start
+	}
+
+	return ro.Start == other.Start && ro.End == other.End
+}
+
+// stringValues returns the strings of a String range, which are generated by `String#succ` from the start
+// until reaching the end or getting longer than the end
+func (ro *RangeObject) stringValues() []string {
+	values := []string{}
+
+	if ro.strStart > ro.strEnd {
+		return values
+	}
+
+	endLength := utf8.RuneCountInString(ro.strEnd)
+
+	for str := ro.strStart; str != "" && utf8.RuneCountInString(str) <= endLength; str = succString(str) {
+		if str == ro.strEnd {
+			if !ro.exclusive {
+				values = append(values, str)
+			}
+
+			break
+		}
+
+		values = append(values, str)
+	}
+
+	return values
+}
+
+// integerBounds returns the lowest and the highest values of an Integer range, the range is empty if low is
+// greater than high
+func (ro *RangeObject) integerBounds() (low, high int) {
+	if ro.Start <= ro.End {
+		low, high = ro.Start, ro.End
+
+		if ro.exclusive {
+			high--
+		}
+
+		return
+	}
+
+	low, high = ro.End, ro.Start
+
+	if ro.exclusive {
+		low++
+	}
+
+	return
+}
+
+// floatBounds returns the start and the end of a numeric range as float64
+func (ro *RangeObject) floatBounds() (start, end float64) {
+	switch {
+	case ro.isFloat:
+		return ro.floatStart, ro.floatEnd
+	case ro.endless:
+		return float64(ro.Start), math.Inf(1)
+	default:
+		return float64(ro.Start), float64(ro.End)
+	}
+}
+
+// coversNumber returns true if obj is a number between the bounds of a numeric range
+func (ro *RangeObject) coversNumber(obj Object) bool {
+	if i, ok := obj.(*IntegerObject); ok && !ro.isFloat {
+		if ro.endless {
+			return i.value >= ro.Start
+		}
+
+		low, high := ro.integerBounds()
+		return low <= i.value && i.value <= high
+	}
+
+	value, ok := toFloat64(obj)
+
+	if !ok || ro.isString {
+		return false
+	}
+
+	start, end := ro.floatBounds()
+
+	if ro.exclusive && value == end {
+		return false
+	}
+
+	return (start <= value && value <= end) || (end <= value && value <= start)
+}
@@ -527,3 +527,247 @@ func TestRangeToArrayMethod(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestStringRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`("a".."e").to_a.to_s`, `["a", "b", "c", "d", "e"]`},
+		{`("az".."bc").to_a.to_s`, `["az", "ba", "bb", "bc"]`},
+		{`("y".."ab").to_a.to_s`, `[]`},
+		{`("e".."a").to_a.to_s`, `[]`},
+		{`("a".."a").to_a.to_s`, `["a"]`},
+		{`
+		s = ""
+		("a".."e").each do |c|
+		  s = s + c
+		end
+		s
+		`, "abcde"},
+		{`
+		s = ""
+		("a".."g").step(3) do |c|
+		  s = s + c
+		end
+		s
+		`, "adg"},
+		{`("a".."e").first`, "a"},
+		{`("a".."e").last`, "e"},
+		{`("a".."e").include?("c")`, true},
+		{`("a".."e").include?("cc")`, false},
+		{`("a".."e").include?(1)`, false},
+		{`("a".."e").size`, nil},
+		{`("a".."e").to_s`, `("a".."e")`},
+		{`("a".."e") == ("a".."e")`, true},
+		{`("a".."e") == ("a".."f")`, false},
+		{`("a".."e") != (1..5)`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringRangeFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`(1.."a")`, "ArgumentError: Bad value for range: Integer..String", 1},
+		{`("a"..nil)`, "ArgumentError: Bad value for range: String..Null", 1},
+		{`("a".."e").bsearch do |c| true end`, "TypeError: Can't do binary search for String", 1},
+		{`"Goby"["a".."b"]`, `TypeError: Expect slice range to be Integer range. got: ("a".."b")`, 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestExclusiveRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1...5).to_a.to_s`, "[1, 2, 3, 4]"},
+		{`(1...1).to_a.to_s`, "[]"},
+		{`(1...5).size`, 4},
+		{`(1...1).size`, 0},
+		{`(1...5).to_s`, "(1...5)"},
+		{`(1...5).last`, 5},
+		{`(1...5).include?(4)`, true},
+		{`(1...5).include?(5)`, false},
+		{`(1...5) == (1...5)`, true},
+		{`(1...5) == (1..5)`, false},
+		{`(1...5) != (1..4)`, true},
+		{`("a"..."d").to_a.to_s`, `["a", "b", "c"]`},
+		{`("a"..."d").include?("d")`, false},
+		{`("a"..."d").to_s`, `("a"..."d")`},
+		{`
+		sum = 0
+		(1...5).each do |i|
+		  sum = sum + i
+		end
+		sum
+		`, 10},
+		{`
+		sum = 0
+		(1...1).each do |i|
+		  sum = sum + i
+		end
+		sum
+		`, 0},
+		{`
+		sum = 0
+		(1...7).step(3) do |i|
+		  sum = sum + i
+		end
+		sum
+		`, 5},
+		{`
+		(0...4).bsearch do |i|
+		  i >= 3
+		end
+		`, 3},
+		{`
+		(0...3).bsearch do |i|
+		  i >= 3
+		end
+		`, nil},
+		{`"Goby"[0...2]`, "Go"},
+		{`"Goby"[1..Float::INFINITY]`, "oby"},
+		{`(1...Float::INFINITY).to_s`, "(1...Infinity)"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRangeCoverMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1..5).cover?(3)`, true},
+		{`(1..5).cover?(6)`, false},
+		{`(1...5).cover?(5)`, false},
+		{`(1..5).cover?(4.5)`, true},
+		{`(1...5).cover?(4.5)`, true},
+		{`(1..5).cover?("a")`, false},
+		{`(1..Float::INFINITY).cover?(1000000)`, true},
+		{`("a".."e").cover?("cc")`, true},
+		{`("a".."e").cover?("f")`, false},
+		{`("a"..."e").cover?("e")`, false},
+		{`("a".."e").cover?(1)`, false},
+		{`(1..5) === 3`, true},
+		{`("a".."e") === "f"`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatRange(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1.0..2.0).to_s`, "(1.0..2.0)"},
+		{`(1..2.5).to_s`, "(1.0..2.5)"},
+		{`(0.5...2).to_s`, "(0.5...2.0)"},
+		{`(1.5..2).first`, 1.5},
+		{`(1..2.5).last`, 2.5},
+		{`(1.0..2.0).include?(1.5)`, true},
+		{`(1.0..2.0).include?(2)`, true},
+		{`(1.0...2.0).include?(2.0)`, false},
+		{`(1.0..2.0).include?(2.5)`, false},
+		{`(1.0..2.0).include?("a")`, false},
+		{`(1.0..2.0).cover?(1)`, true},
+		{`(1..2).include?(1.5)`, true},
+		{`(1.0..2.0) == (1.0..2.0)`, true},
+		{`(1.0..2.0) == (1..2)`, false},
+		{`
+		a = []
+		(1.0..2.0).step(0.5) do |f|
+		  a.push(f)
+		end
+		a.to_s
+		`, "[1.0, 1.5, 2.0]"},
+		{`
+		a = []
+		(1..2).step(0.5) do |f|
+		  a.push(f)
+		end
+		a.to_s
+		`, "[1.0, 1.5, 2.0]"},
+		{`
+		a = []
+		(1.0...2.0).step(0.5) do |f|
+		  a.push(f)
+		end
+		a.to_s
+		`, "[1.0, 1.5]"},
+		{`
+		a = []
+		(0.5..2).step(1) do |f|
+		  a.push(f)
+		end
+		a.to_s
+		`, "[0.5, 1.5]"},
+		{`
+		a = []
+		(2.0..1.0).step(0.5) do |f|
+		  a.push(f)
+		end
+		a.to_s
+		`, "[]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestFloatRangeFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`(1.0..2.0).to_a`, "TypeError: Can't iterate from Float", 1},
+		{`(1.0..2.0).size`, "TypeError: Can't iterate from Float", 1},
+		{`(1.0..2.0).each do |f| end`, "TypeError: Can't iterate from Float", 1},
+		{`(1.0..2.0).bsearch do |f| true end`, "TypeError: Can't do binary search for Float", 1},
+		{`(1.0..2.0).step("a") do |f| end`, "TypeError: Expect argument to be Float. got: String", 1},
+		{`(1..2).step("a") do |f| end`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`("a".."c").step(0.5) do |f| end`, "TypeError: Expect argument to be Integer. got: Float", 1},
+		{`(1..2).step do |f| end`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`(1..2).cover?`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`(1.0.."a")`, "ArgumentError: Bad value for range: Float..String", 1},
+		{`"Goby"[0.5..2]`, "TypeError: Expect slice range to be Integer range. got: (0.5..2.0)", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
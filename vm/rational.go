@@ -0,0 +1,656 @@
+package vm
+
+import (
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// RationalObject represents exact fractions, which are useful for calculations that can't afford the
+// rounding errors of Float, like money.
+// Rationals are created with the `r` suffix on numeric literals, `Rational(numerator, denominator)`
+// or `#to_r`, and are always kept in lowest terms.
+//
+// ```ruby
+// 1/3r                 # => 1/3
+// Rational(3, 6)       # => 1/2
+// Rational("0.1") * 3  # => 3/10
+// 0.1r + 0.2r == 0.3r  # => true
+// 0.1 + 0.2 == 0.3     # => false
+// ```
+//
+// Arithmetic between a Rational and an Integer results in a Rational, and arithmetic with a Float
+// results in a Float.
+//
+// **Note:**
+//
+// - Rational includes Comparable, so `<`, `<=`, `>` and `>=` are based on `#<=>`.
+// - `Rational.new` is not supported.
+type RationalObject struct {
+	*baseObj
+	value *big.Rat
+}
+
+// Class methods --------------------------------------------------------
+func builtinRationalClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinRationalInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the sum of self and a number.
+			//
+			// ```ruby
+			// 1/3r + 1/6r # => 1/2
+			// 1/2r + 1    # => 3/2
+			// 1/2r + 0.5  # => 1.0
+			// ```
+			//
+			// @return [Rational]
+			Name: "+",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalOperation(t, receiver, args, "+")
+				}
+			},
+		},
+		{
+			// Returns the modulo of self divided by a number, the result has the same sign as the divisor.
+			//
+			// ```ruby
+			// 7/2r % 1 # => 1/2
+			// ```
+			//
+			// @return [Rational]
+			Name: "%",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalOperation(t, receiver, args, "%")
+				}
+			},
+		},
+		{
+			// Returns the subtraction of a number from self.
+			//
+			// ```ruby
+			// 1/2r - 1/3r # => 1/6
+			// ```
+			//
+			// @return [Rational]
+			Name: "-",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalOperation(t, receiver, args, "-")
+				}
+			},
+		},
+		{
+			// Returns the negation of self, which is what unary minus calls.
+			//
+			// ```ruby
+			// -(1/2r) # => -1/2
+			// ```
+			//
+			// @return [Rational]
+			Name: "-@",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initRationalObject(new(big.Rat).Neg(receiver.(*RationalObject).value))
+				}
+			},
+		},
+		{
+			// Returns self multiplied by a number.
+			//
+			// ```ruby
+			// 2/3r * 3/4r # => 1/2
+			// ```
+			//
+			// @return [Rational]
+			Name: "*",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalOperation(t, receiver, args, "*")
+				}
+			},
+		},
+		{
+			// Returns self raised to the power of an Integer, or of a Float as a Float.
+			//
+			// ```ruby
+			// (2/3r) ** 2  # => 4/9
+			// (2/3r) ** -1 # => 3/2
+			// ```
+			//
+			// @return [Rational]
+			Name: "**",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					switch e := args[0].(type) {
+					case *IntegerObject:
+						r := receiver.(*RationalObject).value
+						exponent := big.NewInt(int64(e.value))
+
+						if e.value < 0 {
+							if r.Sign() == 0 {
+								return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+							}
+
+							r = new(big.Rat).Inv(r)
+							exponent.Neg(exponent)
+						}
+
+						num := new(big.Int).Exp(r.Num(), exponent, nil)
+						denom := new(big.Int).Exp(r.Denom(), exponent, nil)
+						return t.vm.initRationalObject(new(big.Rat).SetFrac(num, denom))
+					case *FloatObject, *RationalObject:
+						return floatOperation(t, receiver, args, "**")
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, e.Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Returns self divided by a number.
+			//
+			// ```ruby
+			// 1/2r / 3 # => 1/6
+			// ```
+			//
+			// @return [Rational]
+			Name: "/",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalOperation(t, receiver, args, "/")
+				}
+			},
+		},
+		{
+			// Returns 1 if self is larger than the number, -1 if smaller. Otherwise 0.
+			//
+			// ```ruby
+			// 1/3r <=> 1/2r # => -1
+			// 2/2r <=> 1    # => 0
+			// ```
+			//
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					if !isNumber(args[0]) {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.RationalClass, args[0].Class().Name)
+					}
+
+					c, ok := compareNumbers(receiver, args[0])
+
+					if !ok {
+						return NULL
+					}
+
+					return t.vm.initIntegerObject(c)
+				}
+			},
+		},
+		{
+			// Returns if self is equal to a number.
+			//
+			// ```ruby
+			// 1/2r == 2/4r # => true
+			// 2/2r == 1    # => true
+			// 1/2r == 0.5  # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c, ok := compareNumbers(receiver, args[0])
+
+					if ok && c == 0 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns if self is not equal to the argument.
+			//
+			// ```ruby
+			// 1/2r != 1/3r # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "!=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					c, ok := compareNumbers(receiver, args[0])
+
+					if ok && c == 0 {
+						return FALSE
+					}
+
+					return TRUE
+				}
+			},
+		},
+		{
+			// Returns the absolute value of self.
+			//
+			// ```ruby
+			// (-1/2r).abs # => 1/2
+			// ```
+			//
+			// @return [Rational]
+			Name: "abs",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initRationalObject(new(big.Rat).Abs(receiver.(*RationalObject).value))
+				}
+			},
+		},
+		{
+			// Returns the smallest number greater than or equal to self, with the given number of decimal digits.
+			// The digits default to 0, in which case an Integer is returned.
+			//
+			// ```ruby
+			// (7/2r).ceil      # => 4
+			// (1/3r).ceil(2)   # => 17/50
+			// ```
+			//
+			// @return [Integer, Rational]
+			Name: "ceil",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalRounding(t, receiver, args, ceilRat)
+				}
+			},
+		},
+		{
+			// Returns the denominator of self, which is always positive.
+			//
+			// ```ruby
+			// (-3/6r).denominator # => 2
+			// ```
+			//
+			// @return [Integer]
+			Name: "denominator",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerFromBigInt(new(big.Int).Set(receiver.(*RationalObject).value.Denom()))
+				}
+			},
+		},
+		{
+			// Returns the largest number less than or equal to self, with the given number of decimal digits.
+			// The digits default to 0, in which case an Integer is returned.
+			//
+			// ```ruby
+			// (7/2r).floor     # => 3
+			// (2/3r).floor(2)  # => 33/50
+			// ```
+			//
+			// @return [Integer, Rational]
+			Name: "floor",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalRounding(t, receiver, args, floorRat)
+				}
+			},
+		},
+		{
+			// Returns the numerator of self.
+			//
+			// ```ruby
+			// (-3/6r).numerator # => -1
+			// ```
+			//
+			// @return [Integer]
+			Name: "numerator",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerFromBigInt(new(big.Int).Set(receiver.(*RationalObject).value.Num()))
+				}
+			},
+		},
+		{
+			// Rounds self to the given number of decimal digits, halves are rounded away from zero.
+			// The digits default to 0, in which case an Integer is returned.
+			//
+			// ```ruby
+			// (5/2r).round     # => 3
+			// (2/3r).round(2)  # => 67/100
+			// ```
+			//
+			// @return [Integer, Rational]
+			Name: "round",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return rationalRounding(t, receiver, args, roundRat)
+				}
+			},
+		},
+		{
+			// Returns self converted to the nearest Float.
+			//
+			// ```ruby
+			// (1/4r).to_f # => 0.25
+			// ```
+			//
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f, _ := receiver.(*RationalObject).value.Float64()
+					return t.vm.initFloatObject(f)
+				}
+			},
+		},
+		{
+			// Returns self truncated to an Integer.
+			//
+			// ```ruby
+			// (7/2r).to_i  # => 3
+			// (-7/2r).to_i # => -3
+			// ```
+			//
+			// @return [Integer]
+			Name: "to_i",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					r := receiver.(*RationalObject).value
+					return t.vm.initIntegerFromBigInt(new(big.Int).Quo(r.Num(), r.Denom()))
+				}
+			},
+		},
+		{
+			// Returns self.
+			//
+			// ```ruby
+			// (1/3r).to_r # => 1/3
+			// ```
+			//
+			// @return [Rational]
+			Name: "to_r",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns a String representation of self in the form of "numerator/denominator".
+			//
+			// ```ruby
+			// (1/3r).to_s # => "1/3"
+			// 2r.to_s     # => "2/1"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+		{
+			// Returns true if self is zero.
+			//
+			// ```ruby
+			// 0r.zero? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "zero?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if receiver.(*RationalObject).value.Sign() == 0 {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initRationalObject(value *big.Rat) *RationalObject {
+	return &RationalObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.RationalClass), frozen: true},
+		value:   value,
+	}
+}
+
+func (vm *VM) initRationalClass() *RClass {
+	rc := vm.initializeClass(classes.RationalClass, false)
+	rc.setBuiltinMethods(builtinRationalInstanceMethods(), false)
+	rc.setBuiltinMethods(builtinRationalClassMethods(), true)
+	rc.include(vm.topLevelClass(classes.ComparableModule))
+	return rc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (r *RationalObject) Value() interface{} {
+	return r.value
+}
+
+// Returns the rational as "numerator/denominator"
+func (r *RationalObject) toString() string {
+	return r.value.String()
+}
+
+// Returns the rational as a JSON string
+func (r *RationalObject) toJSON() string {
+	return strconv.Quote(r.toString())
+}
+
+// Other helper functions -----------------------------------------------
+
+// toBigRat returns the exact value of an Integer, a BigInt, a Rational or a finite Float as a big.Rat,
+// ok is false for other objects
+func toBigRat(obj Object) (*big.Rat, bool) {
+	switch obj := obj.(type) {
+	case *IntegerObject:
+		return new(big.Rat).SetInt64(int64(obj.value)), true
+	case *BigIntObject:
+		return new(big.Rat).SetInt(obj.value), true
+	case *RationalObject:
+		return obj.value, true
+	case *FloatObject:
+		r := new(big.Rat).SetFloat64(obj.value)
+		return r, r != nil
+	default:
+		return nil, false
+	}
+}
+
+// convertToRational converts a number or a String like "1/3" and "0.75" to a big.Rat, see Kernel#Rational
+func convertToRational(t *thread, obj Object) (*big.Rat, *Error) {
+	switch obj := obj.(type) {
+	case *StringObject:
+		r, ok := new(big.Rat).SetString(strings.Replace(strings.TrimSpace(obj.value), "_", "", -1))
+
+		if !ok {
+			return nil, t.initErrorObject(errors.ArgumentError, "Invalid value for Rational(): %q", obj.value)
+		}
+
+		return r, nil
+	case *FloatObject:
+		r, ok := toBigRat(obj)
+
+		if !ok {
+			return nil, t.initErrorObject(errors.FloatDomainError, formatFloat(obj.value))
+		}
+
+		return r, nil
+	default:
+		r, ok := toBigRat(obj)
+
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.RationalClass, obj.Class().Name)
+		}
+
+		return r, nil
+	}
+}
+
+// rationalOperation applies the arithmetic operator to the receiver and the argument as big.Rats.
+// Float arguments are handed to floatOperation instead.
+func rationalOperation(t *thread, receiver Object, args []Object, operator string) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	if _, ok := args[0].(*FloatObject); ok {
+		return floatOperation(t, receiver, args, operator)
+	}
+
+	left, _ := toBigRat(receiver)
+	right, ok := toBigRat(args[0])
+
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.RationalClass, args[0].Class().Name)
+	}
+
+	result := new(big.Rat)
+
+	switch operator {
+	case "+":
+		result.Add(left, right)
+	case "-":
+		result.Sub(left, right)
+	case "*":
+		result.Mul(left, right)
+	case "/", "%":
+		if right.Sign() == 0 {
+			return t.initErrorObject(errors.ZeroDivisionError, "Divided by 0")
+		}
+
+		result.Quo(left, right)
+
+		if operator == "%" {
+			q := new(big.Rat).SetInt(floorRat(result))
+			result.Sub(left, q.Mul(q, right))
+		}
+	}
+
+	return t.vm.initRationalObject(result)
+}
+
+// rationalRounding rounds the receiver with round to the number of decimal digits given by the optional
+// argument. The result is an Integer unless the digits are positive.
+func rationalRounding(t *thread, receiver Object, args []Object, round func(*big.Rat) *big.Int) Object {
+	digits := 0
+
+	switch len(args) {
+	case 0:
+	case 1:
+		d, ok := args[0].(*IntegerObject)
+
+		if !ok {
+			return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		digits = d.value
+	default:
+		return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+
+	r := receiver.(*RationalObject).value
+
+	if digits == 0 {
+		return t.vm.initIntegerFromBigInt(round(r))
+	}
+
+	scale := new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(abs(digits))), nil))
+
+	if digits > 0 {
+		n := new(big.Rat).SetInt(round(new(big.Rat).Mul(r, scale)))
+		return t.vm.initRationalObject(n.Quo(n, scale))
+	}
+
+	n := new(big.Rat).SetInt(round(new(big.Rat).Quo(r, scale)))
+	return t.vm.initIntegerFromBigInt(new(big.Int).Mul(n.Num(), scale.Num()))
+}
+
+// floorRat returns the largest integer less than or equal to r
+func floorRat(r *big.Rat) *big.Int {
+	// The denominator is always positive, so Euclidean division rounds toward negative infinity
+	q, m := new(big.Int), new(big.Int)
+	q.DivMod(r.Num(), r.Denom(), m)
+	return q
+}
+
+// ceilRat returns the smallest integer greater than or equal to r
+func ceilRat(r *big.Rat) *big.Int {
+	n := floorRat(new(big.Rat).Neg(r))
+	return n.Neg(n)
+}
+
+// roundRat returns the integer nearest to r, halves are rounded away from zero
+func roundRat(r *big.Rat) *big.Int {
+	half := big.NewRat(1, 2)
+
+	if r.Sign() < 0 {
+		return ceilRat(new(big.Rat).Sub(r, half))
+	}
+
+	return floorRat(new(big.Rat).Add(r, half))
+}
+
+var leadingRationalRegexp = regexp.MustCompile(`^[+-]?\d+(_\d+)*(\.\d+(_\d+)*)?([eE][+-]?\d+)?(/\d+(_\d+)*)?`)
+
+// parseLeadingRational converts the leading rational number of str, like "1/3", "0.75" or "1e-3",
+// it returns 0 if str doesn't start with a number. See String#to_r
+func parseLeadingRational(str string) *big.Rat {
+	s := leadingRationalRegexp.FindString(strings.TrimLeftFunc(str, isStripSpace))
+	r, ok := new(big.Rat).SetString(strings.Replace(s, "_", "", -1))
+
+	if !ok {
+		return new(big.Rat)
+	}
+
+	return r
+}
+
+// abs returns the absolute value of an int
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
@@ -0,0 +1,187 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestRationalCreation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`3r.to_s`, "3/1"},
+		{`0.75r.to_s`, "3/4"},
+		{`(1/3r).to_s`, "1/3"},
+		{`(1/3r).class.name`, "Rational"},
+		{`Rational(1, 3).to_s`, "1/3"},
+		{`Rational(4, 6).to_s`, "2/3"},
+		{`Rational(3).to_s`, "3/1"},
+		{`Rational(1, -2).to_s`, "-1/2"},
+		{`Rational("0.75").to_s`, "3/4"},
+		{`Rational("1/3").to_s`, "1/3"},
+		{`Rational(0.5).to_s`, "1/2"},
+		{`Rational(1/2r, 2).to_s`, "1/4"},
+		{`Rational(18446744073709551616, 2).to_s`, "9223372036854775808/1"},
+		{`"1/3".to_r.to_s`, "1/3"},
+		{`"0.75".to_r.to_s`, "3/4"},
+		{`"1e-3".to_r.to_s`, "1/1000"},
+		{`" 2 apples".to_r.to_s`, "2/1"},
+		{`"1_000.5".to_r.to_s`, "2001/2"},
+		{`"some text".to_r.to_s`, "0/1"},
+		{`3.to_r.to_s`, "3/1"},
+		{`0.75.to_r.to_s`, "3/4"},
+		{`18446744073709551616.to_r.to_s`, "18446744073709551616/1"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRationalCreationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Rational()`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`Rational(1, 2, 3)`, "ArgumentError: Expect 1..2 arguments. got=3", 1},
+		{`Rational(1, 0)`, "ZeroDivisionError: Divided by 0", 1},
+		{`Rational("abc")`, `ArgumentError: Invalid value for Rational(): "abc"`, 1},
+		{`Rational(nil)`, "TypeError: Expect argument to be Rational. got: Null", 1},
+		{`Rational(Float::NAN)`, "FloatDomainError: NaN", 1},
+		{`Float::INFINITY.to_r`, "FloatDomainError: Infinity", 1},
+		{`Rational.new`, "UnsupportedMethodError: Unsupported Method #new for Rational", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRationalArithmeticOperation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(1/3r + 1/6r).to_s`, "1/2"},
+		{`(1/2r + 1).to_s`, "3/2"},
+		{`(1 + 1/2r).to_s`, "3/2"},
+		{`(1/2r - 1/3r).to_s`, "1/6"},
+		{`(1 - 1/3r).to_s`, "2/3"},
+		{`(2/3r * 3/4r).to_s`, "1/2"},
+		{`(3 * (1/3r)).to_s`, "1/1"},
+		{`(1/2r / 3).to_s`, "1/6"},
+		{`(7/2r % 1).to_s`, "1/2"},
+		{`((-7/2r) % 1).to_s`, "1/2"},
+		{`(7 % (2/1r)).to_s`, "1/1"},
+		{`((2/3r) ** 2).to_s`, "4/9"},
+		{`((2/3r) ** -1).to_s`, "3/2"},
+		{`((2/3r) ** 0).to_s`, "1/1"},
+		{`(18446744073709551616 + 1/2r).to_s`, "36893488147419103233/2"},
+		{`1/2r + 0.5`, 1.0},
+		{`0.5 + 1/2r`, 1.0},
+		{`4 ** (1/2r)`, 2.0},
+		{`(0.1r + 0.2r).to_s`, "3/10"},
+		{`((-1/2r).abs).to_s`, "1/2"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRationalArithmeticOperationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`1/2r + "1"`, "TypeError: Expect argument to be Rational. got: String", 1},
+		{`1/2r / 0`, "ZeroDivisionError: Divided by 0", 1},
+		{`1 / 0r`, "ZeroDivisionError: Divided by 0", 1},
+		{`0r ** -1`, "ZeroDivisionError: Divided by 0", 1},
+		{`1/2r ** "2"`, "TypeError: Expect argument to be Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRationalComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`0.1r + 0.2r == 0.3r`, true},
+		{`1/2r == 2/4r`, true},
+		{`2/2r == 1`, true},
+		{`1 == 2/2r`, true},
+		{`1/2r == 0.5`, true},
+		{`0.5 == 1/2r`, true},
+		{`1/2r == "1/2"`, false},
+		{`1/2r != 1/3r`, true},
+		{`1/3r <=> 1/2r`, -1},
+		{`2/2r <=> 1`, 0},
+		{`1/2r <=> 0.25`, 1},
+		{`1/3r < 1/2r`, true},
+		{`1/3r >= 1/2r`, false},
+		{`1 < 3/2r`, true},
+		{`2 <= 3/2r`, false},
+		{`0.25 < 1/2r`, true},
+		{`[1/2r, 1/3r, 1].sort.to_s`, "[1/3, 1/2, 1]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRationalConversion(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(-3/6r).numerator`, -1},
+		{`(-3/6r).denominator`, 2},
+		{`(1/4r).to_f`, 0.25},
+		{`(7/2r).to_i`, 3},
+		{`(-7/2r).to_i`, -3},
+		{`(7/2r).ceil`, 4},
+		{`(-7/2r).ceil`, -3},
+		{`(7/2r).floor`, 3},
+		{`(-7/2r).floor`, -4},
+		{`(5/2r).round`, 3},
+		{`(-5/2r).round`, -3},
+		{`(4/3r).round`, 1},
+		{`(1/3r).ceil(2).to_s`, "17/50"},
+		{`(2/3r).floor(2).to_s`, "33/50"},
+		{`(2/3r).round(2).to_s`, "67/100"},
+		{`(12345/2r).round(-2)`, 6200},
+		{`(1/3r).to_r.to_s`, "1/3"},
+		{`0r.zero?`, true},
+		{`(1/3r).zero?`, false},
+		{`{ a: 1/3r }.to_json`, `{"a":"1/3"}`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
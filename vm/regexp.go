@@ -0,0 +1,339 @@
+package vm
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// RegexpObject represents regular expression instances
+// A Regexp holds a regular expression, used to match a pattern against strings.
+// Regexps are created using `/pattern/flags` literals or the `Regexp.new` constructor.
+// Patterns are backed by Golang's regexp package, so they follow its RE2 syntax.
+//
+// ```ruby
+// r = /(?<year>\d+)-(?<month>\d+)/
+// m = "2017-09".match(r)
+// m["year"] # => "2017"
+// ```
+//
+// Supported flags are:
+//
+// - `i`: case insensitive
+// - `m`: make `.` match newline as well
+type RegexpObject struct {
+	*baseObj
+	regexp *regexp.Regexp
+	source string
+	flags  string
+}
+
+// Class methods --------------------------------------------------------
+func builtinRegexpClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new Regexp object with given pattern string and optional flags string.
+			//
+			// ```ruby
+			// Regexp.new("Go+by")       # => /Go+by/
+			// Regexp.new("goby", "i") # => /goby/i
+			// ```
+			//
+			// @return [Regexp]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 && len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+					}
+
+					p := args[0]
+					pattern, ok := p.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, p.Class().Name)
+					}
+
+					var flags string
+					if len(args) == 2 {
+						f := args[1]
+						flagsStr, ok := f.(*StringObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, f.Class().Name)
+						}
+
+						flags = flagsStr.value
+					}
+
+					re, err := t.vm.initRegexpObject(pattern.value, flags)
+
+					if err != nil {
+						return t.initErrorObject(errors.ArgumentError, "%s", err.Error())
+					}
+
+					return re
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinRegexpInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the two regexps have the same source and flags
+			//
+			// ```ruby
+			// /goby/ == /goby/  # => true
+			// /goby/ == /goby/i # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					re := receiver.(*RegexpObject)
+					right, ok := args[0].(*RegexpObject)
+
+					if ok && re.source == right.source && re.flags == right.flags {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns the character index of the first match in given string, or nil if there's no match
+			//
+			// ```ruby
+			// /by/ =~ "Goby"  # => 2
+			// /foo/ =~ "Goby" # => nil
+			// ```
+			//
+			// @return [Integer]
+			Name: "=~",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					s := args[0]
+					str, ok := s.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, s.Class().Name)
+					}
+
+					return receiver.(*RegexpObject).matchIndex(t, str.value)
+				}
+			},
+		},
+		{
+			// Returns a MatchData object that describes the first match in given string, or nil if there's no match
+			//
+			// ```ruby
+			// m = /(\w+)@(\w+)/.match("mail me: goby@example")
+			// m[0] # => "goby@example"
+			// m[1] # => "goby"
+			// /foo/.match("Goby") # => nil
+			// ```
+			//
+			// @return [MatchData]
+			Name: "match",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					s := args[0]
+					str, ok := s.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, s.Class().Name)
+					}
+
+					return receiver.(*RegexpObject).match(t, str.value)
+				}
+			},
+		},
+		{
+			// Returns true if the regexp matches given string
+			//
+			// ```ruby
+			// /G.by/.match?("Goby") # => true
+			// /G.by/.match?("Ruby") # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "match?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					s := args[0]
+					str, ok := s.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, s.Class().Name)
+					}
+
+					if receiver.(*RegexpObject).regexp.MatchString(str.value) {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns true if the given object is a string that matches the regexp, so a regexp can be used as a
+			// pattern. Unlike `match?`, it returns false for other objects.
+			//
+			// ```ruby
+			// /G.by/ === "Goby" # => true
+			// /G.by/ === 1      # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "===",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					str, ok := args[0].(*StringObject)
+
+					if !ok {
+						return FALSE
+					}
+
+					return toBooleanObject(receiver.(*RegexpObject).regexp.MatchString(str.value))
+				}
+			},
+		},
+		{
+			// Returns the pattern string of the regexp
+			//
+			// ```ruby
+			// /Go+by/i.source # => "Go+by"
+			// ```
+			//
+			// @return [String]
+			Name: "source",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*RegexpObject).source)
+				}
+			},
+		},
+		{
+			// Returns the literal form of the regexp
+			//
+			// ```ruby
+			// /Go+by/i.to_s # => "/Go+by/i"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initRegexpObject(pattern, flags string) (*RegexpObject, error) {
+	prefix := ""
+
+	for _, f := range flags {
+		switch f {
+		case 'i':
+			prefix += "i"
+		case 'm':
+			prefix += "s"
+		default:
+			return nil, fmt.Errorf("Unknown regexp flag: %c", f)
+		}
+	}
+
+	expr := pattern
+	if prefix != "" {
+		expr = "(?" + prefix + ")" + pattern
+	}
+
+	re, err := regexp.Compile(expr)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid regexp /%s/: %s", pattern, err.Error())
+	}
+
+	return &RegexpObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.RegexpClass)},
+		regexp:  re,
+		source:  pattern,
+		flags:   flags,
+	}, nil
+}
+
+func (vm *VM) initRegexpClass() *RClass {
+	rc := vm.initializeClass(classes.RegexpClass, false)
+	rc.setBuiltinMethods(builtinRegexpInstanceMethods(), false)
+	rc.setBuiltinMethods(builtinRegexpClassMethods(), true)
+	return rc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the regexp's literal form
+func (re *RegexpObject) toString() string {
+	return "/" + re.source + "/" + re.flags
+}
+
+// Returns the regexp's literal form as a JSON string
+func (re *RegexpObject) toJSON() string {
+	return strconv.Quote(re.toString())
+}
+
+// matchIndex returns the character index of the first match or nil
+func (re *RegexpObject) matchIndex(t *thread, str string) Object {
+	loc := re.regexp.FindStringIndex(str)
+
+	if loc == nil {
+		return NULL
+	}
+
+	return t.vm.initIntegerObject(utf8.RuneCountInString(str[:loc[0]]))
+}
+
+// match returns a MatchData of the first match or nil
+func (re *RegexpObject) match(t *thread, str string) Object {
+	loc := re.regexp.FindStringSubmatchIndex(str)
+
+	if loc == nil {
+		return NULL
+	}
+
+	return t.vm.initMatchDataObject(re, str, loc)
+}
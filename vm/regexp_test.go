@@ -0,0 +1,188 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestRegexpClassSuperclass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`Regexp.class.name`, "Class"},
+		{`Regexp.superclass.name`, "Object"},
+		{`/goby/.class.name`, "Regexp"},
+		{`"goby".match(/o/).class.name`, "MatchData"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpNewMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Regexp.new("Go+by").to_s`, "/Go+by/"},
+		{`Regexp.new("goby", "i").to_s`, "/goby/i"},
+		{`Regexp.new("goby", "i").match?("GOBY")`, true},
+		{`Regexp.new("g.by", "m").match?("g\nby")`, true},
+		{`Regexp.new("g.by").match?("g\nby")`, false},
+		{`Regexp.new("a/b") == /a\/b/`, true},
+		{`/G.by/ === "Goby"`, true},
+		{`/G.by/ === 1`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpNewMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Regexp.new`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`Regexp.new(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Regexp.new("a", 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Regexp.new("a", "x")`, "ArgumentError: Unknown regexp flag: x", 1},
+		{`Regexp.new("(a")`, "ArgumentError: Invalid regexp /(a/: error parsing regexp: missing closing ): `(a`", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpLiteral(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`/goby/.source`, "goby"},
+		{`/Go+by\d/im.source`, `Go+by\d`},
+		{`/Go+by\d/im.to_s`, `/Go+by\d/im`},
+		{`/a\/b/.source`, "a/b"},
+		{`/goby/ == /goby/`, true},
+		{`/goby/ == /goby/i`, false},
+		{`/goby/ == "goby"`, false},
+		{`
+		def foo(r)
+		  r.source
+		end
+		foo(/goby/)
+		`, "goby"},
+		{`[/a/, /b/][1].source`, "b"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpMatchOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`/by/ =~ "Goby"`, 2},
+		{`/foo/ =~ "Goby"`, nil},
+		{`/GOBY/i =~ "I love Goby"`, 7},
+		{`/Go/ =~ "你好Goby"`, 2},
+		{`/go/.match?("goby")`, true},
+		{`/go/.match?("ruby")`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestRegexpMatchOperatorFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`/a/ =~ 1`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`/a/.match(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`/a/.match?(nil)`, "TypeError: Expect argument to be String. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMatchDataMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`/(\w+) (\w+)/.match("Goby Lang")[0]`, "Goby Lang"},
+		{`/(\w+) (\w+)/.match("Goby Lang")[2]`, "Lang"},
+		{`/(\w+) (\w+)/.match("Goby Lang")[-1]`, "Lang"},
+		{`/(\w+) (\w+)/.match("Goby Lang")[3]`, nil},
+		{`/(\w+)(z)?/.match("Goby")[2]`, nil},
+		{`/(?<name>\w+) (\w+)/.match("Goby Lang")["name"]`, "Goby"},
+		{`/(?<name>\w+) (\w+)/.match("Goby Lang")[:name]`, "Goby"},
+		{`/(\w+) (\w+)/.match("Goby Lang").captures.join(",")`, "Goby,Lang"},
+		{`/(\w+) (\w+)/.match("Goby Lang").to_a.join(",")`, "Goby Lang,Goby,Lang"},
+		{`/(\w+) (\w+)/.match("Goby Lang").length`, 3},
+		{`/(\w+) (\w+)/.match("Goby Lang").size`, 3},
+		{`/Goby/.match("Hello Goby Lang").pre_match`, "Hello "},
+		{`/Goby/.match("Hello Goby Lang").post_match`, " Lang"},
+		{`/G\w+/.match("Hello Goby Lang").to_s`, "Goby"},
+		{`
+		h = /(?<year>\d+)-(?<month>\d+)/.match("2017-09").named_captures
+		h["year"] + "/" + h["month"]
+		`, "2017/09"},
+		{`/(?<year>\d+)-(\d+)/.match("2017-09").named_captures.length`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestMatchDataMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`/(\w+)/.match("Goby")["foo"]`, "ArgumentError: Undefined group name: foo", 1},
+		{`/(\w+)/.match("Goby")[:foo]`, "ArgumentError: Undefined group name: foo", 1},
+		{`/(\w+)/.match("Goby")[nil]`, "TypeError: Expect index to be Integer, String or Symbol. got: Null", 1},
+		{`/(\w+)/.match("Goby")[]`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`MatchData.new`, "UnsupportedMethodError: Unsupported Method #new for MatchData", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -57,7 +57,11 @@ func (vm *VM) GetREPLResult() string {
 	top := vm.mainThread.stack.pop()
 
 	if top != nil {
-		return top.Target.toString()
+		s, err := vm.mainThread.objectToString(top.Target)
+		if err != nil {
+			return err.toString()
+		}
+		return s
 	}
 
 	return ""
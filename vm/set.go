@@ -0,0 +1,1033 @@
+package vm
+
+import (
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// SetObject is a collection of unique objects, which are compared with `hash` and `eql?` like the keys of a Hash.
+// The elements are iterated in the order they were added. Use `require "set"` to load it.
+//
+// ```ruby
+// require "set"
+//
+// s = Set.new([1, 2, 3])
+// s.add(3)             # => #<Set: {1, 2, 3}>
+// s.include?(2)        # => true
+// s | Set[3, 4]        # => #<Set: {1, 2, 3, 4}>
+// s & [2, 3, 4]        # => #<Set: {2, 3}>
+// Set[1, 2].subset?(s) # => true
+// [1, 1, 2].to_set     # => #<Set: {1, 2}>
+// ```
+type SetObject struct {
+	*baseObj
+	// elements holds the elements by their hash values, elements with the same hash value are compared with eql?
+	elements map[int][]Object
+	// order holds the elements in the order they were added
+	order []Object
+}
+
+// Class methods --------------------------------------------------------
+func builtinSetClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a set of the given objects.
+			//
+			// ```ruby
+			// Set[1, 2, 2] # => #<Set: {1, 2}>
+			// ```
+			//
+			// @param *objects [Object]
+			// @return [Set]
+			Name: "[]",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					s := t.vm.initSetObject()
+
+					for _, arg := range args {
+						if _, err := s.add(t, arg); err != nil {
+							return err
+						}
+					}
+
+					return s
+				}
+			},
+		},
+		{
+			// Returns a set of the elements of the given Array, Set or other object that responds to `to_a`.
+			// If a block is given, the set holds the block's results of the elements instead.
+			//
+			// ```ruby
+			// Set.new                        # => #<Set: {}>
+			// Set.new([1, 2, 1])             # => #<Set: {1, 2}>
+			// Set.new(1..3) do |i| i % 2 end # => #<Set: {1, 0}>
+			// ```
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					s := t.vm.initSetObject()
+
+					if len(args) == 0 || args[0] == NULL {
+						return s
+					}
+
+					elements, err := t.setElements(args[0])
+					if err != nil {
+						return err
+					}
+
+					for _, el := range elements {
+						if blockFrame != nil {
+							el = t.builtinMethodYield(blockFrame, el).Target
+
+							if err, ok := el.(*Error); ok && !err.rescued {
+								return err
+							}
+						}
+
+						if _, err := s.add(t, el); err != nil {
+							return err
+						}
+					}
+
+					return s
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinSetInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new set of the elements that are in both the set and the given collection.
+			//
+			// ```ruby
+			// Set[1, 2, 3] & [2, 3, 4] # => #<Set: {2, 3}>
+			// ```
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "&",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).intersection)
+				}
+			},
+		},
+		{
+			// Same as `|`.
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "+",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).union)
+				}
+			},
+		},
+		{
+			// Returns a new set of the elements that are in the set but not in the given collection.
+			//
+			// ```ruby
+			// Set[1, 2, 3] - [2] # => #<Set: {1, 3}>
+			// ```
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "-",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).difference)
+				}
+			},
+		},
+		{
+			// Same as `proper_subset?`.
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return s.isSubset(t, other, true)
+					})
+				}
+			},
+		},
+		{
+			// Adds the object to the set and returns the set.
+			//
+			// ```ruby
+			// s = Set.new
+			// s << 1 << 2 << 1 # => #<Set: {1, 2}>
+			// ```
+			//
+			// @param object [Object]
+			// @return [Set]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("add", receiver, args...)
+				}
+			},
+		},
+		{
+			// Same as `subset?`.
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "<=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return s.isSubset(t, other, false)
+					})
+				}
+			},
+		},
+		{
+			// Returns true if the sets have the same elements, regardless of their order.
+			//
+			// ```ruby
+			// Set[1, 2] == Set[2, 1] # => true
+			// Set[1, 2] == [1, 2]    # => false
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					other, ok := args[0].(*SetObject)
+					if !ok {
+						return FALSE
+					}
+
+					s := receiver.(*SetObject)
+					if len(s.order) != len(other.order) {
+						return FALSE
+					}
+
+					subset, err := s.isSubset(t, other, false)
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(subset)
+				}
+			},
+		},
+		{
+			// Same as `proper_superset?`.
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: ">",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return other.isSubset(t, s, true)
+					})
+				}
+			},
+		},
+		{
+			// Same as `superset?`.
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: ">=",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return other.isSubset(t, s, false)
+					})
+				}
+			},
+		},
+		{
+			// Returns a new set of the elements that are in either the set or the given collection, but not both.
+			//
+			// ```ruby
+			// Set[1, 2, 3] ^ [3, 4] # => #<Set: {1, 2, 4}>
+			// ```
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "^",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, func(s *SetObject, t *thread, other *SetObject) (*SetObject, *Error) {
+						left, err := s.difference(t, other)
+						if err != nil {
+							return nil, err
+						}
+
+						right, err := other.difference(t, s)
+						if err != nil {
+							return nil, err
+						}
+
+						return left.union(t, right)
+					})
+				}
+			},
+		},
+		{
+			// Returns a new set of the elements that are in the set or the given collection.
+			//
+			// ```ruby
+			// Set[1, 2] | [2, 3] # => #<Set: {1, 2, 3}>
+			// ```
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "|",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).union)
+				}
+			},
+		},
+		{
+			// Adds the object to the set and returns the set.
+			//
+			// ```ruby
+			// Set[1].add(2) # => #<Set: {1, 2}>
+			// ```
+			//
+			// @param object [Object]
+			// @return [Set]
+			Name: "add",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					if _, err := receiver.(*SetObject).add(t, args[0]); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Adds the object to the set and returns the set, or returns nil if the object is already in the set.
+			//
+			// ```ruby
+			// s = Set[1]
+			// s.add?(2) # => #<Set: {1, 2}>
+			// s.add?(2) # => nil
+			// ```
+			//
+			// @param object [Object]
+			// @return [Set]
+			Name: "add?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					added, err := receiver.(*SetObject).add(t, args[0])
+					if err != nil {
+						return err
+					}
+
+					if !added {
+						return NULL
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Removes all elements and returns the set.
+			//
+			// ```ruby
+			// Set[1, 2].clear # => #<Set: {}>
+			// ```
+			//
+			// @return [Set]
+			Name: "clear",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					s := receiver.(*SetObject)
+					s.elements = map[int][]Object{}
+					s.order = []Object{}
+
+					return s
+				}
+			},
+		},
+		{
+			// Removes the object from the set and returns the set.
+			//
+			// ```ruby
+			// Set[1, 2].delete(1) # => #<Set: {2}>
+			// ```
+			//
+			// @param object [Object]
+			// @return [Set]
+			Name: "delete",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					if _, err := receiver.(*SetObject).remove(t, args[0]); err != nil {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Same as `-`.
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "difference",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).difference)
+				}
+			},
+		},
+		{
+			// Returns true if the set and the given set have no elements in common.
+			//
+			// ```ruby
+			// Set[1, 2].disjoint?(Set[3]) # => true
+			// ```
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "disjoint?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						common, err := s.intersection(t, other)
+						if err != nil {
+							return false, err
+						}
+
+						return len(common.order) == 0, nil
+					})
+				}
+			},
+		},
+		{
+			// Yields each element to the block in the order they were added, and returns the set.
+			//
+			// ```ruby
+			// Set[1, 2].each do |i|
+			//   puts(i)
+			// end
+			// ```
+			//
+			// @return [Set]
+			Name: "each",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each", args)
+					}
+
+					s := receiver.(*SetObject)
+
+					// Iterate over a copy so the block can modify the set
+					for _, el := range append([]Object{}, s.order...) {
+						t.builtinMethodYield(blockFrame, el)
+					}
+
+					return s
+				}
+			},
+		},
+		{
+			// Returns true if the set has no elements.
+			//
+			// ```ruby
+			// Set.new.empty? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "empty?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return toBooleanObject(len(receiver.(*SetObject).order) == 0)
+				}
+			},
+		},
+		{
+			// Returns true if the object is in the set.
+			//
+			// ```ruby
+			// Set[1, 2].include?(2)   # => true
+			// Set["a"].include?(:a)   # => false
+			// ```
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "include?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					_, _, found, err := receiver.(*SetObject).find(t, args[0])
+					if err != nil {
+						return err
+					}
+
+					return toBooleanObject(found)
+				}
+			},
+		},
+		{
+			// Returns true if the set and the given set have any elements in common.
+			//
+			// ```ruby
+			// Set[1, 2].intersect?(Set[2, 3]) # => true
+			// ```
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "intersect?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						common, err := s.intersection(t, other)
+						if err != nil {
+							return false, err
+						}
+
+						return len(common.order) > 0, nil
+					})
+				}
+			},
+		},
+		{
+			// Same as `&`.
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "intersection",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).intersection)
+				}
+			},
+		},
+		{
+			// Returns the number of elements.
+			//
+			// ```ruby
+			// Set[1, 2].length # => 2
+			// ```
+			//
+			// @return [Integer]
+			Name: "length",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initIntegerObject(len(receiver.(*SetObject).order))
+				}
+			},
+		},
+		{
+			// Same as `include?`.
+			//
+			// @param object [Object]
+			// @return [Boolean]
+			Name: "member?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("include?", receiver, args...)
+				}
+			},
+		},
+		{
+			// Returns true if every element of the set is in the given set, and the given set has more elements.
+			//
+			// ```ruby
+			// Set[1].proper_subset?(Set[1, 2])    # => true
+			// Set[1, 2].proper_subset?(Set[1, 2]) # => false
+			// ```
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "proper_subset?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return s.isSubset(t, other, true)
+					})
+				}
+			},
+		},
+		{
+			// Returns true if every element of the given set is in the set, and the set has more elements.
+			//
+			// ```ruby
+			// Set[1, 2].proper_superset?(Set[1]) # => true
+			// ```
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "proper_superset?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return other.isSubset(t, s, true)
+					})
+				}
+			},
+		},
+		{
+			// Same as `length`.
+			//
+			// @return [Integer]
+			Name: "size",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("length", receiver, args...)
+				}
+			},
+		},
+		{
+			// Returns true if every element of the set is in the given set.
+			//
+			// ```ruby
+			// Set[1, 2].subset?(Set[1, 2, 3]) # => true
+			// ```
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "subset?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return s.isSubset(t, other, false)
+					})
+				}
+			},
+		},
+		{
+			// Returns true if every element of the given set is in the set.
+			//
+			// ```ruby
+			// Set[1, 2, 3].superset?(Set[1, 2]) # => true
+			// ```
+			//
+			// @param set [Set]
+			// @return [Boolean]
+			Name: "superset?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setComparison(receiver, args, func(s, other *SetObject) (bool, *Error) {
+						return other.isSubset(t, s, false)
+					})
+				}
+			},
+		},
+		{
+			// Returns an array of the elements in the order they were added.
+			//
+			// ```ruby
+			// Set[2, 1].to_a # => [2, 1]
+			// ```
+			//
+			// @return [Array]
+			Name: "to_a",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initArrayObject(append([]Object{}, receiver.(*SetObject).order...))
+				}
+			},
+		},
+		{
+			// Returns the string representation of the set.
+			//
+			// ```ruby
+			// Set[1, "a"].to_s # => "#<Set: {1, \"a\"}>"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					s, err := receiver.(*SetObject).inspectElements(t.inspectObject)
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(s)
+				}
+			},
+		},
+		{
+			// Same as `|`.
+			//
+			// @param enum [Object]
+			// @return [Set]
+			Name: "union",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.setOperation(receiver, args, (*SetObject).union)
+				}
+			},
+		},
+	}
+}
+
+// `Array#to_set` is loaded with `require "set"`.
+func builtinSetArrayInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a set of the array's elements.
+			//
+			// ```ruby
+			// require "set"
+			//
+			// [1, 2, 1].to_set # => #<Set: {1, 2}>
+			// ```
+			//
+			// @return [Set]
+			Name: "to_set",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					s := t.vm.initSetObject()
+
+					for _, el := range receiver.(*ArrayObject).Elements {
+						if _, err := s.add(t, el); err != nil {
+							return err
+						}
+					}
+
+					return s
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initSetObject() *SetObject {
+	return &SetObject{
+		baseObj:  &baseObj{class: vm.topLevelClass("Set")},
+		elements: map[int][]Object{},
+		order:    []Object{},
+	}
+}
+
+func initSetClass(vm *VM) {
+	sc := vm.initializeClass("Set", false)
+	sc.setBuiltinMethods(builtinSetClassMethods(), true)
+	sc.setBuiltinMethods(builtinSetInstanceMethods(), false)
+	sc.include(vm.topLevelClass(classes.EnumerableModule))
+	vm.objectClass.setClassConstant(sc)
+
+	vm.topLevelClass(classes.ArrayClass).setBuiltinMethods(builtinSetArrayInstanceMethods(), false)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (s *SetObject) toString() string {
+	str, _ := s.inspectElements(defaultInspect)
+	return str
+}
+
+// Returns the set as a JSON array
+func (s *SetObject) toJSON() string {
+	elements := []string{}
+
+	for _, el := range s.order {
+		elements = append(elements, el.toJSON())
+	}
+
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+// Returns the object
+func (s *SetObject) Value() interface{} {
+	return s.order
+}
+
+// Other helper functions -----------------------------------------------
+
+// inspectElements formats the set with each element formatted by inspect
+func (s *SetObject) inspectElements(inspect inspector) (string, *Error) {
+	elements := []string{}
+
+	for _, el := range s.order {
+		str, err := inspect(el)
+		if err != nil {
+			return "", err
+		}
+
+		elements = append(elements, str)
+	}
+
+	return "#<Set: {" + strings.Join(elements, ", ") + "}>", nil
+}
+
+// find returns the hash value of the object, and its index in the bucket of the hash value if it's in the set
+func (s *SetObject) find(t *thread, obj Object) (int, int, bool, *Error) {
+	hash, err := t.hashOf(obj)
+	if err != nil {
+		return 0, 0, false, err
+	}
+
+	for i, el := range s.elements[hash] {
+		eql, err := t.objectsEql(el, obj)
+		if err != nil {
+			return 0, 0, false, err
+		}
+
+		if eql {
+			return hash, i, true, nil
+		}
+	}
+
+	return hash, 0, false, nil
+}
+
+// add adds the object to the set, and returns false if it's already in the set
+func (s *SetObject) add(t *thread, obj Object) (bool, *Error) {
+	hash, _, found, err := s.find(t, obj)
+	if err != nil || found {
+		return false, err
+	}
+
+	s.elements[hash] = append(s.elements[hash], obj)
+	s.order = append(s.order, obj)
+
+	return true, nil
+}
+
+// remove removes the object from the set, and returns false if it's not in the set
+func (s *SetObject) remove(t *thread, obj Object) (bool, *Error) {
+	hash, i, found, err := s.find(t, obj)
+	if err != nil || !found {
+		return false, err
+	}
+
+	el := s.elements[hash][i]
+
+	s.elements[hash] = append(s.elements[hash][:i], s.elements[hash][i+1:]...)
+	if len(s.elements[hash]) == 0 {
+		delete(s.elements, hash)
+	}
+
+	for j, o := range s.order {
+		if o == el {
+			s.order = append(s.order[:j], s.order[j+1:]...)
+			break
+		}
+	}
+
+	return true, nil
+}
+
+// union returns a new set of the elements in either set
+func (s *SetObject) union(t *thread, other *SetObject) (*SetObject, *Error) {
+	result := t.vm.initSetObject()
+
+	for _, el := range append(append([]Object{}, s.order...), other.order...) {
+		if _, err := result.add(t, el); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// intersection returns a new set of the elements in both sets
+func (s *SetObject) intersection(t *thread, other *SetObject) (*SetObject, *Error) {
+	return s.filter(t, other, true)
+}
+
+// difference returns a new set of the elements that are not in the other set
+func (s *SetObject) difference(t *thread, other *SetObject) (*SetObject, *Error) {
+	return s.filter(t, other, false)
+}
+
+// filter returns a new set of the elements whose membership of the other set is the same as in
+func (s *SetObject) filter(t *thread, other *SetObject, in bool) (*SetObject, *Error) {
+	result := t.vm.initSetObject()
+
+	for _, el := range s.order {
+		_, _, found, err := other.find(t, el)
+		if err != nil {
+			return nil, err
+		}
+
+		if found == in {
+			if _, err := result.add(t, el); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// isSubset returns true if every element of the set is in the other set. If proper is true, the other set must
+// also have more elements.
+func (s *SetObject) isSubset(t *thread, other *SetObject, proper bool) (bool, *Error) {
+	if len(s.order) > len(other.order) || proper && len(s.order) == len(other.order) {
+		return false, nil
+	}
+
+	for _, el := range s.order {
+		_, _, found, err := other.find(t, el)
+		if err != nil || !found {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// setElements returns the elements of a Set, an Array or an object that responds to `to_a`
+func (t *thread) setElements(obj Object) ([]Object, *Error) {
+	switch o := obj.(type) {
+	case *SetObject:
+		return o.order, nil
+	case *ArrayObject:
+		return o.Elements, nil
+	}
+
+	if obj.findMethod("to_a") != nil {
+		switch result := t.sendMethod("to_a", obj).(type) {
+		case *Error:
+			return nil, result
+		case *ArrayObject:
+			return result.Elements, nil
+		}
+	}
+
+	return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Array or Set", obj.Class().Name)
+}
+
+// setOperation calls op with the receiver and a set of the only argument, and returns the resulting set
+func (t *thread) setOperation(receiver Object, args []Object, op func(*SetObject, *thread, *SetObject) (*SetObject, *Error)) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	other, ok := args[0].(*SetObject)
+
+	if !ok {
+		elements, err := t.setElements(args[0])
+		if err != nil {
+			return err
+		}
+
+		other = t.vm.initSetObject()
+		for _, el := range elements {
+			if _, err := other.add(t, el); err != nil {
+				return err
+			}
+		}
+	}
+
+	result, err := op(receiver.(*SetObject), t, other)
+	if err != nil {
+		return err
+	}
+
+	return result
+}
+
+// setComparison calls compare with the receiver and the only argument, which must be a Set
+func (t *thread) setComparison(receiver Object, args []Object, compare func(s, other *SetObject) (bool, *Error)) Object {
+	if len(args) != 1 {
+		return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+	}
+
+	other, ok := args[0].(*SetObject)
+	if !ok {
+		return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Set", args[0].Class().Name)
+	}
+
+	result, err := compare(receiver.(*SetObject), other)
+	if err != nil {
+		return err
+	}
+
+	return toBooleanObject(result)
+}
@@ -0,0 +1,214 @@
+package vm
+
+import "testing"
+
+func TestSetClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "set"
+		Set.new.to_s
+		`, "#<Set: {}>"},
+		{`
+		require "set"
+		Set.new([1, 2, 1]).to_s
+		`, "#<Set: {1, 2}>"},
+		{`
+		require "set"
+		Set.new([1, 1.0, 1]).size
+		`, 2},
+		{`
+		require "set"
+		Set.new(1..4) do |i|
+		  i % 2
+		end.to_s
+		`, "#<Set: {1, 0}>"},
+		{`
+		require "set"
+		Set.new(Set[1, 2]).size
+		`, 2},
+		{`
+		require "set"
+		Set[1, "a", "a", :a].to_s
+		`, `#<Set: {1, "a", :a}>`},
+		{`
+		require "set"
+		[3, 1, 3].to_set.to_a.to_s
+		`, "[3, 1]"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetInstanceMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "set"
+		s = Set.new
+		s.add(1).add(2).add(1)
+		s.to_s
+		`, "#<Set: {1, 2}>"},
+		{`
+		require "set"
+		s = Set.new
+		s << [1, 2] << [1, 2] << { a: 1 } << { a: 1 }
+		s.length
+		`, 2},
+		{`
+		require "set"
+		Set[1].add?(1)
+		`, nil},
+		{`
+		require "set"
+		Set[1].add?(2).size
+		`, 2},
+		{`
+		require "set"
+		Set[1, 2, 3].delete(2).delete(4).to_s
+		`, "#<Set: {1, 3}>"},
+		{`
+		require "set"
+		Set[1, 2].clear.empty?
+		`, true},
+		{`
+		require "set"
+		Set[1, [2]].include?([2])
+		`, true},
+		{`
+		require "set"
+		Set["a"].member?(:a)
+		`, false},
+		{`
+		require "set"
+		sum = 0
+		Set[1, 2, 3].each do |i|
+		  sum += i
+		end
+		sum
+		`, 6},
+		{`
+		require "set"
+		Set[1, 2, 3].map do |i|
+		  i * 2
+		end.to_s
+		`, "[2, 4, 6]"},
+		{`
+		require "set"
+		(Set[1, 2] | [2, 3]).to_s
+		`, "#<Set: {1, 2, 3}>"},
+		{`
+		require "set"
+		Set[1, 2].union(Set[3]) == Set[1, 2] + Set[3]
+		`, true},
+		{`
+		require "set"
+		(Set[1, 2, 3] & Set[2, 3, 4]).to_s
+		`, "#<Set: {2, 3}>"},
+		{`
+		require "set"
+		Set[1, 2, 3].intersection(2..5).to_s
+		`, "#<Set: {2, 3}>"},
+		{`
+		require "set"
+		(Set[1, 2, 3] - [2]).to_s
+		`, "#<Set: {1, 3}>"},
+		{`
+		require "set"
+		Set[1, 2].difference([1, 2]).empty?
+		`, true},
+		{`
+		require "set"
+		(Set[1, 2, 3] ^ [3, 4]).to_s
+		`, "#<Set: {1, 2, 4}>"},
+		{`
+		require "set"
+		Set[2, 1] == Set[1, 2]
+		`, true},
+		{`
+		require "set"
+		Set[1, 2] == [1, 2]
+		`, false},
+		{`
+		require "set"
+		Set[1].subset?(Set[1, 2]) && Set[1, 2] <= Set[1, 2]
+		`, true},
+		{`
+		require "set"
+		Set[1, 3].subset?(Set[1, 2])
+		`, false},
+		{`
+		require "set"
+		Set[1].proper_subset?(Set[1, 2]) && !(Set[1, 2] < Set[1, 2])
+		`, true},
+		{`
+		require "set"
+		Set[1, 2].superset?(Set[1]) && Set[1] >= Set[1]
+		`, true},
+		{`
+		require "set"
+		Set[1, 2].proper_superset?(Set[1]) && !(Set[1] > Set[1])
+		`, true},
+		{`
+		require "set"
+		Set[1, 2].disjoint?(Set[3]) && Set[1, 2].intersect?(Set[2])
+		`, true},
+		{`
+		require "set"
+		Set[Set[1, 2]].include?(Set[2, 1])
+		`, true},
+		{`
+		require "set"
+		h = {}
+		h[Set[1, 2]] = "x"
+		h[Set[2, 1]]
+		`, "x"},
+		{`
+		require "set"
+		[Set["a"]].to_s
+		`, `[#<Set: {"a"}>]`},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSetMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "set"
+		Set.new(1)`, "TypeError: Expect argument to be Array or Set. got: Integer", 2},
+		{`require "set"
+		Set.new([1], [2])`, "ArgumentError: Expect 0..1 argument. got: 2", 2},
+		{`require "set"
+		Set[1].add`, "ArgumentError: Expect 1 argument. got: 0", 2},
+		{`require "set"
+		Set[1].freeze.add(2)`, "FrozenError: Can't modify frozen Set: #<Set: {1}>", 2},
+		{`require "set"
+		Set[1] | 1`, "TypeError: Expect argument to be Array or Set. got: Integer", 2},
+		{`require "set"
+		Set[1].subset?([1])`, "TypeError: Expect argument to be Set. got: Array", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
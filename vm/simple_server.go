@@ -1,18 +1,24 @@
 package vm
 
 import (
+	"bytes"
+	"crypto/tls"
 	"io"
 	"io/ioutil"
 	"log"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"unicode"
 
 	"github.com/fatih/structs"
 	"github.com/goby-lang/goby/vm/classes"
-	"github.com/gorilla/mux"
+	"github.com/goby-lang/goby/vm/errors"
 )
 
 type request struct {
@@ -22,7 +28,6 @@ type request struct {
 	Path             string
 	Host             string
 	Protocol         string
-	Headers          map[string][]string
 	ContentLength    int64
 	TransferEncoding []string
 }
@@ -35,17 +40,216 @@ type response struct {
 
 // Instance methods -----------------------------------------------------
 func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
-	router := mux.NewRouter()
-
 	return []*BuiltinMethodObject{
 		{
+			// Registers a block that runs after the routes whose paths match the given path, or after all
+			// requests if the path is omitted. The block gets the request and the response.
+			//
+			// ```ruby
+			// server.after do |req, res|
+			//   puts(req.method + " " + req.path + " " + res.status.to_s)
+			// end
+			// ```
+			//
+			// @param path [String]
+			// @return [Net::SimpleServer]
+			Name: "after",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					hook, err := t.serverHook(args, blockFrame)
+					if err != nil {
+						return err
+					}
+
+					router := t.simpleServerRouter(receiver)
+					router.after = append(router.after, hook)
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Registers a block that runs before the routes whose paths match the given path, or before all
+			// requests if the path is omitted. The block gets the request and the response, and it can call
+			// `res.halt` to skip the route.
+			//
+			// ```ruby
+			// server.before("/admin/*") do |req, res|
+			//   if req.get_header("Authorization").nil?
+			//     res.halt(401, "Unauthorized")
+			//   end
+			// end
+			// ```
+			//
+			// @param path [String]
+			// @return [Net::SimpleServer]
+			Name: "before",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					hook, err := t.serverHook(args, blockFrame)
+					if err != nil {
+						return err
+					}
+
+					router := t.simpleServerRouter(receiver)
+					router.before = append(router.before, hook)
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Runs the before hooks, the route that matches the request and the after hooks, and returns the
+			// response. It's called for every request, so the server is the innermost app of the middlewares
+			// added with `use`, which can call it through the next app.
+			//
+			// ```ruby
+			// class Auth
+			//   def initialize(app, token)
+			//     @app = app
+			//     @token = token
+			//   end
+			//
+			//   def call(req)
+			//     if req.get_header("Authorization") != @token
+			//       res = Net::HTTP::Response.new
+			//       res.status = 401
+			//       res.body = "Unauthorized"
+			//       return res
+			//     end
+			//
+			//     @app.call(req)
+			//   end
+			// end
+			//
+			// server.use(Auth, "secret")
+			// ```
+			//
+			// @param req [Net::HTTP::Request]
+			// @return [Net::HTTP::Response]
+			Name: "call",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					req, ok := args[0].(*RObject)
+					if !ok || req.Class() != httpRequestClass {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Net::HTTP::Request", args[0].Class().Name)
+					}
+
+					return t.simpleServerRouter(receiver).call(t, req)
+				}
+			},
+		},
+		{
+			// Registers the block of the given path and method. The path can have named parameters like
+			// "/users/:id" and wildcards like "/files/*", which are available in `req.params`.
+			// It's used by the methods like `get` and `post`.
+			//
+			// ```ruby
+			// server.mount("/users/:id", "GET") do |req, res|
+			//   res.body = req.params["id"]
+			// end
+			// ```
+			//
+			// @param path [String], method [String]
+			// @return [Net::SimpleServer]
 			Name: "mount",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					path := args[0].(*StringObject).value
-					method := args[1].(*StringObject).value
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					path, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					method, ok := args[1].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					router := t.simpleServerRouter(receiver)
+					router.routes = append(router.routes, newServerRoute(strings.ToUpper(method.value), path.value, blockFrame))
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Registers a block that runs when no routes match the request's path. The response's status is 404
+			// and its body is "Not Found" unless the block changes them.
+			//
+			// ```ruby
+			// server.not_found do |req, res|
+			//   res.body = "There's nothing at " + req.path
+			// end
+			// ```
+			//
+			// @return [Net::SimpleServer]
+			Name: "not_found",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
 
-					router.HandleFunc(path, newHandler(t, blockFrame)).Methods(method)
+					t.simpleServerRouter(receiver).notFound = blockFrame
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Serves the files of the directory for GET and HEAD requests whose paths are under the prefix, which is
+			// "/" by default. Directories are served with their index.html. The content types are set by the files'
+			// extensions or contents, and conditional and range requests are handled with the files' ETags and
+			// modification times. Requests that don't match a file go on to the middlewares and the routes.
+			//
+			// ```ruby
+			// server.serve_static("public")
+			// server.serve_static("build/assets", "/assets")
+			// ```
+			//
+			// @param dir [String], prefix [String]
+			// @return [Net::SimpleServer]
+			Name: "serve_static",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 || len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+					}
+
+					dir, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					prefix := "/"
+
+					if len(args) == 2 {
+						p, ok := args[1].(*StringObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+						}
+
+						prefix = p.value
+					}
+
+					router := t.simpleServerRouter(receiver)
+					router.staticDirs = append(router.staticDirs, &staticDir{prefix: prefix, dir: dir.value})
 
 					return receiver
 				}
@@ -57,7 +261,9 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					prefix := args[0].(*StringObject).value
 					fileName := args[1].(*StringObject).value
-					router.PathPrefix(prefix).Handler(http.StripPrefix(prefix, http.FileServer(http.Dir(fileName))))
+
+					router := t.simpleServerRouter(receiver)
+					router.statics = append(router.statics, &staticMount{prefix: prefix, handler: http.StripPrefix(prefix, http.FileServer(http.Dir(fileName)))})
 
 					return receiver
 				}
@@ -76,10 +282,37 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 					if !ok {
 						port = "8080"
 					} else {
-						port = portVar.(*StringObject).value
+						port = portVar.toString()
 					}
 
-					log.Println("SimpleServer start listening on port: " + port)
+					certFile := stringInstanceVariable(server, "@cert_file")
+					keyFile := stringInstanceVariable(server, "@key_file")
+					useTLS := certFile != "" || keyFile != ""
+
+					if useTLS && (certFile == "" || keyFile == "") {
+						return t.initErrorObject(errors.ArgumentError, "Expect cert_file and key_file to be given together")
+					}
+
+					httpServer := &http.Server{Addr: ":" + port}
+
+					if clientCAFile := stringInstanceVariable(server, "@client_ca_file"); clientCAFile != "" {
+						if !useTLS {
+							return t.initErrorObject(errors.ArgumentError, "Expect cert_file and key_file to verify client certificates")
+						}
+
+						pool, err := loadCertPool(clientCAFile)
+						if err != nil {
+							return t.initErrorObject(errors.InternalError, err.Error())
+						}
+
+						httpServer.TLSConfig = &tls.Config{ClientCAs: pool, ClientAuth: tls.RequireAndVerifyClientCert}
+					}
+
+					if useTLS {
+						log.Println("SimpleServer start listening on port: " + port + " (TLS)")
+					} else {
+						log.Println("SimpleServer start listening on port: " + port)
+					}
 
 					c := make(chan os.Signal, 1)
 					signal.Notify(c, os.Interrupt)
@@ -93,21 +326,24 @@ func builtinSimpleServerInstanceMethods() []*BuiltinMethodObject {
 
 					fileRoot, serveStatic := server.InstanceVariables.get("@file_root")
 
-					router.NotFoundHandler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-						log.Printf("%s %s %s %d\n", r.Method, r.URL.Path, r.Proto, 404)
-					})
+					var handler http.Handler = t.simpleServerRouter(receiver)
 
 					if serveStatic && fileRoot.Class() != t.vm.objectClass.getClassConstant(classes.NullClass) {
 						fr := fileRoot.(*StringObject).value
 						currentDir, _ := os.Getwd()
 						fp := filepath.Join(currentDir, fr)
-						fs := http.FileServer(http.Dir(fp))
-						http.Handle("/", fs)
-					} else {
-						http.Handle("/", router)
+						handler = http.FileServer(http.Dir(fp))
 					}
 
-					err := http.ListenAndServe(":"+port, nil)
+					httpServer.Handler = handler
+
+					var err error
+
+					if useTLS {
+						err = httpServer.ListenAndServeTLS(certFile, keyFile)
+					} else {
+						err = httpServer.ListenAndServe()
+					}
 
 					if err != http.ErrServerClosed { // HL
 						log.Fatalf("listen: %s\n", err)
@@ -130,32 +366,65 @@ func initSimpleServerClass(vm *VM) {
 	simpleServer := vm.initializeClass("SimpleServer", false)
 	simpleServer.setBuiltinMethods(builtinSimpleServerInstanceMethods(), false)
 	net.setClassConstant(simpleServer)
+	initServerStreamClass(vm, simpleServer)
 
 	vm.execGobyLib("net/simple_server.gb")
 }
 
 // Other helper functions -----------------------------------------------
 
-func newHandler(t *thread, blockFrame *callFrame) func(http.ResponseWriter, *http.Request) {
-	return func(w http.ResponseWriter, r *http.Request) {
-		// Go creates one goroutine per request, so we also need to create a new Goby thread for every request.
-		thread := t.vm.newThread()
-		res := httpResponseClass.initializeInstance()
+// simpleServerRouter returns the router of the server, which is kept in its @router variable
+func (t *thread) simpleServerRouter(server Object) *simpleRouter {
+	if r, ok := server.instanceVariableGet("@router"); ok {
+		if g, ok := r.(*GoObject); ok {
+			if router, ok := g.data.(*simpleRouter); ok {
+				return router
+			}
+		}
+	}
+
+	router := newSimpleRouter(t.vm, server)
+	server.instanceVariableSet("@router", t.vm.initGoObject(router))
+
+	return router
+}
+
+// stringInstanceVariable returns the value of the object's String instance variable, or "" if it isn't a String
+func stringInstanceVariable(obj *RObject, name string) string {
+	if v, ok := obj.InstanceVariables.get(name); ok {
+		if s, ok := v.(*StringObject); ok {
+			return s.value
+		}
+	}
+
+	return ""
+}
 
-		req := initRequest(t, w, r)
-		result := thread.builtinMethodYield(blockFrame, req, res)
+// serverHook creates a before or after hook from the optional path argument and the block
+func (t *thread) serverHook(args []Object, blockFrame *callFrame) (*serverRoute, *Error) {
+	path := "*"
 
-		if err, ok := result.Target.(*Error); ok {
-			log.Printf("Error: %s", err.Message)
-			res.instanceVariableSet("@status", t.vm.initIntegerObject(500))
+	switch len(args) {
+	case 0:
+	case 1:
+		p, ok := args[0].(*StringObject)
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 		}
 
-		thread = nil
-		setupResponse(w, r, res)
+		path = p.value
+	default:
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+	}
+
+	if blockFrame == nil {
+		return nil, t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
 	}
+
+	return newServerRoute("", path, blockFrame), nil
 }
 
-func initRequest(t *thread, w http.ResponseWriter, req *http.Request) *RObject {
+func initRequest(t *thread, w http.ResponseWriter, req *http.Request, params map[string]string) *RObject {
 	r := request{}
 	reqObj := httpRequestClass.initializeInstance()
 
@@ -168,7 +437,6 @@ func initRequest(t *thread, w http.ResponseWriter, req *http.Request) *RObject {
 
 	r.Method = req.Method
 	r.Protocol = req.Proto
-	r.Headers = req.Header
 	r.Body = string(body)
 	r.ContentLength = req.ContentLength
 	r.TransferEncoding = req.TransferEncoding
@@ -183,28 +451,172 @@ func initRequest(t *thread, w http.ResponseWriter, req *http.Request) *RObject {
 		reqObj.instanceVariableSet(varName, t.vm.initObjectFromGoType(v))
 	}
 
+	// Headers with multiple values are joined with commas, like the responses of Net::HTTP::Client
+	headers := map[string]Object{}
+
+	for k, values := range req.Header {
+		headers[k] = t.vm.initStringObject(strings.Join(values, ", "))
+	}
+
+	reqObj.instanceVariableSet("@headers", t.vm.initHashObject(headers))
+
 	vars := map[string]Object{}
 
-	for k, v := range mux.Vars(req) {
+	for k, v := range params {
 		vars[k] = t.vm.initStringObject(v)
 	}
 
 	reqObj.instanceVariableSet("@params", t.vm.initHashObject(vars))
 
+	form, files, err := t.parseForm(req.Header.Get("Content-Type"), body)
+	if err != nil {
+		log.Printf("Error parsing form: %v", err)
+	}
+
+	reqObj.instanceVariableSet("@form", t.vm.initHashObject(form))
+	reqObj.instanceVariableSet("@files", t.vm.initHashObject(files))
+
 	return reqObj
 }
 
-func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
-	r := &response{}
+// parseForm parses the URL-encoded or multipart body into the form fields and the uploaded files, which are saved
+// into temporary files. The last value of a repeated field is used, unless its name ends with "[]", in which case
+// all the values are collected into an Array under the name without "[]".
+func (t *thread) parseForm(contentType string, body []byte) (map[string]Object, map[string]Object, error) {
+	form := map[string]Object{}
+	files := map[string]Object{}
 
-	resStatus, ok := res.instanceVariableGet("@status")
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return form, files, nil
+	}
 
-	if ok {
-		r.status = resStatus.(*IntegerObject).value
-	} else {
-		r.status = http.StatusOK
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return form, files, err
+		}
+
+		for name, vs := range values {
+			for _, v := range vs {
+				t.setFormValue(form, name, t.vm.initStringObject(v))
+			}
+		}
+	case "multipart/form-data":
+		reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+
+		for {
+			part, err := reader.NextPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return form, files, err
+			}
+
+			name := part.FormName()
+			if name == "" {
+				continue
+			}
+
+			if part.FileName() == "" {
+				value, err := ioutil.ReadAll(part)
+				if err != nil {
+					return form, files, err
+				}
+
+				t.setFormValue(form, name, t.vm.initStringObject(string(value)))
+				continue
+			}
+
+			file, err := t.saveUploadedFile(part)
+			if err != nil {
+				return form, files, err
+			}
+
+			t.setFormValue(files, name, file)
+		}
+	}
+
+	return form, files, nil
+}
+
+// setFormValue sets the value of the form field, values of the fields whose names end with "[]" are appended to an Array
+func (t *thread) setFormValue(pairs map[string]Object, name string, value Object) {
+	if !strings.HasSuffix(name, "[]") {
+		pairs[name] = value
+		return
+	}
+
+	name = strings.TrimSuffix(name, "[]")
+
+	if arr, ok := pairs[name].(*ArrayObject); ok {
+		arr.Elements = append(arr.Elements, value)
+		return
 	}
 
+	pairs[name] = t.vm.initArrayObject([]Object{value})
+}
+
+// saveUploadedFile saves the file part into a temporary file, and returns it as a `Net::HTTP::UploadedFile`
+func (t *thread) saveUploadedFile(part *multipart.Part) (Object, error) {
+	f, err := ioutil.TempFile("", "goby-upload-*")
+	if err != nil {
+		return nil, err
+	}
+
+	size, err := io.Copy(f, part)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+
+	file := httpUploadedFileClass.initializeInstance()
+	file.instanceVariableSet("@filename", t.vm.initStringObject(part.FileName()))
+	file.instanceVariableSet("@content_type", t.vm.initStringObject(part.Header.Get("Content-Type")))
+	file.instanceVariableSet("@path", t.vm.initStringObject(f.Name()))
+	file.instanceVariableSet("@size", t.vm.initIntegerObject(int(size)))
+
+	return file, nil
+}
+
+// removeUploadedFiles removes the temporary files of the request's uploaded files
+func removeUploadedFiles(reqObj *RObject) {
+	files, ok := reqObj.InstanceVariables.get("@files")
+	if !ok {
+		return
+	}
+
+	h, ok := files.(*HashObject)
+	if !ok {
+		return
+	}
+
+	for _, v := range h.Pairs {
+		uploads := []Object{v}
+		if arr, ok := v.(*ArrayObject); ok {
+			uploads = arr.Elements
+		}
+
+		for _, upload := range uploads {
+			if f, ok := upload.(*RObject); ok && f.Class() == httpUploadedFileClass {
+				os.Remove(stringInstanceVariable(f, "@path"))
+			}
+		}
+	}
+}
+
+func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
+	r := &response{}
+
+	r.status = setResponseHeaders(w, res, true)
+	r.contentType = w.Header().Get("Content-Type")
+
 	resBody, ok := res.instanceVariableGet("@body")
 
 	if !ok {
@@ -213,21 +625,34 @@ func setupResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
 		r.body = resBody.(*StringObject).value
 	}
 
+	w.WriteHeader(r.status)
+
+	io.WriteString(w, r.body)
+	log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, r.status)
+}
+
+// setResponseHeaders sets the headers of the response, and "text/plain" as the content type if it isn't set and
+// defaultContentType is true. It returns the status of the response.
+func setResponseHeaders(w http.ResponseWriter, res *RObject, defaultContentType bool) int {
+	status := http.StatusOK
+
+	if resStatus, ok := res.instanceVariableGet("@status"); ok {
+		status = resStatus.(*IntegerObject).value
+	}
+
 	h, ok := res.instanceVariableGet("@headers")
 
 	if headers, isHashObject := h.(*HashObject); ok && isHashObject {
 		for k, v := range headers.Pairs {
 			w.Header().Set(k, v.(*StringObject).value)
 		}
-	} else {
-		r.contentType = "text/plain; charset=utf-8"
-		w.Header().Set("Content-Type", r.contentType) // normal header
 	}
 
-	w.WriteHeader(r.status)
+	if defaultContentType && w.Header().Get("Content-Type") == "" {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8") // normal header
+	}
 
-	io.WriteString(w, r.body)
-	log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, r.status)
+	return status
 }
 
 func toSnakeCase(in string) string {
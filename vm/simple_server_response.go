@@ -0,0 +1,276 @@
+package vm
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// ServerStreamObject writes the body of a streamed `Net::HTTP::Response` to the client, each write is sent as a
+// chunk right away. It's given to the block of `Net::HTTP::Response#stream`.
+//
+// ```ruby
+// server.get("/events") do |req, res|
+//   res.stream do |out|
+//     3.times do |i|
+//       out.write("event ", i, "\n")
+//       sleep(1)
+//     end
+//   end
+// end
+// ```
+type ServerStreamObject struct {
+	*baseObj
+	w http.ResponseWriter
+}
+
+var serverStreamClass *RClass
+
+// Instance methods -----------------------------------------------------
+func builtinServerStreamInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Writes the string of the given object and returns self.
+			//
+			// ```ruby
+			// out << "Hello, " << "Goby"
+			// ```
+			//
+			// @param object [Object]
+			// @return [Net::SimpleServer::Stream]
+			Name: "<<",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if err, ok := receiver.(*ServerStreamObject).write(t, args).(*Error); ok {
+						return err
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Writes the strings of the given objects and returns the number of bytes written.
+			//
+			// ```ruby
+			// out.write("data: ", 1, "\n\n") # => 10
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Integer]
+			Name: "write",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver.(*ServerStreamObject).write(t, args)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods of Net::HTTP::Response ------------------------------
+func builtinServerResponseInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Sends the file as the body, so large files don't need to be read into a String. The content type is
+			// set by the file's extension or content unless the header is set, and conditional and range requests
+			// are handled with the file's ETag and modification time. The response is 404 if the file doesn't exist.
+			//
+			// ```ruby
+			// server.get("/download") do |req, res|
+			//   res.set_header("Content-Disposition", "attachment; filename=\"report.csv\"")
+			//   res.send_file("reports/latest.csv")
+			// end
+			// ```
+			//
+			// @param path [String]
+			// @return [Net::HTTP::Response]
+			Name: "send_file",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if _, ok := args[0].(*StringObject); !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					receiver.instanceVariableSet("@file", args[0])
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Streams the body with chunked encoding. The block runs after the route and the middlewares, when the
+			// status and the headers are sent, and gets a `Net::SimpleServer::Stream` to write the body with.
+			//
+			// ```ruby
+			// server.get("/numbers") do |req, res|
+			//   res.stream do |out|
+			//     1.upto(1000) do |i|
+			//       out << i << "\n"
+			//     end
+			//   end
+			// end
+			// ```
+			//
+			// @return [Net::HTTP::Response]
+			Name: "stream",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					receiver.instanceVariableSet("@stream", t.vm.initGoObject(blockFrame))
+
+					return receiver
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initServerStreamClass(vm *VM, simpleServer *RClass) {
+	stream := vm.initializeClass("Stream", false)
+	stream.setBuiltinMethods(builtinServerStreamInstanceMethods(), false)
+	simpleServer.setClassConstant(stream)
+	serverStreamClass = stream
+
+	httpResponseClass.setBuiltinMethods(builtinServerResponseInstanceMethods(), false)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (s *ServerStreamObject) toString() string {
+	return "#<" + s.Class().Name + ">"
+}
+
+// Returns the object's name as the JSON string format
+func (s *ServerStreamObject) toJSON() string {
+	return s.toString()
+}
+
+// Returns the object
+func (s *ServerStreamObject) Value() interface{} {
+	return s.w
+}
+
+// Other helper functions -----------------------------------------------
+
+// write writes the objects and flushes them to the client
+func (s *ServerStreamObject) write(t *thread, args []Object) Object {
+	result := t.write(s.w, args)
+
+	if f, ok := s.w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	return result
+}
+
+// staticDir serves the files of a directory under a path prefix, see `Net::SimpleServer#serve_static`
+type staticDir struct {
+	prefix string
+	dir    string
+}
+
+// file returns the path of the file the request's path points to, or the index.html of a directory.
+// The prefix must match whole path segments, so "/assets" doesn't match "/assetsfoo.txt".
+func (s *staticDir) file(urlPath string) (string, bool) {
+	prefix := strings.TrimSuffix(s.prefix, "/")
+	if urlPath != prefix && !strings.HasPrefix(urlPath, prefix+"/") {
+		return "", false
+	}
+
+	name := filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+strings.TrimPrefix(urlPath, prefix))))
+
+	info, err := os.Stat(name)
+	if err == nil && info.IsDir() {
+		name = filepath.Join(name, "index.html")
+		info, err = os.Stat(name)
+	}
+
+	if err != nil || info.IsDir() {
+		return "", false
+	}
+
+	return name, true
+}
+
+// writeResponse writes the response, whose body is the file given to `send_file`, the output of the block given to
+// `stream`, or the body
+func (t *thread) writeResponse(w http.ResponseWriter, req *http.Request, res *RObject) {
+	if s, ok := res.instanceVariableGet("@stream"); ok {
+		if g, ok := s.(*GoObject); ok {
+			if blockFrame, ok := g.data.(*callFrame); ok {
+				t.streamResponse(w, req, res, blockFrame)
+				return
+			}
+		}
+	}
+
+	if name := stringInstanceVariable(res, "@file"); name != "" {
+		f, err := os.Open(name)
+		if err == nil {
+			defer f.Close()
+
+			if info, err := f.Stat(); err == nil && !info.IsDir() {
+				setResponseHeaders(w, res, false)
+				serveFile(w, req, f, info)
+				return
+			}
+		}
+
+		res.instanceVariableSet("@status", t.vm.initIntegerObject(http.StatusNotFound))
+		res.instanceVariableSet("@body", t.vm.initStringObject(http.StatusText(http.StatusNotFound)))
+	}
+
+	setupResponse(w, req, res)
+}
+
+// streamResponse sends the status and the headers, and runs the block with a stream of the body
+func (t *thread) streamResponse(w http.ResponseWriter, req *http.Request, res *RObject, blockFrame *callFrame) {
+	status := setResponseHeaders(w, res, true)
+	w.WriteHeader(status)
+	log.Printf("%s %s %s %d\n", req.Method, req.URL.Path, req.Proto, status)
+
+	stream := &ServerStreamObject{baseObj: &baseObj{class: serverStreamClass}, w: w}
+	result := t.builtinMethodYield(blockFrame, stream)
+
+	if err, ok := result.Target.(*Error); ok {
+		log.Printf("Error: %s", err.Message)
+	}
+}
+
+// serveFile writes the file with its ETag, the content type is set by the file's extension or content unless it's
+// set already. Conditional and range requests are handled by http.ServeContent.
+func serveFile(w http.ResponseWriter, req *http.Request, f *os.File, info os.FileInfo) {
+	if w.Header().Get("ETag") == "" {
+		w.Header().Set("ETag", fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size()))
+	}
+
+	http.ServeContent(w, req, info.Name(), info.ModTime(), f)
+}
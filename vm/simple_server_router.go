@@ -0,0 +1,327 @@
+package vm
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// simpleRouter dispatches the requests of a `Net::SimpleServer` to the block of the matching route, and runs the
+// before and after hooks of the matching paths around it. Paths can have named parameters like "/users/:id",
+// and "*" matches the rest of the path, which is available as the "splat" parameter.
+// Requests go through the middlewares added with `use` before they reach the routes.
+type simpleRouter struct {
+	vm *VM
+	// server is the `Net::SimpleServer` that owns the router, it's the app the middlewares are stacked on
+	server Object
+	// lock is held while the middlewares are stacked, so concurrent requests share the same middleware objects
+	lock     sync.Mutex
+	routes   []*serverRoute
+	before   []*serverRoute
+	after    []*serverRoute
+	statics  []*staticMount
+	notFound *callFrame
+	// staticDirs are the directories added with `serve_static`, whose files are served before the middlewares
+	staticDirs []*staticDir
+}
+
+// serverRoute is a route, or a hook if its method is empty
+type serverRoute struct {
+	method     string
+	pattern    *regexp.Regexp
+	names      []string
+	blockFrame *callFrame
+}
+
+// staticMount serves the files under a path prefix
+type staticMount struct {
+	prefix  string
+	handler http.Handler
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func newSimpleRouter(vm *VM, server Object) *simpleRouter {
+	return &simpleRouter{vm: vm, server: server}
+}
+
+func newServerRoute(method, path string, blockFrame *callFrame) *serverRoute {
+	pattern, names := compileRoutePath(path)
+
+	return &serverRoute{method: method, pattern: pattern, names: names, blockFrame: blockFrame}
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// ServeHTTP calls the app with the request object and writes the returned response. The app is the server itself,
+// or the outermost middleware if any are added. Static files are served before the middlewares.
+// If the app raises an error or doesn't return a response, the response status becomes 500.
+func (r *simpleRouter) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	for _, s := range r.statics {
+		if strings.HasPrefix(req.URL.Path, s.prefix) {
+			s.handler.ServeHTTP(w, req)
+			return
+		}
+	}
+
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		for _, s := range r.staticDirs {
+			if name, ok := s.file(req.URL.Path); ok {
+				if f, err := os.Open(name); err == nil {
+					defer f.Close()
+
+					if info, err := f.Stat(); err == nil {
+						serveFile(w, req, f, info)
+						return
+					}
+				}
+			}
+		}
+	}
+
+	// Go creates one goroutine per request, so we also need to create a new Goby thread for every request.
+	t := r.vm.newThread()
+	reqObj := initRequest(t, w, req, map[string]string{})
+	defer removeUploadedFiles(reqObj)
+
+	app, err := r.app(t)
+	if err == nil {
+		result := t.sendMethod("call", app, reqObj)
+
+		if res, ok := result.(*RObject); ok && res.Class() == httpResponseClass {
+			t.writeResponse(w, req, res)
+			return
+		}
+
+		e, ok := result.(*Error)
+		if !ok {
+			e = t.initErrorObject(errors.TypeError, "Expect app to return Net::HTTP::Response. got: %s", result.Class().Name)
+		}
+
+		err = e
+	}
+
+	log.Printf("Error: %s", err.Message)
+
+	res := httpResponseClass.initializeInstance()
+	res.instanceVariableSet("@status", t.vm.initIntegerObject(http.StatusInternalServerError))
+	setupResponse(w, req, res)
+}
+
+// app returns the object the requests are passed to, which is the outermost middleware or the server itself
+func (r *simpleRouter) app(t *thread) (Object, *Error) {
+	builder, ok := r.server.instanceVariableGet("@builder")
+	if !ok || builder == NULL {
+		return r.server, nil
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	app := t.sendMethod("to_app", builder)
+	if err, ok := app.(*Error); ok {
+		return nil, err
+	}
+
+	return app, nil
+}
+
+// call runs the before hooks, the matching route and the after hooks with the request and a new response object,
+// and returns the response. If a before hook halts the response, the rest of the before hooks and the route are
+// skipped. Requests that don't match any routes get 404, or 405 if only the method doesn't match.
+func (r *simpleRouter) call(t *thread, reqObj *RObject) *RObject {
+	method := stringInstanceVariable(reqObj, "@method")
+	path := stringInstanceVariable(reqObj, "@path")
+
+	route, params, allowed := r.match(method, path)
+
+	for _, hook := range append(r.before, r.after...) {
+		if hookParams, ok := hook.match(path); ok {
+			for k, v := range hookParams {
+				if _, exists := params[k]; !exists {
+					params[k] = v
+				}
+			}
+		}
+	}
+
+	vars := map[string]Object{}
+
+	for k, v := range params {
+		vars[k] = t.vm.initStringObject(v)
+	}
+
+	reqObj.instanceVariableSet("@params", t.vm.initHashObject(vars))
+
+	res := httpResponseClass.initializeInstance()
+	res.instanceVariableSet("@status", t.vm.initIntegerObject(http.StatusOK))
+
+	if r.runHooks(t, r.before, path, reqObj, res, true) {
+		switch {
+		case route != nil:
+			r.yield(t, route.blockFrame, reqObj, res)
+		case len(allowed) > 0:
+			t.sendMethod("set_header", res, t.vm.initStringObject("Allow"), t.vm.initStringObject(strings.Join(allowed, ", ")))
+			res.instanceVariableSet("@status", t.vm.initIntegerObject(http.StatusMethodNotAllowed))
+			res.instanceVariableSet("@body", t.vm.initStringObject(http.StatusText(http.StatusMethodNotAllowed)))
+		default:
+			res.instanceVariableSet("@status", t.vm.initIntegerObject(http.StatusNotFound))
+			res.instanceVariableSet("@body", t.vm.initStringObject(http.StatusText(http.StatusNotFound)))
+
+			if r.notFound != nil {
+				r.yield(t, r.notFound, reqObj, res)
+			}
+		}
+	}
+
+	r.runHooks(t, r.after, path, reqObj, res, false)
+
+	return res
+}
+
+// match returns the first route that matches the method and path, with its parameters.
+// If no route matches, it returns the methods of the routes that match the path.
+// GET routes also match HEAD requests.
+func (r *simpleRouter) match(method, path string) (*serverRoute, map[string]string, []string) {
+	allowed := []string{}
+	var fallback *serverRoute
+	var fallbackParams map[string]string
+
+	for _, route := range r.routes {
+		params, ok := route.match(path)
+		if !ok {
+			continue
+		}
+
+		if route.method == method {
+			return route, params, nil
+		}
+
+		if method == http.MethodHead && route.method == http.MethodGet && fallback == nil {
+			fallback, fallbackParams = route, params
+		}
+
+		allowed = append(allowed, route.method)
+	}
+
+	if fallback != nil {
+		return fallback, fallbackParams, nil
+	}
+
+	sort.Strings(allowed)
+
+	return nil, map[string]string{}, uniqueStrings(allowed)
+}
+
+// runHooks runs the hooks that match the path, and reports whether the request should go on.
+// Before hooks stop when one of them halts the response, and all hooks stop when one of them raises an error.
+func (r *simpleRouter) runHooks(t *thread, hooks []*serverRoute, path string, req, res *RObject, before bool) bool {
+	for _, hook := range hooks {
+		if _, ok := hook.match(path); !ok {
+			continue
+		}
+
+		if !r.yield(t, hook.blockFrame, req, res) {
+			return false
+		}
+
+		if halted, ok := res.instanceVariableGet("@halted"); before && ok && halted == TRUE {
+			return false
+		}
+	}
+
+	return true
+}
+
+// yield runs the block with the request and response, and reports whether it succeeded.
+// If it raises an error, the response status becomes 500.
+func (r *simpleRouter) yield(t *thread, blockFrame *callFrame, req, res *RObject) bool {
+	result := t.builtinMethodYield(blockFrame, req, res)
+
+	if err, ok := result.Target.(*Error); ok {
+		log.Printf("Error: %s", err.Message)
+		res.instanceVariableSet("@status", t.vm.initIntegerObject(http.StatusInternalServerError))
+		return false
+	}
+
+	return true
+}
+
+// match reports whether the route's path matches, and returns the path's parameters
+func (route *serverRoute) match(path string) (map[string]string, bool) {
+	matches := route.pattern.FindStringSubmatch(path)
+	if matches == nil {
+		return nil, false
+	}
+
+	params := map[string]string{}
+	for i, name := range route.names {
+		params[name] = matches[i+1]
+	}
+
+	return params, true
+}
+
+// Other helper functions -----------------------------------------------
+
+// compileRoutePath converts a route's path into a regular expression, and returns the names of its parameters.
+// ":name" matches a path segment and "*" matches anything, including slashes.
+func compileRoutePath(path string) (*regexp.Regexp, []string) {
+	var b strings.Builder
+	names := []string{}
+
+	b.WriteString("^")
+
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case ':':
+			j := i + 1
+			for j < len(path) && (path[j] == '_' || isLetterOrDigit(path[j])) {
+				j++
+			}
+
+			if j == i+1 {
+				b.WriteString(regexp.QuoteMeta(":"))
+				continue
+			}
+
+			names = append(names, path[i+1:j])
+			b.WriteString("([^/]+)")
+			i = j - 1
+		case '*':
+			names = append(names, "splat")
+			b.WriteString("(.*)")
+		default:
+			b.WriteString(regexp.QuoteMeta(path[i : i+1]))
+		}
+	}
+
+	b.WriteString("$")
+
+	return regexp.MustCompile(b.String()), names
+}
+
+func isLetterOrDigit(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// uniqueStrings removes the adjacent duplicates of the sorted strings
+func uniqueStrings(sorted []string) []string {
+	result := []string{}
+
+	for i, s := range sorted {
+		if i == 0 || s != sorted[i-1] {
+			result = append(result, s)
+		}
+	}
+
+	return result
+}
@@ -1,9 +1,17 @@
 package vm
 
 import (
+	"crypto/tls"
+	"fmt"
+	"io/ioutil"
+	"mime/multipart"
+	"net"
+	"net/http"
 	"net/http/httptest"
+	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestServerInitialization(t *testing.T) {
@@ -53,7 +61,7 @@ func TestSetupResponseDefaultValue(t *testing.T) {
 func TestInitRequest(t *testing.T) {
 	v := initTestVM()
 	reader := strings.NewReader("Hello World")
-	r := initRequest(v.mainThread, httptest.NewRecorder(), httptest.NewRequest("GET", "https://google.com/path", reader))
+	r := initRequest(v.mainThread, httptest.NewRecorder(), httptest.NewRequest("GET", "https://google.com/path", reader), map[string]string{})
 
 	tests := []struct {
 		varName  string
@@ -108,3 +116,643 @@ func TestInitRequest(t *testing.T) {
 	}
 
 }
+
+func TestSimpleServerRouting(t *testing.T) {
+	v := initTestVM()
+	server := v.testEval(t, `
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4000)
+
+	server.before do |req, res|
+	  res.set_header("X-Before", "all")
+	end
+
+	server.before("/admin/*") do |req, res|
+	  if req.get_header("Authorization").nil?
+	    res.halt(401, "Unauthorized")
+	  end
+	end
+
+	server.after("/users/:id") do |req, res|
+	  res.body = res.body + "!"
+	end
+
+	server.get("/") do |req, res|
+	  res.body = "home"
+	end
+
+	server.get("/users/:id") do |req, res|
+	  res.body = "user " + req.params["id"]
+	end
+
+	server.put("/users/:id") do |req, res|
+	  res.body = "updated " + req.params["id"]
+	end
+
+	server.get("/users/:user_id/posts/:id") do |req, res|
+	  res.body = req.params["user_id"] + "/" + req.params["id"]
+	end
+
+	server.get("/files/*") do |req, res|
+	  res.body = req.params["splat"]
+	end
+
+	server.get("/admin/stats") do |req, res|
+	  res.body = "stats"
+	end
+
+	server.get("/error") do |req, res|
+	  raise("oops")
+	end
+
+	server
+	`, getFilename())
+
+	tests := []struct {
+		method  string
+		path    string
+		headers map[string]string
+		status  int
+		body    string
+		allow   string
+	}{
+		{"GET", "/", nil, 200, "home", ""},
+		{"GET", "/users/1", nil, 200, "user 1!", ""},
+		{"PUT", "/users/2", nil, 200, "updated 2!", ""},
+		{"HEAD", "/users/1", nil, 200, "user 1!", ""},
+		{"GET", "/users/1/posts/3", nil, 200, "1/3", ""},
+		{"GET", "/files/css/app.css", nil, 200, "css/app.css", ""},
+		{"GET", "/admin/stats", nil, 401, "Unauthorized", ""},
+		{"GET", "/admin/stats", map[string]string{"Authorization": "Basic c3Rhbg=="}, 200, "stats", ""},
+		{"GET", "/users", nil, 404, "Not Found", ""},
+		{"DELETE", "/users/1", nil, 405, "Method Not Allowed!", "GET, PUT"},
+		{"GET", "/error", nil, 500, "", ""},
+	}
+
+	router := server.(*RObject).InstanceVariables
+	r, _ := router.get("@router")
+
+	for i, tt := range tests {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+
+		r.(*GoObject).data.(*simpleRouter).ServeHTTP(recorder, req)
+
+		if recorder.Code != tt.status {
+			t.Errorf("At test case %d: expect status to be %d. got=%d", i, tt.status, recorder.Code)
+		}
+
+		if recorder.Body.String() != tt.body {
+			t.Errorf("At test case %d: expect body to be %q. got=%q", i, tt.body, recorder.Body.String())
+		}
+
+		if recorder.Header().Get("Allow") != tt.allow {
+			t.Errorf("At test case %d: expect Allow header to be %q. got=%q", i, tt.allow, recorder.Header().Get("Allow"))
+		}
+	}
+}
+
+func TestSimpleServerNotFoundHandler(t *testing.T) {
+	v := initTestVM()
+	server := v.testEval(t, `
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4000)
+
+	server.not_found do |req, res|
+	  res.body = "Nothing at " + req.path
+	end
+
+	server
+	`, getFilename())
+
+	r, _ := server.(*RObject).InstanceVariables.get("@router")
+	recorder := httptest.NewRecorder()
+	r.(*GoObject).data.(*simpleRouter).ServeHTTP(recorder, httptest.NewRequest("GET", "/missing", nil))
+
+	if recorder.Code != 404 {
+		t.Fatalf("Expect response code to be 404. got=%d", recorder.Code)
+	}
+
+	if recorder.Body.String() != "Nothing at /missing" {
+		t.Fatalf("Expect response body to be \"Nothing at /missing\". got=%s", recorder.Body.String())
+	}
+}
+
+func TestSimpleServerForms(t *testing.T) {
+	v := initTestVM()
+	server := v.testEval(t, `
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4000)
+
+	server.post("/signup") do |req, res|
+	  res.body = req.form["name"] + " " + req.form["tags"].join(",") + " " + req.files.length.to_s
+	end
+
+	server.post("/upload") do |req, res|
+	  avatar = req.files["avatar"]
+	  res.set_header("X-Path", avatar.path)
+	  res.body = req.form["name"] + " " + avatar.filename + " " + avatar.content_type + " " + avatar.size.to_s + " " + avatar.read
+	end
+
+	server.post("/photos") do |req, res|
+	  res.body = req.files["photos"].map do |f|
+	    f.filename + ":" + f.read
+	  end.join(",")
+	end
+
+	server.get("/") do |req, res|
+	  res.body = req.form.length.to_s + " " + req.files.length.to_s
+	end
+
+	server
+	`, getFilename())
+
+	router := v.mainThread.simpleServerRouter(server)
+
+	multipartBody := func(files map[string][]string, fields map[string]string) (string, string) {
+		var b strings.Builder
+		w := multipart.NewWriter(&b)
+
+		for name, value := range fields {
+			w.WriteField(name, value)
+		}
+
+		for name, f := range files {
+			for i := 0; i < len(f); i += 2 {
+				part, _ := w.CreateFormFile(name, f[i])
+				part.Write([]byte(f[i+1]))
+			}
+		}
+
+		w.Close()
+		return b.String(), w.FormDataContentType()
+	}
+
+	uploadBody, uploadType := multipartBody(map[string][]string{"avatar": {"../../stan.txt", "hello"}}, map[string]string{"name": "Stan"})
+	photosBody, photosType := multipartBody(map[string][]string{"photos[]": {"a.png", "A", "b.png", "B"}}, nil)
+
+	tests := []struct {
+		method      string
+		path        string
+		contentType string
+		body        string
+		expected    string
+	}{
+		{"POST", "/signup", "application/x-www-form-urlencoded", "name=Stan+Lo&tags[]=ruby&tags[]=go&name=Stan", "Stan ruby,go 0"},
+		{"POST", "/upload", uploadType, uploadBody, "Stan stan.txt application/octet-stream 5 hello"},
+		{"POST", "/photos", photosType, photosBody, "a.png:A,b.png:B"},
+		{"GET", "/", "", "", "0 0"},
+		{"GET", "/", "text/plain", "name=Stan", "0 0"},
+	}
+
+	for i, tt := range tests {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		if tt.contentType != "" {
+			req.Header.Set("Content-Type", tt.contentType)
+		}
+
+		router.ServeHTTP(recorder, req)
+
+		if recorder.Body.String() != tt.expected {
+			t.Errorf("At test case %d: expect body to be %q. got=%q", i, tt.expected, recorder.Body.String())
+		}
+
+		if path := recorder.Header().Get("X-Path"); path != "" {
+			if _, err := os.Stat(path); !os.IsNotExist(err) {
+				t.Errorf("At test case %d: expect uploaded file %s to be removed", i, path)
+			}
+		}
+	}
+}
+
+func TestSimpleServerStaticFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "goby-static")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	os.MkdirAll(dir+"/public/docs", 0755)
+	ioutil.WriteFile(dir+"/public/app.css", []byte("body { color: red; }"), 0644)
+	ioutil.WriteFile(dir+"/public/docs/index.html", []byte("<h1>Docs</h1>"), 0644)
+	ioutil.WriteFile(dir+"/secret.txt", []byte("secret"), 0644)
+	ioutil.WriteFile(dir+"/report.csv", []byte("a,b\n1,2\n"), 0644)
+
+	v := initTestVM()
+	server := v.testEval(t, fmt.Sprintf(`
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(4000)
+	server.serve_static("%[1]s/public")
+	server.serve_static("%[1]s/public", "/assets")
+	server.serve_static("%[1]s/public/docs", "/manual/")
+
+	server.get("/") do |req, res|
+	  res.body = "home"
+	end
+
+	server.get("/report") do |req, res|
+	  res.set_header("Content-Disposition", "attachment")
+	  res.send_file("%[1]s/report.csv")
+	end
+
+	server.get("/missing") do |req, res|
+	  res.send_file("%[1]s/missing.csv")
+	end
+
+	server.get("/numbers") do |req, res|
+	  res.status = 201
+	  res.stream do |out|
+	    1.upto(3) do |i|
+	      out << i << "\n"
+	    end
+	    out.write("done")
+	  end
+	end
+
+	server.get("/broken") do |req, res|
+	  res.stream do |out|
+	    out.write("partial")
+	    raise("oops")
+	  end
+	end
+
+	server
+	`, dir), getFilename())
+
+	router := v.mainThread.simpleServerRouter(server)
+
+	tests := []struct {
+		method      string
+		path        string
+		headers     map[string]string
+		status      int
+		body        string
+		contentType string
+	}{
+		{"GET", "/app.css", nil, 200, "body { color: red; }", "text/css; charset=utf-8"},
+		{"GET", "/assets/app.css", nil, 200, "body { color: red; }", "text/css; charset=utf-8"},
+		{"GET", "/assetsapp.css", nil, 404, "Not Found", "text/plain; charset=utf-8"},
+		{"GET", "/manual", nil, 200, "<h1>Docs</h1>", "text/html; charset=utf-8"},
+		{"GET", "/docs/", nil, 200, "<h1>Docs</h1>", "text/html; charset=utf-8"},
+		{"GET", "/app.css", map[string]string{"Range": "bytes=0-3"}, 206, "body", "text/css; charset=utf-8"},
+		{"POST", "/app.css", nil, 404, "Not Found", "text/plain; charset=utf-8"},
+		{"GET", "/../secret.txt", nil, 404, "Not Found", "text/plain; charset=utf-8"},
+		{"GET", "/", nil, 200, "home", "text/plain; charset=utf-8"},
+		{"GET", "/report", nil, 200, "a,b\n1,2\n", "text/csv; charset=utf-8"},
+		{"GET", "/report", map[string]string{"Range": "bytes=4-"}, 206, "1,2\n", "text/csv; charset=utf-8"},
+		{"GET", "/missing", nil, 404, "Not Found", "text/plain; charset=utf-8"},
+		{"GET", "/numbers", nil, 201, "1\n2\n3\ndone", "text/plain; charset=utf-8"},
+		{"GET", "/broken", nil, 200, "partial", "text/plain; charset=utf-8"},
+	}
+
+	for i, tt := range tests {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+
+		router.ServeHTTP(recorder, req)
+
+		if recorder.Code != tt.status {
+			t.Errorf("At test case %d: expect status to be %d. got=%d", i, tt.status, recorder.Code)
+		}
+
+		if recorder.Body.String() != tt.body {
+			t.Errorf("At test case %d: expect body to be %q. got=%q", i, tt.body, recorder.Body.String())
+		}
+
+		if ct := recorder.Header().Get("Content-Type"); ct != tt.contentType {
+			t.Errorf("At test case %d: expect content type to be %q. got=%q", i, tt.contentType, ct)
+		}
+	}
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/report", nil))
+
+	etag := recorder.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("Expect the file to have an ETag")
+	}
+
+	if recorder.Header().Get("Content-Disposition") != "attachment" {
+		t.Fatalf("Expect the response's headers to be sent with the file. got=%q", recorder.Header().Get("Content-Disposition"))
+	}
+
+	for _, path := range []string{"/report", "/app.css"} {
+		recorder = httptest.NewRecorder()
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("If-None-Match", etag)
+		router.ServeHTTP(recorder, req)
+
+		if path == "/report" && recorder.Code != 304 {
+			t.Fatalf("Expect the request with the same ETag to get 304. got=%d", recorder.Code)
+		}
+		if path == "/app.css" && recorder.Code != 200 {
+			t.Fatalf("Expect the request with another file's ETag to get 200. got=%d", recorder.Code)
+		}
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest("GET", "/numbers", nil))
+
+	if !recorder.Flushed {
+		t.Fatal("Expect the streamed response to be flushed")
+	}
+}
+
+func TestSimpleServerMiddlewares(t *testing.T) {
+	v := initTestVM()
+	server := v.testEval(t, `
+	require "net/simple_server"
+
+	class Tag
+	  def initialize(app, name)
+	    @app = app
+	    @name = name
+	  end
+
+	  def call(req)
+	    res = @app.call(req)
+	    res.body = @name + "(" + res.body + ")"
+	    res
+	  end
+	end
+
+	class Auth
+	  def initialize(app)
+	    @app = app
+	  end
+
+	  def call(req)
+	    if req.get_header("Authorization").nil?
+	      res = Net::HTTP::Response.new
+	      res.status = 401
+	      res.body = "Unauthorized"
+	      return res
+	    end
+
+	    @app.call(req)
+	  end
+	end
+
+	class Broken
+	  def initialize(app)
+	    @app = app
+	  end
+
+	  def call(req)
+	    if req.path == "/raise"
+	      raise("oops")
+	    end
+
+	    "not a response"
+	  end
+	end
+
+	server = Net::SimpleServer.new(4000)
+	server.use(Tag, "outer").use(Tag, "inner")
+
+	server.get("/") do |req, res|
+	  res.body = "home"
+	end
+
+	server.get("/users/:id") do |req, res|
+	  res.body = "user " + req.params["id"]
+	end
+
+	secured = Net::SimpleServer.new(4001)
+	secured.use(Auth)
+	secured.get("/") do |req, res|
+	  res.body = "secret"
+	end
+
+	broken = Net::SimpleServer.new(4002)
+	broken.use(Broken)
+
+	[server, secured, broken]
+	`, getFilename())
+
+	servers := server.(*ArrayObject).Elements
+
+	tests := []struct {
+		server  int
+		path    string
+		headers map[string]string
+		status  int
+		body    string
+	}{
+		{0, "/", nil, 200, "outer(inner(home))"},
+		{0, "/users/1", nil, 200, "outer(inner(user 1))"},
+		{0, "/missing", nil, 404, "outer(inner(Not Found))"},
+		{1, "/", nil, 401, "Unauthorized"},
+		{1, "/", map[string]string{"Authorization": "Basic c3Rhbg=="}, 200, "secret"},
+		{2, "/", nil, 500, ""},
+		{2, "/raise", nil, 500, ""},
+	}
+
+	for i, tt := range tests {
+		recorder := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", tt.path, nil)
+		for k, v := range tt.headers {
+			req.Header.Set(k, v)
+		}
+
+		v.mainThread.simpleServerRouter(servers[tt.server]).ServeHTTP(recorder, req)
+
+		if recorder.Code != tt.status {
+			t.Errorf("At test case %d: expect status to be %d. got=%d", i, tt.status, recorder.Code)
+		}
+
+		if recorder.Body.String() != tt.body {
+			t.Errorf("At test case %d: expect body to be %q. got=%q", i, tt.body, recorder.Body.String())
+		}
+
+		if recorder.Header().Get("Content-Type") != "text/plain; charset=utf-8" {
+			t.Errorf("At test case %d: expect content type to be \"text/plain; charset=utf-8\". got=%q", i, recorder.Header().Get("Content-Type"))
+		}
+	}
+}
+
+func TestSimpleServerBuilder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "net/simple_server"
+
+		class Upcase
+		  def initialize(app)
+		    @app = app
+		  end
+
+		  def call(req)
+		    @app.call(req).upcase
+		  end
+		end
+
+		class Hello
+		  def call(req)
+		    "hello " + req
+		  end
+		end
+
+		app = Net::SimpleServer::Builder.new(Hello.new).use(Upcase)
+		app.call("goby")
+		`, "HELLO GOBY"},
+		{`
+		require "net/simple_server"
+
+		class Hello
+		  def call(req)
+		    "hello " + req
+		  end
+		end
+
+		builder = Net::SimpleServer::Builder.new(Hello.new)
+		builder.to_app.class.name
+		`, "Hello"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSimpleServerMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).mount("/", "GET")`, "InternalError: Can't yield without a block", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).mount("/")`, "ArgumentError: Expect 2 arguments. got: 1", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).before(1) do end`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).after("/", "/") do end`, "ArgumentError: Expect 0..1 argument. got: 2", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).not_found`, "InternalError: Can't yield without a block", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).call("/")`, "TypeError: Expect argument to be Net::HTTP::Request. got: String", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).serve_static`, "ArgumentError: Expect 1..2 arguments. got: 0", 2},
+		{`require "net/simple_server"
+		Net::SimpleServer.new(4000).serve_static("public", 1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "net/simple_server"
+		Net::HTTP::Response.new.send_file(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "net/simple_server"
+		Net::HTTP::Response.new.stream`, "InternalError: Can't yield without a block", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSimpleServerListenTLS(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	v := initTestVM()
+	v.testEval(t, fmt.Sprintf(`
+	require "net/simple_server"
+
+	server = Net::SimpleServer.new(%d)
+	server.client_ca_file = "../test_fixtures/tls/ca.pem"
+	server.get("/") do |req, res|
+	  res.body = "Hello, TLS"
+	end
+
+	thread do
+	  server.listen_tls("../test_fixtures/tls/server.pem", "../test_fixtures/tls/server-key.pem")
+	end
+	`, port), getFilename())
+
+	pool, err := loadCertPool("../test_fixtures/tls/ca.pem")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cert, err := tls.LoadX509KeyPair("../test_fixtures/tls/client.pem", "../test_fixtures/tls/client-key.pem")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool, Certificates: []tls.Certificate{cert}}}}
+	url := fmt.Sprintf("https://127.0.0.1:%d/", port)
+
+	var resp *http.Response
+
+	// Wait for the server to start listening
+	for i := 0; i < 50; i++ {
+		if resp, err = client.Get(url); err == nil {
+			break
+		}
+
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+
+	if string(body) != "Hello, TLS" {
+		t.Fatalf("Expect response body to be \"Hello, TLS\". got=%s", body)
+	}
+
+	noCertClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: pool}}}
+	if _, err := noCertClient.Get(url); err == nil {
+		t.Fatal("Expect the request without a client certificate to fail")
+	}
+}
+
+func TestSimpleServerTLSFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "net/simple_server"
+		server = Net::SimpleServer.new(4000)
+		server.cert_file = "../test_fixtures/tls/server.pem"
+		server.start`, "ArgumentError: Expect cert_file and key_file to be given together", 4},
+		{`require "net/simple_server"
+		server = Net::SimpleServer.new(4000)
+		server.client_ca_file = "../test_fixtures/tls/ca.pem"
+		server.start`, "ArgumentError: Expect cert_file and key_file to verify client certificates", 4},
+		{`require "net/simple_server"
+		server = Net::SimpleServer.new(4000)
+		server.cert_file = "../test_fixtures/tls/server.pem"
+		server.key_file = "../test_fixtures/tls/server-key.pem"
+		server.client_ca_file = "../test_fixtures/no_such_file.pem"
+		server.start`, "InternalError: open ../test_fixtures/no_such_file.pem: no such file or directory", 6},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
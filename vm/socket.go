@@ -0,0 +1,866 @@
+package vm
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// The socket library provides `TCPServer`, `TCPSocket` and `UDPSocket`, which make it possible to write servers
+// and clients of custom protocols. Blocking methods like `accept`, `gets` and `recv` stop waiting when the thread's
+// `Timeout.timeout` expires.
+//
+// ```ruby
+// require "socket"
+//
+// server = TCPServer.new("127.0.0.1", 3000)
+// thread do
+//   client = server.accept
+//   client.puts("Hello, " + client.gets.chomp)
+//   client.close
+// end
+//
+// socket = TCPSocket.new("127.0.0.1", 3000)
+// socket.puts("Goby")
+// socket.gets # => "Hello, Goby\n"
+// socket.close
+// ```
+
+// TCPServerObject listens for TCP connections, `accept` returns the connections as `TCPSocket`s.
+type TCPServerObject struct {
+	*baseObj
+	listener *net.TCPListener
+}
+
+// TCPSocketObject is a TCP connection, which can be read and written like `IO`.
+type TCPSocketObject struct {
+	*baseObj
+	conn   *net.TCPConn
+	reader *bufio.Reader
+}
+
+// UDPSocketObject sends and receives UDP datagrams. It can be bound to a local address to receive datagrams,
+// and connected to a remote address to send datagrams without giving the address every time.
+//
+// ```ruby
+// server = UDPSocket.new
+// server.bind("127.0.0.1", 4000)
+//
+// client = UDPSocket.new
+// client.send("ping", "127.0.0.1", 4000)
+//
+// message, host, port = server.recvfrom(1024) # => ["ping", "127.0.0.1", 53421]
+// server.send("pong", host, port)
+// client.recv(1024) # => "pong"
+// ```
+type UDPSocketObject struct {
+	*baseObj
+	conn   *net.UDPConn
+	remote *net.UDPAddr
+}
+
+// Class methods --------------------------------------------------------
+func builtinTCPServerClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Listens on the given host and port, the host is all interfaces if it's omitted.
+			// If the port is 0, a free port is chosen, which can be read by `port`.
+			//
+			// ```ruby
+			// TCPServer.new(3000)
+			// TCPServer.new("127.0.0.1", 0).port # => 53421
+			// ```
+			//
+			// @param host [String], port [Integer]
+			// @return [TCPServer]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					address, e := t.socketAddress(args, true)
+					if e != nil {
+						return e
+					}
+
+					addr, err := net.ResolveTCPAddr("tcp", address)
+					if err != nil {
+						return t.initErrorObject(errors.ArgumentError, err.Error())
+					}
+
+					listener, err := net.ListenTCP("tcp", addr)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return &TCPServerObject{baseObj: &baseObj{class: receiver.(*RClass)}, listener: listener}
+				}
+			},
+		},
+	}
+}
+
+func builtinTCPSocketClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Connects to the given host and port.
+			//
+			// ```ruby
+			// socket = TCPSocket.new("example.com", 80)
+			// ```
+			//
+			// @param host [String], port [Integer]
+			// @return [TCPSocket]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					address, e := t.socketAddress(args, false)
+					if e != nil {
+						return e
+					}
+
+					var dialer net.Dialer
+
+					conn, err := dialer.DialContext(t.context(), "tcp", address)
+					if err != nil {
+						if t.context().Err() != nil {
+							return t.timeoutError()
+						}
+
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return &TCPSocketObject{baseObj: &baseObj{class: receiver.(*RClass)}, conn: conn.(*net.TCPConn)}
+				}
+			},
+		},
+	}
+}
+
+func builtinUDPSocketClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Initializes a socket, which is bound to a local address by `bind`, `connect` or the first `send`.
+			//
+			// ```ruby
+			// socket = UDPSocket.new
+			// ```
+			//
+			// @return [UDPSocket]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return &UDPSocketObject{baseObj: &baseObj{class: receiver.(*RClass)}}
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinTCPServerInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Waits for the next connection and returns it.
+			//
+			// ```ruby
+			// client = server.accept
+			// client.gets
+			// ```
+			//
+			// @return [TCPSocket]
+			Name: "accept",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					s := receiver.(*TCPServerObject)
+					s.listener.SetDeadline(t.socketDeadline())
+
+					conn, err := s.listener.AcceptTCP()
+					if err != nil {
+						return t.socketError(err)
+					}
+
+					return t.vm.initTCPSocketObject(conn)
+				}
+			},
+		},
+		{
+			// Stops listening.
+			//
+			// ```ruby
+			// server.close # => nil
+			// ```
+			//
+			// @return [Null]
+			Name: "close",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					receiver.(*TCPServerObject).listener.Close()
+					return NULL
+				}
+			},
+		},
+		{
+			// Returns the port that the server listens on.
+			//
+			// ```ruby
+			// TCPServer.new(3000).port # => 3000
+			// ```
+			//
+			// @return [Integer]
+			Name: "port",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TCPServerObject).listener.Addr().(*net.TCPAddr).Port)
+				}
+			},
+		},
+	}
+}
+
+func builtinTCPSocketInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Closes the connection.
+			//
+			// ```ruby
+			// socket.close # => nil
+			// ```
+			//
+			// @return [Null]
+			Name: "close",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					receiver.(*TCPSocketObject).conn.Close()
+					return NULL
+				}
+			},
+		},
+		{
+			// Closes the writing side of the connection, so the peer reads the end of the stream
+			// while this side can still read the reply.
+			//
+			// ```ruby
+			// socket.write("request")
+			// socket.close_write
+			// socket.read # => "response"
+			// ```
+			//
+			// @return [Null]
+			Name: "close_write",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := receiver.(*TCPSocketObject).conn.CloseWrite(); err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Yields each line from the connection to the block until the peer closes it.
+			// Returns an enumerator if no block is given.
+			//
+			// ```ruby
+			// client.each_line do |line|
+			//   client.write(line.upcase)
+			// end
+			// ```
+			//
+			// @return [TCPSocket]
+			Name: "each_line",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_line", args)
+					}
+
+					s := receiver.(*TCPSocketObject)
+					s.conn.SetReadDeadline(t.socketDeadline())
+
+					if err := t.eachLine(s.bufferedReader(), blockFrame); err != nil {
+						return t.socketReadError(err)
+					}
+
+					return receiver
+				}
+			},
+		},
+		{
+			// Reads the next line from the connection, including the trailing newline.
+			// Returns nil if the peer has closed the connection.
+			//
+			// ```ruby
+			// socket.gets # => "Hello\n"
+			// ```
+			//
+			// @return [String]
+			Name: "gets",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					s := receiver.(*TCPSocketObject)
+					s.conn.SetReadDeadline(t.socketDeadline())
+
+					line := t.readLine(s.bufferedReader())
+					if err, ok := line.(*Error); ok {
+						return t.socketReadError(err)
+					}
+
+					return line
+				}
+			},
+		},
+		{
+			// Returns the local address of the connection.
+			//
+			// ```ruby
+			// socket.local_address # => "127.0.0.1:53421"
+			// ```
+			//
+			// @return [String]
+			Name: "local_address",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*TCPSocketObject).conn.LocalAddr().String())
+				}
+			},
+		},
+		{
+			// Writes the given objects into the connection without adding newlines.
+			//
+			// ```ruby
+			// socket.print("GET / HTTP/1.0\r\n", "\r\n")
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Null]
+			Name: "print",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.print(receiver.(*TCPSocketObject).conn, args)
+				}
+			},
+		},
+		{
+			// Writes each of the given objects into the connection, followed by a newline.
+			//
+			// ```ruby
+			// socket.puts("HELO goby.org")
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Null]
+			Name: "puts",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.puts(receiver.(*TCPSocketObject).conn, args)
+				}
+			},
+		},
+		{
+			// Reads the given number of bytes from the connection, or fewer if the peer closes it. Without the length,
+			// it reads until the peer closes the connection. Returns nil if the length is given and there's nothing to read.
+			//
+			// ```ruby
+			// socket.read(4) # => "HTTP"
+			// socket.read    # => "/1.0 200 OK\r\n..."
+			// ```
+			//
+			// @param length [Integer]
+			// @return [String]
+			Name: "read",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					s := receiver.(*TCPSocketObject)
+					s.conn.SetReadDeadline(t.socketDeadline())
+
+					if len(args) == 0 {
+						content, err := ioutil.ReadAll(s.bufferedReader())
+						if err != nil {
+							return t.socketError(err)
+						}
+
+						return t.vm.initStringObject(string(content))
+					}
+
+					length, ok := args[0].(*IntegerObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+					}
+
+					buf := make([]byte, length.value)
+
+					n, err := io.ReadFull(s.bufferedReader(), buf)
+					if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+						return t.socketError(err)
+					}
+
+					if n == 0 && length.value > 0 {
+						return NULL
+					}
+
+					return t.vm.initStringObject(string(buf[:n]))
+				}
+			},
+		},
+		{
+			// Returns the address of the peer.
+			//
+			// ```ruby
+			// client.remote_address # => "127.0.0.1:53421"
+			// ```
+			//
+			// @return [String]
+			Name: "remote_address",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*TCPSocketObject).conn.RemoteAddr().String())
+				}
+			},
+		},
+		{
+			// Writes the given objects into the connection and returns the number of bytes written.
+			//
+			// ```ruby
+			// socket.write("PING\r\n") # => 6
+			// ```
+			//
+			// @param *args [Object]
+			// @return [Integer]
+			Name: "write",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.write(receiver.(*TCPSocketObject).conn, args)
+				}
+			},
+		},
+	}
+}
+
+func builtinUDPSocketInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Binds the socket to the given local host and port so it can receive datagrams. If the port is 0,
+			// a free port is chosen, which can be read by `port`. Returns 0.
+			//
+			// ```ruby
+			// socket.bind("127.0.0.1", 4000) # => 0
+			// ```
+			//
+			// @param host [String], port [Integer]
+			// @return [Integer]
+			Name: "bind",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					s := receiver.(*UDPSocketObject)
+					if s.conn != nil {
+						return t.initErrorObject(errors.InternalError, "Socket is already bound to %s", s.conn.LocalAddr().String())
+					}
+
+					addr, e := t.udpAddress(args)
+					if e != nil {
+						return e
+					}
+
+					conn, err := net.ListenUDP("udp", addr)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					s.conn = conn
+
+					return t.vm.initIntegerObject(0)
+				}
+			},
+		},
+		{
+			// Closes the socket.
+			//
+			// ```ruby
+			// socket.close # => nil
+			// ```
+			//
+			// @return [Null]
+			Name: "close",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if s := receiver.(*UDPSocketObject); s.conn != nil {
+						s.conn.Close()
+					}
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Sets the address that `send` sends datagrams to when it's called without an address. Returns 0.
+			//
+			// ```ruby
+			// socket.connect("127.0.0.1", 4000)
+			// socket.send("ping")
+			// ```
+			//
+			// @param host [String], port [Integer]
+			// @return [Integer]
+			Name: "connect",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, errors.WrongNumberOfArgumentFormat, 2, len(args))
+					}
+
+					addr, e := t.udpAddress(args)
+					if e != nil {
+						return e
+					}
+
+					s := receiver.(*UDPSocketObject)
+					if e := t.bindUDPSocket(s); e != nil {
+						return e
+					}
+
+					s.remote = addr
+
+					return t.vm.initIntegerObject(0)
+				}
+			},
+		},
+		{
+			// Returns the local port of the socket, or nil if it isn't bound yet.
+			//
+			// ```ruby
+			// socket.bind("127.0.0.1", 0)
+			// socket.port # => 53421
+			// ```
+			//
+			// @return [Integer]
+			Name: "port",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					s := receiver.(*UDPSocketObject)
+					if s.conn == nil {
+						return NULL
+					}
+
+					return t.vm.initIntegerObject(s.conn.LocalAddr().(*net.UDPAddr).Port)
+				}
+			},
+		},
+		{
+			// Receives a datagram of at most the given number of bytes, which is 65536 by default.
+			//
+			// ```ruby
+			// socket.recv(1024) # => "pong"
+			// ```
+			//
+			// @param length [Integer]
+			// @return [String]
+			Name: "recv",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					message, _, e := t.receiveDatagram(receiver.(*UDPSocketObject), args)
+					if e != nil {
+						return e
+					}
+
+					return message
+				}
+			},
+		},
+		{
+			// Receives a datagram like `recv`, and returns it with the sender's host and port.
+			//
+			// ```ruby
+			// message, host, port = socket.recvfrom(1024) # => ["ping", "127.0.0.1", 53421]
+			// ```
+			//
+			// @param length [Integer]
+			// @return [Array]
+			Name: "recvfrom",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					message, addr, e := t.receiveDatagram(receiver.(*UDPSocketObject), args)
+					if e != nil {
+						return e
+					}
+
+					return t.vm.initArrayObject([]Object{message, t.vm.initStringObject(addr.IP.String()), t.vm.initIntegerObject(addr.Port)})
+				}
+			},
+		},
+		{
+			// Sends the message to the given host and port, or to the connected address if they're omitted.
+			// Returns the number of bytes sent.
+			//
+			// ```ruby
+			// socket.send("ping", "127.0.0.1", 4000) # => 4
+			// ```
+			//
+			// @param message [String], host [String], port [Integer]
+			// @return [Integer]
+			Name: "send",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 && len(args) != 3 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 or 3 arguments. got: %d", len(args))
+					}
+
+					message, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					s := receiver.(*UDPSocketObject)
+					addr := s.remote
+
+					if len(args) == 3 {
+						a, e := t.udpAddress(args[1:])
+						if e != nil {
+							return e
+						}
+
+						addr = a
+					}
+
+					if addr == nil {
+						return t.initErrorObject(errors.ArgumentError, "Expect a destination address since the socket isn't connected")
+					}
+
+					if e := t.bindUDPSocket(s); e != nil {
+						return e
+					}
+
+					n, err := s.conn.WriteToUDP([]byte(message.value), addr)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.vm.initIntegerObject(n)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initTCPSocketObject(conn *net.TCPConn) *TCPSocketObject {
+	return &TCPSocketObject{
+		baseObj: &baseObj{class: vm.topLevelClass("TCPSocket")},
+		conn:    conn,
+	}
+}
+
+func initSocketClasses(vm *VM) {
+	server := vm.initializeClass("TCPServer", false)
+	server.setBuiltinMethods(builtinTCPServerClassMethods(), true)
+	server.setBuiltinMethods(builtinTCPServerInstanceMethods(), false)
+	vm.objectClass.setClassConstant(server)
+
+	socket := vm.initializeClass("TCPSocket", false)
+	socket.setBuiltinMethods(builtinTCPSocketClassMethods(), true)
+	socket.setBuiltinMethods(builtinTCPSocketInstanceMethods(), false)
+	vm.objectClass.setClassConstant(socket)
+
+	udp := vm.initializeClass("UDPSocket", false)
+	udp.setBuiltinMethods(builtinUDPSocketClassMethods(), true)
+	udp.setBuiltinMethods(builtinUDPSocketInstanceMethods(), false)
+	vm.objectClass.setClassConstant(udp)
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the server's address
+func (s *TCPServerObject) toString() string {
+	return "<TCPServer: " + s.listener.Addr().String() + ">"
+}
+
+// Alias of toString
+func (s *TCPServerObject) toJSON() string {
+	return s.toString()
+}
+
+// Returns the addresses of the connection
+func (s *TCPSocketObject) toString() string {
+	return "<TCPSocket: " + s.conn.LocalAddr().String() + " -> " + s.conn.RemoteAddr().String() + ">"
+}
+
+// Alias of toString
+func (s *TCPSocketObject) toJSON() string {
+	return s.toString()
+}
+
+// bufferedReader returns the reader which all the reading methods of the socket share
+func (s *TCPSocketObject) bufferedReader() *bufio.Reader {
+	if s.reader == nil {
+		s.reader = bufio.NewReader(s.conn)
+	}
+
+	return s.reader
+}
+
+// Returns the local address of the socket
+func (s *UDPSocketObject) toString() string {
+	if s.conn == nil {
+		return "<UDPSocket>"
+	}
+
+	return "<UDPSocket: " + s.conn.LocalAddr().String() + ">"
+}
+
+// Alias of toString
+func (s *UDPSocketObject) toJSON() string {
+	return s.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// socketAddress returns the "host:port" address of the host and port arguments.
+// The host can be omitted if optionalHost is true.
+func (t *thread) socketAddress(args []Object, optionalHost bool) (string, *Error) {
+	host := ""
+
+	switch {
+	case len(args) == 2:
+		h, ok := args[0].(*StringObject)
+		if !ok {
+			return "", t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+		}
+
+		host = h.value
+		args = args[1:]
+	case len(args) != 1 || !optionalHost:
+		return "", t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got: %d", len(args))
+	}
+
+	port, ok := args[0].(*IntegerObject)
+	if !ok {
+		return "", t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+	}
+
+	return net.JoinHostPort(host, strconv.Itoa(port.value)), nil
+}
+
+// udpAddress resolves the host and port arguments
+func (t *thread) udpAddress(args []Object) (*net.UDPAddr, *Error) {
+	address, e := t.socketAddress(args, false)
+	if e != nil {
+		return nil, e
+	}
+
+	addr, err := net.ResolveUDPAddr("udp", address)
+	if err != nil {
+		return nil, t.initErrorObject(errors.ArgumentError, err.Error())
+	}
+
+	return addr, nil
+}
+
+// bindUDPSocket binds the socket to a free local port if it isn't bound yet
+func (t *thread) bindUDPSocket(s *UDPSocketObject) *Error {
+	if s.conn != nil {
+		return nil
+	}
+
+	conn, err := net.ListenUDP("udp", nil)
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	s.conn = conn
+
+	return nil
+}
+
+// receiveDatagram receives a datagram of at most the length argument's bytes, and returns it with the sender's address
+func (t *thread) receiveDatagram(s *UDPSocketObject, args []Object) (Object, *net.UDPAddr, *Error) {
+	length := 65536
+
+	switch len(args) {
+	case 0:
+	case 1:
+		l, ok := args[0].(*IntegerObject)
+		if !ok {
+			return nil, nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+		}
+
+		length = l.value
+	default:
+		return nil, nil, t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+	}
+
+	if s.conn == nil {
+		return nil, nil, t.initErrorObject(errors.InternalError, "Can't receive datagrams before the socket is bound")
+	}
+
+	s.conn.SetReadDeadline(t.socketDeadline())
+
+	buf := make([]byte, length)
+
+	n, addr, err := s.conn.ReadFromUDP(buf)
+	if err != nil {
+		return nil, nil, t.socketError(err)
+	}
+
+	return t.vm.initStringObject(string(buf[:n])), addr, nil
+}
+
+// socketDeadline returns the deadline of the thread's timeout, or the zero time if there's no timeout,
+// so the blocking socket methods stop waiting when the timeout expires
+func (t *thread) socketDeadline() time.Time {
+	deadline, _ := t.context().Deadline()
+	return deadline
+}
+
+// socketError converts the error of a socket operation, deadline errors become the timeout's error
+func (t *thread) socketError(err error) *Error {
+	if e, ok := err.(net.Error); ok && e.Timeout() && t.context().Err() != nil {
+		return t.timeoutError()
+	}
+
+	return t.initErrorObject(errors.InternalError, err.Error())
+}
+
+// socketReadError converts the error returned by readLine, which already wraps the socket's error
+func (t *thread) socketReadError(err *Error) *Error {
+	if t.context().Err() != nil {
+		return t.timeoutError()
+	}
+
+	return err
+}
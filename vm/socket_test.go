@@ -0,0 +1,198 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestTCPSocket(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "socket"
+
+		server = TCPServer.new("127.0.0.1", 0)
+		thread do
+		  client = server.accept
+		  client.puts("Hello, " + client.gets.chomp)
+		  client.close
+		end
+
+		socket = TCPSocket.new("127.0.0.1", server.port)
+		socket.puts("Goby")
+		line = socket.gets
+		eof = socket.gets
+		socket.close
+		server.close
+		[line.chomp, eof].to_s
+		`, `["Hello, Goby", nil]`},
+		{`
+		require "socket"
+
+		server = TCPServer.new("127.0.0.1", 0)
+		thread do
+		  client = server.accept
+		  client.each_line do |line|
+		    client.write(line.upcase)
+		  end
+		  client.close
+		end
+
+		socket = TCPSocket.new("127.0.0.1", server.port)
+		socket.print("foo\n", "bar\n")
+		socket.close_write
+		content = socket.read
+		socket.close
+		server.close
+		content
+		`, "FOO\nBAR\n"},
+		{`
+		require "socket"
+
+		server = TCPServer.new("127.0.0.1", 0)
+		thread do
+		  client = server.accept
+		  client.write("GobyLang")
+		  client.close
+		end
+
+		socket = TCPSocket.new("127.0.0.1", server.port)
+		result = [socket.read(4), socket.read(10), socket.read(1)]
+		socket.close
+		server.close
+		result.to_s
+		`, `["Goby", "Lang", nil]`},
+		{`
+		require "socket"
+
+		server = TCPServer.new("127.0.0.1", 0)
+		socket = TCPSocket.new("127.0.0.1", server.port)
+		client = server.accept
+		result = client.remote_address == socket.local_address && socket.remote_address == "127.0.0.1:" + server.port.to_s
+		socket.close
+		client.close
+		server.close
+		result
+		`, true},
+		{`
+		require "socket"
+
+		server = TCPServer.new("127.0.0.1", 0)
+		begin
+		  Timeout.timeout(0.1) do
+		    server.accept
+		  end
+		rescue Timeout::Error => e
+		  server.close
+		  e.message
+		end
+		`, "execution expired"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestUDPSocket(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "socket"
+
+		server = UDPSocket.new
+		server.bind("127.0.0.1", 0)
+		client = UDPSocket.new
+		client.send("ping", "127.0.0.1", server.port)
+
+		message, host, port = server.recvfrom(1024)
+		server.send("pong", host, port)
+		reply = client.recv
+		server.close
+		client.close
+		[message, host, port == client.port, reply].to_s
+		`, `["ping", "127.0.0.1", true, "pong"]`},
+		{`
+		require "socket"
+
+		server = UDPSocket.new
+		server.bind("127.0.0.1", 0)
+		client = UDPSocket.new
+		client.connect("127.0.0.1", server.port)
+		n = client.send("GobyLang")
+		message = server.recv(4)
+		server.close
+		client.close
+		[n, message].to_s
+		`, `[8, "Goby"]`},
+		{`
+		require "socket"
+
+		UDPSocket.new.port
+		`, nil},
+		{`
+		require "socket"
+
+		server = UDPSocket.new
+		server.bind("127.0.0.1", 0)
+		begin
+		  Timeout.timeout(0.1) do
+		    server.recv
+		  end
+		rescue Timeout::Error => e
+		  server.close
+		  e.message
+		end
+		`, "execution expired"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSocketMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "socket"
+		TCPServer.new`, "ArgumentError: Expect 1..2 arguments. got: 0", 2},
+		{`require "socket"
+		TCPServer.new("3000")`, "TypeError: Expect argument to be Integer. got: String", 2},
+		{`require "socket"
+		TCPServer.new(1, 3000)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "socket"
+		TCPSocket.new(3000)`, "ArgumentError: Expect 2 arguments. got: 1", 2},
+		{`require "socket"
+		TCPSocket.new("127.0.0.1", 1)`, "InternalError: dial tcp 127.0.0.1:1: connect: connection refused", 2},
+		{`require "socket"
+		UDPSocket.new(1)`, "ArgumentError: Expect 0 argument. got: 1", 2},
+		{`require "socket"
+		UDPSocket.new.recv`, "InternalError: Can't receive datagrams before the socket is bound", 2},
+		{`require "socket"
+		UDPSocket.new.send("ping")`, "ArgumentError: Expect a destination address since the socket isn't connected", 2},
+		{`require "socket"
+		UDPSocket.new.send("ping", "127.0.0.1")`, "ArgumentError: Expect 1 or 3 arguments. got: 2", 2},
+		{`require "socket"
+		UDPSocket.new.send(1, "127.0.0.1", 4000)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "socket"
+		UDPSocket.new.bind("127.0.0.1")`, "ArgumentError: Expect 2 arguments. got: 1", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
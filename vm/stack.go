@@ -1,8 +1,6 @@
 package vm
 
 import (
-	"fmt"
-	"os"
 	"sync"
 )
 
@@ -18,14 +16,15 @@ type stack struct {
 func (s *stack) set(index int, pointer *Pointer) {
 	t := s.thread
 
-	if err, ok := pointer.Target.(*Error); ok {
+	if err, ok := pointer.Target.(*Error); ok && !err.rescued {
+		t.raise(err)
+
 		cf := t.callFrameStack.top()
 		cf.pc = len(cf.instructionSet.instructions)
 
 		if t.vm.mode == NormalMode {
 			if t.isMainThread() {
-				fmt.Println(err.Message)
-				os.Exit(1)
+				t.vm.exitWithError(err)
 			}
 		}
 	}
@@ -41,21 +40,24 @@ func (s *stack) push(v *Pointer) {
 	s.Lock()
 	defer s.Unlock()
 
+	if err, ok := v.Target.(*Error); ok && !err.rescued {
+		s.thread.raise(err)
+	}
+
 	if len(s.Data) <= s.thread.sp {
 		s.Data = append(s.Data, v)
 	} else {
 		s.Data[s.thread.sp] = v
 	}
 
-	if err, ok := v.Target.(*Error); ok {
+	if err, ok := v.Target.(*Error); ok && !err.rescued {
 		t := s.thread
 		cf := t.callFrameStack.top()
 		cf.pc = len(cf.instructionSet.instructions)
 
 		if t.vm.mode == NormalMode {
 			if t.isMainThread() {
-				fmt.Println(err.Message)
-				os.Exit(1)
+				t.vm.exitWithError(err)
 			}
 		}
 	}
@@ -300,6 +300,72 @@ func TestModuleStatement(t *testing.T) {
 
 		Bar.ten
 		`, 10},
+		{`
+		module Loud
+		  def shout
+		    to_s + "!"
+		  end
+		end
+
+		s = "hi"
+		s.extend(Loud).shout
+		`, "hi!"},
+		{`
+		module Loud
+		  def shout
+		    to_s + "!"
+		  end
+		end
+
+		s = "hi"
+		s.extend(Loud)
+
+		def s.whisper
+		  to_s + "..."
+		end
+
+		s.shout + s.whisper
+		`, "hi!hi..."},
+		{`
+		module Loud
+		  def shout
+		    to_s + "!"
+		  end
+		end
+
+		"hi".extend(Loud)
+		"hi".respond_to?(:shout)
+		`, false},
+		{`
+		module Greeting
+		  module_function
+
+		  def hello(name)
+		    "Hello, " + name
+		  end
+		end
+
+		Greeting.hello("Goby")
+		`, "Hello, Goby"},
+		{`
+		module Util
+		  def twice(n)
+		    n * 2
+		  end
+
+		  module_function :twice
+		end
+
+		class Foo
+		  include Util
+
+		  def four
+		    twice(2)
+		  end
+		end
+
+		Util.twice(3) + Foo.new.four
+		`, 10},
 	}
 
 	for i, tt := range tests {
@@ -311,6 +377,45 @@ func TestModuleStatement(t *testing.T) {
 	}
 }
 
+func TestModuleStatementFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`class Foo
+		end
+
+		"hi".extend(Foo)`, "TypeError: Expect argument to be a module. got=Class", 4},
+		{`"hi".extend`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`module Util
+		  module_function
+
+		  def twice(n)
+		    n * 2
+		  end
+		end
+
+		class Foo
+		  include Util
+		end
+
+		Foo.new.twice(1)`, "UndefinedMethodError: Private method 'twice' called for <Instance of: Foo>", 13},
+		{`class Foo
+		end
+
+		Foo.module_function`, "UndefinedMethodError: Undefined Method 'module_function' for Foo", 4},
+		{`module Util
+		end
+
+		Util.module_function(:twice)`, "UndefinedMethodError: Undefined Method 'twice' for Util", 4},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestWhileStatement(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -377,6 +482,62 @@ func TestWhileStatement(t *testing.T) {
 	}
 }
 
+func TestUntilStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int
+	}{
+		{
+			`
+			i = 10
+
+			until i > 0
+			  i = i + 1
+			end
+
+			i
+			`, 10},
+		{
+			`
+		i = 10
+		until i == 0 do
+		  i -= 1
+		end
+		i
+		`, 0},
+		{
+			`
+		a = [1, 2, 3, 4, 5]
+		i = 0
+		until i >= a.length
+			a[i] += 1
+			i += 1
+		end
+		a[4]
+		`, 6},
+		{
+			`
+		i = 0
+		sum = 0
+		until i == 10
+		  i += 1
+		  next if i.odd?
+		  break if i > 6
+		  sum += i
+		end
+		sum
+		`, 12},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestNextStatement(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -397,6 +558,38 @@ end
 x + y
 		`, 19},
 		{`
+result = [1, 2, 3].map do |x|
+  next 0 if x == 2
+  x * 10
+end
+
+result.to_s
+		`, "[10, 0, 30]"},
+		{`
+sum = 0
+[1, 2, 3].each do |x|
+  i = 0
+  while i < 3 do
+    i += 1
+    next if i == 2
+    sum += i
+  end
+  next if x == 2
+  sum += 100
+end
+
+sum
+		`, 212},
+		{`
+def twice
+  yield + yield
+end
+
+twice do
+  next 5
+end
+		`, 10},
+		{`
 x = 0
 y = 0
 i = 0
@@ -427,6 +620,112 @@ i
 	}
 }
 
+func TestLoopMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+i = 0
+loop do
+  i += 1
+  break if i == 5
+end
+
+i
+		`, 5},
+		{`
+i = 0
+result = loop do
+  i += 1
+  next if i < 3
+  break i * 10
+end
+
+result
+		`, 30},
+		{`
+loop do
+  break
+end
+		`, nil},
+		{`
+i = 0
+begin
+  loop do
+    i += 1
+    raise ArgumentError, "stop" if i == 3
+  end
+rescue ArgumentError
+  i * 10
+end
+		`, 30},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestLoopMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`loop`, "InternalError: Can't yield without a block", 1},
+		{`loop(1) do; end`, "ArgumentError: Expect 0 argument. got=1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+	}
+}
+
+func TestRedoStatement(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+count = 0
+result = [1, 2].map do |x|
+  count += 1
+  redo if count == 1
+  x * count
+end
+
+result.to_s
+		`, "[2, 6]"},
+		{`
+i = 0
+retried = false
+sum = 0
+while i < 3 do
+  i += 1
+  sum += i
+  if i == 2 && !retried
+    retried = true
+    redo
+  end
+end
+
+sum
+		`, 6},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestBreakStatement(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -467,6 +766,61 @@ end
 a = i * 10
 a + 100
 		`, 310},
+		{`
+i = 0
+while i < 5 do
+  i += 1
+  break i * 100 if i == 2
+end
+
+i
+		`, 2},
+		{`
+result = [1, 2, 3, 4].each do |x|
+  break x * 10 if x == 3
+end
+
+result
+		`, 30},
+		{`
+result = [1, 2].each do |x|
+  break
+end
+
+result
+		`, nil},
+		{`
+def foo
+  [1, 2, 3].each do |x|
+    yield(x)
+  end
+
+  "finished"
+end
+
+foo do |x|
+  break x * 100 if x == 2
+end
+		`, 200},
+		{`
+r = begin
+  [1, 2].each do |x|
+    begin
+      raise "error" if x == 2
+    rescue => e
+      break e.message
+    end
+  end
+rescue => e
+  "outer"
+end
+
+r
+		`, "error"},
+		{`
+double = ->(x) { break x * 2 }
+double.call(4)
+		`, 8},
 	}
 
 	for i, tt := range tests {
@@ -1,7 +1,11 @@
 package vm
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
 	"fmt"
+	"math/big"
 	"regexp"
 	"strconv"
 	"strings"
@@ -28,11 +32,16 @@ import (
 // **Note:**
 //
 // - Currently, manipulations are based upon Golang's Unicode manipulations.
-// - Currently, UTF-8 encoding is assumed based upon Golang's string manipulation, but the encoding is not actually specified(TBD).
+// - Lengths and indexes are counted in characters, use `#bytesize` and `#bytes` to work with bytes.
+// - Strings are encoded in UTF-8 by default, `#force_encoding` only changes the label of the bytes and manipulations still treat them as UTF-8.
+// - `<<`, `[]=`, `clear`, `concat`, `insert`, `replace` and the methods ending with `!` modify the string in place, other methods return a new String.
+// - String includes Comparable, so `<`, `<=`, `>` and `>=` are based on `#<=>`.
 // - `String.new` is not supported.
 type StringObject struct {
 	*baseObj
 	value string
+	// encoding is nil for the default UTF-8 encoding
+	encoding *EncodingObject
 }
 
 // Class methods --------------------------------------------------------
@@ -53,13 +62,13 @@ func builtinStringClassMethods() []*BuiltinMethodObject {
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) < 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got=%v", strconv.Itoa(len(args)))
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
 					formatObj, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
 					}
 
 					format := formatObj.value
@@ -73,7 +82,7 @@ func builtinStringClassMethods() []*BuiltinMethodObject {
 					count := len(re.FindAllString(format, -1))
 
 					if len(args[1:]) != count {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect %d string arguments. got=%d", count, len(args[1:]))
+						return t.initErrorObject(errors.ArgumentError, "Expect %d string arguments. got=%d", count, len(args[1:]))
 					}
 
 					return t.vm.initStringObject(fmt.Sprintf(format, arguments...))
@@ -112,7 +121,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					right, ok := r.(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
 					}
 
 					rightValue := right.value
@@ -121,33 +130,34 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns self multiplying another Integer
+			// Returns a string formatted with given argument, or with the elements if the argument is an Array.
+			// See Kernel#sprintf for the supported format directives.
 			//
 			// ```ruby
-			// "string " * 2 # => "string string string "
+			// "%05d" % 42                 # => "00042"
+			// "%s is %d years old" % ["Goby", 1] # => "Goby is 1 years old"
+			// "%-6s|" % "ab"              # => "ab    |"
 			// ```
 			//
 			// @return [String]
-			Name: "*",
+			Name: "%",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-
-					leftValue := receiver.(*StringObject).value
-					r := args[0]
-					right, ok := r.(*IntegerObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, r.Class().Name)
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
 					}
 
-					if right.value < 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Second argument must be greater than or equal to 0. got=%v", right.value)
+					format := receiver.(*StringObject).value
+					formatArgs := []Object{args[0]}
+
+					if arr, ok := args[0].(*ArrayObject); ok {
+						formatArgs = arr.Elements
 					}
 
-					var result string
+					result, err := sprintf(t, format, formatArgs)
 
-					for i := 0; i < right.value; i++ {
-						result += leftValue
+					if err != nil {
+						return err
 					}
 
 					return t.vm.initStringObject(result)
@@ -155,62 +165,70 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns a Boolean if first string greater than second string
+			// Returns self multiplying another Integer
 			//
 			// ```ruby
-			// "a" < "b" # => true
+			// "string " * 2 # => "string string string "
 			// ```
 			//
-			// @return [Boolean]
-			Name: ">",
+			// @return [String]
+			Name: "*",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
 					leftValue := receiver.(*StringObject).value
 					r := args[0]
-					right, ok := r.(*StringObject)
+					right, ok := r.(*IntegerObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, r.Class().Name)
 					}
 
-					rightValue := right.value
+					if right.value < 0 {
+						return t.initErrorObject(errors.ArgumentError, "Second argument must be greater than or equal to 0. got=%v", right.value)
+					}
 
-					if leftValue > rightValue {
-						return TRUE
+					var result string
+
+					for i := 0; i < right.value; i++ {
+						result += leftValue
 					}
 
-					return FALSE
+					return t.vm.initStringObject(result)
 				}
 			},
 		},
 		{
-			// Returns a Boolean if first string less than second string
+			// Appends given string to the receiver in place and returns the receiver
 			//
 			// ```ruby
-			// "a" < "b" # => true
+			// s = "Hello"
+			// s << " " << "Goby" # => "Hello Goby"
+			// s                  # => "Hello Goby"
 			// ```
 			//
-			// @return [Boolean]
-			Name: "<",
+			// @return [String]
+			Name: "<<",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
 
-					leftValue := receiver.(*StringObject).value
-					r := args[0]
-					right, ok := r.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
 					}
 
-					rightValue := right.value
+					s := receiver.(*StringObject)
+					a := args[0]
+					appendStr, ok := a.(*StringObject)
 
-					if leftValue < rightValue {
-						return TRUE
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, a.Class().Name)
 					}
 
-					return FALSE
+					s.value += appendStr.value
+					return s
 				}
 			},
 		},
@@ -246,8 +264,9 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns a Integer. If first string is less than second string returns -1, if equal to returns 0, if greater returns 1
-			//
+			// Returns a Integer. If first string is less than second string returns -1, if equal to returns 0, if greater returns 1.
+			// Strings are compared by their characters' code points. The other comparison operators like `<` and `>=` are
+			// provided by Comparable based on this method.
 			//
 			// ```ruby
 			// "abc" <=> "abcd" # => -1
@@ -265,7 +284,7 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					right, ok := r.(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
 					}
 
 					rightValue := right.value
@@ -312,8 +331,44 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns the character of the string with specified index
-			// It will raise error if the input is not an Integer type
+			// Matches the receiver against given Regexp, returns the character index of the first match
+			// or nil if there's no match
+			//
+			// ```ruby
+			// "Goby" =~ /by/     # => 2
+			// "Goby" =~ /foo/    # => nil
+			// "你好Goby" =~ /Go/ # => 2
+			// ```
+			//
+			// @return [Integer]
+			Name: "=~",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					str := receiver.(*StringObject).value
+					r := args[0]
+					re, ok := r.(*RegexpObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.RegexpClass, r.Class().Name)
+					}
+
+					return re.matchIndex(t, str)
+				}
+			},
+		},
+		{
+			// Returns the substring specified by the arguments, which can be:
+			//
+			// - An Integer index, returns the character at the index.
+			// - An Integer start index and a length, returns the substring of the length from the index.
+			// - A Range, returns the substring within the range.
+			// - A Regexp, returns the matched portion. An optional Integer specifies the capture group to return.
+			//
+			// Negative indexes count from the end of the string. Returns nil if the index is out of range or nothing matches.
 			//
 			// ```ruby
 			// "Hello"[1]        # => "e"
@@ -323,43 +378,36 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			// "Hello"[-6]       # => nil
 			// "Hello😊"[5]      # => "😊"
 			// "Hello😊"[-1]     # => "😊"
+			// "Hello"[1, 3]     # => "ell"
+			// "Hello"[-3, 2]    # => "ll"
+			// "Hello"[1..3]     # => "ell"
+			// "Hello"[1..-1]    # => "ello"
+			// "Hello"[/l+o/]    # => "llo"
+			// "Hello"[/(H)(e)/, 2] # => "e"
+			// "Hello"[/x/]      # => nil
 			// ```
 			//
 			// @return [String]
 			Name: "[]",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
-					}
-
 					str := receiver.(*StringObject).value
-					i := args[0]
-					index, ok := i.(*IntegerObject)
+					start, end, err := substringRange(t, str, args)
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
+					if err != nil {
+						return err
 					}
 
-					indexValue := index.value
-
-					if indexValue < 0 {
-						strLength := utf8.RuneCountInString(str)
-						if -indexValue > strLength {
-							return NULL
-						}
-						return t.vm.initStringObject(string([]rune(str)[strLength+indexValue]))
+					if start < 0 {
+						return NULL
 					}
 
-					if len(str) > indexValue {
-						return t.vm.initStringObject(string([]rune(str)[indexValue]))
-					}
-					return NULL
+					return t.vm.initStringObject(string([]rune(str)[start:end]))
 				}
 			},
 		},
 		{
-			// Replace character of the string with input string
+			// Replace character of the string with input string in place
 			// It will raise error if the index is not Integer type or the index value is out of
 			// range of the string length
 			//
@@ -378,8 +426,12 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			Name: "[]=",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
 					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%v", strconv.Itoa(len(args)))
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%v", strconv.Itoa(len(args)))
 					}
 
 					str := receiver.(*StringObject).value
@@ -387,539 +439,475 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 					index, ok := i.(*IntegerObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
 					}
 
 					indexValue := index.value
 					strLength := utf8.RuneCountInString(str)
 
 					if strLength < indexValue {
-						return t.vm.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", strconv.Itoa(indexValue))
+						return t.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", strconv.Itoa(indexValue))
 					}
 
 					r := args[1]
 					replaceStr, ok := r.(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
 					}
 					replaceStrValue := replaceStr.value
 
 					// Negative Index Case
 					if indexValue < 0 {
 						if -indexValue > strLength {
-							return t.vm.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", strconv.Itoa(indexValue))
+							return t.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", strconv.Itoa(indexValue))
 						}
 						// Change to positive index to replace the string
 						indexValue += strLength
 					}
 
+					s := receiver.(*StringObject)
+
 					if strLength == indexValue {
-						return t.vm.initStringObject(str + replaceStrValue)
+						s.value = str + replaceStrValue
+						return s
 					}
 					// Using rune type to support UTF-8 encoding to replace character
-					result := string([]rune(str)[:indexValue]) + replaceStrValue + string([]rune(str)[indexValue+1:])
-					return t.vm.initStringObject(result)
+					s.value = string([]rune(str)[:indexValue]) + replaceStrValue + string([]rune(str)[indexValue+1:])
+					return s
 				}
 			},
 		},
 		{
-			// Return a new String with the first character converted to uppercase but the rest of string converted to lowercase.
+			// Returns the string decoded from the Base64 encoded receiver. Newlines in the receiver are ignored.
+			// It will raise an error if the receiver isn't valid Base64.
 			//
 			// ```ruby
-			// "test".capitalize         # => "Test"
-			// "tEST".capitalize         # => "Test"
-			// "heLlo\nWoRLd".capitalize # => "Hello\nworld"
-			// "😊HeLlO🐟".capitalize    # => "😊hello🐟"
+			// "R29ieQ==".b64decode # => "Goby"
+			// "8J+Ziw==".b64decode # => "🙋"
 			// ```
 			//
 			// @return [String]
-			Name: "capitalize",
+			Name: "b64decode",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
 
-					str := receiver.(*StringObject).value
-					start := string([]rune(str)[0])
-					rest := string([]rune(str)[1:])
-					result := strings.ToUpper(start) + strings.ToLower(rest)
+					str := strings.Replace(receiver.(*StringObject).value, "\n", "", -1)
+					decoded, err := base64.StdEncoding.DecodeString(str)
 
-					return t.vm.initStringObject(result)
+					if err != nil {
+						return t.initErrorObject(errors.ArgumentError, "Invalid Base64 string: %s", err.Error())
+					}
+
+					return t.vm.initStringObject(string(decoded))
 				}
 			},
 		},
 		{
-			// Returns a string with the last character chopped
+			// Returns the Base64 encoding of the receiver's bytes
 			//
 			// ```ruby
-			// "Hello".chop         # => "Hell"
-			// "Hello World\n".chop # => "Hello World"
-			// "Hello😊".chop       # => "Hello"
+			// "Goby".b64encode # => "R29ieQ=="
+			// "🙋".b64encode   # => "8J+Ziw=="
 			// ```
 			//
 			// @return [String]
-			Name: "chop",
+			Name: "b64encode",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
 
-					str := receiver.(*StringObject).value
-					strLength := utf8.RuneCountInString(str)
-
-					// Support UTF-8 Encoding
-					return t.vm.initStringObject(string([]rune(str)[:strLength-1]))
+					return t.vm.initStringObject(base64.StdEncoding.EncodeToString([]byte(receiver.(*StringObject).value)))
 				}
 			},
 		},
 		{
-			// Returns a string which is concatenate with the input string or character
+			// Returns an array of the bytes in the string. If a block is given, yields each byte and returns self.
 			//
 			// ```ruby
-			// "Hello ".concat("World")   # => "Hello World"
-			// "Hello World".concat("😊") # => "Hello World😊"
+			// "Goby".bytes # => [71, 111, 98, 121]
+			// "😊".bytes   # => [240, 159, 152, 138]
 			// ```
 			//
-			// @return [String]
-			Name: "concat",
+			// @return [Array]
+			Name: "bytes",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
 					str := receiver.(*StringObject).value
-					c := args[0]
-					concatStr, ok := c.(*StringObject)
+					elems := []Object{}
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
+					for _, b := range []byte(str) {
+						elems = append(elems, t.vm.initIntegerObject(int(b)))
 					}
 
-					return t.vm.initStringObject(str + concatStr.value)
+					return yieldOrCollect(t, receiver, elems, blockFrame)
 				}
 			},
 		},
 		{
-			// Returns the integer that count the string chars as UTF-8
+			// Returns the length of self in bytes
 			//
 			// ```ruby
-			// "abcde".count          # => 5
-			// "哈囉！世界！".count     # => 6
-			// "Hello\nWorld".count   # => 11
-			// "Hello\nWorld😊".count # => 12
+			// "Goby".bytesize # => 4
+			// "😊".bytesize   # => 4
+			// "😊".length     # => 1
 			// ```
 			//
 			// @return [Integer]
-			Name: "count",
+			Name: "bytesize",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-
-					str := receiver.(*StringObject).value
-
-					// Support UTF-8 Encoding
-					return t.vm.initIntegerObject(utf8.RuneCountInString(str))
+					return t.vm.initIntegerObject(len(receiver.(*StringObject).value))
 				}
 			},
 		},
 		{
-			// Returns a string which is being partially deleted with specified values
+			// Return a new String with the first character converted to uppercase but the rest of string converted to lowercase.
 			//
 			// ```ruby
-			// "Hello hello HeLlo".delete("el")        # => "Hlo hlo HeLlo"
-			// "Hello 😊 Hello 😊 Hello".delete("😊") # => "Hello  Hello  Hello"
-			// # TODO: Handle delete intersection of multiple strings' input case
-			// "Hello hello HeLlo".delete("el", "e") # => "Hllo hllo HLlo"
+			// "test".capitalize         # => "Test"
+			// "tEST".capitalize         # => "Test"
+			// "heLlo\nWoRLd".capitalize # => "Hello\nworld"
+			// "😊HeLlO🐟".capitalize    # => "😊hello🐟"
 			// ```
 			//
 			// @return [String]
-			Name: "delete",
+			Name: "capitalize",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
-					}
 
 					str := receiver.(*StringObject).value
-					d := args[0]
-					deleteStr, ok := d.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, d.Class().Name)
-					}
 
-					return t.vm.initStringObject(strings.Replace(str, deleteStr.value, "", -1))
+					return t.vm.initStringObject(capitalizeString(str))
 				}
 			},
 		},
 		{
-			// Returns a new String with all characters is lowercase
+			// Performs String#capitalize in place, returns the receiver, or nil if no changes were made.
 			//
 			// ```ruby
-			// "erROR".downcase        # => "error"
-			// "HeLlO\tWorLD".downcase # => "hello\tworld"
+			// s = "goby"
+			// s.capitalize! # => "Goby"
+			// s             # => "Goby"
+			// s.capitalize! # => nil
 			// ```
 			//
 			// @return [String]
-			Name: "downcase",
+			Name: "capitalize!",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
 
-					str := receiver.(*StringObject).value
-
-					return t.vm.initStringObject(strings.ToLower(str))
+					return receiver.(*StringObject).mutate(capitalizeString(receiver.(*StringObject).value))
 				}
 			},
 		},
 		{
-			// Split and loop through the string byte
+			// Compares the receiver with the argument string case-insensitively, returns -1, 0 or 1.
+			// Only ASCII characters are case folded, use `#casecmp?` for Unicode case folding.
 			//
 			// ```ruby
-			// "Sushi 🍣".each_byte do |byte|
-			//   puts byte
-			// end
-			// # => 83  # "S"
-			// # => 117 # "u"
-			// # => 115 # "s"
-			// # => 104 # "h"
-			// # => 105 # "i"
-			// # => 32  # " "
-			// # => 240 # "\xF0"
-			// # => 159 # "\x9F"
-			// # => 141 # "\x8D"
-			// # => 163 # "\xA3"
+			// "goby".casecmp("GOBY") # => 0
+			// "a".casecmp("B")       # => -1
+			// "b".casecmp("A")       # => 1
 			// ```
 			//
-			// @return [String]
-			Name: "each_byte",
+			// @return [Integer]
+			Name: "casecmp",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
-					}
-
-					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
 					}
 
-					str := receiver.(*StringObject).value
+					c := args[0]
+					right, ok := c.(*StringObject)
 
-					for _, byte := range []byte(str) {
-						t.builtinMethodYield(blockFrame, t.vm.initIntegerObject(int(byte)))
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
 					}
 
-					return t.vm.initStringObject(str)
+					left := asciiLower(receiver.(*StringObject).value)
+					return t.vm.initIntegerObject(strings.Compare(left, asciiLower(right.value)))
 				}
 			},
 		},
 		{
-			// Split and loop through the string characters
+			// Returns true if the receiver and the argument string are equal after Unicode case folding
 			//
 			// ```ruby
-			// "Sushi 🍣".each_char do |char|
-			//   puts char
-			// end
-			// # => "S"
-			// # => "u"
-			// # => "s"
-			// # => "h"
-			// # => "i"
-			// # => " "
-			// # => "🍣"
+			// "goby".casecmp?("GOBY")   # => true
+			// "ÄÖÜ".casecmp?("äöü")     # => true
+			// "goby".casecmp?("ruby")   # => false
 			// ```
 			//
-			// @return [String]
-			Name: "each_char",
+			// @return [Boolean]
+			Name: "casecmp?",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
 					}
 
-					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
-					}
+					c := args[0]
+					right, ok := c.(*StringObject)
 
-					str := receiver.(*StringObject).value
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
+					}
 
-					for _, char := range []rune(str) {
-						t.builtinMethodYield(blockFrame, t.vm.initStringObject(string(char)))
+					if strings.EqualFold(receiver.(*StringObject).value, right.value) {
+						return TRUE
 					}
 
-					return t.vm.initStringObject(str)
+					return FALSE
 				}
 			},
 		},
 		{
-			// Split and loop through the string segment split by the newline escaped character
+			// If input integer is greater than the length of receiver string, returns a new String of
+			// length integer with receiver string centered and padded with default " "; otherwise,
+			// returns receiver string. When the padding can't be split evenly, the right side gets one more character.
+			//
+			// It will raise error if the input string length is not integer type
 			//
 			// ```ruby
-			// "Hello\nWorld\nGoby".each_line do |line|
-			//   puts line
-			// end
-			// # => "Hello"
-			// # => "World"
-			// # => "Goby"
+			// "Hello".center(2)           # => "Hello"
+			// "Hello".center(9)           # => "  Hello  "
+			// "Hello".center(10, "xo")    # => "xoHelloxox"
+			// "Hello".center(10, "😊🐟") # => "😊🐟Hello😊🐟😊"
 			// ```
 			//
 			// @return [String]
-			Name: "each_line",
+			Name: "center",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 0 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
-					}
+					str := receiver.(*StringObject).value
+					width, pad, err := parseJustifyArgs(t, args)
 
-					if blockFrame == nil {
-						return t.vm.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					if err != nil {
+						return err
 					}
 
-					str := receiver.(*StringObject).value
-					lineArray := strings.Split(str, "\n")
+					n := width - utf8.RuneCountInString(str)
 
-					for _, line := range lineArray {
-						t.builtinMethodYield(blockFrame, t.vm.initStringObject(line))
+					if n <= 0 {
+						return t.vm.initStringObject(str)
 					}
 
-					return t.vm.initStringObject(str)
+					left := n / 2
+					return t.vm.initStringObject(padding(pad, left) + str + padding(pad, n-left))
 				}
 			},
 		},
 		{
-			// Returns true if string is empty value
+			// Returns an array of the characters in the string. If a block is given, yields each character and returns self.
 			//
 			// ```ruby
-			// "".empty?      # => true
-			// "Hello".empty? # => false
+			// "Goby".chars   # => ["G", "o", "b", "y"]
+			// "哈囉🍣".chars # => ["哈", "囉", "🍣"]
 			// ```
 			//
-			// @return [Boolean]
-			Name: "empty?",
+			// @return [Array]
+			Name: "chars",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
 
 					str := receiver.(*StringObject).value
+					elems := []Object{}
 
-					if str == "" {
-						return TRUE
+					for _, char := range str {
+						elems = append(elems, t.vm.initStringObject(string(char)))
 					}
-					return FALSE
+
+					return yieldOrCollect(t, receiver, elems, blockFrame)
 				}
 			},
 		},
 		{
-			// Returns true if receiver string end with the argument string
+			// Returns a string with the given separator removed from the end. If the separator is omitted,
+			// a trailing "\r\n", "\n" or "\r" is removed. If the separator is an empty string, all trailing
+			// "\r\n" and "\n" are removed.
 			//
 			// ```ruby
-			// "Hello".end_with?("llo")     # => true
-			// "Hello".end_with?("ell")     # => false
-			// "😊Hello🐟".end_with?("🐟") # => true
-			// "😊Hello🐟".end_with?("😊") # => false
+			// "Hello\n".chomp       # => "Hello"
+			// "Hello\r\n".chomp     # => "Hello"
+			// "Hello\n\n".chomp     # => "Hello\n"
+			// "Hello\n\n".chomp("")  # => "Hello"
+			// "Hello".chomp("llo")  # => "He"
+			// "Hello".chomp         # => "Hello"
 			// ```
 			//
-			// @return [Boolean]
-			Name: "end_with?",
+			// @return [String]
+			Name: "chomp",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
-					}
-
-					str := receiver.(*StringObject).value
-					c := args[0]
-					compareStr, ok := c.(*StringObject)
+					str, err := chompString(t, receiver.(*StringObject).value, args)
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
-					}
-
-					compareStrValue := compareStr.value
-					compareStrLength := utf8.RuneCountInString(compareStrValue)
-					strLength := utf8.RuneCountInString(str)
-
-					if compareStrLength > strLength {
-						return FALSE
+					if err != nil {
+						return err
 					}
 
-					if compareStrValue == string([]rune(str)[strLength-compareStrLength:]) {
-						return TRUE
-					}
-					return FALSE
+					return t.vm.initStringObject(str)
 				}
 			},
 		},
 		{
-			// Returns true if receiver string is equal to argument string
+			// Performs String#chomp in place, returns the receiver, or nil if no changes were made.
 			//
 			// ```ruby
-			// "Hello".eql?("Hello")     # => true
-			// "Hello".eql?("World")     # => false
-			// "Hello😊".eql?("Hello😊") # => true
+			// s = "Goby\n"
+			// s.chomp! # => "Goby"
+			// s        # => "Goby"
+			// s.chomp! # => nil
 			// ```
 			//
-			// @return [Boolean]
-			Name: "eql?",
+			// @return [String]
+			Name: "chomp!",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					str := receiver.(*StringObject).value
-					compareStr, ok := args[0].(*StringObject)
+					str, err := chompString(t, receiver.(*StringObject).value, args)
 
-					if !ok {
-						return FALSE
-					} else if compareStr.value == str {
-						return TRUE
+					if err != nil {
+						return err
 					}
-					return FALSE
+
+					return receiver.(*StringObject).mutate(str)
 				}
 			},
 		},
 		{
-			// TODO: Implement String#gsub When RegexObject Implemented
-			// Returns a copy of str with the all occurrences of pattern substituted for the second argument.
-			// The pattern is typically a String or Regexp (Not implemented yet); if given as a String, any
-			// regular expression metacharacters it contains will be interpreted literally, e.g. '\\d' will
-			// match a backslash followed by ‘d’, instead of a digit.
+			// Returns a string with the last character removed. If the string ends with "\r\n", both
+			// characters are removed.
+			//
+			// ```ruby
+			// "Hello".chop           # => "Hell"
+			// "Hello World\n".chop   # => "Hello World"
+			// "Hello World\r\n".chop # => "Hello World"
+			// "Hello😊".chop         # => "Hello"
+			// "".chop                # => ""
+			// ```
 			//
-			// Currently only support string version of String#gsub.
+			// @return [String]
+			Name: "chop",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(chopString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Performs String#chop in place, returns the receiver, or nil if the string is empty.
 			//
 			// ```ruby
-			// "Ruby Lang".gsub("Ru", "Go")                # => "Goby Lang"
-			// "Hello 😊 Hello 😊 Hello".gsub("😊", "🐟") # => "Hello 🐟 Hello 🐟 Hello"
+			// s = "Goby"
+			// s.chop! # => "Gob"
+			// s       # => "Gob"
+			// "".chop! # => nil
 			// ```
 			//
 			// @return [String]
-			Name: "gsub",
+			Name: "chop!",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%v", len(args))
-					}
-
-					str := receiver.(*StringObject).value
-
-					p := args[0]
-					pattern, ok := p.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect pattern to be String. got: %s", p.Class().Name)
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					r := args[1]
-					replacement, ok := r.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect replacement to be String. got: %s", r.Class().Name)
-					}
-
-					return t.vm.initStringObject(strings.Replace(str, pattern.value, replacement.value, -1))
+					return receiver.(*StringObject).mutate(chopString(receiver.(*StringObject).value))
 				}
 			},
 		},
 		{
-			// Checks if the specified string is included in the receiver
+			// Removes all characters of the string in place and returns the receiver
 			//
 			// ```ruby
-			// "Hello\nWorld".include?("\n")   # => true
-			// "Hello 😊 Hello".include?("😊") # => true
+			// s = "Goby"
+			// s.clear # => ""
+			// s       # => ""
 			// ```
 			//
-			// @return [Bool]
-			Name: "include?",
+			// @return [String]
+			Name: "clear",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
-					}
-
-					str := receiver.(*StringObject).value
-					i := args[0]
-					includeStr, ok := i.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, i.Class().Name)
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					if strings.Contains(str, includeStr.value) {
-						return TRUE
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
-					return FALSE
+					s := receiver.(*StringObject)
+					s.value = ""
+					return s
 				}
 			},
 		},
 		{
-			// Insert a string input in specified index value of the receiver string
-			//
-			// It will raise error if index value is not an integer or index value is out
-			// of receiver string's range
-			//
-			// It will also raise error if the input string value is not type string
+			// Appends the input string to the receiver in place and returns the receiver
 			//
 			// ```ruby
-			// "Hello".insert(0, "X") # => "XHello"
-			// "Hello".insert(2, "X") # => "HeXllo"
-			// "Hello".insert(5, "X") # => "HelloX"
-			// "Hello".insert(-1, "X") # => "HelloX"
-			// "Hello".insert(-3, "X") # => "HelXlo"
+			// "Hello ".concat("World")   # => "Hello World"
+			// "Hello World".concat("😊") # => "Hello World😊"
 			// ```
 			//
 			// @return [String]
-			Name: "insert",
+			Name: "concat",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					str := receiver.(*StringObject).value
-					i := args[0]
-					index, ok := i.(*IntegerObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
-					indexValue := index.value
-					ins := args[1]
-					insertStr, ok := ins.(*StringObject)
+					s := receiver.(*StringObject)
+					c := args[0]
+					concatStr, ok := c.(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect insert string to be String. got: %s", ins.Class().Name)
-					}
-					strLength := utf8.RuneCountInString(str)
-
-					if indexValue < 0 {
-						if -indexValue > strLength+1 {
-							return t.vm.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", indexValue)
-						} else if -indexValue == strLength+1 {
-							return t.vm.initStringObject(insertStr.value + str)
-						}
-						// Change it to positive index value to replace the string via index
-						indexValue += strLength
-					}
-
-					if strLength < indexValue {
-						return t.vm.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", indexValue)
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.initStringObject(string([]rune(str)[:indexValue]) + insertStr.value + string([]rune(str)[indexValue:]))
+					s.value += concatStr.value
+					return s
 				}
 			},
 		},
 		{
-			// Returns the character length of self
-			// **Note:** the length is currently byte-based, instead of charcode-based.
+			// Returns the integer that count the string chars as UTF-8
 			//
 			// ```ruby
-			// "zero".length # => 4
-			// "".length     # => 0
-			// "😊".length   # => 1
+			// "abcde".count          # => 5
+			// "哈囉！世界！".count     # => 6
+			// "Hello\nWorld".count   # => 11
+			// "Hello\nWorld😊".count # => 12
 			// ```
 			//
 			// @return [Integer]
-			Name: "length",
+			Name: "count",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
@@ -931,389 +919,293 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// If input integer is greater than the length of receiver string, returns a new String of
-			// length integer with receiver string left justified and padded with default " "; otherwise,
-			// returns receiver string.
-			//
-			// It will raise error if the input string length is not integer type
+			// Returns a copy of the string with all characters in the intersection of the given character sets deleted.
+			// A character set is described like String#tr's, e.g. "a-z" or "^aeiou".
 			//
 			// ```ruby
-			// "Hello".ljust(2)           # => "Hello"
-			// "Hello".ljust(7)           # => "Hello  "
-			// "Hello".ljust(10, "xo")    # => "Helloxoxox"
-			// "Hello".ljust(10, "😊🐟") # => "Hello😊🐟😊🐟😊"
+			// "Hello hello HeLlo".delete("el")        # => "Ho ho HLo"
+			// "Hello 😊 Hello 😊 Hello".delete("😊") # => "Hello  Hello  Hello"
+			// "Hello World".delete("a-z", "^o")       # => "Ho Wo"
 			// ```
 			//
 			// @return [String]
-			Name: "ljust",
+			Name: "delete",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 && len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%v", strconv.Itoa(len(args)))
-					}
-
-					str := receiver.(*StringObject).value
-
-					l := args[0]
-					strLength, ok := l.(*IntegerObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect justify width to be Integer. got: %s", l.Class().Name)
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got=%d", len(args))
 					}
 
-					strLengthValue := strLength.value
-
-					var padStrValue string
-					if len(args) == 1 {
-						padStrValue = " "
-					} else {
-						p := args[1]
-						padStr, ok := p.(*StringObject)
+					sets, err := parseCharSets(t, args)
 
-						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, "Expect padding string to be String. got: %s", p.Class().Name)
-						}
-
-						padStrValue = padStr.value
+					if err != nil {
+						return err
 					}
 
-					currentStrLength := utf8.RuneCountInString(str)
-					padStrLength := utf8.RuneCountInString(padStrValue)
+					var result bytes.Buffer
 
-					if strLengthValue > currentStrLength {
-						for i := currentStrLength; i < strLengthValue; i += padStrLength {
-							str += padStrValue
+					for _, r := range receiver.(*StringObject).value {
+						if !sets.include(r) {
+							result.WriteRune(r)
 						}
-						str = string([]rune(str)[:strLengthValue])
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.initStringObject(str)
+					return t.vm.initStringObject(result.String())
 				}
 			},
 		},
 		{
-			// Return a string replaced by the input string
+			// Returns a new String with all characters is lowercase
 			//
 			// ```ruby
-			// "Hello".replace("World")          # => "World"
-			// "你好"replace("再見")              # => "再見"
-			// "Ruby\nLang".replace("Goby\nLang") # => "Goby Lang"
-			// "Hello😊".replace("World🐟")      # => "World🐟"
+			// "erROR".downcase        # => "error"
+			// "HeLlO\tWorLD".downcase # => "hello\tworld"
 			// ```
 			//
 			// @return [String]
-			Name: "replace",
+			Name: "downcase",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
-					}
 
-					r := args[0]
-					replaceStr, ok := r.(*StringObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
-					}
+					str := receiver.(*StringObject).value
 
-					return t.vm.initStringObject(replaceStr.value)
+					return t.vm.initStringObject(strings.ToLower(str))
 				}
 			},
 		},
 		{
-			// Returns a new String with reverse order of self
-			// **Note:** the length is currently byte-based, instead of charcode-based.
+			// Performs String#downcase in place, returns the receiver, or nil if no changes were made.
 			//
 			// ```ruby
-			// "reverse".reverse           # => "esrever"
-			// "Hello\nWorld".reverse      # => "dlroW\nolleH"
-			// "Hello 😊🐟 World".reverse # => "dlroW 🐟😊 olleH"
+			// s = "GOBY"
+			// s.downcase! # => "goby"
+			// s           # => "goby"
+			// s.downcase! # => nil
 			// ```
 			//
 			// @return [String]
-			Name: "reverse",
+			Name: "downcase!",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-
-					str := receiver.(*StringObject).value
-
-					var revert string
-					for i := utf8.RuneCountInString(str) - 1; i >= 0; i-- {
-						revert += string([]rune(str)[i])
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					// Support UTF-8 Encoding
-					return t.vm.initStringObject(revert)
+					return receiver.(*StringObject).mutate(strings.ToLower(receiver.(*StringObject).value))
 				}
 			},
 		},
 		{
-			// If input integer is greater than the length of receiver string, returns a new String of
-			// length integer with receiver string right justified and padded with default " "; otherwise,
-			// returns receiver string.
-			//
-			// It will raise error if the input string length is not integer type
+			// Split and loop through the string byte
 			//
 			// ```ruby
-			// "Hello".rjust(2)          # => "Hello"
-			// "Hello".rjust(7)          # => "  Hello"
-			// "Hello".rjust(10, "xo")   # => "xoxoxHello"
-			// "Hello".rjust(10, "😊🐟") # => "😊🐟😊🐟😊Hello"
+			// "Sushi 🍣".each_byte do |byte|
+			//   puts byte
+			// end
+			// # => 83  # "S"
+			// # => 117 # "u"
+			// # => 115 # "s"
+			// # => 104 # "h"
+			// # => 105 # "i"
+			// # => 32  # " "
+			// # => 240 # "\xF0"
+			// # => 159 # "\x9F"
+			// # => 141 # "\x8D"
+			// # => 163 # "\xA3"
 			// ```
 			//
 			// @return [String]
-			Name: "rjust",
+			Name: "each_byte",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 && len(args) != 2 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%v", strconv.Itoa(len(args)))
-					}
-
-					str := receiver.(*StringObject).value
-					l := args[0]
-					strLength, ok := l.(*IntegerObject)
-
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, "Expect justify width to be Integer. got: %s", l.Class().Name)
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
 					}
 
-					strLengthValue := strLength.value
-
-					var padStrValue string
-					if len(args) == 1 {
-						padStrValue = " "
-					} else {
-						p := args[1]
-						padStr, ok := p.(*StringObject)
-
-						if !ok {
-							return t.vm.initErrorObject(errors.TypeError, "Expect padding string to be String. got: %s", p.Class().Name)
-						}
-
-						padStrValue = padStr.value
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_byte", args)
 					}
 
-					padStrLength := utf8.RuneCountInString(padStrValue)
+					str := receiver.(*StringObject).value
 
-					if strLengthValue > len(str) {
-						origin := str
-						originStrLength := utf8.RuneCountInString(origin)
-						for i := originStrLength; i < strLengthValue; i += padStrLength {
-							str = padStrValue + str
-						}
-						currentStrLength := utf8.RuneCountInString(str)
-						if currentStrLength > strLengthValue {
-							chopLength := currentStrLength - strLengthValue
-							str = string([]rune(str)[:currentStrLength-originStrLength-chopLength]) + origin
-						}
+					for _, byte := range []byte(str) {
+						t.builtinMethodYield(blockFrame, t.vm.initIntegerObject(int(byte)))
 					}
 
-					// Support UTF-8 Encoding
 					return t.vm.initStringObject(str)
 				}
 			},
 		},
 		{
-			// Returns the character length of self
-			// **Note:** the length is currently byte-based, instead of charcode-based.
+			// Split and loop through the string characters
 			//
 			// ```ruby
-			// "zero".size  # => 4
-			// "".size      # => 0
-			// "😊".size   # => 1
+			// "Sushi 🍣".each_char do |char|
+			//   puts char
+			// end
+			// # => "S"
+			// # => "u"
+			// # => "s"
+			// # => "h"
+			// # => "i"
+			// # => " "
+			// # => "🍣"
 			// ```
 			//
-			// @return [Integer]
-			Name: "size",
+			// @return [String]
+			Name: "each_char",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_char", args)
+					}
 
 					str := receiver.(*StringObject).value
 
-					// Support UTF-8 Encoding
-					return t.vm.initIntegerObject(utf8.RuneCountInString(str))
+					for _, char := range []rune(str) {
+						t.builtinMethodYield(blockFrame, t.vm.initStringObject(string(char)))
+					}
+
+					return t.vm.initStringObject(str)
 				}
 			},
 		},
 		{
-			// Returns a string sliced according to the input range
+			// Loops through each line of the string. Like `#lines`, each line keeps its trailing newline character.
 			//
 			// ```ruby
-			// "Hello World".slice(1..6)    # => "ello W"
-			// "1234567890".slice(6..1)     # => ""
-			// "1234567890".slice(11..1)    # => nil
-			// "1234567890".slice(11..-1)   # => nil
-			// "1234567890".slice(-10..1)   # => "12"
-			// "1234567890".slice(-5..1)    # => ""
-			// "1234567890".slice(-10..-1)  # => "1234567890"
-			// "1234567890".slice(-10..-11) # => ""
-			// "1234567890".slice(1..-1)    # => "234567890"
-			// "1234567890".slice(1..-1234) # => ""
-			// "1234567890".slice(-11..5)   # => nil
-			// "1234567890".slice(-10..-5)  # => "123456"
-			// "1234567890".slice(-5..-10)  # => ""
-			// "1234567890".slice(-11..-12) # => nil
-			// "1234567890".slice(-10..-12) # => ""
-			// "Hello 😊🐟 World".slice(1..6)    # => "ello 😊"
-			// "Hello 😊🐟 World".slice(-10..7)  # => "o 😊🐟"
-			// "Hello 😊🐟 World".slice(1..-1)   # => "ello 😊🐟 World"
-			// "Hello 😊🐟 World".slice(-12..-5) # => "llo 😊🐟 W"
-			// "Hello World".slice(4)       # => "o"
-			// "Hello\nWorld".slice(6)      # => "\n"
-			// "Hello World".slice(-3)      # => "r"
-			// "Hello World".slice(-11)     # => "H"
-			// "Hello World".slice(-12)     # => nil
-			// "Hello World".slice(11)      # => nil
-			// "Hello World".slice(4)       # => "o"
-			// "Hello 😊🐟 World".slice(6)      # => "😊"
-			// "Hello 😊🐟 World".slice(-7)      # => "🐟"
-			// "Hello 😊🐟 World".slice(-10)     # => "o"
-			// "Hello 😊🐟 World".slice(-15)     # => nil
-			// "Hello 😊🐟 World".slice(14)      # => nil
+			// "Hello\nWorld\nGoby".each_line do |line|
+			//   puts line
+			// end
+			// # => "Hello\n"
+			// # => "World\n"
+			// # => "Goby"
 			// ```
 			//
 			// @return [String]
-			Name: "slice",
+			Name: "each_line",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.vm.initEnumeratorObject(receiver, "each_line", args)
 					}
 
 					str := receiver.(*StringObject).value
-					strLength := utf8.RuneCountInString(str)
 
-					// All Case Support UTF-8 Encoding
-					switch args[0].(type) {
-					case *RangeObject:
-						ran := args[0].(*RangeObject)
-						switch {
-						case ran.Start >= 0 && ran.End >= 0:
-							if ran.Start > strLength {
-								return NULL
-							} else if ran.Start > ran.End {
-								return t.vm.initStringObject("")
-							}
-							return t.vm.initStringObject(string([]rune(str)[ran.Start : ran.End+1]))
-						case ran.Start < 0 && ran.End >= 0:
-							positiveStart := strLength + ran.Start
-							if -ran.Start > strLength {
-								return NULL
-							} else if positiveStart > ran.End {
-								return t.vm.initStringObject("")
-							}
-							return t.vm.initStringObject(string([]rune(str)[positiveStart : ran.End+1]))
-						case ran.Start >= 0 && ran.End < 0:
-							positiveEnd := strLength + ran.End
-							if ran.Start > strLength {
-								return NULL
-							} else if positiveEnd < 0 || ran.Start > positiveEnd {
-								return t.vm.initStringObject("")
-							}
-							return t.vm.initStringObject(string([]rune(str)[ran.Start : positiveEnd+1]))
-						default:
-							positiveStart := strLength + ran.Start
-							positiveEnd := strLength + ran.End
-							if positiveStart < 0 {
-								return NULL
-							} else if positiveStart > positiveEnd {
-								return t.vm.initStringObject("")
-							}
-							return t.vm.initStringObject(string([]rune(str)[positiveStart : positiveEnd+1]))
-						}
+					for _, line := range splitLines(str) {
+						t.builtinMethodYield(blockFrame, t.vm.initStringObject(line))
+					}
 
-					case *IntegerObject:
-						intValue := args[0].(*IntegerObject).value
-						if intValue < 0 {
-							if -intValue > strLength {
-								return NULL
-							}
-							return t.vm.initStringObject(string([]rune(str)[strLength+intValue]))
-						}
-						if intValue > strLength-1 {
-							return NULL
-						}
-						return t.vm.initStringObject(string([]rune(str)[intValue]))
+					return t.vm.initStringObject(str)
+				}
+			},
+		},
+		{
+			// Returns the encoding of self
+			//
+			// ```ruby
+			// "Goby".encoding                          # => Encoding::UTF_8
+			// "Goby".force_encoding("BINARY").encoding # => Encoding::ASCII_8BIT
+			// ```
+			//
+			// @return [Encoding]
+			Name: "encoding",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver.(*StringObject).encodingObject(t.vm)
+				}
+			},
+		},
+		{
+			// Returns true if string is empty value
+			//
+			// ```ruby
+			// "".empty?      # => true
+			// "Hello".empty? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "empty?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-					default:
-						return t.vm.initErrorObject(errors.TypeError, "Expect slice range to be Range or Integer. got: %s", args[0].Class().Name)
+					str := receiver.(*StringObject).value
+
+					if str == "" {
+						return TRUE
 					}
+					return FALSE
 				}
 			},
 		},
 		{
-			// Returns an array of strings separated by the given separator
+			// Returns true if receiver string ends with any of the argument strings
 			//
 			// ```ruby
-			// "Hello World".split("o") # => ["Hell", " W", "rld"]
-			// "Goby".split("")         # => ["G", "o", "b", "y"]
-			// "Hello\nWorld\nGoby".split("o") # => ["Hello", "World", "Goby"]
-			// "Hello🐟World🐟Goby".split("🐟") # => ["Hello", "World", "Goby"]
+			// "Hello".end_with?("llo")         # => true
+			// "Hello".end_with?("ell")         # => false
+			// "Hello".end_with?("ell", "llo")  # => true
+			// "😊Hello🐟".end_with?("🐟")     # => true
+			// "😊Hello🐟".end_with?("😊")     # => false
 			// ```
 			//
-			// @return [Array]
-			Name: "split",
+			// @return [Boolean]
+			Name: "end_with?",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got=%d", len(args))
 					}
 
-					s := args[0]
-					seperator, ok := s.(*StringObject)
+					str := receiver.(*StringObject).value
 
-					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, s.Class().Name)
-					}
+					for _, c := range args {
+						suffix, ok := c.(*StringObject)
 
-					str := receiver.(*StringObject).value
-					arr := strings.Split(str, seperator.value)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
+						}
 
-					var elements []Object
-					for i := 0; i < len(arr); i++ {
-						elements = append(elements, t.vm.initStringObject(arr[i]))
+						if strings.HasSuffix(str, suffix.value) {
+							return TRUE
+						}
 					}
 
-					return t.vm.initArrayObject(elements)
+					return FALSE
 				}
 			},
 		},
 		{
-			// Returns true if receiver string start with the argument string
+			// Returns true if receiver string is equal to argument string
 			//
 			// ```ruby
-			// "Hello".start_with("Hel")     # => true
-			// "Hello".start_with("hel")     # => false
-			// "😊Hello🐟".start_with("😊") # => true
-			// "😊Hello🐟".start_with("🐟") # => false
+			// "Hello".eql?("Hello")     # => true
+			// "Hello".eql?("World")     # => false
+			// "Hello😊".eql?("Hello😊") # => true
 			// ```
 			//
 			// @return [Boolean]
-			Name: "start_with",
+			Name: "eql?",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 					if len(args) != 1 {
-						return t.vm.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
 					}
 
 					str := receiver.(*StringObject).value
-					c := args[0]
-					compareStr, ok := c.(*StringObject)
+					compareStr, ok := args[0].(*StringObject)
 
 					if !ok {
-						return t.vm.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, c.Class().Name)
-					}
-
-					compareStrValue := compareStr.value
-					compareStrLength := utf8.RuneCountInString(compareStrValue)
-					strLength := utf8.RuneCountInString(str)
-
-					if compareStrLength > strLength {
 						return FALSE
-					}
-
-					if compareStrValue == string([]rune(str)[:compareStrLength]) {
+					} else if compareStr.value == str {
 						return TRUE
 					}
 					return FALSE
@@ -1321,187 +1213,2349 @@ func builtinStringInstanceMethods() []*BuiltinMethodObject {
 			},
 		},
 		{
-			// Returns a copy of str with leading and trailing whitespace removed.
-			// Whitespace is defined as any of the following characters: null, horizontal tab,
-			// line feed, vertical tab, form feed, carriage return, space.
+			// Changes the encoding of self to given encoding and returns self, the bytes of the string are not changed.
+			// The encoding can be an Encoding or an encoding name.
 			//
 			// ```ruby
-			// "  Goby Lang  ".strip   # => "Goby Lang"
-			// "\nGoby Lang\r\t".strip # => "Goby Lang"
+			// s = "Goby".force_encoding(Encoding::ASCII_8BIT)
+			// s.encoding.name # => "ASCII-8BIT"
+			// s.force_encoding("utf-8").encoding.name # => "UTF-8"
 			// ```
 			//
 			// @return [String]
-			Name: "strip",
+			Name: "force_encoding",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
 
-					str := receiver.(*StringObject).value
+					s := receiver.(*StringObject)
 
-					for {
-						str = strings.Trim(str, " ")
+					switch e := args[0].(type) {
+					case *EncodingObject:
+						s.encoding = e
+					case *StringObject:
+						enc := t.vm.findEncoding(e.value)
 
-						if strings.HasPrefix(str, "\n") || strings.HasPrefix(str, "\t") || strings.HasPrefix(str, "\r") || strings.HasPrefix(str, "\v") {
-							str = string([]rune(str)[1:])
-							continue
+						if enc == nil {
+							return t.initErrorObject(errors.ArgumentError, "Unknown encoding name: %s", e.value)
 						}
-						if strings.HasSuffix(str, "\n") || strings.HasSuffix(str, "\t") || strings.HasSuffix(str, "\r") || strings.HasSuffix(str, "\v") {
-							str = string([]rune(str)[:utf8.RuneCountInString(str)-2])
-							continue
-						}
-						break
+
+						s.encoding = enc
+					default:
+						return t.initErrorObject(errors.TypeError, "Expect encoding to be String or Encoding. got: %s", e.Class().Name)
 					}
-					return t.vm.initStringObject(str)
+
+					return s
 				}
 			},
 		},
 		{
-			// Returns an array of characters converted from a string
+			// Returns a copy of str with the all occurrences of pattern substituted for the second argument.
+			// The pattern can be a String or a Regexp; if given as a String, any regular expression
+			// metacharacters it contains will be interpreted literally, e.g. '\\d' will match a backslash
+			// followed by 'd', instead of a digit.
+			//
+			// The replacement string may contain back-references to the pattern's captured groups:
+			// `\0` or `\&` for the whole match, `\1`..`\9` for numbered groups and `\k<name>` for named groups.
+			// If a block is given instead of the replacement string, each match is yielded to the block
+			// and replaced with the block's result.
 			//
 			// ```ruby
-			// "Goby".to_a       # => ["G", "o", "b", "y"]
-			// "😊Hello🐟".to_a # => ["😊", "H", "e", "l", "l", "o", "🐟"]
+			// "Ruby Lang".gsub("Ru", "Go")                # => "Goby Lang"
+			// "Hello 😊 Hello 😊 Hello".gsub("😊", "🐟") # => "Hello 🐟 Hello 🐟 Hello"
+			// "Goby Lang".gsub(/[aeiou]/, "*")            # => "G*by L*ng"
+			// "John Smith".gsub(/(\w+) (\w+)/, '\2, \1')   # => "Smith, John"
+			// "a1b22".gsub(/\d+/) do |num|
+			//   num.to_i * 2
+			// end                                         # => "a2b44"
 			// ```
 			//
 			// @return [String]
-			Name: "to_a",
+			Name: "gsub",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					str := receiver.(*StringObject).value
+					result, _, err := substitute(t, str, args, blockFrame, -1)
 
-					str := receiver.(*StringObject)
-					strLength := utf8.RuneCountInString(str.value)
-					elems := []Object{}
-
-					for i := 0; i < strLength; i++ {
-						elems = append(elems, t.vm.initStringObject(string([]rune(str.value)[i])))
+					if err != nil {
+						return err
 					}
 
-					return t.vm.initArrayObject(elems)
+					return t.vm.initStringObject(result)
 				}
 			},
 		},
 		{
-			// Returns the result of converting self to Integer
+			// Performs the substitutions of String#gsub in place, returns the receiver, or nil if no
+			// substitutions were performed.
 			//
 			// ```ruby
-			// "123".to_i # => 123
-			// "3d print".to_i # => 3
-			// "some text".to_i # => 0
+			// s = "Goby Lang"
+			// s.gsub!(/[aeiou]/, "*") # => "G*by L*ng"
+			// s                       # => "G*by L*ng"
+			// s.gsub!("z", "*")       # => nil
 			// ```
 			//
-			// @return [Integer]
-			Name: "to_i",
+			// @return [String]
+			Name: "gsub!",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-
-					str := receiver.(*StringObject).value
-					parsedStr, err := strconv.ParseInt(str, 10, 0)
-
-					if err == nil {
-						return t.vm.initIntegerObject(int(parsedStr))
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
 					}
 
-					var digits string
-					for _, char := range str {
-						if unicode.IsDigit(char) {
-							digits += string(char)
-						} else {
-							break
-						}
+					s := receiver.(*StringObject)
+					result, changed, err := substitute(t, s.value, args, blockFrame, -1)
+
+					if err != nil {
+						return err
 					}
 
-					if len(digits) > 0 {
-						parsedStr, _ = strconv.ParseInt(digits, 10, 0)
-						return t.vm.initIntegerObject(int(parsedStr))
+					if !changed {
+						return NULL
 					}
 
-					return t.vm.initIntegerObject(0)
+					s.value = result
+					return s
 				}
 			},
 		},
 		{
-			// Returns a new String with self value
+			// Returns the result of interpreting the leading characters of self as a hexadecimal number.
+			// A sign and a "0x" prefix are allowed, returns 0 if there's no valid number.
 			//
 			// ```ruby
-			// "string".to_s # => "string"
+			// "ff".hex    # => 255
+			// "0x1A".hex  # => 26
+			// "-ff".hex   # => -255
+			// "goby".hex  # => 0
 			// ```
 			//
-			// @return [String]
-			Name: "to_s",
+			// @return [Integer]
+			Name: "hex",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
 
-					str := receiver.(*StringObject).value
-
-					return t.vm.initStringObject(str)
+					return t.vm.initIntegerFromBigInt(parseLeadingInteger(receiver.(*StringObject).value, 16))
 				}
 			},
 		},
 		{
-			// Returns a new String with all characters is upcase
+			// Checks if the specified string is included in the receiver
 			//
 			// ```ruby
-			// "very big".upcase # => "VERY BIG"
+			// "Hello\nWorld".include?("\n")   # => true
+			// "Hello 😊 Hello".include?("😊") # => true
 			// ```
 			//
-			// @return [String]
-			Name: "upcase",
+			// @return [Bool]
+			Name: "include?",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					}
 
 					str := receiver.(*StringObject).value
+					i := args[0]
+					includeStr, ok := i.(*StringObject)
 
-					return t.vm.initStringObject(strings.ToUpper(str))
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, i.Class().Name)
+					}
+
+					if strings.Contains(str, includeStr.value) {
+						return TRUE
+					}
+
+					return FALSE
 				}
 			},
 		},
 		{
-			Name: "to_bytes",
+			// Returns the Symbol of the string, same as `#to_sym`
+			//
+			// ```ruby
+			// "goby".intern          # => :goby
+			// "goby".intern == :goby # => true
+			// ```
+			//
+			// @return [Symbol]
+			Name: "intern",
 			Fn: func(receiver Object) builtinMethodBody {
 				return func(t *thread, args []Object, blockFrame *callFrame) Object {
-					r := receiver.(*StringObject)
-					return t.vm.initGoObject([]byte(r.value))
+					return t.vm.initSymbolObject(receiver.(*StringObject).value)
 				}
 			},
 		},
-	}
-}
+		{
+			// Insert a string input in specified index value of the receiver string in place, and returns the receiver
+			//
+			// It will raise error if index value is not an integer or index value is out
+			// of receiver string's range
+			//
+			// It will also raise error if the input string value is not type string
+			//
+			// ```ruby
+			// "Hello".insert(0, "X") # => "XHello"
+			// "Hello".insert(2, "X") # => "HeXllo"
+			// "Hello".insert(5, "X") # => "HelloX"
+			// "Hello".insert(-1, "X") # => "HelloX"
+			// "Hello".insert(-3, "X") # => "HelXlo"
+			// ```
+			//
+			// @return [String]
+			Name: "insert",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
 
-// Internal functions ===================================================
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+					}
 
-// Functions for initialization -----------------------------------------
+					str := receiver.(*StringObject).value
+					i := args[0]
+					index, ok := i.(*IntegerObject)
 
-func (vm *VM) initStringObject(value string) *StringObject {
-	return &StringObject{
-		baseObj: &baseObj{class: vm.topLevelClass(classes.StringClass)},
-		value:   value,
-	}
-}
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, i.Class().Name)
+					}
 
-func (vm *VM) initStringClass() *RClass {
-	sc := vm.initializeClass(classes.StringClass, false)
-	sc.setBuiltinMethods(builtinStringInstanceMethods(), false)
-	sc.setBuiltinMethods(builtinStringClassMethods(), true)
-	return sc
-}
+					indexValue := index.value
+					ins := args[1]
+					insertStr, ok := ins.(*StringObject)
 
-// Polymorphic helper functions -----------------------------------------
+					if !ok {
+						return t.initErrorObject(errors.TypeError, "Expect insert string to be String. got: %s", ins.Class().Name)
+					}
+					strLength := utf8.RuneCountInString(str)
 
-// Returns the object
-func (s *StringObject) Value() interface{} {
-	return s.value
-}
+					if indexValue < 0 {
+						if -indexValue > strLength+1 {
+							return t.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", indexValue)
+						} else if -indexValue == strLength+1 {
+							receiver.(*StringObject).value = insertStr.value + str
+							return receiver
+						}
+						// Change it to positive index value to replace the string via index
+						indexValue += strLength
+					}
 
-// toString just returns the value of string.
-func (s *StringObject) toString() string {
-	return s.value
-}
+					if strLength < indexValue {
+						return t.initErrorObject(errors.ArgumentError, "Index value out of range. got=%v", indexValue)
+					}
 
-// toJSON converts the receiver into JSON string.
-func (s *StringObject) toJSON() string {
-	return strconv.Quote(s.value)
-}
+					// Support UTF-8 Encoding
+					receiver.(*StringObject).value = string([]rune(str)[:indexValue]) + insertStr.value + string([]rune(str)[indexValue:])
+					return receiver
+				}
+			},
+		},
+		{
+			// Returns the character length of self, use `#bytesize` for the length in bytes
+			//
+			// ```ruby
+			// "zero".length # => 4
+			// "".length     # => 0
+			// "😊".length   # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "length",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
 
-// Returns true if the String values between receiver and parameter are equal
-func (s *StringObject) equal(e *StringObject) bool {
-	return s.value == e.value
+					str := receiver.(*StringObject).value
+
+					// Support UTF-8 Encoding
+					return t.vm.initIntegerObject(utf8.RuneCountInString(str))
+				}
+			},
+		},
+		{
+			// Returns an array of the lines in the string. Each line keeps its trailing newline character.
+			// If a block is given, yields each line and returns self.
+			//
+			// ```ruby
+			// "Hello\nWorld\n".lines # => ["Hello\n", "World\n"]
+			// "Hello\nGoby".lines    # => ["Hello\n", "Goby"]
+			// ```
+			//
+			// @return [Array]
+			Name: "lines",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					elems := []Object{}
+
+					for _, line := range splitLines(receiver.(*StringObject).value) {
+						elems = append(elems, t.vm.initStringObject(line))
+					}
+
+					return yieldOrCollect(t, receiver, elems, blockFrame)
+				}
+			},
+		},
+		{
+			// If input integer is greater than the length of receiver string, returns a new String of
+			// length integer with receiver string left justified and padded with default " "; otherwise,
+			// returns receiver string.
+			//
+			// It will raise error if the input string length is not integer type
+			//
+			// ```ruby
+			// "Hello".ljust(2)           # => "Hello"
+			// "Hello".ljust(7)           # => "Hello  "
+			// "Hello".ljust(10, "xo")    # => "Helloxoxox"
+			// "Hello".ljust(10, "😊🐟") # => "Hello😊🐟😊🐟😊"
+			// ```
+			//
+			// @return [String]
+			Name: "ljust",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					str := receiver.(*StringObject).value
+					width, pad, err := parseJustifyArgs(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					n := width - utf8.RuneCountInString(str)
+
+					if n <= 0 {
+						return t.vm.initStringObject(str)
+					}
+
+					return t.vm.initStringObject(str + padding(pad, n))
+				}
+			},
+		},
+		{
+			// Returns a copy of the string with leading whitespace removed, see String#strip for the definition
+			// of whitespace.
+			//
+			// ```ruby
+			// "  Goby Lang  ".lstrip # => "Goby Lang  "
+			// "\t\nGoby".lstrip      # => "Goby"
+			// ```
+			//
+			// @return [String]
+			Name: "lstrip",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(strings.TrimLeftFunc(receiver.(*StringObject).value, isStripSpace))
+				}
+			},
+		},
+		{
+			// Performs String#lstrip in place, returns the receiver, or nil if no changes were made.
+			//
+			// ```ruby
+			// s = "  Goby"
+			// s.lstrip! # => "Goby"
+			// s         # => "Goby"
+			// s.lstrip! # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "lstrip!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					return receiver.(*StringObject).mutate(strings.TrimLeftFunc(receiver.(*StringObject).value, isStripSpace))
+				}
+			},
+		},
+		{
+			// Matches the receiver against given pattern and returns a MatchData object, or nil if
+			// there's no match. The pattern can be a Regexp or a String, a String pattern will be
+			// compiled into a Regexp.
+			//
+			// ```ruby
+			// m = "Goby Lang".match(/(?<name>\w+) (\w+)/)
+			// m[0]             # => "Goby Lang"
+			// m.captures       # => ["Goby", "Lang"]
+			// m["name"]        # => "Goby"
+			// "Goby".match("o.") # => #<MatchData "ob">
+			// "Goby".match(/z/)  # => nil
+			// ```
+			//
+			// @return [MatchData]
+			Name: "match",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					str := receiver.(*StringObject).value
+
+					switch p := args[0].(type) {
+					case *RegexpObject:
+						return p.match(t, str)
+					case *StringObject:
+						re, err := t.vm.initRegexpObject(p.value, "")
+
+						if err != nil {
+							return t.initErrorObject(errors.ArgumentError, "%s", err.Error())
+						}
+
+						return re.match(t, str)
+					default:
+						return t.initErrorObject(errors.TypeError, "Expect pattern to be Regexp or String. got: %s", p.Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Replaces the content of the receiver with the input string in place and returns the receiver
+			//
+			// ```ruby
+			// "Hello".replace("World")          # => "World"
+			// "你好"replace("再見")              # => "再見"
+			// "Ruby\nLang".replace("Goby\nLang") # => "Goby Lang"
+			// "Hello😊".replace("World🐟")      # => "World🐟"
+			// ```
+			//
+			// @return [String]
+			Name: "replace",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					}
+
+					r := args[0]
+					replaceStr, ok := r.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, r.Class().Name)
+					}
+
+					s := receiver.(*StringObject)
+					s.value = replaceStr.value
+					return s
+				}
+			},
+		},
+		{
+			// Returns the successor of the string. The rightmost alphanumeric is incremented, a digit is
+			// incremented to another digit and a letter to another letter of the same case. Incrementing "9",
+			// "z" or "Z" carries to the alphanumeric on its left, and a new character is added if there's none.
+			// If the string has no alphanumerics, the rightmost character is incremented instead.
+			//
+			// ```ruby
+			// "abcd".next  # => "abce"
+			// "az".next    # => "ba"
+			// "zz".next    # => "aaa"
+			// "a9".next    # => "b0"
+			// "Zz".next    # => "AAa"
+			// "1.9".next   # => "2.0"
+			// "***".next   # => "**+"
+			// ```
+			//
+			// @return [String]
+			Name: "next",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					return t.vm.initStringObject(succString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Returns a new String with reverse order of self
+			//
+			// ```ruby
+			// "reverse".reverse           # => "esrever"
+			// "Hello\nWorld".reverse      # => "dlroW\nolleH"
+			// "Hello 😊🐟 World".reverse # => "dlroW 🐟😊 olleH"
+			// ```
+			//
+			// @return [String]
+			Name: "reverse",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					str := receiver.(*StringObject).value
+
+					return t.vm.initStringObject(reverseString(str))
+				}
+			},
+		},
+		{
+			// Performs String#reverse in place, returns the receiver, or nil if no changes were made.
+			//
+			// ```ruby
+			// s = "Goby"
+			// s.reverse! # => "yboG"
+			// s          # => "yboG"
+			// "aba".reverse! # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "reverse!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					return receiver.(*StringObject).mutate(reverseString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// If input integer is greater than the length of receiver string, returns a new String of
+			// length integer with receiver string right justified and padded with default " "; otherwise,
+			// returns receiver string.
+			//
+			// It will raise error if the input string length is not integer type
+			//
+			// ```ruby
+			// "Hello".rjust(2)          # => "Hello"
+			// "Hello".rjust(7)          # => "  Hello"
+			// "Hello".rjust(10, "xo")   # => "xoxoxHello"
+			// "Hello".rjust(10, "😊🐟") # => "😊🐟😊🐟😊Hello"
+			// ```
+			//
+			// @return [String]
+			Name: "rjust",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					str := receiver.(*StringObject).value
+					width, pad, err := parseJustifyArgs(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					n := width - utf8.RuneCountInString(str)
+
+					if n <= 0 {
+						return t.vm.initStringObject(str)
+					}
+
+					return t.vm.initStringObject(padding(pad, n) + str)
+				}
+			},
+		},
+		{
+			// Returns a copy of the string with trailing whitespace removed, see String#strip for the definition
+			// of whitespace.
+			//
+			// ```ruby
+			// "  Goby Lang  ".rstrip # => "  Goby Lang"
+			// "Goby\r\n".rstrip      # => "Goby"
+			// ```
+			//
+			// @return [String]
+			Name: "rstrip",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(strings.TrimRightFunc(receiver.(*StringObject).value, isStripSpace))
+				}
+			},
+		},
+		{
+			// Performs String#rstrip in place, returns the receiver, or nil if no changes were made.
+			//
+			// ```ruby
+			// s = "Goby  "
+			// s.rstrip! # => "Goby"
+			// s         # => "Goby"
+			// s.rstrip! # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "rstrip!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					return receiver.(*StringObject).mutate(strings.TrimRightFunc(receiver.(*StringObject).value, isStripSpace))
+				}
+			},
+		},
+		{
+			// Returns the character length of self, use `#bytesize` for the length in bytes
+			//
+			// ```ruby
+			// "zero".size  # => 4
+			// "".size      # => 0
+			// "😊".size   # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "size",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					str := receiver.(*StringObject).value
+
+					// Support UTF-8 Encoding
+					return t.vm.initIntegerObject(utf8.RuneCountInString(str))
+				}
+			},
+		},
+		{
+			// Returns the substring specified by the arguments, it's the same as `String#[]`.
+			//
+			// ```ruby
+			// "1234567890".slice(1..-1)    # => "234567890"
+			// "1234567890".slice(1..-1234) # => ""
+			// "1234567890".slice(-11..5)   # => nil
+			// "1234567890".slice(-10..-5)  # => "123456"
+			// "1234567890".slice(-5..-10)  # => ""
+			// "1234567890".slice(-11..-12) # => nil
+			// "1234567890".slice(-10..-12) # => ""
+			// "1234567890".slice(2, 3)     # => "345"
+			// "1234567890".slice(8, 5)     # => "90"
+			// "1234567890".slice(/[5-7]+/) # => "567"
+			// "Hello 😊🐟 World".slice(1..6)    # => "ello 😊"
+			// "Hello 😊🐟 World".slice(-10..7)  # => "o 😊🐟"
+			// "Hello World".slice(4)       # => "o"
+			// "Hello World".slice(-3)      # => "r"
+			// "Hello World".slice(-12)     # => nil
+			// "Hello World".slice(11)      # => nil
+			// "Hello 😊🐟 World".slice(6)      # => "😊"
+			// "Hello 😊🐟 World".slice(-7)      # => "🐟"
+			// ```
+			//
+			// @return [String]
+			Name: "slice",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					str := receiver.(*StringObject).value
+					start, end, err := substringRange(t, str, args)
+
+					if err != nil {
+						return err
+					}
+
+					if start < 0 {
+						return NULL
+					}
+
+					return t.vm.initStringObject(string([]rune(str)[start:end]))
+				}
+			},
+		},
+		{
+			// Removes the substring specified by the arguments from the string and returns it.
+			// The arguments are the same as `String#[]`. Returns nil and keeps the string unchanged if the
+			// substring doesn't exist.
+			//
+			// ```ruby
+			// s = "Hello World"
+			// s.slice!(5..-1) # => " World"
+			// s               # => "Hello"
+			// s.slice!(/l+/)  # => "ll"
+			// s               # => "Heo"
+			// s.slice!(10)    # => nil
+			// s               # => "Heo"
+			// ```
+			//
+			// @return [String]
+			Name: "slice!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					s := receiver.(*StringObject)
+					start, end, err := substringRange(t, s.value, args)
+
+					if err != nil {
+						return err
+					}
+
+					if start < 0 {
+						return NULL
+					}
+
+					runes := []rune(s.value)
+					removed := string(runes[start:end])
+					s.value = string(runes[:start]) + string(runes[end:])
+
+					return t.vm.initStringObject(removed)
+				}
+			},
+		},
+		{
+			// Returns an array of strings separated by the given separator, which can be a String or a Regexp.
+			//
+			// If the separator is omitted or is a single space, the string is split on whitespace, and leading
+			// whitespace is ignored. If the separator is a Regexp containing groups, the captured strings are
+			// included in the result as well.
+			//
+			// If limit is omitted or zero, trailing empty strings are removed from the result. If limit is positive,
+			// at most limit fields are returned, the last one contains the rest of the string. If limit is negative,
+			// trailing empty strings are kept.
+			//
+			// ```ruby
+			// "Hello World".split("o")         # => ["Hell", " W", "rld"]
+			// "Goby".split("")                 # => ["G", "o", "b", "y"]
+			// "Hello\nWorld\nGoby".split("\n") # => ["Hello", "World", "Goby"]
+			// "Hello🐟World🐟Goby".split("🐟") # => ["Hello", "World", "Goby"]
+			// "  a  b c ".split                # => ["a", "b", "c"]
+			// "a1b22c".split(/\d+/)            # => ["a", "b", "c"]
+			// "a-b_c".split(/([-_])/)          # => ["a", "-", "b", "_", "c"]
+			// "a,b,c".split(",", 2)            # => ["a", "b,c"]
+			// ",a,,b,,".split(",")             # => ["", "a", "", "b"]
+			// ",a,,b,,".split(",", -1)         # => ["", "a", "", "b", "", ""]
+			// ```
+			//
+			// @return [Array]
+			Name: "split",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..2 arguments. got=%d", len(args))
+					}
+
+					str := receiver.(*StringObject).value
+					re := whitespaceRegexp
+					awk := true
+
+					if len(args) > 0 {
+						switch sep := args[0].(type) {
+						case *StringObject:
+							if sep.value != " " {
+								re = regexp.MustCompile(regexp.QuoteMeta(sep.value))
+								awk = false
+							}
+						case *RegexpObject:
+							re = sep.regexp
+							awk = false
+						case *NullObject:
+						default:
+							return t.initErrorObject(errors.TypeError, "Expect separator to be String or Regexp. got: %s", sep.Class().Name)
+						}
+					}
+
+					limit := 0
+
+					if len(args) == 2 {
+						l := args[1]
+						limitObj, ok := l.(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, "Expect limit to be Integer. got: %s", l.Class().Name)
+						}
+
+						limit = limitObj.value
+					}
+
+					if awk {
+						str = strings.TrimLeftFunc(str, unicode.IsSpace)
+					}
+
+					elements := []Object{}
+					for _, field := range splitString(str, re, limit) {
+						elements = append(elements, t.vm.initStringObject(field))
+					}
+
+					return t.vm.initArrayObject(elements)
+				}
+			},
+		},
+		{
+			// Returns a copy of the string with runs of the same character replaced by a single character.
+			// If character sets are given, only runs of characters in the intersection of the sets are squeezed.
+			//
+			// ```ruby
+			// "yellow  moon".squeeze        # => "yelow mon"
+			// "  now   is  the".squeeze(" ") # => " now is the"
+			// "putters shoot balls".squeeze("m-z") # => "puters shot balls"
+			// "🍣🍣🍺🍺".squeeze           # => "🍣🍺"
+			// ```
+			//
+			// @return [String]
+			Name: "squeeze",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					sets, err := parseCharSets(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					var result bytes.Buffer
+					var last rune = -1
+
+					for _, r := range receiver.(*StringObject).value {
+						if r == last && sets.include(r) {
+							continue
+						}
+
+						result.WriteRune(r)
+						last = r
+					}
+
+					return t.vm.initStringObject(result.String())
+				}
+			},
+		},
+		{
+			// Returns a copy of the string with leading and trailing whitespace removed, and every run of
+			// whitespace inside the string replaced by a single space.
+			//
+			// ```ruby
+			// "  Goby \n  Lang  ".squish # => "Goby Lang"
+			// "Goby\t\tLang".squish      # => "Goby Lang"
+			// ```
+			//
+			// @return [String]
+			Name: "squish",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(squishString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Performs String#squish in place, returns the receiver, or nil if no changes were made.
+			//
+			// ```ruby
+			// s = " Goby   Lang "
+			// s.squish! # => "Goby Lang"
+			// s         # => "Goby Lang"
+			// s.squish! # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "squish!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					return receiver.(*StringObject).mutate(squishString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Returns true if receiver string start with the argument string. It's kept for compatibility,
+			// use `start_with?` to check several prefixes or a Regexp.
+			//
+			// ```ruby
+			// "Hello".start_with("Hel")     # => true
+			// "Hello".start_with("hel")     # => false
+			// "😊Hello🐟".start_with("😊") # => true
+			// "😊Hello🐟".start_with("🐟") # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "start_with",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%v", strconv.Itoa(len(args)))
+					}
+
+					prefix, ok := args[0].(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					return toBooleanObject(strings.HasPrefix(receiver.(*StringObject).value, prefix.value))
+				}
+			},
+		},
+		{
+			// Returns true if receiver string starts with any of the arguments, which can be Strings or Regexps
+			//
+			// ```ruby
+			// "Hello".start_with?("Hel")        # => true
+			// "Hello".start_with?("hel")        # => false
+			// "Hello".start_with?("hel", "He")  # => true
+			// "Hello".start_with?(/h|H/)        # => true
+			// "😊Hello🐟".start_with?("😊")    # => true
+			// "😊Hello🐟".start_with?("🐟")    # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "start_with?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) < 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect at least 1 argument. got=%d", len(args))
+					}
+
+					str := receiver.(*StringObject).value
+
+					for _, c := range args {
+						switch prefix := c.(type) {
+						case *StringObject:
+							if strings.HasPrefix(str, prefix.value) {
+								return TRUE
+							}
+						case *RegexpObject:
+							loc := prefix.regexp.FindStringIndex(str)
+
+							if loc != nil && loc[0] == 0 {
+								return TRUE
+							}
+						default:
+							return t.initErrorObject(errors.TypeError, "Expect argument to be String or Regexp. got: %s", c.Class().Name)
+						}
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Returns a copy of str with leading and trailing whitespace removed.
+			// Whitespace is defined as null or any Unicode space character, like horizontal tab, line feed,
+			// vertical tab, form feed, carriage return, space and ideographic space.
+			//
+			// ```ruby
+			// "  Goby Lang  ".strip   # => "Goby Lang"
+			// "\nGoby Lang\r\t".strip # => "Goby Lang"
+			// ```
+			//
+			// @return [String]
+			Name: "strip",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					str := receiver.(*StringObject).value
+
+					return t.vm.initStringObject(stripString(str))
+				}
+			},
+		},
+		{
+			// Performs String#strip in place, returns the receiver, or nil if no changes were made.
+			//
+			// ```ruby
+			// s = "  Goby \n"
+			// s.strip! # => "Goby"
+			// s        # => "Goby"
+			// s.strip! # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "strip!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					return receiver.(*StringObject).mutate(stripString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Returns the successor of the string. The rightmost alphanumeric is incremented, a digit is
+			// incremented to another digit and a letter to another letter of the same case. Incrementing "9",
+			// "z" or "Z" carries to the alphanumeric on its left, and a new character is added if there's none.
+			// If the string has no alphanumerics, the rightmost character is incremented instead.
+			//
+			// ```ruby
+			// "abcd".succ  # => "abce"
+			// "az".succ    # => "ba"
+			// "zz".succ    # => "aaa"
+			// "a9".succ    # => "b0"
+			// "Zz".succ    # => "AAa"
+			// "1.9".succ   # => "2.0"
+			// "***".succ   # => "**+"
+			// ```
+			//
+			// @return [String]
+			Name: "succ",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got=%d", len(args))
+					}
+
+					return t.vm.initStringObject(succString(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Returns a copy of str with the first occurrence of pattern substituted for the second argument.
+			// It accepts the same patterns, replacements and block as String#gsub.
+			//
+			// ```ruby
+			// "Ruby Ruby".sub("Ru", "Go")               # => "Goby Ruby"
+			// "Goby Lang".sub(/[aeiou]/, "*")           # => "G*by Lang"
+			// "John Smith".sub(/(?<first>\w+)/, '\k<first>!') # => "John! Smith"
+			// "Goby".sub(/o/) do |c|
+			//   c.upcase
+			// end                                       # => "GOby"
+			// ```
+			//
+			// @return [String]
+			Name: "sub",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					str := receiver.(*StringObject).value
+					result, _, err := substitute(t, str, args, blockFrame, 1)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initStringObject(result)
+				}
+			},
+		},
+		{
+			// Performs the substitution of String#sub in place, returns the receiver, or nil if no
+			// substitution was performed.
+			//
+			// ```ruby
+			// s = "Goby Lang"
+			// s.sub!(/[aeiou]/, "*") # => "G*by Lang"
+			// s                      # => "G*by Lang"
+			// s.sub!("z", "*")       # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "sub!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					s := receiver.(*StringObject)
+					result, changed, err := substitute(t, s.value, args, blockFrame, 1)
+
+					if err != nil {
+						return err
+					}
+
+					if !changed {
+						return NULL
+					}
+
+					s.value = result
+					return s
+				}
+			},
+		},
+		{
+			// Returns an array of characters converted from a string
+			//
+			// ```ruby
+			// "Goby".to_a       # => ["G", "o", "b", "y"]
+			// "😊Hello🐟".to_a # => ["😊", "H", "e", "l", "l", "o", "🐟"]
+			// ```
+			//
+			// @return [String]
+			Name: "to_a",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					str := receiver.(*StringObject)
+					strLength := utf8.RuneCountInString(str.value)
+					elems := []Object{}
+
+					for i := 0; i < strLength; i++ {
+						elems = append(elems, t.vm.initStringObject(string([]rune(str.value)[i])))
+					}
+
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
+		{
+			// Returns the result of interpreting the leading characters of self as an integer in the given base,
+			// which defaults to 10 and must be between 2 and 36. Leading whitespace, a sign, a radix prefix
+			// matching the base (like "0x" for 16) and underscores between digits are allowed. Returns 0 if
+			// there's no valid number.
+			//
+			// ```ruby
+			// "123".to_i         # => 123
+			// "3d print".to_i    # => 3
+			// "some text".to_i   # => 0
+			// " -42".to_i        # => -42
+			// "1_000".to_i       # => 1000
+			// "ff".to_i(16)      # => 255
+			// "0xff".to_i(16)    # => 255
+			// "1010".to_i(2)     # => 10
+			// "z".to_i(36)       # => 35
+			// ```
+			//
+			// @return [Integer]
+			Name: "to_i",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					base, err := parseRadix(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initIntegerFromBigInt(parseLeadingInteger(receiver.(*StringObject).value, base))
+				}
+			},
+		},
+		{
+			// Returns the result of interpreting the leading characters of self as a rational number, which can
+			// be written as a fraction, a decimal or with an exponent. Returns 0 if there's no valid number.
+			//
+			// ```ruby
+			// "1/3".to_r        # => 1/3
+			// "0.75".to_r       # => 3/4
+			// "1e-3".to_r       # => 1/1000
+			// " 2 apples".to_r  # => 2/1
+			// "some text".to_r  # => 0/1
+			// ```
+			//
+			// @return [Rational]
+			Name: "to_r",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initRationalObject(parseLeadingRational(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Returns a new String with self value
+			//
+			// ```ruby
+			// "string".to_s # => "string"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					str := receiver.(*StringObject).value
+
+					return t.vm.initStringObject(str)
+				}
+			},
+		},
+		{
+			// Returns the Symbol of the string
+			//
+			// ```ruby
+			// "goby".to_sym          # => :goby
+			// "goby".to_sym == :goby # => true
+			// ```
+			//
+			// @return [Symbol]
+			Name: "to_sym",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initSymbolObject(receiver.(*StringObject).value)
+				}
+			},
+		},
+		{
+			// Returns a copy of the string with the characters in from replaced by the corresponding characters in to.
+			// Both from and to can contain ranges like "a-z". If to is shorter than from, it's padded with its last
+			// character. If from starts with "^", all characters not in the set are replaced by the last character of to.
+			// If to is empty, the characters in from are deleted. Use a backslash to escape "^" or "-".
+			//
+			// ```ruby
+			// "hello".tr("el", "ip")      # => "hippo"
+			// "hello".tr("aeiou", "*")    # => "h*ll*"
+			// "hello".tr("a-y", "b-z")    # => "ifmmp"
+			// "hello".tr("^l", "*")       # => "**ll*"
+			// "hello".tr("l", "")         # => "heo"
+			// "Goby🍣".tr("🍣", "🍺")     # => "Goby🍺"
+			// ```
+			//
+			// @return [String]
+			Name: "tr",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+					}
+
+					sets, err := parseCharSets(t, args)
+
+					if err != nil {
+						return err
+					}
+
+					from, to := sets[0], sets[1]
+					// "^" is only meaningful in from
+					if to.negated {
+						to.runes = append([]rune{'^'}, to.runes...)
+					}
+
+					mapping := map[rune]rune{}
+
+					if !from.negated && len(to.runes) > 0 {
+						for i, r := range from.runes {
+							if i < len(to.runes) {
+								mapping[r] = to.runes[i]
+							} else {
+								mapping[r] = to.runes[len(to.runes)-1]
+							}
+						}
+					}
+
+					var result bytes.Buffer
+
+					for _, r := range receiver.(*StringObject).value {
+						switch {
+						case !from.include(r):
+							result.WriteRune(r)
+						case len(to.runes) == 0:
+						case from.negated:
+							result.WriteRune(to.runes[len(to.runes)-1])
+						default:
+							result.WriteRune(mapping[r])
+						}
+					}
+
+					return t.vm.initStringObject(result.String())
+				}
+			},
+		},
+		{
+			// Decodes the receiver's bytes according to the format string and returns an array of the values.
+			// Each directive in the format can be followed by a count, or `*` to use all the remaining data.
+			//
+			// - `a`: binary string of count bytes. `A` is the same but removes trailing spaces and nulls.
+			// - `H`, `h`: hex string of count nibbles, with high or low nibble first.
+			// - `B`, `b`: bit string of count bits, with most or least significant bit first.
+			// - `C`, `c`: count unsigned or signed 8-bit integers.
+			// - `n`, `N`: count 16-bit or 32-bit unsigned integers in big-endian byte order.
+			// - `v`, `V`: count 16-bit or 32-bit unsigned integers in little-endian byte order.
+			// - `U`: count UTF-8 characters as integer code points.
+			// - `m`: Base64 encoded string, the count is ignored.
+			//
+			// Spaces in the format are ignored. Integers that run out of data are nil.
+			//
+			// ```ruby
+			// "Goby".unpack("C*")        # => [71, 111, 98, 121]
+			// "Goby".unpack("a2 a2")     # => ["Go", "by"]
+			// "Goby".unpack("H*")        # => ["476f6279"]
+			// "AB".unpack("n")           # => [16706]
+			// "AB".unpack("v")           # => [16961]
+			// "😊!".unpack("U*")         # => [128522, 33]
+			// "R29ieQ==".unpack("m")     # => ["Goby"]
+			// "A".unpack("C2")           # => [65, nil]
+			// ```
+			//
+			// @return [Array]
+			Name: "unpack",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					f := args[0]
+					format, ok := f.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, f.Class().Name)
+					}
+
+					elems, err := unpackString(t, receiver.(*StringObject).value, format.value)
+
+					if err != nil {
+						return err
+					}
+
+					return t.vm.initArrayObject(elems)
+				}
+			},
+		},
+		{
+			// Decodes the receiver like String#unpack, but returns the first value only
+			//
+			// ```ruby
+			// "Goby".unpack1("H*")    # => "476f6279"
+			// "AB".unpack1("n")       # => 16706
+			// "".unpack1("C")         # => nil
+			// ```
+			//
+			// @return [Object]
+			Name: "unpack1",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					f := args[0]
+					format, ok := f.(*StringObject)
+
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, f.Class().Name)
+					}
+
+					elems, err := unpackString(t, receiver.(*StringObject).value, format.value)
+
+					if err != nil {
+						return err
+					}
+
+					if len(elems) == 0 {
+						return NULL
+					}
+
+					return elems[0]
+				}
+			},
+		},
+		{
+			// Returns a new String with all characters is upcase
+			//
+			// ```ruby
+			// "very big".upcase # => "VERY BIG"
+			// ```
+			//
+			// @return [String]
+			Name: "upcase",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+
+					str := receiver.(*StringObject).value
+
+					return t.vm.initStringObject(strings.ToUpper(str))
+				}
+			},
+		},
+		{
+			// Performs String#upcase in place, returns the receiver, or nil if no changes were made.
+			//
+			// ```ruby
+			// s = "goby"
+			// s.upcase! # => "GOBY"
+			// s         # => "GOBY"
+			// s.upcase! # => nil
+			// ```
+			//
+			// @return [String]
+			Name: "upcase!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if err := t.checkFrozen(receiver); err != nil {
+						return err
+					}
+
+					return receiver.(*StringObject).mutate(strings.ToUpper(receiver.(*StringObject).value))
+				}
+			},
+		},
+		{
+			// Returns true if self is a valid byte sequence in its encoding
+			//
+			// ```ruby
+			// "Goby 😊".valid_encoding?                           # => true
+			// "Goby 😊".force_encoding("US-ASCII").valid_encoding? # => false
+			// "Goby 😊".force_encoding("BINARY").valid_encoding?   # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "valid_encoding?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					s := receiver.(*StringObject)
+
+					if s.encodingObject(t.vm).valid(s.value) {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			Name: "to_bytes",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					r := receiver.(*StringObject)
+					return t.vm.initGoObject([]byte(r.value))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initStringObject(value string) *StringObject {
+	return &StringObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.StringClass)},
+		value:   value,
+	}
+}
+
+func (vm *VM) initStringClass() *RClass {
+	sc := vm.initializeClass(classes.StringClass, false)
+	sc.setBuiltinMethods(builtinStringInstanceMethods(), false)
+	sc.setBuiltinMethods(builtinStringClassMethods(), true)
+	sc.include(vm.topLevelClass(classes.ComparableModule))
+	return sc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (s *StringObject) Value() interface{} {
+	return s.value
+}
+
+// toString just returns the value of string.
+func (s *StringObject) toString() string {
+	return s.value
+}
+
+// toJSON converts the receiver into JSON string.
+func (s *StringObject) toJSON() string {
+	return strconv.Quote(s.value)
+}
+
+// mutate replaces the string's value with given value and returns the string, or returns nil if the value
+// is unchanged. It's used by the in-place methods like String#upcase!.
+func (s *StringObject) mutate(value string) Object {
+	if value == s.value {
+		return NULL
+	}
+
+	s.value = value
+	return s
+}
+
+// encodingObject returns the encoding of the string
+func (s *StringObject) encodingObject(vm *VM) *EncodingObject {
+	if s.encoding == nil {
+		return vm.findEncoding(utf8Encoding)
+	}
+
+	return s.encoding
+}
+
+// Returns true if the String values between receiver and parameter are equal
+func (s *StringObject) equal(e *StringObject) bool {
+	return s.value == e.value
+}
+
+// charSet is a set of characters described by a string like "a-z" or "^aeiou", used by String#tr,
+// String#squeeze and String#delete
+type charSet struct {
+	runes   []rune
+	members map[rune]bool
+	negated bool
+}
+
+// charSets is the intersection of multiple character sets
+type charSets []*charSet
+
+func (cs *charSet) include(r rune) bool {
+	return cs.members[r] != cs.negated
+}
+
+func (css charSets) include(r rune) bool {
+	for _, cs := range css {
+		if !cs.include(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// parseCharSets parses each String argument into a charSet
+func parseCharSets(t *thread, args []Object) (charSets, *Error) {
+	sets := charSets{}
+
+	for _, arg := range args {
+		str, ok := arg.(*StringObject)
+
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+		}
+
+		cs := &charSet{members: map[rune]bool{}}
+		rs := []rune(str.value)
+
+		if len(rs) > 1 && rs[0] == '^' {
+			cs.negated = true
+			rs = rs[1:]
+		}
+
+		for i := 0; i < len(rs); i++ {
+			switch {
+			case rs[i] == '\\' && i+1 < len(rs):
+				i++
+				cs.runes = append(cs.runes, rs[i])
+			case i+2 < len(rs) && rs[i+1] == '-':
+				if rs[i] > rs[i+2] {
+					return nil, t.initErrorObject(errors.ArgumentError, "Invalid range \"%s\" in string transliteration", string(rs[i:i+3]))
+				}
+
+				for r := rs[i]; r <= rs[i+2]; r++ {
+					cs.runes = append(cs.runes, r)
+				}
+				i += 2
+			default:
+				cs.runes = append(cs.runes, rs[i])
+			}
+		}
+
+		for _, r := range cs.runes {
+			cs.members[r] = true
+		}
+
+		sets = append(sets, cs)
+	}
+
+	return sets, nil
+}
+
+var whitespaceRegexp = regexp.MustCompile(`\s+`)
+
+// asciiLower converts the ASCII uppercase letters in str to lowercase, other characters are kept as they are
+func asciiLower(str string) string {
+	return strings.Map(func(r rune) rune {
+		if 'A' <= r && r <= 'Z' {
+			return r + 'a' - 'A'
+		}
+		return r
+	}, str)
+}
+
+// capitalizeString converts the first character of str to uppercase and the rest to lowercase
+func capitalizeString(str string) string {
+	if str == "" {
+		return str
+	}
+
+	start := string([]rune(str)[0])
+	rest := string([]rune(str)[1:])
+	return strings.ToUpper(start) + strings.ToLower(rest)
+}
+
+// reverseString reverses the characters of str
+func reverseString(str string) string {
+	runes := []rune(str)
+
+	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
+		runes[i], runes[j] = runes[j], runes[i]
+	}
+
+	return string(runes)
+}
+
+// stripString removes the leading and trailing whitespaces of str
+func stripString(str string) string {
+	return strings.TrimFunc(str, isStripSpace)
+}
+
+// isStripSpace reports whether r is removed by String#strip, #lstrip and #rstrip
+func isStripSpace(r rune) bool {
+	return r == 0 || unicode.IsSpace(r)
+}
+
+// chompString removes the separator given in args from the end of str, see String#chomp
+func chompString(t *thread, str string, args []Object) (string, *Error) {
+	if len(args) > 1 {
+		return "", t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got=%d", len(args))
+	}
+
+	if len(args) == 0 {
+		if strings.HasSuffix(str, "\r\n") {
+			return str[:len(str)-2], nil
+		}
+
+		return strings.TrimSuffix(strings.TrimSuffix(str, "\n"), "\r"), nil
+	}
+
+	s := args[0]
+	sep, ok := s.(*StringObject)
+
+	if !ok {
+		return "", t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, s.Class().Name)
+	}
+
+	if sep.value == "" {
+		for strings.HasSuffix(str, "\n") {
+			str = strings.TrimSuffix(strings.TrimSuffix(str, "\n"), "\r")
+		}
+
+		return str, nil
+	}
+
+	return strings.TrimSuffix(str, sep.value), nil
+}
+
+// chopString removes the last character of str, or "\r\n" if str ends with it
+func chopString(str string) string {
+	if strings.HasSuffix(str, "\r\n") {
+		return str[:len(str)-2]
+	}
+
+	_, size := utf8.DecodeLastRuneInString(str)
+	return str[:len(str)-size]
+}
+
+// squishString strips str and replaces every run of whitespace inside it with a single space
+func squishString(str string) string {
+	return strings.Join(strings.Fields(str), " ")
+}
+
+// parseJustifyArgs parses the width and the optional padding string arguments of String#ljust, #rjust and #center
+func parseJustifyArgs(t *thread, args []Object) (int, string, *Error) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, "", t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%v", strconv.Itoa(len(args)))
+	}
+
+	w := args[0]
+	width, ok := w.(*IntegerObject)
+
+	if !ok {
+		return 0, "", t.initErrorObject(errors.TypeError, "Expect justify width to be Integer. got: %s", w.Class().Name)
+	}
+
+	if len(args) == 1 {
+		return width.value, " ", nil
+	}
+
+	p := args[1]
+	pad, ok := p.(*StringObject)
+
+	if !ok {
+		return 0, "", t.initErrorObject(errors.TypeError, "Expect padding string to be String. got: %s", p.Class().Name)
+	}
+
+	if pad.value == "" {
+		return 0, "", t.initErrorObject(errors.ArgumentError, "Zero width padding")
+	}
+
+	return width.value, pad.value, nil
+}
+
+// substringRange returns the character range [start, end) of the substring specified by the arguments
+// of `String#[]`, `#slice` and `#slice!`. start is -1 if the substring doesn't exist.
+func substringRange(t *thread, str string, args []Object) (int, int, *Error) {
+	if len(args) != 1 && len(args) != 2 {
+		return 0, 0, t.initErrorObject(errors.ArgumentError, "Expect 1..2 arguments. got=%d", len(args))
+	}
+
+	strLength := utf8.RuneCountInString(str)
+
+	switch arg := args[0].(type) {
+	case *RegexpObject:
+		group := 0
+
+		if len(args) == 2 {
+			g, ok := args[1].(*IntegerObject)
+
+			if !ok {
+				return 0, 0, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+			}
+
+			group = g.value
+		}
+
+		loc := arg.regexp.FindStringSubmatchIndex(str)
+
+		if loc == nil || group < 0 || group*2 >= len(loc) || loc[group*2] < 0 {
+			return -1, -1, nil
+		}
+
+		start := utf8.RuneCountInString(str[:loc[group*2]])
+		return start, start + utf8.RuneCountInString(str[loc[group*2]:loc[group*2+1]]), nil
+
+	case *RangeObject:
+		if arg.isString || arg.isFloat {
+			return 0, 0, t.initErrorObject(errors.TypeError, "Expect slice range to be Integer range. got: %s", arg.toString())
+		}
+
+		if len(args) != 1 {
+			return 0, 0, t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+		}
+
+		start, end := arg.Start, arg.End
+
+		if start < 0 {
+			start += strLength
+		}
+
+		if end < 0 {
+			end += strLength
+		}
+
+		if start < 0 || start > strLength {
+			return -1, -1, nil
+		}
+
+		if !arg.exclusive {
+			end++
+		}
+
+		if arg.endless || end > strLength {
+			end = strLength
+		}
+
+		if end < start {
+			end = start
+		}
+
+		return start, end, nil
+
+	case *IntegerObject:
+		start := arg.value
+
+		if start < 0 {
+			start += strLength
+		}
+
+		if len(args) == 1 {
+			if start < 0 || start >= strLength {
+				return -1, -1, nil
+			}
+
+			return start, start + 1, nil
+		}
+
+		l, ok := args[1].(*IntegerObject)
+
+		if !ok {
+			return 0, 0, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[1].Class().Name)
+		}
+
+		if start < 0 || start > strLength || l.value < 0 {
+			return -1, -1, nil
+		}
+
+		end := start + l.value
+
+		if end > strLength {
+			end = strLength
+		}
+
+		return start, end, nil
+
+	default:
+		return 0, 0, t.initErrorObject(errors.TypeError, "Expect slice range to be Range, Integer or Regexp. got: %s", args[0].Class().Name)
+	}
+}
+
+// succString returns the successor of str, see `String#succ`
+func succString(str string) string {
+	runes := []rune(str)
+
+	if len(runes) == 0 {
+		return ""
+	}
+
+	// pos is the position of the leftmost alphanumeric that carried, -1 if there's no alphanumeric
+	pos := -1
+	var carry rune
+
+	for i := len(runes) - 1; i >= 0; i-- {
+		r := runes[i]
+
+		switch {
+		case r >= 'a' && r < 'z', r >= 'A' && r < 'Z', r >= '0' && r < '9':
+			runes[i]++
+			return string(runes)
+		case r == 'z':
+			runes[i], carry = 'a', 'a'
+		case r == 'Z':
+			runes[i], carry = 'A', 'A'
+		case r == '9':
+			runes[i], carry = '0', '1'
+		default:
+			continue
+		}
+
+		pos = i
+	}
+
+	if pos < 0 {
+		runes[len(runes)-1]++
+		return string(runes)
+	}
+
+	return string(runes[:pos]) + string(carry) + string(runes[pos:])
+}
+
+// parseLeadingInteger converts the leading integer of str in the given base, see String#to_i
+func parseLeadingInteger(str string, base int) *big.Int {
+	str = strings.TrimLeftFunc(str, unicode.IsSpace)
+	negative := false
+
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		negative = str[0] == '-'
+		str = str[1:]
+	}
+
+	if len(str) > 2 && str[0] == '0' {
+		switch {
+		case base == 16 && (str[1] == 'x' || str[1] == 'X'),
+			base == 2 && (str[1] == 'b' || str[1] == 'B'),
+			base == 8 && (str[1] == 'o' || str[1] == 'O'):
+			str = str[2:]
+		}
+	}
+
+	value := new(big.Int)
+	b := big.NewInt(int64(base))
+	hasDigit := false
+	lastUnderscore := false
+
+	for _, r := range str {
+		if r == '_' {
+			if !hasDigit || lastUnderscore {
+				break
+			}
+
+			lastUnderscore = true
+			continue
+		}
+
+		d := digitValue(r)
+
+		if d < 0 || d >= base {
+			break
+		}
+
+		value.Mul(value, b).Add(value, big.NewInt(int64(d)))
+		hasDigit = true
+		lastUnderscore = false
+	}
+
+	if negative {
+		value.Neg(value)
+	}
+
+	return value
+}
+
+// digitValue returns the value of r as a digit in base 36, or -1 if r isn't a digit
+func digitValue(r rune) int {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r - '0')
+	case 'a' <= r && r <= 'z':
+		return int(r-'a') + 10
+	case 'A' <= r && r <= 'Z':
+		return int(r-'A') + 10
+	}
+
+	return -1
+}
+
+// unpackString decodes str according to the directives of format, see String#unpack
+func unpackString(t *thread, str, format string) ([]Object, *Error) {
+	data := []byte(str)
+	pos := 0
+	elems := []Object{}
+
+	for i := 0; i < len(format); i++ {
+		d := format[i]
+
+		if unicode.IsSpace(rune(d)) {
+			continue
+		}
+
+		count, all := 1, false
+
+		if i+1 < len(format) && format[i+1] == '*' {
+			all = true
+			i++
+		} else {
+			j := i + 1
+
+			for j < len(format) && isDigit(format[j]) {
+				j++
+			}
+
+			if j > i+1 {
+				count, _ = strconv.Atoi(format[i+1 : j])
+				i = j - 1
+			}
+		}
+
+		rest := data[pos:]
+
+		switch d {
+		case 'a', 'A':
+			n := count
+
+			if all || n > len(rest) {
+				n = len(rest)
+			}
+
+			s := string(rest[:n])
+
+			if d == 'A' {
+				s = strings.TrimRight(s, " \x00")
+			}
+
+			elems = append(elems, t.vm.initStringObject(s))
+			pos += n
+		case 'H', 'h':
+			n := count
+
+			if all || n > len(rest)*2 {
+				n = len(rest) * 2
+			}
+
+			buf := make([]byte, n)
+
+			for k := 0; k < n; k++ {
+				b := rest[k/2]
+
+				if (d == 'H') == (k%2 == 0) {
+					b >>= 4
+				}
+
+				buf[k] = "0123456789abcdef"[b&0xf]
+			}
+
+			elems = append(elems, t.vm.initStringObject(string(buf)))
+			pos += (n + 1) / 2
+		case 'B', 'b':
+			n := count
+
+			if all || n > len(rest)*8 {
+				n = len(rest) * 8
+			}
+
+			buf := make([]byte, n)
+
+			for k := 0; k < n; k++ {
+				shift := uint(k % 8)
+
+				if d == 'B' {
+					shift = 7 - shift
+				}
+
+				buf[k] = '0' + (rest[k/8]>>shift)&1
+			}
+
+			elems = append(elems, t.vm.initStringObject(string(buf)))
+			pos += (n + 7) / 8
+		case 'm':
+			decoded, err := base64.StdEncoding.DecodeString(strings.Replace(string(rest), "\n", "", -1))
+
+			if err != nil {
+				return nil, t.initErrorObject(errors.ArgumentError, "Invalid Base64 string: %s", err.Error())
+			}
+
+			elems = append(elems, t.vm.initStringObject(string(decoded)))
+			pos = len(data)
+		case 'U':
+			for k := 0; (all || k < count) && pos < len(data); k++ {
+				r, size := utf8.DecodeRune(data[pos:])
+
+				if r == utf8.RuneError && size <= 1 {
+					return nil, t.initErrorObject(errors.ArgumentError, "Malformed UTF-8 character")
+				}
+
+				elems = append(elems, t.vm.initIntegerObject(int(r)))
+				pos += size
+			}
+		case 'C', 'c', 'n', 'v', 'N', 'V':
+			size := 1
+
+			switch d {
+			case 'n', 'v':
+				size = 2
+			case 'N', 'V':
+				size = 4
+			}
+
+			for k := 0; all || k < count; k++ {
+				if pos+size > len(data) {
+					if all {
+						break
+					}
+
+					elems = append(elems, NULL)
+					continue
+				}
+
+				b := data[pos : pos+size]
+				var value int
+
+				switch d {
+				case 'C':
+					value = int(b[0])
+				case 'c':
+					value = int(int8(b[0]))
+				case 'n':
+					value = int(binary.BigEndian.Uint16(b))
+				case 'v':
+					value = int(binary.LittleEndian.Uint16(b))
+				case 'N':
+					value = int(binary.BigEndian.Uint32(b))
+				case 'V':
+					value = int(binary.LittleEndian.Uint32(b))
+				}
+
+				elems = append(elems, t.vm.initIntegerObject(value))
+				pos += size
+			}
+		default:
+			return nil, t.initErrorObject(errors.ArgumentError, "Unknown unpack directive: %c", d)
+		}
+	}
+
+	return elems, nil
+}
+
+// padding repeats the characters of pad until it's n characters long
+func padding(pad string, n int) string {
+	runes := []rune(pad)
+	result := make([]rune, n)
+
+	for i := range result {
+		result[i] = runes[i%len(runes)]
+	}
+
+	return string(result)
+}
+
+// splitLines splits str into lines like Ruby's String#lines, each line keeps its trailing "\n"
+func splitLines(str string) []string {
+	lines := []string{}
+
+	for len(str) > 0 {
+		i := strings.IndexByte(str, '\n')
+
+		if i < 0 {
+			lines = append(lines, str)
+			break
+		}
+
+		lines = append(lines, str[:i+1])
+		str = str[i+1:]
+	}
+
+	return lines
+}
+
+// yieldOrCollect yields each element to the block and returns the receiver if a block is given,
+// otherwise returns the elements as an array. It's shared by String#bytes, #chars and #lines.
+func yieldOrCollect(t *thread, receiver Object, elems []Object, blockFrame *callFrame) Object {
+	if blockFrame == nil {
+		return t.vm.initArrayObject(elems)
+	}
+
+	for _, elem := range elems {
+		t.builtinMethodYield(blockFrame, elem)
+	}
+
+	return receiver
+}
+
+// splitString splits str by the matches of re like Ruby's String#split, the captured groups of each
+// match are included in the result. See String#split for how limit works.
+func splitString(str string, re *regexp.Regexp, limit int) []string {
+	fields := []string{}
+
+	if str == "" {
+		return fields
+	}
+
+	begin := 0
+	splits := 0
+
+	for _, loc := range re.FindAllStringSubmatchIndex(str, -1) {
+		if limit > 0 && splits == limit-1 {
+			break
+		}
+
+		// Empty matches at the beginning or the end of the string don't split anything
+		if loc[0] == loc[1] && (loc[0] == 0 || loc[0] == len(str)) {
+			continue
+		}
+
+		fields = append(fields, str[begin:loc[0]])
+
+		for i := 2; i < len(loc); i += 2 {
+			if loc[i] >= 0 {
+				fields = append(fields, str[loc[i]:loc[i+1]])
+			}
+		}
+
+		begin = loc[1]
+		splits++
+	}
+
+	fields = append(fields, str[begin:])
+
+	if limit == 0 {
+		for len(fields) > 0 && fields[len(fields)-1] == "" {
+			fields = fields[:len(fields)-1]
+		}
+	}
+
+	return fields
+}
+
+// substitute replaces at most n (or all if n is negative) matches of the pattern in str, with either the
+// replacement string or the result of the given block. It's shared by String#gsub and String#sub.
+func substitute(t *thread, str string, args []Object, blockFrame *callFrame, n int) (string, bool, *Error) {
+	if blockFrame == nil && len(args) != 2 {
+		return "", false, t.initErrorObject(errors.ArgumentError, "Expect 2 arguments. got=%d", len(args))
+	}
+
+	if blockFrame != nil && len(args) != 1 {
+		return "", false, t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+	}
+
+	var re *regexp.Regexp
+
+	switch p := args[0].(type) {
+	case *RegexpObject:
+		re = p.regexp
+	case *StringObject:
+		re = regexp.MustCompile(regexp.QuoteMeta(p.value))
+	default:
+		return "", false, t.initErrorObject(errors.TypeError, "Expect pattern to be String or Regexp. got: %s", p.Class().Name)
+	}
+
+	var replacement string
+
+	if blockFrame == nil {
+		r := args[1]
+		replacementStr, ok := r.(*StringObject)
+
+		if !ok {
+			return "", false, t.initErrorObject(errors.TypeError, "Expect replacement to be String. got: %s", r.Class().Name)
+		}
+
+		replacement = replacementStr.value
+	}
+
+	matches := re.FindAllStringSubmatchIndex(str, n)
+
+	if len(matches) == 0 {
+		return str, false, nil
+	}
+
+	var result bytes.Buffer
+	last := 0
+
+	for _, loc := range matches {
+		result.WriteString(str[last:loc[0]])
+
+		if blockFrame != nil {
+			matched := t.vm.initStringObject(str[loc[0]:loc[1]])
+			result.WriteString(t.builtinMethodYield(blockFrame, matched).Target.toString())
+		} else {
+			result.WriteString(expandReplacement(re, str, replacement, loc))
+		}
+
+		last = loc[1]
+	}
+
+	result.WriteString(str[last:])
+
+	return result.String(), true, nil
+}
+
+// expandReplacement replaces back-references like \0, \1 or \k<name> in the replacement string with the captured groups
+func expandReplacement(re *regexp.Regexp, str, replacement string, loc []int) string {
+	var result bytes.Buffer
+
+	group := func(i int) string {
+		if i*2 >= len(loc) || loc[i*2] < 0 {
+			return ""
+		}
+		return str[loc[i*2]:loc[i*2+1]]
+	}
+
+	for i := 0; i < len(replacement); i++ {
+		c := replacement[i]
+
+		if c != '\\' || i+1 == len(replacement) {
+			result.WriteByte(c)
+			continue
+		}
+
+		next := replacement[i+1]
+
+		switch {
+		case '0' <= next && next <= '9':
+			result.WriteString(group(int(next - '0')))
+			i++
+		case next == '&':
+			result.WriteString(group(0))
+			i++
+		case next == '\\':
+			result.WriteByte('\\')
+			i++
+		case next == 'k' && i+2 < len(replacement) && replacement[i+2] == '<':
+			end := strings.IndexByte(replacement[i+3:], '>')
+
+			if end < 0 {
+				result.WriteByte(c)
+				continue
+			}
+
+			name := replacement[i+3 : i+3+end]
+
+			if index := re.SubexpIndex(name); index >= 0 {
+				result.WriteString(group(index))
+			}
+
+			i += 3 + end
+		default:
+			result.WriteByte(c)
+		}
+	}
+
+	return result.String()
+}
+
+// sprintf formats the arguments with the format string like C's printf. Each directive looks like
+// `%[flags][width][.precision]type`, where flags can be "-", "+", " ", "0" or "#", and supported types are:
+//
+// - `d`, `i`: Integer or BigInt
+// - `f`, `e`, `E`, `g`, `G`: Float, or Integer and BigInt formatted as a floating point number
+// - `x`, `X`, `o`, `b`: Integer or BigInt in hexadecimal, octal or binary
+// - `c`: Integer as a character code, or the first character of a String
+// - `s`: any object, converted with its `to_s` like `puts` does
+// - `%%`: a literal "%"
+func sprintf(t *thread, format string, args []Object) (string, *Error) {
+	var result bytes.Buffer
+	argIndex := 0
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			result.WriteByte(format[i])
+			continue
+		}
+
+		j := i + 1
+
+		for j < len(format) && strings.IndexByte("-+ 0#", format[j]) >= 0 {
+			j++
+		}
+
+		for j < len(format) && isDigit(format[j]) {
+			j++
+		}
+
+		if j < len(format) && format[j] == '.' {
+			j++
+
+			for j < len(format) && isDigit(format[j]) {
+				j++
+			}
+		}
+
+		if j >= len(format) {
+			return "", t.initErrorObject(errors.ArgumentError, "Incomplete format specifier: %s", format[i:])
+		}
+
+		verb := format[j]
+		directive := format[i:j]
+		i = j
+
+		if verb == '%' {
+			result.WriteByte('%')
+			continue
+		}
+
+		if argIndex >= len(args) {
+			return "", t.initErrorObject(errors.ArgumentError, "Too few arguments for format string: %s", format)
+		}
+
+		arg := args[argIndex]
+		argIndex++
+
+		switch verb {
+		case 'd', 'i', 'x', 'X', 'o', 'b':
+			if verb == 'i' {
+				verb = 'd'
+			}
+
+			switch n := arg.(type) {
+			case *IntegerObject:
+				result.WriteString(fmt.Sprintf(directive+string(verb), n.value))
+			case *BigIntObject:
+				result.WriteString(fmt.Sprintf(directive+string(verb), n.value))
+			default:
+				return "", t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+			}
+		case 'f', 'e', 'E', 'g', 'G':
+			var f float64
+
+			switch n := arg.(type) {
+			case *FloatObject:
+				f = n.value
+			case *IntegerObject:
+				f = float64(n.value)
+			case *BigIntObject:
+				f, _ = new(big.Float).SetInt(n.value).Float64()
+			default:
+				return "", t.initErrorObject(errors.TypeError, "Expect argument to be Float or Integer. got: %s", arg.Class().Name)
+			}
+
+			result.WriteString(fmt.Sprintf(directive+string(verb), f))
+		case 'c':
+			switch c := arg.(type) {
+			case *IntegerObject:
+				result.WriteString(fmt.Sprintf(directive+"c", rune(c.value)))
+			case *StringObject:
+				r, _ := utf8.DecodeRuneInString(c.value)
+				result.WriteString(fmt.Sprintf(directive+"c", r))
+			default:
+				return "", t.initErrorObject(errors.TypeError, "Expect argument to be Integer or String. got: %s", arg.Class().Name)
+			}
+		case 's':
+			s, err := t.objectToString(arg)
+			if err != nil {
+				return "", err
+			}
+
+			result.WriteString(fmt.Sprintf(directive+"s", s))
+		default:
+			return "", t.initErrorObject(errors.ArgumentError, "Malformed format string: %s", directive+string(verb))
+		}
+	}
+
+	if argIndex < len(args) {
+		return "", t.initErrorObject(errors.ArgumentError, "Too many arguments for format string: %s", format)
+	}
+
+	return result.String(), nil
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
 }
@@ -46,6 +46,68 @@ func TestEvalStringExpression(t *testing.T) {
 	}
 }
 
+func TestStringInterpolation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`
+		name = "Goby"
+		count = 3
+		"Hello #{name}, you have #{count * 2} items"
+		`, "Hello Goby, you have 6 items"},
+		{`"#{1}#{2}"`, "12"},
+		{`"#{nil}true"`, "true"},
+		{`"#{}"`, ""},
+		{`"#{[1, 2]} #{true}"`, "[1, 2] true"},
+		{`
+		h = { a: "Goby" }
+		"#{h["a"]}"
+		`, "Goby"},
+		{`"#{"inner #{1 + 1}"}"`, "inner 2"},
+		{`"\#{name}"`, "#{name}"},
+		{`"🍣#{"Goby"}🍺\n"`, "🍣Goby🍺\n"},
+		{`
+		class Foo
+		  def to_s
+		    "foo"
+		  end
+		end
+		"#{Foo.new}!"
+		`, "foo!"},
+		{`
+		def bar(s)
+		  s
+		end
+		bar "#{1 + 1}"
+		`, "2"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringInterpolationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		a = 1
+		"#{a.foo}"`, "UndefinedMethodError: Undefined Method 'foo' for 1", 3},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringConversion(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -62,6 +124,20 @@ func TestStringConversion(t *testing.T) {
 		{`"string".to_i`, 0},
 		{`"123string123".to_i`, 123},
 		{`"string123".to_i`, 0},
+		{`" -42".to_i`, -42},
+		{`"+42".to_i`, 42},
+		{`"1_000".to_i`, 1000},
+		{`"1__000".to_i`, 1},
+		{`"_1".to_i`, 0},
+		{`"ff".to_i(16)`, 255},
+		{`"0xff".to_i(16)`, 255},
+		{`"-0XFF".to_i(16)`, -255},
+		{`"0xff".to_i`, 0},
+		{`"1010".to_i(2)`, 10},
+		{`"0b1012".to_i(2)`, 5},
+		{`"0o17".to_i(8)`, 15},
+		{`"z".to_i(36)`, 35},
+		{`"9".to_i(8)`, 0},
 		{`
 		  arr = "Goby".to_a
 		  arr[0]
@@ -174,6 +250,13 @@ func TestStringComparison(t *testing.T) {
 		{`"123" > "1235"`, false},
 		{`"1234" < "123"`, false},
 		{`"1234" < "12jdkfj3"`, true},
+		{`"a" <= "a"`, true},
+		{`"a" <= "b"`, true},
+		{`"b" <= "a"`, false},
+		{`"a" >= "a"`, true},
+		{`"a" >= "b"`, false},
+		{`"🍣" > "一"`, true},
+		{`"一" >= "🍣"`, false},
 		{`"1234" <=> "1234"`, 0},
 		{`"1234" <=> "4"`, -1},
 		{`"abcdef" <=> "abcde"`, 1},
@@ -201,6 +284,8 @@ func TestStringComparisonFail(t *testing.T) {
 		{`"a" < 1`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"a" > 1`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"a" <=> 1`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"a" <= nil`, "TypeError: Expect argument to be String. got: Null", 1},
+		{`"a" >= 1`, "TypeError: Expect argument to be String. got: Integer", 1},
 	}
 	for i, tt := range testsFail {
 		v := initTestVM()
@@ -227,6 +312,8 @@ func TestStringOperation(t *testing.T) {
 		{`"Hello"[-6]`, nil},
 		{`"Hello🍣"[5]`, "🍣"},
 		{`"Hello🍣"[-1]`, "🍣"},
+		{`"🍣🍺"[2]`, nil},
+		{`"哈囉"[5]`, nil},
 		{`"Hello\nWorld"[5]`, "\n"},
 		{`"\"Maxwell\""[0]`, "\""},
 		{`"\"Maxwell\""[-1]`, "\""},
@@ -264,7 +351,7 @@ func TestStringOperationFail(t *testing.T) {
 		{`"Taipei" * (-101)`, "ArgumentError: Second argument must be greater than or equal to 0. got=-101", 1},
 		{`"Taipei"[1] = 1`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Taipei"[1] = true`, "TypeError: Expect argument to be String. got: Boolean", 1},
-		{`"Taipei"[]`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`"Taipei"[]`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
 		{`"Taipei"[true] = 101`, "TypeError: Expect argument to be Integer. got: Boolean", 1},
 	}
 
@@ -277,6 +364,44 @@ func TestStringOperationFail(t *testing.T) {
 	}
 }
 
+func TestStringFormatOperation(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"%05d" % 42`, "00042"},
+		{`"%s is %d years old" % ["Goby", 1]`, "Goby is 1 years old"},
+		{`"%-6s|" % "ab"`, "ab    |"},
+		{`"%s" % [[1, 2]]`, "[1, 2]"},
+		{`"%.1f%%" % 99.95`, "100.0%"},
+		{`"%.3e" % [12345.678]`, "1.235e+04"},
+		{`"100%%" % []`, "100%"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringFormatOperationFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"%d" % "Goby"`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`"%s %s" % ["Goby"]`, "ArgumentError: Too few arguments for format string: %s %s", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringCapitalizeMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -290,6 +415,7 @@ func TestStringCapitalizeMethod(t *testing.T) {
 		{`"all lower".capitalize`, "All lower"},
 		{`"heLlo\nWoRLd".capitalize`, "Hello\nworld"},
 		{`"🍣HeLlO🍺".capitalize`, "🍣hello🍺"},
+		{`"".capitalize`, ""},
 	}
 
 	for i, tt := range tests {
@@ -301,6 +427,89 @@ func TestStringCapitalizeMethod(t *testing.T) {
 	}
 }
 
+func TestStringCasecmpMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"goby".casecmp("GOBY")`, 0},
+		{`"Goby".casecmp("goby")`, 0},
+		{`"a".casecmp("B")`, -1},
+		{`"b".casecmp("A")`, 1},
+		{`"abc".casecmp("ABCD")`, -1},
+		{`"goby".casecmp?("GOBY")`, true},
+		{`"ÄÖÜ".casecmp?("äöü")`, true},
+		{`"goby".casecmp?("ruby")`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringCasecmpMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"goby".casecmp`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`"goby".casecmp(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"goby".casecmp?("a", "b")`, "ArgumentError: Expect 1 argument. got=2", 1},
+		{`"goby".casecmp?(nil)`, "TypeError: Expect argument to be String. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringCenterMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Hello".center(2)`, "Hello"},
+		{`"Hello".center(5)`, "Hello"},
+		{`"Hello".center(9)`, "  Hello  "},
+		{`"Hello".center(8)`, " Hello  "},
+		{`"Hello".center(10, "xo")`, "xoHelloxox"},
+		{`"Hello".center(10, "🍣🍺")`, "🍣🍺Hello🍣🍺🍣"},
+		{`"哈囉".center(6, "*")`, "**哈囉**"},
+		{`"".center(3, "ab")`, "aab"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringCenterMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Hello".center`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`"Hello".center(1, 2, 3)`, "ArgumentError: Expect 1..2 arguments. got=3", 1},
+		{`"Hello".center("10")`, "TypeError: Expect justify width to be Integer. got: String", 1},
+		{`"Hello".center(10, 10)`, "TypeError: Expect padding string to be String. got: Integer", 1},
+		{`"Hello".center(10, "")`, "ArgumentError: Zero width padding", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringChopMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -309,6 +518,9 @@ func TestStringChopMethod(t *testing.T) {
 		{`"Hello".chop`, "Hell"},
 		{`"Hello\n".chop`, "Hello"},
 		{`"Hello🍣".chop`, "Hello"},
+		{`"Hello\r\n".chop`, "Hello"},
+		{`"Hello\n\r".chop`, "Hello\n"},
+		{`"".chop`, ""},
 	}
 
 	for i, tt := range tests {
@@ -381,8 +593,11 @@ func TestStringDeleteMethod(t *testing.T) {
 		input    string
 		expected interface{}
 	}{
-		{`"Hello hello HeLlo".delete("el")`, "Hlo hlo HeLlo"},
+		{`"Hello hello HeLlo".delete("el")`, "Ho ho HLo"},
 		{`"Hello 🍣 Hello 🍣 Hello".delete("🍣")`, "Hello  Hello  Hello"},
+		{`"Hello World".delete("a-z", "^o")`, "Ho Wo"},
+		{`"Hello World".delete("^a-z")`, "elloorld"},
+		{`"a-b^c".delete("\\-^")`, "abc"},
 	}
 
 	for i, tt := range tests {
@@ -396,7 +611,8 @@ func TestStringDeleteMethod(t *testing.T) {
 
 func TestStringDeleteMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Hello hello HeLlo".delete`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`"Hello hello HeLlo".delete`, "ArgumentError: Expect at least 1 argument. got=0", 1},
+		{`"Hello hello HeLlo".delete("z-a")`, "ArgumentError: Invalid range \"z-a\" in string transliteration", 1},
 		{`"Hello hello HeLlo".delete(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Hello hello HeLlo".delete(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
 		{`"Hello hello HeLlo".delete(nil)`, "TypeError: Expect argument to be String. got: Null", 1},
@@ -431,6 +647,233 @@ func TestStringDowncaseMethod(t *testing.T) {
 	}
 }
 
+func TestStringBytesCharsLinesMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []interface{}
+	}{
+		{`"Goby".bytes`, []interface{}{71, 111, 98, 121}},
+		{`"😊".bytes`, []interface{}{240, 159, 152, 138}},
+		{`"".bytes`, []interface{}{}},
+		{`"Goby".chars`, []interface{}{"G", "o", "b", "y"}},
+		{`"哈囉🍣".chars`, []interface{}{"哈", "囉", "🍣"}},
+		{`"".chars`, []interface{}{}},
+		{`"Hello\nWorld\n".lines`, []interface{}{"Hello\n", "World\n"}},
+		{`"Hello\nGoby".lines`, []interface{}{"Hello\n", "Goby"}},
+		{`"\n\nGoby".lines`, []interface{}{"\n", "\n", "Goby"}},
+		{`"".lines`, []interface{}{}},
+		{`
+		arr = []
+		"Goby".chars do |c|
+		  arr.push(c.upcase)
+		end
+		arr
+		`, []interface{}{"G", "O", "B", "Y"}},
+		{`
+		arr = []
+		"a\nb".lines do |l|
+		  arr.push(l.size)
+		end
+		arr
+		`, []interface{}{2, 1}},
+		{`
+		arr = []
+		"ab".bytes do |b|
+		  arr.push(b)
+		end
+		arr
+		`, []interface{}{97, 98}},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		testArrayObject(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringBytesCharsLinesMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".bytes(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`"Goby".chars(1, 2)`, "ArgumentError: Expect 0 argument. got=2", 1},
+		{`"Goby".lines("\n")`, "ArgumentError: Expect 0 argument. got=1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringEncodingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".bytesize`, 4},
+		{`"😊".bytesize`, 4},
+		{`"哈囉".bytesize`, 6},
+		{`"".bytesize`, 0},
+		{`"Goby".encoding.name`, "UTF-8"},
+		{`"Goby".encoding == Encoding::UTF_8`, true},
+		{`"Goby".force_encoding("BINARY").encoding.name`, "ASCII-8BIT"},
+		{`"Goby".force_encoding(Encoding::US_ASCII).encoding.name`, "US-ASCII"},
+		{`"Goby".force_encoding("us-ascii").force_encoding("UTF-8").encoding.name`, "UTF-8"},
+		{`
+		s = "Goby"
+		s.force_encoding("ASCII-8BIT")
+		s.encoding.name
+		`, "ASCII-8BIT"},
+		{`"😊".force_encoding("BINARY").length`, 1},
+		{`"Goby 😊".valid_encoding?`, true},
+		{`"Goby".force_encoding("US-ASCII").valid_encoding?`, true},
+		{`"Goby 😊".force_encoding("US-ASCII").valid_encoding?`, false},
+		{`"Goby 😊".force_encoding("BINARY").valid_encoding?`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringEncodingMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".force_encoding("Big5")`, "ArgumentError: Unknown encoding name: Big5", 1},
+		{`"Goby".force_encoding(1)`, "TypeError: Expect encoding to be String or Encoding. got: Integer", 1},
+		{`"Goby".force_encoding`, "ArgumentError: Expect 1 argument. got=0", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringMutationMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		s = "Hello"
+		s << " " << "Goby"
+		s
+		`, "Hello Goby"},
+		{`
+		s = "Hello"
+		t = s
+		t << "🍣"
+		s
+		`, "Hello🍣"},
+		{`
+		s = "Hello"
+		s.concat(" World")
+		s
+		`, "Hello World"},
+		{`
+		s = "Hello"
+		s.insert(0, "X")
+		s.insert(6, "Y")
+		s
+		`, "XHelloY"},
+		{`
+		s = "Hello"
+		s.replace("Goby")
+		s
+		`, "Goby"},
+		{`
+		s = "Ruby"
+		s[0] = "G"
+		s
+		`, "Guby"},
+		{`
+		s = "Goby"
+		s.clear
+		s
+		`, ""},
+		{`
+		s = "Goby"
+		s.upcase
+		s
+		`, "Goby"},
+		{`
+		s = "goby"
+		s.upcase!
+		s
+		`, "GOBY"},
+		{`"GOBY".upcase!`, nil},
+		{`
+		s = "GOBY"
+		s.downcase!
+		s
+		`, "goby"},
+		{`"goby".downcase!`, nil},
+		{`
+		s = "gOBY"
+		s.capitalize!
+		s
+		`, "Goby"},
+		{`"Goby".capitalize!`, nil},
+		{`"".capitalize!`, nil},
+		{`
+		s = "Goby🍣"
+		s.reverse!
+		s
+		`, "🍣yboG"},
+		{`"aba".reverse!`, nil},
+		{`
+		s = "  Goby  "
+		s.strip!
+		s
+		`, "Goby"},
+		{`"Goby".strip!`, nil},
+		{`
+		def add_suffix(s)
+		  s << "!"
+		end
+		s = "Goby"
+		add_suffix(s)
+		s
+		`, "Goby!"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringMutationMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby" << 1`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"Goby" << nil`, "TypeError: Expect argument to be String. got: Null", 1},
+		{`"Goby".clear(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringEachByteMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -468,7 +911,6 @@ func TestStringEachByteMethodFail(t *testing.T) {
 		  puts byte
 		end
 		`, "ArgumentError: Expect 0 argument. got=1", 2},
-		{`"Taipei".each_byte`, "InternalError: Can't yield without a block", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -517,7 +959,6 @@ func TestStringEachCharMethodFail(t *testing.T) {
 		  puts char
 		end
 		`, "ArgumentError: Expect 0 argument. got=1", 2},
-		{`"Taipei".each_char`, "InternalError: Can't yield without a block", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -540,14 +981,21 @@ func TestStringEachLineMethod(t *testing.T) {
 		  arr.push(line)
 		end
 		arr
-		`, []interface{}{"Hello", "World", "Goby"}},
+		`, []interface{}{"Hello\n", "World\n", "Goby"}},
 		{`
 		arr = []
 		"Max\vwell\nAlex\fius".each_line do |line|
 		  arr.push(line)
 		end
 		arr
-		`, []interface{}{"Max\vwell", "Alex\fius"}},
+		`, []interface{}{"Max\vwell\n", "Alex\fius"}},
+		{`
+		arr = []
+		"Hello\n\nGoby\n".each_line do |line|
+		  arr.push(line)
+		end
+		arr
+		`, []interface{}{"Hello\n", "\n", "Goby\n"}},
 	}
 
 	for i, tt := range tests {
@@ -566,7 +1014,6 @@ func TestStringEachLineMethodFail(t *testing.T) {
 		  puts line
 		end
 		`, "ArgumentError: Expect 0 argument. got=1", 2},
-		{`"Taipei".each_line`, "InternalError: Can't yield without a block", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -591,6 +1038,10 @@ func TestStringEndWithMethod(t *testing.T) {
 		{`"哈囉！世界！".end_with?("哈囉！")`, false},
 		{`"🍣Hello🍺".end_with?("🍺")`, true},
 		{`"🍣Hello🍺".end_with?("🍣")`, false},
+		{`"Taipei".end_with?("1", "0", "1")`, false},
+		{`"Taipei".end_with?("ei", "pei")`, true},
+		{`"Taipei".end_with?("x", "pei")`, true},
+		{`"Taipei".end_with?("")`, true},
 	}
 
 	for i, tt := range tests {
@@ -604,7 +1055,8 @@ func TestStringEndWithMethod(t *testing.T) {
 
 func TestStringEndWithMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Taipei".end_with?("1", "0", "1")`, "ArgumentError: Expect 1 argument. got=3", 1},
+		{`"Taipei".end_with?`, "ArgumentError: Expect at least 1 argument. got=0", 1},
+		{`"Taipei".end_with?(1, "pei")`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Taipei".end_with?(101)`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Hello".end_with?(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
 		{`"Hello".end_with?(1..5)`, "TypeError: Expect argument to be String. got: Range", 1},
@@ -684,6 +1136,30 @@ func TestStringGlobalSubstituteMethod(t *testing.T) {
 		{`"Hello World".gsub(" ", "\n")`, "Hello\nWorld"},
 		{`"Hello World".gsub("Hello", "Goby")`, "Goby World"},
 		{`"Hello 🍣 Hello 🍣 Hello".gsub("🍣", "🍺")`, "Hello 🍺 Hello 🍺 Hello"},
+		{`"a.b.c".gsub(".", "-")`, "a-b-c"},
+		{`"Goby Lang".gsub(/[aeiou]/, "*")`, "G*by L*ng"},
+		{`"John Smith".gsub(/(\w+) (\w+)/, '\2, \1')`, "Smith, John"},
+		{`"John Smith".gsub(/(\w+) (\w+)/, "\\2 \\0")`, "Smith John Smith"},
+		{`"John Smith".gsub(/(?<first>\w+) (?<last>\w+)/, '\k<last> \k<first>')`, "Smith John"},
+		{`"Goby".gsub(/o/, '[\&]')`, "G[o]by"},
+		{`"Goby".gsub(/z/, "*")`, "Goby"},
+		{`
+		"a1b22".gsub(/\d+/) do |num|
+		  num.to_i * 2
+		end
+		`, "a2b44"},
+		{`
+		s = "Goby Lang"
+		s.gsub!(/[aeiou]/, "*")
+		s
+		`, "G*by L*ng"},
+		{`"Goby".gsub!(/z/, "*")`, nil},
+		{`
+		s = "Goby"
+		s.gsub!("o") do |c|
+		  c.upcase
+		end
+		`, "GOby"},
 	}
 
 	for i, tt := range tests {
@@ -699,8 +1175,14 @@ func TestStringGlobalSubstituteMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
 		{`"Ruby".gsub()`, "ArgumentError: Expect 2 arguments. got=0", 1},
 		{`"Ruby".gsub("Ru")`, "ArgumentError: Expect 2 arguments. got=1", 1},
-		{`"Ruby".gsub(123, "Go")`, "TypeError: Expect pattern to be String. got: Integer", 1},
+		{`"Ruby".gsub(123, "Go")`, "TypeError: Expect pattern to be String or Regexp. got: Integer", 1},
 		{`"Ruby".gsub("Ru", 456)`, "TypeError: Expect replacement to be String. got: Integer", 1},
+		{`"Ruby".gsub!(/R/)`, "ArgumentError: Expect 2 arguments. got=1", 1},
+		{`
+		"Ruby".gsub("R", "G") do |c|
+		  c
+		end
+		`, "ArgumentError: Expect 1 argument. got=2", 2},
 	}
 
 	for i, tt := range testsFail {
@@ -807,6 +1289,8 @@ func TestStringLeftJustifyMethod(t *testing.T) {
 		{`"Hello".ljust(7)`, "Hello  "},
 		{`"Hello".ljust(10, "xo")`, "Helloxoxox"},
 		{`"Hello".ljust(10, "🍣🍺")`, "Hello🍣🍺🍣🍺🍣"},
+		{`"哈囉".ljust(5, "!")`, "哈囉!!!"},
+		{`"Hello".ljust(-1)`, "Hello"},
 	}
 
 	for i, tt := range tests {
@@ -828,6 +1312,7 @@ func TestStringLeftJustifyMethodFail(t *testing.T) {
 		{`"Hello".ljust(10, 10)`, "TypeError: Expect padding string to be String. got: Integer", 1},
 		{`"Hello".ljust(10, 2..5)`, "TypeError: Expect padding string to be String. got: Range", 1},
 		{`"Hello".ljust(10, true)`, "TypeError: Expect padding string to be String. got: Boolean", 1},
+		{`"Hello".ljust(10, "")`, "ArgumentError: Zero width padding", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -858,6 +1343,52 @@ func TestStringLengthMethod(t *testing.T) {
 	}
 }
 
+func TestStringMatchMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby" =~ /by/`, 2},
+		{`"Goby" =~ /foo/`, nil},
+		{`"你好Goby" =~ /Go/`, 2},
+		{`"Goby Lang".match(/(\w+) (\w+)/)[2]`, "Lang"},
+		{`"Goby Lang".match(/(?<name>\w+) (\w+)/)["name"]`, "Goby"},
+		{`"Goby Lang".match(/(\w+) (\w+)/).captures.join(",")`, "Goby,Lang"},
+		{`"Goby".match("o.").to_s`, "ob"},
+		{`"Goby".match(/z/)`, nil},
+		{`
+		if "Goby" =~ /G/
+		  "matched"
+		end
+		`, "matched"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringMatchMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby" =~ "G"`, "TypeError: Expect argument to be Regexp. got: String", 1},
+		{`"Goby".match(1)`, "TypeError: Expect pattern to be Regexp or String. got: Integer", 1},
+		{`"Goby".match("(")`, "ArgumentError: Invalid regexp /(/: error parsing regexp: missing closing ): `(`", 1},
+		{`"Goby".match`, "ArgumentError: Expect 1 argument. got=0", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringReplaceMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -904,6 +1435,8 @@ func TestStringReverseMethod(t *testing.T) {
 		{`"-123".reverse`, "321-"},
 		{`"Hello\nWorld".reverse`, "dlroW\nolleH"},
 		{`"Hello 🍣🍺 World".reverse`, "dlroW 🍺🍣 olleH"},
+		{`"".reverse`, ""},
+		{`"哈囉！".reverse`, "！囉哈"},
 	}
 
 	for i, tt := range tests {
@@ -924,6 +1457,7 @@ func TestStringRightJustifyMethod(t *testing.T) {
 		{`"Hello".rjust(7)`, "  Hello"},
 		{`"Hello".rjust(10, "xo")`, "xoxoxHello"},
 		{`"Hello".rjust(10, "🍣🍺")`, "🍣🍺🍣🍺🍣Hello"},
+		{`"哈囉".rjust(5)`, "   哈囉"},
 	}
 
 	for i, tt := range tests {
@@ -945,6 +1479,7 @@ func TestStringRightJustifyFail(t *testing.T) {
 		{`"Hello".rjust(10, 10)`, "TypeError: Expect padding string to be String. got: Integer", 1},
 		{`"Hello".rjust(10, 2..5)`, "TypeError: Expect padding string to be String. got: Range", 1},
 		{`"Hello".rjust(10, true)`, "TypeError: Expect padding string to be String. got: Boolean", 1},
+		{`"Hello".rjust(10, "")`, "ArgumentError: Zero width padding", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -1011,6 +1546,53 @@ func TestStringSliceMethod(t *testing.T) {
 		{`"Hello 🍣🍺 World".slice(-10)`, "o"},
 		{`"Hello 🍣🍺 World".slice(-15)`, nil},
 		{`"Hello 🍣🍺 World".slice(14)`, nil},
+		{`"1234567890".slice(5..20)`, "67890"},
+		{`"1234567890".slice(10..20)`, ""},
+		{`"1234567890".slice(2, 3)`, "345"},
+		{`"1234567890".slice(8, 5)`, "90"},
+		{`"1234567890".slice(10, 5)`, ""},
+		{`"1234567890".slice(11, 5)`, nil},
+		{`"1234567890".slice(-3, 2)`, "89"},
+		{`"1234567890".slice(-11, 2)`, nil},
+		{`"1234567890".slice(2, -1)`, nil},
+		{`"1234567890".slice(/[5-7]+/)`, "567"},
+		{`"1234567890".slice(/a/)`, nil},
+		{`"Hello 🍣🍺 World".slice(/🍣./)`, "🍣🍺"},
+		{`"Hello World".slice(/(\w+) (\w+)/, 2)`, "World"},
+		{`"Hello World".slice(/(\w+) (\w+)/, 3)`, nil},
+		{`"Hello"[1, 3]`, "ell"},
+		{`"Hello"[1..3]`, "ell"},
+		{`"Hello"[-3..-1]`, "llo"},
+		{`"Hello"[/l+o/]`, "llo"},
+		{`
+		s = "Hello World"
+		r = s.slice!(5..-1)
+		r + "|" + s
+		`, " World|Hello"},
+		{`
+		s = "Hello 🍣🍺 World"
+		r = s.slice!(/🍣🍺 /)
+		r + "|" + s
+		`, "🍣🍺 |Hello World"},
+		{`
+		s = "Hello"
+		r = s.slice!(1, 2)
+		r + "|" + s
+		`, "el|Hlo"},
+		{`
+		s = "Hello"
+		s.slice!(-1)
+		s
+		`, "Hell"},
+		{`
+		s = "Hello"
+		s.slice!(10)
+		`, nil},
+		{`
+		s = "Hello"
+		s.slice!(/x/)
+		s
+		`, "Hello"},
 	}
 
 	for i, tt := range tests {
@@ -1024,9 +1606,206 @@ func TestStringSliceMethod(t *testing.T) {
 
 func TestStringSliceMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Goby Lang".slice`, "ArgumentError: Expect 1 argument. got=0", 1},
-		{`"Goby Lang".slice("Hello")`, "TypeError: Expect slice range to be Range or Integer. got: String", 1},
-		{`"Goby Lang".slice(true)`, "TypeError: Expect slice range to be Range or Integer. got: Boolean", 1},
+		{`"Goby Lang".slice`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`"Goby Lang".slice(1, 2, 3)`, "ArgumentError: Expect 1..2 arguments. got=3", 1},
+		{`"Goby Lang".slice("Hello")`, "TypeError: Expect slice range to be Range, Integer or Regexp. got: String", 1},
+		{`"Goby Lang".slice(true)`, "TypeError: Expect slice range to be Range, Integer or Regexp. got: Boolean", 1},
+		{`"Goby Lang".slice(1..2, 1)`, "ArgumentError: Expect 1 argument. got=2", 1},
+		{`"Goby Lang".slice(1, "2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`"Goby Lang".slice(/o/, "2")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`"Goby Lang".slice!`, "ArgumentError: Expect 1..2 arguments. got=0", 1},
+		{`"Goby Lang".slice!(nil)`, "TypeError: Expect slice range to be Range, Integer or Regexp. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringUnpackMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Goby".unpack("C*").to_s`, "[71, 111, 98, 121]"},
+		{`"Goby".unpack("C2").to_s`, "[71, 111]"},
+		{`"A".unpack("C2").to_s`, "[65, nil]"},
+		{`"🍣".unpack("c").to_s`, "[-16]"},
+		{`"Goby".unpack("a2 a2").to_s`, `["Go", "by"]`},
+		{`"Goby  ".unpack("A*").to_s`, `["Goby"]`},
+		{`"Goby  ".unpack("a*").to_s`, `["Goby  "]`},
+		{`"Goby".unpack("H*").to_s`, `["476f6279"]`},
+		{`"Goby".unpack("h3").to_s`, `["74f"]`},
+		{`"G".unpack("B*").to_s`, `["01000111"]`},
+		{`"G".unpack("b4").to_s`, `["1110"]`},
+		{`"ABCD".unpack("n v").to_s`, "[16706, 17475]"},
+		{`"ABCD".unpack("N").to_s`, "[1094861636]"},
+		{`"ABCD".unpack("V").to_s`, "[1145258561]"},
+		{`"ABC".unpack("n*").to_s`, "[16706]"},
+		{`"🍣!".unpack("U*").to_s`, "[127843, 33]"},
+		{`"R29ieQ==".unpack("m").to_s`, `["Goby"]`},
+		{`"Goby".unpack("C a*").to_s`, `[71, "oby"]`},
+		{`"Goby".unpack1("H*")`, "476f6279"},
+		{`"AB".unpack1("n")`, 16706},
+		{`"".unpack1("a")`, ""},
+		{`"".unpack1("U")`, nil},
+		{`"ff".hex`, 255},
+		{`"0x1A".hex`, 26},
+		{`"-ff".hex`, -255},
+		{`"goby".hex`, 0},
+		{`"Goby".b64encode`, "R29ieQ=="},
+		{`"🍣".b64encode`, "8J+Now=="},
+		{`"R29ieQ==".b64decode`, "Goby"},
+		{`"8J+Now==".b64decode`, "🍣"},
+		{`"".b64encode`, ""},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringUnpackMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".unpack`, "ArgumentError: Expect 1 argument. got=0", 1},
+		{`"Goby".unpack(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"Goby".unpack("Z")`, "ArgumentError: Unknown unpack directive: Z", 1},
+		{`"Goby".unpack1("C", "C")`, "ArgumentError: Expect 1 argument. got=2", 1},
+		{`"Goby!".unpack1("m")`, "ArgumentError: Invalid Base64 string: illegal base64 data at input byte 4", 1},
+		{`"Goby!".b64decode`, "ArgumentError: Invalid Base64 string: illegal base64 data at input byte 4", 1},
+		{`"Goby".b64encode(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`"ff".hex(16)`, "ArgumentError: Expect 0 argument. got=1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringTrimmingMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"  Goby Lang  ".lstrip`, "Goby Lang  "},
+		{`"\t　Goby".lstrip`, "Goby"},
+		{`"  Goby Lang  ".rstrip`, "  Goby Lang"},
+		{`"Goby\r\n ".rstrip`, "Goby"},
+		{`"Goby\n".chomp`, "Goby"},
+		{`"Goby\r\n".chomp`, "Goby"},
+		{`"Goby\r".chomp`, "Goby"},
+		{`"Goby\n\n".chomp`, "Goby\n"},
+		{`"Goby\r\n\n".chomp("")`, "Goby"},
+		{`"Goby\r".chomp("")`, "Goby\r"},
+		{`"Goby".chomp("by")`, "Go"},
+		{`"Goby".chomp("go")`, "Goby"},
+		{`"Goby".chomp`, "Goby"},
+		{`"  Goby \n\t Lang  ".squish`, "Goby Lang"},
+		{`"　Goby　　Lang".squish`, "Goby Lang"},
+		{`"".squish`, ""},
+		{`
+		s = "  Goby  "
+		s.lstrip!
+		s.rstrip!
+		s
+		`, "Goby"},
+		{`"Goby".lstrip!`, nil},
+		{`"Goby".rstrip!`, nil},
+		{`
+		s = "Goby\n"
+		s.chomp!
+		s
+		`, "Goby"},
+		{`"Goby".chomp!`, nil},
+		{`"Goby".chomp!("by")`, "Go"},
+		{`
+		s = "Goby"
+		s.chop!
+		s
+		`, "Gob"},
+		{`"".chop!`, nil},
+		{`
+		s = " Goby   Lang "
+		s.squish!
+		s
+		`, "Goby Lang"},
+		{`"Goby Lang".squish!`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringTrimmingMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".chomp("a", "b")`, "ArgumentError: Expect 0..1 argument. got=2", 1},
+		{`"Goby".chomp(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"Goby".chomp!(nil)`, "TypeError: Expect argument to be String. got: Null", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringSuccMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abcd".succ`, "abce"},
+		{`"az".succ`, "ba"},
+		{`"zz".succ`, "aaa"},
+		{`"a9".succ`, "b0"},
+		{`"Zz".succ`, "AAa"},
+		{`"1.9".succ`, "2.0"},
+		{`"-9".succ`, "-10"},
+		{`"az!".succ`, "ba!"},
+		{`"***".succ`, "**+"},
+		{`"🍣a".succ`, "🍣b"},
+		{`"".succ`, ""},
+		{`"zz".next`, "aaa"},
+		{`
+		s = "a"
+		s.succ
+		s
+		`, "a"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringSuccMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"a".succ(1)`, "ArgumentError: Expect 0 argument. got=1", 1},
+		{`"a".next(1, 2)`, "ArgumentError: Expect 0 argument. got=2", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -1107,6 +1886,19 @@ func TestStringSplitMethod(t *testing.T) {
 		arr = "Hello🍺World🍣Goby".split("🍺")
 		arr[1]
 		`, "World🍣Goby"},
+		{`"  a  b c ".split.join(",")`, "a,b,c"},
+		{`" a\tb\n c ".split(" ").join(",")`, "a,b,c"},
+		{`"a b c".split(nil, 2).join(",")`, "a,b c"},
+		{`"a1b22c".split(/\d+/).join(",")`, "a,b,c"},
+		{`"a-b_c".split(/([-_])/).join(",")`, "a,-,b,_,c"},
+		{`"🍣🍺".split(//).length`, 2},
+		{`"a,b,c".split(",", 2).join("|")`, "a|b,c"},
+		{`"a,b,c".split(",", 1).join("|")`, "a,b,c"},
+		{`",a,,b,,".split(",").join("|")`, "|a||b"},
+		{`",a,,b,,".split(",", -1).join("|")`, "|a||b||"},
+		{`",a,,b,,".split(",", -1).length`, 6},
+		{`"".split(",").length`, 0},
+		{`",,".split(",").length`, 0},
 	}
 
 	for i, tt := range tests {
@@ -1120,10 +1912,47 @@ func TestStringSplitMethod(t *testing.T) {
 
 func TestStringSplitMethodFail(t *testing.T) {
 	testsFail := []errorTestCase{
-		{`"Hello World".split`, "ArgumentError: Expect 1 argument. got=0", 1},
-		{`"Hello World".split(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
-		{`"Hello World".split(123)`, "TypeError: Expect argument to be String. got: Integer", 1},
-		{`"Hello World".split(1..2)`, "TypeError: Expect argument to be String. got: Range", 1},
+		{`"Hello World".split(",", 1, 2)`, "ArgumentError: Expect 0..2 arguments. got=3", 1},
+		{`"Hello World".split(true)`, "TypeError: Expect separator to be String or Regexp. got: Boolean", 1},
+		{`"Hello World".split(123)`, "TypeError: Expect separator to be String or Regexp. got: Integer", 1},
+		{`"Hello World".split(1..2)`, "TypeError: Expect separator to be String or Regexp. got: Range", 1},
+		{`"Hello World".split(",", "1")`, "TypeError: Expect limit to be Integer. got: String", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringSqueezeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"yellow  moon".squeeze`, "yelow mon"},
+		{`"  now   is  the".squeeze(" ")`, " now is the"},
+		{`"putters shoot balls".squeeze("m-z")`, "puters shot balls"},
+		{`"aaabbbccc".squeeze("a-z", "^b")`, "abbbc"},
+		{`"🍣🍣🍺🍺".squeeze`, "🍣🍺"},
+		{`"".squeeze`, ""},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringSqueezeMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".squeeze(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -1148,6 +1977,19 @@ func TestStringStartWithMethod(t *testing.T) {
 		{`"哈囉！世界".start_with("世界！")`, false},
 		{`"🍣Hello🍺".start_with("🍣")`, true},
 		{`"🍣Hello🍺".start_with("🍺")`, false},
+		{`"Hello".start_with?("Hel")`, true},
+		{`"Hello".start_with?("Hello")`, true},
+		{`"Hello".start_with?("Hello ")`, false},
+		{`"哈囉！世界！".start_with?("哈囉！")`, true},
+		{`"Hello".start_with?("hel")`, false},
+		{`"哈囉！世界".start_with?("世界！")`, false},
+		{`"🍣Hello🍺".start_with?("🍣")`, true},
+		{`"🍣Hello🍺".start_with?("🍺")`, false},
+		{`"Taipei".start_with?("1", "0", "1")`, false},
+		{`"Taipei".start_with?("x", "Tai")`, true},
+		{`"Taipei".start_with?(/t/i)`, true},
+		{`"Taipei".start_with?(/ai/)`, false},
+		{`"Taipei".start_with?(/x/, "T")`, true},
 	}
 
 	for i, tt := range tests {
@@ -1165,6 +2007,10 @@ func TestStringStartWithMethodFail(t *testing.T) {
 		{`"Taipei".start_with(101)`, "TypeError: Expect argument to be String. got: Integer", 1},
 		{`"Hello".start_with(true)`, "TypeError: Expect argument to be String. got: Boolean", 1},
 		{`"Hello".start_with(1..5)`, "TypeError: Expect argument to be String. got: Range", 1},
+		{`"Taipei".start_with?`, "ArgumentError: Expect at least 1 argument. got=0", 1},
+		{`"Taipei".start_with?(101)`, "TypeError: Expect argument to be String or Regexp. got: Integer", 1},
+		{`"Hello".start_with?(true)`, "TypeError: Expect argument to be String or Regexp. got: Boolean", 1},
+		{`"Hello".start_with?(1..5)`, "TypeError: Expect argument to be String or Regexp. got: Range", 1},
 	}
 
 	for i, tt := range testsFail {
@@ -1184,6 +2030,82 @@ func TestStringStripMethod(t *testing.T) {
 		{`"  Goby Lang   ".strip`, "Goby Lang"},
 		{`"\nGoby Lang\r\t".strip`, "Goby Lang"},
 		{`" \t 🍣 Goby Lang 🍺 \r\n ".strip`, "🍣 Goby Lang 🍺"},
+		{`"Goby\n\n".strip`, "Goby"},
+		{`"Goby \r\n".strip`, "Goby"},
+		{`"　Goby ".strip`, "Goby"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringSubstituteMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"Ruby Ruby".sub("Ru", "Go")`, "Goby Ruby"},
+		{`"Goby Lang".sub(/[aeiou]/, "*")`, "G*by Lang"},
+		{`"John Smith".sub(/(?<first>\w+)/, '\k<first>!')`, "John! Smith"},
+		{`"John Smith".sub(/(\w+)(z)?/, '\2\1')`, "John Smith"},
+		{`"Goby".sub(/z/, "*")`, "Goby"},
+		{`
+		"Goby".sub(/o/) do |c|
+		  c.upcase
+		end
+		`, "GOby"},
+		{`
+		s = "Goby Lang"
+		s.sub!(/[aeiou]/, "*")
+		s
+		`, "G*by Lang"},
+		{`"Goby".sub!("z", "*")`, nil},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringSubstituteMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Ruby".sub("Ru")`, "ArgumentError: Expect 2 arguments. got=1", 1},
+		{`"Ruby".sub(nil, "Go")`, "TypeError: Expect pattern to be String or Regexp. got: Null", 1},
+		{`"Ruby".sub!(/R/, 1)`, "TypeError: Expect replacement to be String. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringTrMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"hello".tr("el", "ip")`, "hippo"},
+		{`"hello".tr("aeiou", "*")`, "h*ll*"},
+		{`"hello".tr("a-y", "b-z")`, "ifmmp"},
+		{`"hello".tr("^l", "*")`, "**ll*"},
+		{`"hello".tr("l", "")`, "heo"},
+		{`"hello".tr("lo", "x^")`, "hexx^"},
+		{`"hello".tr("^", "x")`, "hello"},
+		{`"Goby🍣".tr("🍣", "🍺")`, "Goby🍺"},
+		{`"日本語".tr("日本", "にほ")`, "にほ語"},
 	}
 
 	for i, tt := range tests {
@@ -1195,6 +2117,22 @@ func TestStringStripMethod(t *testing.T) {
 	}
 }
 
+func TestStringTrMethodFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`"Goby".tr("a")`, "ArgumentError: Expect 2 arguments. got=1", 1},
+		{`"Goby".tr("a", 1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`"Goby".tr("z-a", "b")`, "ArgumentError: Invalid range \"z-a\" in string transliteration", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
 func TestStringUpcaseMethod(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -1250,3 +2188,133 @@ func TestFormattedString(t *testing.T) {
 		v.checkSP(t, i, 1)
 	}
 }
+
+func TestStringHeredoc(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		<<~SQL
+		  SELECT *
+		    FROM users
+		SQL
+		`, "SELECT *\n  FROM users\n"},
+		{`
+		<<-EOS
+		  Hello
+		  EOS
+		`, "\t\t  Hello\n"},
+		{`
+		<<~EOS
+
+		  a
+
+		    b
+		EOS
+		`, "\na\n\n  b\n"},
+		{`
+		name = "Goby"
+		<<~HTML
+		  <p class="name">#{name.upcase}</p>
+		  <p>#{ { a: "}" }["a"] }</p>
+		HTML
+		`, "<p class=\"name\">GOBY</p>\n<p>}</p>\n"},
+		{`
+		<<~EOS
+		  tab\there \#{not_interpolated}
+		EOS
+		`, "tab\there #{not_interpolated}\n"},
+		{`
+		<<~'EOS'
+		  raw\t #{x}
+		EOS
+		`, "raw\\t #{x}\n"},
+		{`
+		<<~EOS.upcase + "!"
+		  goby
+		EOS
+		`, "GOBY\n!"},
+		{`
+		def join(a, b)
+		  a + b
+		end
+		join(<<~A, <<~B)
+		  first
+		A
+		  second
+		B
+		`, "first\nsecond\n"},
+		{`
+		<<~EOS
+		EOS
+		`, ""},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestStringHeredocFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		s = <<~EOS
+		  foo
+		EOS
+		s.bar
+		`, "UndefinedMethodError: Undefined Method 'bar' for foo\n", 5},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestPercentLiterals(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`%w(foo bar  baz).to_s`, `["foo", "bar", "baz"]`},
+		{`%w[a\ b c].to_s`, `["a b", "c"]`},
+		{`%w().to_s`, "[]"},
+		{`
+		%w(
+		  one
+		  two
+		).length
+		`, 2},
+		{`%i{foo bar}.to_s`, "[:foo, :bar]"},
+		{`%i<foo>.first.class.name`, "Symbol"},
+		{`%q(it's "quoted" (nested)\))`, `it's "quoted" (nested))`},
+		{`%q|#{1 + 1}|`, "#{1 + 1}"},
+		{`
+		x = 2
+		%Q{x is #{x} "quoted"\t}
+		`, "x is 2 \"quoted\"\t"},
+		{`%Q!a\!b!`, "a!b"},
+		{`{ a: %w(1 2) }[:a].to_s`, `["1", "2"]`},
+		{`10 % 3`, 1},
+		{`
+		w = 7
+		w %3
+		`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,256 @@
+package vm
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// SymbolObject represents symbol instances
+// A Symbol is an immutable identifier, created with `:name` literals or `String#to_sym`.
+// Symbols are interned, so symbols with the same name are always the same object.
+//
+// ```ruby
+// :goby.to_s             # => "goby"
+// "goby".to_sym          # => :goby
+// :goby == "goby".to_sym # => true
+// ```
+//
+// Symbols can be used wherever a name is expected, like `attr_reader :name` or `h[:key]`.
+//
+// **Note:**
+//
+// - Hash keys are still Strings, so `h[:key]` and `h["key"]` refer to the same value.
+// - `Symbol.new` is not supported.
+type SymbolObject struct {
+	*baseObj
+	value string
+}
+
+// Class methods --------------------------------------------------------
+func builtinSymbolClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinSymbolInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the two symbols are the same symbol
+			//
+			// ```ruby
+			// :goby == :goby  # => true
+			// :goby == "goby" # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					if receiver == args[0] {
+						return TRUE
+					}
+
+					return FALSE
+				}
+			},
+		},
+		{
+			// Compares the names of two symbols, returns -1, 0 or 1.
+			// Returns nil if the argument is not a Symbol.
+			//
+			// ```ruby
+			// :a <=> :b # => -1
+			// :b <=> :b # => 0
+			// :c <=> :b # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got=%d", len(args))
+					}
+
+					right, ok := args[0].(*SymbolObject)
+
+					if !ok {
+						return NULL
+					}
+
+					return t.vm.initIntegerObject(strings.Compare(receiver.(*SymbolObject).value, right.value))
+				}
+			},
+		},
+		{
+			// Returns the symbol as it's written in Goby code, its name is quoted if it isn't an identifier
+			// or an operator.
+			//
+			// ```ruby
+			// :goby.inspect               # => ":goby"
+			// :empty?.inspect             # => ":empty?"
+			// "hello goby".to_sym.inspect # => ":\"hello goby\""
+			// ```
+			//
+			// @return [String]
+			Name: "inspect",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initStringObject(receiver.(*SymbolObject).inspect())
+				}
+			},
+		},
+		{
+			// Returns the character length of the symbol's name
+			//
+			// ```ruby
+			// :goby.length # => 4
+			// ```
+			//
+			// @return [Integer]
+			Name: "length",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(utf8.RuneCountInString(receiver.(*SymbolObject).value))
+				}
+			},
+		},
+		{
+			// Returns the character length of the symbol's name
+			//
+			// ```ruby
+			// :goby.size # => 4
+			// ```
+			//
+			// @return [Integer]
+			Name: "size",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(utf8.RuneCountInString(receiver.(*SymbolObject).value))
+				}
+			},
+		},
+		{
+			// Returns the name of the symbol as a String
+			//
+			// ```ruby
+			// :goby.to_s # => "goby"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*SymbolObject).value)
+				}
+			},
+		},
+		{
+			// Returns the symbol itself
+			//
+			// ```ruby
+			// :goby.to_sym # => :goby
+			// ```
+			//
+			// @return [Symbol]
+			Name: "to_sym",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+// initSymbolObject returns the interned symbol of given name, the symbol is created if it doesn't exist yet
+func (vm *VM) initSymbolObject(name string) *SymbolObject {
+	if s, ok := vm.symbolTable.Load(name); ok {
+		return s.(*SymbolObject)
+	}
+
+	s, _ := vm.symbolTable.LoadOrStore(name, &SymbolObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.SymbolClass), frozen: true},
+		value:   name,
+	})
+
+	return s.(*SymbolObject)
+}
+
+func (vm *VM) initSymbolClass() *RClass {
+	sc := vm.initializeClass(classes.SymbolClass, false)
+	sc.setBuiltinMethods(builtinSymbolInstanceMethods(), false)
+	sc.setBuiltinMethods(builtinSymbolClassMethods(), true)
+	return sc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the symbol's name
+func (s *SymbolObject) toString() string {
+	return s.value
+}
+
+// Returns the symbol's name as a JSON string
+func (s *SymbolObject) toJSON() string {
+	return strconv.Quote(s.value)
+}
+
+// symbolOperators are the method names that can be written as a symbol without quotes
+var symbolOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true, "**": true, "==": true, "!=": true, "<": true,
+	"<=": true, ">": true, ">=": true, "<=>": true, "===": true, "=~": true, "!": true, "[]": true, "[]=": true,
+	"<<": true, ">>": true, "&": true, "|": true, "^": true, "~": true, "+@": true, "-@": true, "`": true,
+}
+
+// inspect returns the symbol with a leading colon, the name is quoted if it isn't an identifier or an operator
+func (s *SymbolObject) inspect() string {
+	if symbolOperators[s.value] {
+		return ":" + s.value
+	}
+
+	name := strings.TrimRight(s.value, "?!=")
+	if len(s.value)-len(name) <= 1 && isAttributeName(strings.TrimPrefix(strings.TrimPrefix(name, "@"), "@")) {
+		return ":" + s.value
+	}
+
+	return ":" + strconv.Quote(s.value)
+}
+
+// stringOrSymbol returns the value of a String or the name of a Symbol, it's used by methods that
+// accept both as a name or a key
+func stringOrSymbol(obj Object) (string, bool) {
+	switch obj := obj.(type) {
+	case *StringObject:
+		return obj.value, true
+	case *SymbolObject:
+		return obj.value, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,141 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestSymbolClassSuperclass(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`Symbol.class.name`, "Class"},
+		{`Symbol.superclass.name`, "Object"},
+		{`:goby.class.name`, "Symbol"},
+		{`"goby".to_sym.class.name`, "Symbol"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSymbolMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`:goby.to_s`, "goby"},
+		{`:empty?.to_s`, "empty?"},
+		{`:sub!.to_s`, "sub!"},
+		{`:goby == :goby`, true},
+		{`:goby == :ruby`, false},
+		{`:goby == "goby"`, false},
+		{`:goby != :ruby`, true},
+		{`"goby".to_sym == :goby`, true},
+		{`"goby".intern == :goby`, true},
+		{`:goby.to_sym == :goby`, true},
+		{`:goby.to_s.to_sym == :goby`, true},
+		{`:a <=> :b`, -1},
+		{`:b <=> :b`, 0},
+		{`:c <=> :b`, 1},
+		{`:a <=> "a"`, nil},
+		{`:goby.inspect`, ":goby"},
+		{`:empty?.inspect`, ":empty?"},
+		{`"[]=".to_sym.inspect`, ":[]="},
+		{`:[]= == "[]=".to_sym`, true},
+		{`[:+, :<=>, :-@].inspect`, "[:+, :<=>, :-@]"},
+		{`"@name".to_sym.inspect`, ":@name"},
+		{`"hello goby".to_sym.inspect`, `:"hello goby"`},
+		{`"a?b".to_sym.inspect`, `:"a?b"`},
+		{`[:a, "a"].to_s`, `[:a, "a"]`},
+		{`{ a: :b }.inspect`, `{ a: :b }`},
+		{`
+		class Kid
+		  def play; end
+		end
+		Kid.instance_methods(false).to_s
+		`, "[:play]"},
+		{`:goby.length`, 4},
+		{`:goby.size`, 4},
+		{`[:a, :b][1].to_s`, "b"},
+		{`
+		def foo(s)
+		  s.to_s + "!"
+		end
+		foo(:goby)
+		`, "goby!"},
+		{`{ a: :b }["a"].to_s`, "b"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSymbolMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Symbol.new`, "UnsupportedMethodError: Unsupported Method #new for Symbol", 1},
+		{`Object.attr_reader(:foo, 1)`, "TypeError: Expect attribute name to be String or Symbol. got: Integer", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestSymbolAsName(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		h = { foo: 1 }
+		h[:foo]
+		`, 1},
+		{`
+		h = {}
+		h[:foo] = 2
+		h["foo"]
+		`, 2},
+		{`{ foo: 1 }.has_key?(:foo)`, true},
+		{`{ foo: 1 }.delete(:foo).length`, 0},
+		{`
+		class Foo
+		  attr_writer :bar
+		  attr_reader "bar"
+		end
+		f = Foo.new
+		f.bar = 10
+		f.bar
+		`, 10},
+		{`
+		class Foo
+		  attr_accessor :bar
+		end
+		f = Foo.new
+		f.instance_variable_set("@bar", 10)
+		f.bar
+		`, 10},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
@@ -0,0 +1,333 @@
+package vm
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Tempfile is a `File` created with a unique name in the temporary directory. The files created by `Tempfile.new`
+// are removed when the program ends, and `Tempfile.create` removes the file right after its block is executed.
+// Use `require "tempfile"` to load it, which also loads `Dir.mktmpdir` from `require "tmpdir"`.
+//
+// ```ruby
+// require "tempfile"
+//
+// file = Tempfile.new("report")
+// file.write("Goby")
+// File.read(file.path) # => "Goby"
+// file.close!
+//
+// Tempfile.create(["config", ".yml"]) do |f|
+//   f.path # => "/tmp/config123456789.yml"
+// end
+// ```
+
+// Class methods --------------------------------------------------------
+func builtinTempfileClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Creates a temporary file and yields it to the block, the file is closed and removed after the block
+			// is executed and the block's return value is returned. Without a block the file is returned, and the
+			// caller is responsible for removing it.
+			//
+			// The basename can be a String prefix or an Array of prefix and suffix, and the file is created in
+			// the system's temporary directory unless a directory is given.
+			//
+			// ```ruby
+			// Tempfile.create("data") do |f|
+			//   f.write("Goby")
+			//   File.read(f.path)
+			// end # => "Goby"
+			// ```
+			//
+			// @param basename [String/Array], dir [String]
+			// @return [Object]
+			Name: "create",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f, err := t.createTempfile(args)
+					if err != nil {
+						return err
+					}
+
+					if blockFrame == nil {
+						return f
+					}
+
+					defer os.Remove(f.File.Name())
+					defer f.File.Close()
+
+					return t.builtinMethodYield(blockFrame, f).Target
+				}
+			},
+		},
+		{
+			// Creates a temporary file opened for reading and writing, which is removed when the program ends.
+			// The basename can be a String prefix or an Array of prefix and suffix.
+			//
+			// ```ruby
+			// Tempfile.new.path              # => "/tmp/123456789"
+			// Tempfile.new("data").path      # => "/tmp/data123456789"
+			// Tempfile.new(["data", ".csv"]) # => "/tmp/data123456789.csv"
+			// ```
+			//
+			// @param basename [String/Array], dir [String]
+			// @return [Tempfile]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					f, err := t.createTempfile(args)
+					if err != nil {
+						return err
+					}
+
+					t.vm.addTempPath(f.File.Name())
+
+					return f
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinTempfileInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Closes and removes the file.
+			//
+			// ```ruby
+			// file = Tempfile.new
+			// file.close!
+			// File.exist?(file.path) # => false
+			// ```
+			//
+			// @return [Null]
+			Name: "close!",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					f := receiver.(*FileObject)
+					f.File.Close()
+
+					if err := os.Remove(f.File.Name()); err != nil && !os.IsNotExist(err) {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return NULL
+				}
+			},
+		},
+		{
+			// Same as `unlink`.
+			//
+			// @return [Null]
+			Name: "delete",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.sendMethod("unlink", receiver, args...)
+				}
+			},
+		},
+		{
+			// Returns the path of the file.
+			//
+			// ```ruby
+			// Tempfile.new("data").path # => "/tmp/data123456789"
+			// ```
+			//
+			// @return [String]
+			Name: "path",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initStringObject(receiver.(*FileObject).File.Name())
+				}
+			},
+		},
+		{
+			// Removes the file without closing it, so it can still be read and written until it's closed.
+			//
+			// ```ruby
+			// file = Tempfile.new
+			// file.unlink
+			// File.exist?(file.path) # => false
+			// ```
+			//
+			// @return [Null]
+			Name: "unlink",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					if err := os.Remove(receiver.(*FileObject).File.Name()); err != nil && !os.IsNotExist(err) {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return NULL
+				}
+			},
+		},
+	}
+}
+
+// `Dir.mktmpdir` and `Dir.tmpdir` are loaded by `require "tmpdir"` or `require "tempfile"`.
+//
+// ```ruby
+// require "tmpdir"
+//
+// Dir.mktmpdir("build") do |dir|
+//   File.write(dir + "/VERSION", "0.1.0")
+// end
+// ```
+
+// Class methods for Dir ------------------------------------------------
+func builtinTmpdirClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Creates a directory with a unique name in the temporary directory and yields its path to the block,
+			// the directory and its contents are removed after the block is executed and the block's return value
+			// is returned. Without a block the path is returned, and the directory is removed when the program ends.
+			//
+			// ```ruby
+			// Dir.mktmpdir("build") do |dir|
+			//   dir # => "/tmp/build123456789"
+			// end
+			// ```
+			//
+			// @param prefix [String], dir [String]
+			// @return [Object]
+			Name: "mktmpdir",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 2 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..2 arguments. got: %d", len(args))
+					}
+
+					names := make([]string, 2)
+					names[0] = "d"
+
+					for i, arg := range args {
+						s, ok := arg.(*StringObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+						}
+
+						names[i] = s.value
+					}
+
+					dir, err := ioutil.TempDir(names[1], names[0])
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					if blockFrame == nil {
+						t.vm.addTempPath(dir)
+						return t.vm.initStringObject(dir)
+					}
+
+					defer os.RemoveAll(dir)
+
+					return t.builtinMethodYield(blockFrame, t.vm.initStringObject(dir)).Target
+				}
+			},
+		},
+		{
+			// Returns the system's temporary directory.
+			//
+			// ```ruby
+			// Dir.tmpdir # => "/tmp"
+			// ```
+			//
+			// @return [String]
+			Name: "tmpdir",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initStringObject(os.TempDir())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initTempfileClass(vm *VM) {
+	initTmpdir(vm)
+
+	tc := vm.initializeClass("Tempfile", false)
+	tc.inherits(vm.topLevelClass(classes.FileClass))
+	tc.setBuiltinMethods(builtinTempfileClassMethods(), true)
+	tc.setBuiltinMethods(builtinTempfileInstanceMethods(), false)
+	vm.objectClass.setClassConstant(tc)
+}
+
+func initTmpdir(vm *VM) {
+	vm.topLevelClass(classes.DirClass).setBuiltinMethods(builtinTmpdirClassMethods(), true)
+}
+
+// Other helper functions -----------------------------------------------
+
+// createTempfile creates a Tempfile with the optional (basename, dir) arguments
+func (t *thread) createTempfile(args []Object) (*FileObject, *Error) {
+	if len(args) > 2 {
+		return nil, t.initErrorObject(errors.ArgumentError, "Expect 0..2 arguments. got: %d", len(args))
+	}
+
+	var prefix, suffix, dir string
+
+	if len(args) > 0 {
+		switch name := args[0].(type) {
+		case *StringObject:
+			prefix = name.value
+		case *ArrayObject:
+			if len(name.Elements) != 2 {
+				return nil, t.initErrorObject(errors.ArgumentError, "Expect basename to be [prefix, suffix]")
+			}
+
+			for _, el := range name.Elements {
+				if _, ok := el.(*StringObject); !ok {
+					return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, el.Class().Name)
+				}
+			}
+
+			prefix = name.Elements[0].(*StringObject).value
+			suffix = name.Elements[1].(*StringObject).value
+		default:
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "String or Array", args[0].Class().Name)
+		}
+	}
+
+	if len(args) > 1 {
+		d, ok := args[1].(*StringObject)
+		if !ok {
+			return nil, t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[1].Class().Name)
+		}
+
+		dir = d.value
+	}
+
+	f, err := ioutil.TempFile(dir, prefix+"*"+suffix)
+	if err != nil {
+		return nil, t.initErrorObject(errors.InternalError, err.Error())
+	}
+
+	return &FileObject{File: f, baseObj: &baseObj{class: t.vm.topLevelClass("Tempfile")}}, nil
+}
@@ -0,0 +1,159 @@
+package vm
+
+import (
+	"os"
+	"testing"
+)
+
+func TestTempfileMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "tempfile"
+		f = Tempfile.new("goby")
+		f.write("Goby")
+		f.close
+		File.read(f.path)
+		`, "Goby"},
+		{`
+		require "tempfile"
+		f = Tempfile.new(["goby", ".csv"])
+		f.path.end_with?(".csv")
+		`, true},
+		{`
+		require "tempfile"
+		f = Tempfile.new
+		f.is_a?(File)
+		`, true},
+		{`
+		require "tempfile"
+		f = Tempfile.new("goby", Dir.tmpdir)
+		f.close!
+		File.exist?(f.path)
+		`, false},
+		{`
+		require "tempfile"
+		f = Tempfile.new("goby")
+		f.unlink
+		File.exist?(f.path)
+		`, false},
+		{`
+		require "tempfile"
+		f = Tempfile.new("goby")
+		f.delete
+		File.exist?(f.path)
+		`, false},
+		{`
+		require "tempfile"
+		Tempfile.create("goby") do |f|
+		  f.write("Goby")
+		  File.read(f.path)
+		end
+		`, "Goby"},
+		{`
+		require "tempfile"
+		path = Tempfile.create("goby") do |f|
+		  f.path
+		end
+		File.exist?(path)
+		`, false},
+		{`
+		require "tempfile"
+		f = Tempfile.create("goby")
+		exist = File.exist?(f.path)
+		f.close!
+		exist
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+		v.removeTempPaths()
+	}
+}
+
+func TestTmpdirMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "tmpdir"
+		Dir.tmpdir
+		`, os.TempDir()},
+		{`
+		require "tmpdir"
+		Dir.mktmpdir("goby") do |dir|
+		  File.write(dir + "/VERSION", "0.1.0")
+		  File.read(dir + "/VERSION")
+		end
+		`, "0.1.0"},
+		{`
+		require "tmpdir"
+		path = Dir.mktmpdir do |dir|
+		  File.write(dir + "/VERSION", "0.1.0")
+		  dir
+		end
+		Dir.exist?(path)
+		`, false},
+		{`
+		require "tempfile"
+		Dir.exist?(Dir.mktmpdir("goby", Dir.tmpdir))
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+		v.removeTempPaths()
+	}
+}
+
+func TestTempPathsRemoval(t *testing.T) {
+	v := initTestVM()
+	evaluated := v.testEval(t, `
+	require "tempfile"
+	[Tempfile.new("goby").path, Dir.mktmpdir("goby")]
+	`, getFilename())
+
+	paths := evaluated.(*ArrayObject).Elements
+	v.removeTempPaths()
+
+	for _, path := range paths {
+		if _, err := os.Stat(path.(*StringObject).value); !os.IsNotExist(err) {
+			t.Errorf("Expect %s to be removed", path.(*StringObject).value)
+		}
+	}
+}
+
+func TestTempfileMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "tempfile"
+		Tempfile.new(1)`, "TypeError: Expect argument to be String or Array. got: Integer", 2},
+		{`require "tempfile"
+		Tempfile.new(["goby"])`, "ArgumentError: Expect basename to be [prefix, suffix]", 2},
+		{`require "tempfile"
+		Tempfile.create("a", "b", "c")`, "ArgumentError: Expect 0..2 arguments. got: 3", 2},
+		{`require "tmpdir"
+		Dir.mktmpdir(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "tmpdir"
+		Dir.tmpdir(1)`, "ArgumentError: Expect 0 argument. got: 1", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
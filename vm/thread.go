@@ -1,9 +1,14 @@
 package vm
 
 import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
 	"github.com/goby-lang/goby/compiler/bytecode"
 	"github.com/goby-lang/goby/vm/errors"
-	"strings"
 )
 
 type thread struct {
@@ -15,6 +20,12 @@ type thread struct {
 	stack *stack
 	// stack pointer
 	sp int
+	// rescuedErrors are the errors being handled by rescue clauses, the innermost one is the last
+	rescuedErrors []*Error
+	// object is the Thread object that represents the thread
+	object *ThreadObject
+	// timeout is the context of the innermost Timeout.timeout block the thread is running, or nil
+	timeout context.Context
 
 	vm *VM
 }
@@ -43,7 +54,13 @@ func (t *thread) startFromTopFrame() {
 func (t *thread) evalCallFrame(cf *callFrame) {
 	for cf.pc < len(cf.instructionSet.instructions) {
 		i := cf.instructionSet.instructions[cf.pc]
-		t.execInstruction(cf, i)
+
+		if len(cf.rescues) > 0 {
+			t.execInstructionWithRescue(cf, i)
+		} else {
+			t.execInstruction(cf, i)
+		}
+
 		if _, yes := t.hasError(); yes {
 			return
 		}
@@ -54,7 +71,7 @@ func (t *thread) hasError() (string, bool) {
 	var hasError bool
 	var msg string
 	if t.stack.top() != nil {
-		if err, ok := t.stack.top().Target.(*Error); ok {
+		if err, ok := t.stack.top().Target.(*Error); ok && !err.rescued {
 			hasError = true
 			msg = err.Message
 		}
@@ -65,17 +82,206 @@ func (t *thread) hasError() (string, bool) {
 func (t *thread) execInstruction(cf *callFrame, i *instruction) {
 	cf.pc++
 
+	if t.timeout != nil && t.timeout.Err() != nil {
+		t.stack.push(&Pointer{Target: t.timeoutError()})
+		return
+	}
+
 	i.action.operation(t, cf, i.Params...)
 }
 
+// When an error is raised while some frame has a rescue handler, the stack panics with the error instead of
+// stopping the program. Then the frame that has the handler recovers here, drops the call frames and stack
+// values above the begin expression, and jumps to the handler with the rescued error on the stack.
+// `break` and `return` that unwind the frame are recovered the same way by ensure clauses' handlers, which
+// get the signal on the stack and keep unwinding after the clause with `raise`.
+func (t *thread) execInstructionWithRescue(cf *callFrame, i *instruction) {
+	defer func() {
+		r := recover()
+
+		if r == nil {
+			return
+		}
+
+		var h *rescueHandler
+		var target Object
+
+		switch signal := r.(type) {
+		case *Error:
+			h = cf.popRescue()
+			target = signal
+		case *blockBreak, *methodReturn:
+			h = cf.popEnsure()
+			target = t.vm.initGoObject(signal)
+		}
+
+		if h == nil {
+			panic(r)
+		}
+
+		for t.cfp > h.cfp {
+			t.callFrameStack.pop()
+		}
+
+		t.sp = h.sp
+		t.rescuedErrors = t.rescuedErrors[:h.rescuedErrors]
+		cf.pc = h.handler
+
+		if err, ok := target.(*Error); ok {
+			t.rescuedErrors = append(t.rescuedErrors, err)
+			err.rescued = true
+		}
+
+		t.stack.push(&Pointer{Target: target})
+	}()
+
+	t.execInstruction(cf, i)
+}
+
+// hasRescue returns true if any frame of the thread can rescue the error raised by current instruction
+func (t *thread) hasRescue() bool {
+	for i := t.cfp - 1; i >= 0; i-- {
+		if t.callFrameStack.callFrames[i].hasRescue() {
+			return true
+		}
+	}
+
+	return false
+}
+
+// currentError returns the error being handled by the innermost rescue clause, or nil if there's none
+func (t *thread) currentError() *Error {
+	if len(t.rescuedErrors) == 0 {
+		return nil
+	}
+
+	return t.rescuedErrors[len(t.rescuedErrors)-1]
+}
+
+// methodReturn is the signal of `return` in a proc, which unwinds the frames until the call of the method
+// that defines the proc
+type methodReturn struct {
+	methodFrame *callFrame
+	value       Object
+}
+
+// returnFromBlock executes `return` in the block: it returns from the block itself if the block is a lambda's,
+// otherwise it returns from the method that defines the block, or the innermost lambda between them
+func (t *thread) returnFromBlock(cf *callFrame, value Object) *Error {
+	f := cf
+
+	for f.method == nil && !f.blockFrame.isLambda {
+		// The block is defined at top level, so there's no method to return from
+		if f.ep == nil || f.ep.blockFrame == nil && f.ep.method == nil {
+			f = cf
+			break
+		}
+
+		f = f.ep
+	}
+
+	if f == cf {
+		t.stack.push(&Pointer{Target: value})
+		t.leaveFrame()
+		return nil
+	}
+
+	if f.method == nil {
+		t.breakBlock(f.blockFrame, value)
+	}
+
+	for i := t.cfp - 1; i >= 0; i-- {
+		if t.callFrameStack.callFrames[i] == f {
+			panic(&methodReturn{methodFrame: f, value: value})
+		}
+	}
+
+	return t.initErrorObject(errors.LocalJumpError, "Can't return from method '%s', which has already returned", f.method.Name)
+}
+
+// catchReturn recovers from the `return` in the procs defined by the method, drops the call frames above the method
+// call and makes the call return the value. It's deferred by every Goby method call.
+func (t *thread) catchReturn(methodFrame *callFrame, cfp, receiverPr int) {
+	r := recover()
+
+	if r == nil {
+		return
+	}
+
+	ret, ok := r.(*methodReturn)
+
+	if !ok || ret.methodFrame != methodFrame {
+		panic(r)
+	}
+
+	for t.cfp > cfp {
+		t.callFrameStack.pop()
+	}
+
+	t.stack.set(receiverPr, &Pointer{Target: ret.value})
+	t.sp = receiverPr + 1
+}
+
+// raise panics with the error if it can be rescued, so that the frame that has the rescue handler can recover from it
+func (t *thread) raise(err *Error) {
+	if !err.rescued && t.hasRescue() {
+		panic(err)
+	}
+}
+
+// blockBreak is the signal of `break` in a block, which unwinds the frames until the method call that
+// the block belongs to
+type blockBreak struct {
+	blockFrame *callFrame
+	value      Object
+}
+
+// breakBlock stops the execution of the block and the method that yields it, like how rescued errors are raised
+func (t *thread) breakBlock(blockFrame *callFrame, value Object) {
+	panic(&blockBreak{blockFrame: blockFrame, value: value})
+}
+
+// catchBreak recovers from the `break` in the given block, drops the call frames above the method call and
+// makes the call return break's value. It needs to be deferred by the method call that passes the block.
+func (t *thread) catchBreak(blockFrame *callFrame, cfp, receiverPr int) {
+	r := recover()
+
+	if r == nil {
+		return
+	}
+
+	b, ok := r.(*blockBreak)
+
+	if !ok || b.blockFrame != blockFrame {
+		panic(r)
+	}
+
+	for t.cfp > cfp {
+		t.callFrameStack.pop()
+	}
+
+	t.stack.set(receiverPr, &Pointer{Target: b.value})
+	t.sp = receiverPr + 1
+}
+
 func (t *thread) builtinMethodYield(blockFrame *callFrame, args ...Object) *Pointer {
+	return t.yieldWithSelf(blockFrame, blockFrame.self, args...)
+}
+
+// yieldWithSelf executes the block like builtinMethodYield, but the block's self is replaced with the given object
+func (t *thread) yieldWithSelf(blockFrame *callFrame, self Object, args ...Object) *Pointer {
+	if blockFrame.goBlock != nil {
+		t.stack.push(&Pointer{Target: blockFrame.goBlock(t, args)})
+		return t.stack.top()
+	}
+
 	c := newCallFrame(blockFrame.instructionSet)
 	c.blockFrame = blockFrame
 	c.ep = blockFrame.ep
-	c.self = blockFrame.self
+	c.self = self
 
-	for i := 0; i < len(args); i++ {
-		c.insertLCL(i, 0, args[i])
+	for i, arg := range blockFrame.blockArguments(args) {
+		c.insertLCL(i, 0, arg)
 	}
 
 	t.callFrameStack.push(c)
@@ -84,6 +290,21 @@ func (t *thread) builtinMethodYield(blockFrame *callFrame, args ...Object) *Poin
 	return t.stack.top()
 }
 
+// blockArguments returns the arguments that the block's parameters are set to. If the block has more than one
+// parameter and it's given only an Array, the Array is destructured into the parameters like
+// `[[1, 2]].each do |a, b| end`. Lambdas take their arguments as they are.
+func (cf *callFrame) blockArguments(args []Object) []Object {
+	if cf.isLambda || len(args) != 1 || len(cf.instructionSet.argTypes) < 2 {
+		return args
+	}
+
+	if arr, ok := args[0].(*ArrayObject); ok {
+		return arr.Elements
+	}
+
+	return args
+}
+
 func (t *thread) retrieveBlock(cf *callFrame, args []interface{}) (blockFrame *callFrame) {
 	var blockName string
 	var hasBlock bool
@@ -112,6 +333,24 @@ func (t *thread) retrieveBlock(cf *callFrame, args []interface{}) (blockFrame *c
 	return
 }
 
+// retrieveProcBlock pushes a block frame for the proc passed with `&`, just like the one of a block literal.
+// Procs implemented in Go don't have instructions to execute, so their frames are used as they are.
+func (t *thread) retrieveProcBlock(proc *ProcObject) *callFrame {
+	if proc.blockFrame.goBlock != nil {
+		return proc.blockFrame
+	}
+
+	c := newCallFrame(proc.blockFrame.instructionSet)
+	c.isBlock = true
+	c.isLambda = proc.isLambda
+	c.ep = proc.blockFrame.ep
+	c.self = proc.blockFrame.self
+
+	t.callFrameStack.push(c)
+
+	return c
+}
+
 func (t *thread) evalBuiltinMethod(receiver Object, method *BuiltinMethodObject, receiverPr, argCount int, blockFrame *callFrame) {
 	methodBody := method.Fn(receiver)
 	args := []Object{}
@@ -121,7 +360,15 @@ func (t *thread) evalBuiltinMethod(receiver Object, method *BuiltinMethodObject,
 		args = append(args, t.stack.Data[argPr+i].Target)
 	}
 
-	evaluated := methodBody(t, args, blockFrame)
+	var evaluated Object
+
+	if s, ok := receiver.(synchronizedObject); ok && s.synchronizer() != nil {
+		evaluated = s.synchronizer().synchronize(t, func() Object {
+			return methodBody(t, args, blockFrame)
+		})
+	} else {
+		evaluated = methodBody(t, args, blockFrame)
+	}
 
 	_, ok := receiver.(*RClass)
 	if method.Name == "new" && ok {
@@ -139,7 +386,14 @@ func (t *thread) evalMethodObject(receiver Object, method *MethodObject, receive
 	c.self = receiver
 	argPr := receiverPr + 1
 	minimumArgNumber := 0
-	argTypesCount := len(method.argTypes())
+	splatArgIndex := method.splatArgIndex()
+	blockArgIndex := method.blockArgIndex()
+	keywordArgCount := method.keywordArgCount()
+	maximumArgNumber := method.argc - keywordArgCount
+
+	if blockArgIndex != -1 {
+		maximumArgNumber--
+	}
 
 	for _, at := range method.argTypes() {
 		if at == bytecode.NormalArg {
@@ -147,15 +401,25 @@ func (t *thread) evalMethodObject(receiver Object, method *MethodObject, receive
 		}
 	}
 
-	if argC > method.argc && method.lastArgType() != bytecode.SplatArg {
-		e := t.vm.initErrorObject(errors.ArgumentError, "Expect at most %d args for method '%s'. got: %d", method.argc, method.Name, argC)
+	// The trailing hash is passed to keyword parameters, unless it's needed by a normal parameter
+	var keywords *HashObject
+
+	if keywordArgCount > 0 && argC > minimumArgNumber {
+		if h, ok := t.stack.Data[argPr+argC-1].Target.(*HashObject); ok {
+			keywords = h
+			argC--
+		}
+	}
+
+	if argC > maximumArgNumber && splatArgIndex == -1 {
+		e := t.initErrorObject(errors.ArgumentError, "Expect at most %d args for method '%s'. got: %d", maximumArgNumber, method.Name, argC)
 		t.stack.set(receiverPr, &Pointer{Target: e})
 		t.sp = argPr
 		return
 	}
 
 	if minimumArgNumber > argC {
-		e := t.vm.initErrorObject(errors.ArgumentError, "Expect at least %d args for method '%s'. got: %d", minimumArgNumber, method.Name, argC)
+		e := t.initErrorObject(errors.ArgumentError, "Expect at least %d args for method '%s'. got: %d", minimumArgNumber, method.Name, argC)
 		t.stack.set(receiverPr, &Pointer{Target: e})
 		t.sp = argPr
 		return
@@ -191,7 +455,7 @@ func (t *thread) evalMethodObject(receiver Object, method *MethodObject, receive
 	if minimumArgNumber < argC {
 		// Fill arguments with default value from beginning
 		for i, argType := range method.argTypes() {
-			if argType != bytecode.NormalArg && argType != bytecode.SplatArg {
+			if argType == bytecode.OptionedArg {
 				c.insertLCL(i, 0, t.stack.Data[argPr+argIndex].Target)
 				argIndex++
 			}
@@ -203,17 +467,37 @@ func (t *thread) evalMethodObject(receiver Object, method *MethodObject, receive
 		}
 	}
 
-	if argTypesCount > 0 && method.lastArgType() == bytecode.SplatArg {
+	if splatArgIndex != -1 {
 		elems := []Object{}
 		for argIndex < argC {
 			elems = append(elems, t.stack.Data[argPr+argIndex].Target)
 			argIndex++
 		}
 
-		c.insertLCL(len(method.argTypes())-1, 0, t.vm.initArrayObject(elems))
+		c.insertLCL(splatArgIndex, 0, t.vm.initArrayObject(elems))
+	}
+
+	if keywordArgCount > 0 {
+		if e := t.bindKeywordArgs(c, method, keywords); e != nil {
+			t.stack.set(receiverPr, &Pointer{Target: e})
+			t.sp = argPr
+			return
+		}
+	}
+
+	if blockArgIndex != -1 {
+		var block Object = NULL
+
+		if blockFrame != nil {
+			block = t.vm.initProcObject(blockFrame, blockFrame.isLambda)
+		}
+
+		c.insertLCL(blockArgIndex, 0, block)
 	}
 
 	c.blockFrame = blockFrame
+	c.method = method
+	defer t.catchReturn(c, t.cfp, receiverPr)
 	t.callFrameStack.push(c)
 	t.startFromTopFrame()
 
@@ -221,11 +505,568 @@ func (t *thread) evalMethodObject(receiver Object, method *MethodObject, receive
 	t.sp = argPr
 }
 
+// methodFrame returns the frame of the method that the frame belongs to, which is the frame itself unless it's
+// a block's frame. It returns nil if the frame is not in a method.
+func (cf *callFrame) methodFrame() *callFrame {
+	for cf != nil && cf.method == nil {
+		cf = cf.ep
+	}
+
+	return cf
+}
+
+// superMethod returns the method that `super` calls in the method: it's found from the superclass of the class or
+// module that defines the method, in the receiver's lookup chain
+func superMethod(receiver Object, method *MethodObject) Object {
+	c := receiver.SingletonClass()
+	if c == nil {
+		c = receiver.Class()
+	}
+
+	for ; c != nil; c = c.superClass {
+		if m, ok := c.Methods.store[method.Name]; ok && m == Object(method) {
+			if c.superClass == nil || c.superClass == c {
+				return nil
+			}
+
+			return c.superClass.lookupMethod(method.Name)
+		}
+
+		if c.superClass == c {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// classVariableScope returns the class or module that class variables are looked up from in the frame: the one
+// that defines the method being executed, or the one whose body is being executed. Class variables used at top
+// level belong to Object, with a warning.
+func (t *thread) classVariableScope(cf *callFrame) *RClass {
+	if mf := cf.methodFrame(); mf != nil {
+		if c := methodOwner(mf.self, mf.method); c != nil {
+			return c
+		}
+	}
+
+	if c, ok := cf.self.(*RClass); ok {
+		return c
+	}
+
+	t.vm.warn(cf, "class variable access from toplevel")
+	return t.vm.objectClass
+}
+
+// methodOwner returns the class or module that defines the method in the receiver's lookup chain. A class method
+// is owned by the class whose singleton class defines it.
+func methodOwner(receiver Object, method *MethodObject) *RClass {
+	c := receiver.SingletonClass()
+	if c == nil {
+		c = receiver.Class()
+	}
+
+	for ; c != nil; c = c.superClass {
+		if m, ok := c.Methods.store[method.Name]; ok && m == Object(method) {
+			switch {
+			case c.includedModule != nil:
+				return c.includedModule
+			case c.isSingleton:
+				if class, ok := receiver.(*RClass); ok {
+					for ; class != nil; class = class.superClass {
+						if class.SingletonClass() == c {
+							return class
+						}
+
+						if class.superClass == class {
+							break
+						}
+					}
+				}
+
+				return receiver.Class()
+			}
+
+			return c
+		}
+
+		if c.superClass == c {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// pushMethodArgs pushes the current values of the method's parameters for bare `super` and returns their count.
+// Splat parameters are expanded, and keyword parameters are passed as a trailing hash.
+func (t *thread) pushMethodArgs(cf *callFrame) int {
+	count := 0
+	keywords := map[string]Object{}
+	names := cf.method.argNames()
+
+	for i, at := range cf.method.argTypes() {
+		var value Object = NULL
+		if i < len(cf.locals) && cf.locals[i] != nil {
+			value = cf.locals[i].Target
+		}
+
+		switch at {
+		case bytecode.NormalArg, bytecode.OptionedArg:
+			t.stack.push(&Pointer{Target: value})
+			count++
+		case bytecode.SplatArg:
+			if arr, ok := value.(*ArrayObject); ok {
+				for _, e := range arr.Elements {
+					t.stack.push(&Pointer{Target: e})
+					count++
+				}
+			}
+		case bytecode.RequiredKeywordArg, bytecode.OptionalKeywordArg:
+			keywords[names[i]] = value
+		}
+	}
+
+	if len(keywords) > 0 {
+		t.stack.push(&Pointer{Target: t.vm.initHashObject(keywords)})
+		count++
+	}
+
+	return count
+}
+
+// bindKeywordArgs assigns keyword arguments to the method's keyword parameters, optional ones that aren't given
+// are assigned with their default values when the method starts
+func (t *thread) bindKeywordArgs(c *callFrame, method *MethodObject, keywords *HashObject) *Error {
+	pairs := map[string]Object{}
+
+	if keywords != nil {
+		pairs = keywords.Pairs
+	}
+
+	names := map[string]bool{}
+
+	for i, argType := range method.argTypes() {
+		if argType != bytecode.RequiredKeywordArg && argType != bytecode.OptionalKeywordArg {
+			continue
+		}
+
+		name := method.argNames()[i]
+		names[name] = true
+
+		if value, ok := pairs[name]; ok {
+			c.insertLCL(i, 0, value)
+		} else if argType == bytecode.RequiredKeywordArg {
+			return t.initErrorObject(errors.ArgumentError, "Missing keyword argument '%s' for method '%s'", name, method.Name)
+		}
+	}
+
+	if keywords != nil {
+		for _, key := range keywords.sortedKeys() {
+			if !names[key] {
+				return t.initErrorObject(errors.ArgumentError, "Unknown keyword argument '%s' for method '%s'", key, method.Name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sendMethod calls receiver's method with args and returns the result, it lets builtin methods call
+// methods that can be overridden in Goby, like `<=>`
+func (t *thread) sendMethod(methodName string, receiver Object, args ...Object) Object {
+	return t.sendMethodWithBlock(methodName, receiver, nil, args...)
+}
+
+// sendMethodWithBlock is like sendMethod, but also passes the block to the method
+func (t *thread) sendMethodWithBlock(methodName string, receiver Object, blockFrame *callFrame, args ...Object) Object {
+	method := receiver.findMethod(methodName)
+
+	if method == nil {
+		return t.initErrorObject(errors.UndefinedMethodError, "Undefined Method '%+v' for %+v", methodName, receiver.toString())
+	}
+
+	return t.callMethod(method, receiver, blockFrame, args...)
+}
+
+// callMethod calls the method object on receiver with args and the block, without looking it up by name
+func (t *thread) callMethod(method Object, receiver Object, blockFrame *callFrame, args ...Object) Object {
+	receiverPr := t.sp
+	t.stack.push(&Pointer{Target: receiver})
+
+	for _, arg := range args {
+		t.stack.push(&Pointer{Target: arg})
+	}
+
+	switch m := method.(type) {
+	case *MethodObject:
+		t.evalMethodObject(receiver, m, receiverPr, len(args), blockFrame)
+	case *BuiltinMethodObject:
+		t.evalBuiltinMethod(receiver, m, receiverPr, len(args), blockFrame)
+	}
+
+	result := t.stack.Data[receiverPr].Target
+	t.sp = receiverPr
+
+	return result
+}
+
+// inspector formats an object for display, it's used by arrays and hashes to format their elements
+type inspector func(obj Object) (string, *Error)
+
+// defaultInspect formats the object with its Go-level representation, quoting and escaping strings and
+// prefixing symbols with a colon
+func defaultInspect(obj Object) (string, *Error) {
+	switch o := obj.(type) {
+	case *StringObject:
+		return strconv.Quote(o.value), nil
+	case *SymbolObject:
+		return o.inspect(), nil
+	}
+
+	return obj.toString(), nil
+}
+
+// userDefinedMethod returns the object's method with the given name if it's defined in Goby code
+func userDefinedMethod(obj Object, methodName string) *MethodObject {
+	m, _ := obj.findMethod(methodName).(*MethodObject)
+	return m
+}
+
+// callForString calls the object's method and returns its result as a Go string
+func (t *thread) callForString(obj Object, methodName string) (string, *Error) {
+	switch result := t.sendMethod(methodName, obj).(type) {
+	case *Error:
+		return "", result
+	case *StringObject:
+		return result.value, nil
+	default:
+		return result.toString(), nil
+	}
+}
+
+// objectToString returns the string `puts` and `to_s` use for the object: a `to_s` defined in Goby is called,
+// and arrays and hashes are formatted with inspectObject
+func (t *thread) objectToString(obj Object) (string, *Error) {
+	if userDefinedMethod(obj, "to_s") != nil {
+		return t.callForString(obj, "to_s")
+	}
+
+	return t.builtinString(obj)
+}
+
+// builtinString is objectToString without the Goby-defined `to_s`, so Object#to_s can be called with `super`
+func (t *thread) builtinString(obj Object) (string, *Error) {
+	switch o := obj.(type) {
+	case *ArrayObject:
+		return o.inspectElements(t.inspectObject)
+	case *HashObject:
+		return o.inspectPairs(t.inspectObject)
+	case *SetObject:
+		return o.inspectElements(t.inspectObject)
+	case *OpenStructObject:
+		return o.inspectAttributes(t.inspectObject)
+	}
+
+	return obj.toString(), nil
+}
+
+// inspectObject returns the string `inspect` and the REPL use for the object: an `inspect` or `to_s` defined
+// in Goby is called, strings are quoted, and elements of arrays and hashes are inspected recursively
+func (t *thread) inspectObject(obj Object) (string, *Error) {
+	if userDefinedMethod(obj, "inspect") != nil {
+		return t.callForString(obj, "inspect")
+	}
+
+	return t.builtinInspect(obj)
+}
+
+// builtinInspect is inspectObject without the Goby-defined `inspect`, so Object#inspect can be called with `super`
+func (t *thread) builtinInspect(obj Object) (string, *Error) {
+	if userDefinedMethod(obj, "to_s") != nil {
+		return t.callForString(obj, "to_s")
+	}
+
+	switch obj.(type) {
+	case *ArrayObject, *HashObject, *SetObject, *OpenStructObject:
+		return t.builtinString(obj)
+	}
+
+	return defaultInspect(obj)
+}
+
+// objectsEqual compares the objects with `==`, so classes that define their own `==` are respected
+func (t *thread) objectsEqual(a, b Object) (bool, *Error) {
+	result := t.sendMethod("==", a, b)
+	if err, ok := result.(*Error); ok {
+		return false, err
+	}
+
+	return isTruthy(result), nil
+}
+
+// objectsEql compares the objects with `eql?`, which is what Hash uses to compare keys
+func (t *thread) objectsEql(a, b Object) (bool, *Error) {
+	result := t.sendMethod("eql?", a, b)
+	if err, ok := result.(*Error); ok {
+		return false, err
+	}
+
+	return isTruthy(result), nil
+}
+
+// deconstruct returns the elements of the subject for an array pattern with the given length, or nil if it doesn't
+// match. Objects other than arrays are deconstructed with their `deconstruct` method. If the pattern has a splat
+// at splatIndex, the elements it matches are grouped into an array.
+func (t *thread) deconstruct(subject Object, length, splatIndex int) (*ArrayObject, *Error) {
+	arr, ok := subject.(*ArrayObject)
+
+	if !ok {
+		if subject.findMethod("deconstruct") == nil {
+			return nil, nil
+		}
+
+		switch result := t.sendMethod("deconstruct", subject).(type) {
+		case *Error:
+			return nil, result
+		case *ArrayObject:
+			arr = result
+		default:
+			return nil, t.initErrorObject(errors.TypeError, "deconstruct must return Array. got: %s", result.Class().Name)
+		}
+	}
+
+	elems := arr.Elements
+
+	if splatIndex < 0 {
+		if len(elems) != length {
+			return nil, nil
+		}
+
+		return t.vm.initArrayObject(elems), nil
+	}
+
+	splatLength := len(elems) - (length - 1)
+
+	if splatLength < 0 {
+		return nil, nil
+	}
+
+	grouped := append([]Object{}, elems[:splatIndex]...)
+	grouped = append(grouped, t.vm.initArrayObject(append([]Object{}, elems[splatIndex:splatIndex+splatLength]...)))
+	grouped = append(grouped, elems[splatIndex+splatLength:]...)
+
+	return t.vm.initArrayObject(grouped), nil
+}
+
+// deconstructKeys returns the subject as a hash for a hash pattern with the given keys, or nil if it doesn't
+// have all the keys. A pattern without keys only matches empty hashes. Objects other than hashes are
+// deconstructed with their `deconstruct_keys` method, which receives the keys.
+func (t *thread) deconstructKeys(subject Object, keys []string) (*HashObject, *Error) {
+	h, ok := subject.(*HashObject)
+
+	if !ok {
+		if subject.findMethod("deconstruct_keys") == nil {
+			return nil, nil
+		}
+
+		keyObjects := []Object{}
+
+		for _, key := range keys {
+			keyObjects = append(keyObjects, t.vm.initStringObject(key))
+		}
+
+		switch result := t.sendMethod("deconstruct_keys", subject, t.vm.initArrayObject(keyObjects)).(type) {
+		case *Error:
+			return nil, result
+		case *HashObject:
+			h = result
+		default:
+			return nil, t.initErrorObject(errors.TypeError, "deconstruct_keys must return Hash. got: %s", result.Class().Name)
+		}
+	}
+
+	if len(keys) == 0 {
+		if len(h.Pairs) != 0 {
+			return nil, nil
+		}
+
+		return h, nil
+	}
+
+	for _, key := range keys {
+		if _, ok := h.Pairs[key]; !ok {
+			return nil, nil
+		}
+	}
+
+	return h, nil
+}
+
+// arraysEqual returns true if both arrays have the same length and their elements are equal with `==`
+func (t *thread) arraysEqual(a, b *ArrayObject) (bool, *Error) {
+	if len(a.Elements) != len(b.Elements) {
+		return false, nil
+	}
+
+	for i, e := range a.Elements {
+		eq, err := t.objectsEqual(e, b.Elements[i])
+		if err != nil || !eq {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// arraysEql returns true if both arrays have the same length and their elements are equal with `eql?`
+func (t *thread) arraysEql(a, b *ArrayObject) (bool, *Error) {
+	if len(a.Elements) != len(b.Elements) {
+		return false, nil
+	}
+
+	for i, e := range a.Elements {
+		eql, err := t.objectsEql(e, b.Elements[i])
+		if err != nil || !eql {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// hashesEqual returns true if both hashes have the same keys and their values are equal with `==`
+func (t *thread) hashesEqual(a, b *HashObject) (bool, *Error) {
+	if a.length() != b.length() {
+		return false, nil
+	}
+
+	for k, v := range a.Pairs {
+		bk, found, err := b.lookupKey(t, a.keyObject(t.vm, k))
+		if err != nil || !found {
+			return false, err
+		}
+
+		eq, err := t.objectsEqual(v, b.Pairs[bk])
+		if err != nil || !eq {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// hashOf returns the object's hash value: a `hash` method defined in Goby is called, and builtinHash is used otherwise
+func (t *thread) hashOf(obj Object) (int, *Error) {
+	if userDefinedMethod(obj, "hash") == nil {
+		return t.builtinHash(obj)
+	}
+
+	switch result := t.sendMethod("hash", obj).(type) {
+	case *Error:
+		return 0, result
+	case *IntegerObject:
+		return result.value, nil
+	default:
+		return 0, t.initErrorObject(errors.TypeError, "Expect hash to return Integer. got: %s", result.Class().Name)
+	}
+}
+
+// builtinHash returns a hash value that is consistent with the builtin `eql?`: arrays and hashes combine the hashes
+// of their contents, instances of Goby classes are hashed by identity, and other objects by their value
+func (t *thread) builtinHash(obj Object) (int, *Error) {
+	switch o := obj.(type) {
+	case *IntegerObject:
+		return o.value, nil
+	case *FloatObject:
+		// Adding 0 turns -0.0 into 0.0, which is eql? to it
+		return stringHash(fmt.Sprintf("%s:%v", o.Class().Name, o.value+0)), nil
+	case *ArrayObject:
+		h := 17
+		for _, e := range o.Elements {
+			eh, err := t.hashOf(e)
+			if err != nil {
+				return 0, err
+			}
+			h = h*31 + eh
+		}
+		return h, nil
+	case *HashObject:
+		// The sum doesn't depend on the order of the pairs
+		h := 0
+		for k, v := range o.Pairs {
+			kh, err := t.hashOf(o.keyObject(t.vm, k))
+			if err != nil {
+				return 0, err
+			}
+			vh, err := t.hashOf(v)
+			if err != nil {
+				return 0, err
+			}
+			h += kh*31 + vh
+		}
+		return h, nil
+	case *SetObject:
+		// Like hashes, the sum doesn't depend on the order of the elements
+		h := 0
+		for _, el := range o.order {
+			eh, err := t.hashOf(el)
+			if err != nil {
+				return 0, err
+			}
+			h += eh
+		}
+		return h, nil
+	case *RObject:
+		return stringHash(fmt.Sprintf("%p", o)), nil
+	}
+
+	if s, ok := stringOrSymbol(obj); ok {
+		return stringHash(s), nil
+	}
+
+	return stringHash(obj.Class().Name + ":" + obj.toString()), nil
+}
+
+// stringHash returns the FNV-1a hash of the string
+func stringHash(s string) int {
+	h := fnv.New32a()
+	h.Write([]byte(s))
+	return int(h.Sum32())
+}
+
+// checkFrozen returns a FrozenError if the object is frozen, builtin methods that modify their receivers call it first
+func (t *thread) checkFrozen(obj Object) *Error {
+	if !obj.isFrozen() {
+		return nil
+	}
+
+	s, _ := defaultInspect(obj)
+	return t.initErrorObject(errors.FrozenError, "Can't modify frozen %s: %s", obj.Class().Name, s)
+}
+
+// checkVisibility returns an error if the method can't be called by caller: private methods can only be called on
+// the caller itself, and protected methods can only be called by objects that have the same method
+func (t *thread) checkVisibility(caller, receiver, method Object, methodName string) *Error {
+	switch methodVisibility(method) {
+	case bytecode.Private:
+		if receiver != caller {
+			return t.initErrorObject(errors.UndefinedMethodError, "Private method '%s' called for %s", methodName, receiver.toString())
+		}
+	case bytecode.Protected:
+		if receiver != caller && (caller == nil || caller.findMethod(methodName) != method) {
+			return t.initErrorObject(errors.UndefinedMethodError, "Protected method '%s' called for %s", methodName, receiver.toString())
+		}
+	}
+
+	return nil
+}
+
 func (t *thread) returnError(errorType, format string, args ...interface{}) {
-	err := t.vm.initErrorObject(errorType, format, args...)
+	err := t.initErrorObject(errorType, format, args...)
 	t.stack.push(&Pointer{Target: err})
 }
 
 func (t *thread) unsupportedMethodError(methodName string, receiver Object) *Error {
-	return t.vm.initErrorObject(errors.UnsupportedMethodError, "Unsupported Method %s for %+v", methodName, receiver.toString())
+	return t.initErrorObject(errors.UnsupportedMethodError, "Unsupported Method %s for %+v", methodName, receiver.toString())
 }
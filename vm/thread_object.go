@@ -0,0 +1,274 @@
+package vm
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// ThreadObject represents a Goby thread, which runs a block on its own goroutine with its own stack and call
+// frames. The arguments of `Thread.new` are passed to the block, and the block's result can be taken by `value`
+// once the thread finishes.
+//
+// ```ruby
+// c = Channel.new
+//
+// t = Thread.new(10) do |n|
+//   c.deliver(n)
+//   n * 2
+// end
+//
+// c.receive # => 10
+// t.value   # => 20
+// t.alive?  # => false
+// ```
+//
+// An error that stops the thread is raised again by `join` and `value`.
+type ThreadObject struct {
+	*baseObj
+	thread *thread
+	// done is closed when the thread finishes
+	done  chan struct{}
+	value Object
+}
+
+// Class methods --------------------------------------------------------
+func builtinThreadClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the thread that is running the current code.
+			//
+			// ```ruby
+			// Thread.current.alive? # => true
+			// ```
+			//
+			// @return [Thread]
+			Name: "current",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.threadObject()
+				}
+			},
+		},
+		{
+			// Starts a new thread that runs the block with the given arguments.
+			//
+			// ```ruby
+			// t = Thread.new(1, 2) do |a, b|
+			//   a + b
+			// end
+			// t.value # => 3
+			// ```
+			//
+			// @param args [Object]
+			// @return [Thread]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					return t.startThread(blockFrame, args)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinThreadInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns true if the thread hasn't finished.
+			//
+			// ```ruby
+			// t = Thread.new do
+			//   1
+			// end
+			// t.join
+			// t.alive? # => false
+			// ```
+			//
+			// @return [Boolean]
+			Name: "alive?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					select {
+					case <-receiver.(*ThreadObject).done:
+						return FALSE
+					default:
+						return TRUE
+					}
+				}
+			},
+		},
+		{
+			// Waits for the thread to finish and returns it. If a timeout (sec) is given, returns nil when the thread
+			// doesn't finish in time. The error that stopped the thread is raised again.
+			//
+			// ```ruby
+			// t = Thread.new do
+			//   sleep(1)
+			// end
+			// t.join(0.1) # => nil
+			// t.join      # => t
+			// ```
+			//
+			// @param timeout [Integer/Float]
+			// @return [Thread]
+			Name: "join",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) > 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					th := receiver.(*ThreadObject)
+
+					if th.thread == t {
+						return t.initErrorObject(errors.ThreadError, "Can't join the current thread")
+					}
+
+					if len(args) == 1 {
+						var seconds float64
+
+						switch timeout := args[0].(type) {
+						case *IntegerObject:
+							seconds = float64(timeout.value)
+						case *FloatObject:
+							seconds = timeout.value
+						default:
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", args[0].Class().Name)
+						}
+
+						select {
+						case <-th.done:
+						case <-time.After(time.Duration(seconds * float64(time.Second))):
+							return NULL
+						}
+					} else {
+						<-th.done
+					}
+
+					if err, ok := th.value.(*Error); ok && !err.rescued {
+						return err
+					}
+
+					return th
+				}
+			},
+		},
+		{
+			// Returns the thread's string representation.
+			//
+			// ```ruby
+			// Thread.current.to_s # => "#<Thread:0xc42000e2a0 run>"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+		{
+			// Waits for the thread to finish and returns the block's result. The error that stopped the thread is
+			// raised again.
+			//
+			// ```ruby
+			// t = Thread.new do
+			//   "done"
+			// end
+			// t.value # => "done"
+			// ```
+			//
+			// @return [Object]
+			Name: "value",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					th := receiver.(*ThreadObject)
+
+					if th.thread == t {
+						return t.initErrorObject(errors.ThreadError, "Can't join the current thread")
+					}
+
+					<-th.done
+
+					return th.value
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initThreadObject(t *thread) *ThreadObject {
+	return &ThreadObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.ThreadClass)},
+		thread:  t,
+		done:    make(chan struct{}),
+	}
+}
+
+func (vm *VM) initThreadClass() *RClass {
+	class := vm.initializeClass(classes.ThreadClass, false)
+	class.setBuiltinMethods(builtinThreadClassMethods(), true)
+	class.setBuiltinMethods(builtinThreadInstanceMethods(), false)
+	return class
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (th *ThreadObject) toString() string {
+	status := "run"
+
+	select {
+	case <-th.done:
+		status = "dead"
+	default:
+	}
+
+	return fmt.Sprintf("#<Thread:%p %s>", th.thread, status)
+}
+
+// Alias of toString
+func (th *ThreadObject) toJSON() string {
+	return th.toString()
+}
+
+// Other helper functions -----------------------------------------------
+
+// startThread runs the block on a new thread's goroutine and returns the thread's object
+func (t *thread) startThread(blockFrame *callFrame, args []Object) *ThreadObject {
+	newT := t.vm.newThread()
+	th := newT.threadObject()
+
+	go func() {
+		defer close(th.done)
+		th.value = newT.builtinMethodYield(blockFrame, args...).Target
+	}()
+
+	// We need to pop the block's frame from the current thread manually,
+	// because the block's 'leave' instruction is running on the new thread
+	t.callFrameStack.pop()
+
+	return th
+}
+
+// threadObject returns the object that represents the thread, it's created when it's first needed
+func (t *thread) threadObject() *ThreadObject {
+	if t.object == nil {
+		t.object = t.vm.initThreadObject(t)
+	}
+
+	return t.object
+}
@@ -0,0 +1,855 @@
+package vm
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// TimeObject represents a point in time with nanosecond precision, it carries a golang `time.Time`.
+// Times are immutable, methods like `+` and `utc` return new Times.
+//
+// ```ruby
+// start = Time.now
+// t = Time.new(2018, 1, 2, 15, 4, 5, "+09:00")
+// t.year                        # => 2018
+// t.strftime("%Y-%m-%d %H:%M")  # => "2018-01-02 15:04"
+// (t + 60).min                  # => 5
+// t.utc.hour                    # => 6
+// Time.parse("2018-01-02") < t  # => true
+// Time.now - start              # => the elapsed seconds as a Float
+// ```
+type TimeObject struct {
+	*baseObj
+	value time.Time
+}
+
+// timeParsingLayouts are the layouts `Time.parse` tries in order
+var timeParsingLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02 15:04:05 -0700",
+	"2006-01-02 15:04:05 -07:00",
+	"2006-01-02 15:04:05 MST",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC822Z,
+	time.RFC822,
+	time.RubyDate,
+	time.UnixDate,
+	time.ANSIC,
+}
+
+// Class methods --------------------------------------------------------
+func builtinTimeClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the local time of the given seconds since the Unix epoch, which can be an Integer or a Float.
+			//
+			// ```ruby
+			// Time.at(0).utc.to_s   # => "1970-01-01 00:00:00 +0000"
+			// Time.at(1.5).to_f     # => 1.5
+			// ```
+			//
+			// @param seconds [Integer/Float]
+			// @return [Time]
+			Name: "at",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					switch s := args[0].(type) {
+					case *IntegerObject:
+						return t.vm.initTimeObject(time.Unix(int64(s.value), 0))
+					case *FloatObject:
+						return t.vm.initTimeObject(time.Unix(0, 0).Add(secondsToDuration(s.value)))
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", args[0].Class().Name)
+					}
+				}
+			},
+		},
+		{
+			// Returns the time of the given year, month, day, hour, minute and second in the given time zone.
+			// The omitted components are the smallest values, the second can be a Float, and the zone can be
+			// "UTC", an offset like "+09:00" or a location like "Asia/Taipei". The local time zone is used if
+			// it's omitted. `Time.new` without arguments is the same as `Time.now`.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2).to_s                        # => "2018-01-02 00:00:00 +0800"
+			// Time.new(2018, 1, 2, 15, 4, 5.5, "UTC").to_s    # => "2018-01-02 15:04:05 +0000"
+			// ```
+			//
+			// @param year [Integer], month [Integer], day [Integer], hour [Integer], minute [Integer], second [Integer/Float], zone [String]
+			// @return [Time]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) == 0 {
+						return t.vm.initTimeObject(time.Now())
+					}
+
+					if len(args) > 7 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..7 arguments. got: %d", len(args))
+					}
+
+					components := []int{0, 1, 1, 0, 0, 0}
+					nsec := 0
+					loc := time.Local
+
+					for i, arg := range args {
+						switch {
+						case i == 6:
+							zone, ok := arg.(*StringObject)
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, arg.Class().Name)
+							}
+
+							l, err := parseTimeZone(zone.value)
+							if err != nil {
+								return t.initErrorObject(errors.ArgumentError, "Unknown time zone: %s", zone.value)
+							}
+
+							loc = l
+						case i == 5:
+							if f, ok := arg.(*FloatObject); ok {
+								components[i] = int(f.value)
+								nsec = int(math.Round((f.value - float64(int(f.value))) * float64(time.Second)))
+								continue
+							}
+
+							fallthrough
+						default:
+							n, ok := arg.(*IntegerObject)
+							if !ok {
+								return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, arg.Class().Name)
+							}
+
+							components[i] = n.value
+						}
+					}
+
+					if components[1] < 1 || components[1] > 12 {
+						return t.initErrorObject(errors.ArgumentError, "Expect month to be between 1 and 12. got: %d", components[1])
+					}
+
+					tm := time.Date(components[0], time.Month(components[1]), components[2], components[3], components[4], components[5], nsec, loc)
+
+					return t.vm.initTimeObject(tm)
+				}
+			},
+		},
+		{
+			// Returns the current local time.
+			//
+			// ```ruby
+			// Time.now.year # => 2018
+			// ```
+			//
+			// @return [Time]
+			Name: "now",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initTimeObject(time.Now())
+				}
+			},
+		},
+		{
+			// Parses the string as a time. It accepts RFC 3339 like "2018-01-02T15:04:05+09:00", dates like
+			// "2018-01-02", date times like "2018-01-02 15:04:05" with an optional offset, and the formats of
+			// RFC 1123, RFC 822 and `date`. Times without offsets are in the local time zone.
+			//
+			// ```ruby
+			// Time.parse("2018-01-02T15:04:05Z").hour          # => 15
+			// Time.parse("2018-01-02 15:04:05 +0900").to_s    # => "2018-01-02 15:04:05 +0900"
+			// Time.parse("Tue, 02 Jan 2018 15:04:05 GMT").day # => 2
+			// ```
+			//
+			// @param string [String]
+			// @return [Time]
+			Name: "parse",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					s, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					str := strings.TrimSpace(s.value)
+
+					for _, layout := range timeParsingLayouts {
+						if tm, err := time.ParseInLocation(layout, str, time.Local); err == nil {
+							return t.vm.initTimeObject(tm)
+						}
+					}
+
+					return t.initErrorObject(errors.ArgumentError, "Can't parse time: %s", s.value)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinTimeInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new time that is the given seconds later.
+			//
+			// ```ruby
+			// (Time.at(0) + 1.5).to_f # => 1.5
+			// ```
+			//
+			// @param seconds [Numeric]
+			// @return [Time]
+			Name: "+",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					s, ok := toFloat64(args[0])
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Numeric", args[0].Class().Name)
+					}
+
+					return t.vm.initTimeObject(receiver.(*TimeObject).value.Add(secondsToDuration(s)))
+				}
+			},
+		},
+		{
+			// Returns a new time that is the given seconds earlier, or the seconds between the receiver and the
+			// given time as a Float.
+			//
+			// ```ruby
+			// (Time.at(10) - 1).to_i       # => 9
+			// Time.at(10) - Time.at(8.5)  # => 1.5
+			// ```
+			//
+			// @param other [Numeric/Time]
+			// @return [Time/Float]
+			Name: "-",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					tm := receiver.(*TimeObject).value
+
+					if other, ok := args[0].(*TimeObject); ok {
+						return t.vm.initFloatObject(tm.Sub(other.value).Seconds())
+					}
+
+					s, ok := toFloat64(args[0])
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Numeric or Time", args[0].Class().Name)
+					}
+
+					return t.vm.initTimeObject(tm.Add(-secondsToDuration(s)))
+				}
+			},
+		},
+		{
+			// Compares the time with another time, returns -1, 0 or 1. Returns nil if the argument isn't a Time.
+			// Time includes Comparable, so it has the other comparison operators.
+			//
+			// ```ruby
+			// Time.at(1) <=> Time.at(2) # => -1
+			// Time.at(1) < Time.at(2)   # => true
+			// ```
+			//
+			// @param other [Time]
+			// @return [Integer]
+			Name: "<=>",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					other, ok := args[0].(*TimeObject)
+					if !ok {
+						return NULL
+					}
+
+					tm := receiver.(*TimeObject).value
+
+					switch {
+					case tm.Before(other.value):
+						return t.vm.initIntegerObject(-1)
+					case tm.After(other.value):
+						return t.vm.initIntegerObject(1)
+					default:
+						return t.vm.initIntegerObject(0)
+					}
+				}
+			},
+		},
+		{
+			// Returns true if the argument is a Time of the same instant, regardless of their time zones.
+			//
+			// ```ruby
+			// Time.at(0) == Time.at(0).utc # => true
+			// ```
+			//
+			// @param other [Object]
+			// @return [Boolean]
+			Name: "==",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					other, ok := args[0].(*TimeObject)
+
+					return toBooleanObject(ok && receiver.(*TimeObject).value.Equal(other.value))
+				}
+			},
+		},
+		{
+			// Returns the day of the month.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2).day # => 2
+			// ```
+			//
+			// @return [Integer]
+			Name: "day",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.Day())
+				}
+			},
+		},
+		{
+			// Returns the hour of the day, from 0 to 23.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2, 15).hour # => 15
+			// ```
+			//
+			// @return [Integer]
+			Name: "hour",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.Hour())
+				}
+			},
+		},
+		{
+			// Returns the time in RFC 3339 format, which ISO 8601 is based on.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2, 15, 4, 5, "+09:00").iso8601 # => "2018-01-02T15:04:05+09:00"
+			// ```
+			//
+			// @return [String]
+			Name: "iso8601",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.(*TimeObject).value.Format(time.RFC3339))
+				}
+			},
+		},
+		{
+			// Returns a new time of the same instant in the local time zone, or in the given zone.
+			//
+			// ```ruby
+			// Time.at(0).localtime("+09:00").hour # => 9
+			// ```
+			//
+			// @param zone [String]
+			// @return [Time]
+			Name: "localtime",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					loc := time.Local
+
+					switch len(args) {
+					case 0:
+					case 1:
+						zone, ok := args[0].(*StringObject)
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+						}
+
+						l, err := parseTimeZone(zone.value)
+						if err != nil {
+							return t.initErrorObject(errors.ArgumentError, "Unknown time zone: %s", zone.value)
+						}
+
+						loc = l
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					return t.vm.initTimeObject(receiver.(*TimeObject).value.In(loc))
+				}
+			},
+		},
+		{
+			// Returns the minute of the hour, from 0 to 59.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2, 15, 4).min # => 4
+			// ```
+			//
+			// @return [Integer]
+			Name: "min",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.Minute())
+				}
+			},
+		},
+		{
+			// Returns the month of the year, from 1 to 12.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2).month # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "month",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(int(receiver.(*TimeObject).value.Month()))
+				}
+			},
+		},
+		{
+			// Returns the nanoseconds of the second.
+			//
+			// ```ruby
+			// Time.at(1.5).nsec # => 500000000
+			// ```
+			//
+			// @return [Integer]
+			Name: "nsec",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.Nanosecond())
+				}
+			},
+		},
+		{
+			// Returns the second of the minute, from 0 to 59.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2, 15, 4, 5).sec # => 5
+			// ```
+			//
+			// @return [Integer]
+			Name: "sec",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.Second())
+				}
+			},
+		},
+		{
+			// Formats the time with the directives in the format string:
+			//
+			// - `%Y` year, `%C` century, `%y` year without century, `%m` month, `%B` month name, `%b` abbreviated
+			//   month name, `%d` day of the month, `%e` day of the month padded with a space, `%j` day of the year
+			// - `%H` hour, `%I` hour of the 12-hour clock, `%l` the same padded with a space, `%M` minute, `%S` second,
+			//   `%L` milliseconds, `%N` nanoseconds, `%p` AM or PM, `%P` am or pm
+			// - `%A` weekday name, `%a` abbreviated weekday name, `%u` weekday from Monday as 1, `%w` weekday from
+			//   Sunday as 0
+			// - `%z` offset like +0900, `%:z` offset like +09:00, `%Z` zone abbreviation, `%s` seconds since the epoch
+			// - `%F` is `%Y-%m-%d`, `%T` is `%H:%M:%S`, `%D` is `%m/%d/%y`, `%R` is `%H:%M`, `%r` is `%I:%M:%S %p`,
+			//   `%c` is `%a %b %e %H:%M:%S %Y`, `%%` is a literal %
+			//
+			// The `-` flag like `%-d` removes padding, and the `^` flag like `%^a` upcases the result.
+			//
+			// ```ruby
+			// t = Time.new(2018, 1, 2, 15, 4, 5)
+			// t.strftime("%Y-%m-%d %H:%M:%S") # => "2018-01-02 15:04:05"
+			// t.strftime("%b %-d, %l:%M %p")  # => "Jan 2,  3:04 PM"
+			// ```
+			//
+			// @param format [String]
+			// @return [String]
+			Name: "strftime",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					format, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					return t.vm.initStringObject(strftime(receiver.(*TimeObject).value, format.value))
+				}
+			},
+		},
+		{
+			// Returns the seconds since the Unix epoch as a Float.
+			//
+			// ```ruby
+			// Time.at(1.5).to_f # => 1.5
+			// ```
+			//
+			// @return [Float]
+			Name: "to_f",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initFloatObject(float64(receiver.(*TimeObject).value.UnixNano()) / float64(time.Second))
+				}
+			},
+		},
+		{
+			// Returns the whole seconds since the Unix epoch.
+			//
+			// ```ruby
+			// Time.at(1.5).to_i # => 1
+			// ```
+			//
+			// @return [Integer]
+			Name: "to_i",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(int(receiver.(*TimeObject).value.Unix()))
+				}
+			},
+		},
+		{
+			// Returns a new time of the same instant in UTC.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2, 9, 0, 0, "+09:00").utc.hour # => 0
+			// ```
+			//
+			// @return [Time]
+			Name: "utc",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initTimeObject(receiver.(*TimeObject).value.UTC())
+				}
+			},
+		},
+		{
+			// Returns true if the time is in UTC.
+			//
+			// ```ruby
+			// Time.now.utc.utc? # => true
+			// ```
+			//
+			// @return [Boolean]
+			Name: "utc?",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return toBooleanObject(receiver.(*TimeObject).value.Location() == time.UTC)
+				}
+			},
+		},
+		{
+			// Returns the offset from UTC in seconds.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2, 0, 0, 0, "+09:00").utc_offset # => 32400
+			// ```
+			//
+			// @return [Integer]
+			Name: "utc_offset",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					_, offset := receiver.(*TimeObject).value.Zone()
+					return t.vm.initIntegerObject(offset)
+				}
+			},
+		},
+		{
+			// Returns the day of the week, from 0 for Sunday to 6.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2).wday # => 2
+			// ```
+			//
+			// @return [Integer]
+			Name: "wday",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(int(receiver.(*TimeObject).value.Weekday()))
+				}
+			},
+		},
+		{
+			// Returns the day of the year, from 1 to 366.
+			//
+			// ```ruby
+			// Time.new(2018, 2, 1).yday # => 32
+			// ```
+			//
+			// @return [Integer]
+			Name: "yday",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.YearDay())
+				}
+			},
+		},
+		{
+			// Returns the year.
+			//
+			// ```ruby
+			// Time.new(2018, 1, 2).year # => 2018
+			// ```
+			//
+			// @return [Integer]
+			Name: "year",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(receiver.(*TimeObject).value.Year())
+				}
+			},
+		},
+		{
+			// Returns the abbreviated name of the time zone.
+			//
+			// ```ruby
+			// Time.now.utc.zone # => "UTC"
+			// ```
+			//
+			// @return [String]
+			Name: "zone",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					name, _ := receiver.(*TimeObject).value.Zone()
+					return t.vm.initStringObject(name)
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initTimeObject(value time.Time) *TimeObject {
+	return &TimeObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.TimeClass)},
+		value:   value,
+	}
+}
+
+func (vm *VM) initTimeClass() *RClass {
+	tc := vm.initializeClass(classes.TimeClass, false)
+	tc.setBuiltinMethods(builtinTimeClassMethods(), true)
+	tc.setBuiltinMethods(builtinTimeInstanceMethods(), false)
+	tc.include(vm.topLevelClass(classes.ComparableModule))
+	return tc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object
+func (t *TimeObject) Value() interface{} {
+	return t.value
+}
+
+// Returns the time like "2018-01-02 15:04:05 +0900"
+func (t *TimeObject) toString() string {
+	return t.value.Format("2006-01-02 15:04:05 -0700")
+}
+
+// Returns the time in RFC 3339 format as a JSON string
+func (t *TimeObject) toJSON() string {
+	return strconv.Quote(t.value.Format(time.RFC3339Nano))
+}
+
+// Other helper functions -----------------------------------------------
+
+// secondsToDuration converts seconds, which can have a fractional part, into a duration
+func secondsToDuration(seconds float64) time.Duration {
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
+
+// parseTimeZone returns the location of "UTC", an offset like "+09:00" or "-0500", or a location name like "Asia/Taipei"
+func parseTimeZone(zone string) (*time.Location, error) {
+	if zone == "UTC" || zone == "Z" {
+		return time.UTC, nil
+	}
+
+	if strings.HasPrefix(zone, "+") || strings.HasPrefix(zone, "-") {
+		for _, layout := range []string{"-07:00", "-0700", "-07"} {
+			if tm, err := time.Parse(layout, zone); err == nil {
+				_, offset := tm.Zone()
+				return time.FixedZone("", offset), nil
+			}
+		}
+
+		return nil, fmt.Errorf("invalid offset %s", zone)
+	}
+
+	return time.LoadLocation(zone)
+}
+
+// strftime formats the time like Ruby's Time#strftime, unknown directives are kept as they are
+func strftime(tm time.Time, format string) string {
+	var b strings.Builder
+
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' || i+1 == len(format) {
+			b.WriteByte(format[i])
+			continue
+		}
+
+		start := i
+		i++
+
+		flag := byte(0)
+		if strings.IndexByte("-^:", format[i]) >= 0 && i+1 < len(format) {
+			flag = format[i]
+			i++
+		}
+
+		s, ok := strftimeDirective(tm, format[i], flag)
+		if !ok {
+			b.WriteString(format[start : i+1])
+			continue
+		}
+
+		if flag == '^' {
+			s = strings.ToUpper(s)
+		}
+
+		b.WriteString(s)
+	}
+
+	return b.String()
+}
+
+// strftimeDirective returns the result of the directive, ok is false if it's unknown
+func strftimeDirective(tm time.Time, directive, flag byte) (string, bool) {
+	pad := func(n, width int, padding string) string {
+		s := strconv.Itoa(n)
+
+		if flag == '-' {
+			return s
+		}
+
+		for len(s) < width {
+			s = padding + s
+		}
+
+		return s
+	}
+
+	hour12 := tm.Hour() % 12
+	if hour12 == 0 {
+		hour12 = 12
+	}
+
+	switch directive {
+	case 'Y':
+		return pad(tm.Year(), 4, "0"), true
+	case 'C':
+		return pad(tm.Year()/100, 2, "0"), true
+	case 'y':
+		return pad(tm.Year()%100, 2, "0"), true
+	case 'm':
+		return pad(int(tm.Month()), 2, "0"), true
+	case 'B':
+		return tm.Month().String(), true
+	case 'b', 'h':
+		return tm.Month().String()[:3], true
+	case 'd':
+		return pad(tm.Day(), 2, "0"), true
+	case 'e':
+		return pad(tm.Day(), 2, " "), true
+	case 'j':
+		return pad(tm.YearDay(), 3, "0"), true
+	case 'H':
+		return pad(tm.Hour(), 2, "0"), true
+	case 'k':
+		return pad(tm.Hour(), 2, " "), true
+	case 'I':
+		return pad(hour12, 2, "0"), true
+	case 'l':
+		return pad(hour12, 2, " "), true
+	case 'M':
+		return pad(tm.Minute(), 2, "0"), true
+	case 'S':
+		return pad(tm.Second(), 2, "0"), true
+	case 'L':
+		return pad(tm.Nanosecond()/int(time.Millisecond), 3, "0"), true
+	case 'N':
+		return pad(tm.Nanosecond(), 9, "0"), true
+	case 'p':
+		return tm.Format("PM"), true
+	case 'P':
+		return strings.ToLower(tm.Format("PM")), true
+	case 'A':
+		return tm.Weekday().String(), true
+	case 'a':
+		return tm.Weekday().String()[:3], true
+	case 'u':
+		wday := int(tm.Weekday())
+		if wday == 0 {
+			wday = 7
+		}
+
+		return strconv.Itoa(wday), true
+	case 'w':
+		return strconv.Itoa(int(tm.Weekday())), true
+	case 'z':
+		if flag == ':' {
+			return tm.Format("-07:00"), true
+		}
+
+		return tm.Format("-0700"), true
+	case 'Z':
+		name, _ := tm.Zone()
+		return name, true
+	case 's':
+		return strconv.FormatInt(tm.Unix(), 10), true
+	case 'F':
+		return strftime(tm, "%Y-%m-%d"), true
+	case 'T', 'X':
+		return strftime(tm, "%H:%M:%S"), true
+	case 'D', 'x':
+		return strftime(tm, "%m/%d/%y"), true
+	case 'R':
+		return strftime(tm, "%H:%M"), true
+	case 'r':
+		return strftime(tm, "%I:%M:%S %p"), true
+	case 'c':
+		return strftime(tm, "%a %b %e %H:%M:%S %Y"), true
+	case 'n':
+		return "\n", true
+	case 't':
+		return "\t", true
+	case '%':
+		return "%", true
+	default:
+		return "", false
+	}
+}
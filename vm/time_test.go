@@ -0,0 +1,178 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestTimeClassMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.now.class.name`, "Time"},
+		{`Time.now - Time.now < 1`, true},
+		{`Time.new.year > 2017`, true},
+		{`Time.at(0).utc.to_s`, "1970-01-01 00:00:00 +0000"},
+		{`Time.at(1.5).to_f`, 1.5},
+		{`Time.at(1.5).nsec`, 500000000},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "UTC").to_s`, "2018-01-02 15:04:05 +0000"},
+		{`Time.new(2018, 1, 2, 15, 4, 5.25, "UTC").nsec`, 250000000},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "+09:00").to_s`, "2018-01-02 15:04:05 +0900"},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "-0500").utc_offset`, -18000},
+		{`Time.new(2018, 1, 2, 0, 0, 0, "Asia/Tokyo").utc_offset`, 32400},
+		{`Time.new(2018).to_s == Time.new(2018, 1, 1, 0, 0, 0).to_s`, true},
+		{`Time.parse("2018-01-02T15:04:05Z").to_s`, "2018-01-02 15:04:05 +0000"},
+		{`Time.parse("2018-01-02T15:04:05.123+09:00").strftime("%L %z")`, "123 +0900"},
+		{`Time.parse("2018-01-02 15:04:05 +0900").to_s`, "2018-01-02 15:04:05 +0900"},
+		{`Time.parse("2018-01-02 15:04:05") == Time.new(2018, 1, 2, 15, 4, 5)`, true},
+		{`Time.parse(" 2018-01-02 ") == Time.new(2018, 1, 2)`, true},
+		{`Time.parse("Tue, 02 Jan 2018 15:04:05 +0900").hour`, 15},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeArithmeticAndComparison(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`(Time.at(0) + 60).to_i`, 60},
+		{`(Time.at(0) + 1.5).to_f`, 1.5},
+		{`(Time.at(10) - 1).to_i`, 9},
+		{`Time.at(10) - Time.at(8.5)`, 1.5},
+		{`Time.at(1) <=> Time.at(2)`, -1},
+		{`Time.at(2) <=> Time.at(1)`, 1},
+		{`Time.at(1) <=> Time.at(1)`, 0},
+		{`Time.at(1) <=> 1`, nil},
+		{`Time.at(1) < Time.at(2)`, true},
+		{`Time.at(1) >= Time.at(2)`, false},
+		{`Time.at(1).between?(Time.at(0), Time.at(2))`, true},
+		{`Time.at(0) == Time.at(0).utc`, true},
+		{`Time.at(0) == Time.at(1)`, false},
+		{`Time.at(0) == 0`, false},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeComponentMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.new(2018, 2, 3, 15, 4, 5).year`, 2018},
+		{`Time.new(2018, 2, 3, 15, 4, 5).month`, 2},
+		{`Time.new(2018, 2, 3, 15, 4, 5).day`, 3},
+		{`Time.new(2018, 2, 3, 15, 4, 5).hour`, 15},
+		{`Time.new(2018, 2, 3, 15, 4, 5).min`, 4},
+		{`Time.new(2018, 2, 3, 15, 4, 5).sec`, 5},
+		{`Time.new(2018, 2, 3).wday`, 6},
+		{`Time.new(2018, 2, 3).yday`, 34},
+		{`Time.at(1.5).to_i`, 1},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeZoneMethods(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.new(2018, 1, 2, 9, 0, 0, "+09:00").utc.to_s`, "2018-01-02 00:00:00 +0000"},
+		{`Time.now.utc.utc?`, true},
+		{`Time.new(2018, 1, 2, 0, 0, 0, "+09:00").utc?`, false},
+		{`Time.now.utc.zone`, "UTC"},
+		{`Time.new(2018, 1, 2, 0, 0, 0, "Asia/Tokyo").zone`, "JST"},
+		{`Time.at(0).localtime("+09:00").to_s`, "1970-01-01 09:00:00 +0900"},
+		{`Time.at(0).localtime("America/New_York").hour`, 19},
+		{`Time.at(0).utc.localtime.to_i`, 0},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "+09:00").iso8601`, "2018-01-02T15:04:05+09:00"},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "UTC").iso8601`, "2018-01-02T15:04:05Z"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeStrftimeMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%Y-%m-%d %H:%M:%S")`, "2018-01-02 15:04:05"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%F %T")`, "2018-01-02 15:04:05"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%D %R")`, "01/02/18 15:04"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%b %-d, %l:%M %p")`, "Jan 2,  3:04 PM"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%B %e %A %a %P")`, "January  2 Tuesday Tue pm"},
+		{`Time.new(2018, 1, 2, 0, 4, 5).strftime("%I %r")`, "12 12:04:05 AM"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%^a %^B")`, "TUE JANUARY"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%C %y %j %u %w")`, "20 18 002 2 2"},
+		{`Time.new(2018, 1, 7).strftime("%u %w")`, "7 0"},
+		{`Time.new(2018, 1, 2, 15, 4, 5.123456789, "UTC").strftime("%L %N")`, "123 123456789"},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "+09:30").strftime("%z %:z")`, "+0930 +09:30"},
+		{`Time.new(2018, 1, 2, 15, 4, 5, "UTC").strftime("%Z %s")`, "UTC 1514905445"},
+		{`Time.new(2018, 1, 2, 15, 4, 5).strftime("%c")`, "Tue Jan  2 15:04:05 2018"},
+		{`Time.new(2018, 1, 2).strftime("100%% %Q %")`, "100% %Q %"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`Time.now(1)`, "ArgumentError: Expect 0 argument. got: 1", 1},
+		{`Time.at`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`Time.at("0")`, "TypeError: Expect argument to be Integer or Float. got: String", 1},
+		{`Time.new(2018, 13)`, "ArgumentError: Expect month to be between 1 and 12. got: 13", 1},
+		{`Time.new(2018, "1")`, "TypeError: Expect argument to be Integer. got: String", 1},
+		{`Time.new(2018, 1, 2, 3, 4, 5, 9)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Time.new(2018, 1, 2, 3, 4, 5, "Mars/Olympus")`, "ArgumentError: Unknown time zone: Mars/Olympus", 1},
+		{`Time.new(2018, 1, 2, 3, 4, 5, "+9")`, "ArgumentError: Unknown time zone: +9", 1},
+		{`Time.new(1, 2, 3, 4, 5, 6, "UTC", 8)`, "ArgumentError: Expect 0..7 arguments. got: 8", 1},
+		{`Time.parse("yesterday")`, "ArgumentError: Can't parse time: yesterday", 1},
+		{`Time.parse(1)`, "TypeError: Expect argument to be String. got: Integer", 1},
+		{`Time.now + "1"`, "TypeError: Expect argument to be Numeric. got: String", 1},
+		{`Time.now - "1"`, "TypeError: Expect argument to be Numeric or Time. got: String", 1},
+		{`Time.now.strftime`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`Time.now.localtime("Nowhere")`, "ArgumentError: Unknown time zone: Nowhere", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}
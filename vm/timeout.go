@@ -0,0 +1,133 @@
+package vm
+
+import (
+	"context"
+	"time"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// Timeout is a module for stopping a block that runs too long, like a slow HTTP request. When the time is up,
+// a `Timeout::Error` is raised in the block, which stops it unless the block rescues the error.
+//
+// ```ruby
+// begin
+//   Timeout.timeout(0.5) do
+//     sleep(2)
+//   end
+// rescue Timeout::Error => e
+//   e.message # => "execution expired"
+// end
+// ```
+//
+// The error is raised between the block's instructions, and methods that wait like `sleep`, `Channel#receive`,
+// `Channel.select` and `Net::HTTP.get` stop waiting when the time is up.
+
+// Class methods --------------------------------------------------------
+func builtinTimeoutClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Runs the block and returns its result, or raises a `Timeout::Error` if it doesn't finish in the given
+			// seconds. The block runs without a time limit if the seconds is nil or 0.
+			//
+			// ```ruby
+			// Timeout.timeout(1) do
+			//   Net::HTTP.get("http://example.com")
+			// end
+			// ```
+			//
+			// @param sec [Integer/Float]
+			// @return [Object]
+			Name: "timeout",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					if blockFrame == nil {
+						return t.initErrorObject(errors.InternalError, errors.CantYieldWithoutBlockFormat)
+					}
+
+					var seconds float64
+
+					switch sec := args[0].(type) {
+					case *IntegerObject:
+						seconds = float64(sec.value)
+					case *FloatObject:
+						seconds = sec.value
+					case *NullObject:
+					default:
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, "Integer or Float", args[0].Class().Name)
+					}
+
+					if seconds < 0 {
+						return t.initErrorObject(errors.ArgumentError, "Time interval must not be negative. got: %s", args[0].toString())
+					}
+
+					if seconds == 0 {
+						return t.builtinMethodYield(blockFrame).Target
+					}
+
+					parent := t.timeout
+					ctx, cancel := context.WithTimeout(t.context(), time.Duration(seconds*float64(time.Second)))
+					t.timeout = ctx
+
+					defer func() {
+						cancel()
+						t.timeout = parent
+					}()
+
+					return t.builtinMethodYield(blockFrame).Target
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initTimeoutModule() *RClass {
+	m := vm.initializeClass(classes.TimeoutModule, true)
+	m.setBuiltinMethods(builtinTimeoutClassMethods(), true)
+
+	e := vm.initializeClass("Error", false)
+	e.inherits(vm.topLevelClass(errors.RuntimeError))
+	e.scope = m
+	m.setClassConstant(e)
+
+	return m
+}
+
+// Other helper functions -----------------------------------------------
+
+// context returns the context of the timeout that the thread is running in, or an empty context
+func (t *thread) context() context.Context {
+	if t.timeout == nil {
+		return context.Background()
+	}
+
+	return t.timeout
+}
+
+// timedOut returns a channel that's closed when the thread's timeout is up, or nil if there's no timeout,
+// so methods that wait can stop waiting by selecting it
+func (t *thread) timedOut() <-chan struct{} {
+	if t.timeout == nil {
+		return nil
+	}
+
+	return t.timeout.Done()
+}
+
+// timeoutError returns the Timeout::Error of the thread's timeout, and stops checking the timeout so the error
+// is raised only once
+func (t *thread) timeoutError() *Error {
+	t.timeout = nil
+	errClass := t.vm.topLevelClass(classes.TimeoutModule).getClassConstant("Error")
+
+	return t.initErrorObjectWithClass(errClass, "execution expired")
+}
@@ -0,0 +1,128 @@
+package vm
+
+import (
+	"testing"
+)
+
+func TestTimeoutModule(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`Timeout::Error.superclass.name`, "RuntimeError"},
+		{`
+		Timeout.timeout(1) do
+		  10
+		end
+		`, 10},
+		{`
+		Timeout.timeout(nil) do
+		  10
+		end
+		`, 10},
+		{`
+		Timeout.timeout(0) do
+		  10
+		end
+		`, 10},
+		{`
+		begin
+		  Timeout.timeout(0.05) do
+		    sleep(1)
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
+		{`
+		begin
+		  Timeout.timeout(0.05) do
+		    loop do
+		    end
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
+		{`
+		def slow
+		  sleep(1)
+		end
+
+		begin
+		  Timeout.timeout(0.05) do
+		    Timeout.timeout(5) do
+		      slow
+		    end
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
+		{`
+		Timeout.timeout(0.05) do
+		  begin
+		    sleep(1)
+		  rescue Timeout::Error
+		    "rescued in the block"
+		  end
+		end
+		`, "rescued in the block"},
+		{`
+		c = Channel.new
+
+		begin
+		  Timeout.timeout(0.05) do
+		    c.receive
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
+		{`
+		c = Channel.new
+
+		begin
+		  Timeout.timeout(0.05) do
+		    Channel.select(c, timeout: 5)
+		  end
+		rescue Timeout::Error => e
+		  e.message
+		end
+		`, "execution expired"},
+		{`
+		Timeout.timeout(0.05) do
+		  sleep(0.01)
+		end
+		sleep(0.1)
+		`, 0},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestTimeoutModuleFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`
+		Timeout.timeout(0.05) do
+		  sleep(1)
+		end
+		`, "Error: execution expired", 3},
+		{`Timeout.timeout(1)`, "InternalError: Can't yield without a block", 1},
+		{`Timeout.timeout do; end`, "ArgumentError: Expect 1 argument. got: 0", 1},
+		{`Timeout.timeout("1") do; end`, "TypeError: Expect argument to be Integer or Float. got: String", 1},
+		{`Timeout.timeout(-1) do; end`, "ArgumentError: Time interval must not be negative. got: -1", 1},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+	}
+}
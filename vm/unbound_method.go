@@ -0,0 +1,211 @@
+package vm
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// UnboundMethodObject represents a method taken out of a class or module with `instance_method`,
+// which isn't bound to any receiver.
+//
+// ```ruby
+// class Foo
+//   def bar; end
+// end
+//
+// m = Foo.instance_method(:bar)
+// m.name  # => :bar
+// m.owner # => Foo
+// m.bind(Foo.new).call
+// ```
+//
+// **Note:**
+//
+// - `UnboundMethod.new` is not supported.
+type UnboundMethodObject struct {
+	*baseObj
+	name string
+	// owner is the class or module that defines the method
+	owner  *RClass
+	method Object
+}
+
+// Class methods --------------------------------------------------------
+func builtinUnboundMethodClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.unsupportedMethodError("#new", receiver)
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinUnboundMethodInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns the number of the method's parameters like `Method#arity`.
+			//
+			// ```ruby
+			// Foo.instance_method(:bar).arity # => 0
+			// ```
+			//
+			// @return [Integer]
+			Name: "arity",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initIntegerObject(methodArity(receiver.(*UnboundMethodObject).method))
+				}
+			},
+		},
+		{
+			// Returns a Method that binds the method to the given object, which must be an instance of the owner.
+			// Methods of modules can be bound to any object.
+			//
+			// ```ruby
+			// class Foo
+			//   def name
+			//     "foo"
+			//   end
+			// end
+			//
+			// class Bar < Foo
+			//   def name
+			//     "bar"
+			//   end
+			// end
+			//
+			// Foo.instance_method(:name).bind(Bar.new).call # => "foo"
+			// Foo.instance_method(:name).bind(1)            # => TypeError
+			// ```
+			//
+			// @param object [Object]
+			// @return [Method]
+			Name: "bind",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					um := receiver.(*UnboundMethodObject)
+
+					if !um.canBind(args[0]) {
+						return t.initErrorObject(errors.TypeError, "Expect argument to be an instance of %s. got: %s", um.owner.Name, args[0].Class().Name)
+					}
+
+					return t.vm.initBoundMethodObject(um.name, um.owner, um.method, args[0])
+				}
+			},
+		},
+		{
+			// Returns the name of the method.
+			//
+			// ```ruby
+			// Array.instance_method(:push).name # => :push
+			// ```
+			//
+			// @return [Symbol]
+			Name: "name",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initSymbolObject(receiver.(*UnboundMethodObject).name)
+				}
+			},
+		},
+		{
+			// Returns the class or module that defines the method.
+			//
+			// ```ruby
+			// module Greet
+			//   def hi; end
+			// end
+			//
+			// class Foo
+			//   include(Greet)
+			// end
+			//
+			// Foo.instance_method(:hi).owner # => Greet
+			// ```
+			//
+			// @return [Class]
+			Name: "owner",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return receiver.(*UnboundMethodObject).owner
+				}
+			},
+		},
+		{
+			// Returns the string representation of the method with its owner.
+			//
+			// ```ruby
+			// Array.instance_method(:push).to_s # => "#<UnboundMethod: Array#push>"
+			// ```
+			//
+			// @return [String]
+			Name: "to_s",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					return t.vm.initStringObject(receiver.toString())
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initUnboundMethodObject(name string, owner *RClass, method Object) *UnboundMethodObject {
+	return &UnboundMethodObject{
+		baseObj: &baseObj{class: vm.topLevelClass(classes.UnboundMethodClass)},
+		name:    name,
+		owner:   owner,
+		method:  method,
+	}
+}
+
+func (vm *VM) initUnboundMethodClass() *RClass {
+	uc := vm.initializeClass(classes.UnboundMethodClass, false)
+	uc.setBuiltinMethods(builtinUnboundMethodInstanceMethods(), false)
+	uc.setBuiltinMethods(builtinUnboundMethodClassMethods(), true)
+	return uc
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the method's owner and name
+func (um *UnboundMethodObject) toString() string {
+	return fmt.Sprintf("#<UnboundMethod: %s#%s>", um.owner.Name, um.name)
+}
+
+// Returns the method's string representation as a JSON string
+func (um *UnboundMethodObject) toJSON() string {
+	return strconv.Quote(um.toString())
+}
+
+// canBind returns true if the object has the method's owner in its method lookup chain, or the owner is a module
+func (um *UnboundMethodObject) canBind(obj Object) bool {
+	if um.owner.isModule || isKindOf(obj, um.owner) {
+		return true
+	}
+
+	if s := obj.SingletonClass(); s != nil {
+		for _, c := range s.ancestors() {
+			if c == um.owner {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 )
 
 // Version stores current Goby version
@@ -45,6 +46,17 @@ var standardLibraries = map[string]func(*VM){
 	"db":                initDBClass,
 	"plugin":            initPluginClass,
 	"json":              initJSONClass,
+	"yaml":              initYAMLClass,
+	"csv":               initCSVClass,
+	"socket":            initSocketClasses,
+	"digest":            initDigestModule,
+	"openssl":           initOpenSSLModule,
+	"tempfile":          initTempfileClass,
+	"tmpdir":            initTmpdir,
+	"fileutils":         initFileUtilsModule,
+	"set":               initSetClass,
+	"ostruct":           initOpenStructClass,
+	"actor":             initActorClass,
 }
 
 // VM represents a stack based virtual machine.
@@ -71,6 +83,21 @@ type VM struct {
 
 	channelObjectMap *objectMap
 
+	// symbolTable holds interned symbols by their names
+	symbolTable *sync.Map
+
+	// globalVariables holds the values of global variables like `$foo` by their names
+	globalVariables *sync.Map
+
+	// randomGenerator is the global generator used by Kernel#rand and Kernel#srand
+	randomGenerator *RandomObject
+
+	// atExitBlocks holds the blocks registered by Kernel#at_exit, they're run in reverse order when the program ends
+	atExitBlocks []*callFrame
+
+	// tempPaths holds the files and directories created by Tempfile.new and Dir.mktmpdir, they're removed when the program ends
+	tempPaths []string
+
 	sync.Mutex
 
 	mode int
@@ -121,6 +148,9 @@ func New(fileDir string, args []string) (vm *VM, e error) {
 	vm.initConstants()
 	vm.mainObj = vm.initMainObj()
 	vm.channelObjectMap = &objectMap{store: &sync.Map{}}
+	vm.symbolTable = &sync.Map{}
+	vm.globalVariables = &sync.Map{}
+	vm.initGlobalVariables()
 
 	for _, fn := range vm.libFiles {
 		vm.execGobyLib(fn)
@@ -141,6 +171,73 @@ func (vm *VM) newThread() *thread {
 
 // ExecInstructions accepts a sequence of bytecodes and use vm to evaluate them.
 func (vm *VM) ExecInstructions(sets []*bytecode.InstructionSet, fn string) {
+	vm.globalVariables.Store("$0", vm.initStringObject(fn))
+	vm.execInstructions(sets, fn)
+	vm.runAtExitBlocks()
+	vm.removeTempPaths()
+}
+
+// runAtExitBlocks runs the blocks registered by Kernel#at_exit in reverse order, each of them is run only once.
+// The blocks are run on a new thread because the main thread may have stopped by an uncaught error.
+func (vm *VM) runAtExitBlocks() {
+	for {
+		vm.Lock()
+
+		if len(vm.atExitBlocks) == 0 {
+			vm.Unlock()
+			return
+		}
+
+		last := len(vm.atExitBlocks) - 1
+		blockFrame := vm.atExitBlocks[last]
+		vm.atExitBlocks = vm.atExitBlocks[:last]
+		vm.Unlock()
+
+		t := vm.newThread()
+		result := t.builtinMethodYield(blockFrame)
+
+		if err, ok := result.Target.(*Error); ok && !err.rescued {
+			fmt.Fprintln(vm.stderr(), err.Message)
+		}
+	}
+}
+
+// addTempPath registers a temporary file or directory to be removed when the program ends.
+func (vm *VM) addTempPath(path string) {
+	vm.Lock()
+	defer vm.Unlock()
+
+	vm.tempPaths = append(vm.tempPaths, path)
+}
+
+// removeTempPaths removes the temporary files and directories registered by addTempPath.
+func (vm *VM) removeTempPaths() {
+	vm.Lock()
+	defer vm.Unlock()
+
+	for _, path := range vm.tempPaths {
+		os.RemoveAll(path)
+	}
+
+	vm.tempPaths = nil
+}
+
+// exitWithError runs the at_exit blocks and stops the program with the uncaught error. A SystemExit stops the
+// program with its status silently, other errors print their messages and stop it with status 1.
+func (vm *VM) exitWithError(err *Error) {
+	vm.runAtExitBlocks()
+	vm.removeTempPaths()
+
+	if err.Class() == vm.topLevelClass(errors.SystemExit) {
+		os.Exit(err.status)
+	}
+
+	fmt.Println(err.Message)
+	os.Exit(1)
+}
+
+// execInstructions evaluates the bytecodes of the program or a required file
+func (vm *VM) execInstructions(sets []*bytecode.InstructionSet, fn string) {
 	p := newInstructionTranslator(fn)
 	p.vm = vm
 	p.transferInstructionSets(sets)
@@ -188,19 +285,47 @@ func (vm *VM) initConstants() {
 	vm.objectClass = initObjectClass(cClass)
 	vm.topLevelClass(classes.ObjectClass).setClassConstant(cClass)
 
+	// Init builtin modules first, since builtin classes include them
+	vm.objectClass.setClassConstant(vm.initComparableModule())
+	vm.objectClass.setClassConstant(vm.initEnumerableModule())
+
+	// IO is initialized before other builtin classes, since File inherits it
+	vm.objectClass.setClassConstant(vm.initIOClass())
+
+	// Queue is initialized before other builtin classes, since SizedQueue inherits it
+	vm.objectClass.setClassConstant(vm.initQueueClass())
+
 	// Init builtin classes
 	builtinClasses := []*RClass{
 		vm.initIntegerClass(),
+		vm.initBigIntClass(),
+		vm.initFloatClass(),
+		vm.initRationalClass(),
 		vm.initStringClass(),
+		vm.initSymbolClass(),
 		vm.initBoolClass(),
 		vm.initNullClass(),
 		vm.initArrayClass(),
 		vm.initHashClass(),
 		vm.initRangeClass(),
 		vm.initMethodClass(),
+		vm.initBoundMethodClass(),
+		vm.initUnboundMethodClass(),
 		vm.initChannelClass(),
+		vm.initThreadClass(),
+		vm.initWaitGroupClass(),
+		vm.initAtomicClass(),
+		vm.initSizedQueueClass(),
 		vm.initGoClass(),
 		vm.initFileClass(),
+		vm.initDirClass(),
+		vm.initTimeClass(),
+		vm.initRegexpClass(),
+		vm.initMatchDataClass(),
+		vm.initEncodingClass(),
+		vm.initRandomClass(),
+		vm.initProcClass(),
+		vm.initEnumeratorClass(),
 	}
 
 	// Init error classes
@@ -210,6 +335,17 @@ func (vm *VM) initConstants() {
 		vm.objectClass.setClassConstant(c)
 	}
 
+	// Init builtin modules that hold instances of builtin classes
+	vm.objectClass.setClassConstant(vm.initMathModule())
+	vm.objectClass.setClassConstant(vm.initObjectSpaceModule())
+	vm.objectClass.setClassConstant(vm.initGCModule())
+	vm.objectClass.setClassConstant(vm.initConcurrentModule())
+	vm.objectClass.setClassConstant(vm.initTimeoutModule())
+	vm.objectClass.setClassConstant(vm.initProcessModule())
+	vm.objectClass.setClassConstant(vm.initMarshalModule())
+
+	vm.randomGenerator = vm.initRandomObject(time.Now().UnixNano())
+
 	// Init ARGV
 	args := []Object{}
 
@@ -228,9 +364,9 @@ func (vm *VM) initConstants() {
 	}
 
 	vm.objectClass.constants["ENV"] = &Pointer{Target: vm.initHashObject(envs)}
-	vm.objectClass.constants["STDOUT"] = &Pointer{Target: vm.initFileObject(os.Stdout)}
-	vm.objectClass.constants["STDERR"] = &Pointer{Target: vm.initFileObject(os.Stderr)}
-	vm.objectClass.constants["STDIN"] = &Pointer{Target: vm.initFileObject(os.Stdin)}
+	vm.objectClass.constants["STDOUT"] = &Pointer{Target: vm.initIOObject(os.Stdout)}
+	vm.objectClass.constants["STDERR"] = &Pointer{Target: vm.initIOObject(os.Stderr)}
+	vm.objectClass.constants["STDIN"] = &Pointer{Target: vm.initIOObject(os.Stdin)}
 }
 
 func (vm *VM) topLevelClass(cn string) *RClass {
@@ -309,11 +445,11 @@ func (vm *VM) loadConstant(name string, isModule bool) *RClass {
 	return c
 }
 
-func (vm *VM) lookupConstant(cf *callFrame, constName string) (constant *Pointer) {
+func (vm *VM) lookupConstant(t *thread, cf *callFrame, constName string) (constant *Pointer) {
 	var namespace *RClass
 	var hasNamespace bool
 
-	top := vm.mainThread.stack.top()
+	top := t.stack.top()
 
 	if top == nil {
 		hasNamespace = false
@@ -375,7 +511,7 @@ func (vm *VM) execRequiredFile(filepath string, file []byte) {
 
 	// This creates new execution environments for required file, including new instruction set table.
 	// So we need to copy old instruction sets and restore them later, otherwise current program's instruction set would be overwrite.
-	vm.ExecInstructions(instructionSets, filepath)
+	vm.execInstructions(instructionSets, filepath)
 
 	// Restore instruction sets.
 	vm.isTables[bytecode.MethodDef] = oldMethodTable
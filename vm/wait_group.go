@@ -0,0 +1,200 @@
+package vm
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// WaitGroupObject waits for a collection of threads to finish, it carries a golang `sync.WaitGroup`.
+// The counter is increased by `add` before starting the threads, and each thread calls `done` when it
+// finishes. Then `wait` blocks until the counter becomes zero.
+//
+// ```ruby
+// wg = WaitGroup.new
+// results = Channel.new(3)
+//
+// 3.times do |i|
+//   wg.add
+//   thread do
+//     results.send(i * 10)
+//     wg.done
+//   end
+// end
+//
+// wg.wait
+// results.receive + results.receive + results.receive # => 30
+// ```
+type WaitGroupObject struct {
+	*baseObj
+	waitGroup *sync.WaitGroup
+}
+
+// Class methods --------------------------------------------------------
+func builtinWaitGroupClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Returns a new WaitGroup whose counter is zero.
+			//
+			// ```ruby
+			// WaitGroup.new
+			// ```
+			//
+			// @return [WaitGroup]
+			Name: "new",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					return t.vm.initWaitGroupObject()
+				}
+			},
+		},
+	}
+}
+
+// Instance methods -----------------------------------------------------
+func builtinWaitGroupInstanceMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Adds the given number to the counter, which is 1 by default. It raises a ThreadError if the counter
+			// becomes negative.
+			//
+			// ```ruby
+			// wg = WaitGroup.new
+			// wg.add(2)
+			// ```
+			//
+			// @param delta [Integer]
+			// @return [WaitGroup]
+			Name: "add",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					delta := 1
+
+					switch len(args) {
+					case 0:
+					case 1:
+						i, ok := args[0].(*IntegerObject)
+
+						if !ok {
+							return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.IntegerClass, args[0].Class().Name)
+						}
+
+						delta = i.value
+					default:
+						return t.initErrorObject(errors.ArgumentError, "Expect 0..1 argument. got: %d", len(args))
+					}
+
+					wg := receiver.(*WaitGroupObject)
+
+					if !wg.add(delta) {
+						return t.initErrorObject(errors.ThreadError, "Negative WaitGroup counter")
+					}
+
+					return wg
+				}
+			},
+		},
+		{
+			// Decreases the counter by 1, it's called when a thread finishes. It raises a ThreadError if the
+			// counter becomes negative.
+			//
+			// ```ruby
+			// wg = WaitGroup.new
+			// wg.add
+			// thread do
+			//   wg.done
+			// end
+			// wg.wait
+			// ```
+			//
+			// @return [WaitGroup]
+			Name: "done",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					wg := receiver.(*WaitGroupObject)
+
+					if !wg.add(-1) {
+						return t.initErrorObject(errors.ThreadError, "Negative WaitGroup counter")
+					}
+
+					return wg
+				}
+			},
+		},
+		{
+			// Blocks until the counter becomes zero.
+			//
+			// ```ruby
+			// wg = WaitGroup.new
+			// wg.wait # returns immediately
+			// ```
+			//
+			// @return [WaitGroup]
+			Name: "wait",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 0 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 0 argument. got: %d", len(args))
+					}
+
+					wg := receiver.(*WaitGroupObject)
+					wg.waitGroup.Wait()
+
+					return wg
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func (vm *VM) initWaitGroupObject() *WaitGroupObject {
+	return &WaitGroupObject{
+		baseObj:   &baseObj{class: vm.topLevelClass(classes.WaitGroupClass)},
+		waitGroup: &sync.WaitGroup{},
+	}
+}
+
+func (vm *VM) initWaitGroupClass() *RClass {
+	class := vm.initializeClass(classes.WaitGroupClass, false)
+	class.setBuiltinMethods(builtinWaitGroupClassMethods(), true)
+	class.setBuiltinMethods(builtinWaitGroupInstanceMethods(), false)
+	return class
+}
+
+// Polymorphic helper functions -----------------------------------------
+
+// Returns the object's name as the string format
+func (wg *WaitGroupObject) toString() string {
+	return fmt.Sprintf("#<WaitGroup:%p>", wg.waitGroup)
+}
+
+// Alias of toString
+func (wg *WaitGroupObject) toJSON() string {
+	return wg.toString()
+}
+
+// add adds the delta to the golang WaitGroup's counter, it returns false if the counter becomes negative
+func (wg *WaitGroupObject) add(delta int) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	wg.waitGroup.Add(delta)
+	return true
+}
@@ -0,0 +1,815 @@
+package vm
+
+import (
+	"fmt"
+	"io/ioutil"
+	"math"
+	"math/big"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/goby-lang/goby/vm/classes"
+	"github.com/goby-lang/goby/vm/errors"
+)
+
+// YAML is loaded by `require "yaml"`, it converts YAML documents into hashes and arrays and the other way around.
+// It supports the block and flow styles of mappings and sequences, quoted and plain scalars, literal (`|`) and
+// folded (`>`) block scalars, and comments. Anchors, aliases and tags are not supported, and only the first
+// document of a stream is loaded.
+//
+// ```ruby
+// require "yaml"
+//
+// config = YAML.load("
+// name: goby
+// ports: [3000, 3001]
+// database:
+//   adapter: postgres
+//   pool: 5
+// ")
+// config["database"]["pool"] # => 5
+//
+// YAML.dump({ name: "goby", tags: ["ruby", "go"] })
+// # => "name: goby\ntags:\n  - ruby\n  - go\n"
+// ```
+
+// Class methods --------------------------------------------------------
+func builtinYAMLClassMethods() []*BuiltinMethodObject {
+	return []*BuiltinMethodObject{
+		{
+			// Converts the object into a YAML document. Hashes are dumped with sorted keys, and objects other than
+			// Strings, Integers, Floats, Booleans, nil, Arrays and Hashes are dumped as their `to_s` strings.
+			//
+			// ```ruby
+			// YAML.dump({ a: 1, b: [true, nil] }) # => "a: 1\nb:\n  - true\n  - null\n"
+			// YAML.dump("1")                     # => "\"1\"\n"
+			// ```
+			//
+			// @param object [Object]
+			// @return [String]
+			Name: "dump",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					var b strings.Builder
+					dumpYAML(&b, args[0], 0)
+
+					return t.vm.initStringObject(b.String())
+				}
+			},
+		},
+		{
+			// Parses the YAML document and returns the Hash, Array or scalar it represents.
+			//
+			// ```ruby
+			// YAML.load("- 1\n- two\n- 3.0") # => [1, "two", 3.0]
+			// YAML.load("a: { b: yes }")     # => { a: { b: "yes" } }
+			// ```
+			//
+			// @param document [String]
+			// @return [Object]
+			Name: "load",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					if len(args) != 1 {
+						return t.initErrorObject(errors.ArgumentError, "Expect 1 argument. got: %d", len(args))
+					}
+
+					doc, ok := args[0].(*StringObject)
+					if !ok {
+						return t.initErrorObject(errors.TypeError, errors.WrongArgumentTypeFormat, classes.StringClass, args[0].Class().Name)
+					}
+
+					return t.loadYAML(doc.value)
+				}
+			},
+		},
+		{
+			// Reads the file and parses it as a YAML document.
+			//
+			// ```ruby
+			// YAML.load_file("config/database.yml")["adapter"] # => "postgres"
+			// ```
+			//
+			// @param filename [String]
+			// @return [Object]
+			Name: "load_file",
+			Fn: func(receiver Object) builtinMethodBody {
+				return func(t *thread, args []Object, blockFrame *callFrame) Object {
+					filename, e := t.filenameArgument(args)
+					if e != nil {
+						return e
+					}
+
+					content, err := ioutil.ReadFile(filename)
+					if err != nil {
+						return t.initErrorObject(errors.InternalError, err.Error())
+					}
+
+					return t.loadYAML(string(content))
+				}
+			},
+		},
+	}
+}
+
+// Internal functions ===================================================
+
+// Functions for initialization -----------------------------------------
+
+func initYAMLClass(vm *VM) {
+	class := vm.initializeClass("YAML", false)
+	class.setBuiltinMethods(builtinYAMLClassMethods(), true)
+	vm.objectClass.setClassConstant(class)
+}
+
+// Other helper functions -----------------------------------------------
+
+// loadYAML parses the document, it returns an error object if the document is invalid
+func (t *thread) loadYAML(doc string) Object {
+	p, err := newYAMLParser(t.vm, doc)
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, "Can't parse yaml: %s", err.Error())
+	}
+
+	obj, err := p.parseDocument()
+	if err != nil {
+		return t.initErrorObject(errors.InternalError, "Can't parse yaml: %s", err.Error())
+	}
+
+	return obj
+}
+
+// yamlLine is a line of a YAML document. content is the line without indentation and comments, it's empty if the
+// line is blank. raw is the original line, which block scalars are made of.
+type yamlLine struct {
+	number  int
+	indent  int
+	content string
+	raw     string
+}
+
+// yamlParser parses YAML documents line by line, nodes are nested by the lines' indentation
+type yamlParser struct {
+	vm    *VM
+	lines []yamlLine
+	pos   int
+}
+
+func newYAMLParser(vm *VM, doc string) (*yamlParser, error) {
+	p := &yamlParser{vm: vm}
+	started := false
+
+	for i, raw := range strings.Split(strings.Replace(doc, "\r\n", "\n", -1), "\n") {
+		trimmed := strings.TrimLeft(raw, " ")
+		content := strings.TrimRight(stripYAMLComment(trimmed), " \t")
+
+		if content != "" && strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("line %d: tabs can't be used for indentation", i+1)
+		}
+
+		if len(trimmed) == len(raw) && (content == "---" || strings.HasPrefix(content, "--- ")) {
+			// The document separator ends the first document
+			if started {
+				break
+			}
+
+			content = strings.TrimSpace(strings.TrimPrefix(content, "---"))
+		}
+
+		if len(trimmed) == len(raw) && content == "..." {
+			break
+		}
+
+		if content != "" {
+			started = true
+		}
+
+		p.lines = append(p.lines, yamlLine{number: i + 1, indent: len(raw) - len(trimmed), content: content, raw: raw})
+	}
+
+	return p, nil
+}
+
+// parseDocument parses the whole document into an object, an empty document is nil
+func (p *yamlParser) parseDocument() (Object, error) {
+	l, ok := p.peek()
+	if !ok {
+		return NULL, nil
+	}
+
+	obj, err := p.parseNode(l.indent)
+	if err != nil {
+		return nil, err
+	}
+
+	if l, ok := p.peek(); ok {
+		return nil, fmt.Errorf("line %d: unexpected content %q", l.number, l.content)
+	}
+
+	return obj, nil
+}
+
+// peek returns the next line that isn't blank
+func (p *yamlParser) peek() (yamlLine, bool) {
+	for p.pos < len(p.lines) {
+		if p.lines[p.pos].content != "" {
+			return p.lines[p.pos], true
+		}
+
+		p.pos++
+	}
+
+	return yamlLine{}, false
+}
+
+// parseNode parses the node starting from the next line, whose indentation is expected to be indent
+func (p *yamlParser) parseNode(indent int) (Object, error) {
+	l, ok := p.peek()
+	if !ok || l.indent < indent {
+		return NULL, nil
+	}
+
+	if isYAMLSequenceEntry(l.content) {
+		return p.parseSequence(l.indent)
+	}
+
+	if _, _, ok := splitYAMLMappingEntry(l.content); ok {
+		return p.parseMapping(l.indent)
+	}
+
+	p.pos++
+	return p.parseValue(l.content, l.indent, l, false)
+}
+
+func (p *yamlParser) parseMapping(indent int) (Object, error) {
+	pairs := map[string]Object{}
+
+	for {
+		l, ok := p.peek()
+		if !ok || l.indent < indent {
+			break
+		}
+
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: bad indentation of a mapping entry", l.number)
+		}
+
+		key, value, ok := splitYAMLMappingEntry(l.content)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expect a mapping entry. got: %q", l.number, l.content)
+		}
+
+		p.pos++
+
+		v, err := p.parseValue(value, indent, l, true)
+		if err != nil {
+			return nil, err
+		}
+
+		pairs[key] = v
+	}
+
+	return p.vm.initHashObject(pairs), nil
+}
+
+func (p *yamlParser) parseSequence(indent int) (Object, error) {
+	elems := []Object{}
+
+	for {
+		l, ok := p.peek()
+		if !ok || l.indent < indent || !isYAMLSequenceEntry(l.content) {
+			break
+		}
+
+		if l.indent > indent {
+			return nil, fmt.Errorf("line %d: bad indentation of a sequence entry", l.number)
+		}
+
+		rest := strings.TrimLeft(l.content[1:], " ")
+		itemIndent := l.indent + len(l.content) - len(rest)
+
+		var elem Object
+		var err error
+
+		_, _, isMapping := splitYAMLMappingEntry(rest)
+
+		if rest != "" && (isMapping || isYAMLSequenceEntry(rest)) {
+			// A compact nested node like "- key: value", its following lines are indented to the entry's content
+			p.lines[p.pos] = yamlLine{number: l.number, indent: itemIndent, content: rest, raw: l.raw}
+			elem, err = p.parseNode(itemIndent)
+		} else {
+			p.pos++
+			elem, err = p.parseValue(rest, indent, l, false)
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		elems = append(elems, elem)
+	}
+
+	return p.vm.initArrayObject(elems), nil
+}
+
+// parseValue parses the value of a mapping entry or a sequence entry in line l, indent is the entry's indentation.
+// An empty value means the value is the nested node in the following lines.
+func (p *yamlParser) parseValue(value string, indent int, l yamlLine, inMapping bool) (Object, error) {
+	if value == "" {
+		next, ok := p.peek()
+
+		switch {
+		case !ok:
+			return NULL, nil
+		case next.indent > indent:
+			return p.parseNode(next.indent)
+		case inMapping && next.indent == indent && isYAMLSequenceEntry(next.content):
+			// Sequences in mappings don't need to be indented
+			return p.parseSequence(indent)
+		default:
+			return NULL, nil
+		}
+	}
+
+	switch value[0] {
+	case '|', '>':
+		return p.parseBlockScalar(value, indent, l)
+	case '&', '*', '!':
+		return nil, fmt.Errorf("line %d: anchors, aliases and tags are not supported", l.number)
+	}
+
+	obj, err := p.parseScalar(value)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %s", l.number, err.Error())
+	}
+
+	return obj, nil
+}
+
+// parseBlockScalar parses a literal (|) or folded (>) scalar made of the following lines that are indented more than indent
+func (p *yamlParser) parseBlockScalar(header string, indent int, l yamlLine) (Object, error) {
+	chomping := byte(0)
+
+	for _, c := range header[1:] {
+		switch {
+		case c == '-' || c == '+':
+			chomping = byte(c)
+		case c < '1' || c > '9':
+			return nil, fmt.Errorf("line %d: invalid block scalar header %q", l.number, header)
+		}
+	}
+
+	var lines []string
+	blockIndent := 0
+
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos].raw
+
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, "")
+			continue
+		}
+
+		lineIndent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if lineIndent <= indent || (blockIndent > 0 && lineIndent < blockIndent) {
+			break
+		}
+
+		if blockIndent == 0 {
+			blockIndent = lineIndent
+		}
+
+		lines = append(lines, raw[blockIndent:])
+	}
+
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+
+	var text string
+
+	if header[0] == '|' {
+		text = strings.Join(lines, "\n")
+	} else {
+		var b strings.Builder
+
+		for i, line := range lines {
+			// Line breaks are folded into spaces, and each empty line becomes a line break
+			switch {
+			case i == 0 || lines[i-1] == "" && line != "":
+			case line == "":
+				b.WriteString("\n")
+			default:
+				b.WriteString(" ")
+			}
+
+			b.WriteString(line)
+		}
+
+		text = b.String()
+	}
+
+	switch {
+	case chomping == '-' || text == "":
+	case chomping == '+':
+		text += strings.Repeat("\n", trailing+1)
+	default:
+		text += "\n"
+	}
+
+	return p.vm.initStringObject(text), nil
+}
+
+// parseScalar parses a scalar or a flow collection written in a single line
+func (p *yamlParser) parseScalar(s string) (Object, error) {
+	obj, rest, err := p.parseFlowNode(s, false)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("unexpected characters after %q", s[:len(s)-len(rest)])
+	}
+
+	return obj, nil
+}
+
+// parseFlowNode parses the node at the start of s, and returns the rest of s. Plain scalars inside flow collections
+// end at flow indicators, and keys also end at ':'.
+func (p *yamlParser) parseFlowNode(s string, inFlow bool) (Object, string, error) {
+	s = strings.TrimLeft(s, " ")
+
+	if s == "" {
+		return NULL, s, nil
+	}
+
+	switch s[0] {
+	case '[':
+		return p.parseFlowSequence(s[1:])
+	case '{':
+		return p.parseFlowMapping(s[1:])
+	case '"', '\'':
+		str, rest, err := parseYAMLQuotedString(s)
+		if err != nil {
+			return nil, "", err
+		}
+
+		return p.vm.initStringObject(str), rest, nil
+	}
+
+	end := len(s)
+	if inFlow {
+		if i := strings.IndexAny(s, ",]}"); i >= 0 {
+			end = i
+		}
+	}
+
+	return p.vm.yamlPlainScalar(strings.TrimSpace(s[:end])), s[end:], nil
+}
+
+func (p *yamlParser) parseFlowSequence(s string) (Object, string, error) {
+	elems := []Object{}
+
+	for {
+		s = strings.TrimLeft(s, " ")
+
+		if s == "" {
+			return nil, "", fmt.Errorf("unterminated flow sequence")
+		}
+
+		if s[0] == ']' {
+			return p.vm.initArrayObject(elems), s[1:], nil
+		}
+
+		elem, rest, err := p.parseFlowNode(s, true)
+		if err != nil {
+			return nil, "", err
+		}
+
+		elems = append(elems, elem)
+		s = strings.TrimLeft(rest, " ")
+
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		} else if !strings.HasPrefix(s, "]") {
+			return nil, "", fmt.Errorf("unterminated flow sequence")
+		}
+	}
+}
+
+func (p *yamlParser) parseFlowMapping(s string) (Object, string, error) {
+	pairs := map[string]Object{}
+
+	for {
+		s = strings.TrimLeft(s, " ")
+
+		if s == "" {
+			return nil, "", fmt.Errorf("unterminated flow mapping")
+		}
+
+		if s[0] == '}' {
+			return p.vm.initHashObject(pairs), s[1:], nil
+		}
+
+		var key string
+
+		if s[0] == '"' || s[0] == '\'' {
+			k, rest, err := parseYAMLQuotedString(s)
+			if err != nil {
+				return nil, "", err
+			}
+
+			key, s = k, strings.TrimLeft(rest, " ")
+		} else {
+			end := strings.IndexAny(s, ":,}")
+			if end < 0 {
+				return nil, "", fmt.Errorf("unterminated flow mapping")
+			}
+
+			key, s = strings.TrimSpace(s[:end]), s[end:]
+		}
+
+		var value Object = NULL
+
+		if strings.HasPrefix(s, ":") {
+			v, rest, err := p.parseFlowNode(s[1:], true)
+			if err != nil {
+				return nil, "", err
+			}
+
+			value, s = v, rest
+		}
+
+		pairs[key] = value
+		s = strings.TrimLeft(s, " ")
+
+		if strings.HasPrefix(s, ",") {
+			s = s[1:]
+		} else if !strings.HasPrefix(s, "}") {
+			return nil, "", fmt.Errorf("unterminated flow mapping")
+		}
+	}
+}
+
+var (
+	yamlIntegerRegexp = regexp.MustCompile(`^[-+]?[0-9]+$`)
+	yamlFloatRegexp   = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolveYAMLPlainScalar returns the Go value of a plain scalar, which is nil, a bool, an int, a *big.Int,
+// a float64 or the string itself
+func resolveYAMLPlainScalar(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1)
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1)
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN()
+	}
+
+	switch {
+	case yamlIntegerRegexp.MatchString(s):
+		if i, err := strconv.Atoi(s); err == nil {
+			return i
+		}
+
+		n, _ := new(big.Int).SetString(strings.TrimPrefix(s, "+"), 10)
+		return n
+	case strings.HasPrefix(s, "0x"), strings.HasPrefix(s, "0o"):
+		if i, err := strconv.ParseInt(s, 0, 64); err == nil {
+			return int(i)
+		}
+	case yamlFloatRegexp.MatchString(s):
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+
+	return s
+}
+
+func (vm *VM) yamlPlainScalar(s string) Object {
+	switch v := resolveYAMLPlainScalar(s).(type) {
+	case *big.Int:
+		return vm.initIntegerFromBigInt(v)
+	case string:
+		return vm.initStringObject(v)
+	default:
+		return vm.initObjectFromGoType(v)
+	}
+}
+
+// parseYAMLQuotedString parses the single or double quoted string at the start of s, and returns the rest of s
+func parseYAMLQuotedString(s string) (string, string, error) {
+	quote := s[0]
+
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case quote == '\'' && s[i] == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			if quote == '\'' {
+				return strings.Replace(s[1:i], "''", "'", -1), s[i+1:], nil
+			}
+
+			str, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", fmt.Errorf("invalid double quoted string %s", s[:i+1])
+			}
+
+			return str, s[i+1:], nil
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated quoted string %s", s)
+}
+
+// splitYAMLMappingEntry splits "key: value" into the key and the value
+func splitYAMLMappingEntry(s string) (string, string, bool) {
+	if s == "" || s[0] == '[' || s[0] == '{' {
+		return "", "", false
+	}
+
+	if s[0] == '"' || s[0] == '\'' {
+		key, rest, err := parseYAMLQuotedString(s)
+		if err != nil || !strings.HasPrefix(rest, ":") || (len(rest) > 1 && rest[1] != ' ') {
+			return "", "", false
+		}
+
+		return key, strings.TrimSpace(rest[1:]), true
+	}
+
+	if strings.HasSuffix(s, ":") && !strings.Contains(s, ": ") {
+		return strings.TrimSpace(s[:len(s)-1]), "", true
+	}
+
+	i := strings.Index(s, ": ")
+	if i < 0 {
+		return "", "", false
+	}
+
+	return strings.TrimSpace(s[:i]), strings.TrimSpace(s[i+2:]), true
+}
+
+func isYAMLSequenceEntry(s string) bool {
+	return s == "-" || strings.HasPrefix(s, "- ")
+}
+
+// stripYAMLComment removes the comment from the line, a comment starts with '#' that is at the start of the line
+// or after a space, and isn't in a quoted string
+func stripYAMLComment(s string) string {
+	var quote byte
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			// Quotes in the middle of plain scalars like "it's" don't start quoted strings
+			if prev := strings.TrimRight(s[:i], " "); prev == "" || strings.ContainsAny(prev[len(prev)-1:], ":-[{,") {
+				quote = c
+			}
+		case c == '#' && (i == 0 || s[i-1] == ' ' || s[i-1] == '\t'):
+			return s[:i]
+		}
+	}
+
+	return s
+}
+
+// dumpYAML writes the object into b as a block style node indented by indent spaces
+func dumpYAML(b *strings.Builder, obj Object, indent int) {
+	prefix := strings.Repeat(" ", indent)
+
+	switch o := obj.(type) {
+	case *HashObject:
+		if o.length() == 0 {
+			break
+		}
+
+		for _, k := range o.sortedKeys() {
+			key := k
+			if keyObj, ok := o.keyObjects[k]; ok {
+				key = keyObj.toString()
+			}
+
+			b.WriteString(prefix + yamlString(key) + ":")
+			dumpYAMLValue(b, o.Pairs[k], indent, false)
+		}
+
+		return
+	case *ArrayObject:
+		if len(o.Elements) == 0 {
+			break
+		}
+
+		for _, elem := range o.Elements {
+			b.WriteString(prefix + "-")
+			dumpYAMLValue(b, elem, indent, true)
+		}
+
+		return
+	}
+
+	b.WriteString(prefix + yamlScalar(obj) + "\n")
+}
+
+// dumpYAMLValue writes the value of a mapping entry or a sequence entry whose indentation is indent.
+// Collections in sequence entries start in the entry's line, like "- key: value".
+func dumpYAMLValue(b *strings.Builder, obj Object, indent int, inSequence bool) {
+	switch o := obj.(type) {
+	case *HashObject:
+		if o.length() == 0 {
+			break
+		}
+
+		dumpYAMLCollection(b, o, indent, inSequence)
+		return
+	case *ArrayObject:
+		if len(o.Elements) == 0 {
+			break
+		}
+
+		dumpYAMLCollection(b, o, indent, inSequence)
+		return
+	}
+
+	b.WriteString(" " + yamlScalar(obj) + "\n")
+}
+
+func dumpYAMLCollection(b *strings.Builder, obj Object, indent int, inSequence bool) {
+	if !inSequence {
+		b.WriteString("\n")
+		dumpYAML(b, obj, indent+2)
+		return
+	}
+
+	var nested strings.Builder
+	dumpYAML(&nested, obj, indent+2)
+	b.WriteString(" " + nested.String()[indent+2:])
+}
+
+// yamlScalar returns the YAML representation of the object that isn't a non-empty collection
+func yamlScalar(obj Object) string {
+	switch o := obj.(type) {
+	case *HashObject:
+		return "{}"
+	case *ArrayObject:
+		return "[]"
+	case *NullObject:
+		return "null"
+	case *BooleanObject, *IntegerObject, *BigIntObject:
+		return o.toString()
+	case *FloatObject:
+		switch {
+		case math.IsNaN(o.value):
+			return ".nan"
+		case math.IsInf(o.value, 1):
+			return ".inf"
+		case math.IsInf(o.value, -1):
+			return "-.inf"
+		}
+
+		return o.toString()
+	default:
+		return yamlString(obj.toString())
+	}
+}
+
+// yamlString returns s as a plain scalar, or a double quoted string if it would be read as another type or it
+// contains characters that have special meanings
+func yamlString(s string) string {
+	if _, ok := resolveYAMLPlainScalar(s).(string); !ok ||
+		s != strings.TrimSpace(s) ||
+		strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") ||
+		strings.HasSuffix(s, ":") ||
+		strings.Contains(s, ": ") ||
+		strings.Contains(s, " #") ||
+		strings.ContainsAny(s, "\n\t\\") {
+		return strconv.Quote(s)
+	}
+
+	return s
+}
@@ -0,0 +1,199 @@
+package vm
+
+import "testing"
+
+func TestYAMLLoadMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "yaml"
+		YAML.load("name: goby\nversion: 13")["version"]
+		`, 13},
+		{`
+		require "yaml"
+		YAML.load("a:\n  b:\n    c: deep")["a"]["b"]["c"]
+		`, "deep"},
+		{`
+		require "yaml"
+		YAML.load("- 1\n- two\n- 3.5\n- true\n- ~").to_s
+		`, `[1, "two", 3.5, true, nil]`},
+		{`
+		require "yaml"
+		YAML.load("list:\n- a\n- b\nother: 1")["list"].to_s
+		`, `["a", "b"]`},
+		{`
+		require "yaml"
+		YAML.load("- name: a\n  id: 1\n- name: b\n  id: 2")[1]["id"]
+		`, 2},
+		{`
+		require "yaml"
+		YAML.load("- - 1\n  - 2\n- - 3").to_s
+		`, "[[1, 2], [3]]"},
+		{`
+		require "yaml"
+		YAML.load("flow: { a: 1, b: [x, 'y, z'] }")["flow"]["b"].to_s
+		`, `["x", "y, z"]`},
+		{`
+		require "yaml"
+		YAML.load("a: \"1\"\nb: 'it''s'\nc: it's # comment")["a"]
+		`, "1"},
+		{`
+		require "yaml"
+		YAML.load("a: \"1\"\nb: 'it''s'\nc: it's # comment")["b"]
+		`, "it's"},
+		{`
+		require "yaml"
+		YAML.load("a: \"1\"\nb: 'it''s'\nc: it's # comment")["c"]
+		`, "it's"},
+		{`
+		require "yaml"
+		YAML.load("url: http://goby-lang.org/#top")["url"]
+		`, "http://goby-lang.org/#top"},
+		{`
+		require "yaml"
+		YAML.load("text: |-\n  a\n  b\n\nnext: 1")["text"]
+		`, "a\nb"},
+		{`
+		require "yaml"
+		YAML.load("text: >\n  a\n  b\n\n  c\n")["text"]
+		`, "a b\nc\n"},
+		{`
+		require "yaml"
+		YAML.load("---\nfirst: 1\n---\nsecond: 2").to_s
+		`, "{ first: 1 }"},
+		{`
+		require "yaml"
+		YAML.load("--- 5")
+		`, 5},
+		{`
+		require "yaml"
+		YAML.load("# only a comment")
+		`, nil},
+		{`
+		require "yaml"
+		YAML.load("empty:\nnull: null")["empty"]
+		`, nil},
+		{`
+		require "yaml"
+		YAML.load("big: 123456789012345678901234567890")["big"].to_s
+		`, "123456789012345678901234567890"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestYAMLLoadFileMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/config.yml")["version"]
+		`, "0.1.13"},
+		{`
+		require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/config.yml")["database"]["pool"]
+		`, 5},
+		{`
+		require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/config.yml")["servers"][0]["ports"].to_s
+		`, "[3000, 3001]"},
+		{`
+		require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/config.yml")["servers"][1]["ports"].length
+		`, 0},
+		{`
+		require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/config.yml")["description"]
+		`, "Goby is a\nscripting language.\n"},
+		{`
+		require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/config.yml")["summary"]
+		`, "Goby is Ruby-like.\n"},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestYAMLDumpMethod(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`
+		require "yaml"
+		YAML.dump({ name: "goby", tags: ["ruby", "go"] })
+		`, "name: goby\ntags:\n  - ruby\n  - go\n"},
+		{`
+		require "yaml"
+		YAML.dump([{ a: 1, b: nil }, [true, 1.5], {}, []])
+		`, "- a: 1\n  b: null\n- - true\n  - 1.5\n- {}\n- []\n"},
+		{`
+		require "yaml"
+		YAML.dump(["1", "true", "", "a: b", " x", "- y", "line\nbreak", :sym])
+		`, "- \"1\"\n- \"true\"\n- \"\"\n- \"a: b\"\n- \" x\"\n- \"- y\"\n- \"line\\nbreak\"\n- sym\n"},
+		{`
+		require "yaml"
+		YAML.dump(1)
+		`, "1\n"},
+		{`
+		require "yaml"
+		h = YAML.load_file("../test_fixtures/yaml_test/config.yml")
+		YAML.load(YAML.dump(h)) == h
+		`, true},
+	}
+
+	for i, tt := range tests {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkExpected(t, i, evaluated, tt.expected)
+		v.checkCFP(t, i, 0)
+		v.checkSP(t, i, 1)
+	}
+}
+
+func TestYAMLMethodsFail(t *testing.T) {
+	testsFail := []errorTestCase{
+		{`require "yaml"
+		YAML.load`, "ArgumentError: Expect 1 argument. got: 0", 2},
+		{`require "yaml"
+		YAML.load(1)`, "TypeError: Expect argument to be String. got: Integer", 2},
+		{`require "yaml"
+		YAML.load("a: 1\n  b: 2")`, "InternalError: Can't parse yaml: line 2: bad indentation of a mapping entry", 2},
+		{`require "yaml"
+		YAML.load("a: [1, 2")`, "InternalError: Can't parse yaml: line 1: unterminated flow sequence", 2},
+		{`require "yaml"
+		YAML.load("- a\nb: 1")`, "InternalError: Can't parse yaml: line 2: unexpected content \"b: 1\"", 2},
+		{`require "yaml"
+		YAML.load("a: &anchor 1")`, "InternalError: Can't parse yaml: line 1: anchors, aliases and tags are not supported", 2},
+		{`require "yaml"
+		YAML.load("\ta: 1")`, "InternalError: Can't parse yaml: line 1: tabs can't be used for indentation", 2},
+		{`require "yaml"
+		YAML.dump(1, 2)`, "ArgumentError: Expect 1 argument. got: 2", 2},
+		{`require "yaml"
+		YAML.load_file("../test_fixtures/yaml_test/no_such_file.yml")`, "InternalError: open ../test_fixtures/yaml_test/no_such_file.yml: no such file or directory", 2},
+	}
+
+	for i, tt := range testsFail {
+		v := initTestVM()
+		evaluated := v.testEval(t, tt.input, getFilename())
+		checkError(t, i, evaluated, tt.expected, getFilename(), tt.errorLine)
+		v.checkCFP(t, i, 1)
+		v.checkSP(t, i, 1)
+	}
+}